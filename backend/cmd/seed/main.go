@@ -9,6 +9,7 @@ import (
 	"bugrelay-backend/internal/database"
 	"bugrelay-backend/internal/logger"
 	"bugrelay-backend/internal/seeder"
+	"bugrelay-backend/internal/utils"
 
 	"github.com/joho/godotenv"
 )
@@ -16,9 +17,13 @@ import (
 func main() {
 	// Parse command line flags
 	var (
-		clear   = flag.Bool("clear", false, "Clear all seeded data")
-		testing = flag.Bool("testing", false, "Seed minimal data for testing")
-		help    = flag.Bool("help", false, "Show help message")
+		clear          = flag.Bool("clear", false, "Clear all seeded data")
+		clearAll       = flag.Bool("clear-all", false, "Clear all seeded data (alias for -clear)")
+		clearBugs      = flag.Bool("clear-bugs", false, "Clear bugs with their votes, comments and attachments")
+		clearUsers     = flag.Bool("clear-users", false, "Anonymize and soft delete users")
+		clearCompanies = flag.Bool("clear-companies", false, "Unassign bugs and clear companies")
+		testing        = flag.Bool("testing", false, "Seed minimal data for testing")
+		help           = flag.Bool("help", false, "Show help message")
 	)
 	flag.Parse()
 
@@ -57,17 +62,40 @@ func main() {
 		logger.Fatal("Failed to initialize database", err)
 	}
 
-	// Create seeder
+	// Create seeder, keeping seeded tags within the configured limits
 	s := seeder.New(db)
+	s.SetTagConfig(utils.TagConfig{
+		MaxTags:   cfg.Validation.MaxTagsPerBug,
+		MinLength: cfg.Validation.MinTagLength,
+		MaxLength: cfg.Validation.MaxTagLength,
+	})
 
 	// Execute based on flags
 	switch {
-	case *clear:
+	case *clear || *clearAll:
 		if err := s.Clear(); err != nil {
 			logger.Fatal("Failed to clear seeded data", err)
 		}
 		logger.Info("Successfully cleared all seeded data")
 
+	case *clearBugs || *clearUsers || *clearCompanies:
+		// Bugs go first so clearing companies has fewer bugs to unassign
+		if *clearBugs {
+			if err := s.ClearBugs(); err != nil {
+				logger.Fatal("Failed to clear bugs", err)
+			}
+		}
+		if *clearCompanies {
+			if err := s.ClearCompanies(); err != nil {
+				logger.Fatal("Failed to clear companies", err)
+			}
+		}
+		if *clearUsers {
+			if err := s.ClearUsers(); err != nil {
+				logger.Fatal("Failed to clear users", err)
+			}
+		}
+
 	case *testing:
 		if err := s.SeedForTesting(); err != nil {
 			logger.Fatal("Failed to seed test data", err)
@@ -89,12 +117,17 @@ func showHelp() {
 	fmt.Println("  go run cmd/seed/main.go [flags]")
 	fmt.Println()
 	fmt.Println("Flags:")
-	fmt.Println("  -clear     Clear all seeded data from the database")
-	fmt.Println("  -testing   Seed minimal data for testing purposes")
-	fmt.Println("  -help      Show this help message")
+	fmt.Println("  -clear             Clear all seeded data from the database")
+	fmt.Println("  -clear-all         Alias for -clear")
+	fmt.Println("  -clear-bugs        Clear bugs with their votes, comments and attachments")
+	fmt.Println("  -clear-users       Anonymize and soft delete users, keeping their bugs and comments")
+	fmt.Println("  -clear-companies   Unassign bugs and applications, then clear companies")
+	fmt.Println("  -testing           Seed minimal data for testing purposes")
+	fmt.Println("  -help              Show this help message")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  go run cmd/seed/main.go                    # Seed all development data")
 	fmt.Println("  go run cmd/seed/main.go -testing          # Seed minimal test data")
 	fmt.Println("  go run cmd/seed/main.go -clear            # Clear all seeded data")
+	fmt.Println("  go run cmd/seed/main.go -clear-bugs       # Clear only bugs")
 }
\ No newline at end of file
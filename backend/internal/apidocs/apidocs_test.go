@@ -0,0 +1,74 @@
+package apidocs
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadSpec converts the generated Swagger 2.0 document to OpenAPI 3.0
+func loadSpec(t *testing.T) *openapi3.T {
+	t.Helper()
+
+	var v2 openapi2.T
+	require.NoError(t, json.Unmarshal([]byte(SwaggerInfo.ReadDoc()), &v2))
+
+	doc, err := openapi2conv.ToV3(&v2)
+	require.NoError(t, err)
+	return doc
+}
+
+func TestSpec_ValidOpenAPI3(t *testing.T) {
+	doc := loadSpec(t)
+	require.NoError(t, doc.Validate(context.Background()))
+	assert.Equal(t, "BugRelay API", doc.Info.Title)
+}
+
+func TestSpec_DocumentsHandlers(t *testing.T) {
+	doc := loadSpec(t)
+
+	for _, path := range []string{
+		"/auth/login",
+		"/auth/register",
+		"/bugs/",
+		"/bugs/{id}",
+		"/bugs/{id}/comments",
+		"/companies/",
+		"/companies/{id}/claim",
+		"/admin/bugs/merge",
+		"/admin/audit-logs",
+	} {
+		assert.NotNil(t, doc.Paths.Find(path), "missing path %s", path)
+	}
+
+	for _, name := range []string{"handlers.CreateBugRequest", "handlers.AuthResponse", "errors.ErrorResponse", "models.BugReport"} {
+		assert.Contains(t, doc.Components.Schemas, name)
+	}
+
+	scheme := doc.Components.SecuritySchemes["BearerAuth"]
+	require.NotNil(t, scheme)
+	assert.Equal(t, "header", scheme.Value.In)
+	assert.Equal(t, "Authorization", scheme.Value.Name)
+}
+
+func TestSpec_AdminRoutesRequireAuth(t *testing.T) {
+	doc := loadSpec(t)
+
+	for path, item := range doc.Paths.Map() {
+		if !strings.HasPrefix(path, "/admin/") {
+			continue
+		}
+		for method, op := range item.Operations() {
+			require.NotNil(t, op.Security, "%s %s has no security", method, path)
+			assert.Contains(t, (*op.Security)[0], "BearerAuth", "%s %s", method, path)
+			assert.NotNil(t, op.Responses.Value("403"), "%s %s does not document 403", method, path)
+		}
+	}
+}
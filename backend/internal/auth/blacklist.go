@@ -60,10 +60,7 @@ func (b *BlacklistService) IsTokenBlacklisted(ctx context.Context, tokenID strin
 
 	// Fallback to database check
 	var count int64
-	err = b.db.Model(&struct {
-		TokenJTI  string    `gorm:"column:token_jti"`
-		ExpiresAt time.Time `gorm:"column:expires_at"`
-	}{}).
+	err = b.db.Table("jwt_blacklist").
 		Where("token_jti = ? AND expires_at > NOW()", tokenID).
 		Count(&count).Error
 
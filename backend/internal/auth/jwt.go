@@ -5,6 +5,8 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"net"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -22,7 +24,9 @@ type JWTClaims struct {
 	UserID    string `json:"user_id"`
 	Email     string `json:"email"`
 	IsAdmin   bool   `json:"is_admin"`
-	TokenType string `json:"token_type"` // "access" or "refresh"
+	TokenType string `json:"token_type"`    // "access" or "refresh"
+	IP        string `json:"ip,omitempty"`  // Client IP the session is bound to, if any
+	SessionID string `json:"sid,omitempty"` // Shared by the access and refresh token of a pair
 	jwt.RegisteredClaims
 }
 
@@ -44,14 +48,25 @@ func NewJWTService(secretKey string, accessTokenTTL, refreshTokenTTL time.Durati
 
 // GenerateTokenPair generates both access and refresh tokens
 func (j *JWTService) GenerateTokenPair(userID, email string, isAdmin bool) (accessToken, refreshToken string, err error) {
+	return j.GenerateTokenPairForIP(userID, email, isAdmin, "")
+}
+
+// GenerateTokenPairForIP generates access and refresh tokens bound to a client IP.
+// Tokens are not bound to an IP when ip is empty.
+func (j *JWTService) GenerateTokenPairForIP(userID, email string, isAdmin bool, ip string) (accessToken, refreshToken string, err error) {
+	if ip != "" {
+		ip = NormalizeIP(ip)
+	}
+	sessionID := uuid.New().String()
+
 	// Generate access token
-	accessToken, err = j.generateToken(userID, email, isAdmin, "access", j.accessTokenTTL)
+	accessToken, err = j.generateToken(userID, email, isAdmin, ip, sessionID, "access", j.accessTokenTTL)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to generate access token: %w", err)
 	}
 
 	// Generate refresh token
-	refreshToken, err = j.generateToken(userID, email, isAdmin, "refresh", j.refreshTokenTTL)
+	refreshToken, err = j.generateToken(userID, email, isAdmin, ip, sessionID, "refresh", j.refreshTokenTTL)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to generate refresh token: %w", err)
 	}
@@ -60,7 +75,7 @@ func (j *JWTService) GenerateTokenPair(userID, email string, isAdmin bool) (acce
 }
 
 // generateToken creates a JWT token with the specified parameters
-func (j *JWTService) generateToken(userID, email string, isAdmin bool, tokenType string, ttl time.Duration) (string, error) {
+func (j *JWTService) generateToken(userID, email string, isAdmin bool, ip, sessionID, tokenType string, ttl time.Duration) (string, error) {
 	now := time.Now()
 	jti := uuid.New().String()
 
@@ -69,6 +84,8 @@ func (j *JWTService) generateToken(userID, email string, isAdmin bool, tokenType
 		Email:     email,
 		IsAdmin:   isAdmin,
 		TokenType: tokenType,
+		IP:        ip,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ID:        jti,
 			Subject:   userID,
@@ -117,6 +134,21 @@ func (j *JWTService) ExtractTokenID(tokenString string) (string, error) {
 	return claims.ID, nil
 }
 
+// NormalizeIP returns ip in canonical form, so the same address matches however it
+// is written, e.g. an IPv4 address and its IPv4-mapped IPv6 form, or IPv6 addresses
+// with different zero compression. Values that are not IP addresses are returned
+// unchanged.
+func NormalizeIP(ip string) string {
+	parsed := net.ParseIP(strings.TrimSpace(ip))
+	if parsed == nil {
+		return ip
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return v4.String()
+	}
+	return parsed.String()
+}
+
 // GenerateSecureToken generates a cryptographically secure random token
 func GenerateSecureToken(length int) (string, error) {
 	bytes := make([]byte, length)
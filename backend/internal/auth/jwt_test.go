@@ -184,4 +184,41 @@ func TestJWTService_TokenExpiration(t *testing.T) {
 	_, err = service.ValidateToken(refreshToken)
 	assert.Error(t, err)
 	assert.Equal(t, ErrExpiredToken, err)
-}
\ No newline at end of file
+}
+func TestJWTService_GenerateTokenPairForIP(t *testing.T) {
+	service := NewJWTService("test-secret", time.Hour, 24*time.Hour)
+
+	accessToken, refreshToken, err := service.GenerateTokenPairForIP("test-user-id", "test@example.com", false, "::ffff:203.0.113.7")
+	require.NoError(t, err)
+
+	for _, token := range []string{accessToken, refreshToken} {
+		claims, err := service.ValidateToken(token)
+		require.NoError(t, err)
+		assert.Equal(t, "203.0.113.7", claims.IP)
+	}
+
+	// Tokens are not bound to an IP by default
+	accessToken, _, err = service.GenerateTokenPair("test-user-id", "test@example.com", false)
+	require.NoError(t, err)
+	claims, err := service.ValidateToken(accessToken)
+	require.NoError(t, err)
+	assert.Empty(t, claims.IP)
+}
+
+func TestNormalizeIP(t *testing.T) {
+	tests := []struct {
+		ip       string
+		expected string
+	}{
+		{"203.0.113.7", "203.0.113.7"},
+		{"::ffff:203.0.113.7", "203.0.113.7"},
+		{" 203.0.113.7 ", "203.0.113.7"},
+		{"2001:DB8:0:0:0:0:0:1", "2001:db8::1"},
+		{"2001:db8::1", "2001:db8::1"},
+		{"not-an-ip", "not-an-ip"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, NormalizeIP(tt.ip), tt.ip)
+	}
+}
@@ -72,25 +72,43 @@ func NewOAuthService(config OAuthConfig) *OAuthService {
 	}
 }
 
-// GetAuthURL generates the OAuth authorization URL
-func (o *OAuthService) GetAuthURL(provider OAuthProvider, state string) (string, error) {
+// SupportsPKCE reports whether the provider's authorization code flow uses PKCE
+func (o *OAuthService) SupportsPKCE(provider OAuthProvider) bool {
+	return provider == ProviderGitHub
+}
+
+// GetAuthURL generates the OAuth authorization URL. For providers that support PKCE,
+// a non-empty codeVerifier adds its S256 code challenge to the URL.
+func (o *OAuthService) GetAuthURL(provider OAuthProvider, state, codeVerifier string) (string, error) {
 	switch provider {
 	case ProviderGoogle:
 		return o.googleConfig.AuthCodeURL(state, oauth2.AccessTypeOffline), nil
 	case ProviderGitHub:
-		return o.githubConfig.AuthCodeURL(state), nil
+		var opts []oauth2.AuthCodeOption
+		if codeVerifier != "" {
+			opts = append(opts,
+				oauth2.SetAuthURLParam("code_challenge_method", PKCEChallengeMethod),
+				oauth2.SetAuthURLParam("code_challenge", PKCEChallenge(codeVerifier)),
+			)
+		}
+		return o.githubConfig.AuthCodeURL(state, opts...), nil
 	default:
 		return "", fmt.Errorf("unsupported OAuth provider: %s", provider)
 	}
 }
 
-// ExchangeCodeForToken exchanges authorization code for access token
-func (o *OAuthService) ExchangeCodeForToken(ctx context.Context, provider OAuthProvider, code string) (*oauth2.Token, error) {
+// ExchangeCodeForToken exchanges authorization code for access token. codeVerifier is
+// the PKCE verifier the authorization URL was generated with, if any.
+func (o *OAuthService) ExchangeCodeForToken(ctx context.Context, provider OAuthProvider, code, codeVerifier string) (*oauth2.Token, error) {
 	switch provider {
 	case ProviderGoogle:
 		return o.googleConfig.Exchange(ctx, code)
 	case ProviderGitHub:
-		return o.githubConfig.Exchange(ctx, code)
+		var opts []oauth2.AuthCodeOption
+		if codeVerifier != "" {
+			opts = append(opts, oauth2.SetAuthURLParam("code_verifier", codeVerifier))
+		}
+		return o.githubConfig.Exchange(ctx, code, opts...)
 	default:
 		return nil, fmt.Errorf("unsupported OAuth provider: %s", provider)
 	}
@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestPKCEChallenge(t *testing.T) {
+	// Example from RFC 7636 Appendix B
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	challenge := PKCEChallenge(verifier)
+
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", challenge)
+	assert.True(t, PKCEVerify(challenge, verifier))
+
+	// A tampered verifier does not match the challenge
+	assert.False(t, PKCEVerify(challenge, verifier[:len(verifier)-1]+"l"))
+	assert.False(t, PKCEVerify(challenge, ""))
+}
+
+func TestGeneratePKCEVerifier(t *testing.T) {
+	verifier1, err := GeneratePKCEVerifier()
+	require.NoError(t, err)
+	assert.Len(t, verifier1, 43)
+
+	verifier2, err := GeneratePKCEVerifier()
+	require.NoError(t, err)
+	assert.NotEqual(t, verifier1, verifier2)
+}
+
+func TestOAuthService_GitHubPKCE(t *testing.T) {
+	var exchanged url.Values
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		exchanged = r.PostForm
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "token", "token_type": "bearer"})
+	}))
+	defer tokenServer.Close()
+
+	service := NewOAuthService(OAuthConfig{
+		GitHubClientID:     "test-github-client-id",
+		GitHubClientSecret: "test-github-client-secret",
+		RedirectURL:        "http://localhost:8080/callback",
+	})
+	service.githubConfig.Endpoint = oauth2.Endpoint{
+		AuthURL:  "https://github.example.com/login/oauth/authorize",
+		TokenURL: tokenServer.URL,
+	}
+
+	verifier, err := GeneratePKCEVerifier()
+	require.NoError(t, err)
+
+	authURL, err := service.GetAuthURL(ProviderGitHub, "test-state", verifier)
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "S256", parsed.Query().Get("code_challenge_method"))
+	assert.True(t, PKCEVerify(parsed.Query().Get("code_challenge"), verifier))
+
+	_, err = service.ExchangeCodeForToken(context.Background(), ProviderGitHub, "test-code", verifier)
+	require.NoError(t, err)
+	assert.Equal(t, verifier, exchanged.Get("code_verifier"))
+}
@@ -16,6 +16,12 @@ type Service struct {
 	blacklistService *BlacklistService
 	db               *gorm.DB
 	redis            *redis.Client
+	bindSessionToIP  bool
+
+	// ipChangeGracePeriod and ipChanges let a refresh come from a new IP shortly
+	// after the session was first used from it, see RefreshTokens
+	ipChangeGracePeriod time.Duration
+	ipChanges           *IPChangeTracker
 }
 
 // Config holds authentication service configuration
@@ -23,6 +29,11 @@ type Config struct {
 	JWTSecret       string
 	AccessTokenTTL  time.Duration
 	RefreshTokenTTL time.Duration
+	// BindSessionToIP adds the client IP to issued tokens as the ip claim
+	BindSessionToIP bool
+	// IPChangeGracePeriod is how long a session may keep working from a new IP
+	// after the change is first seen
+	IPChangeGracePeriod time.Duration
 }
 
 // NewService creates a new authentication service
@@ -37,6 +48,10 @@ func NewService(config Config, db *gorm.DB, redis *redis.Client) *Service {
 		blacklistService: blacklistService,
 		db:               db,
 		redis:            redis,
+		bindSessionToIP:  config.BindSessionToIP,
+
+		ipChangeGracePeriod: config.IPChangeGracePeriod,
+		ipChanges:           NewIPChangeTracker(),
 	}
 }
 
@@ -55,9 +70,24 @@ func (s *Service) GetBlacklistService() *BlacklistService {
 	return s.blacklistService
 }
 
-// GenerateTokens generates access and refresh tokens for a user
-func (s *Service) GenerateTokens(userID, email string, isAdmin bool) (accessToken, refreshToken string, err error) {
-	return s.jwtService.GenerateTokenPair(userID, email, isAdmin)
+// GetIPChangeTracker returns the tracker of sessions used from a new IP, shared
+// with the auth middleware so both apply the same grace period
+func (s *Service) GetIPChangeTracker() *IPChangeTracker {
+	return s.ipChanges
+}
+
+// GenerateTokens generates access and refresh tokens for a user signing in from
+// clientIP. The tokens are bound to clientIP when session IP binding is enabled.
+func (s *Service) GenerateTokens(userID, email string, isAdmin bool, clientIP string) (accessToken, refreshToken string, err error) {
+	return s.jwtService.GenerateTokenPairForIP(userID, email, isAdmin, s.sessionIP(clientIP))
+}
+
+// sessionIP returns the IP to bind new tokens to, or "" when binding is disabled
+func (s *Service) sessionIP(clientIP string) string {
+	if !s.bindSessionToIP {
+		return ""
+	}
+	return clientIP
 }
 
 // ValidateAccessToken validates an access token and returns claims
@@ -84,8 +114,11 @@ func (s *Service) ValidateAccessToken(tokenString string) (*JWTClaims, error) {
 	return claims, nil
 }
 
-// RefreshTokens validates a refresh token and generates new token pair
-func (s *Service) RefreshTokens(refreshTokenString string) (accessToken, refreshToken string, err error) {
+// RefreshTokens validates a refresh token and generates new token pair. With session
+// IP binding, a refresh token only works from the IP it is bound to, or from a new IP
+// within the grace period after the session was first used from it. The new tokens
+// are bound to clientIP, so refreshing moves a session to the client's current IP.
+func (s *Service) RefreshTokens(refreshTokenString, clientIP string) (accessToken, refreshToken string, err error) {
 	claims, err := s.jwtService.ValidateToken(refreshTokenString)
 	if err != nil {
 		return "", "", fmt.Errorf("invalid refresh token: %w", err)
@@ -95,6 +128,23 @@ func (s *Service) RefreshTokens(refreshTokenString string) (accessToken, refresh
 		return "", "", fmt.Errorf("invalid token type: expected refresh, got %s", claims.TokenType)
 	}
 
+	if !s.refreshAllowedFromIP(claims, clientIP, time.Now()) {
+		return "", "", fmt.Errorf("refresh token is bound to another IP")
+	}
+
+	// Changing the password revokes every refresh token issued before the change. The
+	// iat claim has whole seconds, so the change time is truncated to match; otherwise
+	// a login in the same second as the change would be revoked immediately.
+	var user struct {
+		LastPasswordChangedAt *time.Time
+	}
+	if err := s.db.Table("users").Select("last_password_changed_at").Where("id = ?", claims.UserID).Scan(&user).Error; err != nil {
+		return "", "", fmt.Errorf("failed to check password change time: %w", err)
+	}
+	if changedAt := user.LastPasswordChangedAt; changedAt != nil && claims.IssuedAt != nil && claims.IssuedAt.Time.Before(changedAt.Truncate(time.Second)) {
+		return "", "", fmt.Errorf("refresh token has been revoked")
+	}
+
 	// Check if token is blacklisted
 	isBlacklisted, err := s.blacklistService.IsTokenBlacklisted(context.Background(), claims.ID)
 	if err != nil {
@@ -112,7 +162,22 @@ func (s *Service) RefreshTokens(refreshTokenString string) (accessToken, refresh
 	}
 
 	// Generate new token pair
-	return s.jwtService.GenerateTokenPair(claims.UserID, claims.Email, claims.IsAdmin)
+	return s.jwtService.GenerateTokenPairForIP(claims.UserID, claims.Email, claims.IsAdmin, s.sessionIP(clientIP))
+}
+
+// refreshAllowedFromIP reports whether a refresh token may be used from clientIP.
+// Unlike an access token, using a refresh token from a new IP does not start a grace
+// period, so a stolen refresh token cannot move its session to the thief's IP.
+func (s *Service) refreshAllowedFromIP(claims *JWTClaims, clientIP string, now time.Time) bool {
+	if !s.bindSessionToIP || claims.IP == "" {
+		return true
+	}
+	if NormalizeIP(claims.IP) == NormalizeIP(clientIP) {
+		return true
+	}
+
+	changedAt, changed := s.ipChanges.ChangedAt(claims)
+	return changed && now.Sub(changedAt) < s.ipChangeGracePeriod
 }
 
 // RevokeToken revokes a specific token
@@ -0,0 +1,203 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/mattn/go-sqlite3"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// sqliteNowDriver is a SQLite driver with postgres' NOW(), used by the blacklist
+const sqliteNowDriver = "sqlite3_now_auth"
+
+var registerSQLiteNowDriver sync.Once
+
+// emptyRedis is a go-redis hook for a Redis with no keys, so no token is blacklisted
+type emptyRedis struct{}
+
+func (emptyRedis) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, errors.New("empty redis does not dial")
+	}
+}
+
+func (emptyRedis) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if intCmd, ok := cmd.(*redis.IntCmd); ok {
+			intCmd.SetVal(0)
+		}
+		return nil
+	}
+}
+
+func (emptyRedis) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+// setupServiceTest creates an auth service with the session settings in config and a
+// user to issue tokens for
+func setupServiceTest(t *testing.T, config Config) (*Service, *gorm.DB, string) {
+	registerSQLiteNowDriver.Do(func() {
+		sql.Register(sqliteNowDriver, &sqlite3.SQLiteDriver{
+			ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+				return conn.RegisterFunc("now", func() string {
+					return time.Now().UTC().Format("2006-01-02 15:04:05")
+				}, false)
+			},
+		})
+	})
+
+	db, err := gorm.Open(sqlite.New(sqlite.Config{DriverName: sqliteNowDriver, DSN: ":memory:"}), &gorm.Config{})
+	require.NoError(t, err)
+
+	// The models' postgres defaults cannot be migrated on sqlite
+	require.NoError(t, db.Exec(`CREATE TABLE users (
+		id TEXT PRIMARY KEY,
+		last_password_changed_at DATETIME
+	)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE jwt_blacklist (
+		token_jti TEXT PRIMARY KEY,
+		user_id TEXT,
+		expires_at DATETIME,
+		created_at DATETIME
+	)`).Error)
+
+	userID := uuid.New().String()
+	require.NoError(t, db.Exec("INSERT INTO users (id) VALUES (?)", userID).Error)
+
+	redisClient := redis.NewClient(&redis.Options{Addr: "empty:6379"})
+	redisClient.AddHook(emptyRedis{})
+
+	config.JWTSecret = "test-secret"
+	config.AccessTokenTTL = time.Hour
+	config.RefreshTokenTTL = 24 * time.Hour
+	service := NewService(config, db, redisClient)
+	return service, db, userID
+}
+
+func TestService_RefreshTokens_PasswordChange(t *testing.T) {
+	service, db, userID := setupServiceTest(t, Config{})
+
+	t.Run("accepts tokens when the password was never changed", func(t *testing.T) {
+		_, refreshToken, err := service.GenerateTokens(userID, "user@example.com", false, "")
+		require.NoError(t, err)
+
+		_, _, err = service.RefreshTokens(refreshToken, "")
+		assert.NoError(t, err)
+	})
+
+	t.Run("rejects tokens issued before the change", func(t *testing.T) {
+		_, refreshToken, err := service.GenerateTokens(userID, "user@example.com", false, "")
+		require.NoError(t, err)
+
+		changedAt := time.Now().Add(2 * time.Second)
+		require.NoError(t, db.Exec("UPDATE users SET last_password_changed_at = ? WHERE id = ?", changedAt, userID).Error)
+
+		_, _, err = service.RefreshTokens(refreshToken, "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "revoked")
+	})
+
+	t.Run("accepts a login in the same second as the change", func(t *testing.T) {
+		_, refreshToken, err := service.GenerateTokens(userID, "user@example.com", false, "")
+		require.NoError(t, err)
+
+		// iat has whole seconds, so a login just after the change has an iat before it
+		claims, err := service.GetJWTService().ValidateToken(refreshToken)
+		require.NoError(t, err)
+		changedAt := claims.IssuedAt.Time.Add(500 * time.Millisecond)
+		require.NoError(t, db.Exec("UPDATE users SET last_password_changed_at = ? WHERE id = ?", changedAt, userID).Error)
+
+		_, _, err = service.RefreshTokens(refreshToken, "")
+		assert.NoError(t, err)
+	})
+}
+
+func TestService_RefreshTokens_SessionIPBinding(t *testing.T) {
+	service, _, userID := setupServiceTest(t, Config{BindSessionToIP: true, IPChangeGracePeriod: time.Minute})
+
+	t.Run("accepts a refresh from the bound IP", func(t *testing.T) {
+		_, refreshToken, err := service.GenerateTokens(userID, "user@example.com", false, "203.0.113.7")
+		require.NoError(t, err)
+
+		_, newRefreshToken, err := service.RefreshTokens(refreshToken, "203.0.113.7")
+		require.NoError(t, err)
+
+		claims, err := service.GetJWTService().ValidateToken(newRefreshToken)
+		require.NoError(t, err)
+		assert.Equal(t, "203.0.113.7", claims.IP)
+	})
+
+	t.Run("rejects a stolen refresh token used from another IP", func(t *testing.T) {
+		_, refreshToken, err := service.GenerateTokens(userID, "user@example.com", false, "203.0.113.7")
+		require.NoError(t, err)
+
+		_, _, err = service.RefreshTokens(refreshToken, "198.51.100.23")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "bound to another IP")
+	})
+
+	t.Run("accepts a refresh from a new IP within the grace period", func(t *testing.T) {
+		accessToken, refreshToken, err := service.GenerateTokens(userID, "user@example.com", false, "203.0.113.7")
+		require.NoError(t, err)
+
+		// The access token was used from the new IP, starting the grace period
+		accessClaims, err := service.GetJWTService().ValidateToken(accessToken)
+		require.NoError(t, err)
+		service.GetIPChangeTracker().Record(accessClaims, time.Now())
+
+		_, newRefreshToken, err := service.RefreshTokens(refreshToken, "198.51.100.23")
+		require.NoError(t, err)
+
+		claims, err := service.GetJWTService().ValidateToken(newRefreshToken)
+		require.NoError(t, err)
+		assert.Equal(t, "198.51.100.23", claims.IP)
+	})
+
+	t.Run("rejects a refresh from a new IP after the grace period", func(t *testing.T) {
+		accessToken, refreshToken, err := service.GenerateTokens(userID, "user@example.com", false, "203.0.113.7")
+		require.NoError(t, err)
+
+		accessClaims, err := service.GetJWTService().ValidateToken(accessToken)
+		require.NoError(t, err)
+		service.GetIPChangeTracker().Record(accessClaims, time.Now().Add(-2*time.Minute))
+
+		_, _, err = service.RefreshTokens(refreshToken, "198.51.100.23")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "bound to another IP")
+	})
+}
+
+func TestIPChangeTracker_DropsExpiredSessions(t *testing.T) {
+	tracker := NewIPChangeTracker()
+	now := time.Now()
+
+	expired := &JWTClaims{SessionID: "expired"}
+	expired.ExpiresAt = jwt.NewNumericDate(now.Add(time.Minute))
+	active := &JWTClaims{SessionID: "active"}
+	active.ExpiresAt = jwt.NewNumericDate(now.Add(time.Hour))
+
+	changedAt, first := tracker.Record(expired, now)
+	assert.True(t, first)
+	assert.Equal(t, now, changedAt)
+
+	changedAt, first = tracker.Record(expired, now.Add(30*time.Second))
+	assert.False(t, first)
+	assert.Equal(t, now, changedAt)
+
+	tracker.Record(active, now.Add(2*time.Minute))
+	assert.NotContains(t, tracker.changes, "expired")
+	assert.Contains(t, tracker.changes, "active")
+}
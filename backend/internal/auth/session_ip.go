@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// ipChange is when a session was first used from a new IP
+type ipChange struct {
+	changedAt time.Time
+	expiresAt time.Time
+}
+
+// IPChangeTracker remembers when each IP-bound session was first used from an IP
+// other than the one it is bound to. The access and refresh tokens of a pair share a
+// session, so a change seen on the access token also covers refreshing. It is kept in
+// memory, so each instance of the API starts its own grace period.
+type IPChangeTracker struct {
+	mu      sync.Mutex
+	changes map[string]ipChange
+}
+
+// NewIPChangeTracker creates an empty IP change tracker
+func NewIPChangeTracker() *IPChangeTracker {
+	return &IPChangeTracker{changes: make(map[string]ipChange)}
+}
+
+// Record returns when the token's session first changed IP, recording now if this is
+// the first change. Entries are dropped once their token has expired.
+func (t *IPChangeTracker) Record(claims *JWTClaims, now time.Time) (time.Time, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := sessionKey(claims)
+	if change, ok := t.changes[key]; ok {
+		return change.changedAt, false
+	}
+
+	for id, change := range t.changes {
+		if !change.expiresAt.After(now) {
+			delete(t.changes, id)
+		}
+	}
+
+	var expiresAt time.Time
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+	t.changes[key] = ipChange{changedAt: now, expiresAt: expiresAt}
+	return now, true
+}
+
+// ChangedAt returns when the token's session first changed IP, if it has
+func (t *IPChangeTracker) ChangedAt(claims *JWTClaims) (time.Time, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	change, ok := t.changes[sessionKey(claims)]
+	return change.changedAt, ok
+}
+
+// sessionKey identifies the session a token belongs to. Tokens issued before sessions
+// were recorded in the sid claim are their own session.
+func sessionKey(claims *JWTClaims) string {
+	if claims.SessionID != "" {
+		return claims.SessionID
+	}
+	return claims.ID
+}
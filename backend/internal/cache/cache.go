@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"time"
 
+	"bugrelay-backend/internal/metrics"
+
 	"github.com/redis/go-redis/v9"
 )
 
@@ -29,6 +31,14 @@ const (
 	UserCachePrefix       = "user:"
 	ApplicationCachePrefix = "app:"
 	StatsCachePrefix      = "stats:"
+	AuditLogCachePrefix   = "audit_logs:"
+	UserBlocksCachePrefix = "user_blocks:"
+	IdempotencyCachePrefix = "idem:"
+	TagCachePrefix        = "tag:"
+	AnonymousEmailCachePrefix = "anon_email:"
+	ApplicationStatsCachePrefix = "app_stats:"
+	CompanyStatsCachePrefix   = "company_stats:"
+	VoteVelocityCachePrefix   = "votes_24h:"
 )
 
 // Cache durations
@@ -36,6 +46,41 @@ const (
 	ShortCacheDuration  = 5 * time.Minute
 	MediumCacheDuration = 30 * time.Minute
 	LongCacheDuration   = 2 * time.Hour
+
+	AuditLogCacheDuration        = 60 * time.Second
+	UserStatsCacheDuration       = 10 * time.Minute
+	CompanyBugListCacheDuration  = 2 * time.Minute
+	UserBugListCacheDuration     = 2 * time.Minute
+	SearchAnalyticsCacheDuration = time.Hour
+	IdempotencyCacheDuration     = 24 * time.Hour
+	IdempotencyReserveDuration   = time.Minute
+	SimilarBugsCacheDuration     = 30 * time.Second
+	RelatedTagsCacheDuration     = 30 * time.Minute
+	PopularTagsCacheDuration     = 20 * time.Minute
+	TagBugListCacheDuration      = 5 * time.Minute
+	TrendingBugListCacheDuration = 10 * time.Minute
+	CompanyDashboardStatsCacheDuration = 5 * time.Minute
+	BugNotFoundCacheDuration     = 30 * time.Second
+	AnonymousEmailCountDuration  = 24 * time.Hour
+	BugStatisticsCacheDuration   = 10 * time.Minute
+)
+
+// VoteVelocityWindow is the period CountRecentBugVotes counts votes over. Votes are
+// counted in hourly buckets, so the window ends at the start of the next hour.
+const VoteVelocityWindow = 24 * time.Hour
+
+// Cache types counted by the bugrelay_cache_hits_total and bugrelay_cache_misses_total
+// metrics
+const (
+	CacheTypeBug         = "bug"
+	CacheTypeBugList     = "bug_list"
+	CacheTypeCompany     = "company"
+	CacheTypeUser        = "user"
+	CacheTypeApplication = "application"
+	CacheTypeStats       = "stats"
+	CacheTypeSimilarBugs = "similar_bugs"
+	CacheTypeTag         = "tag"
+	CacheTypeAuditLog    = "audit_log"
 )
 
 // Set stores a value in cache with expiration
@@ -66,6 +111,14 @@ func (c *CacheService) Get(ctx context.Context, key string, dest interface{}) er
 	return json.Unmarshal([]byte(data), dest)
 }
 
+// getCounted retrieves a value from cache like Get and counts the lookup as a hit or
+// miss of cacheType
+func (c *CacheService) getCounted(ctx context.Context, cacheType, key string, dest interface{}) error {
+	err := c.Get(ctx, key, dest)
+	metrics.RecordCacheLookup(cacheType, err)
+	return err
+}
+
 // Delete removes a key from cache
 func (c *CacheService) Delete(ctx context.Context, keys ...string) error {
 	if c.client == nil || len(keys) == 0 {
@@ -143,7 +196,21 @@ func (c *CacheService) SetBug(ctx context.Context, bugID string, bug interface{}
 
 func (c *CacheService) GetBug(ctx context.Context, bugID string, dest interface{}) error {
 	key := BugCachePrefix + bugID
-	return c.Get(ctx, key, dest)
+	return c.getCounted(ctx, CacheTypeBug, key, dest)
+}
+
+// SetBugNotFound caches that a bug does not exist, so repeated lookups of a missing
+// bug skip the database. InvalidateBug clears it.
+func (c *CacheService) SetBugNotFound(ctx context.Context, bugID string) error {
+	key := BugCachePrefix + bugID + ":not_found"
+	return c.Set(ctx, key, true, BugNotFoundCacheDuration)
+}
+
+// IsBugNotFound reports whether a bug is cached as not existing
+func (c *CacheService) IsBugNotFound(ctx context.Context, bugID string) bool {
+	var notFound bool
+	key := BugCachePrefix + bugID + ":not_found"
+	return c.Get(ctx, key, &notFound) == nil && notFound
 }
 
 func (c *CacheService) InvalidateBug(ctx context.Context, bugID string) error {
@@ -156,19 +223,54 @@ func (c *CacheService) InvalidateBug(ctx context.Context, bugID string) error {
 	if err := c.DeletePattern(ctx, BugListCachePrefix+"*"); err != nil {
 		return err
 	}
+
+	// And the separately cached relationships of this bug
+	if err := c.DeletePattern(ctx, BugCachePrefix+bugID+":*"); err != nil {
+		return err
+	}
 	
 	return c.Delete(ctx, keys...)
 }
 
+// SetBugWithRelations caches a bug with all its relationships loaded, as returned by
+// batch lookups. InvalidateBug clears it along with the single relationships.
+func (c *CacheService) SetBugWithRelations(ctx context.Context, bugID string, bug interface{}) error {
+	key := BugCachePrefix + bugID + ":full"
+	return c.Set(ctx, key, bug, MediumCacheDuration)
+}
+
+func (c *CacheService) GetBugWithRelations(ctx context.Context, bugID string, dest interface{}) error {
+	key := BugCachePrefix + bugID + ":full"
+	return c.getCounted(ctx, CacheTypeBug, key, dest)
+}
+
+// SetBugRelation caches a single relationship of a bug, e.g. bug:<id>:with_comments
+func (c *CacheService) SetBugRelation(ctx context.Context, bugID, relation string, value interface{}) error {
+	key := BugCachePrefix + bugID + ":with_" + relation
+	return c.Set(ctx, key, value, MediumCacheDuration)
+}
+
+func (c *CacheService) GetBugRelation(ctx context.Context, bugID, relation string, dest interface{}) error {
+	key := BugCachePrefix + bugID + ":with_" + relation
+	return c.getCounted(ctx, CacheTypeBug, key, dest)
+}
+
 // Bug list cache methods
 func (c *CacheService) SetBugList(ctx context.Context, cacheKey string, bugs interface{}) error {
 	key := BugListCachePrefix + cacheKey
 	return c.Set(ctx, key, bugs, ShortCacheDuration)
 }
 
+// SetTrendingBugList caches a bug list sorted by trending score. Trending scores only
+// change slowly, so these lists are kept longer than other bug lists.
+func (c *CacheService) SetTrendingBugList(ctx context.Context, cacheKey string, bugs interface{}) error {
+	key := BugListCachePrefix + cacheKey
+	return c.Set(ctx, key, bugs, TrendingBugListCacheDuration)
+}
+
 func (c *CacheService) GetBugList(ctx context.Context, cacheKey string, dest interface{}) error {
 	key := BugListCachePrefix + cacheKey
-	return c.Get(ctx, key, dest)
+	return c.getCounted(ctx, CacheTypeBugList, key, dest)
 }
 
 // Company cache methods
@@ -179,7 +281,7 @@ func (c *CacheService) SetCompany(ctx context.Context, companyID string, company
 
 func (c *CacheService) GetCompany(ctx context.Context, companyID string, dest interface{}) error {
 	key := CompanyCachePrefix + companyID
-	return c.Get(ctx, key, dest)
+	return c.getCounted(ctx, CacheTypeCompany, key, dest)
 }
 
 func (c *CacheService) InvalidateCompany(ctx context.Context, companyID string) error {
@@ -197,7 +299,7 @@ func (c *CacheService) SetUser(ctx context.Context, userID string, user interfac
 
 func (c *CacheService) GetUser(ctx context.Context, userID string, dest interface{}) error {
 	key := UserCachePrefix + userID
-	return c.Get(ctx, key, dest)
+	return c.getCounted(ctx, CacheTypeUser, key, dest)
 }
 
 func (c *CacheService) InvalidateUser(ctx context.Context, userID string) error {
@@ -207,6 +309,52 @@ func (c *CacheService) InvalidateUser(ctx context.Context, userID string) error
 	return c.Delete(ctx, keys...)
 }
 
+// SetUserBugList caches a filtered page of the bugs a user reported
+func (c *CacheService) SetUserBugList(ctx context.Context, userID, cacheKey string, bugs interface{}) error {
+	key := UserCachePrefix + userID + ":bugs:" + cacheKey
+	return c.Set(ctx, key, bugs, UserBugListCacheDuration)
+}
+
+// GetUserBugList retrieves a cached page of the bugs a user reported
+func (c *CacheService) GetUserBugList(ctx context.Context, userID, cacheKey string, dest interface{}) error {
+	key := UserCachePrefix + userID + ":bugs:" + cacheKey
+	return c.getCounted(ctx, CacheTypeBugList, key, dest)
+}
+
+// InvalidateUserBugList removes every cached page of the bugs a user reported
+func (c *CacheService) InvalidateUserBugList(ctx context.Context, userID string) error {
+	return c.DeletePattern(ctx, UserCachePrefix+userID+":bugs:*")
+}
+
+// SetCompanyBugList caches a filtered page of a company's bugs
+func (c *CacheService) SetCompanyBugList(ctx context.Context, companyID, cacheKey string, bugs interface{}) error {
+	key := CompanyCachePrefix + companyID + ":bugs:" + cacheKey
+	return c.Set(ctx, key, bugs, CompanyBugListCacheDuration)
+}
+
+// GetCompanyBugList retrieves a cached page of a company's bugs
+func (c *CacheService) GetCompanyBugList(ctx context.Context, companyID, cacheKey string, dest interface{}) error {
+	key := CompanyCachePrefix + companyID + ":bugs:" + cacheKey
+	return c.getCounted(ctx, CacheTypeBugList, key, dest)
+}
+
+// SetCompanyDashboardStats caches a company's dashboard bug statistics
+func (c *CacheService) SetCompanyDashboardStats(ctx context.Context, companyID string, stats interface{}) error {
+	key := CompanyCachePrefix + companyID + ":dashboard_stats"
+	return c.Set(ctx, key, stats, CompanyDashboardStatsCacheDuration)
+}
+
+// GetCompanyDashboardStats retrieves a company's cached dashboard bug statistics
+func (c *CacheService) GetCompanyDashboardStats(ctx context.Context, companyID string, dest interface{}) error {
+	key := CompanyCachePrefix + companyID + ":dashboard_stats"
+	return c.getCounted(ctx, CacheTypeStats, key, dest)
+}
+
+// HasCompanyDashboardStats reports whether a company's dashboard bug statistics are cached
+func (c *CacheService) HasCompanyDashboardStats(ctx context.Context, companyID string) (bool, error) {
+	return c.Exists(ctx, CompanyCachePrefix+companyID+":dashboard_stats")
+}
+
 // Application cache methods
 func (c *CacheService) SetApplication(ctx context.Context, appID string, app interface{}) error {
 	key := ApplicationCachePrefix + appID
@@ -215,7 +363,91 @@ func (c *CacheService) SetApplication(ctx context.Context, appID string, app int
 
 func (c *CacheService) GetApplication(ctx context.Context, appID string, dest interface{}) error {
 	key := ApplicationCachePrefix + appID
-	return c.Get(ctx, key, dest)
+	return c.getCounted(ctx, CacheTypeApplication, key, dest)
+}
+
+// SetApplicationHealth caches an application's health score
+func (c *CacheService) SetApplicationHealth(ctx context.Context, appID string, health interface{}) error {
+	key := ApplicationCachePrefix + appID + ":health"
+	return c.Set(ctx, key, health, MediumCacheDuration)
+}
+
+// GetApplicationHealth retrieves an application's cached health score
+func (c *CacheService) GetApplicationHealth(ctx context.Context, appID string, dest interface{}) error {
+	key := ApplicationCachePrefix + appID + ":health"
+	return c.getCounted(ctx, CacheTypeStats, key, dest)
+}
+
+// SetApplicationStats caches an application's bug statistics
+func (c *CacheService) SetApplicationStats(ctx context.Context, appID string, stats interface{}) error {
+	key := ApplicationStatsCachePrefix + appID
+	return c.Set(ctx, key, stats, BugStatisticsCacheDuration)
+}
+
+// GetApplicationStats retrieves an application's cached bug statistics
+func (c *CacheService) GetApplicationStats(ctx context.Context, appID string, dest interface{}) error {
+	key := ApplicationStatsCachePrefix + appID
+	return c.getCounted(ctx, CacheTypeStats, key, dest)
+}
+
+// SetCompanyStats caches the bug statistics of a company's applications
+func (c *CacheService) SetCompanyStats(ctx context.Context, companyID string, stats interface{}) error {
+	key := CompanyStatsCachePrefix + companyID
+	return c.Set(ctx, key, stats, BugStatisticsCacheDuration)
+}
+
+// GetCompanyStats retrieves the cached bug statistics of a company's applications
+func (c *CacheService) GetCompanyStats(ctx context.Context, companyID string, dest interface{}) error {
+	key := CompanyStatsCachePrefix + companyID
+	return c.getCounted(ctx, CacheTypeStats, key, dest)
+}
+
+// SetSimilarBugs caches the suspected duplicates of a submission in an application
+func (c *CacheService) SetSimilarBugs(ctx context.Context, appID, submissionHash string, bugs interface{}) error {
+	key := ApplicationCachePrefix + appID + ":similar:" + submissionHash
+	return c.Set(ctx, key, bugs, SimilarBugsCacheDuration)
+}
+
+// GetSimilarBugs retrieves the cached suspected duplicates of a submission in an application
+func (c *CacheService) GetSimilarBugs(ctx context.Context, appID, submissionHash string, dest interface{}) error {
+	key := ApplicationCachePrefix + appID + ":similar:" + submissionHash
+	return c.getCounted(ctx, CacheTypeSimilarBugs, key, dest)
+}
+
+// SetRelatedTags caches the tags that appear on bugs together with a tag
+func (c *CacheService) SetRelatedTags(ctx context.Context, tag string, related interface{}) error {
+	key := TagCachePrefix + tag + ":related"
+	return c.Set(ctx, key, related, RelatedTagsCacheDuration)
+}
+
+// GetRelatedTags retrieves the cached tags that appear on bugs together with a tag
+func (c *CacheService) GetRelatedTags(ctx context.Context, tag string, dest interface{}) error {
+	key := TagCachePrefix + tag + ":related"
+	return c.getCounted(ctx, CacheTypeTag, key, dest)
+}
+
+// SetTagBugList caches a page of the bugs carrying a tag
+func (c *CacheService) SetTagBugList(ctx context.Context, tag, listKey string, bugs interface{}) error {
+	key := TagCachePrefix + tag + ":bugs:" + listKey
+	return c.Set(ctx, key, bugs, TagBugListCacheDuration)
+}
+
+// GetTagBugList retrieves a cached page of the bugs carrying a tag
+func (c *CacheService) GetTagBugList(ctx context.Context, tag, listKey string, dest interface{}) error {
+	key := TagCachePrefix + tag + ":bugs:" + listKey
+	return c.getCounted(ctx, CacheTypeBugList, key, dest)
+}
+
+// SetPopularTags caches how many bugs carry each tag
+func (c *CacheService) SetPopularTags(ctx context.Context, tags interface{}) error {
+	key := TagCachePrefix + "popular"
+	return c.Set(ctx, key, tags, PopularTagsCacheDuration)
+}
+
+// GetPopularTags retrieves the cached number of bugs carrying each tag
+func (c *CacheService) GetPopularTags(ctx context.Context, dest interface{}) error {
+	key := TagCachePrefix + "popular"
+	return c.getCounted(ctx, CacheTypeTag, key, dest)
 }
 
 // Statistics cache methods
@@ -226,9 +458,216 @@ func (c *CacheService) SetStats(ctx context.Context, statsKey string, stats inte
 
 func (c *CacheService) GetStats(ctx context.Context, statsKey string, dest interface{}) error {
 	key := StatsCachePrefix + statsKey
+	return c.getCounted(ctx, CacheTypeStats, key, dest)
+}
+
+// SetUserStats caches a user's contribution statistics
+func (c *CacheService) SetUserStats(ctx context.Context, userID string, stats interface{}) error {
+	key := StatsCachePrefix + "user:" + userID
+	return c.Set(ctx, key, stats, UserStatsCacheDuration)
+}
+
+// GetUserStats retrieves a user's cached contribution statistics
+func (c *CacheService) GetUserStats(ctx context.Context, userID string, dest interface{}) error {
+	key := StatsCachePrefix + "user:" + userID
+	return c.getCounted(ctx, CacheTypeStats, key, dest)
+}
+
+// SetSearchAnalytics caches search analytics for a look-back window
+func (c *CacheService) SetSearchAnalytics(ctx context.Context, days int, analytics interface{}) error {
+	key := fmt.Sprintf("%ssearch:%d", StatsCachePrefix, days)
+	return c.Set(ctx, key, analytics, SearchAnalyticsCacheDuration)
+}
+
+// GetSearchAnalytics retrieves cached search analytics for a look-back window
+func (c *CacheService) GetSearchAnalytics(ctx context.Context, days int, dest interface{}) error {
+	key := fmt.Sprintf("%ssearch:%d", StatsCachePrefix, days)
+	return c.getCounted(ctx, CacheTypeStats, key, dest)
+}
+
+// SetIdempotentResponse stores the response to a request made with an idempotency
+// key so that retries of the request can be answered with it
+func (c *CacheService) SetIdempotentResponse(ctx context.Context, keyHash string, response interface{}) error {
+	key := IdempotencyCachePrefix + keyHash
+	return c.Set(ctx, key, response, IdempotencyCacheDuration)
+}
+
+// ReserveIdempotencyKey claims an idempotency key for a request in progress by
+// storing marker under it, unless the key already holds a marker or a response. The
+// reservation expires after IdempotencyReserveDuration, so a request that never
+// finishes does not hold the key for the full day. Without Redis every key is
+// reserved, and requests are not deduplicated.
+func (c *CacheService) ReserveIdempotencyKey(ctx context.Context, keyHash string, marker interface{}) (bool, error) {
+	if c.client == nil {
+		return true, nil
+	}
+	return c.SetNX(ctx, IdempotencyCachePrefix+keyHash, marker, IdempotencyReserveDuration)
+}
+
+// ReleaseIdempotencyKey removes the reservation of a request that failed, so it can
+// be retried with the same key
+func (c *CacheService) ReleaseIdempotencyKey(ctx context.Context, keyHash string) error {
+	return c.Delete(ctx, IdempotencyCachePrefix+keyHash)
+}
+
+// GetIdempotentResponse retrieves the stored response for an idempotency key
+func (c *CacheService) GetIdempotentResponse(ctx context.Context, keyHash string, dest interface{}) error {
+	key := IdempotencyCachePrefix + keyHash
+	return c.Get(ctx, key, dest)
+}
+
+// GetAnonymousEmailCount returns how many bugs were submitted anonymously with a
+// contact email in the current day, zero when there is no count
+func (c *CacheService) GetAnonymousEmailCount(ctx context.Context, emailHash string) (int64, error) {
+	if c.client == nil {
+		return 0, nil
+	}
+
+	key := AnonymousEmailCachePrefix + emailHash + ":daily_count"
+	count, err := c.client.Get(ctx, key).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return count, err
+}
+
+// IncrementAnonymousEmailCount counts a bug submitted anonymously with a contact
+// email. The count expires a day after the first submission rather than being
+// extended by each one.
+func (c *CacheService) IncrementAnonymousEmailCount(ctx context.Context, emailHash string) (int64, error) {
+	if c.client == nil {
+		return 0, nil
+	}
+
+	key := AnonymousEmailCachePrefix + emailHash + ":daily_count"
+	count, err := c.client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if count == 1 {
+		if err := c.client.Expire(ctx, key, AnonymousEmailCountDuration).Err(); err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+// voteVelocityKey is the key of the bucket counting a bug's votes in the hour of at
+func voteVelocityKey(bugID string, at time.Time) string {
+	return fmt.Sprintf("%s%s:%d", VoteVelocityCachePrefix, bugID, at.Truncate(time.Hour).Unix())
+}
+
+// RecordBugVote counts a vote cast on a bug at the given time
+func (c *CacheService) RecordBugVote(ctx context.Context, bugID string, at time.Time) error {
+	return c.adjustBugVotes(ctx, bugID, at, 1)
+}
+
+// RemoveBugVote uncounts a vote withdrawn from a bug at the given time, so voting
+// and unvoting repeatedly does not add up
+func (c *CacheService) RemoveBugVote(ctx context.Context, bugID string, at time.Time) error {
+	return c.adjustBugVotes(ctx, bugID, at, -1)
+}
+
+// adjustBugVotes adds delta to the bucket of the hour of at. Buckets outlive the
+// window by an hour so the oldest bucket of a window is still there to count.
+func (c *CacheService) adjustBugVotes(ctx context.Context, bugID string, at time.Time, delta int64) error {
+	if c.client == nil {
+		return nil
+	}
+
+	key := voteVelocityKey(bugID, at)
+	pipe := c.client.Pipeline()
+	if delta > 0 {
+		pipe.Incr(ctx, key)
+	} else {
+		pipe.Decr(ctx, key)
+	}
+	pipe.Expire(ctx, key, VoteVelocityWindow+time.Hour)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// CountRecentBugVotes returns how many votes a bug gained, net of votes withdrawn, in
+// the VoteVelocityWindow hours up to and including the hour of now
+func (c *CacheService) CountRecentBugVotes(ctx context.Context, bugID string, now time.Time) (int64, error) {
+	if c.client == nil {
+		return 0, nil
+	}
+
+	hours := int(VoteVelocityWindow / time.Hour)
+	pipe := c.client.Pipeline()
+	buckets := make([]*redis.StringCmd, hours)
+	for i := range buckets {
+		buckets[i] = pipe.Get(ctx, voteVelocityKey(bugID, now.Add(-time.Duration(i)*time.Hour)))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, bucket := range buckets {
+		count, err := bucket.Int64()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		total += count
+	}
+	// Votes cast before the window and withdrawn within it are uncounted too
+	if total < 0 {
+		total = 0
+	}
+	return total, nil
+}
+
+// Audit log cache methods
+func (c *CacheService) SetAuditLogs(ctx context.Context, cacheKey string, logs interface{}) error {
+	key := AuditLogCachePrefix + cacheKey
+	return c.Set(ctx, key, logs, AuditLogCacheDuration)
+}
+
+func (c *CacheService) GetAuditLogs(ctx context.Context, cacheKey string, dest interface{}) error {
+	key := AuditLogCachePrefix + cacheKey
+	return c.getCounted(ctx, CacheTypeAuditLog, key, dest)
+}
+
+// User block list cache methods
+func (c *CacheService) SetUserBlocks(ctx context.Context, userID string, blocks interface{}) error {
+	key := UserBlocksCachePrefix + userID
+	return c.Set(ctx, key, blocks, MediumCacheDuration)
+}
+
+func (c *CacheService) GetUserBlocks(ctx context.Context, userID string, dest interface{}) error {
+	key := UserBlocksCachePrefix + userID
+	return c.Get(ctx, key, dest)
+}
+
+func (c *CacheService) InvalidateUserBlocks(ctx context.Context, userIDs ...string) error {
+	keys := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		keys = append(keys, UserBlocksCachePrefix+userID)
+	}
+	return c.Delete(ctx, keys...)
+}
+
+// Notification preferences cache methods
+func (c *CacheService) SetNotificationPreferences(ctx context.Context, userID string, preferences interface{}) error {
+	key := UserCachePrefix + userID + ":prefs"
+	return c.Set(ctx, key, preferences, ShortCacheDuration)
+}
+
+func (c *CacheService) GetNotificationPreferences(ctx context.Context, userID string, dest interface{}) error {
+	key := UserCachePrefix + userID + ":prefs"
 	return c.Get(ctx, key, dest)
 }
 
+func (c *CacheService) InvalidateNotificationPreferences(ctx context.Context, userID string) error {
+	key := UserCachePrefix + userID + ":prefs"
+	return c.Delete(ctx, key)
+}
+
 // GenerateCacheKey creates a consistent cache key from parameters
 func GenerateCacheKey(params ...interface{}) string {
 	var keyParts []string
@@ -6,6 +6,9 @@ import (
 	"testing"
 	"time"
 
+	"bugrelay-backend/internal/metrics"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 )
@@ -238,6 +241,46 @@ func TestCacheService_BugMethods(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestCacheService_LookupMetrics(t *testing.T) {
+	cache := setupTestCache()
+	ctx := context.Background()
+
+	misses := func(cacheType string) float64 {
+		return testutil.ToFloat64(metrics.CacheMissesTotal.WithLabelValues(cacheType))
+	}
+	bugMisses, listMisses := misses(CacheTypeBug), misses(CacheTypeBugList)
+
+	var result map[string]interface{}
+	cache.GetBug(ctx, "bug-123", &result)
+	cache.GetBugRelation(ctx, "bug-123", "comments", &result)
+	cache.GetBugList(ctx, "page-1", &result)
+
+	// Lookups are counted by cache type; without Redis every lookup misses
+	assert.Equal(t, bugMisses+2, misses(CacheTypeBug))
+	assert.Equal(t, listMisses+1, misses(CacheTypeBugList))
+
+	// Plain Get is not counted
+	cache.Get(ctx, BugCachePrefix+"bug-123", &result)
+	assert.Equal(t, bugMisses+2, misses(CacheTypeBug))
+}
+
+func TestCacheService_BugRelationMethods(t *testing.T) {
+	cache := setupTestCache()
+	ctx := context.Background()
+
+	comments := []map[string]interface{}{
+		{"id": "comment-1", "content": "Test comment"},
+	}
+
+	// Test per-relationship bug cache methods
+	err := cache.SetBugRelation(ctx, "bug-123", "comments", comments)
+	assert.NoError(t, err)
+
+	var result []map[string]interface{}
+	err = cache.GetBugRelation(ctx, "bug-123", "comments", &result)
+	assert.Equal(t, redis.Nil, err) // Should be cache miss without Redis
+}
+
 func TestCacheService_BugListMethods(t *testing.T) {
 	cache := setupTestCache()
 	ctx := context.Background()
@@ -0,0 +1,76 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"bugrelay-backend/internal/config"
+)
+
+// Providers that CAPTCHA_PROVIDER selects between
+const (
+	ProviderRecaptcha = "recaptcha"
+	ProviderHCaptcha  = "hcaptcha"
+)
+
+// Verifier checks a captcha token with the provider that issued it
+type Verifier interface {
+	// Verify asks the provider whether token was solved. An error means the provider
+	// could not be asked, not that the token was rejected.
+	Verify(ctx context.Context, token string) (Result, error)
+}
+
+// Result is a provider's verdict on a token
+type Result struct {
+	// Success reports whether the provider accepted the token
+	Success bool
+	// Score is the reCAPTCHA v3 likelihood, from 0 to 1, that the token was solved
+	// by a person. It is zero for reCAPTCHA v2 and hCaptcha, which only report success.
+	Score float64
+}
+
+// New creates the verifier for the configured provider, or nil when the provider has
+// no secret key and tokens are not checked. Providers other than hcaptcha use
+// reCAPTCHA. Verification requests are made with client.
+func New(cfg config.CaptchaConfig, client *http.Client) Verifier {
+	if cfg.Provider == ProviderHCaptcha {
+		if cfg.HCaptchaSecretKey == "" {
+			return nil
+		}
+		return NewHCaptchaVerifier(cfg.HCaptchaSecretKey, client)
+	}
+
+	if cfg.RecaptchaSecretKey == "" {
+		return nil
+	}
+	return NewRecaptchaVerifier(cfg.RecaptchaSecretKey, client)
+}
+
+// siteverify posts a token to a provider's verification endpoint and decodes the
+// response into dest. reCAPTCHA and hCaptcha share the request format.
+func siteverify(ctx context.Context, client *http.Client, verifyURL, secret, token string, dest interface{}) error {
+	data := url.Values{}
+	data.Set("secret", secret)
+	data.Set("response", token)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, verifyURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("captcha verification returned status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(dest)
+}
@@ -0,0 +1,118 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bugrelay-backend/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSiteverifyServer stands in for a provider's verification endpoint. It expects
+// the given secret and token and replies with response.
+func newSiteverifyServer(t *testing.T, secret, token string, response interface{}) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/x-www-form-urlencoded", r.Header.Get("Content-Type"))
+		assert.Equal(t, secret, r.FormValue("secret"))
+		assert.Equal(t, token, r.FormValue("response"))
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRecaptchaVerifier_Verify(t *testing.T) {
+	tests := []struct {
+		name     string
+		response map[string]interface{}
+		expected Result
+	}{
+		{"v3 pass", map[string]interface{}{"success": true, "score": 0.9, "action": "submit_bug"}, Result{Success: true, Score: 0.9}},
+		{"v2 pass", map[string]interface{}{"success": true}, Result{Success: true}},
+		{"fail", map[string]interface{}{"success": false, "error-codes": []string{"invalid-input-response"}}, Result{Success: false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newSiteverifyServer(t, "recaptcha-secret", "token", tt.response)
+			verifier := NewRecaptchaVerifier("recaptcha-secret", server.Client())
+			verifier.SetVerifyURL(server.URL)
+
+			result, err := verifier.Verify(context.Background(), "token")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestHCaptchaVerifier_Verify(t *testing.T) {
+	tests := []struct {
+		name     string
+		response map[string]interface{}
+		expected Result
+	}{
+		{"pass", map[string]interface{}{"success": true, "hostname": "bugrelay.com"}, Result{Success: true}},
+		// Enterprise risk scores are ignored
+		{"pass with risk score", map[string]interface{}{"success": true, "score": 0.8}, Result{Success: true}},
+		{"fail", map[string]interface{}{"success": false, "error-codes": []string{"invalid-or-already-seen-response"}}, Result{Success: false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newSiteverifyServer(t, "hcaptcha-secret", "token", tt.response)
+			verifier := NewHCaptchaVerifier("hcaptcha-secret", server.Client())
+			verifier.SetVerifyURL(server.URL)
+
+			result, err := verifier.Verify(context.Background(), "token")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestVerifier_ProviderErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/unavailable":
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte("<html>not json</html>"))
+		}
+	}))
+	defer server.Close()
+
+	recaptcha := NewRecaptchaVerifier("secret", server.Client())
+	hcaptcha := NewHCaptchaVerifier("secret", server.Client())
+
+	for _, path := range []string{"/unavailable", "/invalid"} {
+		recaptcha.SetVerifyURL(server.URL + path)
+		_, err := recaptcha.Verify(context.Background(), "token")
+		assert.Error(t, err, "recaptcha %s", path)
+
+		hcaptcha.SetVerifyURL(server.URL + path)
+		_, err = hcaptcha.Verify(context.Background(), "token")
+		assert.Error(t, err, "hcaptcha %s", path)
+	}
+}
+
+func TestNew(t *testing.T) {
+	client := http.DefaultClient
+
+	recaptcha := New(config.CaptchaConfig{Provider: ProviderRecaptcha, RecaptchaSecretKey: "secret"}, client)
+	require.IsType(t, &RecaptchaVerifier{}, recaptcha)
+	assert.Equal(t, RecaptchaVerifyURL, recaptcha.(*RecaptchaVerifier).verifyURL)
+
+	hcaptcha := New(config.CaptchaConfig{Provider: ProviderHCaptcha, HCaptchaSecretKey: "secret"}, client)
+	require.IsType(t, &HCaptchaVerifier{}, hcaptcha)
+	assert.Equal(t, HCaptchaVerifyURL, hcaptcha.(*HCaptchaVerifier).verifyURL)
+
+	// The selected provider's secret is required, even when the other has one
+	assert.Nil(t, New(config.CaptchaConfig{Provider: ProviderHCaptcha, RecaptchaSecretKey: "secret"}, client))
+	assert.Nil(t, New(config.CaptchaConfig{Provider: ProviderRecaptcha, HCaptchaSecretKey: "secret"}, client))
+}
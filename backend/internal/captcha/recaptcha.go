@@ -0,0 +1,46 @@
+package captcha
+
+import (
+	"context"
+	"net/http"
+)
+
+// RecaptchaVerifyURL is Google's reCAPTCHA token verification endpoint
+const RecaptchaVerifyURL = "https://www.google.com/recaptcha/api/siteverify"
+
+// recaptchaResponse is the response from Google's reCAPTCHA API
+type recaptchaResponse struct {
+	Success     bool     `json:"success"`
+	Score       float64  `json:"score,omitempty"`
+	Action      string   `json:"action,omitempty"`
+	ChallengeTS string   `json:"challenge_ts,omitempty"`
+	Hostname    string   `json:"hostname,omitempty"`
+	ErrorCodes  []string `json:"error-codes,omitempty"`
+}
+
+// RecaptchaVerifier checks reCAPTCHA v2 and v3 tokens
+type RecaptchaVerifier struct {
+	secret    string
+	verifyURL string
+	client    *http.Client
+}
+
+// NewRecaptchaVerifier creates a verifier that checks tokens with Google using secret
+func NewRecaptchaVerifier(secret string, client *http.Client) *RecaptchaVerifier {
+	return &RecaptchaVerifier{secret: secret, verifyURL: RecaptchaVerifyURL, client: client}
+}
+
+// SetVerifyURL points the verifier at another verification endpoint
+func (v *RecaptchaVerifier) SetVerifyURL(verifyURL string) {
+	v.verifyURL = verifyURL
+}
+
+// Verify checks the token with Google. v3 tokens carry a score; v2 tokens only
+// succeed or fail.
+func (v *RecaptchaVerifier) Verify(ctx context.Context, token string) (Result, error) {
+	var resp recaptchaResponse
+	if err := siteverify(ctx, v.client, v.verifyURL, v.secret, token, &resp); err != nil {
+		return Result{}, err
+	}
+	return Result{Success: resp.Success, Score: resp.Score}, nil
+}
@@ -3,17 +3,37 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type Config struct {
-	Database  DatabaseConfig
-	Redis     RedisConfig
-	JWT       JWTConfig
-	OAuth     OAuthConfig
-	Server    ServerConfig
-	Recaptcha RecaptchaConfig
-	Logger    LoggerConfig
+	Database      DatabaseConfig
+	Redis         RedisConfig
+	JWT           JWTConfig
+	Auth          AuthConfig
+	OAuth         OAuthConfig
+	Server        ServerConfig
+	Captcha       CaptchaConfig
+	Logger        LoggerConfig
+	Features      FeaturesConfig
+	SMTP          SMTPConfig
+	Outbox        OutboxConfig
+	Storage       StorageConfig
+	Spam          SpamConfig
+	Validation    ValidationConfig
+	RateLimit     RateLimitConfig
+	Notifications NotificationsConfig
+	Pagination    PaginationConfig
+	Sentry        SentryConfig
+	Companies     CompaniesConfig
+	Tags          TagsConfig
+	Trending      TrendingConfig
+	Escalation    EscalationConfig
+	External      ExternalConfig
+	Webhooks      WebhooksConfig
+	GeoIP         GeoIPConfig
+	Metrics       MetricsConfig
 }
 
 type DatabaseConfig struct {
@@ -38,6 +58,15 @@ type JWTConfig struct {
 	RefreshTokenTTL  time.Duration
 }
 
+// AuthConfig holds session security settings. With BindSessionToIP, access tokens
+// only work from the IP they were issued to. IPChangeGracePeriodMinutes lets a
+// session keep working for that long after its IP first changes, so mobile users
+// switching networks can refresh their tokens.
+type AuthConfig struct {
+	BindSessionToIP            bool
+	IPChangeGracePeriodMinutes int
+}
+
 type OAuthConfig struct {
 	GoogleClientID     string
 	GoogleClientSecret string
@@ -48,13 +77,40 @@ type OAuthConfig struct {
 
 type ServerConfig struct {
 	Environment string
+	// GinMode is the Gin mode the router runs in: debug, release or test. It
+	// defaults to release in production and debug elsewhere.
+	GinMode     string
 	Port        string
 	LogsAPIKey  string
+	FrontendURL string
+	// MaxRequestBodyBytes limits request bodies on all routes except attachment uploads
+	MaxRequestBodyBytes int64
+	// HTMLRenderingEnabled serves bug listings and bug pages as HTML, rendered from the
+	// templates in TemplatesDir, to clients that ask for text/html
+	HTMLRenderingEnabled bool
+	TemplatesDir         string
+	// APIVersion is the newest API version served. /api/v1 is always served.
+	APIVersion string
+	// StripNullsEnabled removes null fields from JSON responses up to
+	// StripNullsMaxBytes. Only StripNullsKeys are removed when set, and
+	// StripNullsKeepKeys are always kept.
+	StripNullsEnabled  bool
+	StripNullsKeys     []string
+	StripNullsKeepKeys []string
+	StripNullsMaxBytes int
 }
 
-type RecaptchaConfig struct {
-	SecretKey string
-	SiteKey   string
+// CaptchaConfig selects the service that checks captcha tokens on anonymous bug
+// submissions. Tokens are not checked when the selected provider has no secret key.
+type CaptchaConfig struct {
+	// Provider is "recaptcha" or "hcaptcha"
+	Provider           string
+	RecaptchaSecretKey string
+	RecaptchaSiteKey   string
+	// RecaptchaScoreThreshold is the lowest reCAPTCHA v3 score a submission may have
+	RecaptchaScoreThreshold float64
+	HCaptchaSecretKey       string
+	HCaptchaSiteKey         string
 }
 
 type LoggerConfig struct {
@@ -67,6 +123,163 @@ type LoggerConfig struct {
 	Compress   bool
 }
 
+type FeaturesConfig struct {
+	MaterializedDashboard    bool
+	ParallelDashboardQueries bool
+}
+
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	// Directory with the account email templates, e.g. password reset
+	TemplateDir string
+}
+
+type OutboxConfig struct {
+	PollInterval time.Duration
+	WebhookURL   string
+	AdminEmail   string
+}
+
+type SpamConfig struct {
+	ScoreThreshold float64
+}
+
+// ValidationConfig holds the minimum word counts for bug submissions, so reports
+// like "broken" are rejected. Admins can skip the checks. Comments and company
+// responses must contain CommentMinChars non-whitespace characters. Bug tags are
+// limited in number and length (in characters).
+type ValidationConfig struct {
+	BugTitleMinWords       int
+	BugDescriptionMinWords int
+	CommentMinChars        int
+	MaxTagsPerBug          int
+	MinTagLength           int
+	MaxTagLength           int
+}
+
+// NotificationsConfig holds the vote counts at which a bug's reporter is notified
+// that their report is gaining traction
+type NotificationsConfig struct {
+	VoteMilestones []int
+}
+
+// PaginationConfig holds the largest page size of each route group's listings.
+// Admin listings use GlobalOverrideMaxLimit, so admins can page through more at once.
+type PaginationConfig struct {
+	BugListMaxLimit        int
+	CompanyListMaxLimit    int
+	GlobalOverrideMaxLimit int
+}
+
+// CompaniesConfig holds company team management settings
+type CompaniesConfig struct {
+	// InviteLinkMaxBypasses is how many people whose email is outside the company's
+	// domain a single invite link can admit
+	InviteLinkMaxBypasses int
+}
+
+// TagsConfig holds how popular tags are grouped. TagGroups maps a group name to the
+// tag patterns it collects, where * matches any characters, e.g. "*-bug".
+type TagsConfig struct {
+	TagGroups map[string][]string
+}
+
+// TrendingConfig tunes the trending bug sort. Bugs' trending scores,
+// vote_count / (hours_since_created + 2) ^ Gravity, are refreshed every 10 minutes,
+// and only bugs created in the last WindowDays are scored and listed. Zero
+// WindowDays lists bugs of any age.
+type TrendingConfig struct {
+	Gravity    float64
+	WindowDays int
+}
+
+// EscalationConfig sets how many votes in 24 hours raise an unresolved bug to high
+// or critical priority. Bugs are only escalated, never lowered. Zero disables a level.
+type EscalationConfig struct {
+	HighVotes24h     int
+	CriticalVotes24h int
+}
+
+// ExternalConfig holds settings for requests to external services such as captcha providers
+type ExternalConfig struct {
+	// HTTPClientTimeoutSeconds bounds each request, including reading the response
+	HTTPClientTimeoutSeconds int
+}
+
+// WebhooksConfig holds settings for delivering outbox events to the webhook URL and
+// bug events to company webhooks
+type WebhooksConfig struct {
+	// DeliveryTimeoutSeconds bounds each delivery attempt. Timed out deliveries are
+	// retried like other failures.
+	DeliveryTimeoutSeconds int
+	// Workers is the number of company webhook deliveries made at once
+	Workers int
+}
+
+// GeoIPConfig locates bug submitters for geographic analysis
+type GeoIPConfig struct {
+	// DBPath is the MaxMind GeoLite2 or GeoIP2 City database, used to locate bug
+	// submitters and for the geographic rate limits. Bugs are not located and every
+	// client gets the default geographic limit when it is empty or the file is missing.
+	DBPath string
+}
+
+// MetricsConfig configures the Prometheus metrics endpoint
+type MetricsConfig struct {
+	// Token is the shared secret scrapers send in the X-Metrics-Token header. The
+	// endpoint is not served when it is empty.
+	Token string
+}
+
+// SentryConfig configures error reporting to Sentry. Reporting is off when DSN is empty.
+type SentryConfig struct {
+	DSN string
+}
+
+// RateLimitWindow is a sliding window rate limit: at most MaxRequests per IP in
+// any WindowSeconds long period
+type RateLimitWindow struct {
+	WindowSeconds int
+	MaxRequests   int
+}
+
+type RateLimitConfig struct {
+	// General limits anonymous requests per IP. Authenticated users are limited to
+	// AuthenticatedPerMinute per user instead.
+	General       RateLimitWindow
+	BugSubmission RateLimitWindow
+	// AuthenticatedPerMinute is how many requests each authenticated user can make a
+	// minute, and WritePerMinute how many bugs, votes and comments they can submit
+	AuthenticatedPerMinute int
+	WritePerMinute         int
+	// Export limits each user's bug exports
+	Export RateLimitWindow
+	// GeoLimits are per-minute request limits per IP by country code, with "default"
+	// for other countries and unknown IPs. Countries are looked up in the GeoIP
+	// database.
+	GeoLimits map[string]int
+	// AnonymousBugsPerEmailPerDay is how many bugs can be submitted anonymously with
+	// the same contact email each day. Zero disables the limit.
+	AnonymousBugsPerEmailPerDay int
+}
+
+type StorageConfig struct {
+	Backend           string
+	S3Bucket          string
+	S3Region          string
+	S3Endpoint        string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	// S3ACL is the canned ACL attachments and logos are uploaded with. Empty sends
+	// none, for buckets with ACLs disabled.
+	S3ACL         string
+	PresignExpiry time.Duration
+}
+
 func Load() *Config {
 	return &Config{
 		Database: DatabaseConfig{
@@ -88,6 +301,10 @@ func Load() *Config {
 			AccessTokenTTL:   getDurationEnv("JWT_ACCESS_TOKEN_TTL", 15*time.Minute),
 			RefreshTokenTTL:  getDurationEnv("JWT_REFRESH_TOKEN_TTL", 7*24*time.Hour),
 		},
+		Auth: AuthConfig{
+			BindSessionToIP:            getBoolEnv("AUTH_BIND_SESSION_TO_IP", false),
+			IPChangeGracePeriodMinutes: getIntEnv("AUTH_IP_CHANGE_GRACE_PERIOD_MINUTES", 0),
+		},
 		OAuth: OAuthConfig{
 			GoogleClientID:     getEnv("GOOGLE_CLIENT_ID", ""),
 			GoogleClientSecret: getEnv("GOOGLE_CLIENT_SECRET", ""),
@@ -96,13 +313,27 @@ func Load() *Config {
 			RedirectURL:        getEnv("OAUTH_REDIRECT_URL", "http://localhost:8080/api/v1/auth/oauth/callback"),
 		},
 		Server: ServerConfig{
-			Environment: getEnv("ENVIRONMENT", "development"),
-			Port:        getEnv("PORT", "8080"),
-			LogsAPIKey:  getEnv("LOGS_API_KEY", "dev-api-key"),
+			Environment:          getEnv("ENVIRONMENT", "development"),
+			GinMode:              getEnv("GIN_MODE", defaultGinMode(getEnv("ENVIRONMENT", "development"))),
+			Port:                 getEnv("PORT", "8080"),
+			LogsAPIKey:           getEnv("LOGS_API_KEY", "dev-api-key"),
+			FrontendURL:          strings.TrimRight(getEnv("FRONTEND_URL", "http://localhost:3000"), "/"),
+			MaxRequestBodyBytes:  getInt64Env("MAX_REQUEST_BODY_BYTES", 1<<20),
+			HTMLRenderingEnabled: getBoolEnv("HTML_RENDERING_ENABLED", false),
+			TemplatesDir:         getEnv("TEMPLATES_DIR", "templates/html"),
+			APIVersion:           getEnv("API_VERSION", "v2"),
+			StripNullsEnabled:    getBoolEnv("STRIP_NULLS_ENABLED", false),
+			StripNullsKeys:       getStringSliceEnv("STRIP_NULLS_KEYS", nil),
+			StripNullsKeepKeys:   getStringSliceEnv("STRIP_NULLS_KEEP_KEYS", []string{"resolved_at", "reporter"}),
+			StripNullsMaxBytes:   getIntEnv("STRIP_NULLS_MAX_BYTES", 512<<10),
 		},
-		Recaptcha: RecaptchaConfig{
-			SecretKey: getEnv("RECAPTCHA_SECRET_KEY", ""),
-			SiteKey:   getEnv("RECAPTCHA_SITE_KEY", ""),
+		Captcha: CaptchaConfig{
+			Provider:                getEnv("CAPTCHA_PROVIDER", "recaptcha"),
+			RecaptchaSecretKey:      getEnv("RECAPTCHA_SECRET_KEY", ""),
+			RecaptchaSiteKey:        getEnv("RECAPTCHA_SITE_KEY", ""),
+			RecaptchaScoreThreshold: getFloatEnv("RECAPTCHA_SCORE_THRESHOLD", 0.5),
+			HCaptchaSecretKey:       getEnv("HCAPTCHA_SECRET_KEY", ""),
+			HCaptchaSiteKey:         getEnv("HCAPTCHA_SITE_KEY", ""),
 		},
 		Logger: LoggerConfig{
 			Level:      getEnv("LOG_LEVEL", "info"),
@@ -113,7 +344,115 @@ func Load() *Config {
 			MaxAge:     getIntEnv("LOG_MAX_AGE", 28),
 			Compress:   getBoolEnv("LOG_COMPRESS", true),
 		},
+		Features: FeaturesConfig{
+			MaterializedDashboard:    getBoolEnv("FEATURE_MATERIALIZED_DASHBOARD", false),
+			ParallelDashboardQueries: getBoolEnv("FEATURE_PARALLEL_DASHBOARD_QUERIES", false),
+		},
+		SMTP: SMTPConfig{
+			Host:        getEnv("SMTP_HOST", ""),
+			Port:        getEnv("SMTP_PORT", "587"),
+			Username:    getEnv("SMTP_USERNAME", ""),
+			Password:    getEnv("SMTP_PASSWORD", ""),
+			From:        getEnv("SMTP_FROM_EMAIL", "noreply@bugrelay.com"),
+			TemplateDir: getEnv("EMAIL_TEMPLATE_DIR", "templates/email"),
+		},
+		Outbox: OutboxConfig{
+			PollInterval: getDurationEnv("OUTBOX_POLL_INTERVAL", 10*time.Second),
+			WebhookURL:   getEnv("OUTBOX_WEBHOOK_URL", ""),
+			AdminEmail:   getEnv("OUTBOX_ADMIN_EMAIL", ""),
+		},
+		Storage: StorageConfig{
+			Backend:           getEnv("STORAGE_BACKEND", "local"),
+			S3Bucket:          getEnv("STORAGE_S3_BUCKET", ""),
+			S3Region:          getEnv("STORAGE_S3_REGION", "us-east-1"),
+			S3Endpoint:        getEnv("STORAGE_S3_ENDPOINT", ""),
+			S3AccessKeyID:     getEnv("AWS_ACCESS_KEY_ID", ""),
+			S3SecretAccessKey: getEnv("AWS_SECRET_ACCESS_KEY", ""),
+			S3ACL:             getEnv("STORAGE_S3_ACL", "public-read"),
+			PresignExpiry:     getDurationEnv("STORAGE_PRESIGN_EXPIRY", 15*time.Minute),
+		},
+		Spam: SpamConfig{
+			ScoreThreshold: getFloatEnv("SPAM_SCORE_THRESHOLD", 0.8),
+		},
+		Validation: ValidationConfig{
+			BugTitleMinWords:       getIntEnv("BUG_TITLE_MIN_WORDS", 2),
+			BugDescriptionMinWords: getIntEnv("BUG_DESCRIPTION_MIN_WORDS", 5),
+			CommentMinChars:        getIntEnv("COMMENT_MIN_CHARS", 10),
+			MaxTagsPerBug:          getIntEnv("MAX_TAGS_PER_BUG", 10),
+			MinTagLength:           getIntEnv("MIN_TAG_LENGTH", 2),
+			MaxTagLength:           getIntEnv("MAX_TAG_LENGTH", 50),
+		},
+		RateLimit: RateLimitConfig{
+			General: RateLimitWindow{
+				WindowSeconds: getIntEnv("RATE_LIMIT_GENERAL_WINDOW_SECONDS", 60),
+				MaxRequests:   getIntEnv("RATE_LIMIT_GENERAL_MAX_REQUESTS", 60),
+			},
+			BugSubmission: RateLimitWindow{
+				WindowSeconds: getIntEnv("RATE_LIMIT_BUG_SUBMISSION_WINDOW_SECONDS", 60),
+				MaxRequests:   getIntEnv("RATE_LIMIT_BUG_SUBMISSION_MAX_REQUESTS", 5),
+			},
+			AuthenticatedPerMinute: getIntEnv("RATE_LIMIT_AUTHENTICATED_PER_MINUTE", 300),
+			WritePerMinute:         getIntEnv("RATE_LIMIT_WRITE_PER_MINUTE", 20),
+			Export: RateLimitWindow{
+				WindowSeconds: getIntEnv("RATE_LIMIT_EXPORT_WINDOW_SECONDS", 60),
+				MaxRequests:   getIntEnv("RATE_LIMIT_EXPORT_MAX_REQUESTS", 1),
+			},
+			GeoLimits: getIntMapEnv("RATE_LIMIT_GEO_LIMITS", nil),
+
+			AnonymousBugsPerEmailPerDay: getIntEnv("RATE_LIMIT_ANONYMOUS_BUGS_PER_EMAIL_PER_DAY", 3),
+		},
+		Notifications: NotificationsConfig{
+			VoteMilestones: getIntSliceEnv("NOTIFICATION_VOTE_MILESTONES", []int{10, 50, 100, 500}),
+		},
+		Pagination: PaginationConfig{
+			BugListMaxLimit:        getIntEnv("PAGINATION_BUG_LIST_MAX_LIMIT", 100),
+			CompanyListMaxLimit:    getIntEnv("PAGINATION_COMPANY_LIST_MAX_LIMIT", 50),
+			GlobalOverrideMaxLimit: getIntEnv("PAGINATION_GLOBAL_OVERRIDE_MAX_LIMIT", 500),
+		},
+		Sentry: SentryConfig{
+			DSN: getEnv("SENTRY_DSN", ""),
+		},
+		Companies: CompaniesConfig{
+			InviteLinkMaxBypasses: getIntEnv("COMPANY_INVITE_LINK_MAX_BYPASSES", 3),
+		},
+		Tags: TagsConfig{
+			TagGroups: getStringSliceMapEnv("TAG_GROUPS", map[string][]string{
+				"Error":       {"*-bug", "*-issue", "*-error"},
+				"UI":          {"ui-*", "*-ui", "*-display"},
+				"Performance": {"perf-*", "*-slow"},
+			}),
+		},
+		Trending: TrendingConfig{
+			Gravity:    getFloatEnv("TRENDING_GRAVITY", 1.8),
+			WindowDays: getIntEnv("TRENDING_WINDOW_DAYS", 30),
+		},
+		Escalation: EscalationConfig{
+			HighVotes24h:     getIntEnv("ESCALATION_HIGH_VOTES_24H", 50),
+			CriticalVotes24h: getIntEnv("ESCALATION_CRITICAL_VOTES_24H", 200),
+		},
+		External: ExternalConfig{
+			HTTPClientTimeoutSeconds: getIntEnv("EXTERNAL_HTTP_CLIENT_TIMEOUT_SECONDS", 10),
+		},
+		Webhooks: WebhooksConfig{
+			DeliveryTimeoutSeconds: getIntEnv("WEBHOOK_DELIVERY_TIMEOUT_SECONDS", 10),
+			Workers:                getIntEnv("WEBHOOK_WORKERS", 4),
+		},
+		GeoIP: GeoIPConfig{
+			// GEOIP_DATABASE_PATH is the older name of the variable
+			DBPath: getEnv("GEOIP_DB_PATH", getEnv("GEOIP_DATABASE_PATH", "")),
+		},
+		Metrics: MetricsConfig{
+			Token: getEnv("METRICS_TOKEN", ""),
+		},
+	}
+}
+
+// defaultGinMode is the Gin mode used in environment when GIN_MODE is not set
+func defaultGinMode(environment string) string {
+	if environment == "production" {
+		return "release"
 	}
+	return "debug"
 }
 
 func getEnv(key, defaultValue string) string {
@@ -144,6 +483,104 @@ func getIntEnv(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getInt64Env(key string, defaultValue int64) int64 {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
+
+// getIntMapEnv parses a comma-separated list of key:value pairs, e.g. "CN:10,default:60".
+// Pairs that do not parse are skipped.
+func getIntMapEnv(key string, defaultValue map[string]int) map[string]int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	result := make(map[string]int)
+	for _, pair := range strings.Split(value, ",") {
+		name, number, found := strings.Cut(strings.TrimSpace(pair), ":")
+		if !found {
+			continue
+		}
+		if intValue, err := strconv.Atoi(strings.TrimSpace(number)); err == nil {
+			result[strings.TrimSpace(name)] = intValue
+		}
+	}
+	return result
+}
+
+// getStringSliceMapEnv parses a semicolon-separated list of key:values pairs whose
+// values are separated by |, e.g. "Error:*-bug|*-error;UI:ui-*". Pairs without a
+// key or values are skipped.
+func getStringSliceMapEnv(key string, defaultValue map[string][]string) map[string][]string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	result := make(map[string][]string)
+	for _, pair := range strings.Split(value, ";") {
+		name, items, found := strings.Cut(strings.TrimSpace(pair), ":")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			continue
+		}
+		for _, item := range strings.Split(items, "|") {
+			if item = strings.TrimSpace(item); item != "" {
+				result[name] = append(result[name], item)
+			}
+		}
+	}
+	return result
+}
+
+// getIntSliceEnv parses a comma-separated list of integers, e.g. "10,50,100".
+// Values that do not parse are skipped.
+func getIntSliceEnv(key string, defaultValue []int) []int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var result []int
+	for _, item := range strings.Split(value, ",") {
+		if intValue, err := strconv.Atoi(strings.TrimSpace(item)); err == nil {
+			result = append(result, intValue)
+		}
+	}
+	return result
+}
+
+// getStringSliceEnv parses a comma-separated list, e.g. "resolved_at,reporter".
+// Empty items are skipped.
+func getStringSliceEnv(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func getFloatEnv(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
 func getBoolEnv(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if boolValue, err := strconv.ParseBool(value); err == nil {
@@ -33,5 +33,9 @@ func Initialize(cfg config.DatabaseConfig) (*gorm.DB, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
+	if err := RegisterErrorReporting(db); err != nil {
+		return nil, fmt.Errorf("failed to register error reporting: %w", err)
+	}
+
 	return db, nil
 }
\ No newline at end of file
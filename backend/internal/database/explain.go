@@ -0,0 +1,42 @@
+package database
+
+import (
+	"strings"
+
+	"bugrelay-backend/internal/logger"
+
+	"gorm.io/gorm"
+)
+
+// ExplainQuery runs EXPLAIN (ANALYZE, BUFFERS) for the given query and logs the
+// plan at debug level. It is a no-op unless debug logging is enabled.
+func ExplainQuery(db *gorm.DB, name string, query *gorm.DB, dest interface{}) {
+	if !logger.IsDebugEnabled() {
+		return
+	}
+
+	stmt := query.Session(&gorm.Session{DryRun: true}).Find(dest).Statement
+
+	rows, err := db.Raw("EXPLAIN (ANALYZE, BUFFERS) "+stmt.SQL.String(), stmt.Vars...).Rows()
+	if err != nil {
+		logger.Debug("Failed to explain query", logger.Fields{"query": name, "error": err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	var plan []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			logger.Debug("Failed to read query plan", logger.Fields{"query": name, "error": err.Error()})
+			return
+		}
+		plan = append(plan, line)
+	}
+
+	logger.Debug("Query plan", logger.Fields{
+		"query": name,
+		"sql":   stmt.SQL.String(),
+		"plan":  strings.Join(plan, "\n"),
+	})
+}
@@ -0,0 +1,13 @@
+package database
+
+import "gorm.io/gorm"
+
+// SearchLanguages returns the names of the text search configurations installed
+// in the database, the languages full-text search can use
+func SearchLanguages(db *gorm.DB) ([]string, error) {
+	var languages []string
+	if err := db.Raw("SELECT cfgname FROM pg_ts_config ORDER BY cfgname").Scan(&languages).Error; err != nil {
+		return nil, err
+	}
+	return languages, nil
+}
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"github.com/getsentry/sentry-go"
+	"gorm.io/gorm"
+)
+
+// RegisterErrorReporting reports failed queries to Sentry. Lookups that find no
+// record are expected and not reported.
+func RegisterErrorReporting(db *gorm.DB) error {
+	report := func(tx *gorm.DB) {
+		if tx.Error == nil || errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			return
+		}
+		captureDBError(tx.Statement.Context, tx.Error, tx.Statement.SQL.String())
+	}
+
+	callbacks := db.Callback()
+	for name, register := range map[string]func(string, func(*gorm.DB)) error{
+		"create": callbacks.Create().After("gorm:create").Register,
+		"query":  callbacks.Query().After("gorm:query").Register,
+		"update": callbacks.Update().After("gorm:update").Register,
+		"delete": callbacks.Delete().After("gorm:delete").Register,
+		"row":    callbacks.Row().After("gorm:row").Register,
+		"raw":    callbacks.Raw().After("gorm:raw").Register,
+	} {
+		if err := register("sentry:report_"+name+"_errors", report); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// captureDBError sends a database error to Sentry along with the failed query. The
+// error is reported on the request's hub when ctx carries one, so it keeps the
+// request's scope, and on the global hub otherwise.
+func captureDBError(ctx context.Context, err error, query string) {
+	hub := sentry.CurrentHub()
+	if ctx != nil {
+		if requestHub := sentry.GetHubFromContext(ctx); requestHub != nil {
+			hub = requestHub
+		}
+	}
+	hub = hub.Clone()
+	hub.Scope().SetTag("component", "database")
+	hub.Scope().SetContext("database", sentry.Context{"query": query})
+	hub.CaptureException(err)
+}
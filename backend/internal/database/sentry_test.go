@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// recordingTransport keeps the events sent to Sentry instead of sending them
+type recordingTransport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (t *recordingTransport) Configure(options sentry.ClientOptions) {}
+
+func (t *recordingTransport) Flush(timeout time.Duration) bool { return true }
+
+func (t *recordingTransport) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+
+func (t *recordingTransport) Events() []*sentry.Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*sentry.Event(nil), t.events...)
+}
+
+func TestRegisterErrorReporting(t *testing.T) {
+	transport := &recordingTransport{}
+	client, err := sentry.NewClient(sentry.ClientOptions{Transport: transport})
+	require.NoError(t, err)
+	previous := sentry.CurrentHub().Client()
+	sentry.CurrentHub().BindClient(client)
+	defer sentry.CurrentHub().BindClient(previous)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, RegisterErrorReporting(db))
+	require.NoError(t, db.AutoMigrate(&softDeleteBug{}))
+
+	// Missing records are not errors worth reporting
+	var bug softDeleteBug
+	assert.ErrorIs(t, db.First(&bug, 42).Error, gorm.ErrRecordNotFound)
+	assert.NoError(t, db.Create(&softDeleteBug{Status: "open"}).Error)
+	assert.Empty(t, transport.Events())
+
+	assert.Error(t, db.Table("missing_table").Where("id = ?", 1).Find(&bug).Error)
+	assert.Error(t, db.Exec("UPDATE missing_table SET status = 'closed'").Error)
+
+	events := transport.Events()
+	require.Len(t, events, 2)
+	assert.Equal(t, "database", events[0].Tags["component"])
+	assert.Contains(t, events[0].Contexts["database"]["query"], "FROM `missing_table`")
+	assert.Equal(t, "UPDATE missing_table SET status = 'closed'", events[1].Contexts["database"]["query"])
+	require.NotEmpty(t, events[1].Exception)
+	assert.Contains(t, events[1].Exception[0].Value, "no such table")
+}
+
+func TestRegisterErrorReporting_UsesRequestHub(t *testing.T) {
+	globalTransport := &recordingTransport{}
+	globalClient, err := sentry.NewClient(sentry.ClientOptions{Transport: globalTransport})
+	require.NoError(t, err)
+	previous := sentry.CurrentHub().Client()
+	sentry.CurrentHub().BindClient(globalClient)
+	defer sentry.CurrentHub().BindClient(previous)
+
+	requestTransport := &recordingTransport{}
+	requestClient, err := sentry.NewClient(sentry.ClientOptions{Transport: requestTransport})
+	require.NoError(t, err)
+	requestHub := sentry.NewHub(requestClient, sentry.NewScope())
+	requestHub.Scope().SetTag("request_id", "req-123")
+	ctx := sentry.SetHubOnContext(context.Background(), requestHub)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, RegisterErrorReporting(db))
+
+	assert.Error(t, db.WithContext(ctx).Exec("UPDATE missing_table SET status = 'closed'").Error)
+
+	assert.Empty(t, globalTransport.Events())
+	events := requestTransport.Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, "req-123", events[0].Tags["request_id"])
+	assert.Equal(t, "database", events[0].Tags["component"])
+
+}
@@ -0,0 +1,24 @@
+package database
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WithActive restricts a query to rows that have not been soft-deleted. GORM adds
+// this condition to queries on soft-deletable models by itself, but not to queries
+// built with Table, so those should use WithActive. The condition is qualified with
+// the query's table, so it stays unambiguous when other soft-deletable tables are
+// joined in.
+func WithActive(db *gorm.DB) *gorm.DB {
+	return db.Where(clause.Eq{
+		Column: clause.Column{Table: clause.CurrentTable, Name: "deleted_at"},
+		Value:  nil,
+	})
+}
+
+// IncludingDeleted includes soft-deleted rows in a query. It is db.Unscoped(),
+// named so that handlers read as intending to see deleted rows.
+func IncludingDeleted(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
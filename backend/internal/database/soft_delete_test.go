@@ -0,0 +1,100 @@
+package database
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// softDeleteBug is the part of the bug_reports table the active bug indexes cover
+type softDeleteBug struct {
+	ID        uint `gorm:"primaryKey"`
+	Status    string
+	CreatedAt time.Time
+	DeletedAt gorm.DeletedAt
+}
+
+func (softDeleteBug) TableName() string {
+	return "bug_reports"
+}
+
+// setupSoftDeleteTestDB creates a SQLite bug_reports table with the active bug
+// indexes migration applied. SQLite supports partial indexes but not CONCURRENTLY.
+func setupSoftDeleteTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&softDeleteBug{}))
+
+	migration, err := os.ReadFile("../../migrations/023_active_bug_indexes.up.sql")
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(strings.ReplaceAll(string(migration), " CONCURRENTLY", "")).Error)
+
+	return db
+}
+
+// queryPlan returns SQLite's plan for query
+func queryPlan(t *testing.T, db *gorm.DB, query *gorm.DB) string {
+	stmt := query.Session(&gorm.Session{DryRun: true}).Find(&[]map[string]interface{}{}).Statement
+
+	var plan []struct {
+		Detail string
+	}
+	require.NoError(t, db.Raw("EXPLAIN QUERY PLAN "+stmt.SQL.String(), stmt.Vars...).Scan(&plan).Error)
+
+	var details []string
+	for _, step := range plan {
+		details = append(details, step.Detail)
+	}
+	return strings.Join(details, "\n")
+}
+
+func TestActiveBugIndexes(t *testing.T) {
+	db := setupSoftDeleteTestDB(t)
+
+	// Queries on active bugs use the partial index
+	active := WithActive(db.Table("bug_reports")).Where("status = ?", "open").Order("created_at DESC")
+	assert.Contains(t, queryPlan(t, db, active), "idx_bug_reports_active_status_created_at")
+
+	model := db.Model(&softDeleteBug{}).Where("status = ?", "open").Order("created_at DESC")
+	assert.Contains(t, queryPlan(t, db, model), "idx_bug_reports_active_status_created_at")
+
+	// Queries that include deleted bugs cannot
+	deleted := IncludingDeleted(db).Model(&softDeleteBug{}).Where("status = ?", "open").Order("created_at DESC")
+	assert.NotContains(t, queryPlan(t, db, deleted), "idx_bug_reports_active_status_created_at")
+}
+
+func TestWithActive(t *testing.T) {
+	db := setupSoftDeleteTestDB(t)
+
+	bugs := []softDeleteBug{{Status: "open"}, {Status: "open"}, {Status: "fixed"}}
+	require.NoError(t, db.Create(&bugs).Error)
+	require.NoError(t, db.Delete(&bugs[1]).Error)
+
+	countTable := func(query *gorm.DB) int64 {
+		var count int64
+		require.NoError(t, query.Count(&count).Error)
+		return count
+	}
+
+	// Table queries are not scoped by GORM, so they see deleted rows without WithActive
+	assert.Equal(t, int64(3), countTable(db.Table("bug_reports")))
+	assert.Equal(t, int64(2), countTable(WithActive(db.Table("bug_reports"))))
+	assert.Equal(t, int64(1), countTable(WithActive(db.Table("bug_reports")).Where("status = ?", "open")))
+
+	// The condition is qualified with the table, so joining a table that also has
+	// deleted_at stays unambiguous. Only the queried table is filtered: the active
+	// open bug still joins with the deleted one.
+	joined := WithActive(db.Table("bug_reports")).
+		Joins("JOIN bug_reports AS duplicates ON duplicates.status = bug_reports.status AND duplicates.id <> bug_reports.id")
+	assert.Equal(t, int64(1), countTable(joined))
+
+	// IncludingDeleted returns deleted rows for model queries
+	assert.Equal(t, int64(2), countTable(db.Model(&softDeleteBug{})))
+	assert.Equal(t, int64(3), countTable(IncludingDeleted(db).Model(&softDeleteBug{})))
+}
@@ -0,0 +1,71 @@
+package email
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// DeepLinkGenerator builds links into the frontend for use in emails
+type DeepLinkGenerator struct {
+	frontendURL string
+	secret      []byte
+}
+
+// NewDeepLinkGenerator creates a deep link generator. The secret signs unsubscribe
+// links so they cannot be forged for other users.
+func NewDeepLinkGenerator(frontendURL, secret string) *DeepLinkGenerator {
+	return &DeepLinkGenerator{
+		frontendURL: strings.TrimRight(frontendURL, "/"),
+		secret:      []byte(secret),
+	}
+}
+
+// GenerateBugLink returns the frontend page of a bug
+func (g *DeepLinkGenerator) GenerateBugLink(bugID uuid.UUID) string {
+	return g.frontendURL + "/bugs/" + bugID.String()
+}
+
+// GenerateCompanyDashboardLink returns the frontend dashboard of a company
+func (g *DeepLinkGenerator) GenerateCompanyDashboardLink(companyID uuid.UUID) string {
+	return g.frontendURL + "/companies/" + companyID.String() + "/dashboard"
+}
+
+// GeneratePasswordResetLink returns the frontend page that sets a new password with
+// a password reset token
+func (g *DeepLinkGenerator) GeneratePasswordResetLink(token string) string {
+	return g.frontendURL + "/reset-password?token=" + url.QueryEscape(token)
+}
+
+// GenerateEmailVerificationLink returns the frontend page that verifies an email
+// address with a verification token
+func (g *DeepLinkGenerator) GenerateEmailVerificationLink(token string) string {
+	return g.frontendURL + "/verify-email?token=" + url.QueryEscape(token)
+}
+
+// GenerateUnsubscribeLink returns a signed link that turns off a notification type
+// for a user without requiring them to log in
+func (g *DeepLinkGenerator) GenerateUnsubscribeLink(userID uuid.UUID, notificationType string) string {
+	query := url.Values{}
+	query.Set("user_id", userID.String())
+	query.Set("type", notificationType)
+	query.Set("signature", g.unsubscribeSignature(userID, notificationType))
+	return g.frontendURL + "/unsubscribe?" + query.Encode()
+}
+
+// VerifyUnsubscribeSignature reports whether signature was issued by
+// GenerateUnsubscribeLink for the user and notification type
+func (g *DeepLinkGenerator) VerifyUnsubscribeSignature(userID uuid.UUID, notificationType, signature string) bool {
+	expected := g.unsubscribeSignature(userID, notificationType)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+func (g *DeepLinkGenerator) unsubscribeSignature(userID uuid.UUID, notificationType string) string {
+	mac := hmac.New(sha256.New, g.secret)
+	mac.Write([]byte("unsubscribe:" + userID.String() + ":" + notificationType))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,50 @@
+package email
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeepLinkGenerator_Links(t *testing.T) {
+	links := NewDeepLinkGenerator("https://bugrelay.example.com/", "secret")
+	bugID := uuid.MustParse("3f1c2a8e-5b7d-4c9a-8e21-0d6f4b3a9c17")
+	companyID := uuid.MustParse("9a4e7b21-1c3d-4f5a-b6e8-2d9c0f7a1b34")
+
+	assert.Equal(t, "https://bugrelay.example.com/bugs/"+bugID.String(), links.GenerateBugLink(bugID))
+	assert.Equal(t, "https://bugrelay.example.com/companies/"+companyID.String()+"/dashboard", links.GenerateCompanyDashboardLink(companyID))
+	assert.Equal(t, "https://bugrelay.example.com/reset-password?token=abc123", links.GeneratePasswordResetLink("abc123"))
+	assert.Equal(t, "https://bugrelay.example.com/verify-email?token=a%2Bb%26c", links.GenerateEmailVerificationLink("a+b&c"))
+}
+
+func TestDeepLinkGenerator_UnsubscribeLink(t *testing.T) {
+	links := NewDeepLinkGenerator("https://bugrelay.example.com", "secret")
+	userID := uuid.New()
+
+	link, err := url.Parse(links.GenerateUnsubscribeLink(userID, "bug_status_change"))
+	require.NoError(t, err)
+	assert.Equal(t, "/unsubscribe", link.Path)
+
+	query := link.Query()
+	assert.Equal(t, userID.String(), query.Get("user_id"))
+	assert.Equal(t, "bug_status_change", query.Get("type"))
+
+	signature := query.Get("signature")
+	assert.True(t, links.VerifyUnsubscribeSignature(userID, "bug_status_change", signature))
+
+	t.Run("rejects a signature for another user", func(t *testing.T) {
+		assert.False(t, links.VerifyUnsubscribeSignature(uuid.New(), "bug_status_change", signature))
+	})
+
+	t.Run("rejects a signature for another type", func(t *testing.T) {
+		assert.False(t, links.VerifyUnsubscribeSignature(userID, "all", signature))
+	})
+
+	t.Run("rejects a signature from another secret", func(t *testing.T) {
+		other := NewDeepLinkGenerator("https://bugrelay.example.com", "other-secret")
+		assert.False(t, other.VerifyUnsubscribeSignature(userID, "bug_status_change", signature))
+	})
+}
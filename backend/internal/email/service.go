@@ -0,0 +1,217 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/smtp"
+	"net/textproto"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// PasswordResetExpiry is how long a password reset link can be used
+const PasswordResetExpiry = time.Hour
+
+// Names of the account email templates. Each has a <name>.txt and a <name>.html
+// file in the template directory.
+const (
+	TemplatePasswordReset     = "password_reset"
+	TemplateEmailVerification = "email_verification"
+)
+
+// accountTemplates are the templates the email service requires
+var accountTemplates = []string{TemplatePasswordReset, TemplateEmailVerification}
+
+// EmailService sends the emails of the account flows
+type EmailService interface {
+	SendPasswordReset(to, resetToken string) error
+	SendEmailVerification(to, verificationToken string) error
+}
+
+// Config holds the SMTP server and templates used to send account emails
+type Config struct {
+	Host        string
+	Port        string
+	Username    string
+	Password    string
+	From        string
+	TemplateDir string
+}
+
+// PasswordResetData is the data the password reset templates are rendered with
+type PasswordResetData struct {
+	Email            string
+	ResetURL         string
+	ExpiresInMinutes int
+}
+
+// EmailVerificationData is the data the email verification templates are rendered with
+type EmailVerificationData struct {
+	Email           string
+	VerificationURL string
+}
+
+// Templates holds the plain text and HTML versions of the account emails
+type Templates struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// LoadTemplates parses the *.txt and *.html templates in dir. Every account email
+// must have both versions.
+func LoadTemplates(dir string) (*Templates, error) {
+	text, err := texttemplate.ParseGlob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return nil, err
+	}
+	html, err := htmltemplate.ParseGlob(filepath.Join(dir, "*.html"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range accountTemplates {
+		if text.Lookup(name+".txt") == nil {
+			return nil, fmt.Errorf("email template %s.txt not found in %s", name, dir)
+		}
+		if html.Lookup(name+".html") == nil {
+			return nil, fmt.Errorf("email template %s.html not found in %s", name, dir)
+		}
+	}
+
+	return &Templates{text: text, html: html}, nil
+}
+
+// Render renders the plain text and HTML versions of an email
+func (t *Templates) Render(name string, data interface{}) (string, string, error) {
+	var text, html strings.Builder
+	if err := t.text.ExecuteTemplate(&text, name+".txt", data); err != nil {
+		return "", "", err
+	}
+	if err := t.html.ExecuteTemplate(&html, name+".html", data); err != nil {
+		return "", "", err
+	}
+	return text.String(), html.String(), nil
+}
+
+// SMTPService sends account emails as multipart plain text and HTML messages over SMTP
+type SMTPService struct {
+	cfg       Config
+	links     *DeepLinkGenerator
+	templates *Templates
+
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTPService creates an SMTP email service, loading its templates from the
+// configured directory. Links in the emails point at the frontend.
+func NewSMTPService(cfg Config, links *DeepLinkGenerator) (*SMTPService, error) {
+	if cfg.Host == "" {
+		return nil, fmt.Errorf("SMTP is not configured")
+	}
+
+	templates, err := LoadTemplates(cfg.TemplateDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SMTPService{
+		cfg:       cfg,
+		links:     links,
+		templates: templates,
+		sendMail:  smtp.SendMail,
+	}, nil
+}
+
+// SendPasswordReset sends a link that sets a new password with the reset token
+func (s *SMTPService) SendPasswordReset(to, resetToken string) error {
+	return s.send(to, "[BugRelay] Reset your password", TemplatePasswordReset, PasswordResetData{
+		Email:            to,
+		ResetURL:         s.links.GeneratePasswordResetLink(resetToken),
+		ExpiresInMinutes: int(PasswordResetExpiry.Minutes()),
+	})
+}
+
+// SendEmailVerification sends a link that verifies the email address with the
+// verification token
+func (s *SMTPService) SendEmailVerification(to, verificationToken string) error {
+	return s.send(to, "[BugRelay] Verify your email address", TemplateEmailVerification, EmailVerificationData{
+		Email:           to,
+		VerificationURL: s.links.GenerateEmailVerificationLink(verificationToken),
+	})
+}
+
+// send renders a template and sends it to a single recipient
+func (s *SMTPService) send(to, subject, templateName string, data interface{}) error {
+	text, html, err := s.templates.Render(templateName, data)
+	if err != nil {
+		return fmt.Errorf("failed to render %s email: %w", templateName, err)
+	}
+
+	message, err := buildMessage(s.cfg.From, to, subject, text, html)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.cfg.Username != "" {
+		auth = smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+	}
+
+	return s.sendMail(s.cfg.Host+":"+s.cfg.Port, auth, s.cfg.From, []string{to}, message)
+}
+
+// EncodeSubject returns subject as the value of a Subject header. Line breaks are
+// replaced with spaces, so a bug title cannot end the header and add headers or body
+// content of its own, and non-ASCII text is Q-encoded.
+func EncodeSubject(subject string) string {
+	return mime.QEncoding.Encode("UTF-8", strings.Join(strings.Fields(subject), " "))
+}
+
+// buildMessage builds a multipart/alternative message with plain text and HTML parts
+func buildMessage(from, to, subject, text, html string) ([]byte, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=UTF-8", text},
+		{"text/html; charset=UTF-8", html},
+	}
+	for _, part := range parts {
+		partWriter, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		encoder := quotedprintable.NewWriter(partWriter)
+		if _, err := encoder.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	var message bytes.Buffer
+	message.WriteString("From: " + from + "\r\n")
+	message.WriteString("To: " + to + "\r\n")
+	message.WriteString("Subject: " + EncodeSubject(subject) + "\r\n")
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: multipart/alternative; boundary=" + writer.Boundary() + "\r\n")
+	message.WriteString("\r\n")
+	message.Write(body.Bytes())
+	return message.Bytes(), nil
+}
@@ -0,0 +1,121 @@
+package email
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sentMail is a message captured instead of being sent
+type sentMail struct {
+	addr string
+	from string
+	to   []string
+	msg  []byte
+}
+
+// newTestSMTPService creates an SMTP service using the shipped templates that
+// captures messages instead of sending them
+func newTestSMTPService(t *testing.T) (*SMTPService, *[]sentMail) {
+	service, err := NewSMTPService(Config{
+		Host:        "smtp.example.com",
+		Port:        "587",
+		From:        "noreply@bugrelay.example.com",
+		TemplateDir: filepath.Join("..", "..", "templates", "email"),
+	}, NewDeepLinkGenerator("https://bugrelay.example.com", "secret"))
+	require.NoError(t, err)
+
+	var sent []sentMail
+	service.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		sent = append(sent, sentMail{addr: addr, from: from, to: to, msg: msg})
+		return nil
+	}
+	return service, &sent
+}
+
+// readMessage parses a multipart/alternative message into its subject and the
+// decoded parts by content type
+func readMessage(t *testing.T, msg []byte) (string, map[string]string) {
+	message, err := mail.ReadMessage(strings.NewReader(string(msg)))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	require.NoError(t, err)
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/alternative", mediaType)
+
+	parts := map[string]string{}
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		// The reader decodes quoted-printable parts
+		content, err := io.ReadAll(part)
+		require.NoError(t, err)
+		contentType, _, err := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		require.NoError(t, err)
+		parts[contentType] = string(content)
+	}
+	return subject, parts
+}
+
+func TestSMTPService_SendPasswordReset(t *testing.T) {
+	service, sent := newTestSMTPService(t)
+
+	require.NoError(t, service.SendPasswordReset("user@example.com", "reset-token"))
+	require.Len(t, *sent, 1)
+	message := (*sent)[0]
+	assert.Equal(t, "smtp.example.com:587", message.addr)
+	assert.Equal(t, "noreply@bugrelay.example.com", message.from)
+	assert.Equal(t, []string{"user@example.com"}, message.to)
+
+	subject, parts := readMessage(t, message.msg)
+	assert.Equal(t, "[BugRelay] Reset your password", subject)
+	for _, contentType := range []string{"text/plain", "text/html"} {
+		assert.Contains(t, parts[contentType], "https://bugrelay.example.com/reset-password?token=reset-token", contentType)
+		assert.Contains(t, parts[contentType], "user@example.com", contentType)
+		assert.Contains(t, parts[contentType], "60 minutes", contentType)
+	}
+}
+
+func TestSMTPService_SendEmailVerification(t *testing.T) {
+	service, sent := newTestSMTPService(t)
+
+	require.NoError(t, service.SendEmailVerification("user@example.com", "a+b"))
+	require.Len(t, *sent, 1)
+
+	subject, parts := readMessage(t, (*sent)[0].msg)
+	assert.Equal(t, "[BugRelay] Verify your email address", subject)
+	assert.Contains(t, parts["text/plain"], "https://bugrelay.example.com/verify-email?token=a%2Bb")
+	assert.Contains(t, parts["text/html"], `href="https://bugrelay.example.com/verify-email?token=a%2Bb"`)
+}
+
+func TestLoadTemplates_MissingTemplate(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"password_reset.txt", "password_reset.html", "email_verification.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("{{.Email}}"), 0o644))
+	}
+
+	_, err := LoadTemplates(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "email_verification.html")
+}
+
+func TestNewSMTPService_NotConfigured(t *testing.T) {
+	_, err := NewSMTPService(Config{TemplateDir: filepath.Join("..", "..", "templates", "email")}, NewDeepLinkGenerator("https://bugrelay.example.com", "secret"))
+	assert.Error(t, err)
+}
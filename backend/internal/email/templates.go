@@ -0,0 +1,63 @@
+package email
+
+import (
+	"fmt"
+	"html"
+
+	"bugrelay-backend/internal/models"
+)
+
+// BugFixedEmail tells a reporter that their bug has been fixed
+func BugFixedEmail(links *DeepLinkGenerator, recipient models.User, bug models.BugReport) models.EmailPayload {
+	body := fmt.Sprintf("Hi %s,\n\n"+
+		"Good news: the bug you reported, \"%s\", has been marked as fixed.\n\n"+
+		"View the bug: %s\n",
+		recipient.DisplayName, plainText(bug.Title), links.GenerateBugLink(bug.ID))
+
+	return models.EmailPayload{
+		To:      []string{recipient.Email},
+		Subject: fmt.Sprintf("[BugRelay] Fixed: %s", plainText(bug.Title)),
+		Body:    body + unsubscribeFooter(links, recipient, models.NotificationTypeBugStatusChange),
+	}
+}
+
+// CompanyResponseEmail tells a reporter that the company responded to their bug
+func CompanyResponseEmail(links *DeepLinkGenerator, recipient models.User, bug models.BugReport, companyName, response string) models.EmailPayload {
+	body := fmt.Sprintf("Hi %s,\n\n"+
+		"%s responded to the bug you reported, \"%s\":\n\n"+
+		"%s\n\n"+
+		"View the conversation: %s\n",
+		recipient.DisplayName, plainText(companyName), plainText(bug.Title), plainText(response), links.GenerateBugLink(bug.ID))
+
+	return models.EmailPayload{
+		To:      []string{recipient.Email},
+		Subject: fmt.Sprintf("[BugRelay] %s responded to: %s", plainText(companyName), plainText(bug.Title)),
+		Body:    body + unsubscribeFooter(links, recipient, models.NotificationTypeCompanyResponse),
+	}
+}
+
+// MentionEmail tells a user that they were mentioned in a comment on a bug
+func MentionEmail(links *DeepLinkGenerator, recipient models.User, bug models.BugReport, mentionedBy string) models.EmailPayload {
+	body := fmt.Sprintf("Hi %s,\n\n"+
+		"%s mentioned you in a comment on \"%s\".\n\n"+
+		"View the comment: %s\n",
+		recipient.DisplayName, plainText(mentionedBy), plainText(bug.Title), links.GenerateBugLink(bug.ID))
+
+	return models.EmailPayload{
+		To:      []string{recipient.Email},
+		Subject: fmt.Sprintf("[BugRelay] %s mentioned you on: %s", plainText(mentionedBy), plainText(bug.Title)),
+		Body:    body + unsubscribeFooter(links, recipient, models.NotificationTypeMention),
+	}
+}
+
+// unsubscribeFooter is appended to every notification email
+func unsubscribeFooter(links *DeepLinkGenerator, recipient models.User, notificationType string) string {
+	return fmt.Sprintf("\n--\nYou received this email because of your BugRelay notification settings.\n"+
+		"Unsubscribe: %s\n", links.GenerateUnsubscribeLink(recipient.ID, notificationType))
+}
+
+// plainText undoes the HTML escaping user input is stored with, since notification
+// emails are plain text
+func plainText(s string) string {
+	return html.UnescapeString(s)
+}
@@ -0,0 +1,47 @@
+package email
+
+import (
+	"net/mail"
+	"strings"
+	"testing"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationEmails_TitleWithNewline(t *testing.T) {
+	links := NewDeepLinkGenerator("https://bugrelay.example.com", "secret")
+	recipient := models.User{ID: uuid.New(), Email: "reporter@example.com", DisplayName: "Reporter"}
+	// Titles are stored HTML-escaped, and may contain a line break
+	bug := models.BugReport{ID: uuid.New(), Title: "Can&#39;t log in\r\nBcc: victim@example.com"}
+
+	payloads := map[string]models.EmailPayload{
+		"fixed":    BugFixedEmail(links, recipient, bug),
+		"response": CompanyResponseEmail(links, recipient, bug, "Acme &amp; Co", "We&#39;re on it"),
+		"mention":  MentionEmail(links, recipient, bug, "O&#39;Brien"),
+	}
+	for name, payload := range payloads {
+		t.Run(name, func(t *testing.T) {
+			assert.Contains(t, payload.Body, "Can't log in")
+			assert.NotContains(t, payload.Subject, "&#39;")
+
+			message, err := mail.ReadMessage(strings.NewReader("Subject: " + EncodeSubject(payload.Subject) + "\r\n\r\nbody"))
+			require.NoError(t, err)
+			assert.Empty(t, message.Header.Get("Bcc"), "the title must not add headers")
+			assert.Contains(t, message.Header.Get("Subject"), "Can't log in Bcc: victim@example.com")
+		})
+	}
+
+	assert.Equal(t, "[BugRelay] Acme & Co responded to: Can't log in\r\nBcc: victim@example.com", payloads["response"].Subject)
+	assert.Contains(t, payloads["response"].Body, "We're on it")
+	assert.Contains(t, payloads["mention"].Subject, "O'Brien mentioned you")
+}
+
+func TestEncodeSubject(t *testing.T) {
+	assert.Equal(t, "Bug fixed", EncodeSubject("Bug fixed"))
+	assert.Equal(t, "Bug fixed Bcc: victim@example.com", EncodeSubject("Bug fixed\r\nBcc: victim@example.com\n"))
+	assert.Equal(t, "=?UTF-8?q?Caf=C3=A9_crashes?=", EncodeSubject("Café crashes"))
+}
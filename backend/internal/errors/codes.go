@@ -0,0 +1,2161 @@
+package errors
+
+import "net/http"
+
+// Errors returned across several areas of the API
+var (
+	ErrBugNotFound = register(ErrorCode{
+		Code: "BUG_NOT_FOUND",
+		HTTP: http.StatusNotFound,
+		Desc: "Bug report not found",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"DELETE /api/v1/admin/bugs/:id",
+			"POST /api/v1/admin/bugs/:id/flag",
+			"PATCH /api/v1/admin/bugs/:id/owner",
+			"POST /api/v1/admin/bugs/:id/rebuild-projection",
+			"POST /api/v1/admin/bugs/:id/restore",
+			"GET /api/v1/bugs/:id",
+			"PATCH /api/v1/bugs/:id",
+			"POST /api/v1/bugs/:id/attachments",
+			"GET /api/v1/bugs/:id/attachments",
+			"DELETE /api/v1/bugs/:id/attachments/:attachment_id",
+			"POST /api/v1/bugs/:id/comments",
+			"GET /api/v1/bugs/:id/comments",
+			"DELETE /api/v1/bugs/:id/comments/:comment_id",
+			"POST /api/v1/bugs/:id/company-response",
+			"PATCH /api/v1/bugs/:id/priority",
+			"PATCH /api/v1/bugs/:id/status",
+			"POST /api/v1/bugs/:id/subscribe",
+			"DELETE /api/v1/bugs/:id/subscribe",
+			"POST /api/v1/bugs/:id/vote",
+			"GET /api/v2/bugs/:id",
+			"GET /api/v2/bugs/:id/attachments",
+		},
+	})
+	ErrCommitFailed = register(ErrorCode{
+		Code: "COMMIT_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to commit the database transaction",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/admin/bugs/merge",
+			"DELETE /api/v1/admin/bugs/purge",
+			"POST /api/v1/bugs",
+			"POST /api/v1/bugs/:id/comments",
+			"POST /api/v1/bugs/:id/company-response",
+			"POST /api/v1/bugs/:id/vote",
+			"POST /api/v1/companies/:id/verify",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrCountFailed = register(ErrorCode{
+		Code: "COUNT_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to count records",
+		Endpoints: []string{
+			"GET /api/v1/bugs",
+			"GET /api/v1/bugs/:id",
+			"GET /api/v1/bugs/:id/attachments",
+			"GET /api/v1/bugs/:id/comments",
+			"GET /api/v1/companies",
+			"GET /api/v1/companies/:id/bugs",
+			"DELETE /api/v1/companies/:id/members",
+			"GET /api/v1/me/bugs",
+			"GET /api/v1/tags/:tag/bugs",
+			"GET /api/v2/bugs/:id",
+			"GET /api/v2/bugs/:id/attachments",
+		},
+	})
+	ErrCountUpdateFailed = register(ErrorCode{
+		Code: "COUNT_UPDATE_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to update cached counts",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/admin/bugs/merge",
+			"POST /api/v1/bugs/:id/comments",
+			"POST /api/v1/bugs/:id/company-response",
+			"POST /api/v1/bugs/:id/vote",
+		},
+	})
+	ErrInsufficientPermissions = register(ErrorCode{
+		Code: "INSUFFICIENT_PERMISSIONS",
+		HTTP: http.StatusForbidden,
+		Desc: "You do not have permission to perform this action",
+		Endpoints: []string{
+			"PATCH /api/v1/applications/:id",
+			"POST /api/v1/applications/:id/archive",
+			"POST /api/v1/applications/:id/unarchive",
+			"PATCH /api/v1/bugs/:id",
+			"DELETE /api/v1/bugs/:id/attachments/:attachment_id",
+			"PUT /api/v1/bugs/:id/comments/:comment_id",
+			"DELETE /api/v1/bugs/:id/comments/:comment_id",
+			"POST /api/v1/bugs/:id/company-response",
+			"PATCH /api/v1/bugs/:id/priority",
+			"PATCH /api/v1/bugs/:id/status",
+			"POST /api/v1/companies/:id/assignment-rules",
+			"GET /api/v1/companies/:id/bugs",
+			"GET /api/v1/companies/:id/bugs/export",
+			"POST /api/v1/companies/:id/domain-change",
+			"POST /api/v1/companies/:id/domain-change/confirm",
+			"POST /api/v1/companies/:id/invite-links",
+			"POST /api/v1/companies/:id/logo",
+			"POST /api/v1/companies/:id/members",
+			"DELETE /api/v1/companies/:id/members",
+			"PATCH /api/v1/companies/:id/members/:user_id/role",
+			"POST /api/v1/companies/:id/members/bulk",
+			"POST /api/v1/companies/:id/webhooks",
+			"GET /api/v1/companies/:id/webhooks",
+			"DELETE /api/v1/companies/:id/webhooks/:webhook_id",
+			"DELETE /api/v1/companies/:id/webhooks/:webhook_id",
+		},
+	})
+	ErrInvalidID = register(ErrorCode{
+		Code: "INVALID_ID",
+		HTTP: http.StatusBadRequest,
+		Desc: "Invalid ID format",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"GET /api/v1/admin/audit-logs/:id",
+			"DELETE /api/v1/admin/bugs/:id",
+			"POST /api/v1/admin/bugs/:id/flag",
+			"PATCH /api/v1/admin/bugs/:id/owner",
+			"POST /api/v1/admin/bugs/:id/rebuild-projection",
+			"POST /api/v1/admin/bugs/:id/restore",
+			"GET /api/v1/admin/bugs/export",
+			"POST /api/v1/admin/companies/:id/verify",
+			"DELETE /api/v1/admin/dead-letters/:id",
+			"POST /api/v1/admin/dead-letters/:id/retry",
+			"GET /api/v1/admin/security-events",
+			"PATCH /api/v1/applications/:id",
+			"POST /api/v1/applications/:id/archive",
+			"GET /api/v1/applications/:id/health",
+			"GET /api/v1/applications/:id/stats",
+			"POST /api/v1/applications/:id/unarchive",
+			"GET /api/v1/bugs",
+			"GET /api/v1/bugs/:id",
+			"PATCH /api/v1/bugs/:id",
+			"POST /api/v1/bugs/:id/attachments",
+			"GET /api/v1/bugs/:id/attachments",
+			"DELETE /api/v1/bugs/:id/attachments/:attachment_id",
+			"POST /api/v1/bugs/:id/comments",
+			"GET /api/v1/bugs/:id/comments",
+			"PUT /api/v1/bugs/:id/comments/:comment_id",
+			"DELETE /api/v1/bugs/:id/comments/:comment_id",
+			"POST /api/v1/bugs/:id/company-response",
+			"PATCH /api/v1/bugs/:id/priority",
+			"PATCH /api/v1/bugs/:id/status",
+			"POST /api/v1/bugs/:id/subscribe",
+			"DELETE /api/v1/bugs/:id/subscribe",
+			"POST /api/v1/bugs/:id/vote",
+			"GET /api/v1/companies/:id",
+			"POST /api/v1/companies/:id/assignment-rules",
+			"GET /api/v1/companies/:id/bugs",
+			"GET /api/v1/companies/:id/bugs/export",
+			"POST /api/v1/companies/:id/claim",
+			"GET /api/v1/companies/:id/dashboard",
+			"GET /api/v1/companies/:id/dashboard/export",
+			"POST /api/v1/companies/:id/domain-change",
+			"POST /api/v1/companies/:id/domain-change/confirm",
+			"POST /api/v1/companies/:id/invite-links",
+			"POST /api/v1/companies/:id/logo",
+			"POST /api/v1/companies/:id/members",
+			"DELETE /api/v1/companies/:id/members",
+			"PATCH /api/v1/companies/:id/members/:user_id/role",
+			"POST /api/v1/companies/:id/members/bulk",
+			"POST /api/v1/companies/:id/resend-verification",
+			"GET /api/v1/companies/:id/stats",
+			"POST /api/v1/companies/:id/verify",
+			"GET /api/v1/companies/:id/webhooks",
+			"POST /api/v1/companies/:id/webhooks",
+			"GET /api/v1/companies/:id/webhooks",
+			"DELETE /api/v1/companies/:id/webhooks/:webhook_id",
+			"DELETE /api/v1/companies/:id/webhooks/:webhook_id",
+			"POST /api/v1/me/blocks",
+			"DELETE /api/v1/me/blocks/:user_id",
+			"GET /api/v1/unsubscribe",
+			"GET /api/v1/users/:id/stats",
+			"GET /api/v2/bugs/:id",
+			"GET /api/v2/bugs/:id/attachments",
+		},
+	})
+	ErrInvalidPriority = register(ErrorCode{
+		Code: "INVALID_PRIORITY",
+		HTTP: http.StatusBadRequest,
+		Desc: "Invalid priority value",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs",
+			"PATCH /api/v1/bugs/:id",
+			"PATCH /api/v1/bugs/:id/priority",
+			"POST /api/v1/companies/:id/assignment-rules",
+			"GET /api/v1/me/bugs",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrInvalidToken = register(ErrorCode{
+		Code: "INVALID_TOKEN",
+		HTTP: http.StatusBadRequest,
+		Desc: "Invalid or expired token",
+		Endpoints: []string{
+			"GET /api/v1/auth/verify-email",
+			"POST /api/v1/companies/:id/domain-change/confirm",
+			"POST /api/v1/companies/:id/verify",
+		},
+	})
+	ErrLoadFailed = register(ErrorCode{
+		Code: "LOAD_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Change saved but failed to load details",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs",
+			"PATCH /api/v1/bugs/:id",
+			"POST /api/v1/bugs/:id/comments",
+			"PUT /api/v1/bugs/:id/comments/:comment_id",
+			"POST /api/v1/bugs/:id/company-response",
+			"PATCH /api/v1/bugs/:id/priority",
+			"PATCH /api/v1/bugs/:id/status",
+			"POST /api/v1/companies/:id/members",
+			"PATCH /api/v1/companies/:id/members/:user_id/role",
+			"POST /api/v1/companies/:id/verify",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrMissingToken = register(ErrorCode{
+		Code: "MISSING_TOKEN",
+		HTTP: http.StatusBadRequest,
+		Desc: "Token is required",
+		Endpoints: []string{
+			"POST /api/v1/auth/logout",
+			"GET /api/v1/auth/verify-email",
+			"POST /api/v1/invite/accept",
+			"POST /api/v1/invite/link",
+		},
+	})
+	ErrQueryFailed = register(ErrorCode{
+		Code: "QUERY_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to query the database",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"GET /api/v1/admin/audit-logs",
+			"GET /api/v1/admin/audit-logs/:id",
+			"GET /api/v1/admin/bugs",
+			"DELETE /api/v1/admin/bugs/:id",
+			"POST /api/v1/admin/bugs/:id/flag",
+			"PATCH /api/v1/admin/bugs/:id/owner",
+			"POST /api/v1/admin/bugs/:id/rebuild-projection",
+			"POST /api/v1/admin/bugs/:id/restore",
+			"GET /api/v1/admin/bugs/deleted",
+			"GET /api/v1/admin/bugs/export",
+			"POST /api/v1/admin/bugs/merge",
+			"DELETE /api/v1/admin/bugs/purge",
+			"POST /api/v1/admin/companies/:id/verify",
+			"GET /api/v1/admin/dashboard",
+			"GET /api/v1/admin/dead-letters",
+			"DELETE /api/v1/admin/dead-letters/:id",
+			"POST /api/v1/admin/dead-letters/:id/retry",
+			"GET /api/v1/admin/ip-blocks",
+			"DELETE /api/v1/admin/ip-blocks/*ip",
+			"GET /api/v1/admin/moderation-queue",
+			"POST /api/v1/admin/rate-limits/exempt",
+			"GET /api/v1/admin/search-analytics",
+			"GET /api/v1/admin/security-events",
+			"GET /api/v1/admin/stats",
+			"GET /api/v1/admin/users",
+			"PATCH /api/v1/applications/:id",
+			"POST /api/v1/applications/:id/archive",
+			"GET /api/v1/applications/:id/health",
+			"GET /api/v1/applications/:id/stats",
+			"POST /api/v1/applications/:id/unarchive",
+			"GET /api/v1/bugs",
+			"GET /api/v1/bugs/:id",
+			"PATCH /api/v1/bugs/:id",
+			"POST /api/v1/bugs/:id/attachments",
+			"GET /api/v1/bugs/:id/attachments",
+			"DELETE /api/v1/bugs/:id/attachments/:attachment_id",
+			"POST /api/v1/bugs/:id/comments",
+			"GET /api/v1/bugs/:id/comments",
+			"PUT /api/v1/bugs/:id/comments/:comment_id",
+			"DELETE /api/v1/bugs/:id/comments/:comment_id",
+			"POST /api/v1/bugs/:id/company-response",
+			"PATCH /api/v1/bugs/:id/priority",
+			"PATCH /api/v1/bugs/:id/status",
+			"POST /api/v1/bugs/:id/subscribe",
+			"DELETE /api/v1/bugs/:id/subscribe",
+			"POST /api/v1/bugs/:id/vote",
+			"POST /api/v1/bugs/batch",
+			"GET /api/v1/bugs/stats/geo",
+			"GET /api/v1/bugs/tags/popular",
+			"GET /api/v1/companies",
+			"GET /api/v1/companies/:id",
+			"POST /api/v1/companies/:id/assignment-rules",
+			"GET /api/v1/companies/:id/bugs",
+			"GET /api/v1/companies/:id/bugs/export",
+			"POST /api/v1/companies/:id/claim",
+			"GET /api/v1/companies/:id/dashboard",
+			"GET /api/v1/companies/:id/dashboard/export",
+			"POST /api/v1/companies/:id/domain-change",
+			"POST /api/v1/companies/:id/domain-change/confirm",
+			"POST /api/v1/companies/:id/invite-links",
+			"POST /api/v1/companies/:id/logo",
+			"POST /api/v1/companies/:id/members",
+			"DELETE /api/v1/companies/:id/members",
+			"PATCH /api/v1/companies/:id/members/:user_id/role",
+			"POST /api/v1/companies/:id/members/bulk",
+			"POST /api/v1/companies/:id/resend-verification",
+			"GET /api/v1/companies/:id/stats",
+			"POST /api/v1/companies/:id/verify",
+			"GET /api/v1/companies/:id/webhooks",
+			"POST /api/v1/companies/:id/webhooks",
+			"GET /api/v1/companies/:id/webhooks",
+			"DELETE /api/v1/companies/:id/webhooks/:webhook_id",
+			"DELETE /api/v1/companies/:id/webhooks/:webhook_id",
+			"POST /api/v1/invite/accept",
+			"POST /api/v1/invite/link",
+			"POST /api/v1/me/blocks",
+			"GET /api/v1/me/bugs",
+			"GET /api/v1/me/notification-preferences",
+			"GET /api/v1/tags/:tag/bugs",
+			"GET /api/v1/tags/:tag/related",
+			"GET /api/v1/users/:id/stats",
+			"GET /api/v2/bugs",
+			"GET /api/v2/bugs/:id",
+			"GET /api/v2/bugs/:id/attachments",
+		},
+	})
+	ErrTokenGenerationFailed = register(ErrorCode{
+		Code: "TOKEN_GENERATION_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to generate token",
+		Endpoints: []string{
+			"POST /api/v1/auth/login",
+			"GET /api/v1/auth/oauth/callback/:provider",
+			"POST /api/v1/auth/password-reset",
+			"POST /api/v1/auth/register",
+			"POST /api/v1/auth/resend-verification",
+			"POST /api/v1/companies/:id/claim",
+			"POST /api/v1/companies/:id/domain-change",
+			"POST /api/v1/companies/:id/domain-change/confirm",
+			"POST /api/v1/companies/:id/resend-verification",
+			"POST /api/v1/companies/:id/webhooks",
+		},
+	})
+	ErrUnauthorized = register(ErrorCode{
+		Code: "UNAUTHORIZED",
+		HTTP: http.StatusUnauthorized,
+		Desc: "Authentication required",
+		Endpoints: []string{
+			"POST /api/v1/admin/companies/:id/verify",
+			"PATCH /api/v1/applications/:id",
+			"POST /api/v1/applications/:id/archive",
+			"POST /api/v1/applications/:id/unarchive",
+			"POST /api/v1/auth/logout-all",
+			"POST /api/v1/auth/oauth/link/:provider",
+			"GET /api/v1/auth/profile",
+			"PUT /api/v1/auth/profile",
+			"PATCH /api/v1/bugs/:id",
+			"DELETE /api/v1/bugs/:id/attachments/:attachment_id",
+			"POST /api/v1/bugs/:id/company-response",
+			"PATCH /api/v1/bugs/:id/priority",
+			"PATCH /api/v1/bugs/:id/status",
+			"POST /api/v1/companies/:id/assignment-rules",
+			"GET /api/v1/companies/:id/bugs",
+			"GET /api/v1/companies/:id/bugs/export",
+			"POST /api/v1/companies/:id/claim",
+			"GET /api/v1/companies/:id/dashboard",
+			"GET /api/v1/companies/:id/dashboard/export",
+			"POST /api/v1/companies/:id/domain-change",
+			"POST /api/v1/companies/:id/domain-change/confirm",
+			"POST /api/v1/companies/:id/invite-links",
+			"POST /api/v1/companies/:id/logo",
+			"POST /api/v1/companies/:id/members",
+			"DELETE /api/v1/companies/:id/members",
+			"PATCH /api/v1/companies/:id/members/:user_id/role",
+			"POST /api/v1/companies/:id/members/bulk",
+			"POST /api/v1/companies/:id/resend-verification",
+			"POST /api/v1/companies/:id/verify",
+			"POST /api/v1/companies/:id/webhooks",
+			"GET /api/v1/companies/:id/webhooks",
+			"DELETE /api/v1/companies/:id/webhooks/:webhook_id",
+			"DELETE /api/v1/companies/:id/webhooks/:webhook_id",
+			"POST /api/v1/invite/link",
+			"POST /api/v1/me/blocks",
+			"DELETE /api/v1/me/blocks/:user_id",
+			"POST /api/v1/me/change-password",
+			"GET /api/v1/me/notification-preferences",
+			"PATCH /api/v1/me/notification-preferences",
+			"GET /api/v1/notifications/stream",
+			"GET /metrics",
+		},
+	})
+	ErrUpdateFailed = register(ErrorCode{
+		Code: "UPDATE_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to update the resource",
+		Endpoints: []string{
+			"PATCH /api/v1/applications/:id",
+			"POST /api/v1/applications/:id/archive",
+			"POST /api/v1/applications/:id/unarchive",
+			"PUT /api/v1/auth/profile",
+			"PATCH /api/v1/bugs/:id",
+			"PUT /api/v1/bugs/:id/comments/:comment_id",
+			"PATCH /api/v1/bugs/:id/priority",
+			"PATCH /api/v1/bugs/:id/status",
+			"POST /api/v1/companies/:id/claim",
+			"POST /api/v1/companies/:id/domain-change",
+			"POST /api/v1/companies/:id/domain-change/confirm",
+			"POST /api/v1/companies/:id/logo",
+			"PATCH /api/v1/companies/:id/members/:user_id/role",
+			"POST /api/v1/invite/accept",
+			"POST /api/v1/invite/link",
+			"PATCH /api/v1/me/notification-preferences",
+		},
+	})
+	ErrUserNotFound = register(ErrorCode{
+		Code: "USER_NOT_FOUND",
+		HTTP: http.StatusNotFound,
+		Desc: "User not found",
+		Endpoints: []string{
+			"PATCH /api/v1/admin/bugs/:id/owner",
+			"POST /api/v1/admin/rate-limits/exempt",
+			"POST /api/v1/auth/oauth/link/:provider",
+			"GET /api/v1/auth/profile",
+			"PUT /api/v1/auth/profile",
+			"POST /api/v1/companies/:id/members",
+			"POST /api/v1/invite/accept",
+			"POST /api/v1/invite/link",
+			"POST /api/v1/me/blocks",
+			"POST /api/v1/me/change-password",
+			"GET /api/v1/unsubscribe",
+			"GET /api/v1/users/:id/stats",
+		},
+	})
+	ErrValidationError = register(ErrorCode{
+		Code: "VALIDATION_ERROR",
+		HTTP: http.StatusBadRequest,
+		Desc: "Invalid request data",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"DELETE /api/v1/admin/bugs/:id",
+			"POST /api/v1/admin/bugs/:id/flag",
+			"PATCH /api/v1/admin/bugs/:id/owner",
+			"GET /api/v1/admin/bugs/export",
+			"POST /api/v1/admin/bugs/merge",
+			"POST /api/v1/admin/companies/:id/verify",
+			"POST /api/v1/admin/ip-blocks",
+			"POST /api/v1/admin/rate-limits/exempt",
+			"PATCH /api/v1/applications/:id",
+			"GET /api/v1/bugs",
+			"POST /api/v1/bugs",
+			"PATCH /api/v1/bugs/:id",
+			"GET /api/v1/bugs/:id/attachments",
+			"POST /api/v1/bugs/:id/comments",
+			"GET /api/v1/bugs/:id/comments",
+			"PUT /api/v1/bugs/:id/comments/:comment_id",
+			"POST /api/v1/bugs/:id/company-response",
+			"PATCH /api/v1/bugs/:id/priority",
+			"PATCH /api/v1/bugs/:id/status",
+			"POST /api/v1/bugs/batch",
+			"GET /api/v1/companies",
+			"POST /api/v1/companies/:id/assignment-rules",
+			"GET /api/v1/companies/:id/bugs",
+			"GET /api/v1/companies/:id/bugs/export",
+			"POST /api/v1/companies/:id/claim",
+			"POST /api/v1/companies/:id/domain-change",
+			"POST /api/v1/companies/:id/domain-change/confirm",
+			"POST /api/v1/companies/:id/invite-links",
+			"POST /api/v1/companies/:id/members",
+			"DELETE /api/v1/companies/:id/members",
+			"PATCH /api/v1/companies/:id/members/:user_id/role",
+			"POST /api/v1/companies/:id/members/bulk",
+			"POST /api/v1/companies/:id/verify",
+			"POST /api/v1/companies/:id/webhooks",
+			"POST /api/v1/me/blocks",
+			"GET /api/v1/me/bugs",
+			"PATCH /api/v1/me/notification-preferences",
+			"GET /api/v1/tags/:tag/bugs",
+			"GET /api/v2/bugs",
+			"POST /api/v2/bugs",
+			"GET /api/v2/bugs/:id/attachments",
+		},
+	})
+	ErrVerificationFailed = register(ErrorCode{
+		Code: "VERIFICATION_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to complete verification",
+		Endpoints: []string{
+			"POST /api/v1/admin/companies/:id/verify",
+			"POST /api/v1/auth/resend-verification",
+			"GET /api/v1/auth/verify-email",
+			"POST /api/v1/companies/:id/verify",
+		},
+	})
+)
+
+// Authentication and account errors
+var (
+	ErrAuthURLGenerationFailed = register(ErrorCode{
+		Code: "AUTH_URL_GENERATION_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to generate authorization URL",
+		Endpoints: []string{
+			"GET /api/v1/auth/oauth/:provider",
+			"GET /api/v1/auth/oauth/:provider/redirect",
+		},
+	})
+	ErrEmailNotVerified = register(ErrorCode{
+		Code: "EMAIL_NOT_VERIFIED",
+		HTTP: http.StatusUnauthorized,
+		Desc: "Please verify your email address before logging in",
+		Endpoints: []string{
+			"POST /api/v1/auth/login",
+		},
+	})
+	ErrHashFailed = register(ErrorCode{
+		Code: "HASH_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to process password",
+		Endpoints: []string{
+			"POST /api/v1/auth/password-reset/confirm",
+			"POST /api/v1/auth/register",
+			"POST /api/v1/me/change-password",
+		},
+	})
+	ErrInvalidPasswordAuthMethod = register(ErrorCode{
+		Code: "INVALID_AUTH_METHOD",
+		HTTP: http.StatusBadRequest,
+		Desc: "Password can only be changed for email accounts",
+		Endpoints: []string{
+			"POST /api/v1/me/change-password",
+		},
+	})
+	ErrInvalidAuthMethod = register(ErrorCode{
+		Code: "INVALID_AUTH_METHOD",
+		HTTP: http.StatusUnauthorized,
+		Desc: "This account uses a different authentication method",
+		Endpoints: []string{
+			"POST /api/v1/auth/login",
+		},
+	})
+	ErrInvalidCredentials = register(ErrorCode{
+		Code: "INVALID_CREDENTIALS",
+		HTTP: http.StatusUnauthorized,
+		Desc: "Invalid email or password",
+		Endpoints: []string{
+			"POST /api/v1/auth/login",
+		},
+	})
+	ErrInvalidCurrentPassword = register(ErrorCode{
+		Code: "INVALID_CURRENT_PASSWORD",
+		HTTP: http.StatusBadRequest,
+		Desc: "Current password is incorrect",
+		Endpoints: []string{
+			"POST /api/v1/me/change-password",
+		},
+	})
+	ErrInvalidProvider = register(ErrorCode{
+		Code: "INVALID_PROVIDER",
+		HTTP: http.StatusBadRequest,
+		Desc: "Unsupported OAuth provider",
+		Endpoints: []string{
+			"GET /api/v1/auth/oauth/:provider",
+			"GET /api/v1/auth/oauth/:provider/redirect",
+			"GET /api/v1/auth/oauth/callback/:provider",
+			"POST /api/v1/auth/oauth/link/:provider",
+		},
+	})
+	ErrInvalidRefreshToken = register(ErrorCode{
+		Code: "INVALID_REFRESH_TOKEN",
+		HTTP: http.StatusUnauthorized,
+		Desc: "Invalid or expired refresh token",
+		Endpoints: []string{
+			"POST /api/v1/auth/refresh",
+		},
+	})
+	ErrInvalidRequest = register(ErrorCode{
+		Code: "INVALID_REQUEST",
+		HTTP: http.StatusBadRequest,
+		Desc: "Invalid request data",
+		Endpoints: []string{
+			"DELETE /api/v1/admin/bugs/:id",
+			"DELETE /api/v1/admin/bugs/purge",
+			"POST /api/v1/auth/login",
+			"POST /api/v1/auth/oauth/link/:provider",
+			"POST /api/v1/auth/password-reset",
+			"POST /api/v1/auth/password-reset/confirm",
+			"PUT /api/v1/auth/profile",
+			"POST /api/v1/auth/refresh",
+			"POST /api/v1/auth/register",
+			"POST /api/v1/auth/resend-verification",
+			"POST /api/v1/me/change-password",
+		},
+	})
+	ErrInvalidResetToken = register(ErrorCode{
+		Code: "INVALID_RESET_TOKEN",
+		HTTP: http.StatusBadRequest,
+		Desc: "Invalid or expired reset token",
+		Endpoints: []string{
+			"POST /api/v1/auth/password-reset/confirm",
+		},
+	})
+	ErrInvalidState = register(ErrorCode{
+		Code: "INVALID_STATE",
+		HTTP: http.StatusBadRequest,
+		Desc: "Invalid state parameter",
+		Endpoints: []string{
+			"GET /api/v1/auth/oauth/callback/:provider",
+			"POST /api/v1/auth/oauth/link/:provider",
+		},
+	})
+	ErrInvalidTokenFormat = register(ErrorCode{
+		Code: "INVALID_TOKEN_FORMAT",
+		HTTP: http.StatusBadRequest,
+		Desc: "Invalid authorization header format",
+		Endpoints: []string{
+			"POST /api/v1/auth/logout",
+		},
+	})
+	ErrLinkFailed = register(ErrorCode{
+		Code: "LINK_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to link OAuth account",
+		Endpoints: []string{
+			"POST /api/v1/auth/oauth/link/:provider",
+		},
+	})
+	ErrLogoutAllFailed = register(ErrorCode{
+		Code: "LOGOUT_ALL_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to logout from all devices",
+		Endpoints: []string{
+			"POST /api/v1/auth/logout-all",
+		},
+	})
+	ErrLogoutFailed = register(ErrorCode{
+		Code: "LOGOUT_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to logout",
+		Endpoints: []string{
+			"POST /api/v1/auth/logout",
+		},
+	})
+	ErrMissingCode = register(ErrorCode{
+		Code: "MISSING_CODE",
+		HTTP: http.StatusBadRequest,
+		Desc: "Authorization code is required",
+		Endpoints: []string{
+			"GET /api/v1/auth/oauth/callback/:provider",
+		},
+	})
+	ErrMissingProvider = register(ErrorCode{
+		Code: "MISSING_PROVIDER",
+		HTTP: http.StatusBadRequest,
+		Desc: "OAuth provider is required",
+		Endpoints: []string{
+			"GET /api/v1/auth/oauth/:provider",
+			"GET /api/v1/auth/oauth/:provider/redirect",
+			"GET /api/v1/auth/oauth/callback/:provider",
+			"POST /api/v1/auth/oauth/link/:provider",
+		},
+	})
+	ErrMissingState = register(ErrorCode{
+		Code: "MISSING_STATE",
+		HTTP: http.StatusBadRequest,
+		Desc: "State parameter is required",
+		Endpoints: []string{
+			"GET /api/v1/auth/oauth/callback/:provider",
+		},
+	})
+	ErrOAuthAccountLinked = register(ErrorCode{
+		Code: "OAUTH_ACCOUNT_LINKED",
+		HTTP: http.StatusConflict,
+		Desc: "This OAuth account is already linked to another user",
+		Endpoints: []string{
+			"POST /api/v1/auth/oauth/link/:provider",
+		},
+	})
+	ErrPasswordUpdateFailed = register(ErrorCode{
+		Code: "PASSWORD_UPDATE_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to update password",
+		Endpoints: []string{
+			"POST /api/v1/auth/password-reset/confirm",
+			"POST /api/v1/me/change-password",
+		},
+	})
+	ErrResetRequestFailed = register(ErrorCode{
+		Code: "RESET_REQUEST_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to process password reset request",
+		Endpoints: []string{
+			"POST /api/v1/auth/password-reset",
+		},
+	})
+	ErrStateGenerationFailed = register(ErrorCode{
+		Code: "STATE_GENERATION_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to generate OAuth state",
+		Endpoints: []string{
+			"GET /api/v1/auth/oauth/:provider",
+			"GET /api/v1/auth/oauth/:provider/redirect",
+		},
+	})
+	ErrTokenExchangeFailed = register(ErrorCode{
+		Code: "TOKEN_EXCHANGE_FAILED",
+		HTTP: http.StatusBadRequest,
+		Desc: "Failed to exchange authorization code for token",
+		Endpoints: []string{
+			"GET /api/v1/auth/oauth/callback/:provider",
+			"POST /api/v1/auth/oauth/link/:provider",
+		},
+	})
+	ErrUserCreationFailed = register(ErrorCode{
+		Code: "USER_CREATION_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to create user account",
+		Endpoints: []string{
+			"GET /api/v1/auth/oauth/callback/:provider",
+			"POST /api/v1/auth/register",
+		},
+	})
+	ErrUserExists = register(ErrorCode{
+		Code: "USER_EXISTS",
+		HTTP: http.StatusConflict,
+		Desc: "User with this email already exists",
+		Endpoints: []string{
+			"POST /api/v1/auth/register",
+		},
+	})
+	ErrUserInfoFailed = register(ErrorCode{
+		Code: "USER_INFO_FAILED",
+		HTTP: http.StatusBadRequest,
+		Desc: "Failed to get user information from OAuth provider",
+		Endpoints: []string{
+			"GET /api/v1/auth/oauth/callback/:provider",
+			"POST /api/v1/auth/oauth/link/:provider",
+		},
+	})
+	ErrWeakPassword = register(ErrorCode{
+		Code: "WEAK_PASSWORD",
+		HTTP: http.StatusBadRequest,
+		Desc: "Password does not meet the strength requirements",
+		Endpoints: []string{
+			"POST /api/v1/auth/password-reset/confirm",
+			"POST /api/v1/auth/register",
+			"POST /api/v1/me/change-password",
+		},
+	})
+)
+
+// Bug report errors
+var (
+	ErrActivityUpdateFailed = register(ErrorCode{
+		Code: "ACTIVITY_UPDATE_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to update user activity",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs",
+			"POST /api/v1/bugs/:id/comments",
+			"POST /api/v1/bugs/:id/company-response",
+			"POST /api/v1/bugs/:id/vote",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrAnonymousEmailLimitReached = register(ErrorCode{
+		Code: "ANONYMOUS_EMAIL_LIMIT_REACHED",
+		HTTP: http.StatusTooManyRequests,
+		Desc: "Too many anonymous bug reports with this contact email today",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrApplicationArchived = register(ErrorCode{
+		Code: "APPLICATION_ARCHIVED",
+		HTTP: http.StatusUnprocessableEntity,
+		Desc: "Application has been archived and no longer accepts bug reports",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs",
+			"PATCH /api/v1/bugs/:id",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrApplicationError = register(ErrorCode{
+		Code: "APPLICATION_ERROR",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to process application",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrApplicationUpdateError = register(ErrorCode{
+		Code: "APPLICATION_UPDATE_ERROR",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to associate application with company",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrAssignmentRulesFailed = register(ErrorCode{
+		Code: "ASSIGNMENT_RULES_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to evaluate bug assignment rules",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs",
+			"PATCH /api/v1/bugs/:id",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrAttachmentNotFound = register(ErrorCode{
+		Code: "ATTACHMENT_NOT_FOUND",
+		HTTP: http.StatusNotFound,
+		Desc: "Attachment not found",
+		Endpoints: []string{
+			"DELETE /api/v1/bugs/:id/attachments/:attachment_id",
+			"GET /attachments/serve/:filename",
+		},
+	})
+	ErrAuthRequired = register(ErrorCode{
+		Code: "AUTH_REQUIRED",
+		HTTP: http.StatusUnauthorized,
+		Desc: "Authentication required",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs/:id/attachments",
+			"POST /api/v1/bugs/:id/comments",
+			"PUT /api/v1/bugs/:id/comments/:comment_id",
+			"DELETE /api/v1/bugs/:id/comments/:comment_id",
+			"POST /api/v1/bugs/:id/company-response",
+			"PATCH /api/v1/bugs/:id/status",
+			"POST /api/v1/bugs/:id/subscribe",
+			"DELETE /api/v1/bugs/:id/subscribe",
+			"POST /api/v1/bugs/:id/vote",
+		},
+	})
+	ErrCommentCreateFailed = register(ErrorCode{
+		Code: "COMMENT_CREATE_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to create comment",
+		Endpoints: []string{
+			"POST /api/v1/bugs/:id/comments",
+		},
+	})
+	ErrCommentEditWindowExpired = register(ErrorCode{
+		Code: "COMMENT_EDIT_WINDOW_EXPIRED",
+		HTTP: http.StatusForbidden,
+		Desc: "Comments can only be edited or deleted by their author within 24 hours of posting",
+		Endpoints: []string{
+			"DELETE /api/v1/bugs/:id/comments/:comment_id",
+			"PUT /api/v1/bugs/:id/comments/:comment_id",
+		},
+	})
+	ErrCommentNotFound = register(ErrorCode{
+		Code: "COMMENT_NOT_FOUND",
+		HTTP: http.StatusNotFound,
+		Desc: "Comment not found",
+		Endpoints: []string{
+			"DELETE /api/v1/bugs/:id/comments/:comment_id",
+			"PUT /api/v1/bugs/:id/comments/:comment_id",
+		},
+	})
+	ErrCommentTooShort = register(ErrorCode{
+		Code: "COMMENT_TOO_SHORT",
+		HTTP: http.StatusUnprocessableEntity,
+		Desc: "Comment has too few non-whitespace characters",
+		Endpoints: []string{
+			"POST /api/v1/bugs/:id/comments",
+			"PUT /api/v1/bugs/:id/comments/:comment_id",
+			"POST /api/v1/bugs/:id/company-response",
+		},
+	})
+	ErrCompanyError = register(ErrorCode{
+		Code: "COMPANY_ERROR",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to process company",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrCreateFailed = register(ErrorCode{
+		Code: "CREATE_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to create the resource",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs",
+			"POST /api/v1/bugs/:id/company-response",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrCustomFieldsEncodingFailed = register(ErrorCode{
+		Code: "CUSTOM_FIELDS_ENCODING_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to encode custom fields",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrCustomFieldsInvalid = register(ErrorCode{
+		Code: "CUSTOM_FIELDS_INVALID",
+		HTTP: http.StatusBadRequest,
+		Desc: "Custom fields do not match the application schema",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrDBError = register(ErrorCode{
+		Code: "DB_ERROR",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to save file attachment record",
+		Endpoints: []string{
+			"POST /api/v1/bugs/:id/attachments",
+		},
+	})
+	ErrDescriptionTooShort = register(ErrorCode{
+		Code: "DESCRIPTION_TOO_SHORT",
+		HTTP: http.StatusUnprocessableEntity,
+		Desc: "Description has too few words",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs",
+			"PATCH /api/v1/bugs/:id",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrFileReadError = register(ErrorCode{
+		Code: "FILE_READ_ERROR",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to read uploaded file",
+		Endpoints: []string{
+			"POST /api/v1/bugs/:id/attachments",
+			"POST /api/v1/companies/:id/logo",
+		},
+	})
+	ErrFileTooLarge = register(ErrorCode{
+		Code: "FILE_TOO_LARGE",
+		HTTP: http.StatusBadRequest,
+		Desc: "File size exceeds 10MB limit",
+		Endpoints: []string{
+			"POST /api/v1/bugs/:id/attachments",
+			"POST /api/v1/companies/:id/logo",
+		},
+	})
+	ErrIdempotencyKeyInUse = register(ErrorCode{
+		Code: "IDEMPOTENCY_KEY_IN_USE",
+		HTTP: http.StatusConflict,
+		Desc: "A request with this idempotency key is still being processed. Retry once it has finished",
+		Endpoints: []string{
+			"POST /api/v1/bugs",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrInvalidApplicationName = register(ErrorCode{
+		Code: "INVALID_APPLICATION_NAME",
+		HTTP: http.StatusBadRequest,
+		Desc: "Application name must be between 1 and 255 characters and contain no malicious content",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrInvalidApplicationURL = register(ErrorCode{
+		Code: "INVALID_APPLICATION_URL",
+		HTTP: http.StatusBadRequest,
+		Desc: "Invalid application URL format",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs",
+			"PATCH /api/v1/bugs/:id",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrInvalidContactEmail = register(ErrorCode{
+		Code: "INVALID_CONTACT_EMAIL",
+		HTTP: http.StatusBadRequest,
+		Desc: "Invalid email format",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrInvalidContent = register(ErrorCode{
+		Code: "INVALID_CONTENT",
+		HTTP: http.StatusBadRequest,
+		Desc: "Content must be between 1 and 2000 characters and contain no malicious content",
+		Endpoints: []string{
+			"POST /api/v1/bugs/:id/comments",
+			"PUT /api/v1/bugs/:id/comments/:comment_id",
+			"POST /api/v1/bugs/:id/company-response",
+		},
+	})
+	ErrInvalidCursor = register(ErrorCode{
+		Code: "INVALID_CURSOR",
+		HTTP: http.StatusBadRequest,
+		Desc: "Invalid pagination cursor",
+		Endpoints: []string{
+			"GET /api/v1/bugs",
+			"GET /api/v2/bugs",
+		},
+	})
+	ErrInvalidCustomFields = register(ErrorCode{
+		Code: "INVALID_CUSTOM_FIELDS",
+		HTTP: http.StatusBadRequest,
+		Desc: "Custom fields must be a JSON object",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrInvalidCustomFieldFilter = register(ErrorCode{
+		Code: "INVALID_CUSTOM_FIELD_FILTER",
+		HTTP: http.StatusBadRequest,
+		Desc: "Invalid custom field name",
+		Endpoints: []string{
+			"GET /api/v1/admin/bugs/export",
+			"GET /api/v1/bugs",
+			"GET /api/v1/companies/:id/bugs/export",
+		},
+	})
+	ErrInvalidDescription = register(ErrorCode{
+		Code: "INVALID_DESCRIPTION",
+		HTTP: http.StatusBadRequest,
+		Desc: "Description must be between 10 and 5000 characters and contain no malicious content",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs",
+			"PATCH /api/v1/bugs/:id",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrInvalidFilename = register(ErrorCode{
+		Code: "INVALID_FILENAME",
+		HTTP: http.StatusBadRequest,
+		Desc: "Invalid attachment filename",
+		Endpoints: []string{
+			"GET /attachments/serve/:filename",
+		},
+	})
+	ErrInvalidFileType = register(ErrorCode{
+		Code: "INVALID_FILE_TYPE",
+		HTTP: http.StatusBadRequest,
+		Desc: "Only image files are allowed (JPEG, PNG, GIF, WebP)",
+		Endpoints: []string{
+			"POST /api/v1/bugs/:id/attachments",
+			"POST /api/v1/companies/:id/logo",
+		},
+	})
+	ErrInvalidInclude = register(ErrorCode{
+		Code: "INVALID_INCLUDE",
+		HTTP: http.StatusBadRequest,
+		Desc: "Invalid include parameter",
+		Endpoints: []string{
+			"GET /api/v1/bugs/:id",
+			"GET /api/v2/bugs/:id",
+		},
+	})
+	ErrInvalidParentComment = register(ErrorCode{
+		Code: "INVALID_PARENT_COMMENT",
+		HTTP: http.StatusBadRequest,
+		Desc: "Parent comment does not exist on this bug",
+		Endpoints: []string{
+			"POST /api/v1/bugs/:id/comments",
+		},
+	})
+	ErrInvalidStatus = register(ErrorCode{
+		Code: "INVALID_STATUS",
+		HTTP: http.StatusBadRequest,
+		Desc: "Invalid status value",
+		Endpoints: []string{
+			"PATCH /api/v1/bugs/:id/status",
+			"GET /api/v1/companies/:id/dashboard",
+			"GET /api/v1/me/bugs",
+		},
+	})
+	ErrInvalidTag = register(ErrorCode{
+		Code: "INVALID_TAG",
+		HTTP: http.StatusBadRequest,
+		Desc: "Invalid tag",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs",
+			"PATCH /api/v1/bugs/:id",
+			"GET /api/v1/tags/:tag/bugs",
+			"GET /api/v1/tags/:tag/related",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrInvalidTitle = register(ErrorCode{
+		Code: "INVALID_TITLE",
+		HTTP: http.StatusBadRequest,
+		Desc: "Title must be between 5 and 255 characters and contain no malicious content",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs",
+			"PATCH /api/v1/bugs/:id",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrInvalidUser = register(ErrorCode{
+		Code: "INVALID_USER",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Invalid user ID",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs/:id/comments",
+			"PUT /api/v1/bugs/:id/comments/:comment_id",
+			"DELETE /api/v1/bugs/:id/comments/:comment_id",
+			"POST /api/v1/bugs/:id/company-response",
+			"PATCH /api/v1/bugs/:id/status",
+			"POST /api/v1/bugs/:id/subscribe",
+			"DELETE /api/v1/bugs/:id/subscribe",
+			"POST /api/v1/bugs/:id/vote",
+		},
+	})
+	ErrNoFile = register(ErrorCode{
+		Code: "NO_FILE",
+		HTTP: http.StatusBadRequest,
+		Desc: "No file uploaded",
+		Endpoints: []string{
+			"POST /api/v1/bugs/:id/attachments",
+			"POST /api/v1/companies/:id/logo",
+		},
+	})
+	ErrOutboxEnqueueFailed = register(ErrorCode{
+		Code: "OUTBOX_ENQUEUE_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to queue notification",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs",
+			"POST /api/v1/bugs/:id/company-response",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrRecaptchaError = register(ErrorCode{
+		Code: "RECAPTCHA_ERROR",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to validate captcha",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrRecaptchaFailed = register(ErrorCode{
+		Code: "RECAPTCHA_FAILED",
+		HTTP: http.StatusBadRequest,
+		Desc: "Captcha validation failed",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrSaveFailed = register(ErrorCode{
+		Code: "SAVE_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to save uploaded file",
+		Endpoints: []string{
+			"POST /api/v1/bugs/:id/attachments",
+			"POST /api/v1/companies/:id/logo",
+		},
+	})
+	ErrSchemaLookupFailed = register(ErrorCode{
+		Code: "SCHEMA_LOOKUP_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to load custom field schema",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrSubscriptionFailed = register(ErrorCode{
+		Code: "SUBSCRIPTION_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to update bug subscription",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs",
+			"DELETE /api/v1/bugs/:id/subscribe",
+			"POST /api/v1/bugs/:id/subscribe",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrTagNotFound = register(ErrorCode{
+		Code: "TAG_NOT_FOUND",
+		HTTP: http.StatusNotFound,
+		Desc: "No bug reports carry this tag",
+		Endpoints: []string{
+			"GET /api/v1/tags/:tag/bugs",
+		},
+	})
+	ErrTitleTooShort = register(ErrorCode{
+		Code: "TITLE_TOO_SHORT",
+		HTTP: http.StatusUnprocessableEntity,
+		Desc: "Title has too few words",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs",
+			"PATCH /api/v1/bugs/:id",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrTooManyBugIDs = register(ErrorCode{
+		Code: "TOO_MANY_BUG_IDS",
+		HTTP: http.StatusBadRequest,
+		Desc: "Maximum 50 bugs can be fetched at once",
+		Endpoints: []string{
+			"POST /api/v1/bugs/batch",
+		},
+	})
+	ErrTooManyTags = register(ErrorCode{
+		Code: "TOO_MANY_TAGS",
+		HTTP: http.StatusBadRequest,
+		Desc: "Bug has more tags than allowed (10 by default)",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs",
+			"PATCH /api/v1/bugs/:id",
+			"POST /api/v2/bugs",
+		},
+	})
+	ErrUploadForbidden = register(ErrorCode{
+		Code: "UPLOAD_FORBIDDEN",
+		HTTP: http.StatusForbidden,
+		Desc: "You can only upload files to your own bug reports",
+		Endpoints: []string{
+			"POST /api/v1/bugs/:id/attachments",
+		},
+	})
+	ErrUserBlocked = register(ErrorCode{
+		Code: "USER_BLOCKED",
+		HTTP: http.StatusForbidden,
+		Desc: "The reporter of this bug has blocked you from commenting",
+		Endpoints: []string{
+			"POST /api/v1/bugs/:id/comments",
+		},
+	})
+	ErrVoteCheckFailed = register(ErrorCode{
+		Code: "VOTE_CHECK_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to check existing vote",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs/:id/vote",
+		},
+	})
+	ErrVoteCreateFailed = register(ErrorCode{
+		Code: "VOTE_CREATE_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to create vote",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs/:id/vote",
+		},
+	})
+	ErrVoteRemoveFailed = register(ErrorCode{
+		Code: "VOTE_REMOVE_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to remove vote",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"POST /api/v1/bugs/:id/vote",
+		},
+	})
+)
+
+// Company, team and application errors
+var (
+	ErrAlreadyArchived = register(ErrorCode{
+		Code: "ALREADY_ARCHIVED",
+		HTTP: http.StatusConflict,
+		Desc: "Application is already archived",
+		Endpoints: []string{
+			"POST /api/v1/applications/:id/archive",
+		},
+	})
+	ErrAlreadyMember = register(ErrorCode{
+		Code: "ALREADY_MEMBER",
+		HTTP: http.StatusBadRequest,
+		Desc: "User is already a member of this company",
+		Endpoints: []string{
+			"POST /api/v1/companies/:id/claim",
+			"POST /api/v1/companies/:id/members",
+			"POST /api/v1/invite/link",
+		},
+	})
+	ErrAlreadyVerified = register(ErrorCode{
+		Code: "ALREADY_VERIFIED",
+		HTTP: http.StatusBadRequest,
+		Desc: "Company is already verified",
+		Endpoints: []string{
+			"POST /api/v1/admin/companies/:id/verify",
+			"POST /api/v1/companies/:id/claim",
+			"POST /api/v1/companies/:id/resend-verification",
+			"POST /api/v1/companies/:id/verify",
+		},
+	})
+	ErrApplicationNotFound = register(ErrorCode{
+		Code: "APPLICATION_NOT_FOUND",
+		HTTP: http.StatusNotFound,
+		Desc: "Application not found",
+		Endpoints: []string{
+			"POST /api/graphql",
+			"PATCH /api/v1/applications/:id",
+			"POST /api/v1/applications/:id/archive",
+			"GET /api/v1/applications/:id/health",
+			"GET /api/v1/applications/:id/stats",
+			"POST /api/v1/applications/:id/unarchive",
+			"PATCH /api/v1/bugs/:id",
+		},
+	})
+	ErrApplicationUpdateFailed = register(ErrorCode{
+		Code: "APPLICATION_UPDATE_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to associate applications with company",
+		Endpoints: []string{
+			"POST /api/v1/companies/:id/verify",
+		},
+	})
+	ErrBugAssignmentFailed = register(ErrorCode{
+		Code: "BUG_ASSIGNMENT_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to assign bug reports to company",
+		Endpoints: []string{
+			"POST /api/v1/companies/:id/verify",
+		},
+	})
+	ErrCannotChangeOwnRole = register(ErrorCode{
+		Code: "CANNOT_CHANGE_OWN_ROLE",
+		HTTP: http.StatusForbidden,
+		Desc: "Admins cannot change their own role",
+		Endpoints: []string{
+			"PATCH /api/v1/companies/:id/members/:user_id/role",
+		},
+	})
+	ErrCompanyNotFound = register(ErrorCode{
+		Code: "COMPANY_NOT_FOUND",
+		HTTP: http.StatusNotFound,
+		Desc: "Company not found",
+		Endpoints: []string{
+			"POST /api/v1/admin/companies/:id/verify",
+			"GET /api/v1/companies/:id",
+			"POST /api/v1/companies/:id/assignment-rules",
+			"GET /api/v1/companies/:id/bugs/export",
+			"POST /api/v1/companies/:id/claim",
+			"GET /api/v1/companies/:id/dashboard",
+			"GET /api/v1/companies/:id/dashboard/export",
+			"POST /api/v1/companies/:id/domain-change",
+			"POST /api/v1/companies/:id/domain-change/confirm",
+			"POST /api/v1/companies/:id/invite-links",
+			"POST /api/v1/companies/:id/logo",
+			"POST /api/v1/companies/:id/members",
+			"PATCH /api/v1/companies/:id/members/:user_id/role",
+			"POST /api/v1/companies/:id/members/bulk",
+			"POST /api/v1/companies/:id/resend-verification",
+			"GET /api/v1/companies/:id/stats",
+			"POST /api/v1/companies/:id/webhooks",
+			"DELETE /api/v1/companies/:id/webhooks/:webhook_id",
+			"DELETE /api/v1/companies/:id/webhooks/:webhook_id",
+			"POST /api/v1/invite/link",
+		},
+	})
+	ErrDomainTaken = register(ErrorCode{
+		Code: "DOMAIN_TAKEN",
+		HTTP: http.StatusConflict,
+		Desc: "Domain is already registered to another company",
+		Endpoints: []string{
+			"POST /api/v1/companies/:id/domain-change",
+		},
+	})
+	ErrInvalidApplication = register(ErrorCode{
+		Code: "INVALID_APPLICATION",
+		HTTP: http.StatusBadRequest,
+		Desc: "Application does not belong to this company",
+		Endpoints: []string{
+			"POST /api/v1/companies/:id/assignment-rules",
+		},
+	})
+	ErrInvalidAssignee = register(ErrorCode{
+		Code: "INVALID_ASSIGNEE",
+		HTTP: http.StatusBadRequest,
+		Desc: "Assignee must be a member of this company",
+		Endpoints: []string{
+			"POST /api/v1/companies/:id/assignment-rules",
+		},
+	})
+	ErrInvalidDomain = register(ErrorCode{
+		Code: "INVALID_DOMAIN",
+		HTTP: http.StatusBadRequest,
+		Desc: "Invalid or mismatched domain",
+		Endpoints: []string{
+			"POST /api/v1/companies/:id/claim",
+			"POST /api/v1/companies/:id/domain-change",
+			"POST /api/v1/companies/:id/members",
+			"POST /api/v1/companies/:id/resend-verification",
+			"POST /api/v1/invite/link",
+		},
+	})
+	ErrInvalidImageDimensions = register(ErrorCode{
+		Code: "INVALID_IMAGE_DIMENSIONS",
+		HTTP: http.StatusBadRequest,
+		Desc: "Image dimensions are outside the allowed range",
+		Endpoints: []string{
+			"POST /api/v1/companies/:id/logo",
+		},
+	})
+	ErrInvalidRole = register(ErrorCode{
+		Code: "INVALID_ROLE",
+		HTTP: http.StatusBadRequest,
+		Desc: "Invalid member role",
+		Endpoints: []string{
+			"POST /api/v1/companies/:id/invite-links",
+			"POST /api/v1/companies/:id/members",
+			"PATCH /api/v1/companies/:id/members/:user_id/role",
+			"POST /api/v1/companies/:id/members/bulk",
+		},
+	})
+	ErrInvalidSearchLanguage = register(ErrorCode{
+		Code: "INVALID_SEARCH_LANGUAGE",
+		HTTP: http.StatusBadRequest,
+		Desc: "Search language is not a text search configuration supported by the database",
+		Endpoints: []string{
+			"PATCH /api/v1/applications/:id",
+		},
+	})
+	ErrInvalidSLAStatus = register(ErrorCode{
+		Code: "INVALID_SLA_STATUS",
+		HTTP: http.StatusBadRequest,
+		Desc: "sla_status must be one of ok, warning or breached",
+		Endpoints: []string{
+			"GET /api/v1/companies/:id/bugs",
+		},
+	})
+	ErrInvalidUserID = register(ErrorCode{
+		Code: "INVALID_USER_ID",
+		HTTP: http.StatusBadRequest,
+		Desc: "Invalid user ID format",
+		Endpoints: []string{
+			"DELETE /api/v1/companies/:id/members",
+			"PATCH /api/v1/companies/:id/members/:user_id/role",
+		},
+	})
+	ErrInvalidWebhookEvent = register(ErrorCode{
+		Code: "INVALID_WEBHOOK_EVENT",
+		HTTP: http.StatusBadRequest,
+		Desc: "Webhooks can subscribe to bug.created, bug.status_changed and comment.created",
+		Endpoints: []string{
+			"POST /api/v1/companies/:id/webhooks",
+		},
+	})
+	ErrInvalidWebhookURL = register(ErrorCode{
+		Code: "INVALID_WEBHOOK_URL",
+		HTTP: http.StatusBadRequest,
+		Desc: "Webhook URL must be an http or https URL that is not a loopback, private or link-local address",
+		Endpoints: []string{
+			"POST /api/v1/companies/:id/webhooks",
+		},
+	})
+	ErrInvitationAlreadyAccepted = register(ErrorCode{
+		Code: "INVITATION_ALREADY_ACCEPTED",
+		HTTP: http.StatusConflict,
+		Desc: "Invitation has already been accepted",
+		Endpoints: []string{
+			"POST /api/v1/invite/accept",
+		},
+	})
+	ErrInvitationExpired = register(ErrorCode{
+		Code: "INVITATION_EXPIRED",
+		HTTP: http.StatusGone,
+		Desc: "Invitation has expired. Ask a company admin to invite you again.",
+		Endpoints: []string{
+			"POST /api/v1/invite/accept",
+		},
+	})
+	ErrInvitationFailed = register(ErrorCode{
+		Code: "INVITATION_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to invite team members",
+		Endpoints: []string{
+			"POST /api/v1/companies/:id/invite-links",
+			"POST /api/v1/companies/:id/members/bulk",
+		},
+	})
+	ErrInvitationNotFound = register(ErrorCode{
+		Code: "INVITATION_NOT_FOUND",
+		HTTP: http.StatusNotFound,
+		Desc: "Invitation not found",
+		Endpoints: []string{
+			"POST /api/v1/invite/accept",
+		},
+	})
+	ErrInviteLinkBypassLimitReached = register(ErrorCode{
+		Code: "INVITE_LINK_BYPASS_LIMIT_REACHED",
+		HTTP: http.StatusForbidden,
+		Desc: "Invite link cannot admit more members from outside the company's domain",
+		Endpoints: []string{
+			"POST /api/v1/invite/link",
+		},
+	})
+	ErrInviteLinkExpired = register(ErrorCode{
+		Code: "INVITE_LINK_EXPIRED",
+		HTTP: http.StatusGone,
+		Desc: "Invite link has expired. Ask a company admin for a new link.",
+		Endpoints: []string{
+			"POST /api/v1/invite/link",
+		},
+	})
+	ErrInviteLinkNotFound = register(ErrorCode{
+		Code: "INVITE_LINK_NOT_FOUND",
+		HTTP: http.StatusNotFound,
+		Desc: "Invite link not found",
+		Endpoints: []string{
+			"POST /api/v1/invite/link",
+		},
+	})
+	ErrLastAdmin = register(ErrorCode{
+		Code: "LAST_ADMIN",
+		HTTP: http.StatusBadRequest,
+		Desc: "A company must keep at least one admin",
+		Endpoints: []string{
+			"DELETE /api/v1/companies/:id/members",
+			"PATCH /api/v1/companies/:id/members/:user_id/role",
+		},
+	})
+	ErrMemberCreationFailed = register(ErrorCode{
+		Code: "MEMBER_CREATION_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to add team member",
+		Endpoints: []string{
+			"POST /api/v1/companies/:id/members",
+			"POST /api/v1/companies/:id/verify",
+			"POST /api/v1/invite/accept",
+			"POST /api/v1/invite/link",
+		},
+	})
+	ErrMemberNotFound = register(ErrorCode{
+		Code: "MEMBER_NOT_FOUND",
+		HTTP: http.StatusNotFound,
+		Desc: "Team member not found",
+		Endpoints: []string{
+			"DELETE /api/v1/companies/:id/members",
+			"PATCH /api/v1/companies/:id/members/:user_id/role",
+		},
+	})
+	ErrNotArchived = register(ErrorCode{
+		Code: "NOT_ARCHIVED",
+		HTTP: http.StatusConflict,
+		Desc: "Application is not archived",
+		Endpoints: []string{
+			"POST /api/v1/applications/:id/unarchive",
+		},
+	})
+	ErrNotMember = register(ErrorCode{
+		Code: "NOT_MEMBER",
+		HTTP: http.StatusForbidden,
+		Desc: "User is not a member of this company",
+		Endpoints: []string{
+			"GET /api/v1/companies/:id/dashboard",
+			"GET /api/v1/companies/:id/dashboard/export",
+			"DELETE /api/v1/companies/:id/members",
+		},
+	})
+	ErrNoPendingVerification = register(ErrorCode{
+		Code: "NO_PENDING_VERIFICATION",
+		HTTP: http.StatusBadRequest,
+		Desc: "Company has no pending verification. Start a new claim instead.",
+		Endpoints: []string{
+			"POST /api/v1/companies/:id/resend-verification",
+		},
+	})
+	ErrRemovalFailed = register(ErrorCode{
+		Code: "REMOVAL_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to remove team member",
+		Endpoints: []string{
+			"DELETE /api/v1/companies/:id/members",
+		},
+	})
+	ErrResendFailed = register(ErrorCode{
+		Code: "RESEND_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to resend verification email",
+		Endpoints: []string{
+			"POST /api/v1/companies/:id/resend-verification",
+		},
+	})
+	ErrRuleCreationFailed = register(ErrorCode{
+		Code: "RULE_CREATION_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to create assignment rule",
+		Endpoints: []string{
+			"POST /api/v1/companies/:id/assignment-rules",
+		},
+	})
+	ErrSameDomain = register(ErrorCode{
+		Code: "SAME_DOMAIN",
+		HTTP: http.StatusBadRequest,
+		Desc: "New domain is the same as the current domain",
+		Endpoints: []string{
+			"POST /api/v1/companies/:id/domain-change",
+		},
+	})
+	ErrStatsFailed = register(ErrorCode{
+		Code: "STATS_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to fetch statistics",
+		Endpoints: []string{
+			"GET /api/v1/companies/:id/dashboard",
+		},
+	})
+	ErrTokenExpired = register(ErrorCode{
+		Code: "TOKEN_EXPIRED",
+		HTTP: http.StatusBadRequest,
+		Desc: "Verification token has expired. Request a new verification email.",
+		Endpoints: []string{
+			"POST /api/v1/companies/:id/domain-change/confirm",
+			"POST /api/v1/companies/:id/verify",
+		},
+	})
+	ErrTooManyEmails = register(ErrorCode{
+		Code: "TOO_MANY_EMAILS",
+		HTTP: http.StatusBadRequest,
+		Desc: "Too many emails invited at once",
+		Endpoints: []string{
+			"POST /api/v1/companies/:id/members/bulk",
+		},
+	})
+	ErrTransactionFailed = register(ErrorCode{
+		Code: "TRANSACTION_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to accept invitation",
+		Endpoints: []string{
+			"POST /api/v1/invite/accept",
+			"POST /api/v1/invite/link",
+		},
+	})
+	ErrWebhookCreationFailed = register(ErrorCode{
+		Code: "WEBHOOK_CREATION_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to create webhook",
+		Endpoints: []string{
+			"POST /api/v1/companies/:id/webhooks",
+		},
+	})
+	ErrWebhookNotFound = register(ErrorCode{
+		Code: "WEBHOOK_NOT_FOUND",
+		HTTP: http.StatusNotFound,
+		Desc: "Webhook not found",
+		Endpoints: []string{
+			"DELETE /api/v1/companies/:id/webhooks/:webhook_id",
+		},
+	})
+)
+
+// User profile and preference errors
+var (
+	ErrAlreadyBlocked = register(ErrorCode{
+		Code: "ALREADY_BLOCKED",
+		HTTP: http.StatusConflict,
+		Desc: "User is already blocked",
+		Endpoints: []string{
+			"POST /api/v1/me/blocks",
+		},
+	})
+	ErrBlockFailed = register(ErrorCode{
+		Code: "BLOCK_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to block user",
+		Endpoints: []string{
+			"POST /api/v1/me/blocks",
+		},
+	})
+	ErrBlockNotFound = register(ErrorCode{
+		Code: "BLOCK_NOT_FOUND",
+		HTTP: http.StatusNotFound,
+		Desc: "User is not blocked",
+		Endpoints: []string{
+			"DELETE /api/v1/me/blocks/:user_id",
+		},
+	})
+	ErrCannotBlockAdmin = register(ErrorCode{
+		Code: "CANNOT_BLOCK_ADMIN",
+		HTTP: http.StatusForbidden,
+		Desc: "Administrators cannot be blocked",
+		Endpoints: []string{
+			"POST /api/v1/me/blocks",
+		},
+	})
+	ErrCannotBlockSelf = register(ErrorCode{
+		Code: "CANNOT_BLOCK_SELF",
+		HTTP: http.StatusBadRequest,
+		Desc: "You cannot block yourself",
+		Endpoints: []string{
+			"POST /api/v1/me/blocks",
+		},
+	})
+	ErrInvalidDigestFrequency = register(ErrorCode{
+		Code: "INVALID_DIGEST_FREQUENCY",
+		HTTP: http.StatusBadRequest,
+		Desc: "Digest frequency must be one of never, daily, weekly",
+		Endpoints: []string{
+			"PATCH /api/v1/me/notification-preferences",
+		},
+	})
+	ErrInvalidNotificationType = register(ErrorCode{
+		Code: "INVALID_NOTIFICATION_TYPE",
+		HTTP: http.StatusBadRequest,
+		Desc: "Invalid notification type",
+		Endpoints: []string{
+			"GET /api/v1/unsubscribe",
+		},
+	})
+	ErrInvalidSignature = register(ErrorCode{
+		Code: "INVALID_SIGNATURE",
+		HTTP: http.StatusForbidden,
+		Desc: "Invalid unsubscribe link",
+		Endpoints: []string{
+			"GET /api/v1/unsubscribe",
+		},
+	})
+	ErrUnblockFailed = register(ErrorCode{
+		Code: "UNBLOCK_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to unblock user",
+		Endpoints: []string{
+			"DELETE /api/v1/me/blocks/:user_id",
+		},
+	})
+	ErrUnsubscribeFailed = register(ErrorCode{
+		Code: "UNSUBSCRIBE_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to update notification preferences",
+		Endpoints: []string{
+			"GET /api/v1/unsubscribe",
+		},
+	})
+)
+
+// Administration errors
+var (
+	ErrAttachmentMergeFailed = register(ErrorCode{
+		Code: "ATTACHMENT_MERGE_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to merge attachments",
+		Endpoints: []string{
+			"POST /api/v1/admin/bugs/merge",
+		},
+	})
+	ErrAuditLogFailed = register(ErrorCode{
+		Code: "AUDIT_LOG_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to log audit action",
+		Endpoints: []string{
+			"POST /api/v1/admin/bugs/:id/flag",
+		},
+	})
+	ErrAuditLogNotFound = register(ErrorCode{
+		Code: "AUDIT_LOG_NOT_FOUND",
+		HTTP: http.StatusNotFound,
+		Desc: "Audit log entry not found",
+		Endpoints: []string{
+			"GET /api/v1/admin/audit-logs/:id",
+		},
+	})
+	ErrBugNotDeleted = register(ErrorCode{
+		Code: "BUG_NOT_DELETED",
+		HTTP: http.StatusBadRequest,
+		Desc: "Bug report is not deleted",
+		Endpoints: []string{
+			"POST /api/v1/admin/bugs/:id/restore",
+		},
+	})
+	ErrCommentMergeFailed = register(ErrorCode{
+		Code: "COMMENT_MERGE_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to merge comments",
+		Endpoints: []string{
+			"POST /api/v1/admin/bugs/merge",
+		},
+	})
+	ErrDeadLetterNotFound = register(ErrorCode{
+		Code: "DEAD_LETTER_NOT_FOUND",
+		HTTP: http.StatusNotFound,
+		Desc: "Dead-lettered event not found",
+		Endpoints: []string{
+			"DELETE /api/v1/admin/dead-letters/:id",
+			"POST /api/v1/admin/dead-letters/:id/retry",
+		},
+	})
+	ErrDeleteFailed = register(ErrorCode{
+		Code: "DELETE_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to delete the resource",
+		Endpoints: []string{
+			"DELETE /api/v1/admin/bugs/:id",
+			"DELETE /api/v1/admin/dead-letters/:id",
+			"DELETE /api/v1/admin/ip-blocks/*ip",
+			"DELETE /api/v1/bugs/:id/attachments/:attachment_id",
+			"DELETE /api/v1/bugs/:id/comments/:comment_id",
+			"DELETE /api/v1/companies/:id/webhooks/:webhook_id",
+			"DELETE /api/v1/companies/:id/webhooks/:webhook_id",
+		},
+	})
+	ErrExemptionFailed = register(ErrorCode{
+		Code: "EXEMPTION_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to create rate limit exemption",
+		Endpoints: []string{
+			"POST /api/v1/admin/rate-limits/exempt",
+		},
+	})
+	ErrInvalidAuditState = register(ErrorCode{
+		Code: "INVALID_AUDIT_STATE",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to decode audit log states",
+		Endpoints: []string{
+			"GET /api/v1/admin/audit-logs/:id",
+		},
+	})
+	ErrInvalidDate = register(ErrorCode{
+		Code: "INVALID_DATE",
+		HTTP: http.StatusBadRequest,
+		Desc: "Dates must be RFC3339 timestamps",
+		Endpoints: []string{
+			"GET /api/v1/admin/audit-logs",
+			"GET /api/v1/admin/security-events",
+		},
+	})
+	ErrInvalidDateRange = register(ErrorCode{
+		Code: "INVALID_DATE_RANGE",
+		HTTP: http.StatusBadRequest,
+		Desc: "from_date must be before to_date",
+		Endpoints: []string{
+			"GET /api/v1/admin/audit-logs",
+		},
+	})
+	ErrInvalidDays = register(ErrorCode{
+		Code: "INVALID_DAYS",
+		HTTP: http.StatusBadRequest,
+		Desc: "days must be an integer between 1 and 365",
+		Endpoints: []string{
+			"GET /api/v1/admin/search-analytics",
+		},
+	})
+	ErrInvalidEventType = register(ErrorCode{
+		Code: "INVALID_EVENT_TYPE",
+		HTTP: http.StatusBadRequest,
+		Desc: "Invalid security event type",
+		Endpoints: []string{
+			"GET /api/v1/admin/security-events",
+		},
+	})
+	ErrInvalidFormat = register(ErrorCode{
+		Code: "INVALID_FORMAT",
+		HTTP: http.StatusBadRequest,
+		Desc: "Format must be 'json' or 'csv'",
+		Endpoints: []string{
+			"GET /api/v1/admin/audit-logs",
+			"GET /api/v1/admin/bugs/export",
+			"GET /api/v1/companies/:id/bugs/export",
+			"GET /api/v1/companies/:id/dashboard/export",
+		},
+	})
+	ErrInvalidIPBlock = register(ErrorCode{
+		Code: "INVALID_IP_BLOCK",
+		HTTP: http.StatusBadRequest,
+		Desc: "Must be an IP address or a CIDR range no wider than /8 (IPv4) or /16 (IPv6)",
+		Endpoints: []string{
+			"POST /api/v1/admin/ip-blocks",
+			"DELETE /api/v1/admin/ip-blocks/*ip",
+		},
+	})
+	ErrInvalidMerge = register(ErrorCode{
+		Code: "INVALID_MERGE",
+		HTTP: http.StatusBadRequest,
+		Desc: "Source and target bugs must be different",
+		Endpoints: []string{
+			"POST /api/v1/admin/bugs/merge",
+		},
+	})
+	ErrInvalidPeriod = register(ErrorCode{
+		Code: "INVALID_PERIOD",
+		HTTP: http.StatusBadRequest,
+		Desc: "Period must be one of 7d, 30d, 90d (all, 7d, 30d for the company dashboard)",
+		Endpoints: []string{
+			"GET /api/v1/admin/stats",
+			"GET /api/v1/companies/:id/dashboard",
+			"GET /api/v1/companies/:id/dashboard/export",
+		},
+	})
+	ErrInvalidPurgeAfterDays = register(ErrorCode{
+		Code: "INVALID_PURGE_AFTER_DAYS",
+		HTTP: http.StatusBadRequest,
+		Desc: "purge_after_days must be a non-negative integer",
+		Endpoints: []string{
+			"DELETE /api/v1/admin/bugs/purge",
+		},
+	})
+	ErrInvalidSort = register(ErrorCode{
+		Code: "INVALID_SORT",
+		HTTP: http.StatusBadRequest,
+		Desc: "Sort must be 'asc' or 'desc'",
+		Endpoints: []string{
+			"GET /api/v1/admin/audit-logs",
+			"GET /api/v1/bugs/:id/attachments",
+			"GET /api/v1/me/bugs",
+			"GET /api/v1/tags/:tag/bugs",
+			"GET /api/v2/bugs/:id/attachments",
+		},
+	})
+	ErrIPBlockFailed = register(ErrorCode{
+		Code: "IP_BLOCK_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to block IP address",
+		Endpoints: []string{
+			"POST /api/v1/admin/ip-blocks",
+		},
+	})
+	ErrIPBlockNotFound = register(ErrorCode{
+		Code: "IP_BLOCK_NOT_FOUND",
+		HTTP: http.StatusNotFound,
+		Desc: "IP address is not blocked",
+		Endpoints: []string{
+			"DELETE /api/v1/admin/ip-blocks/*ip",
+		},
+	})
+	ErrIPBlockerUnavailable = register(ErrorCode{
+		Code: "IP_BLOCKER_UNAVAILABLE",
+		HTTP: http.StatusServiceUnavailable,
+		Desc: "IP blocking is not configured",
+		Endpoints: []string{
+			"POST /api/v1/admin/ip-blocks",
+			"DELETE /api/v1/admin/ip-blocks/*ip",
+		},
+	})
+	ErrMergeCommentFailed = register(ErrorCode{
+		Code: "MERGE_COMMENT_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to create merge comment",
+		Endpoints: []string{
+			"POST /api/v1/admin/bugs/merge",
+		},
+	})
+	ErrPurgeFailed = register(ErrorCode{
+		Code: "PURGE_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to purge deleted bugs",
+		Endpoints: []string{
+			"DELETE /api/v1/admin/bugs/purge",
+		},
+	})
+	ErrRateLimiterUnavailable = register(ErrorCode{
+		Code: "RATE_LIMITER_UNAVAILABLE",
+		HTTP: http.StatusServiceUnavailable,
+		Desc: "Rate limiter is not configured",
+		Endpoints: []string{
+			"POST /api/v1/admin/rate-limits/exempt",
+		},
+	})
+	ErrRebuildFailed = register(ErrorCode{
+		Code: "REBUILD_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to rebuild bug report from its events",
+		Endpoints: []string{
+			"POST /api/v1/admin/bugs/:id/rebuild-projection",
+		},
+	})
+	ErrRefreshFailed = register(ErrorCode{
+		Code: "REFRESH_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to refresh bug statistics",
+		Endpoints: []string{
+			"POST /api/v1/admin/stats/refresh",
+		},
+	})
+	ErrRestoreFailed = register(ErrorCode{
+		Code: "RESTORE_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to restore bug reports",
+		Endpoints: []string{
+			"POST /api/v1/admin/bugs/:id/restore",
+			"POST /api/v1/admin/bugs/restore-all",
+		},
+	})
+	ErrRetryFailed = register(ErrorCode{
+		Code: "RETRY_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to re-queue event",
+		Endpoints: []string{
+			"POST /api/v1/admin/dead-letters/:id/retry",
+		},
+	})
+	ErrSameOwner = register(ErrorCode{
+		Code: "SAME_OWNER",
+		HTTP: http.StatusBadRequest,
+		Desc: "Bug report already belongs to this user",
+		Endpoints: []string{
+			"PATCH /api/v1/admin/bugs/:id/owner",
+		},
+	})
+	ErrSourceBugNotFound = register(ErrorCode{
+		Code: "SOURCE_BUG_NOT_FOUND",
+		HTTP: http.StatusNotFound,
+		Desc: "Source bug report not found",
+		Endpoints: []string{
+			"POST /api/v1/admin/bugs/merge",
+		},
+	})
+	ErrSourceDeleteFailed = register(ErrorCode{
+		Code: "SOURCE_DELETE_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to remove source bug",
+		Endpoints: []string{
+			"POST /api/v1/admin/bugs/merge",
+		},
+	})
+	ErrTargetBugNotFound = register(ErrorCode{
+		Code: "TARGET_BUG_NOT_FOUND",
+		HTTP: http.StatusNotFound,
+		Desc: "Target bug report not found",
+		Endpoints: []string{
+			"POST /api/v1/admin/bugs/merge",
+		},
+	})
+	ErrTransferFailed = register(ErrorCode{
+		Code: "TRANSFER_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to transfer bug ownership",
+		Endpoints: []string{
+			"PATCH /api/v1/admin/bugs/:id/owner",
+		},
+	})
+	ErrVoteMergeFailed = register(ErrorCode{
+		Code: "VOTE_MERGE_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to merge votes",
+		Endpoints: []string{
+			"POST /api/v1/admin/bugs/merge",
+		},
+	})
+)
+
+// Errors returned by middleware before a request reaches its handler
+var (
+	ErrAuthCheckFailed = register(ErrorCode{
+		Code:      "AUTH_CHECK_FAILED",
+		HTTP:      http.StatusInternalServerError,
+		Desc:      "Failed to verify authentication status",
+		Endpoints: authenticatedEndpoints,
+	})
+	ErrConfirmationFailed = register(ErrorCode{
+		Code: "CONFIRMATION_FAILED",
+		HTTP: http.StatusInternalServerError,
+		Desc: "Failed to check or issue the action's confirmation token",
+		Endpoints: []string{
+			"DELETE /api/v1/admin/bugs/:id",
+			"DELETE /api/v1/admin/bugs/purge",
+		},
+	})
+	ErrConfirmationRequired = register(ErrorCode{
+		Code: "CONFIRMATION_REQUIRED",
+		HTTP: http.StatusPreconditionRequired,
+		Desc: "Destructive action must be confirmed by repeating the request with the confirmation token in X-Confirm-Token",
+		Endpoints: []string{
+			"DELETE /api/v1/admin/bugs/:id",
+			"DELETE /api/v1/admin/bugs/purge",
+		},
+	})
+	ErrInsufficientPrivileges = register(ErrorCode{
+		Code:      "INSUFFICIENT_PRIVILEGES",
+		HTTP:      http.StatusForbidden,
+		Desc:      "Admin privileges required",
+		Endpoints: adminEndpoints,
+	})
+	ErrInvalidAuthToken = register(ErrorCode{
+		Code:      "INVALID_TOKEN",
+		HTTP:      http.StatusUnauthorized,
+		Desc:      "Invalid or expired authentication token",
+		Endpoints: authenticatedEndpoints,
+	})
+	ErrInvalidTokenType = register(ErrorCode{
+		Code:      "INVALID_TOKEN_TYPE",
+		HTTP:      http.StatusUnauthorized,
+		Desc:      "Access token required",
+		Endpoints: authenticatedEndpoints,
+	})
+	ErrIPBlocked = register(ErrorCode{
+		Code:      "IP_BLOCKED",
+		HTTP:      http.StatusForbidden,
+		Desc:      "Requests from this IP address are blocked",
+		Endpoints: allEndpoints,
+	})
+	ErrIPNotAllowed = register(ErrorCode{
+		Code:      "IP_NOT_ALLOWED",
+		HTTP:      http.StatusForbidden,
+		Desc:      "Access denied from this IP address",
+		Endpoints: adminEndpoints,
+	})
+	ErrMissingAuthToken = register(ErrorCode{
+		Code:      "MISSING_TOKEN",
+		HTTP:      http.StatusUnauthorized,
+		Desc:      "Authentication token is required",
+		Endpoints: authenticatedEndpoints,
+	})
+	ErrRateLimitExceeded = register(ErrorCode{
+		Code:      "RATE_LIMIT_EXCEEDED",
+		HTTP:      http.StatusTooManyRequests,
+		Desc:      "Too many requests, please try again later",
+		Endpoints: apiEndpoints,
+	})
+	ErrRequestEntityTooLarge = register(ErrorCode{
+		Code:      "REQUEST_ENTITY_TOO_LARGE",
+		HTTP:      http.StatusRequestEntityTooLarge,
+		Desc:      "Request body too large",
+		Endpoints: allEndpoints,
+	})
+	ErrRequestTooLarge = register(ErrorCode{
+		Code: "REQUEST_TOO_LARGE",
+		HTTP: http.StatusRequestEntityTooLarge,
+		Desc: "Request body too large",
+	})
+	ErrSessionIPMismatch = register(ErrorCode{
+		Code:      "SESSION_IP_MISMATCH",
+		HTTP:      http.StatusUnauthorized,
+		Desc:      "Session is bound to a different IP address",
+		Endpoints: authenticatedEndpoints,
+	})
+	ErrSuspiciousUserAgent = register(ErrorCode{
+		Code:      "SUSPICIOUS_USER_AGENT",
+		HTTP:      http.StatusForbidden,
+		Desc:      "Access denied",
+		Endpoints: allEndpoints,
+	})
+	ErrTokenRevoked = register(ErrorCode{
+		Code:      "TOKEN_REVOKED",
+		HTTP:      http.StatusUnauthorized,
+		Desc:      "Authentication token has been revoked",
+		Endpoints: authenticatedEndpoints,
+	})
+)
@@ -0,0 +1,107 @@
+package errors
+
+// allEndpoints covers every route, for middleware applied to the whole router
+var allEndpoints = []string{"* /*"}
+
+// apiEndpoints covers the /api/v1, /api/v2 and GraphQL routes behind the general rate
+// limiter
+var apiEndpoints = []string{"POST /api/graphql", "* /api/v1/*", "* /api/v2/*"}
+
+// authenticatedEndpoints are the routes that require an access token
+var authenticatedEndpoints = []string{
+	"GET /api/v1/admin/audit-logs",
+	"GET /api/v1/admin/audit-logs/:id",
+	"GET /api/v1/admin/bugs",
+	"DELETE /api/v1/admin/bugs/:id",
+	"POST /api/v1/admin/bugs/:id/flag",
+	"PATCH /api/v1/admin/bugs/:id/owner",
+	"POST /api/v1/admin/bugs/:id/rebuild-projection",
+	"POST /api/v1/admin/bugs/:id/restore",
+	"GET /api/v1/admin/bugs/deleted",
+	"POST /api/v1/admin/bugs/merge",
+	"DELETE /api/v1/admin/bugs/purge",
+	"POST /api/v1/admin/bugs/restore-all",
+	"POST /api/v1/admin/companies/:id/verify",
+	"GET /api/v1/admin/dashboard",
+	"GET /api/v1/admin/dead-letters",
+	"DELETE /api/v1/admin/dead-letters/:id",
+	"POST /api/v1/admin/dead-letters/:id/retry",
+	"GET /api/v1/admin/ip-blocks",
+	"POST /api/v1/admin/ip-blocks",
+	"DELETE /api/v1/admin/ip-blocks/*ip",
+	"GET /api/v1/admin/moderation-queue",
+	"POST /api/v1/admin/rate-limits/exempt",
+	"GET /api/v1/admin/search-analytics",
+	"GET /api/v1/admin/security-events",
+	"GET /api/v1/admin/stats",
+	"POST /api/v1/admin/stats/refresh",
+	"GET /api/v1/admin/users",
+	"PATCH /api/v1/applications/:id",
+	"POST /api/v1/applications/:id/archive",
+	"POST /api/v1/applications/:id/unarchive",
+	"POST /api/v1/auth/logout",
+	"POST /api/v1/auth/logout-all",
+	"POST /api/v1/auth/oauth/link/:provider",
+	"GET /api/v1/auth/profile",
+	"PUT /api/v1/auth/profile",
+	"POST /api/v1/bugs/:id/attachments",
+	"DELETE /api/v1/bugs/:id/attachments/:attachment_id",
+	"POST /api/v1/bugs/:id/comments",
+	"POST /api/v1/bugs/:id/company-response",
+	"PATCH /api/v1/bugs/:id/priority",
+	"PATCH /api/v1/bugs/:id/status",
+	"POST /api/v1/bugs/:id/vote",
+	"POST /api/v1/companies/:id/assignment-rules",
+	"GET /api/v1/companies/:id/bugs",
+	"POST /api/v1/companies/:id/claim",
+	"GET /api/v1/companies/:id/dashboard",
+	"GET /api/v1/companies/:id/dashboard/export",
+	"POST /api/v1/companies/:id/domain-change",
+	"POST /api/v1/companies/:id/domain-change/confirm",
+	"POST /api/v1/companies/:id/invite-links",
+	"POST /api/v1/companies/:id/logo",
+	"POST /api/v1/companies/:id/members",
+	"DELETE /api/v1/companies/:id/members",
+	"PATCH /api/v1/companies/:id/members/:user_id/role",
+	"POST /api/v1/companies/:id/members/bulk",
+	"POST /api/v1/companies/:id/resend-verification",
+	"POST /api/v1/companies/:id/verify",
+	"POST /api/v1/invite/link",
+	"POST /api/v1/me/blocks",
+	"DELETE /api/v1/me/blocks/:user_id",
+	"GET /api/v1/me/bugs",
+	"POST /api/v1/me/change-password",
+	"GET /api/v1/me/notification-preferences",
+	"PATCH /api/v1/me/notification-preferences",
+}
+
+// adminEndpoints are the routes that require admin privileges
+var adminEndpoints = []string{
+	"GET /api/v1/admin/audit-logs",
+	"GET /api/v1/admin/audit-logs/:id",
+	"GET /api/v1/admin/bugs",
+	"DELETE /api/v1/admin/bugs/:id",
+	"POST /api/v1/admin/bugs/:id/flag",
+	"PATCH /api/v1/admin/bugs/:id/owner",
+	"POST /api/v1/admin/bugs/:id/rebuild-projection",
+	"POST /api/v1/admin/bugs/:id/restore",
+	"GET /api/v1/admin/bugs/deleted",
+	"POST /api/v1/admin/bugs/merge",
+	"DELETE /api/v1/admin/bugs/purge",
+	"POST /api/v1/admin/bugs/restore-all",
+	"POST /api/v1/admin/companies/:id/verify",
+	"GET /api/v1/admin/dashboard",
+	"GET /api/v1/admin/dead-letters",
+	"DELETE /api/v1/admin/dead-letters/:id",
+	"POST /api/v1/admin/dead-letters/:id/retry",
+	"GET /api/v1/admin/ip-blocks",
+	"POST /api/v1/admin/ip-blocks",
+	"DELETE /api/v1/admin/ip-blocks/*ip",
+	"GET /api/v1/admin/moderation-queue",
+	"POST /api/v1/admin/rate-limits/exempt",
+	"GET /api/v1/admin/search-analytics",
+	"GET /api/v1/admin/security-events",
+	"GET /api/v1/admin/stats",
+	"POST /api/v1/admin/stats/refresh",
+	"GET /api/v1/admin/users",
+}
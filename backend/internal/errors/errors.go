@@ -0,0 +1,89 @@
+// Package errors defines the error codes returned in API error responses. Every code
+// is registered so clients can look them up through GET /api/v1/error-codes instead of
+// hard-coding them.
+package errors
+
+import (
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorCode is an error code returned by the API together with its HTTP status.
+// A code string may be registered more than once when it is returned with different
+// statuses, such as MISSING_TOKEN from the auth middleware and from token parameters.
+type ErrorCode struct {
+	Code string `json:"code"`
+	HTTP int    `json:"http_status"`
+	Desc string `json:"description"`
+	// Endpoints lists the routes that can return the code as "METHOD /path"; a "*"
+	// method or path segment covers every route it matches
+	Endpoints []string `json:"endpoints,omitempty"`
+
+	message string
+	details interface{}
+}
+
+// registry holds every error code defined in this package
+var registry []ErrorCode
+
+// register adds an error code to the registry
+func register(code ErrorCode) ErrorCode {
+	registry = append(registry, code)
+	return code
+}
+
+// All returns every registered error code ordered by code and HTTP status
+func All() []ErrorCode {
+	codes := make([]ErrorCode, len(registry))
+	copy(codes, registry)
+	sort.Slice(codes, func(i, j int) bool {
+		if codes[i].Code != codes[j].Code {
+			return codes[i].Code < codes[j].Code
+		}
+		return codes[i].HTTP < codes[j].HTTP
+	})
+	return codes
+}
+
+// WithMessage returns a copy of the error code that responds with the given message
+// instead of the code's description
+func (e ErrorCode) WithMessage(message string) ErrorCode {
+	e.message = message
+	return e
+}
+
+// WithDetails returns a copy of the error code that includes details in its response
+func (e ErrorCode) WithDetails(details interface{}) ErrorCode {
+	e.details = details
+	return e
+}
+
+// ErrorResponse is the body of every API error response
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+// ErrorBody describes the error in an ErrorResponse
+type ErrorBody struct {
+	Code      string      `json:"code" example:"BUG_NOT_FOUND"`
+	Message   string      `json:"message" example:"Bug report not found"`
+	Details   interface{} `json:"details,omitempty"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// Response writes the standard error response for the code
+func (e ErrorCode) Response(c *gin.Context) {
+	message := e.message
+	if message == "" {
+		message = e.Desc
+	}
+
+	c.JSON(e.HTTP, ErrorResponse{Error: ErrorBody{
+		Code:      e.Code,
+		Message:   message,
+		Details:   e.details,
+		Timestamp: time.Now().UTC(),
+	}})
+}
@@ -0,0 +1,184 @@
+package errors
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// importPath is the import path of this package
+const importPath = "bugrelay-backend/internal/errors"
+
+// responseSourceDirs are the packages that write error responses
+var responseSourceDirs = []string{"../handlers", "../middleware"}
+
+// parseSources parses the non-test Go files in dir
+func parseSources(t *testing.T, dir string) (*token.FileSet, []*ast.File) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	require.NoError(t, err)
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		require.NoError(t, err)
+		files = append(files, file)
+	}
+	return fset, files
+}
+
+// registeredVars returns the names of the package variables defined with register
+func registeredVars(t *testing.T) map[string]bool {
+	_, files := parseSources(t, ".")
+
+	vars := map[string]bool{}
+	for _, file := range files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			spec, ok := n.(*ast.ValueSpec)
+			if !ok {
+				return true
+			}
+			for i, value := range spec.Values {
+				if call, ok := value.(*ast.CallExpr); ok {
+					if fn, ok := call.Fun.(*ast.Ident); ok && fn.Name == "register" {
+						vars[spec.Names[i].Name] = true
+					}
+				}
+			}
+			return true
+		})
+	}
+	return vars
+}
+
+// packageName returns the name a file imports this package under, if it does
+func packageName(file *ast.File) (string, bool) {
+	for _, imp := range file.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		if path != importPath {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name, true
+		}
+		return "errors", true
+	}
+	return "", false
+}
+
+func TestErrorCodes_AllUsedAndRegistered(t *testing.T) {
+	registered := registeredVars(t)
+	require.Len(t, registered, len(registry))
+
+	used := map[string]bool{}
+	for _, dir := range responseSourceDirs {
+		fset, files := parseSources(t, dir)
+		for _, file := range files {
+			name, imported := packageName(file)
+			ast.Inspect(file, func(n ast.Node) bool {
+				switch node := n.(type) {
+				case *ast.SelectorExpr:
+					if pkg, ok := node.X.(*ast.Ident); imported && ok && pkg.Name == name && strings.HasPrefix(node.Sel.Name, "Err") {
+						used[node.Sel.Name] = true
+					}
+				case *ast.KeyValueExpr:
+					// Error responses must go through a registered code rather than a
+					// hand-written gin.H{"error": gin.H{"code": ...}}
+					if key, ok := node.Key.(*ast.BasicLit); ok && key.Value == `"error"` {
+						if body, ok := node.Value.(*ast.CompositeLit); ok {
+							for _, elt := range body.Elts {
+								if kv, ok := elt.(*ast.KeyValueExpr); ok {
+									if lit, ok := kv.Key.(*ast.BasicLit); ok && lit.Value == `"code"` {
+										t.Errorf("%s: error code written without the errors package", fset.Position(node.Pos()))
+									}
+								}
+							}
+						}
+					}
+				}
+				return true
+			})
+		}
+	}
+
+	for name := range used {
+		assert.True(t, registered[name], "%s is used but not registered", name)
+	}
+	for name := range registered {
+		assert.True(t, used[name], "%s is registered but never used", name)
+	}
+}
+
+func TestErrorCodes_Registry(t *testing.T) {
+	codes := All()
+	require.NotEmpty(t, codes)
+
+	seen := map[string]bool{}
+	for _, code := range codes {
+		key := code.Code + " " + strconv.Itoa(code.HTTP)
+		assert.False(t, seen[key], "%s is registered twice", key)
+		seen[key] = true
+
+		assert.NotEmpty(t, code.Desc, code.Code)
+		assert.NotEmpty(t, http.StatusText(code.HTTP), "%s has an unknown status", code.Code)
+		assert.GreaterOrEqual(t, code.HTTP, 400, code.Code)
+		for _, endpoint := range code.Endpoints {
+			assert.Len(t, strings.Fields(endpoint), 2, "%s has a malformed endpoint %q", code.Code, endpoint)
+		}
+	}
+
+	for i := 1; i < len(codes); i++ {
+		assert.LessOrEqual(t, codes[i-1].Code, codes[i].Code)
+	}
+}
+
+func TestErrorCode_Response(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	respond := func(code ErrorCode) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		code.Response(c)
+
+		var body struct {
+			Error map[string]interface{} `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return w.Code, body.Error
+	}
+
+	t.Run("uses the description as the message", func(t *testing.T) {
+		status, body := respond(ErrBugNotFound)
+		assert.Equal(t, http.StatusNotFound, status)
+		assert.Equal(t, "BUG_NOT_FOUND", body["code"])
+		assert.Equal(t, ErrBugNotFound.Desc, body["message"])
+		assert.NotEmpty(t, body["timestamp"])
+		assert.NotContains(t, body, "details")
+	})
+
+	t.Run("message and details can be set per response", func(t *testing.T) {
+		status, body := respond(ErrInvalidID.WithMessage("Invalid bug ID format").WithDetails("bad uuid"))
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "INVALID_ID", body["code"])
+		assert.Equal(t, "Invalid bug ID format", body["message"])
+		assert.Equal(t, "bad uuid", body["details"])
+
+		// The registered code is left untouched
+		_, body = respond(ErrInvalidID)
+		assert.Equal(t, ErrInvalidID.Desc, body["message"])
+	})
+}
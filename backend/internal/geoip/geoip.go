@@ -0,0 +1,97 @@
+// Package geoip locates IP addresses in a local MaxMind GeoLite2 or GeoIP2 database.
+package geoip
+
+import (
+	"errors"
+	"io/fs"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/oschwald/geoip2-golang"
+)
+
+// Location is where an IP address is located. Fields are empty when the database
+// does not know them.
+type Location struct {
+	// CountryCode is the ISO 3166-1 alpha-2 code of the country
+	CountryCode string
+	// Region is the English name of the largest subdivision, such as a state or
+	// province. Country databases do not have regions.
+	Region string
+}
+
+// LookupFunc locates an IP address
+type LookupFunc func(ip net.IP) (Location, error)
+
+// Reader looks up locations in a City or Country database
+type Reader struct {
+	reader *geoip2.Reader
+	// hasCity reports whether the database has City records, with regions
+	hasCity bool
+}
+
+// Open opens the database at path. It returns a nil Reader and no error when path is
+// empty or there is no file at path, so locations are not looked up.
+func Open(path string) (*Reader, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	reader, err := geoip2.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{
+		reader:  reader,
+		hasCity: strings.Contains(reader.Metadata().DatabaseType, "City"),
+	}, nil
+}
+
+// Lookup returns the location of ip. Private, loopback, link-local and unspecified
+// addresses have an empty location.
+func (r *Reader) Lookup(ip net.IP) (Location, error) {
+	if !IsPublic(ip) {
+		return Location{}, nil
+	}
+
+	if !r.hasCity {
+		country, err := r.CountryCode(ip)
+		return Location{CountryCode: country}, err
+	}
+
+	record, err := r.reader.City(ip)
+	if err != nil {
+		return Location{}, err
+	}
+	location := Location{CountryCode: record.Country.IsoCode}
+	if len(record.Subdivisions) > 0 {
+		location.Region = record.Subdivisions[0].Names["en"]
+	}
+	return location, nil
+}
+
+// CountryCode returns the ISO country code of ip, or an empty string when the IP is
+// not in the database. It lets a Reader serve as the rate limiter's GeoIPLookup.
+func (r *Reader) CountryCode(ip net.IP) (string, error) {
+	record, err := r.reader.Country(ip)
+	if err != nil {
+		return "", err
+	}
+	return record.Country.IsoCode, nil
+}
+
+// Close closes the database
+func (r *Reader) Close() error {
+	return r.reader.Close()
+}
+
+// IsPublic reports whether ip can be located, i.e. it is not private, loopback,
+// link-local or unspecified
+func IsPublic(ip net.IP) bool {
+	return ip != nil && !ip.IsPrivate() && !ip.IsLoopback() && !ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() && !ip.IsUnspecified()
+}
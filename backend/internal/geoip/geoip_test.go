@@ -0,0 +1,49 @@
+package geoip
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen_MissingDatabase(t *testing.T) {
+	for _, path := range []string{"", filepath.Join(t.TempDir(), "GeoLite2-City.mmdb")} {
+		reader, err := Open(path)
+		assert.NoError(t, err, "path %q", path)
+		assert.Nil(t, reader, "path %q", path)
+	}
+}
+
+func TestOpen_InvalidDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "GeoLite2-City.mmdb")
+	require.NoError(t, os.WriteFile(path, []byte("not a maxmind database"), 0o644))
+
+	reader, err := Open(path)
+	assert.Error(t, err)
+	assert.Nil(t, reader)
+}
+
+func TestReader_LookupSkipsNonPublicIPs(t *testing.T) {
+	// Non-public addresses are not looked up, so no database is needed
+	reader := &Reader{}
+	for _, ip := range []string{"10.0.0.1", "192.168.1.10", "127.0.0.1", "::1", "fe80::1", "0.0.0.0"} {
+		location, err := reader.Lookup(net.ParseIP(ip))
+		assert.NoError(t, err, ip)
+		assert.Equal(t, Location{}, location, ip)
+	}
+
+	location, err := reader.Lookup(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, Location{}, location)
+}
+
+func TestIsPublic(t *testing.T) {
+	assert.True(t, IsPublic(net.ParseIP("8.8.8.8")))
+	assert.True(t, IsPublic(net.ParseIP("2001:4860:4860::8888")))
+	assert.False(t, IsPublic(net.ParseIP("172.16.0.1")))
+	assert.False(t, IsPublic(nil))
+}
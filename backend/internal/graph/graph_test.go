@@ -0,0 +1,470 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/handlers"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// graphResponse is a GraphQL response with the data left to each test to decode
+type graphResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// setupGraphTest creates a GraphQL handler backed by an in-memory SQLite database.
+// Requests are authenticated as the user whose ID follows "Bearer " in the
+// Authorization header, standing in for the auth middleware.
+func setupGraphTest(t *testing.T) (*Handler, *gin.Engine, *gorm.DB) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, autoMigrateSQLite(db,
+		&models.User{},
+		&models.Company{},
+		&models.CompanyMember{},
+		&models.Application{},
+		&models.BugReport{},
+		&models.BugVote{},
+		&models.Comment{},
+		&models.CommentEdit{},
+		&models.BugEditHistory{},
+		&models.FileAttachment{},
+		&models.AuditLog{},
+		&models.CustomFieldSchema{},
+		&models.BugAssignmentRule{},
+		&models.CompanyWebhook{},
+		&models.OutboxEvent{},
+		&models.UserBlock{},
+		&models.BugEvent{},
+		&models.Notification{},
+		&models.BugSubscription{},
+	))
+
+	graphHandler := NewHandler(db, handlers.NewBugHandler(db, nil), handlers.NewCompanyHandler(db, nil))
+
+	router := gin.New()
+	router.POST("/api/graphql", func(c *gin.Context) {
+		if userID := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer "); userID != "" {
+			c.Set("user_id", userID)
+		}
+		c.Next()
+	}, graphHandler.ServeGraphQL)
+
+	return graphHandler, router, db
+}
+
+// autoMigrateSQLite migrates models into a SQLite database, swapping the Postgres
+// column defaults SQLite can't parse for equivalents first. The parsed schemas are
+// cached per database, so other databases keep the Postgres defaults.
+func autoMigrateSQLite(db *gorm.DB, values ...interface{}) error {
+	for _, value := range values {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(value); err != nil {
+			return err
+		}
+		for _, field := range stmt.Schema.Fields {
+			switch field.DefaultValue {
+			case "uuid_generate_v4()":
+				field.DefaultValue = "(lower(hex(randomblob(16))))"
+			case "now()":
+				field.DefaultValue = "CURRENT_TIMESTAMP"
+			}
+		}
+	}
+	return db.AutoMigrate(values...)
+}
+
+// execute sends a GraphQL query as userID, anonymously when it is empty
+func execute(t *testing.T, router *gin.Engine, userID, query string, variables map[string]interface{}) graphResponse {
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	require.NoError(t, err)
+
+	req, _ := http.NewRequest("POST", "/api/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if userID != "" {
+		req.Header.Set("Authorization", "Bearer "+userID)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response graphResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response), w.Body.String())
+	return response
+}
+
+func createGraphBug(t *testing.T, db *gorm.DB, title string, application *models.Application, company *models.Company, createdAt time.Time) *models.BugReport {
+	bug := &models.BugReport{
+		ID:            uuid.New(),
+		Title:         title,
+		Description:   "The page goes blank after saving",
+		Status:        models.BugStatusOpen,
+		Priority:      models.BugPriorityMedium,
+		ApplicationID: application.ID,
+		CreatedAt:     createdAt,
+	}
+	if company != nil {
+		bug.AssignedCompanyID = &company.ID
+	}
+	require.NoError(t, db.Create(bug).Error)
+	return bug
+}
+
+func TestGraphQL_Bugs(t *testing.T) {
+	_, router, db := setupGraphTest(t)
+
+	web := &models.Application{ID: uuid.New(), Name: "Web App"}
+	mobile := &models.Application{ID: uuid.New(), Name: "Mobile App"}
+	require.NoError(t, db.Create(web).Error)
+	require.NoError(t, db.Create(mobile).Error)
+	company := &models.Company{ID: uuid.New(), Name: "Acme", Domain: "acme.com", IsVerified: true}
+	require.NoError(t, db.Create(company).Error)
+
+	now := time.Now()
+	createGraphBug(t, db, "Blank page on save", web, company, now.Add(-3*time.Hour))
+	createGraphBug(t, db, "Crash on launch", mobile, nil, now.Add(-2*time.Hour))
+	newest := createGraphBug(t, db, "Slow search", web, company, now.Add(-time.Hour))
+
+	// Count the application and company lookups made by the relationship fields
+	var applicationQueries, companyQueries atomic.Int32
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("count_lookups", func(tx *gorm.DB) {
+		switch tx.Statement.Table {
+		case "applications":
+			applicationQueries.Add(1)
+		case "companies":
+			companyQueries.Add(1)
+		}
+	}))
+
+	query := `query($filter: BugFilter, $limit: Int) {
+		bugs(filter: $filter, limit: $limit) {
+			nodes { id title tags application { name } company { name isVerified } }
+			pageInfo { page limit total totalPages hasNext hasPrev }
+		}
+	}`
+
+	var data struct {
+		Bugs struct {
+			Nodes []struct {
+				ID          string   `json:"id"`
+				Title       string   `json:"title"`
+				Tags        []string `json:"tags"`
+				Application struct {
+					Name string `json:"name"`
+				} `json:"application"`
+				Company *struct {
+					Name       string `json:"name"`
+					IsVerified bool   `json:"isVerified"`
+				} `json:"company"`
+			} `json:"nodes"`
+			PageInfo struct {
+				Page       int  `json:"page"`
+				Limit      int  `json:"limit"`
+				Total      int  `json:"total"`
+				TotalPages int  `json:"totalPages"`
+				HasNext    bool `json:"hasNext"`
+				HasPrev    bool `json:"hasPrev"`
+			} `json:"pageInfo"`
+		} `json:"bugs"`
+	}
+
+	t.Run("loads relationships with one query each", func(t *testing.T) {
+		response := execute(t, router, "", query, nil)
+		require.Empty(t, response.Errors)
+		require.NoError(t, json.Unmarshal(response.Data, &data))
+
+		nodes := data.Bugs.Nodes
+		require.Len(t, nodes, 3)
+		assert.Equal(t, newest.ID.String(), nodes[0].ID)
+		assert.Equal(t, "Web App", nodes[0].Application.Name)
+		require.NotNil(t, nodes[0].Company)
+		assert.Equal(t, "Acme", nodes[0].Company.Name)
+		assert.True(t, nodes[0].Company.IsVerified)
+		assert.Equal(t, "Mobile App", nodes[1].Application.Name)
+		assert.Nil(t, nodes[1].Company)
+		assert.Equal(t, []string{}, nodes[1].Tags)
+		assert.Equal(t, 3, data.Bugs.PageInfo.Total)
+
+		assert.Equal(t, int32(1), applicationQueries.Load())
+		assert.Equal(t, int32(1), companyQueries.Load())
+	})
+
+	t.Run("filters and paginates like the REST listing", func(t *testing.T) {
+		response := execute(t, router, "", query, map[string]interface{}{
+			"filter": map[string]interface{}{"application": "web", "sort": "OLDEST"},
+			"limit":  1,
+		})
+		require.Empty(t, response.Errors)
+		require.NoError(t, json.Unmarshal(response.Data, &data))
+
+		require.Len(t, data.Bugs.Nodes, 1)
+		assert.Equal(t, "Blank page on save", data.Bugs.Nodes[0].Title)
+		pageInfo := data.Bugs.PageInfo
+		assert.Equal(t, 1, pageInfo.Page)
+		assert.Equal(t, 1, pageInfo.Limit)
+		assert.Equal(t, 2, pageInfo.Total)
+		assert.Equal(t, 2, pageInfo.TotalPages)
+		assert.True(t, pageInfo.HasNext)
+		assert.False(t, pageInfo.HasPrev)
+	})
+
+	t.Run("returns a single bug", func(t *testing.T) {
+		var single struct {
+			Bug *struct {
+				Title       string `json:"title"`
+				Application struct {
+					Name string `json:"name"`
+				} `json:"application"`
+			} `json:"bug"`
+		}
+		bugQuery := `query($id: ID!) { bug(id: $id) { title application { name } } }`
+
+		response := execute(t, router, "", bugQuery, map[string]interface{}{"id": newest.ID.String()})
+		require.Empty(t, response.Errors)
+		require.NoError(t, json.Unmarshal(response.Data, &single))
+		require.NotNil(t, single.Bug)
+		assert.Equal(t, "Slow search", single.Bug.Title)
+		assert.Equal(t, "Web App", single.Bug.Application.Name)
+
+		response = execute(t, router, "", bugQuery, map[string]interface{}{"id": uuid.New().String()})
+		require.Empty(t, response.Errors)
+		require.NoError(t, json.Unmarshal(response.Data, &single))
+		assert.Nil(t, single.Bug)
+	})
+}
+
+func TestGraphQL_Companies(t *testing.T) {
+	_, router, db := setupGraphTest(t)
+
+	require.NoError(t, db.Create(&models.Company{ID: uuid.New(), Name: "Acme", Domain: "acme.com", IsVerified: true}).Error)
+	require.NoError(t, db.Create(&models.Company{ID: uuid.New(), Name: "Acme Labs", Domain: "acmelabs.io"}).Error)
+	require.NoError(t, db.Create(&models.Company{ID: uuid.New(), Name: "Globex", Domain: "globex.com", IsVerified: true}).Error)
+
+	response := execute(t, router, "", `{
+		companies(search: "acme", verified: true) { nodes { name domain } pageInfo { total } }
+	}`, nil)
+	require.Empty(t, response.Errors)
+
+	var data struct {
+		Companies struct {
+			Nodes []struct {
+				Name   string `json:"name"`
+				Domain string `json:"domain"`
+			} `json:"nodes"`
+			PageInfo struct {
+				Total int `json:"total"`
+			} `json:"pageInfo"`
+		} `json:"companies"`
+	}
+	require.NoError(t, json.Unmarshal(response.Data, &data))
+	require.Len(t, data.Companies.Nodes, 1)
+	assert.Equal(t, "acme.com", data.Companies.Nodes[0].Domain)
+	assert.Equal(t, 1, data.Companies.PageInfo.Total)
+}
+
+func TestGraphQL_CreateBug(t *testing.T) {
+	_, router, db := setupGraphTest(t)
+
+	mutation := `mutation($input: CreateBugInput!) {
+		createBug(input: $input) { id title priority tags application { name } }
+	}`
+
+	t.Run("creates the bug through the REST submission", func(t *testing.T) {
+		response := execute(t, router, "", mutation, map[string]interface{}{
+			"input": map[string]interface{}{
+				"title":           "Export button does nothing",
+				"description":     "Clicking export on the report page has no effect",
+				"priority":        "high",
+				"tags":            []string{"export"},
+				"applicationName": "Reports App",
+			},
+		})
+		require.Empty(t, response.Errors)
+
+		var data struct {
+			CreateBug struct {
+				ID          string `json:"id"`
+				Title       string `json:"title"`
+				Priority    string `json:"priority"`
+				Application struct {
+					Name string `json:"name"`
+				} `json:"application"`
+			} `json:"createBug"`
+		}
+		require.NoError(t, json.Unmarshal(response.Data, &data))
+		assert.Equal(t, "Export button does nothing", data.CreateBug.Title)
+		assert.Equal(t, models.BugPriorityHigh, data.CreateBug.Priority)
+		assert.Equal(t, "Reports App", data.CreateBug.Application.Name)
+
+		var bug models.BugReport
+		require.NoError(t, db.First(&bug, "id = ?", data.CreateBug.ID).Error)
+		assert.Equal(t, "Export button does nothing", bug.Title)
+	})
+
+	t.Run("returns REST validation errors with their code", func(t *testing.T) {
+		response := execute(t, router, "", mutation, map[string]interface{}{
+			"input": map[string]interface{}{
+				"title":           "Bad",
+				"description":     "Too short",
+				"applicationName": "Reports App",
+			},
+		})
+		require.Len(t, response.Errors, 1)
+		assert.Equal(t, errors.ErrValidationError.Code, response.Errors[0].Extensions["code"])
+		assert.Equal(t, float64(http.StatusBadRequest), response.Errors[0].Extensions["http_status"])
+		// createBug is non-null, so the error nulls the whole response
+		assert.Equal(t, "null", string(response.Data))
+	})
+}
+
+func TestGraphQL_VoteBug(t *testing.T) {
+	graphHandler, router, db := setupGraphTest(t)
+
+	application := &models.Application{ID: uuid.New(), Name: "Web App"}
+	require.NoError(t, db.Create(application).Error)
+	bug := createGraphBug(t, db, "Blank page on save", application, nil, time.Now())
+	voter := &models.User{ID: uuid.New(), Email: "voter@example.com", DisplayName: "Voter"}
+	require.NoError(t, db.Create(voter).Error)
+
+	mutation := `mutation($id: ID!) { voteBug(id: $id) { voted bug { voteCount } } }`
+	variables := map[string]interface{}{"id": bug.ID.String()}
+
+	var data struct {
+		VoteBug struct {
+			Voted bool `json:"voted"`
+			Bug   struct {
+				VoteCount int `json:"voteCount"`
+			} `json:"bug"`
+		} `json:"voteBug"`
+	}
+
+	t.Run("requires authentication", func(t *testing.T) {
+		response := execute(t, router, "", mutation, variables)
+		require.Len(t, response.Errors, 1)
+		assert.Equal(t, errors.ErrAuthRequired.Code, response.Errors[0].Extensions["code"])
+	})
+
+	t.Run("toggles the current user's vote", func(t *testing.T) {
+		response := execute(t, router, voter.ID.String(), mutation, variables)
+		require.Empty(t, response.Errors)
+		require.NoError(t, json.Unmarshal(response.Data, &data))
+		assert.True(t, data.VoteBug.Voted)
+		assert.Equal(t, 1, data.VoteBug.Bug.VoteCount)
+
+		response = execute(t, router, voter.ID.String(), mutation, variables)
+		require.Empty(t, response.Errors)
+		require.NoError(t, json.Unmarshal(response.Data, &data))
+		assert.False(t, data.VoteBug.Voted)
+		assert.Equal(t, 0, data.VoteBug.Bug.VoteCount)
+	})
+
+	t.Run("runs the vote middleware", func(t *testing.T) {
+		graphHandler.SetVoteBugMiddleware(func(c *gin.Context) {
+			errors.ErrRateLimitExceeded.Response(c)
+			c.Abort()
+		})
+		defer graphHandler.SetVoteBugMiddleware()
+
+		response := execute(t, router, voter.ID.String(), mutation, variables)
+		require.Len(t, response.Errors, 1)
+		assert.Equal(t, errors.ErrRateLimitExceeded.Code, response.Errors[0].Extensions["code"])
+
+		var count int64
+		require.NoError(t, db.Model(&models.BugVote{}).Where("bug_id = ?", bug.ID).Count(&count).Error)
+		assert.Zero(t, count)
+	})
+}
+
+func TestGraphQL_Limits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Limits are checked before anything is resolved, so no database is needed
+	graphHandler := NewHandler(nil, nil, nil)
+	router := gin.New()
+	router.POST("/api/graphql", graphHandler.ServeGraphQL)
+
+	send := func(query string) (int, graphResponse) {
+		body, err := json.Marshal(map[string]interface{}{"query": query})
+		require.NoError(t, err)
+
+		req, _ := http.NewRequest("POST", "/api/graphql", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response graphResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response), w.Body.String())
+		return w.Code, response
+	}
+
+	typeRef := "kind name"
+	for i := 0; i < 7; i++ {
+		typeRef = "kind name ofType { " + typeRef + " }"
+	}
+
+	t.Run("introspection is within the limits", func(t *testing.T) {
+		status, response := send("{ __schema { types { name fields { name type { " + typeRef + " } } } } }")
+		require.Equal(t, http.StatusOK, status)
+		assert.Empty(t, response.Errors)
+	})
+
+	t.Run("deeply nested operations are rejected", func(t *testing.T) {
+		deep := typeRef
+		for i := 0; i < 5; i++ {
+			deep = "kind name ofType { " + deep + " }"
+		}
+		status, response := send("{ __schema { types { fields { type { " + deep + " } } } } }")
+		assert.Equal(t, http.StatusOK, status)
+		require.Len(t, response.Errors, 1)
+		assert.Equal(t, "DEPTH_LIMIT_EXCEEDED", response.Errors[0].Extensions["code"])
+	})
+
+	t.Run("depth counts fields in fragments", func(t *testing.T) {
+		deep := typeRef
+		for i := 0; i < 5; i++ {
+			deep = "kind name ofType { " + deep + " }"
+		}
+		status, response := send("{ __schema { types { ...Fields } } } fragment Fields on __Type { fields { type { " + deep + " } } }")
+		assert.Equal(t, http.StatusOK, status)
+		require.Len(t, response.Errors, 1)
+		assert.Equal(t, "DEPTH_LIMIT_EXCEEDED", response.Errors[0].Extensions["code"])
+	})
+
+	t.Run("operations selecting too many fields are rejected", func(t *testing.T) {
+		var query strings.Builder
+		query.WriteString("{")
+		for i := 0; i < 200; i++ {
+			fmt.Fprintf(&query, " bug%d: bug(id: %q) { id title }", i, uuid.New())
+		}
+		query.WriteString(" }")
+
+		status, response := send(query.String())
+		assert.Equal(t, http.StatusOK, status)
+		require.Len(t, response.Errors, 1)
+		assert.Equal(t, "COMPLEXITY_LIMIT_EXCEEDED", response.Errors[0].Extensions["code"])
+	})
+}
@@ -0,0 +1,70 @@
+package graph
+
+import (
+	"context"
+
+	"bugrelay-backend/internal/handlers"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Handler serves GraphQL requests
+type Handler struct {
+	db       *gorm.DB
+	resolver *Resolver
+	server   *handler.Server
+}
+
+// NewHandler creates a new GraphQL handler resolving bugs and companies with the
+// given REST handlers. Operations over maxQueryComplexity or maxQueryDepth are
+// rejected before they are resolved.
+func NewHandler(db *gorm.DB, bugs *handlers.BugHandler, companies *handlers.CompanyHandler) *Handler {
+	resolver := &Resolver{bugs: bugs, companies: companies}
+
+	server := handler.New(NewExecutableSchema(Config{Resolvers: resolver}))
+	server.AddTransport(transport.POST{})
+	server.Use(extension.Introspection{})
+	server.Use(extension.FixedComplexityLimit(maxQueryComplexity))
+	server.Use(depthLimit{max: maxQueryDepth})
+
+	return &Handler{db: db, resolver: resolver, server: server}
+}
+
+// SetCreateBugMiddleware sets the middleware run before a createBug mutation, such
+// as the rate limits of the REST bug submission route
+func (h *Handler) SetCreateBugMiddleware(middleware ...gin.HandlerFunc) {
+	h.resolver.createBugMiddleware = middleware
+}
+
+// SetVoteBugMiddleware sets the middleware run before a voteBug mutation, such as
+// the rate limits of the REST vote route
+func (h *Handler) SetVoteBugMiddleware(middleware ...gin.HandlerFunc) {
+	h.resolver.voteBugMiddleware = middleware
+}
+
+// ServeGraphQL handles a GraphQL request. The current user is the one identified by
+// the Authorization header through the auth middleware run before it.
+func (h *Handler) ServeGraphQL(c *gin.Context) {
+	ctx := withGinContext(c.Request.Context(), c)
+	ctx = withLoaders(ctx, newLoaders(ctx, h.db))
+
+	h.server.ServeHTTP(c.Writer, c.Request.WithContext(ctx))
+}
+
+// ginContextKey is the context key of the gin context a GraphQL request is served on
+type ginContextKey struct{}
+
+// withGinContext returns ctx carrying the gin context c
+func withGinContext(ctx context.Context, c *gin.Context) context.Context {
+	return context.WithValue(ctx, ginContextKey{}, c)
+}
+
+// ginContext returns the gin context the GraphQL request in ctx is served on
+func ginContext(ctx context.Context) *gin.Context {
+	c, _ := ctx.Value(ginContextKey{}).(*gin.Context)
+	return c
+}
@@ -0,0 +1,77 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	// maxQueryComplexity is the most fields an operation may select, counting each
+	// field once per alias
+	maxQueryComplexity = 300
+
+	// maxQueryDepth is how deeply an operation may nest selections. The schema's own
+	// fields nest at most four deep; the standard introspection query nests twelve.
+	maxQueryDepth = 15
+)
+
+// errDepthLimit is the extension code of operations rejected by depthLimit, alongside
+// gqlgen's COMPLEXITY_LIMIT_EXCEEDED
+const errDepthLimit = "DEPTH_LIMIT_EXCEEDED"
+
+// depthLimit is a gqlgen extension that rejects operations nesting selections more
+// than max deep, reported like the complexity limit
+type depthLimit struct {
+	max int
+}
+
+var _ interface {
+	graphql.OperationContextMutator
+	graphql.HandlerExtension
+} = depthLimit{}
+
+func (d depthLimit) ExtensionName() string {
+	return "DepthLimit"
+}
+
+func (d depthLimit) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (d depthLimit) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	if opCtx.Operation == nil {
+		return nil
+	}
+
+	if depth := selectionDepth(opCtx.Operation.SelectionSet); depth > d.max {
+		err := gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", depth, d.max)
+		errcode.Set(err, errDepthLimit)
+		return err
+	}
+	return nil
+}
+
+// selectionDepth returns how deeply fields nest in set, following fragments.
+// Validation has already rejected fragment cycles.
+func selectionDepth(set ast.SelectionSet) int {
+	depth := 0
+	for _, selection := range set {
+		var nested int
+		switch s := selection.(type) {
+		case *ast.Field:
+			nested = 1 + selectionDepth(s.SelectionSet)
+		case *ast.InlineFragment:
+			nested = selectionDepth(s.SelectionSet)
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				nested = selectionDepth(s.Definition.SelectionSet)
+			}
+		}
+		depth = max(depth, nested)
+	}
+	return depth
+}
@@ -0,0 +1,132 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// loaderWait is how long a loader collects IDs before fetching them. Fields of the
+// bugs in a list resolve concurrently, so this is enough to load the applications
+// or companies of a whole page with one query.
+const loaderWait = 2 * time.Millisecond
+
+// loaders batch the relationship lookups of one GraphQL request
+type loaders struct {
+	applications *loader[models.Application]
+	companies    *loader[models.Company]
+}
+
+// newLoaders creates the loaders for a request served with ctx
+func newLoaders(ctx context.Context, db *gorm.DB) *loaders {
+	return &loaders{
+		applications: newLoader(ctx, func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]models.Application, error) {
+			var applications []models.Application
+			if err := db.WithContext(ctx).Where("id IN ?", ids).Find(&applications).Error; err != nil {
+				return nil, err
+			}
+
+			byID := make(map[uuid.UUID]models.Application, len(applications))
+			for _, application := range applications {
+				byID[application.ID] = application
+			}
+			return byID, nil
+		}),
+		companies: newLoader(ctx, func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]models.Company, error) {
+			var companies []models.Company
+			if err := db.WithContext(ctx).Where("id IN ?", ids).Find(&companies).Error; err != nil {
+				return nil, err
+			}
+
+			byID := make(map[uuid.UUID]models.Company, len(companies))
+			for _, company := range companies {
+				byID[company.ID] = company
+			}
+			return byID, nil
+		}),
+	}
+}
+
+// loadersKey is the context key of a request's loaders
+type loadersKey struct{}
+
+// withLoaders returns ctx carrying the loaders l
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+// loadersFromContext returns the loaders of the request served with ctx
+func loadersFromContext(ctx context.Context) *loaders {
+	l, _ := ctx.Value(loadersKey{}).(*loaders)
+	return l
+}
+
+// loader batches lookups by ID made within loaderWait of each other into one fetch,
+// and remembers the results for the rest of the request
+type loader[V any] struct {
+	ctx   context.Context
+	fetch func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]V, error)
+
+	mu      sync.Mutex
+	results map[uuid.UUID]*loadResult[V]
+	pending []uuid.UUID
+}
+
+// loadResult is the result of loading one ID, available once done is closed
+type loadResult[V any] struct {
+	done  chan struct{}
+	value V
+	found bool
+	err   error
+}
+
+// newLoader creates a loader fetching with fetch, which returns the values found
+func newLoader[V any](ctx context.Context, fetch func(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]V, error)) *loader[V] {
+	return &loader[V]{
+		ctx:     ctx,
+		fetch:   fetch,
+		results: make(map[uuid.UUID]*loadResult[V]),
+	}
+}
+
+// load returns the value with the given ID, and whether it was found
+func (l *loader[V]) load(id uuid.UUID) (V, bool, error) {
+	l.mu.Lock()
+	result, ok := l.results[id]
+	if !ok {
+		result = &loadResult[V]{done: make(chan struct{})}
+		l.results[id] = result
+		l.pending = append(l.pending, id)
+		if len(l.pending) == 1 {
+			time.AfterFunc(loaderWait, l.dispatch)
+		}
+	}
+	l.mu.Unlock()
+
+	<-result.done
+	return result.value, result.found, result.err
+}
+
+// dispatch fetches the pending IDs and completes their results
+func (l *loader[V]) dispatch() {
+	l.mu.Lock()
+	ids := l.pending
+	l.pending = nil
+	results := make([]*loadResult[V], len(ids))
+	for i, id := range ids {
+		results[i] = l.results[id]
+	}
+	l.mu.Unlock()
+
+	values, err := l.fetch(l.ctx, ids)
+	for i, id := range ids {
+		results[i].value, results[i].found = values[id]
+		results[i].err = err
+		close(results[i].done)
+	}
+}
@@ -0,0 +1,134 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package model
+
+import (
+	"bugrelay-backend/internal/models"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+type BugConnection struct {
+	Nodes    []models.BugReport `json:"nodes"`
+	PageInfo *PageInfo          `json:"pageInfo"`
+}
+
+type BugFilter struct {
+	Search   *string `json:"search,omitempty"`
+	Status   *string `json:"status,omitempty"`
+	Priority *string `json:"priority,omitempty"`
+	// Bugs must have all of the tags
+	Tags []string `json:"tags,omitempty"`
+	// Matched against the application name
+	Application   *string    `json:"application,omitempty"`
+	ApplicationID *uuid.UUID `json:"applicationId,omitempty"`
+	// Matched against the assigned company name
+	Company *string `json:"company,omitempty"`
+	// One of linux, windows, macos, ios or android
+	Os              *string  `json:"os,omitempty"`
+	Sort            *BugSort `json:"sort,omitempty"`
+	BoostByPriority *bool    `json:"boostByPriority,omitempty"`
+}
+
+type CompanyConnection struct {
+	Nodes    []models.Company `json:"nodes"`
+	PageInfo *PageInfo        `json:"pageInfo"`
+}
+
+type CreateBugInput struct {
+	Title           string   `json:"title"`
+	Description     string   `json:"description"`
+	Priority        *string  `json:"priority,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+	ApplicationName string   `json:"applicationName"`
+	ApplicationURL  *string  `json:"applicationUrl,omitempty"`
+	ContactEmail    *string  `json:"contactEmail,omitempty"`
+	ForceCreate     *bool    `json:"forceCreate,omitempty"`
+	RecaptchaToken  *string  `json:"recaptchaToken,omitempty"`
+}
+
+type Mutation struct {
+}
+
+type PageInfo struct {
+	Page       int  `json:"page"`
+	Limit      int  `json:"limit"`
+	Total      int  `json:"total"`
+	TotalPages int  `json:"totalPages"`
+	HasNext    bool `json:"hasNext"`
+	HasPrev    bool `json:"hasPrev"`
+}
+
+type Query struct {
+}
+
+type VoteResult struct {
+	// Whether the current user now has a vote on the bug
+	Voted bool `json:"voted"`
+	// The weight the vote was cast with, null when a vote was removed
+	VoteWeight *float64          `json:"voteWeight,omitempty"`
+	Bug        *models.BugReport `json:"bug"`
+}
+
+type BugSort string
+
+const (
+	BugSortRecent   BugSort = "RECENT"
+	BugSortPopular  BugSort = "POPULAR"
+	BugSortTrending BugSort = "TRENDING"
+	BugSortOldest   BugSort = "OLDEST"
+)
+
+var AllBugSort = []BugSort{
+	BugSortRecent,
+	BugSortPopular,
+	BugSortTrending,
+	BugSortOldest,
+}
+
+func (e BugSort) IsValid() bool {
+	switch e {
+	case BugSortRecent, BugSortPopular, BugSortTrending, BugSortOldest:
+		return true
+	}
+	return false
+}
+
+func (e BugSort) String() string {
+	return string(e)
+}
+
+func (e *BugSort) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = BugSort(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid BugSort", str)
+	}
+	return nil
+}
+
+func (e BugSort) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *BugSort) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e BugSort) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
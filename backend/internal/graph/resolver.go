@@ -0,0 +1,86 @@
+// Package graph serves the GraphQL API at POST /api/graphql, an alternative to the
+// REST bug and company listings for clients that want to choose their fields.
+// Queries reuse the REST handlers' queries and cache lookups, and mutations run the
+// REST handlers themselves.
+package graph
+
+//go:generate go tool gqlgen generate
+
+import (
+	"strings"
+
+	"bugrelay-backend/internal/graph/model"
+	"bugrelay-backend/internal/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Resolver resolves the queries and mutations in schema.graphqls
+type Resolver struct {
+	bugs      *handlers.BugHandler
+	companies *handlers.CompanyHandler
+
+	createBugMiddleware []gin.HandlerFunc
+	voteBugMiddleware   []gin.HandlerFunc
+}
+
+// bugListQuery returns the bug listing query for a bugs filter
+func bugListQuery(filter *model.BugFilter) handlers.BugListQuery {
+	query := handlers.BugListQuery{Sort: "recent", BoostByPriority: true}
+	if filter == nil {
+		return query
+	}
+
+	query.BugQueryOptions = handlers.BugQueryOptions{
+		Status:        stringValue(filter.Status),
+		Priority:      stringValue(filter.Priority),
+		Tags:          strings.Join(filter.Tags, ","),
+		Application:   stringValue(filter.Application),
+		ApplicationID: filter.ApplicationID,
+		Company:       stringValue(filter.Company),
+		OS:            stringValue(filter.Os),
+		Search:        stringValue(filter.Search),
+	}
+	if filter.Sort != nil {
+		query.Sort = strings.ToLower(string(*filter.Sort))
+	}
+	query.BoostByPriority = boolValue(filter.BoostByPriority, true)
+	return query
+}
+
+// newPageInfo returns the page info of a page from a listing of total items
+func newPageInfo(page, limit int, total int64) *model.PageInfo {
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	return &model.PageInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      int(total),
+		TotalPages: totalPages,
+		HasNext:    page < totalPages,
+		HasPrev:    page > 1,
+	}
+}
+
+// stringValue returns the string s points to, or "" when it is nil
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// intValue returns the int i points to, or 0 when it is nil
+func intValue(i *int) int {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
+
+// boolValue returns the bool b points to, or def when it is nil
+func boolValue(b *bool, def bool) bool {
+	if b == nil {
+		return def
+	}
+	return *b
+}
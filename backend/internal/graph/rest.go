@@ -0,0 +1,146 @@
+package graph
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+
+	"bugrelay-backend/internal/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// runREST runs a REST route's middleware and handler on a copy of the GraphQL
+// request's gin context, with params as the path parameters and body as the JSON
+// request body. Mutations go through the REST handlers this way so submissions are
+// validated, rate limited and announced exactly as on the REST API. A successful
+// response is decoded into out, and an error response is returned as a GraphQL
+// error with the same code.
+func runREST(ctx context.Context, chain []gin.HandlerFunc, params gin.Params, body interface{}, out interface{}) error {
+	c := ginContext(ctx)
+	if c == nil {
+		return fmt.Errorf("graph: request has no gin context")
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	request := c.Request.Clone(ctx)
+	request.Method = http.MethodPost
+	request.Body = io.NopCloser(bytes.NewReader(payload))
+	request.ContentLength = int64(len(payload))
+	request.Header.Set("Content-Type", "application/json")
+
+	response := newResponseBuffer()
+	rc := c.Copy()
+	rc.Request = request
+	rc.Params = params
+	rc.Writer = response
+
+	// A middleware that rejects the request writes its error response
+	for _, handler := range chain {
+		handler(rc)
+		if response.Written() {
+			break
+		}
+	}
+
+	if response.Status() >= http.StatusBadRequest {
+		var errorResponse struct {
+			Error struct {
+				Code    string      `json:"code"`
+				Message string      `json:"message"`
+				Details interface{} `json:"details"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(response.body.Bytes(), &errorResponse); err != nil || errorResponse.Error.Code == "" {
+			return fmt.Errorf("graph: unexpected %d response", response.Status())
+		}
+		return restError(errorResponse.Error.Code, errorResponse.Error.Message, response.Status(), errorResponse.Error.Details)
+	}
+
+	return json.Unmarshal(response.body.Bytes(), out)
+}
+
+// apiError returns a GraphQL error for an API error code
+func apiError(code errors.ErrorCode, message string) error {
+	if message == "" {
+		message = code.Desc
+	}
+	return restError(code.Code, message, code.HTTP, nil)
+}
+
+// queryFailed returns the QUERY_FAILED error with the given message
+func queryFailed(message string) error {
+	return apiError(errors.ErrQueryFailed, message)
+}
+
+// applicationNotFound returns the APPLICATION_NOT_FOUND error
+func applicationNotFound() error {
+	return apiError(errors.ErrApplicationNotFound, "")
+}
+
+// restError returns a GraphQL error carrying an API error code, its HTTP status and
+// details in its extensions, so clients can handle it as they would the REST error
+func restError(code, message string, status int, details interface{}) error {
+	extensions := map[string]interface{}{
+		"code":        code,
+		"http_status": status,
+	}
+	if details != nil {
+		extensions["details"] = details
+	}
+	return &gqlerror.Error{Message: message, Extensions: extensions}
+}
+
+// responseBuffer is a gin.ResponseWriter that keeps a REST handler's response in
+// memory
+type responseBuffer struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+// newResponseBuffer creates an empty response buffer
+func newResponseBuffer() *responseBuffer {
+	return &responseBuffer{header: make(http.Header), status: http.StatusOK}
+}
+
+func (r *responseBuffer) Header() http.Header { return r.header }
+
+func (r *responseBuffer) WriteHeader(status int) {
+	if !r.Written() {
+		r.status = status
+	}
+}
+
+func (r *responseBuffer) WriteHeaderNow() {}
+
+func (r *responseBuffer) Write(data []byte) (int, error) { return r.body.Write(data) }
+
+func (r *responseBuffer) WriteString(s string) (int, error) { return r.body.WriteString(s) }
+
+func (r *responseBuffer) Status() int { return r.status }
+
+func (r *responseBuffer) Size() int { return r.body.Len() }
+
+// Written reports whether a response body was written
+func (r *responseBuffer) Written() bool { return r.body.Len() > 0 }
+
+func (r *responseBuffer) Flush() {}
+
+func (r *responseBuffer) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, fmt.Errorf("graph: response buffer cannot be hijacked")
+}
+
+func (r *responseBuffer) CloseNotify() <-chan bool { return make(chan bool) }
+
+func (r *responseBuffer) Pusher() http.Pusher { return nil }
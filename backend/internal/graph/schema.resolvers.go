@@ -0,0 +1,185 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.81
+
+import (
+	"bugrelay-backend/internal/graph/model"
+	"bugrelay-backend/internal/handlers"
+	"bugrelay-backend/internal/models"
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Tags is the resolver for the tags field.
+func (r *bugResolver) Tags(ctx context.Context, obj *models.BugReport) ([]string, error) {
+	if obj.Tags == nil {
+		return []string{}, nil
+	}
+	return obj.Tags, nil
+}
+
+// Application is the resolver for the application field.
+func (r *bugResolver) Application(ctx context.Context, obj *models.BugReport) (*models.Application, error) {
+	// Bugs returned by createBug come with their application loaded
+	if obj.Application.ID != uuid.Nil {
+		return &obj.Application, nil
+	}
+
+	application, found, err := loadersFromContext(ctx).applications.load(obj.ApplicationID)
+	if err != nil {
+		return nil, queryFailed("Failed to fetch application")
+	}
+	if !found {
+		return nil, applicationNotFound()
+	}
+	return &application, nil
+}
+
+// Company is the resolver for the company field.
+func (r *bugResolver) Company(ctx context.Context, obj *models.BugReport) (*models.Company, error) {
+	if obj.AssignedCompanyID == nil {
+		return nil, nil
+	}
+	if obj.AssignedCompany != nil {
+		return obj.AssignedCompany, nil
+	}
+
+	company, found, err := loadersFromContext(ctx).companies.load(*obj.AssignedCompanyID)
+	if err != nil {
+		return nil, queryFailed("Failed to fetch company")
+	}
+	if !found {
+		return nil, nil
+	}
+	return &company, nil
+}
+
+// CreateBug is the resolver for the createBug field.
+func (r *mutationResolver) CreateBug(ctx context.Context, input model.CreateBugInput) (*models.BugReport, error) {
+	request := handlers.CreateBugRequest{
+		Title:           input.Title,
+		Description:     input.Description,
+		Priority:        stringValue(input.Priority),
+		Tags:            input.Tags,
+		ApplicationName: input.ApplicationName,
+		ApplicationURL:  input.ApplicationURL,
+		ContactEmail:    input.ContactEmail,
+		ForceCreate:     boolValue(input.ForceCreate, false),
+		RecaptchaToken:  input.RecaptchaToken,
+	}
+
+	var response struct {
+		Bug models.BugReport `json:"bug"`
+		handlers.PossibleDuplicateResponse
+	}
+	chain := append(append([]gin.HandlerFunc{}, r.createBugMiddleware...), r.bugs.CreateBug)
+	if err := runREST(ctx, chain, nil, request, &response); err != nil {
+		return nil, err
+	}
+
+	// A submission similar to open bugs is not created, and has no bug to return
+	if response.SuggestedDuplicates != nil {
+		return nil, restError(response.Code, response.Message, http.StatusOK, map[string]interface{}{
+			"suggested_duplicates": response.SuggestedDuplicates,
+		})
+	}
+	return &response.Bug, nil
+}
+
+// VoteBug is the resolver for the voteBug field.
+func (r *mutationResolver) VoteBug(ctx context.Context, id uuid.UUID) (*model.VoteResult, error) {
+	var response struct {
+		Voted      bool     `json:"voted"`
+		VoteWeight *float64 `json:"vote_weight"`
+	}
+	chain := append(append([]gin.HandlerFunc{}, r.voteBugMiddleware...), r.bugs.VoteBug)
+	if err := runREST(ctx, chain, gin.Params{{Key: "id", Value: id.String()}}, nil, &response); err != nil {
+		return nil, err
+	}
+
+	// The vote invalidated the cached bug, so this reads the new vote count
+	bug, err := r.bugs.FindBug(ctx, id)
+	if err != nil {
+		return nil, queryFailed("Failed to fetch bug report")
+	}
+
+	return &model.VoteResult{
+		Voted:      response.Voted,
+		VoteWeight: response.VoteWeight,
+		Bug:        &bug,
+	}, nil
+}
+
+// Bugs is the resolver for the bugs field.
+func (r *queryResolver) Bugs(ctx context.Context, filter *model.BugFilter, page *int, limit *int) (*model.BugConnection, error) {
+	query := bugListQuery(filter)
+	query.Page = intValue(page)
+	query.Limit = intValue(limit)
+
+	result, err := r.bugs.FindBugs(ctx, query)
+	if err != nil {
+		return nil, queryFailed("Failed to fetch bug reports")
+	}
+
+	nodes := result.Bugs
+	if nodes == nil {
+		nodes = []models.BugReport{}
+	}
+	return &model.BugConnection{
+		Nodes:    nodes,
+		PageInfo: newPageInfo(result.Page, result.Limit, result.Total),
+	}, nil
+}
+
+// Bug is the resolver for the bug field.
+func (r *queryResolver) Bug(ctx context.Context, id uuid.UUID) (*models.BugReport, error) {
+	bug, err := r.bugs.FindBug(ctx, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, queryFailed("Failed to fetch bug report")
+	}
+	return &bug, nil
+}
+
+// Companies is the resolver for the companies field.
+func (r *queryResolver) Companies(ctx context.Context, search *string, verified *bool, page *int, limit *int) (*model.CompanyConnection, error) {
+	result, err := r.companies.FindCompanies(ctx, handlers.ListCompaniesRequest{
+		Page:     intValue(page),
+		Limit:    intValue(limit),
+		Search:   stringValue(search),
+		Verified: verified,
+	})
+	if err != nil {
+		return nil, queryFailed("Failed to fetch companies")
+	}
+
+	nodes := result.Companies
+	if nodes == nil {
+		nodes = []models.Company{}
+	}
+	return &model.CompanyConnection{
+		Nodes:    nodes,
+		PageInfo: newPageInfo(result.Page, result.Limit, result.Total),
+	}, nil
+}
+
+// Bug returns BugResolver implementation.
+func (r *Resolver) Bug() BugResolver { return &bugResolver{r} }
+
+// Mutation returns MutationResolver implementation.
+func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+type bugResolver struct{ *Resolver }
+type mutationResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
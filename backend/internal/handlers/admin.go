@@ -1,61 +1,104 @@
 package handlers
 
 import (
+	"context"
+	"encoding/csv"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"bugrelay-backend/internal/cache"
+	"bugrelay-backend/internal/database"
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/jobs"
+	"bugrelay-backend/internal/logger"
 	"bugrelay-backend/internal/middleware"
 	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/storage"
+	"bugrelay-backend/internal/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/errgroup"
 	"gorm.io/gorm"
 )
 
 // AdminHandler handles admin-related HTTP requests
 type AdminHandler struct {
-	db *gorm.DB
+	db                       *gorm.DB
+	cache                    *cache.CacheService
+	rateLimiter              *middleware.RateLimiter
+	ipBlocker                *middleware.IPBlocker
+	projector                *jobs.BugProjector
+	storage                  storage.Backend
+	parallelDashboardQueries bool
+	spamScoreThreshold       float64
+	pagination               PaginationConfig
 }
 
 // NewAdminHandler creates a new admin handler
-func NewAdminHandler(db *gorm.DB) *AdminHandler {
+func NewAdminHandler(db *gorm.DB, redisClient *redis.Client) *AdminHandler {
 	return &AdminHandler{
-		db: db,
+		db:                 db,
+		cache:              cache.NewCacheService(redisClient),
+		projector:          jobs.NewBugProjector(db),
+		storage:            storage.NewLocalBackend(storage.DefaultLocalDir),
+		spamScoreThreshold: defaultSpamScoreThreshold,
+		pagination:         PaginationConfig{DefaultLimit: DefaultPageLimit, MaxLimit: defaultAdminListMaxLimit},
 	}
 }
 
-// logAuditAction logs an administrative action to the audit log
-func (h *AdminHandler) logAuditAction(c *gin.Context, action, resource string, resourceID *uuid.UUID, details string) error {
-	userIDStr, exists := middleware.GetCurrentUserID(c)
-	if !exists {
-		return fmt.Errorf("user ID not found in context")
-	}
+// SetPagination sets the page sizes of admin listings
+func (h *AdminHandler) SetPagination(pagination PaginationConfig) {
+	h.pagination = pagination
+}
 
-	userUUID, err := uuid.Parse(userIDStr)
-	if err != nil {
-		return fmt.Errorf("invalid user ID: %v", err)
-	}
+// SetBugProjector sets the projector used to rebuild bug reports from their events
+func (h *AdminHandler) SetBugProjector(projector *jobs.BugProjector) {
+	h.projector = projector
+}
+
+// SetStorage sets the backend attachment files of purged bugs are deleted from
+func (h *AdminHandler) SetStorage(backend storage.Backend) {
+	h.storage = backend
+}
 
-	// Get IP address and user agent
-	ipAddress := c.ClientIP()
-	userAgent := c.GetHeader("User-Agent")
+// SetSpamScoreThreshold sets the spam score at which bugs count as spam
+func (h *AdminHandler) SetSpamScoreThreshold(threshold float64) {
+	h.spamScoreThreshold = threshold
+}
 
-	auditLog := models.AuditLog{
-		Action:     action,
-		Resource:   resource,
-		ResourceID: resourceID,
-		Details:    details,
-		UserID:     userUUID,
-		IPAddress:  &ipAddress,
-		UserAgent:  &userAgent,
-	}
+// SetParallelDashboardQueries enables running dashboard count queries concurrently
+func (h *AdminHandler) SetParallelDashboardQueries(enabled bool) {
+	h.parallelDashboardQueries = enabled
+}
+
+// SetRateLimiter sets the rate limiter used to grant temporary exemptions
+func (h *AdminHandler) SetRateLimiter(rateLimiter *middleware.RateLimiter) {
+	h.rateLimiter = rateLimiter
+}
 
-	return h.db.Create(&auditLog).Error
+// logAuditAction logs an administrative action to the audit log, along with optional
+// snapshots of the resource before and after the action
+func (h *AdminHandler) logAuditAction(c *gin.Context, action, resource string, resourceID *uuid.UUID, details string, before, after interface{}) error {
+	return createAuditLog(h.db, c, action, resource, resourceID, details, before, after)
 }
 
 // GetAdminDashboard returns admin dashboard statistics
+//
+// @Summary     Get the admin dashboard
+// @Description Returns counts of users, bugs, companies and recent activity for the admin dashboard.
+// @Tags        admin
+// @Produce     json
+// @Security    BearerAuth
+// @Success     200 {object} object{stats=object}
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /admin/dashboard [get]
 func (h *AdminHandler) GetAdminDashboard(c *gin.Context) {
 	// Get various statistics for the admin dashboard
 	var stats struct {
@@ -68,16 +111,33 @@ func (h *AdminHandler) GetAdminDashboard(c *gin.Context) {
 		RecentActivity []models.AuditLog `json:"recent_activity"`
 	}
 
-	// Count bugs
-	h.db.Model(&models.BugReport{}).Count(&stats.TotalBugs)
-	h.db.Model(&models.BugReport{}).Where("status = ?", models.BugStatusOpen).Count(&stats.OpenBugs)
-	
-	// Count users
-	h.db.Model(&models.User{}).Count(&stats.TotalUsers)
-	
-	// Count companies
-	h.db.Model(&models.Company{}).Count(&stats.TotalCompanies)
-	h.db.Model(&models.Company{}).Where("is_verified = ?", true).Count(&stats.VerifiedCompanies)
+	counts := []dashboardCount{
+		// Count bugs
+		{func(db *gorm.DB) *gorm.DB { return db.Model(&models.BugReport{}) }, &stats.TotalBugs},
+		{func(db *gorm.DB) *gorm.DB {
+			return db.Model(&models.BugReport{}).Where("status = ?", models.BugStatusOpen)
+		}, &stats.OpenBugs},
+
+		// Count users
+		{func(db *gorm.DB) *gorm.DB { return db.Model(&models.User{}) }, &stats.TotalUsers},
+
+		// Count companies
+		{func(db *gorm.DB) *gorm.DB { return db.Model(&models.Company{}) }, &stats.TotalCompanies},
+		{func(db *gorm.DB) *gorm.DB {
+			return db.Model(&models.Company{}).Where("is_verified = ?", true)
+		}, &stats.VerifiedCompanies},
+	}
+
+	if h.parallelDashboardQueries {
+		if err := h.runDashboardCountsParallel(c.Request.Context(), counts); err != nil {
+			errors.ErrQueryFailed.WithMessage("Failed to fetch dashboard statistics").Response(c)
+			return
+		}
+	} else {
+		for _, count := range counts {
+			count.query(h.db).Count(count.dest)
+		}
+	}
 
 	// Get recent audit activity (last 50 entries)
 	h.db.Preload("User").
@@ -90,19 +150,55 @@ func (h *AdminHandler) GetAdminDashboard(c *gin.Context) {
 	})
 }
 
+// dashboardCount pairs a dashboard count query with its destination
+type dashboardCount struct {
+	query func(db *gorm.DB) *gorm.DB
+	dest  *int64
+}
+
+// runDashboardCountsParallel runs each count query in its own goroutine.
+// The first failure cancels the remaining queries.
+func (h *AdminHandler) runDashboardCountsParallel(ctx context.Context, counts []dashboardCount) error {
+	g, gctx := errgroup.WithContext(ctx)
+	for _, count := range counts {
+		count := count
+		g.Go(func() error {
+			return count.query(h.db.WithContext(gctx)).Count(count.dest).Error
+		})
+	}
+	return g.Wait()
+}
+
 // ListBugsForModeration returns bugs that need moderation
+//
+// @Summary     List bugs for moderation
+// @Description Lists bug reports for review, optionally limited to a status, flagged or spam reports, or reports that skipped the duplicate check.
+// @Tags        admin
+// @Produce     json
+// @Security    BearerAuth
+// @Param       page query int false "Page number" default(1)
+// @Param       limit query int false "Page size"
+// @Param       status query string false "Bug status"
+// @Param       flagged query bool false "Only flagged bugs"
+// @Param       spam_only query bool false "Only bugs scored as spam"
+// @Param       duplicate_check_skipped query bool false "Only bugs that skipped the duplicate check"
+// @Success     200 {object} object{bugs=[]models.BugReport,pagination=object}
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /admin/bugs [get]
 func (h *AdminHandler) ListBugsForModeration(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 	status := c.Query("status")
 	flagged := c.Query("flagged")
+	spamOnly := c.Query("spam_only")
+	duplicateCheckSkipped := c.Query("duplicate_check_skipped")
 
 	if page <= 0 {
 		page = 1
 	}
-	if limit <= 0 || limit > 100 {
-		limit = 20
-	}
+	limit = h.pagination.Limit(limit)
 
 	query := h.db.Model(&models.BugReport{}).
 		Preload("Application").
@@ -119,6 +215,15 @@ func (h *AdminHandler) ListBugsForModeration(c *gin.Context) {
 		query = query.Where("vote_count > ? OR comment_count > ?", 100, 50)
 	}
 
+	if spamOnly == "true" {
+		query = query.Where("is_spam = ? OR spam_score >= ?", true, h.spamScoreThreshold)
+	}
+
+	// Bugs submitted with force_create despite suspected duplicates
+	if duplicateCheckSkipped == "true" {
+		query = query.Where("duplicate_check_skipped = ?", true)
+	}
+
 	// Get total count
 	var total int64
 	query.Count(&total)
@@ -127,13 +232,7 @@ func (h *AdminHandler) ListBugsForModeration(c *gin.Context) {
 	offset := (page - 1) * limit
 	var bugs []models.BugReport
 	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&bugs).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "QUERY_FAILED",
-				"message":   "Failed to fetch bugs for moderation",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrQueryFailed.WithMessage("Failed to fetch bugs for moderation").Response(c)
 		return
 	}
 
@@ -161,31 +260,38 @@ type FlagBugRequest struct {
 }
 
 // FlagBug flags a bug report for review
+//
+// @Summary     Flag a bug
+// @Description Flags a bug report for review.
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Bug ID" format(uuid)
+// @Param       request body FlagBugRequest true "Reason for flagging"
+// @Success     200 {object} object{message=string,bug_id=string,reason=string}
+// @Failure     400 {object} errors.ErrorResponse
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     413 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /admin/bugs/{id}/flag [post]
 func (h *AdminHandler) FlagBug(c *gin.Context) {
 	bugID := c.Param("id")
 	
 	bugUUID, err := uuid.Parse(bugID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_ID",
-				"message":   "Invalid bug ID format",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInvalidID.WithMessage("Invalid bug ID format").Response(c)
 		return
 	}
 
 	var req FlagBugRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "VALIDATION_ERROR",
-				"message":   "Invalid request data",
-				"details":   err.Error(),
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
 		return
 	}
 
@@ -193,36 +299,18 @@ func (h *AdminHandler) FlagBug(c *gin.Context) {
 	var bug models.BugReport
 	if err := h.db.First(&bug, bugUUID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": gin.H{
-					"code":      "BUG_NOT_FOUND",
-					"message":   "Bug report not found",
-					"timestamp": time.Now().UTC(),
-				},
-			})
+			errors.ErrBugNotFound.Response(c)
 			return
 		}
 		
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "QUERY_FAILED",
-				"message":   "Failed to fetch bug report",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrQueryFailed.WithMessage("Failed to fetch bug report").Response(c)
 		return
 	}
 
 	// Log the flag action
 	details := fmt.Sprintf("Bug flagged for review. Reason: %s", req.Reason)
-	if err := h.logAuditAction(c, models.AuditActionBugFlag, models.AuditResourceBug, &bugUUID, details); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "AUDIT_LOG_FAILED",
-				"message":   "Failed to log audit action",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+	if err := h.logAuditAction(c, models.AuditActionBugFlag, models.AuditResourceBug, &bugUUID, details, nil, nil); err != nil {
+		errors.ErrAuditLogFailed.Response(c)
 		return
 	}
 
@@ -239,31 +327,40 @@ type RemoveBugRequest struct {
 }
 
 // RemoveBug removes a bug report (soft delete)
+//
+// @Summary     Remove a bug
+// @Description Soft deletes a bug report. The first request returns CONFIRMATION_REQUIRED with a token; repeating it with the token in X-Confirm-Token removes the bug.
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Bug ID" format(uuid)
+// @Param       X-Confirm-Token header string false "Confirmation token from a previous CONFIRMATION_REQUIRED response"
+// @Param       request body RemoveBugRequest true "Reason for removal"
+// @Success     200 {object} object{message=string,bug_id=string,reason=string}
+// @Failure     400 {object} errors.ErrorResponse
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     413 {object} errors.ErrorResponse
+// @Failure     428 {object} errors.ErrorResponse "Confirmation required"
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /admin/bugs/{id} [delete]
 func (h *AdminHandler) RemoveBug(c *gin.Context) {
 	bugID := c.Param("id")
 	
 	bugUUID, err := uuid.Parse(bugID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_ID",
-				"message":   "Invalid bug ID format",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInvalidID.WithMessage("Invalid bug ID format").Response(c)
 		return
 	}
 
 	var req RemoveBugRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "VALIDATION_ERROR",
-				"message":   "Invalid request data",
-				"details":   err.Error(),
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
 		return
 	}
 
@@ -271,43 +368,32 @@ func (h *AdminHandler) RemoveBug(c *gin.Context) {
 	var bug models.BugReport
 	if err := h.db.First(&bug, bugUUID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": gin.H{
-					"code":      "BUG_NOT_FOUND",
-					"message":   "Bug report not found",
-					"timestamp": time.Now().UTC(),
-				},
-			})
+			errors.ErrBugNotFound.Response(c)
 			return
 		}
 		
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "QUERY_FAILED",
-				"message":   "Failed to fetch bug report",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrQueryFailed.WithMessage("Failed to fetch bug report").Response(c)
 		return
 	}
 
 	// Soft delete the bug report
 	if err := h.db.Delete(&bug).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "DELETE_FAILED",
-				"message":   "Failed to remove bug report",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrDeleteFailed.WithMessage("Failed to remove bug report").Response(c)
 		return
 	}
 
+	if bug.ReporterID != nil {
+		if err := h.cache.InvalidateUserBugList(c.Request.Context(), bug.ReporterID.String()); err != nil {
+			// Log cache error but don't fail the request
+			logger.FromContext(c.Request.Context()).Error("Failed to invalidate user bug list cache", err, logger.Fields{"user_id": bug.ReporterID.String()})
+		}
+	}
+
 	// Log the removal action
 	details := fmt.Sprintf("Bug removed. Reason: %s. Title: %s", req.Reason, bug.Title)
-	if err := h.logAuditAction(c, models.AuditActionBugRemove, models.AuditResourceBug, &bugUUID, details); err != nil {
+	if err := h.logAuditAction(c, models.AuditActionBugRemove, models.AuditResourceBug, &bugUUID, details, nil, nil); err != nil {
 		// Log error but don't fail the request since the bug was already removed
-		fmt.Printf("Failed to log audit action: %v\n", err)
+		logger.FromContext(c.Request.Context()).Error("Failed to log audit action", err)
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -325,29 +411,35 @@ type MergeBugsRequest struct {
 }
 
 // MergeBugs merges duplicate bug reports
+//
+// @Summary     Merge duplicate bugs
+// @Description Moves the comments, votes and attachments of a duplicate bug report onto the target and deletes the duplicate.
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       request body MergeBugsRequest true "Source and target bugs"
+// @Success     200 {object} object{message=string,source_bug_id=string,target_bug_id=string,reason=string}
+// @Failure     400 {object} errors.ErrorResponse
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse "Source or target bug not found"
+// @Failure     413 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /admin/bugs/merge [post]
 func (h *AdminHandler) MergeBugs(c *gin.Context) {
 	var req MergeBugsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "VALIDATION_ERROR",
-				"message":   "Invalid request data",
-				"details":   err.Error(),
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
 		return
 	}
 
 	// Validate that source and target are different
 	if req.SourceBugID == req.TargetBugID {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_MERGE",
-				"message":   "Source and target bugs must be different",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInvalidMerge.Response(c)
 		return
 	}
 
@@ -364,65 +456,41 @@ func (h *AdminHandler) MergeBugs(c *gin.Context) {
 	if err := tx.First(&sourceBug, req.SourceBugID).Error; err != nil {
 		tx.Rollback()
 		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": gin.H{
-					"code":      "SOURCE_BUG_NOT_FOUND",
-					"message":   "Source bug report not found",
-					"timestamp": time.Now().UTC(),
-				},
-			})
+			errors.ErrSourceBugNotFound.Response(c)
 			return
 		}
 		
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "QUERY_FAILED",
-				"message":   "Failed to fetch source bug report",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrQueryFailed.WithMessage("Failed to fetch source bug report").Response(c)
 		return
 	}
 
 	if err := tx.First(&targetBug, req.TargetBugID).Error; err != nil {
 		tx.Rollback()
 		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": gin.H{
-					"code":      "TARGET_BUG_NOT_FOUND",
-					"message":   "Target bug report not found",
-					"timestamp": time.Now().UTC(),
-				},
-			})
+			errors.ErrTargetBugNotFound.Response(c)
 			return
 		}
 		
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "QUERY_FAILED",
-				"message":   "Failed to fetch target bug report",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrQueryFailed.WithMessage("Failed to fetch target bug report").Response(c)
 		return
 	}
 
+	// Capture both bugs before the merge modifies them
+	beforeState := mergeAuditState{
+		Source: newBugAuditState(&sourceBug),
+		Target: newBugAuditState(&targetBug),
+	}
+
 	// Move votes from source to target (avoiding duplicates)
 	if err := tx.Exec(`
-		INSERT INTO bug_votes (bug_id, user_id, created_at)
-		SELECT ?, user_id, created_at
+		INSERT INTO bug_votes (bug_id, user_id, vote_weight, created_at)
+		SELECT ?, user_id, vote_weight, created_at
 		FROM bug_votes
 		WHERE bug_id = ?
 		ON CONFLICT (bug_id, user_id) DO NOTHING
 	`, req.TargetBugID, req.SourceBugID).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "VOTE_MERGE_FAILED",
-				"message":   "Failed to merge votes",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrVoteMergeFailed.Response(c)
 		return
 	}
 
@@ -431,13 +499,7 @@ func (h *AdminHandler) MergeBugs(c *gin.Context) {
 		Where("bug_id = ?", req.SourceBugID).
 		Update("bug_id", req.TargetBugID).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "COMMENT_MERGE_FAILED",
-				"message":   "Failed to merge comments",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrCommentMergeFailed.Response(c)
 		return
 	}
 
@@ -446,34 +508,25 @@ func (h *AdminHandler) MergeBugs(c *gin.Context) {
 		Where("bug_id = ?", req.SourceBugID).
 		Update("bug_id", req.TargetBugID).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "ATTACHMENT_MERGE_FAILED",
-				"message":   "Failed to merge attachments",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrAttachmentMergeFailed.Response(c)
 		return
 	}
 
 	// Update target bug's vote and comment counts
 	var newVoteCount, newCommentCount int64
+	var newWeightedVoteCount float64
 	tx.Model(&models.BugVote{}).Where("bug_id = ?", req.TargetBugID).Count(&newVoteCount)
-	tx.Model(&models.Comment{}).Where("bug_id = ?", req.TargetBugID).Count(&newCommentCount)
+	tx.Model(&models.BugVote{}).Where("bug_id = ?", req.TargetBugID).Select("COALESCE(SUM(vote_weight), 0)").Scan(&newWeightedVoteCount)
+	tx.Unscoped().Model(&models.Comment{}).Where("bug_id = ? AND parent_id IS NULL", req.TargetBugID).Count(&newCommentCount)
 
 	if err := tx.Model(&targetBug).Updates(map[string]interface{}{
-		"vote_count":    newVoteCount,
-		"comment_count": newCommentCount,
-		"updated_at":    time.Now(),
+		"vote_count":          newVoteCount,
+		"weighted_vote_count": newWeightedVoteCount,
+		"comment_count":       newCommentCount,
+		"updated_at":          time.Now(),
 	}).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "COUNT_UPDATE_FAILED",
-				"message":   "Failed to update target bug counts",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrCountUpdateFailed.WithMessage("Failed to update target bug counts").Response(c)
 		return
 	}
 
@@ -489,47 +542,33 @@ func (h *AdminHandler) MergeBugs(c *gin.Context) {
 
 	if err := tx.Create(&mergeComment).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "MERGE_COMMENT_FAILED",
-				"message":   "Failed to create merge comment",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrMergeCommentFailed.Response(c)
 		return
 	}
 
 	// Soft delete the source bug
 	if err := tx.Delete(&sourceBug).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "SOURCE_DELETE_FAILED",
-				"message":   "Failed to remove source bug",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrSourceDeleteFailed.Response(c)
 		return
 	}
 
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "COMMIT_FAILED",
-				"message":   "Failed to complete bug merge",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrCommitFailed.WithMessage("Failed to complete bug merge").Response(c)
 		return
 	}
 
 	// Log the merge action
 	details := fmt.Sprintf("Merged bug '%s' (ID: %s) into '%s' (ID: %s). Reason: %s", 
 		sourceBug.Title, req.SourceBugID, targetBug.Title, req.TargetBugID, req.Reason)
-	if err := h.logAuditAction(c, models.AuditActionBugMerge, models.AuditResourceBug, &req.TargetBugID, details); err != nil {
+	afterState := mergeAuditState{
+		Source: newBugAuditState(&sourceBug),
+		Target: newBugAuditState(&targetBug),
+	}
+	if err := h.logAuditAction(c, models.AuditActionBugMerge, models.AuditResourceBug, &req.TargetBugID, details, beforeState, afterState); err != nil {
 		// Log error but don't fail the request since the merge was successful
-		fmt.Printf("Failed to log audit action: %v\n", err)
+		logger.FromContext(c.Request.Context()).Error("Failed to log audit action", err)
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -540,19 +579,95 @@ func (h *AdminHandler) MergeBugs(c *gin.Context) {
 	})
 }
 
-// GetAuditLogs returns audit log entries with pagination
+// maxAuditLogExportRows caps the number of audit log entries exported as CSV
+const maxAuditLogExportRows = 10000
+
+// auditLogPage is a page of audit logs as returned and cached by GetAuditLogs
+type auditLogPage struct {
+	Logs       []models.AuditLog `json:"logs"`
+	Pagination gin.H             `json:"pagination"`
+}
+
+// GetAuditLogs returns audit log entries with pagination. Entries can be filtered by
+// action, resource, user, date range (from_date inclusive, to_date exclusive) and a
+// text search on details, and exported as CSV with format=csv.
+//
+// @Summary     List audit logs
+// @Description Lists audit log entries, filtered by action, resource, user, date range (from_date inclusive, to_date exclusive) and a text search. With format=csv the entries are exported as CSV.
+// @Tags        admin
+// @Produce     json,text/csv
+// @Security    BearerAuth
+// @Param       action query string false "Action"
+// @Param       resource query string false "Resource type"
+// @Param       user_id query string false "Acting user" format(uuid)
+// @Param       from_date query string false "Start date (inclusive)"
+// @Param       to_date query string false "End date (exclusive)"
+// @Param       search query string false "Text search on details"
+// @Param       page query int false "Page number" default(1)
+// @Param       limit query int false "Page size" default(50)
+// @Param       sort query string false "Sort order" Enums(asc,desc) default(desc)
+// @Param       format query string false "Response format" Enums(json,csv) default(json)
+// @Success     200 {object} auditLogPage
+// @Failure     400 {object} errors.ErrorResponse
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /admin/audit-logs [get]
 func (h *AdminHandler) GetAuditLogs(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
 	action := c.Query("action")
 	resource := c.Query("resource")
 	userID := c.Query("user_id")
+	fromDateStr := c.Query("from_date")
+	toDateStr := c.Query("to_date")
+	search := c.Query("search")
+	sortOrder := strings.ToLower(c.DefaultQuery("sort", "desc"))
+	format := c.DefaultQuery("format", "json")
 
 	if page <= 0 {
 		page = 1
 	}
-	if limit <= 0 || limit > 100 {
-		limit = 50
+	limit = h.pagination.WithDefault(50).Limit(limit)
+
+	if sortOrder != "asc" && sortOrder != "desc" {
+		errors.ErrInvalidSort.Response(c)
+		return
+	}
+
+	if format != "json" && format != "csv" {
+		errors.ErrInvalidFormat.Response(c)
+		return
+	}
+
+	var fromDate, toDate time.Time
+	var err error
+	if fromDateStr != "" {
+		if fromDate, err = time.Parse(time.RFC3339, fromDateStr); err != nil {
+			errors.ErrInvalidDate.WithMessage("from_date must be an RFC3339 timestamp").Response(c)
+			return
+		}
+	}
+	if toDateStr != "" {
+		if toDate, err = time.Parse(time.RFC3339, toDateStr); err != nil {
+			errors.ErrInvalidDate.WithMessage("to_date must be an RFC3339 timestamp").Response(c)
+			return
+		}
+	}
+	if fromDateStr != "" && toDateStr != "" && !fromDate.Before(toDate) {
+		errors.ErrInvalidDateRange.Response(c)
+		return
+	}
+
+	// The first page of common queries is cached briefly
+	cacheable := format == "json" && page == 1 && fromDateStr == "" && toDateStr == "" && search == ""
+	cacheKey := cache.GenerateCacheKey(limit, action, resource, userID, sortOrder)
+	if cacheable {
+		var cached auditLogPage
+		if err := h.cache.GetAuditLogs(c.Request.Context(), cacheKey, &cached); err == nil {
+			c.JSON(http.StatusOK, cached)
+			return
+		}
 	}
 
 	query := h.db.Model(&models.AuditLog{}).Preload("User")
@@ -569,6 +684,28 @@ func (h *AdminHandler) GetAuditLogs(c *gin.Context) {
 			query = query.Where("user_id = ?", userUUID)
 		}
 	}
+	if fromDateStr != "" {
+		query = query.Where("created_at >= ?", fromDate)
+	}
+	if toDateStr != "" {
+		query = query.Where("created_at < ?", toDate)
+	}
+	if search != "" {
+		query = query.Where("LOWER(details) LIKE LOWER(?)", "%"+search+"%")
+	}
+
+	order := "created_at " + strings.ToUpper(sortOrder)
+
+	if format == "csv" {
+		var logs []models.AuditLog
+		if err := query.Order(order).Limit(maxAuditLogExportRows).Find(&logs).Error; err != nil {
+			errors.ErrQueryFailed.WithMessage("Failed to fetch audit logs").Response(c)
+			return
+		}
+
+		writeAuditLogsCSV(c, logs)
+		return
+	}
 
 	// Get total count
 	var total int64
@@ -577,14 +714,8 @@ func (h *AdminHandler) GetAuditLogs(c *gin.Context) {
 	// Apply pagination
 	offset := (page - 1) * limit
 	var logs []models.AuditLog
-	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&logs).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "QUERY_FAILED",
-				"message":   "Failed to fetch audit logs",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+	if err := query.Offset(offset).Limit(limit).Order(order).Find(&logs).Error; err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to fetch audit logs").Response(c)
 		return
 	}
 
@@ -593,9 +724,9 @@ func (h *AdminHandler) GetAuditLogs(c *gin.Context) {
 	hasNext := page < totalPages
 	hasPrev := page > 1
 
-	c.JSON(http.StatusOK, gin.H{
-		"logs": logs,
-		"pagination": gin.H{
+	response := auditLogPage{
+		Logs: logs,
+		Pagination: gin.H{
 			"page":        page,
 			"limit":       limit,
 			"total":       total,
@@ -603,82 +734,781 @@ func (h *AdminHandler) GetAuditLogs(c *gin.Context) {
 			"has_next":    hasNext,
 			"has_prev":    hasPrev,
 		},
+	}
+
+	if cacheable {
+		if err := h.cache.SetAuditLogs(c.Request.Context(), cacheKey, response); err != nil {
+			// Log cache error but don't fail the request
+			logger.FromContext(c.Request.Context()).Error("Failed to cache audit logs", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// writeAuditLogsCSV writes audit log entries as a CSV attachment
+func writeAuditLogsCSV(c *gin.Context, logs []models.AuditLog) {
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", "attachment; filename=audit-logs.csv")
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{"timestamp", "user_email", "action", "resource", "resource_id", "details", "ip_address"})
+
+	for _, log := range logs {
+		resourceID := ""
+		if log.ResourceID != nil {
+			resourceID = log.ResourceID.String()
+		}
+		ipAddress := ""
+		if log.IPAddress != nil {
+			ipAddress = *log.IPAddress
+		}
+
+		record := []string{
+			log.CreatedAt.UTC().Format(time.RFC3339),
+			log.User.Email,
+			log.Action,
+			log.Resource,
+			resourceID,
+			log.Details,
+			ipAddress,
+		}
+		for i, value := range record {
+			record[i] = escapeCSVFormula(value)
+		}
+		writer.Write(record)
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		logger.FromContext(c.Request.Context()).Error("Failed to write audit log CSV", err)
+	}
+}
+
+// GetAuditLog returns a single audit log entry, including its before/after states
+// and the fields that changed between them
+//
+// @Summary     Get an audit log entry
+// @Description Returns an audit log entry with the fields changed between its before and after states.
+// @Tags        admin
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Audit log ID" format(uuid)
+// @Success     200 {object} object{log=models.AuditLog,changes=object}
+// @Failure     400 {object} errors.ErrorResponse
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /admin/audit-logs/{id} [get]
+func (h *AdminHandler) GetAuditLog(c *gin.Context) {
+	logUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid audit log ID format").Response(c)
+		return
+	}
+
+	var log models.AuditLog
+	if err := h.db.Preload("User").First(&log, "id = ?", logUUID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrAuditLogNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch audit log entry").Response(c)
+		return
+	}
+
+	changes, err := auditStateDiff(log.BeforeState, log.AfterState)
+	if err != nil {
+		errors.ErrInvalidAuditState.WithDetails(err.Error()).Response(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"log":     log,
+		"changes": changes,
 	})
 }
 
 // RestoreBug restores a soft-deleted bug report
+//
+// @Summary     Restore a deleted bug
+// @Description Restores a soft deleted bug report.
+// @Tags        admin
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Bug ID" format(uuid)
+// @Success     200 {object} object{message=string,bug_id=string}
+// @Failure     400 {object} errors.ErrorResponse "Invalid ID or the bug is not deleted"
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /admin/bugs/{id}/restore [post]
 func (h *AdminHandler) RestoreBug(c *gin.Context) {
 	bugID := c.Param("id")
 	
 	bugUUID, err := uuid.Parse(bugID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_ID",
-				"message":   "Invalid bug ID format",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInvalidID.WithMessage("Invalid bug ID format").Response(c)
 		return
 	}
 
 	// Find the soft-deleted bug
 	var bug models.BugReport
-	if err := h.db.Unscoped().First(&bug, bugUUID).Error; err != nil {
+	if err := database.IncludingDeleted(h.db).First(&bug, bugUUID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": gin.H{
-					"code":      "BUG_NOT_FOUND",
-					"message":   "Bug report not found",
-					"timestamp": time.Now().UTC(),
-				},
-			})
+			errors.ErrBugNotFound.Response(c)
 			return
 		}
 		
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "QUERY_FAILED",
-				"message":   "Failed to fetch bug report",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrQueryFailed.WithMessage("Failed to fetch bug report").Response(c)
 		return
 	}
 
 	// Check if bug is actually deleted
 	if bug.DeletedAt.Time.IsZero() {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "BUG_NOT_DELETED",
-				"message":   "Bug report is not deleted",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrBugNotDeleted.Response(c)
 		return
 	}
 
 	// Restore the bug
-	if err := h.db.Unscoped().Model(&bug).Update("deleted_at", nil).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "RESTORE_FAILED",
-				"message":   "Failed to restore bug report",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+	beforeState := newBugAuditState(&bug)
+	if err := database.IncludingDeleted(h.db).Model(&bug).Update("deleted_at", nil).Error; err != nil {
+		errors.ErrRestoreFailed.WithMessage("Failed to restore bug report").Response(c)
 		return
 	}
 
 	// Log the restore action
 	details := fmt.Sprintf("Bug restored. Title: %s", bug.Title)
-	if err := h.logAuditAction(c, models.AuditActionBugRestore, models.AuditResourceBug, &bugUUID, details); err != nil {
+	afterState := beforeState
+	afterState.DeletedAt = nil
+	if err := h.logAuditAction(c, models.AuditActionBugRestore, models.AuditResourceBug, &bugUUID, details, beforeState, afterState); err != nil {
 		// Log error but don't fail the request since the bug was restored
-		fmt.Printf("Failed to log audit action: %v\n", err)
+		logger.FromContext(c.Request.Context()).Error("Failed to log audit action", err)
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Bug report restored successfully",
 		"bug_id":  bugUUID,
 	})
-}
\ No newline at end of file
+}
+// RebuildBugProjection replays a bug's events to reconstruct its projected status
+// and priority, e.g. after the projection was edited by hand or a projection bug
+//
+// @Summary     Rebuild a bug from its events
+// @Description Replays a bug report's events to rebuild its stored state.
+// @Tags        admin
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Bug ID" format(uuid)
+// @Success     200 {object} object{message=string,bug=models.BugReport,event_count=int}
+// @Failure     400 {object} errors.ErrorResponse
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /admin/bugs/{id}/rebuild-projection [post]
+func (h *AdminHandler) RebuildBugProjection(c *gin.Context) {
+	bugUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid bug ID format").Response(c)
+		return
+	}
+
+	var bug models.BugReport
+	if err := h.db.First(&bug, bugUUID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrBugNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch bug report").Response(c)
+		return
+	}
+	beforeState := newBugAuditState(&bug)
+
+	rebuilt, err := h.projector.Rebuild(c.Request.Context(), bugUUID)
+	if err != nil {
+		errors.ErrRebuildFailed.Response(c)
+		return
+	}
+
+	if err := h.cache.InvalidateBug(c.Request.Context(), bugUUID.String()); err != nil {
+		// Log cache error but don't fail the request
+		logger.FromContext(c.Request.Context()).Error("Failed to invalidate bug cache", err, logger.Fields{"bug_id": bugUUID.String()})
+	}
+
+	details := fmt.Sprintf("Bug projection rebuilt from %d events", rebuilt.EventSequence)
+	if err := h.logAuditAction(c, models.AuditActionBugProjectionRebuild, models.AuditResourceBug, &bugUUID, details, beforeState, newBugAuditState(rebuilt)); err != nil {
+		// Log error but don't fail the request since the bug was rebuilt
+		logger.FromContext(c.Request.Context()).Error("Failed to log audit action", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Bug report rebuilt successfully",
+		"bug":         rebuilt,
+		"event_count": rebuilt.EventSequence,
+	})
+}
+
+// ListDeletedBugs returns soft-deleted bug reports with pagination and filters
+//
+// @Summary     List deleted bugs
+// @Description Lists soft deleted bug reports.
+// @Tags        admin
+// @Produce     json
+// @Security    BearerAuth
+// @Param       page query int false "Page number" default(1)
+// @Param       limit query int false "Page size"
+// @Param       status query string false "Bug status"
+// @Param       priority query string false "Bug priority"
+// @Param       application_id query string false "Application ID" format(uuid)
+// @Param       search query string false "Search text"
+// @Success     200 {object} object{bugs=[]models.BugReport,pagination=object}
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /admin/bugs/deleted [get]
+func (h *AdminHandler) ListDeletedBugs(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	search := c.Query("search")
+	status := c.Query("status")
+	priority := c.Query("priority")
+	applicationID := c.Query("application_id")
+
+	if page <= 0 {
+		page = 1
+	}
+	limit = h.pagination.Limit(limit)
+
+	query := database.IncludingDeleted(h.db).Model(&models.BugReport{}).
+		Where("deleted_at IS NOT NULL").
+		Preload("Application").
+		Preload("Reporter").
+		Preload("AssignedCompany")
+
+	// Apply filters
+	if search != "" {
+		searchTerm := "%" + strings.ToLower(search) + "%"
+		query = query.Where("LOWER(title) LIKE ? OR LOWER(description) LIKE ?", searchTerm, searchTerm)
+	}
+	if status != "" && models.IsValidStatus(status) {
+		query = query.Where("status = ?", status)
+	}
+	if priority != "" && models.IsValidPriority(priority) {
+		query = query.Where("priority = ?", priority)
+	}
+	if applicationID != "" {
+		if appUUID, err := uuid.Parse(applicationID); err == nil {
+			query = query.Where("application_id = ?", appUUID)
+		}
+	}
+
+	// Get total count
+	var total int64
+	query.Count(&total)
+
+	// Apply pagination
+	offset := (page - 1) * limit
+	var bugs []models.BugReport
+	if err := query.Offset(offset).Limit(limit).Order("deleted_at DESC").Find(&bugs).Error; err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to fetch deleted bugs").Response(c)
+		return
+	}
+
+	// Calculate pagination info
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	hasNext := page < totalPages
+	hasPrev := page > 1
+
+	c.JSON(http.StatusOK, gin.H{
+		"bugs": bugs,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": totalPages,
+			"has_next":    hasNext,
+			"has_prev":    hasPrev,
+		},
+	})
+}
+
+// RestoreAllDeletedBugs restores every soft-deleted bug report
+//
+// @Summary     Restore all deleted bugs
+// @Description Restores every soft deleted bug report.
+// @Tags        admin
+// @Produce     json
+// @Security    BearerAuth
+// @Success     200 {object} object{message=string,restored_count=int}
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /admin/bugs/restore-all [post]
+func (h *AdminHandler) RestoreAllDeletedBugs(c *gin.Context) {
+	result := database.IncludingDeleted(h.db).Model(&models.BugReport{}).
+		Where("deleted_at IS NOT NULL").
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		errors.ErrRestoreFailed.WithMessage("Failed to restore deleted bugs").Response(c)
+		return
+	}
+
+	// Log the restore action
+	details := fmt.Sprintf("Restored all deleted bugs. Count: %d", result.RowsAffected)
+	if err := h.logAuditAction(c, models.AuditActionBugRestore, models.AuditResourceBug, nil, details, nil, nil); err != nil {
+		// Log error but don't fail the request since the bugs were restored
+		logger.FromContext(c.Request.Context()).Error("Failed to log audit action", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Deleted bugs restored successfully",
+		"restored_count": result.RowsAffected,
+	})
+}
+
+// PurgeDeletedBugs permanently deletes soft-deleted bugs older than purge_after_days
+//
+// @Summary     Purge deleted bugs
+// @Description Permanently deletes bug reports that were soft deleted more than purge_after_days ago, with their comments, votes and attachment files. The first request returns CONFIRMATION_REQUIRED with a token; repeating it with the token in X-Confirm-Token purges the bugs.
+// @Tags        admin
+// @Produce     json
+// @Security    BearerAuth
+// @Param       purge_after_days query int false "Minimum days since deletion" default(30)
+// @Param       X-Confirm-Token header string false "Confirmation token from a previous CONFIRMATION_REQUIRED response"
+// @Success     200 {object} object{message=string,purged_count=int,purge_after_days=int}
+// @Failure     400 {object} errors.ErrorResponse
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     428 {object} errors.ErrorResponse "Confirmation required"
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /admin/bugs/purge [delete]
+func (h *AdminHandler) PurgeDeletedBugs(c *gin.Context) {
+	purgeAfterDays, err := strconv.Atoi(c.DefaultQuery("purge_after_days", "30"))
+	if err != nil || purgeAfterDays < 0 {
+		errors.ErrInvalidPurgeAfterDays.Response(c)
+		return
+	}
+
+	cutoff := time.Now().Add(-time.Duration(purgeAfterDays) * 24 * time.Hour)
+
+	// Start transaction
+	tx := h.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			// Let the recovery middleware respond with 500
+			panic(r)
+		}
+	}()
+
+	var bugIDs []uuid.UUID
+	if err := database.IncludingDeleted(tx).Model(&models.BugReport{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Pluck("id", &bugIDs).Error; err != nil {
+		tx.Rollback()
+		errors.ErrQueryFailed.WithMessage("Failed to fetch deleted bugs").Response(c)
+		return
+	}
+
+	var purgedCount int64
+	var attachments []models.FileAttachment
+	if len(bugIDs) > 0 {
+		// The files are deleted from storage once the purge is committed
+		if err := database.IncludingDeleted(tx).Where("bug_id IN ?", bugIDs).Find(&attachments).Error; err != nil {
+			tx.Rollback()
+			errors.ErrQueryFailed.WithMessage("Failed to fetch bug attachments").Response(c)
+			return
+		}
+
+		// Remove associated records before the bugs themselves, including soft-deleted
+		// attachments
+		for _, model := range []interface{}{&models.Comment{}, &models.BugVote{}, &models.FileAttachment{}} {
+			if err := database.IncludingDeleted(tx).Where("bug_id IN ?", bugIDs).Delete(model).Error; err != nil {
+				tx.Rollback()
+				errors.ErrPurgeFailed.WithMessage("Failed to purge bug associations").Response(c)
+				return
+			}
+		}
+
+		result := database.IncludingDeleted(tx).Where("id IN ?", bugIDs).Delete(&models.BugReport{})
+		if result.Error != nil {
+			tx.Rollback()
+			errors.ErrPurgeFailed.Response(c)
+			return
+		}
+		purgedCount = result.RowsAffected
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		errors.ErrCommitFailed.WithMessage("Failed to complete bug purge").Response(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+	for _, attachment := range attachments {
+		if err := h.storage.Delete(ctx, attachment); err != nil {
+			// The bug is already purged, so an orphaned file only wastes space
+			logger.FromContext(ctx).Error("Failed to delete attachment file", err, logger.Fields{
+				"attachment_id": attachment.ID.String(),
+				"file_url":      attachment.FileURL,
+			})
+		}
+	}
+
+	// Log the purge action
+	details := fmt.Sprintf("Purged %d deleted bugs older than %d days", purgedCount, purgeAfterDays)
+	if err := h.logAuditAction(c, models.AuditActionBugPurge, models.AuditResourceBug, nil, details, nil, nil); err != nil {
+		// Log error but don't fail the request since the bugs were purged
+		logger.FromContext(ctx).Error("Failed to log audit action", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Deleted bugs purged successfully",
+		"purged_count":     purgedCount,
+		"purge_after_days": purgeAfterDays,
+	})
+}
+
+// DailyStats represents activity within a single time bucket
+type DailyStats struct {
+	Date         string `json:"date"`
+	BugsCreated  int64  `json:"bugs_created"`
+	BugsResolved int64  `json:"bugs_resolved"`
+	NewUsers     int64  `json:"new_users"`
+	ActiveUsers  int64  `json:"active_users"`
+}
+
+// CurrentPeriodTotals represents activity totals for a whole period
+type CurrentPeriodTotals struct {
+	BugsCreated  int64 `json:"bugs_created"`
+	BugsResolved int64 `json:"bugs_resolved"`
+	NewUsers     int64 `json:"new_users"`
+	ActiveUsers  int64 `json:"active_users"`
+}
+
+// PeriodOverPeriodChanges represents percentage changes against the previous period
+type PeriodOverPeriodChanges struct {
+	BugsCreated  float64 `json:"bugs_created"`
+	BugsResolved float64 `json:"bugs_resolved"`
+	NewUsers     float64 `json:"new_users"`
+	ActiveUsers  float64 `json:"active_users"`
+}
+
+// AdminStatsResponse represents the response for admin time-series statistics
+type AdminStatsResponse struct {
+	Period  string                  `json:"period"`
+	Series  []DailyStats            `json:"series"`
+	Totals  CurrentPeriodTotals     `json:"totals"`
+	Changes PeriodOverPeriodChanges `json:"changes"`
+}
+
+// statsPeriods maps supported period values to their length in days
+var statsPeriods = map[string]int{
+	"7d":  7,
+	"30d": 30,
+	"90d": 90,
+}
+
+// percentageChange calculates the change from previous to current in percent.
+// When the previous value is zero, any growth is reported as 100%.
+func percentageChange(previous, current int64) float64 {
+	if previous == 0 {
+		if current == 0 {
+			return 0
+		}
+		return 100
+	}
+	return float64(current-previous) / float64(previous) * 100
+}
+
+// statsMetric is a count in the admin statistics: the number of distinct counted
+// values among the rows of a query, dated by one of its timestamp columns
+type statsMetric struct {
+	query   func(db *gorm.DB) *gorm.DB
+	column  string
+	counted string
+}
+
+var (
+	statsBugsCreated = statsMetric{
+		query:   func(db *gorm.DB) *gorm.DB { return db.Model(&models.BugReport{}) },
+		column:  "created_at",
+		counted: "id",
+	}
+	statsBugsResolved = statsMetric{
+		query:   func(db *gorm.DB) *gorm.DB { return db.Model(&models.BugReport{}) },
+		column:  "resolved_at",
+		counted: "id",
+	}
+	statsNewUsers = statsMetric{
+		query:   func(db *gorm.DB) *gorm.DB { return db.Model(&models.User{}) },
+		column:  "created_at",
+		counted: "id",
+	}
+	// Users are active when they report a bug or comment on one
+	statsActiveUsers = statsMetric{
+		query: func(db *gorm.DB) *gorm.DB {
+			reporters := db.Model(&models.BugReport{}).
+				Select("reporter_id AS user_id, created_at").
+				Where("reporter_id IS NOT NULL")
+			commenters := db.Model(&models.Comment{}).Select("user_id, created_at")
+			return db.Table("(?) AS activity", db.Raw("? UNION ALL ?", reporters, commenters))
+		},
+		column:  "created_at",
+		counted: "user_id",
+	}
+)
+
+// statsCounts holds a metric's count in each bucket of the series, keyed by the
+// start of the bucket, and its totals for the current and previous periods
+type statsCounts struct {
+	buckets  map[time.Time]int64
+	current  int64
+	previous int64
+}
+
+// loadStatsCounts counts metric in buckets of bucketDays days over [start, end),
+// and in total over [previousStart, start) and [start, end)
+func (h *AdminHandler) loadStatsCounts(metric statsMetric, previousStart, start, end time.Time, bucketDays int) (*statsCounts, error) {
+	column := metric.column
+
+	// Buckets are aligned to start, so weekly buckets start on the same weekday as the period
+	var buckets []struct {
+		BucketStart time.Time
+		Count       int64
+	}
+	if err := metric.query(h.db).
+		Select(fmt.Sprintf("date_trunc('day', %[1]s AT TIME ZONE 'UTC') - "+
+			"((%[1]s AT TIME ZONE 'UTC')::date - CAST(? AS date)) %% ? * INTERVAL '1 day' AS bucket_start, "+
+			"COUNT(DISTINCT %[2]s) AS count", column, metric.counted), start.Format("2006-01-02"), bucketDays).
+		Where(column+" >= ? AND "+column+" < ?", start, end).
+		Group("bucket_start").
+		Scan(&buckets).Error; err != nil {
+		return nil, err
+	}
+
+	var totals struct {
+		CurrentCount  int64
+		PreviousCount int64
+	}
+	if err := metric.query(h.db).
+		Select(fmt.Sprintf("COUNT(DISTINCT CASE WHEN %[1]s >= ? THEN %[2]s END) AS current_count, "+
+			"COUNT(DISTINCT CASE WHEN %[1]s < ? THEN %[2]s END) AS previous_count", column, metric.counted), start, start).
+		Where(column+" >= ? AND "+column+" < ?", previousStart, end).
+		Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+
+	counts := &statsCounts{
+		buckets:  make(map[time.Time]int64, len(buckets)),
+		current:  totals.CurrentCount,
+		previous: totals.PreviousCount,
+	}
+	for _, bucket := range buckets {
+		bucketStart := bucket.BucketStart.UTC()
+		counts.buckets[time.Date(bucketStart.Year(), bucketStart.Month(), bucketStart.Day(), 0, 0, 0, 0, time.UTC)] = bucket.Count
+	}
+	return counts, nil
+}
+
+// GetAdminStats returns time-series statistics for the requested period
+//
+// @Summary     Get bug statistics
+// @Description Returns daily bug statistics for the period with totals and the change from the previous period.
+// @Tags        admin
+// @Produce     json
+// @Security    BearerAuth
+// @Param       period query string false "Period" Enums(7d,30d,90d) default(7d)
+// @Success     200 {object} AdminStatsResponse
+// @Failure     400 {object} errors.ErrorResponse
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /admin/stats [get]
+func (h *AdminHandler) GetAdminStats(c *gin.Context) {
+	period := c.DefaultQuery("period", "7d")
+	days, ok := statsPeriods[period]
+	if !ok {
+		errors.ErrInvalidPeriod.Response(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+	cacheKey := "admin:" + period
+
+	var cached AdminStatsResponse
+	if err := h.cache.GetStats(ctx, cacheKey, &cached); err == nil {
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+
+	// Daily buckets for short periods, weekly buckets for longer ones
+	bucketDays := 1
+	if days > 30 {
+		bucketDays = 7
+	}
+
+	now := time.Now().UTC()
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).Add(24 * time.Hour)
+	start := end.Add(-time.Duration(days) * 24 * time.Hour)
+	previousStart := start.Add(-time.Duration(days) * 24 * time.Hour)
+
+	metrics := []statsMetric{statsBugsCreated, statsBugsResolved, statsNewUsers, statsActiveUsers}
+	counts := make([]*statsCounts, len(metrics))
+	for i, metric := range metrics {
+		var err error
+		if counts[i], err = h.loadStatsCounts(metric, previousStart, start, end, bucketDays); err != nil {
+			errors.ErrQueryFailed.WithMessage("Failed to fetch statistics").Response(c)
+			return
+		}
+	}
+	bugsCreated, bugsResolved, newUsers, activeUsers := counts[0], counts[1], counts[2], counts[3]
+
+	series := make([]DailyStats, 0)
+	for bucketStart := start; bucketStart.Before(end); bucketStart = bucketStart.AddDate(0, 0, bucketDays) {
+		series = append(series, DailyStats{
+			Date:         bucketStart.Format("2006-01-02"),
+			BugsCreated:  bugsCreated.buckets[bucketStart],
+			BugsResolved: bugsResolved.buckets[bucketStart],
+			NewUsers:     newUsers.buckets[bucketStart],
+			ActiveUsers:  activeUsers.buckets[bucketStart],
+		})
+	}
+
+	totals := CurrentPeriodTotals{
+		BugsCreated:  bugsCreated.current,
+		BugsResolved: bugsResolved.current,
+		NewUsers:     newUsers.current,
+		ActiveUsers:  activeUsers.current,
+	}
+	previous := CurrentPeriodTotals{
+		BugsCreated:  bugsCreated.previous,
+		BugsResolved: bugsResolved.previous,
+		NewUsers:     newUsers.previous,
+		ActiveUsers:  activeUsers.previous,
+	}
+
+	response := AdminStatsResponse{
+		Period: period,
+		Series: series,
+		Totals: totals,
+		Changes: PeriodOverPeriodChanges{
+			BugsCreated:  percentageChange(previous.BugsCreated, totals.BugsCreated),
+			BugsResolved: percentageChange(previous.BugsResolved, totals.BugsResolved),
+			NewUsers:     percentageChange(previous.NewUsers, totals.NewUsers),
+			ActiveUsers:  percentageChange(previous.ActiveUsers, totals.ActiveUsers),
+		},
+	}
+
+	if err := h.cache.SetStats(ctx, cacheKey, response); err != nil {
+		// Log cache error but don't fail the request
+		logger.FromContext(ctx).Error("Failed to cache admin stats", err, logger.Fields{"period": period})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RefreshBugStats manually refreshes the bug_stats_by_company materialized view
+//
+// @Summary     Refresh bug statistics
+// @Description Recomputes the daily bug statistics.
+// @Tags        admin
+// @Produce     json
+// @Security    BearerAuth
+// @Success     200 {object} object{message=string,refreshed_at=string}
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /admin/stats/refresh [post]
+func (h *AdminHandler) RefreshBugStats(c *gin.Context) {
+	if err := models.RefreshBugStatsByCompany(h.db.WithContext(c.Request.Context())); err != nil {
+		errors.ErrRefreshFailed.Response(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Bug statistics refreshed successfully",
+		"refreshed_at": time.Now().UTC(),
+	})
+}
+
+// RateLimitExemptionRequest represents the request to temporarily exempt a user from rate limits
+type RateLimitExemptionRequest struct {
+	UserID          uuid.UUID `json:"user_id" binding:"required"`
+	DurationMinutes int       `json:"duration_minutes" binding:"required,min=1,max=1440"`
+	Reason          string    `json:"reason" binding:"required,min=1,max=500"`
+}
+
+// ExemptUserFromRateLimits temporarily exempts a user from API rate limiting
+//
+// @Summary     Exempt a user from rate limits
+// @Description Temporarily exempts a user from API rate limiting.
+// @Tags        admin
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       request body RateLimitExemptionRequest true "User and duration"
+// @Success     200 {object} object{message=string,user_id=string,expires_at=string,reason=string}
+// @Failure     400 {object} errors.ErrorResponse
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     413 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Failure     503 {object} errors.ErrorResponse "Rate limiter unavailable"
+// @Router      /admin/rate-limits/exempt [post]
+func (h *AdminHandler) ExemptUserFromRateLimits(c *gin.Context) {
+	var req RateLimitExemptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+
+	if h.rateLimiter == nil {
+		errors.ErrRateLimiterUnavailable.Response(c)
+		return
+	}
+
+	// Verify user exists
+	var user models.User
+	if err := h.db.First(&user, req.UserID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrUserNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch user").Response(c)
+		return
+	}
+
+	duration := time.Duration(req.DurationMinutes) * time.Minute
+	if err := h.rateLimiter.AddExemption(c.Request.Context(), req.UserID.String(), duration); err != nil {
+		errors.ErrExemptionFailed.Response(c)
+		return
+	}
+	expiresAt := time.Now().Add(duration).UTC()
+
+	// Log the exemption
+	details := fmt.Sprintf("Rate limit exemption granted for %d minutes. Reason: %s", req.DurationMinutes, req.Reason)
+	if err := h.logAuditAction(c, models.AuditActionRateLimitExempt, models.AuditResourceUser, &req.UserID, details, nil, nil); err != nil {
+		// Log error but don't fail the request since the exemption was already created
+		logger.FromContext(c.Request.Context()).Error("Failed to log audit action", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Rate limit exemption created successfully",
+		"user_id":    req.UserID,
+		"expires_at": expiresAt,
+		"reason":     req.Reason,
+	})
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// TransferBugOwnershipRequest represents the request to move a bug to another reporter
+type TransferBugOwnershipRequest struct {
+	NewReporterID uuid.UUID `json:"new_reporter_id" binding:"required"`
+	Reason        string    `json:"reason" binding:"required,min=1,max=500"`
+}
+
+// bugOwnerAuditState is the snapshot of a bug's ownership stored in audit log states
+type bugOwnerAuditState struct {
+	ReporterID       *uuid.UUID `json:"reporter_id"`
+	AssignedMemberID *uuid.UUID `json:"assigned_member_id"`
+}
+
+// copyUUID returns a copy of id, or nil
+func copyUUID(id *uuid.UUID) *uuid.UUID {
+	if id == nil {
+		return nil
+	}
+	copied := *id
+	return &copied
+}
+
+// TransferBugOwnership moves a bug to a new reporter, e.g. when its reporter leaves
+// their company. A bug assigned to its old reporter is assigned to the new one. Both
+// reporters are notified; the transfer is still allowed when the old reporter's
+// account no longer exists.
+func (h *AdminHandler) TransferBugOwnership(c *gin.Context) {
+	bugUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid bug ID format").Response(c)
+		return
+	}
+
+	var req TransferBugOwnershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+
+	var bug models.BugReport
+	if err := h.db.First(&bug, "id = ?", bugUUID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrBugNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch bug report").Response(c)
+		return
+	}
+
+	// Deleted accounts cannot take over bugs
+	var newReporter models.User
+	if err := h.db.First(&newReporter, "id = ?", req.NewReporterID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrUserNotFound.WithMessage("New reporter not found").Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch user").Response(c)
+		return
+	}
+
+	if bug.ReporterID != nil && *bug.ReporterID == newReporter.ID {
+		errors.ErrSameOwner.Response(c)
+		return
+	}
+
+	// Updates writes through the bug's pointer fields, so the old IDs are copied
+	beforeState := bugOwnerAuditState{ReporterID: copyUUID(bug.ReporterID), AssignedMemberID: copyUUID(bug.AssignedMemberID)}
+	oldReporterID := beforeState.ReporterID
+	updates := map[string]interface{}{"reporter_id": newReporter.ID}
+	afterState := bugOwnerAuditState{ReporterID: &newReporter.ID, AssignedMemberID: beforeState.AssignedMemberID}
+	if oldReporterID != nil && beforeState.AssignedMemberID != nil && *beforeState.AssignedMemberID == *oldReporterID {
+		updates["assigned_member_id"] = newReporter.ID
+		afterState.AssignedMemberID = &newReporter.ID
+	}
+
+	// The old reporter is only notified while their account exists
+	recipients := []uuid.UUID{newReporter.ID}
+	if oldReporterID != nil {
+		var count int64
+		if err := h.db.Model(&models.User{}).Where("id = ?", *oldReporterID).Count(&count).Error; err != nil {
+			errors.ErrQueryFailed.WithMessage("Failed to fetch previous reporter").Response(c)
+			return
+		}
+		if count > 0 {
+			recipients = append(recipients, *oldReporterID)
+		}
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"bug_id":               bug.ID,
+		"previous_reporter_id": oldReporterID,
+		"new_reporter_id":      newReporter.ID,
+	})
+	if err != nil {
+		errors.ErrTransferFailed.Response(c)
+		return
+	}
+
+	// The transfer, its notifications and its audit entry are saved together so an
+	// ownership change is never left unrecorded
+	details := fmt.Sprintf("Bug ownership transferred. Reason: %s", req.Reason)
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&bug).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		for _, recipient := range recipients {
+			body := fmt.Sprintf("The bug report '%s' was transferred to you by an administrator", bug.Title)
+			if recipient != newReporter.ID {
+				body = fmt.Sprintf("Your bug report '%s' was transferred to another user by an administrator", bug.Title)
+			}
+			if err := tx.Create(&models.Notification{
+				UserID:     recipient,
+				Type:       models.NotificationTypeBugOwnershipTransfer,
+				ResourceID: &bug.ID,
+				Body:       body,
+				Payload:    datatypes.JSON(payload),
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		return createAuditLog(tx, c, models.AuditActionBugOwnerTransfer, models.AuditResourceBug, &bugUUID, details, beforeState, afterState)
+	})
+	if err != nil {
+		errors.ErrTransferFailed.Response(c)
+		return
+	}
+
+	if err := h.cache.InvalidateBug(c.Request.Context(), bugUUID.String()); err != nil {
+		// Log cache error but don't fail the request
+		logger.FromContext(c.Request.Context()).Error("Failed to invalidate bug cache", err, logger.Fields{"bug_id": bugUUID.String()})
+	}
+
+	// The bug moves from one reporter's bug list to the other's
+	reporterIDs := []uuid.UUID{newReporter.ID}
+	if oldReporterID != nil {
+		reporterIDs = append(reporterIDs, *oldReporterID)
+	}
+	for _, reporterID := range reporterIDs {
+		if err := h.cache.InvalidateUserBugList(c.Request.Context(), reporterID.String()); err != nil {
+			// Log cache error but don't fail the request
+			logger.FromContext(c.Request.Context()).Error("Failed to invalidate user bug list cache", err, logger.Fields{"user_id": reporterID.String()})
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":              "Bug ownership transferred successfully",
+		"bug_id":               bugUUID,
+		"previous_reporter_id": oldReporterID,
+		"new_reporter_id":      newReporter.ID,
+		"assigned_member_id":   afterState.AssignedMemberID,
+	})
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminHandler_TransferBugOwnership(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupAdminTestHandler(t)
+	admin := createTestAdmin(t, db)
+	app := createTestApplication(t, db)
+
+	createUser := func(email string) *models.User {
+		user := &models.User{ID: uuid.New(), Email: email, DisplayName: email}
+		require.NoError(t, db.Create(user).Error)
+		return user
+	}
+	leaver := createUser("leaver@example.com")
+	colleague := createUser("colleague@example.com")
+
+	transfer := func(bugID uuid.UUID, newReporterID uuid.UUID) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(mockAdminAuthMiddleware(admin.ID))
+		router.PATCH("/admin/bugs/:id/owner", handler.TransferBugOwnership)
+
+		body, err := json.Marshal(gin.H{"new_reporter_id": newReporterID, "reason": "Reporter left the company"})
+		require.NoError(t, err)
+		req, _ := http.NewRequest("PATCH", "/admin/bugs/"+bugID.String()+"/owner", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	notifiedUsers := func(bugID uuid.UUID) []uuid.UUID {
+		var notifications []models.Notification
+		require.NoError(t, db.Where("resource_id = ? AND type = ?", bugID, models.NotificationTypeBugOwnershipTransfer).
+			Order("created_at").Find(&notifications).Error)
+		var users []uuid.UUID
+		for _, notification := range notifications {
+			users = append(users, notification.UserID)
+		}
+		return users
+	}
+
+	t.Run("transfers the bug and its assignment", func(t *testing.T) {
+		bug := createTestBugReport(t, db, app, leaver)
+		require.NoError(t, db.Model(bug).Update("assigned_member_id", leaver.ID).Error)
+
+		w := transfer(bug.ID, colleague.ID)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var reloaded models.BugReport
+		require.NoError(t, db.First(&reloaded, "id = ?", bug.ID).Error)
+		assert.Equal(t, colleague.ID, *reloaded.ReporterID)
+		assert.Equal(t, colleague.ID, *reloaded.AssignedMemberID)
+
+		// Both reporters are told
+		assert.ElementsMatch(t, []uuid.UUID{leaver.ID, colleague.ID}, notifiedUsers(bug.ID))
+
+		// The audit log records both owners
+		var log models.AuditLog
+		require.NoError(t, db.Where("action = ? AND resource_id = ?", models.AuditActionBugOwnerTransfer, bug.ID).First(&log).Error)
+		assert.Equal(t, admin.ID, log.UserID)
+		assert.Contains(t, log.Details, "Reporter left the company")
+
+		var before, after bugOwnerAuditState
+		require.NotNil(t, log.BeforeState)
+		require.NotNil(t, log.AfterState)
+		require.NoError(t, json.Unmarshal(*log.BeforeState, &before))
+		require.NoError(t, json.Unmarshal(*log.AfterState, &after))
+		assert.Equal(t, leaver.ID, *before.ReporterID)
+		assert.Equal(t, colleague.ID, *after.ReporterID)
+		assert.Equal(t, colleague.ID, *after.AssignedMemberID)
+	})
+
+	t.Run("keeps other assignees", func(t *testing.T) {
+		bug := createTestBugReport(t, db, app, colleague)
+		require.NoError(t, db.Model(bug).Update("assigned_member_id", admin.ID).Error)
+
+		w := transfer(bug.ID, leaver.ID)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var reloaded models.BugReport
+		require.NoError(t, db.First(&reloaded, "id = ?", bug.ID).Error)
+		assert.Equal(t, leaver.ID, *reloaded.ReporterID)
+		assert.Equal(t, admin.ID, *reloaded.AssignedMemberID)
+	})
+
+	t.Run("transfers bugs of deleted accounts", func(t *testing.T) {
+		departed := createUser("departed@example.com")
+		bug := createTestBugReport(t, db, app, departed)
+		require.NoError(t, db.Delete(departed).Error)
+
+		w := transfer(bug.ID, colleague.ID)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, []uuid.UUID{colleague.ID}, notifiedUsers(bug.ID))
+	})
+
+	t.Run("rejects deleted and current reporters", func(t *testing.T) {
+		deleted := createUser("deleted@example.com")
+		require.NoError(t, db.Delete(deleted).Error)
+		bug := createTestBugReport(t, db, app, colleague)
+
+		w := transfer(bug.ID, deleted.ID)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "USER_NOT_FOUND")
+
+		w = transfer(bug.ID, colleague.ID)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "SAME_OWNER")
+
+		var reloaded models.BugReport
+		require.NoError(t, db.First(&reloaded, "id = ?", bug.ID).Error)
+		assert.Equal(t, colleague.ID, *reloaded.ReporterID)
+		assert.Empty(t, notifiedUsers(bug.ID))
+
+		w = transfer(uuid.New(), colleague.ID)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "BUG_NOT_FOUND")
+	})
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/middleware"
+	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AdminVerifyCompanyRequest represents the request to verify a company manually
+type AdminVerifyCompanyRequest struct {
+	Reason string `json:"reason" binding:"required,min=1,max=500"`
+	// AdminEmailProvided is the company contact who confirmed ownership outside
+	// the email flow, if any
+	AdminEmailProvided string `json:"admin_email_provided" binding:"omitempty,email,max=255"`
+}
+
+// AdminVerifyCompany marks a company as verified without the email flow, for
+// companies whose mail server never delivers the verification email
+func (h *AdminHandler) AdminVerifyCompany(c *gin.Context) {
+	companyUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid company ID format").Response(c)
+		return
+	}
+
+	if !middleware.IsCurrentUserAdmin(c) {
+		errors.ErrInsufficientPrivileges.Response(c)
+		return
+	}
+
+	userIDStr, _ := middleware.GetCurrentUserID(c)
+	adminID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		errors.ErrUnauthorized.Response(c)
+		return
+	}
+
+	var req AdminVerifyCompanyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+
+	var company models.Company
+	if err := h.db.First(&company, "id = ?", companyUUID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrCompanyNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch company").Response(c)
+		return
+	}
+
+	if company.IsVerified {
+		errors.ErrAlreadyVerified.Response(c)
+		return
+	}
+
+	beforeState := newCompanyVerificationAuditState(&company)
+	now := time.Now()
+	updates := map[string]interface{}{
+		"is_verified":                   true,
+		"verified_at":                   now,
+		"verification_token":            nil,
+		"verification_token_expires_at": nil,
+		"admin_verified":                true,
+		"admin_verified_by":             adminID,
+	}
+	details := fmt.Sprintf("Company verified by an administrator. Reason: %s", req.Reason)
+	if req.AdminEmailProvided != "" {
+		updates["verification_email"] = req.AdminEmailProvided
+		details += fmt.Sprintf(". Contact: %s", req.AdminEmailProvided)
+	}
+
+	// The verification and its audit entry are saved together so a manual
+	// verification is never left unrecorded
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&company).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		afterState := newCompanyVerificationAuditState(&company)
+		return createAuditLog(tx, c, models.AuditActionCompanyVerify, models.AuditResourceCompany, &companyUUID, details, beforeState, afterState)
+	})
+	if err != nil {
+		errors.ErrVerificationFailed.Response(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Company verified successfully",
+		"company": company,
+	})
+}
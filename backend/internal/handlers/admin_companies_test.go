@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminHandler_AdminVerifyCompany(t *testing.T) {
+	handler, db := setupAdminTestHandler(t)
+	companyHandler := NewCompanyHandler(db, nil)
+	admin := createTestAdmin(t, db)
+	user := createTestUser(t, db)
+
+	// A company stuck in the email flow with a token that never arrived
+	token := "undelivered-verification-token"
+	expiresAt := time.Now().Add(models.CompanyVerificationTokenTTL)
+	company := &models.Company{
+		ID:                         uuid.New(),
+		Name:                       "Unreliable Mail Inc",
+		Domain:                     "unreliable-mail.com",
+		VerificationToken:          &token,
+		VerificationTokenExpiresAt: &expiresAt,
+	}
+	require.NoError(t, db.Create(company).Error)
+
+	gin.SetMode(gin.TestMode)
+	newRouter := func(auth gin.HandlerFunc) *gin.Engine {
+		router := gin.New()
+		router.Use(auth)
+		router.POST("/admin/companies/:id/verify", handler.AdminVerifyCompany)
+		router.POST("/companies/:id/verify", companyHandler.CompleteCompanyVerification)
+		router.GET("/companies/:id", companyHandler.GetCompany)
+		return router
+	}
+	adminRouter := newRouter(mockAdminAuthMiddleware(admin.ID))
+	userRouter := newRouter(mockAuthMiddleware(user.ID))
+
+	request := func(router *gin.Engine, method, path string, body interface{}) (int, map[string]interface{}) {
+		var reader *bytes.Reader
+		if body != nil {
+			encoded, err := json.Marshal(body)
+			require.NoError(t, err)
+			reader = bytes.NewReader(encoded)
+		} else {
+			reader = bytes.NewReader(nil)
+		}
+
+		req, _ := http.NewRequest(method, path, reader)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w.Code, response
+	}
+	errorCode := func(response map[string]interface{}) interface{} {
+		return response["error"].(map[string]interface{})["code"]
+	}
+
+	verifyPath := "/admin/companies/" + company.ID.String() + "/verify"
+	validBody := gin.H{
+		"reason":               "Verification emails bounce; ownership confirmed by phone",
+		"admin_email_provided": "cto@unreliable-mail.com",
+	}
+
+	t.Run("rejects invalid requests", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			path           string
+			body           gin.H
+			expectedStatus int
+			expectedError  string
+		}{
+			{"invalid company ID", "/admin/companies/invalid-id/verify", validBody, http.StatusBadRequest, "INVALID_ID"},
+			{"missing reason", verifyPath, gin.H{"admin_email_provided": "cto@unreliable-mail.com"}, http.StatusBadRequest, "VALIDATION_ERROR"},
+			{"invalid contact email", verifyPath, gin.H{"reason": "Phone call", "admin_email_provided": "not-an-email"}, http.StatusBadRequest, "VALIDATION_ERROR"},
+			{"non-existent company", "/admin/companies/" + uuid.New().String() + "/verify", validBody, http.StatusNotFound, "COMPANY_NOT_FOUND"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				status, response := request(adminRouter, "POST", tt.path, tt.body)
+				assert.Equal(t, tt.expectedStatus, status)
+				assert.Equal(t, tt.expectedError, errorCode(response))
+			})
+		}
+	})
+
+	t.Run("non-admins cannot verify", func(t *testing.T) {
+		status, response := request(userRouter, "POST", verifyPath, validBody)
+		assert.Equal(t, http.StatusForbidden, status)
+		assert.Equal(t, "INSUFFICIENT_PRIVILEGES", errorCode(response))
+	})
+
+	t.Run("verifies the company", func(t *testing.T) {
+		status, response := request(adminRouter, "POST", verifyPath, validBody)
+		require.Equal(t, http.StatusOK, status)
+		verified := response["company"].(map[string]interface{})
+		assert.Equal(t, true, verified["is_verified"])
+		assert.Equal(t, true, verified["admin_verified"])
+		assert.Equal(t, admin.ID.String(), verified["admin_verified_by"])
+
+		var stored models.Company
+		require.NoError(t, db.First(&stored, "id = ?", company.ID).Error)
+		assert.True(t, stored.IsVerified)
+		assert.NotNil(t, stored.VerifiedAt)
+		assert.Nil(t, stored.VerificationToken)
+		assert.Nil(t, stored.VerificationTokenExpiresAt)
+		assert.True(t, stored.AdminVerified)
+		require.NotNil(t, stored.AdminVerifiedBy)
+		assert.Equal(t, admin.ID, *stored.AdminVerifiedBy)
+		require.NotNil(t, stored.VerificationEmail)
+		assert.Equal(t, "cto@unreliable-mail.com", *stored.VerificationEmail)
+
+		var auditLog models.AuditLog
+		require.NoError(t, db.Where("action = ? AND resource_id = ?", models.AuditActionCompanyVerify, company.ID).First(&auditLog).Error)
+		assert.Equal(t, models.AuditResourceCompany, auditLog.Resource)
+		assert.Equal(t, admin.ID, auditLog.UserID)
+		assert.Contains(t, auditLog.Details, "ownership confirmed by phone")
+		assert.Contains(t, auditLog.Details, "cto@unreliable-mail.com")
+
+		var before, after companyVerificationAuditState
+		require.NotNil(t, auditLog.BeforeState)
+		require.NotNil(t, auditLog.AfterState)
+		require.NoError(t, json.Unmarshal(*auditLog.BeforeState, &before))
+		require.NoError(t, json.Unmarshal(*auditLog.AfterState, &after))
+		assert.False(t, before.IsVerified)
+		assert.False(t, before.AdminVerified)
+		assert.True(t, after.IsVerified)
+		assert.True(t, after.AdminVerified)
+	})
+
+	t.Run("company profile shows the admin verification", func(t *testing.T) {
+		status, response := request(userRouter, "GET", "/companies/"+company.ID.String(), nil)
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, true, response["company"].(map[string]interface{})["admin_verified"])
+	})
+
+	t.Run("cannot verify twice", func(t *testing.T) {
+		status, response := request(adminRouter, "POST", verifyPath, validBody)
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "ALREADY_VERIFIED", errorCode(response))
+	})
+
+	t.Run("email flow cannot complete after admin verification", func(t *testing.T) {
+		status, response := request(userRouter, "POST", "/companies/"+company.ID.String()+"/verify", gin.H{"token": token})
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "ALREADY_VERIFIED", errorCode(response))
+
+		var members int64
+		require.NoError(t, db.Model(&models.CompanyMember{}).Where("company_id = ?", company.ID).Count(&members).Error)
+		assert.Zero(t, members)
+	})
+
+	t.Run("self-service verification is not an admin verification", func(t *testing.T) {
+		selfVerified := createTestVerifiedCompany(t, db)
+
+		status, response := request(userRouter, "GET", "/companies/"+selfVerified.ID.String(), nil)
+		require.Equal(t, http.StatusOK, status)
+		profile := response["company"].(map[string]interface{})
+		assert.Equal(t, true, profile["is_verified"])
+		assert.Equal(t, false, profile["admin_verified"])
+		assert.NotContains(t, profile, "admin_verified_by")
+	})
+}
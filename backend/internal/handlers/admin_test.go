@@ -2,11 +2,15 @@ package handlers
 
 import (
 	"bytes"
+	"context"
+	"encoding/csv"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"bugrelay-backend/internal/middleware"
 	"bugrelay-backend/internal/models"
 
 	"github.com/gin-gonic/gin"
@@ -32,9 +36,25 @@ func setupAdminTestDB(t *testing.T) *gorm.DB {
 		&models.BugReport{},
 		&models.BugVote{},
 		&models.Comment{},
+		&models.CommentEdit{},
+		&models.BugEditHistory{},
 		&models.CompanyMember{},
 		&models.FileAttachment{},
 		&models.AuditLog{},
+		&models.CustomFieldSchema{},
+		&models.BugAssignmentRule{},
+		&models.CompanyWebhook{},
+		&models.OutboxEvent{},
+		&models.CompanyInvitation{},
+		&models.UserBlock{},
+		&models.NotificationPreferences{},
+		&models.SecurityEvent{},
+		&models.ModerationQueueEntry{},
+		&models.BugEvent{},
+		&models.SearchQuery{},
+		&models.Notification{},
+		&models.BugSubscription{},
+		&models.IPBlock{},
 	)
 	require.NoError(t, err)
 
@@ -44,7 +64,7 @@ func setupAdminTestDB(t *testing.T) *gorm.DB {
 // setupAdminTestHandler creates an admin handler with test database
 func setupAdminTestHandler(t *testing.T) (*AdminHandler, *gorm.DB) {
 	db := setupAdminTestDB(t)
-	handler := NewAdminHandler(db)
+	handler := NewAdminHandler(db, nil)
 	return handler, db
 }
 
@@ -129,6 +149,60 @@ func TestAdminHandler_GetAdminDashboard(t *testing.T) {
 	assert.Contains(t, stats, "recent_activity")
 }
 
+func TestAdminHandler_GetAdminDashboard_Parallel(t *testing.T) {
+	handler, db := setupAdminTestHandler(t)
+	handler.SetParallelDashboardQueries(true)
+
+	// In-memory SQLite databases are per-connection, so share a single one
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	admin := createTestAdmin(t, db)
+	user := createTestUser(t, db)
+	_ = createTestVerifiedCompany(t, db)
+	app := createTestApplication(t, db)
+	_ = createTestBugReport(t, db, app, user)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(mockAdminAuthMiddleware(admin.ID))
+	router.GET("/admin/dashboard", handler.GetAdminDashboard)
+
+	t.Run("returns correct counts", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/admin/dashboard", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+		stats := response["stats"].(map[string]interface{})
+		assert.Equal(t, float64(1), stats["total_bugs"])
+		assert.Equal(t, float64(1), stats["open_bugs"])
+		assert.Equal(t, float64(2), stats["total_users"])
+		assert.Equal(t, float64(1), stats["total_companies"])
+		assert.Equal(t, float64(1), stats["verified_companies"])
+	})
+
+	t.Run("failing query returns 500", func(t *testing.T) {
+		require.NoError(t, db.Migrator().DropTable(&models.Company{}))
+
+		req, _ := http.NewRequest("GET", "/admin/dashboard", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		errorData := response["error"].(map[string]interface{})
+		assert.Equal(t, "QUERY_FAILED", errorData["code"])
+	})
+}
+
 func TestAdminHandler_ListBugsForModeration(t *testing.T) {
 	handler, db := setupAdminTestHandler(t)
 	admin := createTestAdmin(t, db)
@@ -635,6 +709,120 @@ func TestAdminHandler_GetAuditLogs(t *testing.T) {
 	}
 }
 
+func TestAdminHandler_GetAuditLogs_Filters(t *testing.T) {
+	handler, db := setupAdminTestHandler(t)
+	admin := createTestAdmin(t, db)
+
+	base := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	ipAddress := "10.0.0.1"
+	bugID := uuid.New()
+	entries := []models.AuditLog{
+		{Action: models.AuditActionBugFlag, Resource: models.AuditResourceBug, ResourceID: &bugID, Details: "Before range", UserID: admin.ID, IPAddress: &ipAddress, CreatedAt: base.Add(-time.Hour)},
+		{Action: models.AuditActionBugFlag, Resource: models.AuditResourceBug, ResourceID: &bugID, Details: "At from_date, spam report", UserID: admin.ID, IPAddress: &ipAddress, CreatedAt: base},
+		{Action: models.AuditActionBugRemove, Resource: models.AuditResourceBug, ResourceID: &bugID, Details: "Inside range, duplicate", UserID: admin.ID, IPAddress: &ipAddress, CreatedAt: base.Add(12 * time.Hour)},
+		{Action: models.AuditActionBugRemove, Resource: models.AuditResourceBug, ResourceID: &bugID, Details: "At to_date, spam report", UserID: admin.ID, IPAddress: &ipAddress, CreatedAt: base.Add(24 * time.Hour)},
+	}
+	require.NoError(t, db.Create(&entries).Error)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(mockAdminAuthMiddleware(admin.ID))
+	router.GET("/admin/audit-logs", handler.GetAuditLogs)
+
+	fromDate := base.Format(time.RFC3339)
+	toDate := base.Add(24 * time.Hour).Format(time.RFC3339)
+
+	getDetails := func(t *testing.T, query string) []string {
+		req, _ := http.NewRequest("GET", "/admin/audit-logs?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response auditLogPage
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+		details := make([]string, 0, len(response.Logs))
+		for _, log := range response.Logs {
+			details = append(details, log.Details)
+		}
+		return details
+	}
+
+	t.Run("from_date is inclusive and to_date is exclusive", func(t *testing.T) {
+		details := getDetails(t, "from_date="+fromDate+"&to_date="+toDate+"&sort=asc")
+		assert.Equal(t, []string{"At from_date, spam report", "Inside range, duplicate"}, details)
+	})
+
+	t.Run("search matches details case-insensitively", func(t *testing.T) {
+		details := getDetails(t, "search=SPAM&sort=asc")
+		assert.Equal(t, []string{"At from_date, spam report", "At to_date, spam report"}, details)
+	})
+
+	t.Run("sort descending by default", func(t *testing.T) {
+		details := getDetails(t, "search=range")
+		assert.Equal(t, []string{"Inside range, duplicate", "Before range"}, details)
+	})
+
+	t.Run("rejects invalid ranges and parameters", func(t *testing.T) {
+		for query, code := range map[string]string{
+			"from_date=" + toDate + "&to_date=" + fromDate:   "INVALID_DATE_RANGE",
+			"from_date=" + fromDate + "&to_date=" + fromDate: "INVALID_DATE_RANGE",
+			"from_date=2024-03-01":                           "INVALID_DATE",
+			"sort=sideways":                                  "INVALID_SORT",
+			"format=xml":                                     "INVALID_FORMAT",
+		} {
+			req, _ := http.NewRequest("GET", "/admin/audit-logs?"+query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+			assert.Contains(t, w.Body.String(), code, query)
+		}
+	})
+
+	t.Run("exports CSV", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/admin/audit-logs?format=csv&sort=asc&from_date="+fromDate+"&to_date="+toDate, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+
+		records, err := csv.NewReader(w.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+		assert.Equal(t, []string{"timestamp", "user_email", "action", "resource", "resource_id", "details", "ip_address"}, records[0])
+		assert.Equal(t, []string{
+			base.Format(time.RFC3339),
+			admin.Email,
+			models.AuditActionBugFlag,
+			models.AuditResourceBug,
+			bugID.String(),
+			"At from_date, spam report",
+			ipAddress,
+		}, records[1])
+		assert.Equal(t, "Inside range, duplicate", records[2][5])
+	})
+
+	t.Run("escapes formulas in CSV exports", func(t *testing.T) {
+		later := base.Add(48 * time.Hour)
+		require.NoError(t, db.Create(&models.AuditLog{
+			Action: models.AuditActionBugFlag, Resource: models.AuditResourceBug, ResourceID: &bugID,
+			Details: `=HYPERLINK("https://evil.example","Click")`, UserID: admin.ID, CreatedAt: later,
+		}).Error)
+
+		req, _ := http.NewRequest("GET", "/admin/audit-logs?format=csv&from_date="+later.Format(time.RFC3339)+"&to_date="+later.Add(time.Hour).Format(time.RFC3339), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		records, err := csv.NewReader(w.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, `'=HYPERLINK("https://evil.example","Click")`, records[1][5])
+	})
+}
+
 func TestAdminHandler_PermissionEnforcement(t *testing.T) {
 	handler, db := setupAdminTestHandler(t)
 	regularUser := createTestUser(t, db)
@@ -706,7 +894,7 @@ func TestAdminHandler_AuditLogCreation(t *testing.T) {
 	c.Request.Header.Set("User-Agent", "Test-Agent")
 
 	bugID := uuid.New()
-	err := handler.logAuditAction(c, models.AuditActionBugFlag, models.AuditResourceBug, &bugID, "Test audit log")
+	err := handler.logAuditAction(c, models.AuditActionBugFlag, models.AuditResourceBug, &bugID, "Test audit log", nil, nil)
 	assert.NoError(t, err)
 
 	// Verify audit log was created
@@ -718,4 +906,360 @@ func TestAdminHandler_AuditLogCreation(t *testing.T) {
 	assert.NotNil(t, auditLog.IPAddress)
 	assert.NotNil(t, auditLog.UserAgent)
 	assert.Equal(t, "Test-Agent", *auditLog.UserAgent)
-}
\ No newline at end of file
+}
+func TestAdminHandler_GetAuditLog(t *testing.T) {
+	handler, db := setupAdminTestHandler(t)
+	admin := createTestAdmin(t, db)
+	user := createTestUser(t, db)
+	app := createTestApplication(t, db)
+	bug := createTestBugReport(t, db, app, user)
+
+	db.Delete(&bug)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(mockAdminAuthMiddleware(admin.ID))
+	router.POST("/admin/bugs/:id/restore", handler.RestoreBug)
+	router.GET("/admin/audit-logs/:id", handler.GetAuditLog)
+
+	req, _ := http.NewRequest("POST", "/admin/bugs/"+bug.ID.String()+"/restore", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var auditLog models.AuditLog
+	require.NoError(t, db.Where("action = ?", models.AuditActionBugRestore).First(&auditLog).Error)
+
+	t.Run("returns entry with state diff", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/admin/audit-logs/"+auditLog.ID.String(), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Log     models.AuditLog             `json:"log"`
+			Changes map[string]AuditStateChange `json:"changes"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+		assert.Equal(t, auditLog.ID, response.Log.ID)
+		assert.NotNil(t, response.Log.BeforeState)
+		assert.NotNil(t, response.Log.AfterState)
+
+		require.Len(t, response.Changes, 1)
+		require.Contains(t, response.Changes, "deleted_at")
+		assert.NotNil(t, response.Changes["deleted_at"].Before)
+		assert.Nil(t, response.Changes["deleted_at"].After)
+	})
+
+	t.Run("invalid ID", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/admin/audit-logs/invalid-uuid", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("non-existent entry", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/admin/audit-logs/"+uuid.New().String(), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestAdminHandler_ListDeletedBugs(t *testing.T) {
+	handler, db := setupAdminTestHandler(t)
+	admin := createTestAdmin(t, db)
+	user := createTestUser(t, db)
+	app := createTestApplication(t, db)
+	activeBug := createTestBugReport(t, db, app, user)
+	deletedBug := createTestBugReport(t, db, app, user)
+
+	db.Delete(deletedBug)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(mockAdminAuthMiddleware(admin.ID))
+	router.GET("/admin/bugs/deleted", handler.ListDeletedBugs)
+
+	req, _ := http.NewRequest("GET", "/admin/bugs/deleted", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	bugs := response["bugs"].([]interface{})
+	require.Len(t, bugs, 1)
+	assert.Equal(t, deletedBug.ID.String(), bugs[0].(map[string]interface{})["id"])
+	assert.NotEqual(t, activeBug.ID.String(), bugs[0].(map[string]interface{})["id"])
+}
+
+func TestAdminHandler_PurgeDeletedBugs(t *testing.T) {
+	handler, db := setupAdminTestHandler(t)
+	admin := createTestAdmin(t, db)
+	user := createTestUser(t, db)
+	app := createTestApplication(t, db)
+
+	activeBug := createTestBugReport(t, db, app, user)
+	oldDeletedBug := createTestBugReport(t, db, app, user)
+	recentDeletedBug := createTestBugReport(t, db, app, user)
+
+	// Attach associations to the bugs
+	for _, bug := range []*models.BugReport{activeBug, oldDeletedBug} {
+		require.NoError(t, db.Create(&models.Comment{ID: uuid.New(), BugID: bug.ID, UserID: user.ID, Content: "Comment"}).Error)
+		require.NoError(t, db.Create(&models.BugVote{ID: uuid.New(), BugID: bug.ID, UserID: user.ID}).Error)
+		require.NoError(t, db.Create(&models.FileAttachment{ID: uuid.New(), BugID: bug.ID, Filename: "a.png", FileURL: "/uploads/" + bug.ID.String() + ".png"}).Error)
+	}
+	fileStorage := newMockStorage()
+	handler.SetStorage(fileStorage)
+
+	db.Delete(oldDeletedBug)
+	db.Delete(recentDeletedBug)
+	require.NoError(t, db.Unscoped().Model(oldDeletedBug).
+		Update("deleted_at", time.Now().Add(-60*24*time.Hour)).Error)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(gin.Recovery(), mockAdminAuthMiddleware(admin.ID))
+	router.DELETE("/admin/bugs/purge", handler.PurgeDeletedBugs)
+
+	// A panic rolls the first purge back and responds with 500
+	panicked := false
+	require.NoError(t, db.Callback().Delete().Before("gorm:delete").Register("test:panic_on_purge", func(tx *gorm.DB) {
+		if tx.Statement.Table == "bug_votes" && !panicked {
+			panicked = true
+			panic("purge failed")
+		}
+	}))
+	req, _ := http.NewRequest("DELETE", "/admin/bugs/purge?purge_after_days=30", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NoError(t, db.Unscoped().First(&models.BugReport{}, oldDeletedBug.ID).Error)
+	assert.Empty(t, fileStorage.deleted)
+
+	req, _ = http.NewRequest("DELETE", "/admin/bugs/purge?purge_after_days=30", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	// Only the old soft-deleted bug should be purged
+	assert.Equal(t, float64(1), response["purged_count"])
+
+	var remaining int64
+	db.Unscoped().Model(&models.BugReport{}).Where("id = ?", oldDeletedBug.ID).Count(&remaining)
+	assert.Equal(t, int64(0), remaining)
+
+	// Non-deleted and recently deleted bugs are untouched
+	assert.NoError(t, db.First(&models.BugReport{}, activeBug.ID).Error)
+	assert.NoError(t, db.Unscoped().First(&models.BugReport{}, recentDeletedBug.ID).Error)
+
+	var activeComments, purgedComments int64
+	db.Model(&models.Comment{}).Where("bug_id = ?", activeBug.ID).Count(&activeComments)
+	db.Model(&models.Comment{}).Where("bug_id = ?", oldDeletedBug.ID).Count(&purgedComments)
+	assert.Equal(t, int64(1), activeComments)
+	assert.Equal(t, int64(0), purgedComments)
+
+	var purgedVotes, purgedAttachments int64
+	db.Model(&models.BugVote{}).Where("bug_id = ?", oldDeletedBug.ID).Count(&purgedVotes)
+	db.Unscoped().Model(&models.FileAttachment{}).Where("bug_id = ?", oldDeletedBug.ID).Count(&purgedAttachments)
+	assert.Equal(t, int64(0), purgedVotes)
+	assert.Equal(t, int64(0), purgedAttachments)
+
+	// Only the purged bug's file is deleted from storage
+	assert.Equal(t, []string{"/uploads/" + oldDeletedBug.ID.String() + ".png"}, fileStorage.deleted)
+
+	// Verify audit log was created
+	var auditLog models.AuditLog
+	err = db.Where("action = ?", models.AuditActionBugPurge).First(&auditLog).Error
+	assert.NoError(t, err)
+	assert.Contains(t, auditLog.Details, "Purged 1")
+}
+
+func TestPercentageChange(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous int64
+		current  int64
+		expected float64
+	}{
+		{"both zero", 0, 0, 0},
+		{"previous zero", 0, 5, 100},
+		{"growth", 4, 6, 50},
+		{"decline", 4, 1, -75},
+		{"unchanged", 3, 3, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, percentageChange(tt.previous, tt.current))
+		})
+	}
+}
+
+func TestAdminHandler_GetAdminStats(t *testing.T) {
+	handler, db := setupAdminTestHandler(t)
+	admin := createTestAdmin(t, db)
+	user := createTestUser(t, db)
+	app := createTestApplication(t, db)
+
+	// Two bugs in the current period, one in the previous period
+	now := time.Now().UTC()
+	var bug *models.BugReport
+	for _, createdAt := range []time.Time{now.Add(-time.Hour), now.Add(-48 * time.Hour), now.Add(-10 * 24 * time.Hour)} {
+		bug = createTestBugReport(t, db, app, user)
+		require.NoError(t, db.Model(bug).UpdateColumn("created_at", createdAt).Error)
+	}
+	// Commenting makes the admin active, and the reporter is counted once
+	for _, commenter := range []uuid.UUID{admin.ID, user.ID} {
+		require.NoError(t, db.Create(&models.Comment{ID: uuid.New(), BugID: bug.ID, UserID: commenter, Content: "A comment", CreatedAt: now.Add(-time.Hour)}).Error)
+	}
+	require.NoError(t, db.Model(&models.User{}).Where("id IN ?", []uuid.UUID{admin.ID, user.ID}).
+		UpdateColumn("created_at", now.Add(-time.Hour)).Error)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(mockAdminAuthMiddleware(admin.ID))
+	router.GET("/admin/stats", handler.GetAdminStats)
+
+	t.Run("invalid period", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/admin/stats?period=1y", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("seven day period", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/admin/stats?period=7d", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response AdminStatsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+		assert.Equal(t, "7d", response.Period)
+		assert.Len(t, response.Series, 7)
+		assert.Equal(t, int64(2), response.Totals.BugsCreated)
+		assert.Equal(t, int64(2), response.Totals.NewUsers)
+		assert.Equal(t, int64(2), response.Totals.ActiveUsers)
+
+		// One bug previously, two now
+		assert.Equal(t, float64(100), response.Changes.BugsCreated)
+		// No users were created in the previous period
+		assert.Equal(t, float64(100), response.Changes.NewUsers)
+		// Nothing resolved in either period
+		assert.Equal(t, float64(0), response.Changes.BugsResolved)
+	})
+
+	t.Run("ninety day period uses weekly buckets", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/admin/stats?period=90d", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response AdminStatsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+		require.Len(t, response.Series, 13)
+		var bugsCreated int64
+		for _, bucket := range response.Series {
+			bugsCreated += bucket.BugsCreated
+		}
+		assert.Equal(t, int64(3), bugsCreated)
+		assert.Equal(t, int64(3), response.Totals.BugsCreated)
+		assert.Equal(t, int64(2), response.Totals.ActiveUsers)
+	})
+}
+
+func TestAdminHandler_ExemptUserFromRateLimits(t *testing.T) {
+	handler, db := setupAdminTestHandler(t)
+	admin := createTestAdmin(t, db)
+	user := createTestUser(t, db)
+
+	rateLimiter := middleware.NewRateLimiter(nil, 60)
+	handler.SetRateLimiter(rateLimiter)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(mockAdminAuthMiddleware(admin.ID))
+	router.POST("/admin/rate-limits/exempt", handler.ExemptUserFromRateLimits)
+
+	tests := []struct {
+		name           string
+		body           map[string]interface{}
+		expectedStatus int
+	}{
+		{
+			name: "valid exemption",
+			body: map[string]interface{}{
+				"user_id":          user.ID,
+				"duration_minutes": 30,
+				"reason":           "Bulk data migration",
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "missing reason",
+			body: map[string]interface{}{
+				"user_id":          user.ID,
+				"duration_minutes": 30,
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "duration out of range",
+			body: map[string]interface{}{
+				"user_id":          user.ID,
+				"duration_minutes": 0,
+				"reason":           "Bulk data migration",
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "non-existent user",
+			body: map[string]interface{}{
+				"user_id":          uuid.New(),
+				"duration_minutes": 30,
+				"reason":           "Bulk data migration",
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.body)
+			req, _ := http.NewRequest("POST", "/admin/rate-limits/exempt", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+
+	assert.True(t, rateLimiter.IsExempt(context.Background(), user.ID.String()))
+
+	var auditLog models.AuditLog
+	err := db.Where("action = ? AND resource_id = ?", models.AuditActionRateLimitExempt, user.ID).First(&auditLog).Error
+	assert.NoError(t, err)
+	assert.Equal(t, admin.ID, auditLog.UserID)
+	assert.Contains(t, auditLog.Details, "Bulk data migration")
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AdminUserResponse is a user as listed to administrators. Unlike models.User it
+// includes when the password was last changed, for security auditing.
+type AdminUserResponse struct {
+	ID                    uuid.UUID  `json:"id"`
+	Email                 string     `json:"email"`
+	DisplayName           string     `json:"display_name"`
+	AuthProvider          string     `json:"auth_provider"`
+	IsEmailVerified       bool       `json:"is_email_verified"`
+	IsAdmin               bool       `json:"is_admin"`
+	CreatedAt             time.Time  `json:"created_at"`
+	LastActiveAt          time.Time  `json:"last_active_at"`
+	LastPasswordChangedAt *time.Time `json:"last_password_changed_at"`
+}
+
+// ListUsers returns users with pagination, newest first
+//
+// @Summary     List users
+// @Description Lists users, newest first, including when each last changed their password.
+// @Tags        admin
+// @Produce     json
+// @Security    BearerAuth
+// @Param       page query int false "Page number" default(1)
+// @Param       limit query int false "Page size"
+// @Success     200 {object} object{users=[]AdminUserResponse,pagination=object}
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /admin/users [get]
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	if page <= 0 {
+		page = 1
+	}
+	limit = h.pagination.WithDefault(50).Limit(limit)
+
+	query := h.db.Model(&models.User{})
+
+	var total int64
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	var users []models.User
+	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&users).Error; err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to fetch users").Response(c)
+		return
+	}
+
+	responses := make([]AdminUserResponse, len(users))
+	for i, user := range users {
+		responses[i] = AdminUserResponse{
+			ID:                    user.ID,
+			Email:                 user.Email,
+			DisplayName:           user.DisplayName,
+			AuthProvider:          user.AuthProvider,
+			IsEmailVerified:       user.IsEmailVerified,
+			IsAdmin:               user.IsAdmin,
+			CreatedAt:             user.CreatedAt,
+			LastActiveAt:          user.LastActiveAt,
+			LastPasswordChangedAt: user.LastPasswordChangedAt,
+		}
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	c.JSON(http.StatusOK, gin.H{
+		"users": responses,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": totalPages,
+			"has_next":    page < totalPages,
+			"has_prev":    page > 1,
+		},
+	})
+}
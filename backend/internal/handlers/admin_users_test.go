@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminHandler_ListUsers(t *testing.T) {
+	handler, db := setupAdminTestHandler(t)
+	admin := createTestAdmin(t, db)
+
+	changedAt := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	user := &models.User{
+		ID:                    uuid.New(),
+		Email:                 "changed@example.com",
+		DisplayName:           "Changed Password",
+		LastPasswordChangedAt: &changedAt,
+	}
+	require.NoError(t, db.Create(user).Error)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin/users", handler.ListUsers)
+
+	req, _ := http.NewRequest("GET", "/admin/users", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Users      []AdminUserResponse    `json:"users"`
+		Pagination map[string]interface{} `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Users, 2)
+	assert.Equal(t, float64(2), response.Pagination["total"])
+
+	byID := make(map[uuid.UUID]AdminUserResponse)
+	for _, listed := range response.Users {
+		byID[listed.ID] = listed
+	}
+	require.NotNil(t, byID[user.ID].LastPasswordChangedAt)
+	assert.True(t, changedAt.Equal(*byID[user.ID].LastPasswordChangedAt))
+	assert.Nil(t, byID[admin.ID].LastPasswordChangedAt)
+	assert.Contains(t, w.Body.String(), `"last_password_changed_at":null`)
+}
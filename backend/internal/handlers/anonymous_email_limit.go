@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultAnonymousBugsPerEmailPerDay is how many bugs can be submitted anonymously
+// with the same contact email each day
+const defaultAnonymousBugsPerEmailPerDay = 3
+
+// anonymousEmailHash identifies a contact email in Redis without storing it
+func anonymousEmailHash(email string) string {
+	hash := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(hash[:])
+}
+
+// anonymousEmailLimitReached reports whether the contact email has been used for as
+// many anonymous bug submissions today as allowed, recording a security event if so.
+// Submissions are allowed when Redis is unavailable.
+func (h *BugHandler) anonymousEmailLimitReached(c *gin.Context, email string) bool {
+	if h.anonymousBugsPerEmailPerDay <= 0 {
+		return false
+	}
+
+	ctx := c.Request.Context()
+	count, err := h.cache.GetAnonymousEmailCount(ctx, anonymousEmailHash(email))
+	if err != nil {
+		logger.FromContext(ctx).Error("Failed to check anonymous submissions for contact email", err)
+		return false
+	}
+	if count < int64(h.anonymousBugsPerEmailPerDay) {
+		return false
+	}
+
+	if err := recordSecurityEvent(h.db, c, nil, models.SecurityEventAnonymousEmailLimit, map[string]interface{}{
+		"contact_email": strings.ToLower(email),
+		"count":         count,
+		"limit":         h.anonymousBugsPerEmailPerDay,
+	}); err != nil {
+		logger.FromContext(ctx).Error("Failed to record security event", err, logger.Fields{"event_type": models.SecurityEventAnonymousEmailLimit})
+	}
+	return true
+}
+
+// countAnonymousEmailSubmission counts a bug submitted anonymously with the contact
+// email towards its daily limit
+func (h *BugHandler) countAnonymousEmailSubmission(c *gin.Context, email string) {
+	if h.anonymousBugsPerEmailPerDay <= 0 {
+		return
+	}
+
+	ctx := c.Request.Context()
+	if _, err := h.cache.IncrementAnonymousEmailCount(ctx, anonymousEmailHash(email)); err != nil {
+		// Log cache error but don't fail the request
+		logger.FromContext(ctx).Error("Failed to count anonymous submission for contact email", err)
+	}
+}
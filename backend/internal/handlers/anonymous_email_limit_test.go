@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// submitBugWithContactEmail creates a bug through the handler with the given contact
+// email, anonymously unless userID is set
+func submitBugWithContactEmail(t *testing.T, handler *BugHandler, title, contactEmail string, userID *uuid.UUID) *httptest.ResponseRecorder {
+	body, err := json.Marshal(map[string]interface{}{
+		"title":            title,
+		"description":      "This is a bug description with sufficient length",
+		"application_name": "Contact Email App",
+		"contact_email":    contactEmail,
+	})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/bugs", bytes.NewBuffer(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	if userID != nil {
+		mockAuthMiddleware(*userID)(c)
+	}
+
+	handler.CreateBug(c)
+	return w
+}
+
+func TestBugHandler_CreateBug_AnonymousEmailLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupBugTestDB(t)
+	redisClient, mock := newMockRedisClient()
+	handler := NewBugHandler(db, redisClient)
+	user := createTestUser(t, db)
+
+	for i := 1; i <= defaultAnonymousBugsPerEmailPerDay; i++ {
+		w := submitBugWithContactEmail(t, handler, fmt.Sprintf("Anonymous bug number %d", i), "spammer@example.com", nil)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	key := "anon_email:" + anonymousEmailHash("spammer@example.com") + ":daily_count"
+	assert.Equal(t, fmt.Sprint(defaultAnonymousBugsPerEmailPerDay), mock.values[key])
+	assert.Equal(t, 24*time.Hour, mock.ttls[key])
+	assert.NotContains(t, key, "spammer")
+
+	t.Run("rejects submissions over the limit", func(t *testing.T) {
+		// The email is matched case-insensitively
+		w := submitBugWithContactEmail(t, handler, "One anonymous bug too many", "Spammer@Example.com", nil)
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Contains(t, w.Body.String(), "ANONYMOUS_EMAIL_LIMIT_REACHED")
+
+		var count int64
+		db.Model(&models.BugReport{}).Where("title = ?", "One anonymous bug too many").Count(&count)
+		assert.Zero(t, count)
+
+		var events []models.SecurityEvent
+		require.NoError(t, db.Where("event_type = ?", models.SecurityEventAnonymousEmailLimit).Find(&events).Error)
+		require.Len(t, events, 1)
+		assert.Contains(t, string(events[0].Details), "spammer@example.com")
+	})
+
+	t.Run("other emails and signed in users are not limited", func(t *testing.T) {
+		w := submitBugWithContactEmail(t, handler, "Another anonymous bug", "someone@example.com", nil)
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		w = submitBugWithContactEmail(t, handler, "Signed in user bug", "spammer@example.com", &user.ID)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("zero disables the limit", func(t *testing.T) {
+		handler.SetAnonymousBugsPerEmailPerDay(0)
+		defer handler.SetAnonymousBugsPerEmailPerDay(defaultAnonymousBugsPerEmailPerDay)
+
+		w := submitBugWithContactEmail(t, handler, "Unlimited anonymous bug", "spammer@example.com", nil)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+}
+
+func TestBugHandler_CreateBug_AnonymousEmailLimitRedisUnavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupBugTestDB(t)
+	// Nothing listens on port 1, so every Redis command fails
+	redisClient := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	defer redisClient.Close()
+	handler := NewBugHandler(db, redisClient)
+
+	for i := 1; i <= defaultAnonymousBugsPerEmailPerDay+1; i++ {
+		w := submitBugWithContactEmail(t, handler, fmt.Sprintf("Anonymous bug number %d", i), "spammer@example.com", nil)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	var events int64
+	db.Model(&models.SecurityEvent{}).Count(&events)
+	assert.Zero(t, events)
+}
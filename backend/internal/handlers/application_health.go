@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Health score weights: points subtracted per open bug of each priority, and
+// points added per bug resolved in the last 30 days
+const (
+	healthPenaltyCritical = 20
+	healthPenaltyHigh     = 5
+	healthPenaltyMedium   = 2
+	healthPenaltyLow      = 0.5
+	healthBonusResolved   = 2
+
+	// healthTrendWindow is the period the trend compares with the period before it
+	healthTrendWindow = 30 * 24 * time.Hour
+)
+
+// Health trends
+const (
+	HealthTrendImproving = "improving"
+	HealthTrendDeclining = "declining"
+	HealthTrendStable    = "stable"
+)
+
+// ApplicationHealthBreakdown holds the bug counts an application's health score is
+// computed from
+type ApplicationHealthBreakdown struct {
+	OpenCritical        int64 `json:"open_critical"`
+	OpenHigh            int64 `json:"open_high"`
+	OpenMedium          int64 `json:"open_medium"`
+	OpenLow             int64 `json:"open_low"`
+	ResolvedLast30Days  int64 `json:"resolved_last_30d"`
+	ResolvedPrior30Days int64 `json:"resolved_prior_30d"`
+	ReportedLast30Days  int64 `json:"reported_last_30d"`
+	ReportedPrior30Days int64 `json:"reported_prior_30d"`
+}
+
+// ApplicationHealthResponse represents the response for an application's health
+type ApplicationHealthResponse struct {
+	ApplicationID uuid.UUID                  `json:"application_id"`
+	Score         float64                    `json:"score"`
+	Grade         string                     `json:"grade"`
+	Trend         string                     `json:"trend"`
+	Breakdown     ApplicationHealthBreakdown `json:"breakdown"`
+}
+
+// healthScore computes a 0-100 score: 100 less a penalty per open bug by priority,
+// plus a bonus per bug resolved in the last 30 days
+func healthScore(breakdown ApplicationHealthBreakdown) float64 {
+	score := 100 -
+		float64(breakdown.OpenCritical)*healthPenaltyCritical -
+		float64(breakdown.OpenHigh)*healthPenaltyHigh -
+		float64(breakdown.OpenMedium)*healthPenaltyMedium -
+		float64(breakdown.OpenLow)*healthPenaltyLow +
+		float64(breakdown.ResolvedLast30Days)*healthBonusResolved
+
+	if score < 0 {
+		return 0
+	}
+	if score > 100 {
+		return 100
+	}
+	return score
+}
+
+// healthGrade returns the letter grade for a health score
+func healthGrade(score float64) string {
+	switch {
+	case score >= 90:
+		return "A"
+	case score >= 80:
+		return "B"
+	case score >= 70:
+		return "C"
+	case score >= 60:
+		return "D"
+	default:
+		return "F"
+	}
+}
+
+// healthTrend compares how many more bugs were resolved than reported in the last
+// 30 days with the 30 days before
+func healthTrend(breakdown ApplicationHealthBreakdown) string {
+	last := breakdown.ResolvedLast30Days - breakdown.ReportedLast30Days
+	prior := breakdown.ResolvedPrior30Days - breakdown.ReportedPrior30Days
+
+	switch {
+	case last > prior:
+		return HealthTrendImproving
+	case last < prior:
+		return HealthTrendDeclining
+	default:
+		return HealthTrendStable
+	}
+}
+
+// GetApplicationHealth returns a 0-100 health score for an application, derived
+// from its open bugs and recently resolved bugs, with a letter grade and a trend
+func (h *ApplicationHandler) GetApplicationHealth(c *gin.Context) {
+	applicationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid application ID format").Response(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	var cached ApplicationHealthResponse
+	if err := h.cache.GetApplicationHealth(ctx, applicationID.String(), &cached); err == nil {
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+
+	var application models.Application
+	if err := h.db.First(&application, "id = ?", applicationID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrApplicationNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch application").Response(c)
+		return
+	}
+
+	breakdown, err := h.applicationHealthBreakdown(applicationID, time.Now())
+	if err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to compute application health").Response(c)
+		return
+	}
+
+	score := healthScore(breakdown)
+	response := ApplicationHealthResponse{
+		ApplicationID: applicationID,
+		Score:         score,
+		Grade:         healthGrade(score),
+		Trend:         healthTrend(breakdown),
+		Breakdown:     breakdown,
+	}
+
+	if err := h.cache.SetApplicationHealth(ctx, applicationID.String(), response); err != nil {
+		// Log cache error but don't fail the request
+		logger.FromContext(ctx).Error("Failed to cache application health", err, logger.Fields{"application_id": applicationID.String()})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// applicationHealthBreakdown counts an application's open bugs by priority and the
+// bugs reported and resolved in the last two 30 day periods. Spam is not counted.
+func (h *ApplicationHandler) applicationHealthBreakdown(applicationID uuid.UUID, now time.Time) (ApplicationHealthBreakdown, error) {
+	var breakdown ApplicationHealthBreakdown
+
+	bugs := func() *gorm.DB {
+		return h.db.Model(&models.BugReport{}).Where("application_id = ? AND is_spam = ?", applicationID, false)
+	}
+
+	var openCounts []struct {
+		Priority string
+		Count    int64
+	}
+	if err := bugs().
+		Select("priority, COUNT(*) AS count").
+		Where("status IN ?", []string{models.BugStatusOpen, models.BugStatusReviewing}).
+		Group("priority").
+		Scan(&openCounts).Error; err != nil {
+		return breakdown, err
+	}
+	for _, count := range openCounts {
+		switch count.Priority {
+		case models.BugPriorityCritical:
+			breakdown.OpenCritical = count.Count
+		case models.BugPriorityHigh:
+			breakdown.OpenHigh = count.Count
+		case models.BugPriorityMedium:
+			breakdown.OpenMedium = count.Count
+		case models.BugPriorityLow:
+			breakdown.OpenLow = count.Count
+		}
+	}
+
+	lastStart := now.Add(-healthTrendWindow)
+	priorStart := lastStart.Add(-healthTrendWindow)
+
+	periodCounts := []struct {
+		column string
+		start  time.Time
+		end    time.Time
+		dest   *int64
+	}{
+		{"resolved_at", lastStart, now, &breakdown.ResolvedLast30Days},
+		{"resolved_at", priorStart, lastStart, &breakdown.ResolvedPrior30Days},
+		{"created_at", lastStart, now, &breakdown.ReportedLast30Days},
+		{"created_at", priorStart, lastStart, &breakdown.ReportedPrior30Days},
+	}
+	for _, period := range periodCounts {
+		if err := bugs().
+			Where(period.column+" >= ? AND "+period.column+" < ?", period.start, period.end).
+			Count(period.dest).Error; err != nil {
+			return breakdown, err
+		}
+	}
+
+	return breakdown, nil
+}
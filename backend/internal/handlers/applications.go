@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"bugrelay-backend/internal/cache"
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/middleware"
+	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// ApplicationHandler handles application-related HTTP requests
+type ApplicationHandler struct {
+	db              *gorm.DB
+	cache           *cache.CacheService
+	searchLanguages map[string]bool
+}
+
+// NewApplicationHandler creates a new application handler
+func NewApplicationHandler(db *gorm.DB, redisClient *redis.Client) *ApplicationHandler {
+	return &ApplicationHandler{
+		db:              db,
+		cache:           cache.NewCacheService(redisClient),
+		searchLanguages: map[string]bool{models.DefaultSearchLanguage: true},
+	}
+}
+
+// SetSearchLanguages sets the text search configurations applications can search
+// their bugs in, normally those installed in the database
+func (h *ApplicationHandler) SetSearchLanguages(languages []string) {
+	h.searchLanguages = make(map[string]bool, len(languages))
+	for _, language := range languages {
+		h.searchLanguages[language] = true
+	}
+}
+
+// UpdateApplicationRequest represents the request to update an application
+type UpdateApplicationRequest struct {
+	SearchLanguage *string `json:"search_language"`
+}
+
+// UpdateApplication updates an application's settings
+func (h *ApplicationHandler) UpdateApplication(c *gin.Context) {
+	var req UpdateApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+
+	if req.SearchLanguage != nil && !h.searchLanguages[*req.SearchLanguage] {
+		errors.ErrInvalidSearchLanguage.WithMessage(fmt.Sprintf("Unsupported search language: %s", *req.SearchLanguage)).Response(c)
+		return
+	}
+
+	application, ok := h.loadManagedApplication(c)
+	if !ok {
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if req.SearchLanguage != nil {
+		updates["search_language"] = *req.SearchLanguage
+	}
+
+	if len(updates) > 0 {
+		if err := h.db.Model(application).Updates(updates).Error; err != nil {
+			errors.ErrUpdateFailed.WithMessage("Failed to update application").Response(c)
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Application updated successfully",
+		"application": application,
+	})
+}
+
+// ArchiveApplication archives an application so it no longer accepts bug reports
+func (h *ApplicationHandler) ArchiveApplication(c *gin.Context) {
+	application, ok := h.loadManagedApplication(c)
+	if !ok {
+		return
+	}
+
+	if application.IsArchived {
+		errors.ErrAlreadyArchived.Response(c)
+		return
+	}
+
+	now := time.Now()
+	if err := h.db.Model(application).Updates(map[string]interface{}{
+		"is_archived": true,
+		"archived_at": now,
+	}).Error; err != nil {
+		errors.ErrUpdateFailed.WithMessage("Failed to archive application").Response(c)
+		return
+	}
+
+	application.IsArchived = true
+	application.ArchivedAt = &now
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Application archived successfully",
+		"application": application,
+	})
+}
+
+// UnarchiveApplication restores an archived application so it accepts bug reports again
+func (h *ApplicationHandler) UnarchiveApplication(c *gin.Context) {
+	application, ok := h.loadManagedApplication(c)
+	if !ok {
+		return
+	}
+
+	if !application.IsArchived {
+		errors.ErrNotArchived.Response(c)
+		return
+	}
+
+	if err := h.db.Model(application).Updates(map[string]interface{}{
+		"is_archived": false,
+		"archived_at": nil,
+	}).Error; err != nil {
+		errors.ErrUpdateFailed.WithMessage("Failed to unarchive application").Response(c)
+		return
+	}
+
+	application.IsArchived = false
+	application.ArchivedAt = nil
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Application unarchived successfully",
+		"application": application,
+	})
+}
+
+// loadManagedApplication loads the application from the request path and verifies
+// that the current user is an admin of the company that owns it. It writes the
+// error response and returns false if the application cannot be managed.
+func (h *ApplicationHandler) loadManagedApplication(c *gin.Context) (*models.Application, bool) {
+	applicationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid application ID format").Response(c)
+		return nil, false
+	}
+
+	// Get current user
+	userIDStr, _ := middleware.GetCurrentUserID(c)
+	currentUserID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		errors.ErrUnauthorized.Response(c)
+		return nil, false
+	}
+
+	var application models.Application
+	if err := h.db.First(&application, "id = ?", applicationID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrApplicationNotFound.Response(c)
+			return nil, false
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch application").Response(c)
+		return nil, false
+	}
+
+	// Only admins of the owning company can manage the application
+	var member models.CompanyMember
+	if application.CompanyID == nil || h.db.Where("company_id = ? AND user_id = ? AND role = ?",
+		*application.CompanyID, currentUserID, "admin").First(&member).Error != nil {
+		errors.ErrInsufficientPermissions.WithMessage("Only company admins can manage this application").Response(c)
+		return nil, false
+	}
+
+	return &application, true
+}
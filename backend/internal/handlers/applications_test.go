@@ -0,0 +1,403 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// setupApplicationTestHandler creates an application handler with test database
+func setupApplicationTestHandler(t *testing.T) (*ApplicationHandler, *gorm.DB) {
+	db := setupBugTestDB(t)
+	handler := NewApplicationHandler(db, nil)
+	return handler, db
+}
+
+// createTestCompanyApplication creates an application owned by the given company
+func createTestCompanyApplication(t *testing.T, db *gorm.DB, company *models.Company) *models.Application {
+	app := createTestApplication(t, db)
+	require.NoError(t, db.Model(app).Update("company_id", company.ID).Error)
+	app.CompanyID = &company.ID
+	return app
+}
+
+func TestApplicationHandler_ArchiveApplication(t *testing.T) {
+	handler, db := setupApplicationTestHandler(t)
+	user := createTestUser(t, db)
+	company := createTestCompany(t, db, true)
+	app := createTestCompanyApplication(t, db, company)
+	bug := createTestBugReport(t, db, app, user)
+
+	companyAdmin := &models.User{ID: uuid.New(), Email: "admin@testcompany.com", DisplayName: "Company Admin"}
+	require.NoError(t, db.Create(companyAdmin).Error)
+	createTestCompanyMember(t, db, company.ID, companyAdmin.ID, "admin")
+
+	companyMember := &models.User{ID: uuid.New(), Email: "member@testcompany.com", DisplayName: "Company Member"}
+	require.NoError(t, db.Create(companyMember).Error)
+	createTestCompanyMember(t, db, company.ID, companyMember.ID, "member")
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		userID         uuid.UUID
+		appID          string
+		path           string
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "company member cannot archive",
+			userID:         companyMember.ID,
+			appID:          app.ID.String(),
+			path:           "archive",
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "INSUFFICIENT_PERMISSIONS",
+		},
+		{
+			name:           "invalid application ID",
+			userID:         companyAdmin.ID,
+			appID:          "invalid-uuid",
+			path:           "archive",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "INVALID_ID",
+		},
+		{
+			name:           "non-existent application",
+			userID:         companyAdmin.ID,
+			appID:          uuid.New().String(),
+			path:           "archive",
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "APPLICATION_NOT_FOUND",
+		},
+		{
+			name:           "company admin archives application",
+			userID:         companyAdmin.ID,
+			appID:          app.ID.String(),
+			path:           "archive",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "archiving twice conflicts",
+			userID:         companyAdmin.ID,
+			appID:          app.ID.String(),
+			path:           "archive",
+			expectedStatus: http.StatusConflict,
+			expectedError:  "ALREADY_ARCHIVED",
+		},
+		{
+			name:           "company admin unarchives application",
+			userID:         companyAdmin.ID,
+			appID:          app.ID.String(),
+			path:           "unarchive",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(mockAuthMiddleware(tt.userID))
+			router.POST("/applications/:id/archive", handler.ArchiveApplication)
+			router.POST("/applications/:id/unarchive", handler.UnarchiveApplication)
+
+			req, _ := http.NewRequest("POST", "/applications/"+tt.appID+"/"+tt.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+			if tt.expectedError != "" {
+				errorData := response["error"].(map[string]interface{})
+				assert.Equal(t, tt.expectedError, errorData["code"])
+				return
+			}
+
+			application := response["application"].(map[string]interface{})
+			assert.Equal(t, tt.path == "archive", application["is_archived"])
+		})
+	}
+
+	// Archiving must not delete existing bugs
+	var existingBug models.BugReport
+	assert.NoError(t, db.First(&existingBug, bug.ID).Error)
+}
+
+func TestApplicationHandler_UpdateApplication(t *testing.T) {
+	handler, db := setupApplicationTestHandler(t)
+	handler.SetSearchLanguages([]string{"english", "french", "simple"})
+	company := createTestCompany(t, db, true)
+	app := createTestCompanyApplication(t, db, company)
+
+	companyAdmin := &models.User{ID: uuid.New(), Email: "admin@testcompany.com", DisplayName: "Company Admin"}
+	require.NoError(t, db.Create(companyAdmin).Error)
+	createTestCompanyMember(t, db, company.ID, companyAdmin.ID, "admin")
+
+	companyMember := &models.User{ID: uuid.New(), Email: "member@testcompany.com", DisplayName: "Company Member"}
+	require.NoError(t, db.Create(companyMember).Error)
+	createTestCompanyMember(t, db, company.ID, companyMember.ID, "member")
+
+	gin.SetMode(gin.TestMode)
+
+	update := func(userID uuid.UUID, body string) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(mockAuthMiddleware(userID))
+		router.PATCH("/applications/:id", handler.UpdateApplication)
+
+		req, _ := http.NewRequest("PATCH", "/applications/"+app.ID.String(), bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	searchLanguage := func() string {
+		var stored models.Application
+		require.NoError(t, db.First(&stored, "id = ?", app.ID).Error)
+		return stored.SearchLanguage
+	}
+
+	assert.Equal(t, models.DefaultSearchLanguage, searchLanguage())
+
+	t.Run("company member cannot update", func(t *testing.T) {
+		w := update(companyMember.ID, `{"search_language": "french"}`)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, models.DefaultSearchLanguage, searchLanguage())
+	})
+
+	t.Run("rejects languages the database does not support", func(t *testing.T) {
+		w := update(companyAdmin.ID, `{"search_language": "klingon"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_SEARCH_LANGUAGE")
+		assert.Equal(t, models.DefaultSearchLanguage, searchLanguage())
+	})
+
+	t.Run("admin sets the search language", func(t *testing.T) {
+		w := update(companyAdmin.ID, `{"search_language": "french"}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response struct {
+			Application models.Application `json:"application"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "french", response.Application.SearchLanguage)
+		assert.Equal(t, "french", searchLanguage())
+
+		// Fields left out are unchanged
+		w = update(companyAdmin.ID, `{}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "french", searchLanguage())
+	})
+}
+
+func TestBugHandler_CreateBug_ArchivedApplication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	user := createTestUser(t, db)
+	app := createTestApplication(t, db)
+	existingBug := createTestBugReport(t, db, app, user)
+
+	require.NoError(t, db.Model(app).Update("is_archived", true).Error)
+
+	body, err := json.Marshal(map[string]interface{}{
+		"title":            "Bug on retired app",
+		"description":      "This is a valid bug description with sufficient length",
+		"application_name": app.Name,
+	})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/bugs", bytes.NewBuffer(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	mockAuthMiddleware(user.ID)(c)
+
+	handler.CreateBug(c)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	errorData := response["error"].(map[string]interface{})
+	assert.Equal(t, "APPLICATION_ARCHIVED", errorData["code"])
+
+	// No new bug was created and the existing bug is untouched
+	var count int64
+	db.Model(&models.BugReport{}).Where("application_id = ?", app.ID).Count(&count)
+	assert.Equal(t, int64(1), count)
+	assert.NoError(t, db.First(&models.BugReport{}, existingBug.ID).Error)
+}
+
+func TestBugHandler_ListBugs_ArchivedApplication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	user := createTestUser(t, db)
+	app := createTestApplication(t, db)
+	createTestBugReport(t, db, app, user)
+
+	require.NoError(t, db.Model(app).Update("is_archived", true).Error)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/bugs", nil)
+
+	handler.ListBugs(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Bugs []models.BugReport `json:"bugs"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Bugs, 1)
+	assert.True(t, response.Bugs[0].Application.IsArchived)
+}
+
+func TestHealthScore(t *testing.T) {
+	tests := []struct {
+		name      string
+		breakdown ApplicationHealthBreakdown
+		expected  float64
+	}{
+		{name: "no bugs", breakdown: ApplicationHealthBreakdown{}, expected: 100},
+		{name: "one of each priority", breakdown: ApplicationHealthBreakdown{OpenCritical: 1, OpenHigh: 1, OpenMedium: 1, OpenLow: 1}, expected: 72.5},
+		{name: "low bugs count half a point", breakdown: ApplicationHealthBreakdown{OpenLow: 3}, expected: 98.5},
+		{name: "resolved bugs offset open bugs", breakdown: ApplicationHealthBreakdown{OpenHigh: 2, ResolvedLast30Days: 4}, expected: 98},
+		{name: "capped at 100", breakdown: ApplicationHealthBreakdown{OpenMedium: 1, ResolvedLast30Days: 10}, expected: 100},
+		{name: "exactly zero", breakdown: ApplicationHealthBreakdown{OpenCritical: 5}, expected: 0},
+		{name: "capped at 0", breakdown: ApplicationHealthBreakdown{OpenCritical: 6, ResolvedLast30Days: 1}, expected: 0},
+		{name: "only recent resolutions count", breakdown: ApplicationHealthBreakdown{OpenCritical: 1, ResolvedPrior30Days: 10}, expected: 80},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, healthScore(tt.breakdown))
+		})
+	}
+}
+
+func TestHealthGrade(t *testing.T) {
+	tests := []struct {
+		score    float64
+		expected string
+	}{
+		{100, "A"},
+		{90, "A"},
+		{89.5, "B"},
+		{80, "B"},
+		{79.5, "C"},
+		{70, "C"},
+		{69.5, "D"},
+		{60, "D"},
+		{59.5, "F"},
+		{0, "F"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, healthGrade(tt.score), "score %v", tt.score)
+	}
+}
+
+func TestHealthTrend(t *testing.T) {
+	assert.Equal(t, HealthTrendStable, healthTrend(ApplicationHealthBreakdown{}))
+	assert.Equal(t, HealthTrendImproving, healthTrend(ApplicationHealthBreakdown{ResolvedLast30Days: 3, ReportedLast30Days: 1, ResolvedPrior30Days: 1, ReportedPrior30Days: 1}))
+	assert.Equal(t, HealthTrendDeclining, healthTrend(ApplicationHealthBreakdown{ReportedLast30Days: 4, ReportedPrior30Days: 1}))
+	assert.Equal(t, HealthTrendStable, healthTrend(ApplicationHealthBreakdown{ResolvedLast30Days: 2, ReportedLast30Days: 2, ResolvedPrior30Days: 5, ReportedPrior30Days: 5}))
+}
+
+func TestApplicationHandler_GetApplicationHealth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupBugTestDB(t)
+	redisClient, mock := newMockRedisClient()
+	handler := NewApplicationHandler(db, redisClient)
+	user := createTestUser(t, db)
+	app := createTestApplication(t, db)
+
+	now := time.Now()
+	addBug := func(status, priority string, createdAt time.Time, resolvedAt *time.Time, spam bool) {
+		bug := createTestBugReport(t, db, app, user)
+		require.NoError(t, db.Model(bug).Updates(map[string]interface{}{
+			"status":      status,
+			"priority":    priority,
+			"created_at":  createdAt,
+			"resolved_at": resolvedAt,
+			"is_spam":     spam,
+		}).Error)
+	}
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+	resolvedDaysAgo := func(days int) *time.Time { resolved := daysAgo(days); return &resolved }
+
+	addBug(models.BugStatusOpen, models.BugPriorityCritical, daysAgo(5), nil, false)
+	addBug(models.BugStatusReviewing, models.BugPriorityHigh, daysAgo(40), nil, false)
+	addBug(models.BugStatusOpen, models.BugPriorityLow, daysAgo(45), nil, false)
+	addBug(models.BugStatusFixed, models.BugPriorityMedium, daysAgo(50), resolvedDaysAgo(10), false)
+	addBug(models.BugStatusFixed, models.BugPriorityMedium, daysAgo(50), resolvedDaysAgo(12), false)
+	addBug(models.BugStatusWontFix, models.BugPriorityHigh, daysAgo(55), resolvedDaysAgo(45), false)
+	// Spam is not counted
+	addBug(models.BugStatusOpen, models.BugPriorityCritical, daysAgo(1), nil, true)
+
+	getHealth := func(appID string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/applications/"+appID+"/health", nil)
+		c.Params = gin.Params{{Key: "id", Value: appID}}
+		handler.GetApplicationHealth(c)
+		return w
+	}
+
+	w := getHealth(app.ID.String())
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response ApplicationHealthResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, app.ID, response.ApplicationID)
+	assert.Equal(t, ApplicationHealthBreakdown{
+		OpenCritical:        1,
+		OpenHigh:            1,
+		OpenLow:             1,
+		ResolvedLast30Days:  2,
+		ResolvedPrior30Days: 1,
+		ReportedLast30Days:  1,
+		ReportedPrior30Days: 5,
+	}, response.Breakdown)
+	// 100 - 20 - 5 - 0.5 + 2*2
+	assert.Equal(t, 78.5, response.Score)
+	assert.Equal(t, "C", response.Grade)
+	assert.Equal(t, HealthTrendImproving, response.Trend)
+
+	// The result is cached for 30 minutes
+	keys := mock.keysWithPrefix("app:" + app.ID.String() + ":health")
+	require.Len(t, keys, 1)
+	assert.Equal(t, 30*time.Minute, mock.ttls[keys[0]])
+
+	addBug(models.BugStatusOpen, models.BugPriorityCritical, now, nil, false)
+	cached := getHealth(app.ID.String())
+	assert.Equal(t, w.Body.String(), cached.Body.String())
+
+	t.Run("errors", func(t *testing.T) {
+		w := getHealth("not-a-uuid")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_ID")
+
+		w = getHealth(uuid.New().String())
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "APPLICATION_NOT_FOUND")
+	})
+}
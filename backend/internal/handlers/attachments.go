@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"mime"
+	"os"
+	"path/filepath"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AttachmentHandler serves attachments stored on the local filesystem
+type AttachmentHandler struct {
+	local *storage.LocalBackend
+}
+
+// NewAttachmentHandler creates a new attachment handler
+func NewAttachmentHandler(local *storage.LocalBackend) *AttachmentHandler {
+	return &AttachmentHandler{
+		local: local,
+	}
+}
+
+// ServeAttachment streams a locally stored attachment so it can be previewed inline
+func (h *AttachmentHandler) ServeAttachment(c *gin.Context) {
+	filePath, err := h.local.FilePath(c.Param("filename"))
+	if err != nil {
+		errors.ErrInvalidFilename.Response(c)
+		return
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil || info.IsDir() {
+		errors.ErrAttachmentNotFound.Response(c)
+		return
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(filePath))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.File(filePath)
+}
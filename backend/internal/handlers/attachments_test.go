@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"bugrelay-backend/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachmentHandler_ServeAttachment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "crash.png"), []byte("\x89PNG\r\n\x1a\nimage-data"), 0o644))
+
+	handler := NewAttachmentHandler(storage.NewLocalBackend(dir))
+	router := gin.New()
+	router.GET(storage.LocalServePath+":filename", handler.ServeAttachment)
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "serves existing attachment",
+			path:           "/attachments/serve/crash.png",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing attachment",
+			path:           "/attachments/serve/missing.png",
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "ATTACHMENT_NOT_FOUND",
+		},
+		{
+			name:           "rejects path traversal",
+			path:           "/attachments/serve/..%5Csecrets.env",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "INVALID_FILENAME",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if tt.expectedError != "" {
+				var response map[string]interface{}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				errorData := response["error"].(map[string]interface{})
+				assert.Equal(t, tt.expectedError, errorData["code"])
+				return
+			}
+
+			assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+			assert.Equal(t, "public, max-age=3600", w.Header().Get("Cache-Control"))
+			assert.Equal(t, "\x89PNG\r\n\x1a\nimage-data", w.Body.String())
+		})
+	}
+}
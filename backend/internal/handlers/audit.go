@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"bugrelay-backend/internal/middleware"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// bugAuditState is the snapshot of a bug report stored in audit log states
+type bugAuditState struct {
+	ID                uuid.UUID  `json:"id"`
+	Title             string     `json:"title"`
+	Status            string     `json:"status"`
+	Priority          string     `json:"priority"`
+	AssignedCompanyID *uuid.UUID `json:"assigned_company_id"`
+	VoteCount         int        `json:"vote_count"`
+	CommentCount      int        `json:"comment_count"`
+	ResolvedAt        *time.Time `json:"resolved_at"`
+	DeletedAt         *time.Time `json:"deleted_at"`
+}
+
+// newBugAuditState captures the audited fields of a bug report
+func newBugAuditState(bug *models.BugReport) bugAuditState {
+	state := bugAuditState{
+		ID:                bug.ID,
+		Title:             bug.Title,
+		Status:            bug.Status,
+		Priority:          bug.Priority,
+		AssignedCompanyID: bug.AssignedCompanyID,
+		VoteCount:         bug.VoteCount,
+		CommentCount:      bug.CommentCount,
+		ResolvedAt:        bug.ResolvedAt,
+	}
+	if bug.DeletedAt.Valid {
+		deletedAt := bug.DeletedAt.Time
+		state.DeletedAt = &deletedAt
+	}
+	return state
+}
+
+// companyVerificationAuditState is the snapshot of a company's verification stored
+// in audit log states
+type companyVerificationAuditState struct {
+	ID              uuid.UUID  `json:"id"`
+	Domain          string     `json:"domain"`
+	IsVerified      bool       `json:"is_verified"`
+	VerifiedAt      *time.Time `json:"verified_at"`
+	AdminVerified   bool       `json:"admin_verified"`
+	AdminVerifiedBy *uuid.UUID `json:"admin_verified_by"`
+}
+
+// newCompanyVerificationAuditState captures the verification fields of a company
+func newCompanyVerificationAuditState(company *models.Company) companyVerificationAuditState {
+	return companyVerificationAuditState{
+		ID:              company.ID,
+		Domain:          company.Domain,
+		IsVerified:      company.IsVerified,
+		VerifiedAt:      company.VerifiedAt,
+		AdminVerified:   company.AdminVerified,
+		AdminVerifiedBy: company.AdminVerifiedBy,
+	}
+}
+
+// mergeAuditState is the snapshot of both bugs involved in a merge
+type mergeAuditState struct {
+	Source bugAuditState `json:"source"`
+	Target bugAuditState `json:"target"`
+}
+
+// createAuditLog records an action in the audit log, with optional before and after state snapshots
+func createAuditLog(db *gorm.DB, c *gin.Context, action, resource string, resourceID *uuid.UUID, details string, before, after interface{}) error {
+	userIDStr, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		return fmt.Errorf("user ID not found in context")
+	}
+
+	userUUID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return fmt.Errorf("invalid user ID: %v", err)
+	}
+
+	beforeState, err := marshalAuditState(before)
+	if err != nil {
+		return fmt.Errorf("failed to encode before state: %v", err)
+	}
+	afterState, err := marshalAuditState(after)
+	if err != nil {
+		return fmt.Errorf("failed to encode after state: %v", err)
+	}
+
+	// Get IP address and user agent
+	ipAddress := c.ClientIP()
+	userAgent := c.GetHeader("User-Agent")
+
+	auditLog := models.AuditLog{
+		Action:      action,
+		Resource:    resource,
+		ResourceID:  resourceID,
+		Details:     details,
+		BeforeState: beforeState,
+		AfterState:  afterState,
+		UserID:      userUUID,
+		IPAddress:   &ipAddress,
+		UserAgent:   &userAgent,
+	}
+
+	return db.Create(&auditLog).Error
+}
+
+// marshalAuditState encodes a state snapshot, returning nil when none was captured
+func marshalAuditState(state interface{}) (*datatypes.JSON, error) {
+	if state == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	encoded := datatypes.JSON(data)
+	return &encoded, nil
+}
+
+// AuditStateChange describes how a single field differs between audit log states
+type AuditStateChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// auditStateDiff returns the top-level fields that differ between two state snapshots
+func auditStateDiff(before, after *datatypes.JSON) (map[string]AuditStateChange, error) {
+	beforeFields := map[string]interface{}{}
+	afterFields := map[string]interface{}{}
+	if before != nil {
+		if err := json.Unmarshal(*before, &beforeFields); err != nil {
+			return nil, err
+		}
+	}
+	if after != nil {
+		if err := json.Unmarshal(*after, &afterFields); err != nil {
+			return nil, err
+		}
+	}
+
+	changes := make(map[string]AuditStateChange)
+	for field, beforeValue := range beforeFields {
+		afterValue := afterFields[field]
+		if !reflect.DeepEqual(beforeValue, afterValue) {
+			changes[field] = AuditStateChange{Before: beforeValue, After: afterValue}
+		}
+	}
+	for field, afterValue := range afterFields {
+		if _, seen := beforeFields[field]; !seen {
+			changes[field] = AuditStateChange{Before: nil, After: afterValue}
+		}
+	}
+
+	return changes, nil
+}
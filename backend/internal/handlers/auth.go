@@ -1,13 +1,18 @@
 package handlers
 
 import (
-	"fmt"
+	"context"
 	"net/http"
 	"strings"
 	"time"
 
 	"bugrelay-backend/internal/auth"
+	"bugrelay-backend/internal/email"
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/jobs"
+	"bugrelay-backend/internal/logger"
 	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -16,8 +21,10 @@ import (
 
 // AuthHandler handles authentication-related requests
 type AuthHandler struct {
-	db          *gorm.DB
-	authService *auth.Service
+	db              *gorm.DB
+	authService     *auth.Service
+	emailService    email.EmailService
+	backgroundQueue *jobs.Queue
 }
 
 // NewAuthHandler creates a new authentication handler
@@ -28,6 +35,39 @@ func NewAuthHandler(db *gorm.DB, authService *auth.Service) *AuthHandler {
 	}
 }
 
+// SetEmailService sets the service that sends verification and password reset
+// emails. Without one, new accounts are verified right away and reset tokens are
+// only logged, for development.
+func (h *AuthHandler) SetEmailService(emailService email.EmailService) {
+	h.emailService = emailService
+}
+
+// SetBackgroundQueue sets the queue account emails are sent on. Without one, or
+// when it is full, they are sent on their own goroutine.
+func (h *AuthHandler) SetBackgroundQueue(queue *jobs.Queue) {
+	h.backgroundQueue = queue
+}
+
+// sendEmailInBackground sends an account email off the request path, so responses
+// take as long whether or not an email is sent
+func (h *AuthHandler) sendEmailInBackground(name string, userID uuid.UUID, send func() error) {
+	task := jobs.Task{
+		Name: name,
+		Run: func(ctx context.Context) error {
+			return send()
+		},
+	}
+	if h.backgroundQueue != nil && h.backgroundQueue.Enqueue(task) {
+		return
+	}
+
+	go func() {
+		if err := send(); err != nil {
+			logger.Error("Failed to send account email", err, logger.Fields{"task": name, "user_id": userID.String()})
+		}
+	}()
+}
+
 // RegisterRequest represents the registration request payload
 type RegisterRequest struct {
 	Email       string `json:"email" binding:"required,email"`
@@ -51,12 +91,23 @@ type PasswordResetRequest struct {
 	Email string `json:"email" binding:"required,email"`
 }
 
+// ResendVerificationRequest represents the request for a new verification email
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
 // PasswordResetConfirmRequest represents the password reset confirmation payload
 type PasswordResetConfirmRequest struct {
 	Token       string `json:"token" binding:"required"`
 	NewPassword string `json:"new_password" binding:"required,min=8"`
 }
 
+// ChangePasswordRequest represents the change password payload
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=8"`
+}
+
 // UpdateProfileRequest represents the profile update request payload
 type UpdateProfileRequest struct {
 	DisplayName string  `json:"display_name,omitempty" binding:"omitempty,min=1,max=100"`
@@ -81,70 +132,61 @@ type UserResponse struct {
 	CreatedAt   time.Time `json:"createdAt"`
 }
 
+// logSecurityEvent records a security event, logging rather than failing the request on error
+func (h *AuthHandler) logSecurityEvent(c *gin.Context, userID *uuid.UUID, eventType string, details map[string]interface{}) {
+	if err := recordSecurityEvent(h.db, c, userID, eventType, details); err != nil {
+		logger.FromContext(c.Request.Context()).Error("Failed to record security event", err, logger.Fields{"event_type": eventType})
+	}
+}
+
 // Register handles user registration
+//
+// @Summary     Register an account
+// @Description Creates an account with an email and password. The email has to be verified before logging in, unless no email service is configured.
+// @Tags        auth
+// @Accept      json
+// @Produce     json
+// @Param       request body RegisterRequest true "Account details"
+// @Success     201 {object} object{code=string,data=AuthResponse}
+// @Failure     400 {object} errors.ErrorResponse "Invalid request or weak password"
+// @Failure     409 {object} errors.ErrorResponse "Email already registered"
+// @Failure     413 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /auth/register [post]
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_REQUEST",
-				"message":   "Invalid request data",
-				"details":   err.Error(),
-				"timestamp": time.Now(),
-			},
-		})
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrInvalidRequest.WithDetails(utils.FormatValidationErrors(err)).Response(c)
 		return
 	}
 
 	// Validate password strength
 	if err := h.authService.ValidatePasswordStrength(req.Password); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "WEAK_PASSWORD",
-				"message":   err.Error(),
-				"details":   err.Error(),
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrWeakPassword.WithMessage(err.Error()).WithDetails(err.Error()).Response(c)
 		return
 	}
 
 	// Check if user already exists
 	var existingUser models.User
 	if err := h.db.Where("email = ?", strings.ToLower(req.Email)).First(&existingUser).Error; err == nil {
-		c.JSON(http.StatusConflict, gin.H{
-			"error": gin.H{
-				"code":      "USER_EXISTS",
-				"message":   "User with this email already exists",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrUserExists.Response(c)
 		return
 	}
 
 	// Hash password
 	hashedPassword, err := h.authService.HashPassword(req.Password)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "HASH_FAILED",
-				"message":   "Failed to process password",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrHashFailed.Response(c)
 		return
 	}
 
 	// Generate email verification token
 	verificationToken, err := auth.GenerateSecureToken(32)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "TOKEN_GENERATION_FAILED",
-				"message":   "Failed to generate verification token",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrTokenGenerationFailed.WithMessage("Failed to generate verification token").Response(c)
 		return
 	}
 
@@ -160,32 +202,37 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		LastActiveAt:           time.Now(),
 	}
 
-	if err := h.db.Create(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "USER_CREATION_FAILED",
-				"message":   "Failed to create user account",
-				"timestamp": time.Now(),
-			},
-		})
+	// Every new account starts with the default notification preferences
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		preferences := models.DefaultNotificationPreferences(user.ID)
+		return tx.Create(&preferences).Error
+	})
+	if err != nil {
+		errors.ErrUserCreationFailed.Response(c)
 		return
 	}
 
-	// TODO: Send verification email (implement email service)
-	// For now, we'll auto-verify for development
-	user.IsEmailVerified = true
-	user.EmailVerificationToken = nil
-	h.db.Save(&user)
+	if h.emailService != nil {
+		// The account can sign in once the address is verified. A failed send is
+		// only logged, since the account already exists; another email can be
+		// requested with ResendVerification.
+		if err := h.emailService.SendEmailVerification(user.Email, verificationToken); err != nil {
+			logger.FromContext(c.Request.Context()).Error("Failed to send verification email", err, logger.Fields{"user_id": user.ID.String()})
+		}
+	} else {
+		// Without an email service, auto-verify for development
+		user.IsEmailVerified = true
+		user.EmailVerificationToken = nil
+		h.db.Save(&user)
+	}
 
 	// Generate tokens
-	accessToken, refreshToken, err := h.authService.GenerateTokens(user.ID.String(), user.Email, user.IsAdmin)
+	accessToken, refreshToken, err := h.authService.GenerateTokens(user.ID.String(), user.Email, user.IsAdmin, c.ClientIP())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "TOKEN_GENERATION_FAILED",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrTokenGenerationFailed.WithMessage("Failed to generate authentication tokens").Response(c)
 		return
 	}
 
@@ -210,66 +257,59 @@ func (h *AuthHandler) Register(c *gin.Context) {
 }
 
 // Login handles user login
+//
+// @Summary     Log in
+// @Description Exchanges an email and password for an access token and a refresh token.
+// @Tags        auth
+// @Accept      json
+// @Produce     json
+// @Param       request body LoginRequest true "Credentials"
+// @Success     200 {object} object{code=string,message=string,data=AuthResponse}
+// @Failure     400 {object} errors.ErrorResponse
+// @Failure     401 {object} errors.ErrorResponse "Invalid credentials or email not verified"
+// @Failure     413 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_REQUEST",
-				"message":   "Invalid request data",
-				"details":   err.Error(),
-				"timestamp": time.Now(),
-			},
-		})
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrInvalidRequest.WithDetails(utils.FormatValidationErrors(err)).Response(c)
 		return
 	}
 
 	// Find user by email
 	var user models.User
 	if err := h.db.Where("email = ?", strings.ToLower(req.Email)).First(&user).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_CREDENTIALS",
-				"message":   "Invalid email or password",
-				"timestamp": time.Now(),
-			},
+		h.logSecurityEvent(c, nil, models.SecurityEventFailedLogin, map[string]interface{}{
+			"email":  strings.ToLower(req.Email),
+			"reason": "unknown_email",
 		})
+		errors.ErrInvalidCredentials.Response(c)
 		return
 	}
 
 	// Check if user uses email authentication
 	if user.AuthProvider != "email" || user.PasswordHash == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_AUTH_METHOD",
-				"message":   "This account uses a different authentication method",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrInvalidAuthMethod.Response(c)
 		return
 	}
 
 	// Validate password
 	if err := h.authService.ValidatePassword(req.Password, *user.PasswordHash); err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_CREDENTIALS",
-				"message":   "Invalid email or password",
-				"timestamp": time.Now(),
-			},
+		h.logSecurityEvent(c, &user.ID, models.SecurityEventFailedLogin, map[string]interface{}{
+			"email":  user.Email,
+			"reason": "invalid_password",
 		})
+		errors.ErrInvalidCredentials.Response(c)
 		return
 	}
 
 	// Check if email is verified
 	if !user.IsEmailVerified {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": gin.H{
-				"code":      "EMAIL_NOT_VERIFIED",
-				"message":   "Please verify your email address before logging in",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrEmailNotVerified.Response(c)
 		return
 	}
 
@@ -278,15 +318,9 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	h.db.Save(&user)
 
 	// Generate tokens
-	accessToken, refreshToken, err := h.authService.GenerateTokens(user.ID.String(), user.Email, user.IsAdmin)
+	accessToken, refreshToken, err := h.authService.GenerateTokens(user.ID.String(), user.Email, user.IsAdmin, c.ClientIP())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "TOKEN_GENERATION_FAILED",
-				"message":   "Failed to generate authentication tokens",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrTokenGenerationFailed.WithMessage("Failed to generate authentication tokens").Response(c)
 		return
 	}
 
@@ -312,30 +346,36 @@ func (h *AuthHandler) Login(c *gin.Context) {
 }
 
 // RefreshToken handles token refresh
+//
+// @Summary     Refresh tokens
+// @Description Exchanges a refresh token for a new access token and refresh token.
+// @Tags        auth
+// @Accept      json
+// @Produce     json
+// @Param       request body RefreshTokenRequest true "Refresh token"
+// @Success     200 {object} object{message=string,data=object{access_token=string,refresh_token=string,expires_in=int}}
+// @Failure     400 {object} errors.ErrorResponse
+// @Failure     401 {object} errors.ErrorResponse "Invalid or expired refresh token"
+// @Failure     413 {object} errors.ErrorResponse
+// @Router      /auth/refresh [post]
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	var req RefreshTokenRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_REQUEST",
-				"message":   "Invalid request data",
-				"details":   err.Error(),
-				"timestamp": time.Now(),
-			},
-		})
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrInvalidRequest.WithDetails(utils.FormatValidationErrors(err)).Response(c)
 		return
 	}
 
 	// Refresh tokens
-	accessToken, refreshToken, err := h.authService.RefreshTokens(req.RefreshToken)
+	accessToken, refreshToken, err := h.authService.RefreshTokens(req.RefreshToken, c.ClientIP())
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_REFRESH_TOKEN",
-				"message":   "Invalid or expired refresh token",
-				"timestamp": time.Now(),
-			},
+		h.logSecurityEvent(c, nil, models.SecurityEventTokenRejected, map[string]interface{}{
+			"token_type": "refresh",
+			"reason":     err.Error(),
 		})
+		errors.ErrInvalidRefreshToken.Response(c)
 		return
 	}
 
@@ -350,29 +390,28 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 }
 
 // Logout handles user logout
+//
+// @Summary     Log out
+// @Description Revokes the access token the request is made with.
+// @Tags        auth
+// @Produce     json
+// @Security    BearerAuth
+// @Success     200 {object} object{message=string}
+// @Failure     400 {object} errors.ErrorResponse "Missing or malformed token"
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /auth/logout [post]
 func (h *AuthHandler) Logout(c *gin.Context) {
 	// Get token from header
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "MISSING_TOKEN",
-				"message":   "Authorization token is required",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrMissingToken.WithMessage("Authorization token is required").Response(c)
 		return
 	}
 
 	parts := strings.SplitN(authHeader, " ", 2)
 	if len(parts) != 2 || parts[0] != "Bearer" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_TOKEN_FORMAT",
-				"message":   "Invalid authorization header format",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrInvalidTokenFormat.Response(c)
 		return
 	}
 
@@ -380,13 +419,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 
 	// Revoke the token
 	if err := h.authService.RevokeToken(token); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "LOGOUT_FAILED",
-				"message":   "Failed to logout",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrLogoutFailed.Response(c)
 		return
 	}
 
@@ -396,28 +429,26 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 }
 
 // LogoutAll handles logout from all devices
+//
+// @Summary     Log out everywhere
+// @Description Revokes every token issued to the current user.
+// @Tags        auth
+// @Produce     json
+// @Security    BearerAuth
+// @Success     200 {object} object{message=string}
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /auth/logout-all [post]
 func (h *AuthHandler) LogoutAll(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": gin.H{
-				"code":      "UNAUTHORIZED",
-				"message":   "User not authenticated",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrUnauthorized.WithMessage("User not authenticated").Response(c)
 		return
 	}
 
 	// Revoke all user tokens
 	if err := h.authService.RevokeAllUserTokens(userID.(string)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "LOGOUT_ALL_FAILED",
-				"message":   "Failed to logout from all devices",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrLogoutAllFailed.Response(c)
 		return
 	}
 
@@ -427,17 +458,25 @@ func (h *AuthHandler) LogoutAll(c *gin.Context) {
 }
 
 // RequestPasswordReset handles password reset requests
+//
+// @Summary     Request a password reset
+// @Description Emails a password reset link. The response is the same, and takes as long, whether or not the email is registered.
+// @Tags        auth
+// @Accept      json
+// @Produce     json
+// @Param       request body PasswordResetRequest true "Account email"
+// @Success     200 {object} object{message=string}
+// @Failure     400 {object} errors.ErrorResponse
+// @Failure     413 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /auth/password-reset [post]
 func (h *AuthHandler) RequestPasswordReset(c *gin.Context) {
 	var req PasswordResetRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_REQUEST",
-				"message":   "Invalid request data",
-				"details":   err.Error(),
-				"timestamp": time.Now(),
-			},
-		})
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrInvalidRequest.WithDetails(utils.FormatValidationErrors(err)).Response(c)
 		return
 	}
 
@@ -462,35 +501,34 @@ func (h *AuthHandler) RequestPasswordReset(c *gin.Context) {
 	// Generate password reset token
 	resetToken, err := auth.GenerateSecureToken(32)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "TOKEN_GENERATION_FAILED",
-				"message":   "Failed to generate reset token",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrTokenGenerationFailed.WithMessage("Failed to generate reset token").Response(c)
 		return
 	}
 
-	// Set reset token and expiration (1 hour)
-	expiresAt := time.Now().Add(time.Hour)
+	// Set reset token and expiration
+	expiresAt := time.Now().Add(email.PasswordResetExpiry)
 	user.PasswordResetToken = &resetToken
 	user.PasswordResetExpires = &expiresAt
 
 	if err := h.db.Save(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "RESET_REQUEST_FAILED",
-				"message":   "Failed to process password reset request",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrResetRequestFailed.Response(c)
 		return
 	}
 
-	// TODO: Send password reset email (implement email service)
-	// For development, we'll log the token
-	fmt.Printf("Password reset token for %s: %s\n", user.Email, resetToken)
+	h.logSecurityEvent(c, &user.ID, models.SecurityEventPasswordReset, map[string]interface{}{
+		"email": user.Email,
+	})
+
+	if h.emailService != nil {
+		// The response is the same, and is not held up by the send, whether or not
+		// the address has an account
+		h.sendEmailInBackground("send_password_reset", user.ID, func() error {
+			return h.emailService.SendPasswordReset(user.Email, resetToken)
+		})
+	} else {
+		// For development, log the token
+		logger.FromContext(c.Request.Context()).Debug("Password reset token generated", logger.Fields{"email": user.Email, "token": resetToken})
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "If the email exists, a password reset link has been sent",
@@ -498,71 +536,57 @@ func (h *AuthHandler) RequestPasswordReset(c *gin.Context) {
 }
 
 // ResetPassword handles password reset confirmation
+//
+// @Summary     Reset a password
+// @Description Sets a new password with the token from a password reset email.
+// @Tags        auth
+// @Accept      json
+// @Produce     json
+// @Param       request body PasswordResetConfirmRequest true "Reset token and new password"
+// @Success     200 {object} object{message=string}
+// @Failure     400 {object} errors.ErrorResponse "Invalid request, invalid token or weak password"
+// @Failure     413 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /auth/password-reset/confirm [post]
 func (h *AuthHandler) ResetPassword(c *gin.Context) {
 	var req PasswordResetConfirmRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_REQUEST",
-				"message":   "Invalid request data",
-				"details":   err.Error(),
-				"timestamp": time.Now(),
-			},
-		})
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrInvalidRequest.WithDetails(utils.FormatValidationErrors(err)).Response(c)
 		return
 	}
 
 	// Validate password strength
 	if err := h.authService.ValidatePasswordStrength(req.NewPassword); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "WEAK_PASSWORD",
-				"message":   err.Error(),
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrWeakPassword.WithMessage(err.Error()).Response(c)
 		return
 	}
 
 	// Find user by reset token
 	var user models.User
 	if err := h.db.Where("password_reset_token = ? AND password_reset_expires > ?", req.Token, time.Now()).First(&user).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_RESET_TOKEN",
-				"message":   "Invalid or expired reset token",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrInvalidResetToken.Response(c)
 		return
 	}
 
 	// Hash new password
 	hashedPassword, err := h.authService.HashPassword(req.NewPassword)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "HASH_FAILED",
-				"message":   "Failed to process new password",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrHashFailed.WithMessage("Failed to process new password").Response(c)
 		return
 	}
 
 	// Update password and clear reset token
+	now := time.Now()
 	user.PasswordHash = &hashedPassword
 	user.PasswordResetToken = nil
 	user.PasswordResetExpires = nil
+	user.LastPasswordChangedAt = &now
 
 	if err := h.db.Save(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "PASSWORD_UPDATE_FAILED",
-				"message":   "Failed to update password",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrPasswordUpdateFailed.Response(c)
 		return
 	}
 
@@ -574,29 +598,106 @@ func (h *AuthHandler) ResetPassword(c *gin.Context) {
 	})
 }
 
+// ChangePassword changes the current user's password after checking their current
+// one. Refresh tokens issued before the change stop working, so every device has to
+// log in again.
+//
+// @Summary     Change password
+// @Description Changes the current user's password. Refresh tokens issued before the change stop working.
+// @Tags        auth
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       request body ChangePasswordRequest true "Current and new password"
+// @Success     200 {object} object{message=string}
+// @Failure     400 {object} errors.ErrorResponse "Invalid request, wrong current password or weak password"
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     413 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /me/change-password [post]
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errors.ErrUnauthorized.WithMessage("User not authenticated").Response(c)
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrInvalidRequest.WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+
+	var user models.User
+	if err := h.db.Where("id = ?", userID).First(&user).Error; err != nil {
+		errors.ErrUserNotFound.Response(c)
+		return
+	}
+
+	// OAuth users have no password to change
+	if user.AuthProvider != "email" || user.PasswordHash == nil {
+		errors.ErrInvalidPasswordAuthMethod.Response(c)
+		return
+	}
+
+	if err := h.authService.ValidatePassword(req.CurrentPassword, *user.PasswordHash); err != nil {
+		errors.ErrInvalidCurrentPassword.Response(c)
+		return
+	}
+
+	// Validate password strength
+	if err := h.authService.ValidatePasswordStrength(req.NewPassword); err != nil {
+		errors.ErrWeakPassword.WithMessage(err.Error()).Response(c)
+		return
+	}
+
+	hashedPassword, err := h.authService.HashPassword(req.NewPassword)
+	if err != nil {
+		errors.ErrHashFailed.WithMessage("Failed to process new password").Response(c)
+		return
+	}
+
+	// Update password, clear any pending reset and revoke existing refresh tokens
+	if err := h.db.Model(&user).Updates(map[string]interface{}{
+		"password_hash":            hashedPassword,
+		"password_reset_token":     nil,
+		"password_reset_expires":   nil,
+		"last_password_changed_at": time.Now(),
+	}).Error; err != nil {
+		errors.ErrPasswordUpdateFailed.Response(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Password changed successfully. Please log in again",
+	})
+}
+
 // GetProfile returns the current user's profile
+//
+// @Summary     Get profile
+// @Description Returns the current user's profile.
+// @Tags        auth
+// @Produce     json
+// @Security    BearerAuth
+// @Success     200 {object} object{data=UserResponse}
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse
+// @Router      /auth/profile [get]
 func (h *AuthHandler) GetProfile(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": gin.H{
-				"code":      "UNAUTHORIZED",
-				"message":   "User not authenticated",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrUnauthorized.WithMessage("User not authenticated").Response(c)
 		return
 	}
 
 	var user models.User
 	if err := h.db.Where("id = ?", userID).First(&user).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": gin.H{
-				"code":      "USER_NOT_FOUND",
-				"message":   "User not found",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrUserNotFound.Response(c)
 		return
 	}
 
@@ -615,41 +716,40 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 }
 
 // UpdateProfile updates the current user's profile
+//
+// @Summary     Update profile
+// @Description Updates the current user's display name and avatar.
+// @Tags        auth
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       request body UpdateProfileRequest true "Profile fields to change"
+// @Success     200 {object} object{message=string,data=UserResponse}
+// @Failure     400 {object} errors.ErrorResponse
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     413 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /auth/profile [put]
 func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": gin.H{
-				"code":      "UNAUTHORIZED",
-				"message":   "User not authenticated",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrUnauthorized.WithMessage("User not authenticated").Response(c)
 		return
 	}
 
 	var req UpdateProfileRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_REQUEST",
-				"message":   "Invalid request data",
-				"details":   err.Error(),
-				"timestamp": time.Now(),
-			},
-		})
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrInvalidRequest.WithDetails(utils.FormatValidationErrors(err)).Response(c)
 		return
 	}
 
 	var user models.User
 	if err := h.db.Where("id = ?", userID).First(&user).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": gin.H{
-				"code":      "USER_NOT_FOUND",
-				"message":   "User not found",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrUserNotFound.Response(c)
 		return
 	}
 
@@ -662,13 +762,7 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 	}
 
 	if err := h.db.Save(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "UPDATE_FAILED",
-				"message":   "Failed to update profile",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrUpdateFailed.WithMessage("Failed to update profile").Response(c)
 		return
 	}
 
@@ -688,28 +782,26 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 }
 
 // VerifyEmail handles email verification
+//
+// @Summary     Verify email
+// @Description Verifies an account's email with the token from the verification email.
+// @Tags        auth
+// @Produce     json
+// @Param       token query string true "Verification token"
+// @Success     200 {object} object{message=string}
+// @Failure     400 {object} errors.ErrorResponse "Missing or invalid token"
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /auth/verify-email [get]
 func (h *AuthHandler) VerifyEmail(c *gin.Context) {
 	token := c.Query("token")
 	if token == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "MISSING_TOKEN",
-				"message":   "Verification token is required",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrMissingToken.WithMessage("Verification token is required").Response(c)
 		return
 	}
 
 	var user models.User
 	if err := h.db.Where("email_verification_token = ?", token).First(&user).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_TOKEN",
-				"message":   "Invalid verification token",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrInvalidToken.WithMessage("Invalid verification token").Response(c)
 		return
 	}
 
@@ -718,13 +810,7 @@ func (h *AuthHandler) VerifyEmail(c *gin.Context) {
 	user.EmailVerificationToken = nil
 
 	if err := h.db.Save(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "VERIFICATION_FAILED",
-				"message":   "Failed to verify email",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrVerificationFailed.WithMessage("Failed to verify email").Response(c)
 		return
 	}
 
@@ -732,3 +818,57 @@ func (h *AuthHandler) VerifyEmail(c *gin.Context) {
 		"message": "Email verified successfully",
 	})
 }
+
+// ResendVerification sends a new verification email, for accounts whose first one
+// did not arrive
+//
+// @Summary     Resend the verification email
+// @Description Emails a new verification link to an unverified account, replacing the previous one. The response is the same whether or not the email is registered or already verified.
+// @Tags        auth
+// @Accept      json
+// @Produce     json
+// @Param       request body ResendVerificationRequest true "Account email"
+// @Success     200 {object} object{message=string}
+// @Failure     400 {object} errors.ErrorResponse
+// @Failure     413 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /auth/resend-verification [post]
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	var req ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrInvalidRequest.WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+
+	// Don't reveal whether the email has an account, or whether it is verified
+	response := gin.H{
+		"message": "If the email has an unverified account, a verification link has been sent",
+	}
+
+	var user models.User
+	if err := h.db.Where("email = ?", strings.ToLower(req.Email)).First(&user).Error; err != nil ||
+		user.AuthProvider != "email" || user.IsEmailVerified || h.emailService == nil {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	verificationToken, err := auth.GenerateSecureToken(32)
+	if err != nil {
+		errors.ErrTokenGenerationFailed.WithMessage("Failed to generate verification token").Response(c)
+		return
+	}
+
+	if err := h.db.Model(&user).Update("email_verification_token", verificationToken).Error; err != nil {
+		errors.ErrVerificationFailed.WithMessage("Failed to create verification token").Response(c)
+		return
+	}
+
+	h.sendEmailInBackground("send_email_verification", user.ID, func() error {
+		return h.emailService.SendEmailVerification(user.Email, verificationToken)
+	})
+
+	c.JSON(http.StatusOK, response)
+}
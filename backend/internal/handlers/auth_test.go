@@ -5,10 +5,13 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"bugrelay-backend/internal/auth"
+	"bugrelay-backend/internal/middleware"
 	"bugrelay-backend/internal/models"
 
 	"github.com/gin-gonic/gin"
@@ -29,6 +32,9 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	err = db.AutoMigrate(
 		&models.User{},
 		&models.JWTBlacklist{},
+		&models.NotificationPreferences{},
+		&models.SecurityEvent{},
+		&models.ModerationQueueEntry{},
 	)
 	require.NoError(t, err)
 
@@ -132,6 +138,35 @@ func TestAuthHandler_Register(t *testing.T) {
 	}
 }
 
+func TestAuthHandler_Register_CreatesNotificationPreferences(t *testing.T) {
+	handler, db := setupTestAuthHandler(t)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/register", handler.Register)
+
+	jsonPayload, _ := json.Marshal(RegisterRequest{
+		Email:       "prefs@example.com",
+		Password:    "password123",
+		DisplayName: "Prefs User",
+	})
+	req, _ := http.NewRequest("POST", "/register", bytes.NewBuffer(jsonPayload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var user models.User
+	require.NoError(t, db.Where("email = ?", "prefs@example.com").First(&user).Error)
+
+	var preferences models.NotificationPreferences
+	require.NoError(t, db.Where("user_id = ?", user.ID).First(&preferences).Error)
+	assert.True(t, preferences.BugStatusChange)
+	assert.True(t, preferences.CommentReply)
+	assert.False(t, preferences.Marketing)
+	assert.Equal(t, models.DigestFrequencyNever, preferences.DigestFrequency)
+}
+
 func TestAuthHandler_Register_DuplicateEmail(t *testing.T) {
 	handler, db := setupTestAuthHandler(t)
 	
@@ -171,6 +206,31 @@ func TestAuthHandler_Register_DuplicateEmail(t *testing.T) {
 	assert.Equal(t, "USER_EXISTS", errorData["code"])
 }
 
+func TestAuthHandler_Register_BodyTooLarge(t *testing.T) {
+	handler, _ := setupTestAuthHandler(t)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.BodySizeLimit(256))
+	router.POST("/register", handler.Register)
+
+	payload := RegisterRequest{
+		Email:       "test@example.com",
+		Password:    "password123",
+		DisplayName: strings.Repeat("a", 256),
+	}
+
+	jsonPayload, _ := json.Marshal(payload)
+	req, _ := http.NewRequest("POST", "/register", bytes.NewBuffer(jsonPayload))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), "REQUEST_ENTITY_TOO_LARGE")
+}
+
 func TestAuthHandler_Login(t *testing.T) {
 	handler, db := setupTestAuthHandler(t)
 	
@@ -299,7 +359,7 @@ func TestAuthHandler_RefreshToken(t *testing.T) {
 	// Generate initial tokens
 	userID := uuid.New().String()
 	email := "test@example.com"
-	_, refreshToken, err := handler.authService.GenerateTokens(userID, email, false)
+	_, refreshToken, err := handler.authService.GenerateTokens(userID, email, false, "")
 	require.NoError(t, err)
 
 	gin.SetMode(gin.TestMode)
@@ -412,4 +472,289 @@ func TestAuthHandler_RequestPasswordReset(t *testing.T) {
 			assert.Equal(t, tt.expectedStatus, w.Code)
 		})
 	}
-}
\ No newline at end of file
+}
+
+// sentAccountEmail is an account email recorded by mockEmailService
+type sentAccountEmail struct {
+	kind  string
+	to    string
+	token string
+}
+
+// mockEmailService records account emails instead of sending them. Emails sent in
+// the background are read with sentEmails once they have been sent.
+type mockEmailService struct {
+	mu   sync.Mutex
+	sent []sentAccountEmail
+	err  error
+}
+
+func (m *mockEmailService) SendPasswordReset(to, resetToken string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, sentAccountEmail{kind: "password_reset", to: to, token: resetToken})
+	return m.err
+}
+
+func (m *mockEmailService) SendEmailVerification(to, verificationToken string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, sentAccountEmail{kind: "email_verification", to: to, token: verificationToken})
+	return m.err
+}
+
+func (m *mockEmailService) setErr(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.err = err
+}
+
+// sentEmails waits for count emails to have been sent and returns them
+func (m *mockEmailService) sentEmails(t *testing.T, count int) []sentAccountEmail {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		return len(m.sent) >= count
+	}, time.Second, 5*time.Millisecond)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]sentAccountEmail(nil), m.sent...)
+}
+
+func TestAuthHandler_RequestPasswordReset_SendsEmail(t *testing.T) {
+	handler, db := setupTestAuthHandler(t)
+	emailService := &mockEmailService{}
+	handler.SetEmailService(emailService)
+
+	hashedPassword, _ := handler.authService.HashPassword("password123")
+	user := models.User{
+		Email:        "test@example.com",
+		DisplayName:  "Test User",
+		PasswordHash: &hashedPassword,
+		AuthProvider: "email",
+	}
+	require.NoError(t, db.Create(&user).Error)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/password-reset", handler.RequestPasswordReset)
+
+	request := func(address string) *httptest.ResponseRecorder {
+		jsonPayload, _ := json.Marshal(PasswordResetRequest{Email: address})
+		req, _ := http.NewRequest("POST", "/password-reset", bytes.NewBuffer(jsonPayload))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, request("Test@Example.com").Code)
+
+	// The email carries the token stored for the user
+	var stored models.User
+	require.NoError(t, db.First(&stored, "id = ?", user.ID).Error)
+	require.NotNil(t, stored.PasswordResetToken)
+	sent := emailService.sentEmails(t, 1)
+	require.Len(t, sent, 1)
+	assert.Equal(t, sentAccountEmail{kind: "password_reset", to: "test@example.com", token: *stored.PasswordResetToken}, sent[0])
+
+	// No email for unknown addresses, with the same response
+	assert.Equal(t, http.StatusOK, request("nonexistent@example.com").Code)
+	time.Sleep(20 * time.Millisecond)
+	assert.Len(t, emailService.sentEmails(t, 1), 1)
+
+	// A failed send doesn't change the response
+	emailService.setErr(assert.AnError)
+	assert.Equal(t, http.StatusOK, request("test@example.com").Code)
+	emailService.sentEmails(t, 2)
+}
+
+func TestAuthHandler_RequestPasswordReset_DoesNotWaitForEmail(t *testing.T) {
+	handler, db := setupTestAuthHandler(t)
+	release := make(chan struct{})
+	defer close(release)
+	handler.SetEmailService(&blockingEmailService{release: release})
+
+	hashedPassword, _ := handler.authService.HashPassword("password123")
+	require.NoError(t, db.Create(&models.User{
+		Email:        "test@example.com",
+		DisplayName:  "Test User",
+		PasswordHash: &hashedPassword,
+		AuthProvider: "email",
+	}).Error)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/password-reset", handler.RequestPasswordReset)
+
+	// The send blocks until the test ends, so the response must not wait for it
+	jsonPayload, _ := json.Marshal(PasswordResetRequest{Email: "test@example.com"})
+	req, _ := http.NewRequest("POST", "/password-reset", bytes.NewBuffer(jsonPayload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+// blockingEmailService blocks every send until release is closed
+type blockingEmailService struct {
+	release chan struct{}
+}
+
+func (b *blockingEmailService) SendPasswordReset(to, resetToken string) error {
+	<-b.release
+	return nil
+}
+
+func (b *blockingEmailService) SendEmailVerification(to, verificationToken string) error {
+	<-b.release
+	return nil
+}
+
+func TestAuthHandler_ResendVerification(t *testing.T) {
+	handler, db := setupTestAuthHandler(t)
+	emailService := &mockEmailService{}
+	handler.SetEmailService(emailService)
+
+	oldToken := "old-token"
+	unverified := models.User{Email: "new@example.com", DisplayName: "New User", AuthProvider: "email", EmailVerificationToken: &oldToken}
+	verified := models.User{Email: "verified@example.com", DisplayName: "Verified User", AuthProvider: "email", IsEmailVerified: true}
+	require.NoError(t, db.Create(&unverified).Error)
+	require.NoError(t, db.Create(&verified).Error)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/resend-verification", handler.ResendVerification)
+
+	request := func(address string) *httptest.ResponseRecorder {
+		jsonPayload, _ := json.Marshal(ResendVerificationRequest{Email: address})
+		req, _ := http.NewRequest("POST", "/resend-verification", bytes.NewBuffer(jsonPayload))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	unverifiedResponse := request("New@Example.com")
+	require.Equal(t, http.StatusOK, unverifiedResponse.Code)
+
+	// A new token replaces the old one and is emailed
+	var stored models.User
+	require.NoError(t, db.First(&stored, "id = ?", unverified.ID).Error)
+	require.NotNil(t, stored.EmailVerificationToken)
+	assert.NotEqual(t, oldToken, *stored.EmailVerificationToken)
+	sent := emailService.sentEmails(t, 1)
+	require.Len(t, sent, 1)
+	assert.Equal(t, sentAccountEmail{kind: "email_verification", to: "new@example.com", token: *stored.EmailVerificationToken}, sent[0])
+
+	// Verified and unknown addresses get the same response and no email
+	for _, address := range []string{"verified@example.com", "nonexistent@example.com"} {
+		w := request(address)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, unverifiedResponse.Body.String(), w.Body.String())
+	}
+	time.Sleep(20 * time.Millisecond)
+	assert.Len(t, emailService.sentEmails(t, 1), 1)
+}
+
+func TestAuthHandler_Register_SendsVerificationEmail(t *testing.T) {
+	handler, db := setupTestAuthHandler(t)
+	emailService := &mockEmailService{}
+	handler.SetEmailService(emailService)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/register", handler.Register)
+
+	jsonPayload, _ := json.Marshal(RegisterRequest{
+		Email:       "New@Example.com",
+		Password:    "SecurePass123!",
+		DisplayName: "New User",
+	})
+	req, _ := http.NewRequest("POST", "/register", bytes.NewBuffer(jsonPayload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	// The account stays unverified until the emailed token is used
+	var user models.User
+	require.NoError(t, db.First(&user, "email = ?", "new@example.com").Error)
+	assert.False(t, user.IsEmailVerified)
+	require.NotNil(t, user.EmailVerificationToken)
+	sent := emailService.sentEmails(t, 1)
+	require.Len(t, sent, 1)
+	assert.Equal(t, sentAccountEmail{kind: "email_verification", to: "new@example.com", token: *user.EmailVerificationToken}, sent[0])
+}
+func TestAuthHandler_ChangePassword(t *testing.T) {
+	handler, db := setupTestAuthHandler(t)
+
+	hashedPassword, _ := handler.authService.HashPassword("password123")
+	resetToken := "pending-reset-token"
+	user := models.User{
+		Email:              "test@example.com",
+		DisplayName:        "Test User",
+		PasswordHash:       &hashedPassword,
+		AuthProvider:       "email",
+		PasswordResetToken: &resetToken,
+	}
+	require.NoError(t, db.Create(&user).Error)
+
+	oauthUser := models.User{
+		Email:        "oauth@example.com",
+		DisplayName:  "OAuth User",
+		AuthProvider: "github",
+	}
+	require.NoError(t, db.Create(&oauthUser).Error)
+
+	_, refreshToken, err := handler.authService.GenerateTokens(user.ID.String(), user.Email, false, "")
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	changePassword := func(userID uuid.UUID, payload ChangePasswordRequest) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(mockAuthMiddleware(userID))
+		router.POST("/me/change-password", handler.ChangePassword)
+
+		jsonPayload, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("POST", "/me/change-password", bytes.NewBuffer(jsonPayload))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("rejects wrong current password", func(t *testing.T) {
+		w := changePassword(user.ID, ChangePasswordRequest{CurrentPassword: "wrongpassword", NewPassword: "newpassword456"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_CURRENT_PASSWORD")
+
+		var unchanged models.User
+		require.NoError(t, db.First(&unchanged, "id = ?", user.ID).Error)
+		assert.NoError(t, handler.authService.ValidatePassword("password123", *unchanged.PasswordHash))
+		assert.Nil(t, unchanged.LastPasswordChangedAt)
+	})
+
+	t.Run("rejects OAuth users", func(t *testing.T) {
+		w := changePassword(oauthUser.ID, ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "newpassword456"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_AUTH_METHOD")
+	})
+
+	t.Run("changes password and revokes refresh tokens", func(t *testing.T) {
+		w := changePassword(user.ID, ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "newpassword456"})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var updated models.User
+		require.NoError(t, db.First(&updated, "id = ?", user.ID).Error)
+		assert.NoError(t, handler.authService.ValidatePassword("newpassword456", *updated.PasswordHash))
+		assert.Nil(t, updated.PasswordResetToken)
+		require.NotNil(t, updated.LastPasswordChangedAt)
+
+		_, _, err := handler.authService.RefreshTokens(refreshToken, "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "revoked")
+	})
+}
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/middleware"
+	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxAttachmentPageLimit is the most attachments listed per page
+const maxAttachmentPageLimit = 20
+
+// attachmentPagination is the page size of attachment listings
+var attachmentPagination = PaginationConfig{DefaultLimit: DefaultPageLimit, MaxLimit: maxAttachmentPageLimit}
+
+// attachmentSortOrders maps the sort parameter of attachment listings to its ordering
+var attachmentSortOrders = map[string]string{
+	"recent":  "uploaded_at DESC",
+	"oldest":  "uploaded_at ASC",
+	"largest": "COALESCE(file_size, 0) DESC, uploaded_at DESC",
+}
+
+// ListBugAttachmentsRequest represents the query parameters of an attachment listing
+type ListBugAttachmentsRequest struct {
+	Page  int    `form:"page,default=1"`
+	Limit int    `form:"limit,default=20"`
+	Sort  string `form:"sort,default=recent"`
+}
+
+// AttachmentUploader is the user who uploaded an attachment
+type AttachmentUploader struct {
+	ID          uuid.UUID `json:"id"`
+	DisplayName string    `json:"display_name"`
+	AvatarURL   *string   `json:"avatar_url,omitempty"`
+}
+
+// BugAttachment is an attachment as listed by ListBugAttachments
+type BugAttachment struct {
+	ID            uuid.UUID           `json:"id"`
+	BugID         uuid.UUID           `json:"bug_id"`
+	Filename      string              `json:"filename"`
+	FileURL       string              `json:"file_url"`
+	FileSize      string              `json:"file_size,omitempty"` // human-readable, e.g. "1.5 MB"
+	FileSizeBytes *int                `json:"file_size_bytes,omitempty"`
+	MimeType      *string             `json:"mime_type,omitempty"`
+	PreviewURL    string              `json:"preview_url"`
+	DownloadCount int                 `json:"download_count"`
+	UploadedBy    *AttachmentUploader `json:"uploaded_by"`
+	UploadedAt    time.Time           `json:"uploaded_at"`
+}
+
+// newBugAttachment builds the listing of an attachment with its uploader loaded
+func (h *BugHandler) newBugAttachment(attachment models.FileAttachment) BugAttachment {
+	listed := BugAttachment{
+		ID:            attachment.ID,
+		BugID:         attachment.BugID,
+		Filename:      attachment.Filename,
+		FileURL:       attachment.FileURL,
+		FileSizeBytes: attachment.FileSize,
+		MimeType:      attachment.MimeType,
+		PreviewURL:    h.storage.GeneratePreviewURL(attachment),
+		DownloadCount: attachment.DownloadCount,
+		UploadedAt:    attachment.UploadedAt,
+	}
+	if attachment.FileSize != nil {
+		listed.FileSize = utils.FormatFileSize(int64(*attachment.FileSize))
+	}
+	if attachment.UploadedBy != nil {
+		listed.UploadedBy = &AttachmentUploader{
+			ID:          attachment.UploadedBy.ID,
+			DisplayName: attachment.UploadedBy.DisplayName,
+			AvatarURL:   attachment.UploadedBy.AvatarURL,
+		}
+	}
+	return listed
+}
+
+// ListBugAttachments returns a page of a bug's attachments, sorted by sort: recent
+// (the default), oldest or largest
+func (h *BugHandler) ListBugAttachments(c *gin.Context) {
+	bugUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid bug ID format").Response(c)
+		return
+	}
+
+	var req ListBugAttachmentsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		errors.ErrValidationError.WithMessage("Invalid query parameters").WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+
+	order, ok := attachmentSortOrders[req.Sort]
+	if !ok {
+		errors.ErrInvalidSort.WithMessage("Sort must be one of recent, oldest, largest").Response(c)
+		return
+	}
+
+	req.Limit = attachmentPagination.Limit(req.Limit)
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+
+	// Verify bug exists
+	var bug models.BugReport
+	if err := h.db.Select("id").First(&bug, "id = ?", bugUUID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrBugNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to verify bug report").Response(c)
+		return
+	}
+
+	query := h.db.Model(&models.FileAttachment{}).Where("bug_id = ?", bugUUID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		errors.ErrCountFailed.WithMessage("Failed to count bug attachments").Response(c)
+		return
+	}
+
+	var attachments []models.FileAttachment
+	if err := query.Preload("UploadedBy").
+		Order(order).
+		Offset((req.Page - 1) * req.Limit).
+		Limit(req.Limit).
+		Find(&attachments).Error; err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to fetch bug attachments").Response(c)
+		return
+	}
+
+	listed := make([]BugAttachment, 0, len(attachments))
+	for _, attachment := range attachments {
+		listed = append(listed, h.newBugAttachment(attachment))
+	}
+
+	// Calculate pagination info
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+
+	c.JSON(http.StatusOK, gin.H{
+		"attachments": listed,
+		"pagination": gin.H{
+			"page":        req.Page,
+			"limit":       req.Limit,
+			"total":       total,
+			"total_pages": totalPages,
+			"has_next":    req.Page < totalPages,
+			"has_prev":    req.Page > 1,
+		},
+	})
+}
+
+// DeleteBugAttachment removes an attachment from a bug. Only the bug's reporter and
+// admins can remove attachments. The record is soft-deleted and the file removed
+// from storage.
+func (h *BugHandler) DeleteBugAttachment(c *gin.Context) {
+	bugUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid bug ID format").Response(c)
+		return
+	}
+
+	attachmentUUID, err := uuid.Parse(c.Param("attachment_id"))
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid attachment ID format").Response(c)
+		return
+	}
+
+	userIDStr, _ := middleware.GetCurrentUserID(c)
+	userUUID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		errors.ErrUnauthorized.Response(c)
+		return
+	}
+
+	// Verify bug exists
+	var bug models.BugReport
+	if err := h.db.First(&bug, "id = ?", bugUUID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrBugNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to verify bug report").Response(c)
+		return
+	}
+
+	if !middleware.IsCurrentUserAdmin(c) && (bug.ReporterID == nil || *bug.ReporterID != userUUID) {
+		errors.ErrInsufficientPermissions.WithMessage("Only the bug's reporter or an admin can delete its attachments").Response(c)
+		return
+	}
+
+	var attachment models.FileAttachment
+	if err := h.db.Where("id = ? AND bug_id = ?", attachmentUUID, bugUUID).First(&attachment).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrAttachmentNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch attachment").Response(c)
+		return
+	}
+
+	if err := h.db.Delete(&attachment).Error; err != nil {
+		errors.ErrDeleteFailed.WithMessage("Failed to delete attachment").Response(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.storage.Delete(ctx, attachment); err != nil {
+		// The attachment is already gone from the bug, so an orphaned file only wastes space
+		logger.FromContext(ctx).Error("Failed to delete attachment file", err, logger.Fields{
+			"attachment_id": attachment.ID.String(),
+			"file_url":      attachment.FileURL,
+		})
+	}
+
+	// Batch lookups cache bugs with their attachments
+	if err := h.cache.InvalidateBug(ctx, bugUUID.String()); err != nil {
+		// Log cache error but don't fail the request
+		logger.FromContext(ctx).Error("Failed to invalidate bug cache", err, logger.Fields{"bug_id": bugUUID.String()})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Attachment deleted successfully",
+	})
+}
@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createTestAttachment creates an attachment of size bytes on the bug, uploaded by
+// the user at uploadedAt
+func createTestAttachment(t *testing.T, handler *BugHandler, bug *models.BugReport, user *models.User, filename string, size int, uploadedAt time.Time) *models.FileAttachment {
+	attachment := &models.FileAttachment{
+		ID:           uuid.New(),
+		BugID:        bug.ID,
+		Filename:     filename,
+		FileURL:      "uploads/bugs/" + filename,
+		FileSize:     &size,
+		UploadedAt:   uploadedAt,
+		UploadedByID: &user.ID,
+	}
+	require.NoError(t, handler.db.Create(attachment).Error)
+	return attachment
+}
+
+func TestBugHandler_ListBugAttachments(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	user := createTestUser(t, db)
+	app := createTestApplication(t, db)
+	bug := createTestBugReport(t, db, app, user)
+
+	now := time.Now()
+	oldest := createTestAttachment(t, handler, bug, user, "oldest.png", 2048, now.Add(-3*time.Hour))
+	largest := createTestAttachment(t, handler, bug, user, "largest.png", 3*1024*1024, now.Add(-2*time.Hour))
+	recent := createTestAttachment(t, handler, bug, user, "recent.png", 512, now.Add(-time.Hour))
+
+	// Attachments of other bugs are not listed
+	otherBug := createTestBugReport(t, db, app, user)
+	createTestAttachment(t, handler, otherBug, user, "other.png", 100, now)
+
+	list := func(query string) (*httptest.ResponseRecorder, []BugAttachment, map[string]interface{}) {
+		router := gin.New()
+		router.GET("/bugs/:id/attachments", handler.ListBugAttachments)
+
+		req, _ := http.NewRequest("GET", fmt.Sprintf("/bugs/%s/attachments%s", bug.ID, query), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response struct {
+			Attachments []BugAttachment        `json:"attachments"`
+			Pagination  map[string]interface{} `json:"pagination"`
+		}
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		}
+		return w, response.Attachments, response.Pagination
+	}
+
+	ids := func(attachments []BugAttachment) []uuid.UUID {
+		var ids []uuid.UUID
+		for _, attachment := range attachments {
+			ids = append(ids, attachment.ID)
+		}
+		return ids
+	}
+
+	t.Run("sorts by upload time and size", func(t *testing.T) {
+		for query, expected := range map[string][]uuid.UUID{
+			"":              {recent.ID, largest.ID, oldest.ID},
+			"?sort=recent":  {recent.ID, largest.ID, oldest.ID},
+			"?sort=oldest":  {oldest.ID, largest.ID, recent.ID},
+			"?sort=largest": {largest.ID, oldest.ID, recent.ID},
+		} {
+			w, attachments, _ := list(query)
+			require.Equal(t, http.StatusOK, w.Code, query)
+			assert.Equal(t, expected, ids(attachments), query)
+		}
+	})
+
+	t.Run("returns attachment metadata", func(t *testing.T) {
+		w, attachments, _ := list("?sort=largest")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		listed := attachments[0]
+		assert.Equal(t, "3.0 MB", listed.FileSize)
+		assert.Equal(t, 3*1024*1024, *listed.FileSizeBytes)
+		assert.Equal(t, "/attachments/serve/largest.png", listed.PreviewURL)
+		assert.Zero(t, listed.DownloadCount)
+		require.NotNil(t, listed.UploadedBy)
+		assert.Equal(t, user.ID, listed.UploadedBy.ID)
+		assert.Equal(t, user.DisplayName, listed.UploadedBy.DisplayName)
+		assert.NotContains(t, w.Body.String(), user.Email)
+	})
+
+	t.Run("paginates", func(t *testing.T) {
+		w, attachments, pagination := list("?limit=2&page=2")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []uuid.UUID{oldest.ID}, ids(attachments))
+		assert.Equal(t, float64(3), pagination["total"])
+		assert.Equal(t, float64(2), pagination["total_pages"])
+
+		// Pages hold at most 20 attachments
+		_, _, pagination = list("?limit=100")
+		assert.Equal(t, float64(maxAttachmentPageLimit), pagination["limit"])
+	})
+
+	t.Run("rejects unknown sorts", func(t *testing.T) {
+		w, _, _ := list("?sort=name")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_SORT")
+	})
+}
+
+func TestBugHandler_UploadBugAttachment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	storage := newMockStorage()
+	handler.SetStorage(storage)
+
+	reporter := createTestUser(t, db)
+	app := createTestApplication(t, db)
+	bug := createTestBugReport(t, db, app, reporter)
+
+	screenshot := encodeTestImage(t, "png", 8, 8)
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", "screenshot.png")
+	require.NoError(t, err)
+	_, err = part.Write(screenshot)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	router := gin.New()
+	router.Use(mockAuthMiddleware(reporter.ID))
+	router.POST("/bugs/:id/attachments", handler.UploadBugAttachment)
+
+	req, _ := http.NewRequest("POST", fmt.Sprintf("/bugs/%s/attachments", bug.ID), body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	// The whole file is stored, including the bytes read to detect its type
+	require.Len(t, storage.objects, 1)
+	for key, data := range storage.objects {
+		assert.Regexp(t, `^bugs/`+bug.ID.String()+`_\d+\.png$`, key)
+		assert.Equal(t, screenshot, data)
+		assert.Equal(t, "image/png", storage.contentTypes[key])
+	}
+
+	// The attachment records the URL returned by the storage backend
+	var attachment models.FileAttachment
+	require.NoError(t, db.Where("bug_id = ?", bug.ID).First(&attachment).Error)
+	assert.Regexp(t, `^https://storage\.example\.com/bugs/`, attachment.FileURL)
+	assert.Equal(t, "screenshot.png", attachment.Filename)
+	assert.Contains(t, w.Body.String(), attachment.FileURL)
+}
+
+func TestBugHandler_DeleteBugAttachment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	storage := newMockStorage()
+	handler.SetStorage(storage)
+
+	reporter := createTestUser(t, db)
+	other := &models.User{ID: uuid.New(), Email: "other@example.com", DisplayName: "Other User"}
+	require.NoError(t, db.Create(other).Error)
+	app := createTestApplication(t, db)
+	bug := createTestBugReport(t, db, app, reporter)
+	require.NoError(t, db.Model(bug).Updates(map[string]interface{}{"vote_count": 4, "comment_count": 2}).Error)
+
+	first := createTestAttachment(t, handler, bug, reporter, "first.png", 100, time.Now())
+	second := createTestAttachment(t, handler, bug, reporter, "second.png", 100, time.Now())
+
+	deleteAttachment := func(auth gin.HandlerFunc, attachmentID uuid.UUID) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(auth)
+		router.DELETE("/bugs/:id/attachments/:attachment_id", handler.DeleteBugAttachment)
+
+		req, _ := http.NewRequest("DELETE", fmt.Sprintf("/bugs/%s/attachments/%s", bug.ID, attachmentID), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	attachmentCount := func() float64 {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/bugs/"+bug.ID.String(), nil)
+		c.Params = gin.Params{{Key: "id", Value: bug.ID.String()}}
+		handler.GetBug(c)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Bug map[string]interface{} `json:"bug"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.Bug["attachment_count"].(float64)
+	}
+
+	require.Equal(t, float64(2), attachmentCount())
+
+	t.Run("only the reporter or an admin can delete", func(t *testing.T) {
+		w := deleteAttachment(mockAuthMiddleware(other.ID), first.ID)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "INSUFFICIENT_PERMISSIONS")
+		assert.Empty(t, storage.deleted)
+	})
+
+	t.Run("reporter deletes an attachment", func(t *testing.T) {
+		w := deleteAttachment(mockAuthMiddleware(reporter.ID), first.ID)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, []string{first.FileURL}, storage.deleted)
+
+		// The record is soft-deleted
+		var deleted models.FileAttachment
+		require.NoError(t, db.Unscoped().First(&deleted, "id = ?", first.ID).Error)
+		assert.True(t, deleted.DeletedAt.Valid)
+
+		// Counts are computed, so only the attachment count changes
+		assert.Equal(t, float64(1), attachmentCount())
+		var reloaded models.BugReport
+		require.NoError(t, db.First(&reloaded, "id = ?", bug.ID).Error)
+		assert.Equal(t, 4, reloaded.VoteCount)
+		assert.Equal(t, 2, reloaded.CommentCount)
+
+		w = deleteAttachment(mockAuthMiddleware(reporter.ID), first.ID)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "ATTACHMENT_NOT_FOUND")
+	})
+
+	t.Run("admin deletes an attachment", func(t *testing.T) {
+		w := deleteAttachment(mockAdminAuthMiddleware(other.ID), second.ID)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Zero(t, attachmentCount())
+	})
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxBatchBugIDs is the most bugs a single batch lookup can fetch
+const maxBatchBugIDs = 50
+
+// BatchGetBugsRequest represents the request to fetch several bugs by ID
+type BatchGetBugsRequest struct {
+	IDs []uuid.UUID `json:"ids" binding:"required,min=1"`
+}
+
+// BatchGetBugs returns the bugs with the given IDs and all their relationships, keyed
+// by ID. Bugs that do not exist map to null. Cached bugs are served from the cache and
+// the rest are fetched with a single query.
+func (h *BugHandler) BatchGetBugs(c *gin.Context) {
+	var req BatchGetBugsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+
+	if len(req.IDs) > maxBatchBugIDs {
+		errors.ErrTooManyBugIDs.WithMessage(fmt.Sprintf("At most %d bugs can be fetched at once", maxBatchBugIDs)).Response(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+	bugs := make(map[string]*models.BugReport, len(req.IDs))
+
+	// Serve what the cache has, collecting the misses
+	var misses []uuid.UUID
+	for _, id := range req.IDs {
+		key := id.String()
+		if _, seen := bugs[key]; seen {
+			continue
+		}
+
+		var bug models.BugReport
+		if err := h.cache.GetBugWithRelations(ctx, key, &bug); err == nil {
+			bugs[key] = &bug
+			continue
+		}
+		bugs[key] = nil
+		if !h.cache.IsBugNotFound(ctx, key) {
+			misses = append(misses, id)
+		}
+	}
+
+	if len(misses) > 0 {
+		var fetched []models.BugReport
+		if err := h.db.
+			Preload("Application").
+			Preload("Reporter").
+			Preload("AssignedCompany").
+			// Comments are threaded as in GetBug: top-level comments with their replies
+			// nested, and deleted comments kept without content so replies keep their place
+			Preload("Comments", func(db *gorm.DB) *gorm.DB {
+				return preloadCommentReplies(db.Unscoped().Preload("User"), bugCommentReplyDepth).
+					Where("parent_id IS NULL").
+					Order("created_at ASC")
+			}).
+			Preload("Attachments", func(db *gorm.DB) *gorm.DB { return db.Order("uploaded_at ASC") }).
+			Preload("Votes").
+			Where("id IN ?", misses).
+			Find(&fetched).Error; err != nil {
+			errors.ErrQueryFailed.WithMessage("Failed to fetch bug reports").Response(c)
+			return
+		}
+
+		for i := range fetched {
+			bug := &fetched[i]
+			key := bug.ID.String()
+			bugs[key] = bug
+
+			if err := h.cache.SetBugWithRelations(ctx, key, bug); err != nil {
+				// Log cache error but don't fail the request
+				logger.FromContext(ctx).Error("Failed to cache bug", err, logger.Fields{"bug_id": key})
+			}
+		}
+
+		for _, id := range misses {
+			key := id.String()
+			if bugs[key] != nil {
+				continue
+			}
+			if err := h.cache.SetBugNotFound(ctx, key); err != nil {
+				// Log cache error but don't fail the request
+				logger.FromContext(ctx).Error("Failed to cache missing bug", err, logger.Fields{"bug_id": key})
+			}
+		}
+	}
+
+	// Preview URLs expire, so they are generated per request rather than cached. Comments
+	// between the current user and users they have a block with are hidden, as in GetBug.
+	blocks, hasBlocks, err := h.currentUserBlockList(c)
+	if err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to check user blocks").Response(c)
+		return
+	}
+	for _, bug := range bugs {
+		if bug == nil {
+			continue
+		}
+		h.setPreviewURLs(bug.Attachments)
+		if hasBlocks && len(bug.Comments) > 0 {
+			bug.Comments = blocks.visibleComments(bug.Comments)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"bugs": bugs,
+	})
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBugHandler_BatchGetBugs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupBugTestDB(t)
+	redisClient, mock := newMockRedisClient()
+	handler := NewBugHandler(db, redisClient)
+
+	user := createTestUser(t, db)
+	app := createTestApplication(t, db)
+	cached := createTestBugReport(t, db, app, user)
+	uncached := createTestBugReport(t, db, app, user)
+	require.NoError(t, db.Create(&models.Comment{BugID: uncached.ID, UserID: user.ID, Content: "Happens to me too"}).Error)
+	missingID := uuid.New()
+
+	router := gin.New()
+	router.POST("/bugs/batch", handler.BatchGetBugs)
+
+	batchGet := func(ids ...uuid.UUID) (*httptest.ResponseRecorder, map[string]*models.BugReport) {
+		body, err := json.Marshal(map[string]interface{}{"ids": ids})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest("POST", "/bugs/batch", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response struct {
+			Bugs map[string]*models.BugReport `json:"bugs"`
+		}
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		}
+		return w, response.Bugs
+	}
+
+	// Warm the cache for one bug
+	w, _ := batchGet(cached.ID)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, mock.values, "bug:"+cached.ID.String()+":full")
+
+	queries := countBugQueries(t, db, 0)
+
+	t.Run("only cache misses are queried", func(t *testing.T) {
+		w, bugs := batchGet(cached.ID, uncached.ID, missingID)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, int64(1), queries.Load())
+
+		require.Len(t, bugs, 3)
+		require.NotNil(t, bugs[cached.ID.String()])
+		assert.Equal(t, cached.Title, bugs[cached.ID.String()].Title)
+		assert.Equal(t, app.Name, bugs[cached.ID.String()].Application.Name)
+
+		fetched := bugs[uncached.ID.String()]
+		require.NotNil(t, fetched)
+		assert.Equal(t, app.Name, fetched.Application.Name)
+		require.NotNil(t, fetched.Reporter)
+		assert.Equal(t, user.ID, fetched.Reporter.ID)
+		require.Len(t, fetched.Comments, 1)
+		assert.Equal(t, user.ID, fetched.Comments[0].User.ID)
+
+		// Missing bugs map to null rather than failing the request
+		value, ok := bugs[missingID.String()]
+		assert.True(t, ok)
+		assert.Nil(t, value)
+		assert.Contains(t, w.Body.String(), `"`+missingID.String()+`":null`)
+	})
+
+	t.Run("repeated lookups are served from the cache", func(t *testing.T) {
+		queries.Store(0)
+		w, bugs := batchGet(cached.ID, uncached.ID, missingID, uncached.ID)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Zero(t, queries.Load())
+		assert.Len(t, bugs, 3)
+		assert.NotNil(t, bugs[uncached.ID.String()])
+		assert.Nil(t, bugs[missingID.String()])
+	})
+
+	t.Run("limits the batch size", func(t *testing.T) {
+		ids := make([]uuid.UUID, maxBatchBugIDs+1)
+		for i := range ids {
+			ids[i] = uuid.New()
+		}
+		w, _ := batchGet(ids...)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "TOO_MANY_BUG_IDS")
+
+		w, _ = batchGet()
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "VALIDATION_ERROR")
+	})
+}
+
+func TestBugHandler_BatchGetBugs_NestedReplies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupBugTestDB(t)
+	handler := NewBugHandler(db, nil)
+
+	user := createTestUser(t, db)
+	app := createTestApplication(t, db)
+	bug := createTestBugReport(t, db, app, user)
+
+	now := time.Now()
+	comment := func(parent *models.Comment, content string, age time.Duration) *models.Comment {
+		c := &models.Comment{BugID: bug.ID, UserID: user.ID, Content: content, CreatedAt: now.Add(-age)}
+		if parent != nil {
+			c.ParentID = &parent.ID
+		}
+		require.NoError(t, db.Create(c).Error)
+		return c
+	}
+	top := comment(nil, "Happens to me too", 5*time.Hour)
+	reply := comment(top, "Same on Android", 4*time.Hour)
+	comment(reply, "Fixed by clearing the cache", 3*time.Hour)
+	deleted := comment(nil, "Wrong bug, sorry", 2*time.Hour)
+	comment(deleted, "Reply to a deleted comment", time.Hour)
+	require.NoError(t, db.Delete(deleted).Error)
+
+	router := gin.New()
+	router.POST("/bugs/batch", handler.BatchGetBugs)
+
+	body, err := json.Marshal(map[string]interface{}{"ids": []uuid.UUID{bug.ID}})
+	require.NoError(t, err)
+	req := httptest.NewRequest("POST", "/bugs/batch", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response struct {
+		Bugs map[string]*models.BugReport `json:"bugs"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	fetched := response.Bugs[bug.ID.String()]
+	require.NotNil(t, fetched)
+
+	// Replies are only listed under their parent, not again at the top level
+	require.Len(t, fetched.Comments, 2)
+	assert.Equal(t, top.ID, fetched.Comments[0].ID)
+	require.Len(t, fetched.Comments[0].Children, 1)
+	assert.Equal(t, reply.ID, fetched.Comments[0].Children[0].ID)
+	assert.Equal(t, user.ID, fetched.Comments[0].Children[0].User.ID)
+	require.Len(t, fetched.Comments[0].Children[0].Children, 1)
+	assert.Equal(t, "Fixed by clearing the cache", fetched.Comments[0].Children[0].Children[0].Content)
+
+	// A deleted comment keeps its place, without content, so its replies are not orphaned
+	assert.Equal(t, deleted.ID, fetched.Comments[1].ID)
+	assert.True(t, fetched.Comments[1].IsDeleted)
+	assert.Empty(t, fetched.Comments[1].Content)
+	require.Len(t, fetched.Comments[1].Children, 1)
+	assert.Equal(t, "Reply to a deleted comment", fetched.Comments[1].Children[0].Content)
+}
@@ -0,0 +1,476 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/middleware"
+	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// bugCommentReplyDepth is how many levels of replies GetBug loads under each
+// top-level comment. ListBugComments returns replies at any depth.
+const bugCommentReplyDepth = 3
+
+// commentPagination is the page size of comment listings, counted in top-level
+// comments
+var commentPagination = PaginationConfig{DefaultLimit: 10, MaxLimit: 50}
+
+// ListBugCommentsRequest represents the query parameters of a comment listing
+type ListBugCommentsRequest struct {
+	Page  int `form:"page,default=1"`
+	Limit int `form:"limit,default=10"`
+}
+
+// preloadCommentReplies adds preloads to query for depth levels of replies and
+// their authors, oldest first
+func preloadCommentReplies(query *gorm.DB, depth int) *gorm.DB {
+	oldestFirst := func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }
+
+	path := make([]string, 0, depth)
+	for i := 0; i < depth; i++ {
+		path = append(path, "Children")
+		relation := strings.Join(path, ".")
+		query = query.Preload(relation, oldestFirst).Preload(relation + ".User")
+	}
+	return query
+}
+
+// visibleComments returns comments without those from users hidden by the block
+// list. Replies to a hidden comment are hidden along with it.
+func (b *userBlockList) visibleComments(comments []models.Comment) []models.Comment {
+	visible := make([]models.Comment, 0, len(comments))
+	for _, comment := range comments {
+		if b.hides(comment.UserID) {
+			continue
+		}
+		if len(comment.Children) > 0 {
+			comment.Children = b.visibleComments(comment.Children)
+		}
+		visible = append(visible, comment)
+	}
+	return visible
+}
+
+// ListBugComments returns a page of a bug's top-level comments, oldest first, each
+// with its full tree of replies
+func (h *BugHandler) ListBugComments(c *gin.Context) {
+	bugUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid bug ID format").Response(c)
+		return
+	}
+
+	var req ListBugCommentsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		errors.ErrValidationError.WithMessage("Invalid query parameters").WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+
+	req.Limit = commentPagination.Limit(req.Limit)
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+
+	// Verify bug exists
+	var bug models.BugReport
+	if err := h.db.Select("id").First(&bug, "id = ?", bugUUID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrBugNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to verify bug report").Response(c)
+		return
+	}
+
+	// Deleted comments are listed, without content, so replies keep their place
+	query := h.db.Unscoped().Model(&models.Comment{}).Where("bug_id = ? AND parent_id IS NULL", bugUUID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		errors.ErrCountFailed.WithMessage("Failed to count bug comments").Response(c)
+		return
+	}
+
+	var comments []models.Comment
+	if err := query.Preload("User").
+		Order("created_at ASC").
+		Offset((req.Page - 1) * req.Limit).
+		Limit(req.Limit).
+		Find(&comments).Error; err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to fetch bug comments").Response(c)
+		return
+	}
+
+	if err := h.loadCommentTrees(comments); err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to fetch comment replies").Response(c)
+		return
+	}
+
+	// Hide comments between the current user and users they have a block with
+	blocks, ok, err := h.currentUserBlockList(c)
+	if err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to check user blocks").Response(c)
+		return
+	}
+	if ok {
+		comments = blocks.visibleComments(comments)
+	}
+
+	// Calculate pagination info
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+
+	c.JSON(http.StatusOK, gin.H{
+		"comments": comments,
+		"pagination": gin.H{
+			"page":        req.Page,
+			"limit":       req.Limit,
+			"total":       total,
+			"total_pages": totalPages,
+			"has_next":    req.Page < totalPages,
+			"has_prev":    req.Page > 1,
+		},
+	})
+}
+
+// loadCommentTrees loads the replies to comments at every depth, one query per
+// level, and nests each under the comment it answers
+func (h *BugHandler) loadCommentTrees(comments []models.Comment) error {
+	var replies []models.Comment
+	parentIDs := make([]uuid.UUID, 0, len(comments))
+	for _, comment := range comments {
+		parentIDs = append(parentIDs, comment.ID)
+	}
+
+	for len(parentIDs) > 0 {
+		var level []models.Comment
+		if err := h.db.Unscoped().Preload("User").Where("parent_id IN ?", parentIDs).Order("created_at ASC").Find(&level).Error; err != nil {
+			return err
+		}
+
+		parentIDs = parentIDs[:0]
+		for _, reply := range level {
+			parentIDs = append(parentIDs, reply.ID)
+		}
+		replies = append(replies, level...)
+	}
+
+	byParent := make(map[uuid.UUID][]models.Comment)
+	for _, reply := range replies {
+		byParent[*reply.ParentID] = append(byParent[*reply.ParentID], reply)
+	}
+
+	var attach func(comments []models.Comment)
+	attach = func(comments []models.Comment) {
+		for i := range comments {
+			comments[i].Children = byParent[comments[i].ID]
+			attach(comments[i].Children)
+		}
+	}
+	attach(comments)
+	return nil
+}
+
+// UpdateCommentRequest represents the request to edit a comment
+type UpdateCommentRequest struct {
+	Content string `json:"content" binding:"required,comment_min,max=2000"`
+}
+
+// UpdateComment replaces the content of a comment. Only the author can edit a
+// comment, within 24 hours of posting it. The previous content is kept in the
+// comment's edit history.
+//
+// @Summary     Edit a comment
+// @Description Replaces the content of a comment. Authors can edit their comments for 24 hours after posting them. The previous content is added to the comment's edit_history.
+// @Tags        bugs
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Bug ID" format(uuid)
+// @Param       comment_id path string true "Comment ID" format(uuid)
+// @Param       request body UpdateCommentRequest true "New content"
+// @Success     200 {object} object{message=string,comment=models.Comment}
+// @Failure     400 {object} errors.ErrorResponse "Invalid ID or content"
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse "Not the author, or the edit window has passed"
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     413 {object} errors.ErrorResponse
+// @Failure     422 {object} errors.ErrorResponse "Too few non-whitespace characters"
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /bugs/{id}/comments/{comment_id} [put]
+func (h *BugHandler) UpdateComment(c *gin.Context) {
+	bugUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid bug ID format").Response(c)
+		return
+	}
+	commentUUID, err := uuid.Parse(c.Param("comment_id"))
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid comment ID format").Response(c)
+		return
+	}
+
+	var req UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		if commentTooShort(c, err, req.Content) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+	if commentMostlyWhitespace(c, req.Content) {
+		return
+	}
+
+	userUUID, ok := currentCommentUser(c)
+	if !ok {
+		return
+	}
+
+	var comment models.Comment
+	if err := h.db.First(&comment, "id = ? AND bug_id = ?", commentUUID, bugUUID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrCommentNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch comment").Response(c)
+		return
+	}
+
+	now := time.Now()
+	if comment.UserID != userUUID {
+		errors.ErrInsufficientPermissions.WithMessage("Only the author can edit this comment").Response(c)
+		return
+	}
+	if !comment.EditableBy(userUUID, now) {
+		errors.ErrCommentEditWindowExpired.Response(c)
+		return
+	}
+
+	sanitizedContent, contentValid := utils.ValidateString(req.Content, 1, 2000)
+	if !contentValid {
+		errors.ErrInvalidContent.WithMessage("Comment content must be between 1 and 2000 characters and contain no malicious content").Response(c)
+		return
+	}
+
+	// Saving the same content again is not an edit
+	if sanitizedContent != comment.Content {
+		err := h.db.Transaction(func(tx *gorm.DB) error {
+			edit := models.CommentEdit{
+				CommentID: comment.ID,
+				Content:   comment.Content,
+				EditedAt:  now,
+			}
+			if err := tx.Create(&edit).Error; err != nil {
+				return err
+			}
+
+			return tx.Model(&comment).Updates(map[string]interface{}{
+				"content":   sanitizedContent,
+				"edited_at": now,
+			}).Error
+		})
+		if err != nil {
+			errors.ErrUpdateFailed.WithMessage("Failed to update comment").Response(c)
+			return
+		}
+
+		h.invalidateCommentBug(c.Request.Context(), bugUUID)
+	}
+
+	var updated models.Comment
+	if err := h.db.Preload("User").
+		Preload("EditHistory", func(db *gorm.DB) *gorm.DB { return db.Order("edited_at ASC") }).
+		First(&updated, "id = ?", comment.ID).Error; err != nil {
+		errors.ErrLoadFailed.WithMessage("Comment updated but failed to load details").Response(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Comment updated successfully",
+		"comment": updated,
+	})
+}
+
+// DeleteComment deletes a comment. Authors can delete their comments within 24
+// hours of posting them; the comment stays in its thread, without content, so
+// replies keep their place. Admins of the assigned company and platform admins
+// can remove any comment, including ones their authors already deleted, which
+// deletes it and its replies permanently.
+//
+// @Summary     Delete a comment
+// @Description Authors can delete their comments for 24 hours after posting them. The comment is kept in its thread with is_deleted set and no content. Admins of the assigned company and platform admins remove the comment and its replies permanently, including comments their authors already deleted.
+// @Tags        bugs
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Bug ID" format(uuid)
+// @Param       comment_id path string true "Comment ID" format(uuid)
+// @Success     200 {object} object{message=string,permanent=bool}
+// @Failure     400 {object} errors.ErrorResponse
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse "Not the author or an admin, or the edit window has passed"
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /bugs/{id}/comments/{comment_id} [delete]
+func (h *BugHandler) DeleteComment(c *gin.Context) {
+	bugUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid bug ID format").Response(c)
+		return
+	}
+	commentUUID, err := uuid.Parse(c.Param("comment_id"))
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid comment ID format").Response(c)
+		return
+	}
+
+	userUUID, ok := currentCommentUser(c)
+	if !ok {
+		return
+	}
+
+	var bug models.BugReport
+	if err := h.db.Select("id", "assigned_company_id").First(&bug, "id = ?", bugUUID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrBugNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to verify bug report").Response(c)
+		return
+	}
+
+	// Comments their authors deleted are loaded too, so admins can remove them
+	var comment models.Comment
+	if err := h.db.Unscoped().First(&comment, "id = ? AND bug_id = ?", commentUUID, bugUUID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrCommentNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch comment").Response(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	// Authors delete their own comments softly, even when they are also an admin
+	if !comment.DeletedAt.Valid && comment.EditableBy(userUUID, time.Now()) {
+		if err := h.db.Delete(&comment).Error; err != nil {
+			errors.ErrDeleteFailed.WithMessage("Failed to delete comment").Response(c)
+			return
+		}
+
+		h.invalidateCommentBug(ctx, bugUUID)
+		c.JSON(http.StatusOK, gin.H{
+			"message":   "Comment deleted successfully",
+			"permanent": false,
+		})
+		return
+	}
+
+	canRemove, err := h.canRemoveComments(c, &bug, userUUID)
+	if err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to check company membership").Response(c)
+		return
+	}
+	if !canRemove {
+		if comment.DeletedAt.Valid {
+			errors.ErrCommentNotFound.Response(c)
+			return
+		}
+		if comment.UserID == userUUID {
+			errors.ErrCommentEditWindowExpired.Response(c)
+			return
+		}
+		errors.ErrInsufficientPermissions.WithMessage("Only the author or a company admin can delete this comment").Response(c)
+		return
+	}
+
+	// Replies are removed with the comment by the parent_id foreign key
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Delete(&comment).Error; err != nil {
+			return err
+		}
+
+		// Only top-level comments are counted
+		if comment.ParentID != nil {
+			return nil
+		}
+		return tx.Model(&models.BugReport{}).
+			Where("id = ? AND comment_count > 0", bugUUID).
+			Update("comment_count", gorm.Expr("comment_count - 1")).Error
+	})
+	if err != nil {
+		errors.ErrDeleteFailed.WithMessage("Failed to delete comment").Response(c)
+		return
+	}
+
+	details := fmt.Sprintf("Comment removed from bug %s", bugUUID)
+	if err := createAuditLog(h.db, c, models.AuditActionCommentDelete, models.AuditResourceComment, &comment.ID, details, comment, nil); err != nil {
+		// Log error but don't fail the request since the comment was already removed
+		logger.FromContext(ctx).Error("Failed to log audit action", err)
+	}
+
+	h.invalidateCommentBug(ctx, bugUUID)
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Comment removed permanently",
+		"permanent": true,
+	})
+}
+
+// currentCommentUser returns the current user. Errors are written to the response
+// and reported by returning false.
+func currentCommentUser(c *gin.Context) (uuid.UUID, bool) {
+	userIDStr, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		errors.ErrAuthRequired.WithMessage("Authentication required to manage comments").Response(c)
+		return uuid.Nil, false
+	}
+	userUUID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		errors.ErrInvalidUser.Response(c)
+		return uuid.Nil, false
+	}
+	return userUUID, true
+}
+
+// canRemoveComments reports whether the user can permanently remove comments on the
+// bug: platform admins and admins of the assigned company can
+func (h *BugHandler) canRemoveComments(c *gin.Context, bug *models.BugReport, userID uuid.UUID) (bool, error) {
+	if middleware.IsCurrentUserAdmin(c) {
+		return true, nil
+	}
+	if bug.AssignedCompanyID == nil {
+		return false, nil
+	}
+
+	var count int64
+	err := h.db.Model(&models.CompanyMember{}).
+		Where("company_id = ? AND user_id = ? AND role = ?", *bug.AssignedCompanyID, userID, models.CompanyRoleAdmin).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// invalidateCommentBug clears the cached bug, including its comments, after a
+// comment changes
+func (h *BugHandler) invalidateCommentBug(ctx context.Context, bugID uuid.UUID) {
+	if err := h.cache.InvalidateBug(ctx, bugID.String()); err != nil {
+		logger.FromContext(ctx).Error("Failed to invalidate bug cache", err, logger.Fields{"bug_id": bugID.String()})
+	}
+}
@@ -0,0 +1,384 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBugHandler_CommentReplies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	user := createTestUser(t, db)
+	app := createTestApplication(t, db)
+	bug := createTestBugReport(t, db, app, user)
+
+	postComment := func(bugID uuid.UUID, body gin.H) (*httptest.ResponseRecorder, map[string]interface{}) {
+		encoded, err := json.Marshal(body)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", fmt.Sprintf("/bugs/%s/comments", bugID), bytes.NewBuffer(encoded))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "id", Value: bugID.String()}}
+
+		mockAuthMiddleware(user.ID)(c)
+		handler.CreateComment(c)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w, response
+	}
+
+	// A thread five levels deep: a top-level comment and four nested replies
+	var thread []string
+	for depth := 0; depth < 5; depth++ {
+		body := gin.H{"content": fmt.Sprintf("Reply at depth %d with enough detail", depth)}
+		if depth > 0 {
+			body["parent_id"] = thread[depth-1]
+		}
+
+		w, response := postComment(bug.ID, body)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		comment := response["comment"].(map[string]interface{})
+		if depth > 0 {
+			assert.Equal(t, thread[depth-1], comment["parent_id"])
+		} else {
+			assert.NotContains(t, comment, "parent_id")
+		}
+		thread = append(thread, comment["id"].(string))
+	}
+
+	t.Run("only top-level comments are counted", func(t *testing.T) {
+		var updated models.BugReport
+		require.NoError(t, db.First(&updated, "id = ?", bug.ID).Error)
+		assert.Equal(t, 1, updated.CommentCount)
+	})
+
+	t.Run("parent must be a comment on the same bug", func(t *testing.T) {
+		otherBug := createTestBugReport(t, db, app, user)
+
+		w, response := postComment(otherBug.ID, gin.H{"content": "Replying across bugs should fail", "parent_id": thread[0]})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "INVALID_PARENT_COMMENT", response["error"].(map[string]interface{})["code"])
+
+		w, response = postComment(bug.ID, gin.H{"content": "Replying to a missing comment", "parent_id": uuid.New()})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "INVALID_PARENT_COMMENT", response["error"].(map[string]interface{})["code"])
+
+		var count int64
+		require.NoError(t, db.Model(&models.Comment{}).Count(&count).Error)
+		assert.Equal(t, int64(5), count)
+	})
+
+	listComments := func(query string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", fmt.Sprintf("/bugs/%s/comments%s", bug.ID, query), nil)
+		c.Params = gin.Params{{Key: "id", Value: bug.ID.String()}}
+
+		handler.ListBugComments(c)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w, response
+	}
+
+	// threadIDs follows the first reply down from comment, returning the IDs at each depth
+	threadIDs := func(comment map[string]interface{}) []string {
+		var ids []string
+		for comment != nil {
+			ids = append(ids, comment["id"].(string))
+			children, _ := comment["children"].([]interface{})
+			if len(children) == 0 {
+				break
+			}
+			require.Len(t, children, 1)
+			comment = children[0].(map[string]interface{})
+		}
+		return ids
+	}
+
+	t.Run("listing returns the full tree", func(t *testing.T) {
+		w, response := listComments("")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		comments := response["comments"].([]interface{})
+		require.Len(t, comments, 1)
+		assert.Equal(t, thread, threadIDs(comments[0].(map[string]interface{})))
+	})
+
+	t.Run("get bug loads three levels of replies", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", fmt.Sprintf("/bugs/%s?include=comments", bug.ID), nil)
+		c.Params = gin.Params{{Key: "id", Value: bug.ID.String()}}
+
+		handler.GetBug(c)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		comments := response["bug"].(map[string]interface{})["comments"].([]interface{})
+		require.Len(t, comments, 1)
+		assert.Equal(t, thread[:4], threadIDs(comments[0].(map[string]interface{})))
+	})
+
+	t.Run("top-level comments are paginated", func(t *testing.T) {
+		base := time.Now().Add(time.Hour)
+		for i := 0; i < 11; i++ {
+			comment := &models.Comment{
+				ID:        uuid.New(),
+				BugID:     bug.ID,
+				UserID:    user.ID,
+				Content:   fmt.Sprintf("Later comment %d", i),
+				CreatedAt: base.Add(time.Duration(i) * time.Minute),
+			}
+			require.NoError(t, db.Create(comment).Error)
+		}
+
+		w, response := listComments("")
+		require.Equal(t, http.StatusOK, w.Code)
+		comments := response["comments"].([]interface{})
+		require.Len(t, comments, 10)
+		assert.Equal(t, thread, threadIDs(comments[0].(map[string]interface{})))
+
+		pagination := response["pagination"].(map[string]interface{})
+		assert.Equal(t, float64(12), pagination["total"])
+		assert.Equal(t, float64(10), pagination["limit"])
+		assert.Equal(t, true, pagination["has_next"])
+
+		w, response = listComments("?page=2")
+		require.Equal(t, http.StatusOK, w.Code)
+		comments = response["comments"].([]interface{})
+		require.Len(t, comments, 2)
+		assert.Equal(t, "Later comment 10", comments[1].(map[string]interface{})["content"])
+		assert.Equal(t, false, response["pagination"].(map[string]interface{})["has_next"])
+	})
+
+	t.Run("replies from blocked users are hidden with their replies", func(t *testing.T) {
+		blocker := &models.User{ID: uuid.New(), Email: "blocker@example.com", DisplayName: "Blocker"}
+		require.NoError(t, db.Create(blocker).Error)
+		require.NoError(t, db.Create(&models.UserBlock{BlockerID: blocker.ID, BlockedID: user.ID}).Error)
+
+		other := &models.User{ID: uuid.New(), Email: "other@example.com", DisplayName: "Other"}
+		require.NoError(t, db.Create(other).Error)
+		top := &models.Comment{ID: uuid.New(), BugID: bug.ID, UserID: other.ID, Content: "Top-level from another user", CreatedAt: time.Now().Add(-time.Hour)}
+		require.NoError(t, db.Create(top).Error)
+		reply := &models.Comment{ID: uuid.New(), BugID: bug.ID, UserID: user.ID, ParentID: &top.ID, Content: "Reply from the blocked user"}
+		require.NoError(t, db.Create(reply).Error)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", fmt.Sprintf("/bugs/%s/comments", bug.ID), nil)
+		c.Params = gin.Params{{Key: "id", Value: bug.ID.String()}}
+		mockAuthMiddleware(blocker.ID)(c)
+
+		handler.ListBugComments(c)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		comments := response["comments"].([]interface{})
+		require.Len(t, comments, 1)
+		first := comments[0].(map[string]interface{})
+		assert.Equal(t, top.ID.String(), first["id"])
+		assert.NotContains(t, first, "children")
+	})
+}
+
+// commentRequest calls a comment handler for the comment as the user, with an
+// optional JSON body
+func commentRequest(t *testing.T, handler gin.HandlerFunc, method string, comment *models.Comment, auth gin.HandlerFunc, body gin.H) (*httptest.ResponseRecorder, map[string]interface{}) {
+	var encoded []byte
+	if body != nil {
+		var err error
+		encoded, err = json.Marshal(body)
+		require.NoError(t, err)
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, fmt.Sprintf("/bugs/%s/comments/%s", comment.BugID, comment.ID), bytes.NewBuffer(encoded))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: comment.BugID.String()}, {Key: "comment_id", Value: comment.ID.String()}}
+	auth(c)
+
+	handler(c)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return w, response
+}
+
+func TestBugHandler_UpdateComment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	author := createTestUser(t, db)
+	app := createTestApplication(t, db)
+	bug := createTestBugReport(t, db, app, author)
+
+	comment := &models.Comment{ID: uuid.New(), BugID: bug.ID, UserID: author.ID, Content: "The original comment"}
+	require.NoError(t, db.Create(comment).Error)
+
+	edit := func(auth gin.HandlerFunc, content string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		return commentRequest(t, handler.UpdateComment, "PUT", comment, auth, gin.H{"content": content})
+	}
+
+	t.Run("edits keep the previous content in order", func(t *testing.T) {
+		w, response := edit(mockAuthMiddleware(author.ID), "The first edit of the comment")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		updated := response["comment"].(map[string]interface{})
+		assert.Equal(t, "The first edit of the comment", updated["content"])
+		assert.Equal(t, true, updated["is_edited"])
+		assert.Equal(t, false, updated["is_deleted"])
+
+		// Saving the same content again does not add to the history
+		w, _ = edit(mockAuthMiddleware(author.ID), "The first edit of the comment")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		w, response = edit(mockAuthMiddleware(author.ID), "The second edit of the comment")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		history := response["comment"].(map[string]interface{})["edit_history"].([]interface{})
+		require.Len(t, history, 2)
+		assert.Equal(t, "The original comment", history[0].(map[string]interface{})["content"])
+		assert.Equal(t, "The first edit of the comment", history[1].(map[string]interface{})["content"])
+
+		var edits []models.CommentEdit
+		require.NoError(t, db.Where("comment_id = ?", comment.ID).Order("edited_at ASC").Find(&edits).Error)
+		require.Len(t, edits, 2)
+		assert.Equal(t, "The original comment", edits[0].Content)
+	})
+
+	t.Run("only the author can edit", func(t *testing.T) {
+		other := &models.User{ID: uuid.New(), Email: "other@example.com", DisplayName: "Other"}
+		require.NoError(t, db.Create(other).Error)
+
+		w, response := edit(mockAdminAuthMiddleware(other.ID), "An edit from someone else")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "INSUFFICIENT_PERMISSIONS", response["error"].(map[string]interface{})["code"])
+	})
+
+	t.Run("edits close after 24 hours", func(t *testing.T) {
+		require.NoError(t, db.Model(comment).Update("created_at", time.Now().Add(-25*time.Hour)).Error)
+
+		w, response := edit(mockAuthMiddleware(author.ID), "An edit that is too late")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "COMMENT_EDIT_WINDOW_EXPIRED", response["error"].(map[string]interface{})["code"])
+
+		var unchanged models.Comment
+		require.NoError(t, db.First(&unchanged, "id = ?", comment.ID).Error)
+		assert.Equal(t, "The second edit of the comment", unchanged.Content)
+	})
+}
+
+func TestBugHandler_DeleteComment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	author := createTestUser(t, db)
+	company := createTestCompany(t, db, true)
+	app := createTestCompanyApplication(t, db, company)
+	bug := createTestBugReport(t, db, app, author)
+	require.NoError(t, db.Model(bug).Updates(map[string]interface{}{"assigned_company_id": company.ID, "comment_count": 2}).Error)
+
+	companyAdmin := &models.User{ID: uuid.New(), Email: "admin@testcompany.com", DisplayName: "Company Admin"}
+	require.NoError(t, db.Create(companyAdmin).Error)
+	createTestCompanyMember(t, db, company.ID, companyAdmin.ID, models.CompanyRoleAdmin)
+	stranger := &models.User{ID: uuid.New(), Email: "stranger@example.com", DisplayName: "Stranger"}
+	require.NoError(t, db.Create(stranger).Error)
+
+	first := &models.Comment{ID: uuid.New(), BugID: bug.ID, UserID: author.ID, Content: "A comment the author deletes", CreatedAt: time.Now().Add(-time.Hour)}
+	reply := &models.Comment{ID: uuid.New(), BugID: bug.ID, UserID: stranger.ID, ParentID: &first.ID, Content: "A reply to the deleted comment"}
+	second := &models.Comment{ID: uuid.New(), BugID: bug.ID, UserID: author.ID, Content: "An old comment", CreatedAt: time.Now().Add(-48 * time.Hour)}
+	for _, comment := range []*models.Comment{first, reply, second} {
+		require.NoError(t, db.Create(comment).Error)
+	}
+
+	commentCount := func() int {
+		var updated models.BugReport
+		require.NoError(t, db.First(&updated, "id = ?", bug.ID).Error)
+		return updated.CommentCount
+	}
+
+	t.Run("strangers cannot delete", func(t *testing.T) {
+		w, response := commentRequest(t, handler.DeleteComment, "DELETE", first, mockAuthMiddleware(stranger.ID), nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "INSUFFICIENT_PERMISSIONS", response["error"].(map[string]interface{})["code"])
+	})
+
+	t.Run("authors delete softly within 24 hours", func(t *testing.T) {
+		w, response := commentRequest(t, handler.DeleteComment, "DELETE", first, mockAuthMiddleware(author.ID), nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, false, response["permanent"])
+		assert.Equal(t, 2, commentCount())
+
+		// The comment stays in the thread without content, with its reply
+		w = httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", fmt.Sprintf("/bugs/%s/comments", bug.ID), nil)
+		c.Params = gin.Params{{Key: "id", Value: bug.ID.String()}}
+		handler.ListBugComments(c)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var listing map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listing))
+		comments := listing["comments"].([]interface{})
+		require.Len(t, comments, 2)
+		deleted := comments[1].(map[string]interface{})
+		assert.Equal(t, first.ID.String(), deleted["id"])
+		assert.Equal(t, true, deleted["is_deleted"])
+		assert.Equal(t, "", deleted["content"])
+		children := deleted["children"].([]interface{})
+		require.Len(t, children, 1)
+		assert.Equal(t, "A reply to the deleted comment", children[0].(map[string]interface{})["content"])
+
+		// Deleted comments cannot be deleted again or edited
+		w, _ = commentRequest(t, handler.DeleteComment, "DELETE", first, mockAuthMiddleware(author.ID), nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		w, _ = commentRequest(t, handler.UpdateComment, "PUT", first, mockAuthMiddleware(author.ID), gin.H{"content": "Editing a deleted comment"})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("authors cannot delete after 24 hours", func(t *testing.T) {
+		w, response := commentRequest(t, handler.DeleteComment, "DELETE", second, mockAuthMiddleware(author.ID), nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "COMMENT_EDIT_WINDOW_EXPIRED", response["error"].(map[string]interface{})["code"])
+	})
+
+	t.Run("company admins remove comments permanently", func(t *testing.T) {
+		w, response := commentRequest(t, handler.DeleteComment, "DELETE", second, mockAuthMiddleware(companyAdmin.ID), nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, true, response["permanent"])
+		assert.Equal(t, 1, commentCount())
+
+		var count int64
+		require.NoError(t, db.Unscoped().Model(&models.Comment{}).Where("id = ?", second.ID).Count(&count).Error)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("company admins remove comments their authors deleted", func(t *testing.T) {
+		w, response := commentRequest(t, handler.DeleteComment, "DELETE", first, mockAuthMiddleware(companyAdmin.ID), nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, true, response["permanent"])
+		assert.Equal(t, 0, commentCount())
+
+		var count int64
+		require.NoError(t, db.Unscoped().Model(&models.Comment{}).Where("id = ?", first.ID).Count(&count).Error)
+		assert.Equal(t, int64(0), count)
+	})
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Directions of a cursor page from its cursor
+const (
+	CursorDirectionAfter  = "after"
+	CursorDirectionBefore = "before"
+)
+
+// bugCursor is the position of a bug in creation order, with the ID breaking ties
+// between bugs created at the same time
+type bugCursor struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        uuid.UUID `json:"id"`
+}
+
+// encodeBugCursor returns the opaque cursor of bug's position
+func encodeBugCursor(bug models.BugReport) string {
+	encoded, _ := json.Marshal(bugCursor{CreatedAt: bug.CreatedAt, ID: bug.ID})
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+// decodeBugCursor parses a cursor returned by encodeBugCursor
+func decodeBugCursor(raw string) (bugCursor, error) {
+	var cursor bugCursor
+	decoded, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return cursor, err
+	}
+	if err := json.Unmarshal(decoded, &cursor); err != nil {
+		return cursor, err
+	}
+	return cursor, nil
+}
+
+// orderByBugCursor orders bugs by creation, newest first unless ascending, and
+// limits them to the bugs after the cursor, or before it when before is set. A nil
+// cursor starts from the first bug. Pages before the cursor are fetched walking the
+// order backwards, so their bugs must be put back in order with reverseBugs.
+func orderByBugCursor(query *gorm.DB, cursor *bugCursor, ascending, before bool) *gorm.DB {
+	comparison, order := "<", "DESC"
+	if ascending != before {
+		comparison, order = ">", "ASC"
+	}
+
+	if cursor != nil {
+		query = query.Where(
+			"(bug_reports.created_at "+comparison+" ? OR (bug_reports.created_at = ? AND bug_reports.id "+comparison+" ?))",
+			cursor.CreatedAt, cursor.CreatedAt, cursor.ID,
+		)
+	}
+	return query.Order("bug_reports.created_at " + order).Order("bug_reports.id " + order)
+}
+
+// reverseBugs reverses bugs in place
+func reverseBugs(bugs []models.BugReport) {
+	for i, j := 0, len(bugs)-1; i < j; i, j = i+1, j-1 {
+		bugs[i], bugs[j] = bugs[j], bugs[i]
+	}
+}
+
+// listBugsByCursor responds with the page of bugs next to the cursor. Unlike offset
+// pages, the bugs before the page are not skipped over and the total is not counted,
+// and the page does not shift when bugs are submitted while a client pages through.
+func (h *BugHandler) listBugsByCursor(c *gin.Context, req ListBugsRequest, query *gorm.DB, cursor *bugCursor) {
+	before := req.Direction == CursorDirectionBefore
+	query = orderByBugCursor(query, cursor, req.Sort == "oldest", before)
+
+	// Fetch one extra bug to tell whether there is another page in this direction
+	bugs := make([]models.BugReport, 0, req.Limit+1)
+	if err := query.Limit(req.Limit + 1).Find(&bugs).Error; err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to fetch bug reports").Response(c)
+		return
+	}
+
+	hasMore := len(bugs) > req.Limit
+	if hasMore {
+		bugs = bugs[:req.Limit]
+	}
+
+	// The bug the cursor came from is on the other side of the page. An empty page
+	// has no position to continue from.
+	hasNext, hasPrev := hasMore, true
+	if before {
+		reverseBugs(bugs)
+		hasNext, hasPrev = true, hasMore
+	}
+	if len(bugs) == 0 {
+		hasNext, hasPrev = false, false
+	}
+
+	pagination := gin.H{
+		"limit":    req.Limit,
+		"has_next": hasNext,
+		"has_prev": hasPrev,
+	}
+	setBugCursors(pagination, bugs, hasNext, hasPrev)
+
+	h.respondBugList(c, req, bugs, pagination)
+}
+
+// setBugCursors adds the cursors of the pages after and before bugs to pagination
+func setBugCursors(pagination gin.H, bugs []models.BugReport, hasNext, hasPrev bool) {
+	pagination["next_cursor"] = nil
+	pagination["prev_cursor"] = nil
+	if hasNext && len(bugs) > 0 {
+		pagination["next_cursor"] = encodeBugCursor(bugs[len(bugs)-1])
+	}
+	if hasPrev && len(bugs) > 0 {
+		pagination["prev_cursor"] = encodeBugCursor(bugs[0])
+	}
+}
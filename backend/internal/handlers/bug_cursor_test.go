@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// cursorListResponse is the body of a ListBugs response
+type cursorListResponse struct {
+	Bugs       []models.BugReport `json:"bugs"`
+	Pagination struct {
+		HasNext    bool    `json:"has_next"`
+		HasPrev    bool    `json:"has_prev"`
+		NextCursor *string `json:"next_cursor"`
+		PrevCursor *string `json:"prev_cursor"`
+	} `json:"pagination"`
+}
+
+// createBugsAt creates a bug created at each of the times
+func createBugsAt(t *testing.T, db *gorm.DB, app *models.Application, reporter *models.User, times ...time.Time) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(times))
+	for _, at := range times {
+		bug := createTestBugReport(t, db, app, reporter)
+		require.NoError(t, db.Model(bug).Update("created_at", at).Error)
+		ids = append(ids, bug.ID)
+	}
+	return ids
+}
+
+func TestBugHandler_ListBugs_Cursor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	user := createTestUser(t, db)
+	app := createTestApplication(t, db)
+
+	// Seven bugs, three of them created at the same instant so pages split ties by ID
+	base := time.Now().Add(-time.Hour).UTC().Truncate(time.Microsecond)
+	original := createBugsAt(t, db, app, user,
+		base, base, base, base.Add(time.Minute), base.Add(2*time.Minute), base.Add(3*time.Minute), base.Add(4*time.Minute))
+
+	router := gin.New()
+	router.GET("/bugs", handler.ListBugs)
+
+	list := func(t *testing.T, params url.Values) cursorListResponse {
+		params.Set("limit", "2")
+		req, _ := http.NewRequest("GET", "/bugs?"+params.Encode(), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response cursorListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	// pageThrough follows next_cursor from the first page, calling between after each
+	// page, and returns the IDs listed and the last page
+	pageThrough := func(t *testing.T, sort string, between func()) ([]uuid.UUID, cursorListResponse) {
+		var ids []uuid.UUID
+		page := list(t, url.Values{"sort": {sort}})
+		for {
+			for _, bug := range page.Bugs {
+				ids = append(ids, bug.ID)
+			}
+			between()
+			if page.Pagination.NextCursor == nil {
+				return ids, page
+			}
+			page = list(t, url.Values{"sort": {sort}, "cursor": {*page.Pagination.NextCursor}})
+		}
+	}
+
+	var inserted []uuid.UUID
+	insertNewBug := func() {
+		inserted = append(inserted, createBugsAt(t, db, app, user, time.Now().UTC())...)
+	}
+
+	t.Run("recent pages list every bug once while bugs are submitted", func(t *testing.T) {
+		ids, last := pageThrough(t, "recent", insertNewBug)
+
+		// Bugs submitted after the first page was fetched are newer than the
+		// cursor, so they don't push older bugs onto the next page
+		assert.ElementsMatch(t, original, ids)
+		assert.False(t, last.Pagination.HasNext)
+		assert.True(t, last.Pagination.HasPrev)
+
+		t.Run("and back again with prev_cursor", func(t *testing.T) {
+			var ids []uuid.UUID
+			for page := last; ; {
+				pageIDs := make([]uuid.UUID, 0, len(page.Bugs))
+				for _, bug := range page.Bugs {
+					pageIDs = append(pageIDs, bug.ID)
+				}
+				ids = append(pageIDs, ids...)
+				if page.Pagination.PrevCursor == nil {
+					break
+				}
+				page = list(t, url.Values{"cursor": {*page.Pagination.PrevCursor}, "direction": {CursorDirectionBefore}})
+				assert.True(t, page.Pagination.HasNext)
+			}
+
+			// Going back reaches the bugs submitted since, in the same order
+			var expected []uuid.UUID
+			require.NoError(t, db.Model(&models.BugReport{}).Order("created_at DESC").Order("id DESC").Pluck("id", &expected).Error)
+			assert.Equal(t, expected, ids)
+		})
+	})
+
+	t.Run("oldest pages list every bug once while bugs are submitted", func(t *testing.T) {
+		before := append(append([]uuid.UUID{}, original...), inserted...)
+		inserted = nil
+		ids, _ := pageThrough(t, "oldest", insertNewBug)
+
+		// Bugs submitted mid-iteration come after the cursor and are listed at the end
+		require.GreaterOrEqual(t, len(ids), len(before))
+		assert.ElementsMatch(t, before, ids[:len(before)])
+		seen := map[uuid.UUID]bool{}
+		for _, id := range ids {
+			assert.False(t, seen[id], "bug %s listed twice", id)
+			seen[id] = true
+		}
+	})
+
+	t.Run("offset pages return cursors", func(t *testing.T) {
+		first := list(t, url.Values{})
+		require.NotNil(t, first.Pagination.NextCursor)
+		assert.Nil(t, first.Pagination.PrevCursor)
+
+		second := list(t, url.Values{"page": {"2"}})
+		next := list(t, url.Values{"cursor": {*first.Pagination.NextCursor}})
+		assert.Equal(t, second.Bugs[0].ID, next.Bugs[0].ID)
+	})
+
+	t.Run("invalid cursors are rejected", func(t *testing.T) {
+		cursor := encodeBugCursor(models.BugReport{ID: original[0], CreatedAt: base})
+		tests := []struct {
+			name   string
+			params url.Values
+		}{
+			{"malformed cursor", url.Values{"cursor": {"not-a-cursor"}}},
+			{"unknown direction", url.Values{"cursor": {cursor}, "direction": {"sideways"}}},
+			{"sort by votes", url.Values{"cursor": {cursor}, "sort": {"popular"}}},
+			{"search", url.Values{"cursor": {cursor}, "search": {"crash"}}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				req, _ := http.NewRequest("GET", "/bugs?"+tt.params.Encode(), nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+				assert.Contains(t, w.Body.String(), "INVALID_CURSOR")
+			})
+		}
+	})
+}
@@ -0,0 +1,405 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/middleware"
+	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// UpdateBugRequest represents the request to edit a bug report. Fields left out
+// are unchanged; an empty technical field clears it.
+type UpdateBugRequest struct {
+	Title       *string  `json:"title,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Priority    *string  `json:"priority,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+
+	// Technical details
+	OperatingSystem *string `json:"operating_system,omitempty"`
+	DeviceType      *string `json:"device_type,omitempty"`
+	AppVersion      *string `json:"app_version,omitempty"`
+	BrowserVersion  *string `json:"browser_version,omitempty"`
+
+	// ApplicationURL moves the bug to the application at this URL
+	ApplicationURL *string `json:"application_url,omitempty"`
+}
+
+// onlyPriority reports whether the request changes nothing but the priority
+func (r *UpdateBugRequest) onlyPriority() bool {
+	return r.Title == nil && r.Description == nil && r.Tags == nil &&
+		r.OperatingSystem == nil && r.DeviceType == nil && r.AppVersion == nil &&
+		r.BrowserVersion == nil && r.ApplicationURL == nil
+}
+
+// bugFieldEdit is a change to one field of a bug report. Columns in alsoSet are
+// written with it but not recorded in the edit history.
+type bugFieldEdit struct {
+	field    string
+	column   string
+	oldValue string
+	newValue string
+	value    interface{}
+	alsoSet  map[string]interface{}
+}
+
+// UpdateBug edits a bug report. The reporter and admins can change any field;
+// members of the assigned company can only change the priority. Each changed
+// field is recorded in the bug's edit history.
+//
+// @Summary     Edit a bug report
+// @Description Updates the fields given in the request. The reporter and admins can change any field; members of the assigned company can only change the priority. Titles and descriptions are validated as on submission. Setting application_url moves the bug to the application registered at that URL and assigns it to that application's company, as on submission. Each changed field is recorded in bug_edit_history, and the changes are returned.
+// @Tags        bugs
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Bug ID" format(uuid)
+// @Param       request body UpdateBugRequest true "Fields to change"
+// @Success     200 {object} object{message=string,bug=models.BugReport,changes=[]models.BugEditHistory}
+// @Failure     400 {object} errors.ErrorResponse "Invalid ID or field"
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse "Not the reporter, or a company member changing more than the priority"
+// @Failure     404 {object} errors.ErrorResponse "Bug, or application at application_url, not found"
+// @Failure     413 {object} errors.ErrorResponse
+// @Failure     422 {object} errors.ErrorResponse "Title or description too short, or the application is archived"
+// @Failure     500 {object} errors.ErrorResponse "Update failed, or the assignment rules of the new application's company could not be evaluated"
+// @Router      /bugs/{id} [patch]
+func (h *BugHandler) UpdateBug(c *gin.Context) {
+	bugUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid bug ID format").Response(c)
+		return
+	}
+
+	var req UpdateBugRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+
+	userUUID, ok := currentUserUUID(c)
+	if !ok {
+		return
+	}
+
+	var bug models.BugReport
+	if err := h.db.Preload("Application").First(&bug, bugUUID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrBugNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch bug report").Response(c)
+		return
+	}
+
+	isReporter := bug.ReporterID != nil && *bug.ReporterID == userUUID
+	if !isReporter && !middleware.IsCurrentUserAdmin(c) {
+		if !h.canManageBug(c, &bug, userUUID) {
+			errors.ErrInsufficientPermissions.WithMessage("Only the reporter can edit this bug report").Response(c)
+			return
+		}
+		if !req.onlyPriority() {
+			errors.ErrInsufficientPermissions.WithMessage("Company members can only change the priority of a bug report").Response(c)
+			return
+		}
+	}
+
+	edits, ok := h.validateBugEdits(c, &req, &bug)
+	if !ok {
+		return
+	}
+
+	beforeState := newBugAuditState(&bug)
+
+	var changes []models.BugEditHistory
+	if len(edits) > 0 {
+		now := time.Now()
+		changes, err = h.applyBugEdits(bugUUID, userUUID, edits, now)
+		if err != nil {
+			errors.ErrUpdateFailed.WithMessage("Failed to update bug report").Response(c)
+			return
+		}
+
+		ctx := c.Request.Context()
+		if _, err := h.projector.Project(ctx, bugUUID); err != nil {
+			errors.ErrUpdateFailed.WithMessage("Failed to update bug report").Response(c)
+			return
+		}
+		if err := h.cache.InvalidateBug(ctx, bugUUID.String()); err != nil {
+			// Log cache error but don't fail the request
+			logger.FromContext(ctx).Error("Failed to invalidate bug cache", err, logger.Fields{"bug_id": bugUUID.String()})
+		}
+	}
+
+	if err := h.db.Preload("Application").Preload("AssignedCompany").
+		First(&bug, bugUUID).Error; err != nil {
+		errors.ErrLoadFailed.WithMessage("Bug updated but failed to load bug details").Response(c)
+		return
+	}
+
+	if len(changes) > 0 {
+		fields := make([]string, len(changes))
+		for i, change := range changes {
+			fields[i] = change.FieldName
+		}
+		details := fmt.Sprintf("Bug report edited: %s", strings.Join(fields, ", "))
+		if err := createAuditLog(h.db, c, models.AuditActionBugEdit, models.AuditResourceBug, &bugUUID, details, beforeState, newBugAuditState(&bug)); err != nil {
+			// Log error but don't fail the request since the bug was already updated
+			logger.FromContext(c.Request.Context()).Error("Failed to log audit action", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Bug report updated successfully",
+		"bug":     bug,
+		"changes": changes,
+	})
+}
+
+// validateBugEdits sanitizes the fields of the request the same way CreateBug does
+// and returns those that differ from the bug. It writes the error response and
+// returns false when a field is invalid.
+func (h *BugHandler) validateBugEdits(c *gin.Context, req *UpdateBugRequest, bug *models.BugReport) ([]bugFieldEdit, bool) {
+	var edits []bugFieldEdit
+	addEdit := func(field, column, oldValue, newValue string, value interface{}) {
+		if oldValue != newValue {
+			edits = append(edits, bugFieldEdit{field: field, column: column, oldValue: oldValue, newValue: newValue, value: value})
+		}
+	}
+
+	// Reject titles and descriptions like "broken" unless an admin overrides the check
+	checkWords := !(c.GetHeader(adminOverrideHeader) == "true" && middleware.IsCurrentUserAdmin(c))
+
+	if req.Title != nil {
+		sanitizedTitle, titleValid := utils.ValidateString(*req.Title, 5, 255)
+		if !titleValid {
+			errors.ErrInvalidTitle.Response(c)
+			return nil, false
+		}
+		if words := utils.CountWords(sanitizedTitle); checkWords && words < h.titleMinWords {
+			errors.ErrTitleTooShort.WithDetails(gin.H{"min_words": h.titleMinWords, "current_words": words}).Response(c)
+			return nil, false
+		}
+		addEdit("title", "title", bug.Title, sanitizedTitle, sanitizedTitle)
+	}
+
+	if req.Description != nil {
+		sanitizedDescription, descValid := utils.ValidateString(*req.Description, 10, 5000)
+		if !descValid {
+			errors.ErrInvalidDescription.Response(c)
+			return nil, false
+		}
+		if words := utils.CountWords(sanitizedDescription); checkWords && words < h.descriptionMinWords {
+			errors.ErrDescriptionTooShort.WithDetails(gin.H{"min_words": h.descriptionMinWords, "current_words": words}).Response(c)
+			return nil, false
+		}
+		addEdit("description", "description", bug.Description, sanitizedDescription, sanitizedDescription)
+	}
+
+	if req.Priority != nil {
+		if !utils.ValidatePriority(*req.Priority) {
+			errors.ErrInvalidPriority.Response(c)
+			return nil, false
+		}
+		// Priority changes are applied as a bug event rather than written directly
+		addEdit("priority", "", bug.Priority, *req.Priority, nil)
+	}
+
+	if req.Tags != nil {
+		sanitizedTags, err := utils.ValidateTags(req.Tags, h.tagConfig)
+		if err != nil {
+			switch tagErr := err.(type) {
+			case *utils.TooManyTagsError:
+				errors.ErrTooManyTags.WithMessage(fmt.Sprintf("Maximum %d tags allowed", tagErr.Max)).
+					WithDetails(gin.H{"max_tags": tagErr.Max, "current_tags": tagErr.Count}).Response(c)
+			case *utils.InvalidTagsError:
+				errors.ErrInvalidTag.WithMessage("One or more tags are invalid").
+					WithDetails(gin.H{"tags": tagErr.Tags}).Response(c)
+			default:
+				errors.ErrValidationError.WithDetails(err.Error()).Response(c)
+			}
+			return nil, false
+		}
+		addEdit("tags", "tags", strings.Join(bug.Tags, ","), strings.Join(sanitizedTags, ","), pq.StringArray(sanitizedTags))
+	}
+
+	technicalFields := []struct {
+		field     string
+		requested *string
+		current   *string
+		maxLength int
+	}{
+		{"operating_system", req.OperatingSystem, bug.OperatingSystem, 100},
+		{"device_type", req.DeviceType, bug.DeviceType, 100},
+		{"app_version", req.AppVersion, bug.AppVersion, 50},
+		{"browser_version", req.BrowserVersion, bug.BrowserVersion, 100},
+	}
+	for _, tf := range technicalFields {
+		if tf.requested == nil {
+			continue
+		}
+
+		var value *string
+		if *tf.requested != "" {
+			sanitized, valid := utils.ValidateString(*tf.requested, 1, tf.maxLength)
+			if !valid {
+				errors.ErrValidationError.WithMessage(fmt.Sprintf("%s must be at most %d characters and contain no malicious content", tf.field, tf.maxLength)).
+					WithDetails(gin.H{"field": tf.field}).Response(c)
+				return nil, false
+			}
+			value = &sanitized
+		}
+		addEdit(tf.field, tf.field, stringValue(tf.current), stringValue(value), value)
+	}
+
+	if req.ApplicationURL != nil {
+		application, ok := h.findBugApplicationByURL(c, *req.ApplicationURL)
+		if !ok {
+			return nil, false
+		}
+		if application.ID != bug.ApplicationID {
+			assignment, err := h.bugReassignment(bug, application, edits)
+			if err != nil {
+				errors.ErrAssignmentRulesFailed.Response(c)
+				return nil, false
+			}
+			edits = append(edits, bugFieldEdit{
+				field:    "application_url",
+				column:   "application_id",
+				oldValue: stringValue(bug.Application.URL),
+				newValue: stringValue(application.URL),
+				value:    application.ID,
+				alsoSet:  assignment,
+			})
+		}
+	}
+
+	return edits, true
+}
+
+// bugReassignment returns the assignment of a bug moved to application, as
+// createBug would assign it: to the application's company, and to the member picked
+// by the company's assignment rules for the bug as edited. A bug moved to an
+// application without a company is unassigned.
+func (h *BugHandler) bugReassignment(bug *models.BugReport, application *models.Application, edits []bugFieldEdit) (map[string]interface{}, error) {
+	assignment := map[string]interface{}{
+		"assigned_company_id": nil,
+		"assigned_member_id":  nil,
+	}
+	if application.CompanyID == nil {
+		return assignment, nil
+	}
+	assignment["assigned_company_id"] = *application.CompanyID
+
+	moved := *bug
+	moved.ApplicationID = application.ID
+	for _, edit := range edits {
+		switch edit.field {
+		case "priority":
+			moved.Priority = edit.newValue
+		case "tags":
+			moved.Tags = edit.value.(pq.StringArray)
+		}
+	}
+
+	assigneeID, err := h.matchAssignmentRule(h.db, *application.CompanyID, &moved)
+	if err != nil {
+		return nil, err
+	}
+	if assigneeID != nil {
+		assignment["assigned_member_id"] = *assigneeID
+	}
+	return assignment, nil
+}
+
+// findBugApplicationByURL returns the application registered at rawURL, writing
+// the error response when there is none or it no longer accepts bug reports
+func (h *BugHandler) findBugApplicationByURL(c *gin.Context, rawURL string) (*models.Application, bool) {
+	if !utils.ValidateURL(rawURL) {
+		errors.ErrInvalidApplicationURL.Response(c)
+		return nil, false
+	}
+	canonicalURL, err := utils.NormalizeURL(rawURL)
+	if err != nil {
+		errors.ErrInvalidApplicationURL.Response(c)
+		return nil, false
+	}
+
+	var application models.Application
+	if err := h.db.Where("canonical_url = ?", canonicalURL).First(&application).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrApplicationNotFound.WithMessage("No application is registered at this URL").Response(c)
+			return nil, false
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch application").Response(c)
+		return nil, false
+	}
+
+	if application.IsArchived {
+		errors.ErrApplicationArchived.WithMessage(fmt.Sprintf("Application '%s' has been archived and no longer accepts bug reports", application.Name)).Response(c)
+		return nil, false
+	}
+	return &application, true
+}
+
+// applyBugEdits writes the edits and their history in one transaction. A priority
+// edit is appended to the bug's events; the caller projects it.
+func (h *BugHandler) applyBugEdits(bugID, userID uuid.UUID, edits []bugFieldEdit, now time.Time) ([]models.BugEditHistory, error) {
+	changes := make([]models.BugEditHistory, 0, len(edits))
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{}
+		for _, edit := range edits {
+			if edit.field == "priority" {
+				if _, err := models.AppendBugEvent(tx, bugID, models.BugEventPriorityChanged, &userID, models.BugFieldChange{
+					From: edit.oldValue,
+					To:   edit.newValue,
+				}); err != nil {
+					return err
+				}
+			} else {
+				updates[edit.column] = edit.value
+			}
+			for column, value := range edit.alsoSet {
+				updates[column] = value
+			}
+
+			change := models.BugEditHistory{
+				BugID:     bugID,
+				FieldName: edit.field,
+				OldValue:  edit.oldValue,
+				NewValue:  edit.newValue,
+				ChangedBy: userID,
+				ChangedAt: now,
+			}
+			if err := tx.Create(&change).Error; err != nil {
+				return err
+			}
+			changes = append(changes, change)
+		}
+
+		if len(updates) == 0 {
+			return nil
+		}
+		return tx.Model(&models.BugReport{}).Where("id = ?", bugID).Updates(updates).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bugEditRequest sends body to UpdateBug for the bug as the user auth sets
+func bugEditRequest(t *testing.T, handler *BugHandler, bug *models.BugReport, auth gin.HandlerFunc, body gin.H) (*httptest.ResponseRecorder, map[string]interface{}) {
+	encoded, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("PATCH", fmt.Sprintf("/bugs/%s", bug.ID), bytes.NewBuffer(encoded))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: bug.ID.String()}}
+	auth(c)
+
+	handler.UpdateBug(c)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return w, response
+}
+
+func TestBugHandler_UpdateBug_Permissions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	reporter := createTestUser(t, db)
+	company := createTestCompany(t, db, true)
+	app := createTestCompanyApplication(t, db, company)
+	bug := createTestBugReport(t, db, app, reporter)
+	require.NoError(t, db.Model(bug).Update("assigned_company_id", company.ID).Error)
+
+	member := &models.User{ID: uuid.New(), Email: "member@testcompany.com", DisplayName: "Company Member"}
+	require.NoError(t, db.Create(member).Error)
+	createTestCompanyMember(t, db, company.ID, member.ID, "member")
+
+	stranger := &models.User{ID: uuid.New(), Email: "stranger@example.com", DisplayName: "Stranger"}
+	require.NoError(t, db.Create(stranger).Error)
+
+	t.Run("reporter can edit any field", func(t *testing.T) {
+		w, response := bugEditRequest(t, handler, bug, mockAuthMiddleware(reporter.ID), gin.H{
+			"title":    "Checkout button does nothing",
+			"priority": models.BugPriorityHigh,
+		})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		updated := response["bug"].(map[string]interface{})
+		assert.Equal(t, "Checkout button does nothing", updated["title"])
+		assert.Equal(t, models.BugPriorityHigh, updated["priority"])
+	})
+
+	t.Run("company member can change the priority", func(t *testing.T) {
+		w, response := bugEditRequest(t, handler, bug, mockAuthMiddleware(member.ID), gin.H{"priority": models.BugPriorityCritical})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, models.BugPriorityCritical, response["bug"].(map[string]interface{})["priority"])
+	})
+
+	t.Run("company member cannot change other fields", func(t *testing.T) {
+		w, response := bugEditRequest(t, handler, bug, mockAuthMiddleware(member.ID), gin.H{
+			"priority": models.BugPriorityLow,
+			"title":    "A title from the company",
+		})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "INSUFFICIENT_PERMISSIONS", response["error"].(map[string]interface{})["code"])
+	})
+
+	t.Run("other users cannot edit", func(t *testing.T) {
+		w, response := bugEditRequest(t, handler, bug, mockAuthMiddleware(stranger.ID), gin.H{"priority": models.BugPriorityLow})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "INSUFFICIENT_PERMISSIONS", response["error"].(map[string]interface{})["code"])
+	})
+
+	t.Run("admin can edit any field", func(t *testing.T) {
+		w, response := bugEditRequest(t, handler, bug, mockAdminAuthMiddleware(stranger.ID), gin.H{
+			"description": "Clicking the checkout button on the cart page does nothing at all",
+		})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "Clicking the checkout button on the cart page does nothing at all",
+			response["bug"].(map[string]interface{})["description"])
+	})
+
+	var stored models.BugReport
+	require.NoError(t, db.First(&stored, "id = ?", bug.ID).Error)
+	assert.Equal(t, "Checkout button does nothing", stored.Title)
+	assert.Equal(t, models.BugPriorityCritical, stored.Priority)
+}
+
+func TestBugHandler_UpdateBug_Validation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	reporter := createTestUser(t, db)
+	app := createTestApplication(t, db)
+	bug := createTestBugReport(t, db, app, reporter)
+
+	tests := []struct {
+		name         string
+		body         gin.H
+		expectedCode int
+		errorCode    string
+	}{
+		{"title too short", gin.H{"title": "Bad"}, http.StatusBadRequest, "INVALID_TITLE"},
+		{"title with one word", gin.H{"title": "Broken!!!"}, http.StatusUnprocessableEntity, "TITLE_TOO_SHORT"},
+		{"description too short", gin.H{"description": "Broken"}, http.StatusBadRequest, "INVALID_DESCRIPTION"},
+		{"invalid priority", gin.H{"priority": "urgent"}, http.StatusBadRequest, "INVALID_PRIORITY"},
+		{"invalid tag", gin.H{"tags": []string{"<script>"}}, http.StatusBadRequest, "INVALID_TAG"},
+		{"invalid application URL", gin.H{"application_url": "not a url"}, http.StatusBadRequest, "INVALID_APPLICATION_URL"},
+		{"unknown application URL", gin.H{"application_url": "https://unknown.example.com"}, http.StatusNotFound, "APPLICATION_NOT_FOUND"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, response := bugEditRequest(t, handler, bug, mockAuthMiddleware(reporter.ID), tt.body)
+			assert.Equal(t, tt.expectedCode, w.Code, w.Body.String())
+			assert.Equal(t, tt.errorCode, response["error"].(map[string]interface{})["code"])
+		})
+	}
+
+	t.Run("fields are sanitized", func(t *testing.T) {
+		w, response := bugEditRequest(t, handler, bug, mockAuthMiddleware(reporter.ID), gin.H{
+			"title": "Search <b>results</b> are empty",
+		})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.NotContains(t, response["bug"].(map[string]interface{})["title"], "<b>")
+	})
+
+	// Nothing is recorded for rejected edits
+	var count int64
+	require.NoError(t, db.Model(&models.BugEditHistory{}).Where("field_name <> ?", "title").Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestBugHandler_UpdateBug_History(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	reporter := createTestUser(t, db)
+	app := createTestApplication(t, db)
+	bug := createTestBugReport(t, db, app, reporter)
+
+	otherURL := "https://other.example.com"
+	canonicalURL, err := utils.NormalizeURL(otherURL)
+	require.NoError(t, err)
+	otherApp := &models.Application{ID: uuid.New(), Name: "Other App", URL: &otherURL, CanonicalURL: &canonicalURL}
+	require.NoError(t, db.Create(otherApp).Error)
+
+	w, response := bugEditRequest(t, handler, bug, mockAuthMiddleware(reporter.ID), gin.H{
+		"title":            bug.Title, // unchanged, so not recorded
+		"priority":         models.BugPriorityHigh,
+		"tags":             []string{"checkout", "payments"},
+		"operating_system": "macOS 14",
+		"application_url":  otherURL + "/",
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Len(t, response["changes"], 4)
+
+	var history []models.BugEditHistory
+	require.NoError(t, db.Where("bug_id = ?", bug.ID).Find(&history).Error)
+	changes := make(map[string]models.BugEditHistory)
+	for _, change := range history {
+		assert.Equal(t, reporter.ID, change.ChangedBy)
+		assert.False(t, change.ChangedAt.IsZero())
+		changes[change.FieldName] = change
+	}
+	require.Len(t, changes, 4)
+	assert.Equal(t, models.BugPriorityMedium, changes["priority"].OldValue)
+	assert.Equal(t, models.BugPriorityHigh, changes["priority"].NewValue)
+	assert.Equal(t, "", changes["tags"].OldValue)
+	assert.Equal(t, "checkout,payments", changes["tags"].NewValue)
+	assert.Equal(t, "macOS 14", changes["operating_system"].NewValue)
+	assert.Equal(t, "https://testapp.com", changes["application_url"].OldValue)
+	assert.Equal(t, otherURL, changes["application_url"].NewValue)
+
+	var stored models.BugReport
+	require.NoError(t, db.First(&stored, "id = ?", bug.ID).Error)
+	assert.Equal(t, otherApp.ID, stored.ApplicationID)
+	assert.Equal(t, []string{"checkout", "payments"}, []string(stored.Tags))
+	require.NotNil(t, stored.OperatingSystem)
+	assert.Equal(t, "macOS 14", *stored.OperatingSystem)
+
+	// The priority change is a bug event like those of UpdateBugPriority
+	var events []models.BugEvent
+	require.NoError(t, db.Where("bug_id = ?", bug.ID).Find(&events).Error)
+	require.Len(t, events, 1)
+	assert.Equal(t, models.BugEventPriorityChanged, events[0].EventType)
+
+	var audit models.AuditLog
+	require.NoError(t, db.Where("action = ?", models.AuditActionBugEdit).First(&audit).Error)
+	assert.Equal(t, bug.ID, *audit.ResourceID)
+
+	// Clearing a technical field records it too
+	w, _ = bugEditRequest(t, handler, bug, mockAuthMiddleware(reporter.ID), gin.H{"operating_system": ""})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, db.First(&stored, "id = ?", bug.ID).Error)
+	assert.Nil(t, stored.OperatingSystem)
+
+	var cleared models.BugEditHistory
+	require.NoError(t, db.Where("bug_id = ? AND field_name = ? AND new_value = ?", bug.ID, "operating_system", "").First(&cleared).Error)
+	assert.Equal(t, "macOS 14", cleared.OldValue)
+}
+
+func TestBugHandler_UpdateBug_MovesAssignment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	reporter := createTestUser(t, db)
+	oldCompany := createTestCompany(t, db, true)
+	app := createTestCompanyApplication(t, db, oldCompany)
+
+	oldMember := &models.User{ID: uuid.New(), Email: "old@testcompany.com", DisplayName: "Old Member"}
+	require.NoError(t, db.Create(oldMember).Error)
+
+	newCompany := &models.Company{ID: uuid.New(), Name: "New Company", Domain: "newcompany.com", IsVerified: true}
+	require.NoError(t, db.Create(newCompany).Error)
+	newMember := &models.User{ID: uuid.New(), Email: "ui@newcompany.com", DisplayName: "UI Owner"}
+	require.NoError(t, db.Create(newMember).Error)
+
+	newURL := "https://new.example.com"
+	newCanonicalURL, err := utils.NormalizeURL(newURL)
+	require.NoError(t, err)
+	newApp := &models.Application{ID: uuid.New(), Name: "New App", URL: &newURL, CanonicalURL: &newCanonicalURL, CompanyID: &newCompany.ID}
+	require.NoError(t, db.Create(newApp).Error)
+	require.NoError(t, db.Create(&models.BugAssignmentRule{
+		CompanyID:      newCompany.ID,
+		ApplicationID:  newApp.ID,
+		Tags:           []string{"ui"},
+		AssigneeUserID: &newMember.ID,
+	}).Error)
+
+	unownedURL := "https://unowned.example.com"
+	unownedCanonicalURL, err := utils.NormalizeURL(unownedURL)
+	require.NoError(t, err)
+	unownedApp := &models.Application{ID: uuid.New(), Name: "Unowned App", URL: &unownedURL, CanonicalURL: &unownedCanonicalURL}
+	require.NoError(t, db.Create(unownedApp).Error)
+
+	newAssignedBug := func(t *testing.T) *models.BugReport {
+		bug := createTestBugReport(t, db, app, reporter)
+		require.NoError(t, db.Model(bug).Updates(map[string]interface{}{
+			"assigned_company_id": oldCompany.ID,
+			"assigned_member_id":  oldMember.ID,
+		}).Error)
+		return bug
+	}
+
+	t.Run("moved bug is assigned by the new company's rules", func(t *testing.T) {
+		bug := newAssignedBug(t)
+		w, _ := bugEditRequest(t, handler, bug, mockAuthMiddleware(reporter.ID), gin.H{
+			"application_url": newURL,
+			"tags":            []string{"ui"},
+		})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var stored models.BugReport
+		require.NoError(t, db.First(&stored, "id = ?", bug.ID).Error)
+		assert.Equal(t, newApp.ID, stored.ApplicationID)
+		require.NotNil(t, stored.AssignedCompanyID)
+		assert.Equal(t, newCompany.ID, *stored.AssignedCompanyID)
+		require.NotNil(t, stored.AssignedMemberID)
+		assert.Equal(t, newMember.ID, *stored.AssignedMemberID)
+	})
+
+	t.Run("old member is cleared when no rule matches", func(t *testing.T) {
+		bug := newAssignedBug(t)
+		w, _ := bugEditRequest(t, handler, bug, mockAuthMiddleware(reporter.ID), gin.H{"application_url": newURL})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var stored models.BugReport
+		require.NoError(t, db.First(&stored, "id = ?", bug.ID).Error)
+		require.NotNil(t, stored.AssignedCompanyID)
+		assert.Equal(t, newCompany.ID, *stored.AssignedCompanyID)
+		assert.Nil(t, stored.AssignedMemberID)
+	})
+
+	t.Run("bug moved to an application without a company is unassigned", func(t *testing.T) {
+		bug := newAssignedBug(t)
+		w, response := bugEditRequest(t, handler, bug, mockAuthMiddleware(reporter.ID), gin.H{"application_url": unownedURL})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		// Only the application is recorded in the history
+		assert.Len(t, response["changes"], 1)
+
+		var stored models.BugReport
+		require.NoError(t, db.First(&stored, "id = ?", bug.ID).Error)
+		assert.Equal(t, unownedApp.ID, stored.ApplicationID)
+		assert.Nil(t, stored.AssignedCompanyID)
+		assert.Nil(t, stored.AssignedMemberID)
+	})
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// loadBugEvents returns a bug's events in sequence order
+func loadBugEvents(t *testing.T, db *gorm.DB, bugID uuid.UUID) []models.BugEvent {
+	var events []models.BugEvent
+	require.NoError(t, db.Where("bug_id = ?", bugID).Order("sequence ASC").Find(&events).Error)
+	return events
+}
+
+func TestBugHandler_BugEvents(t *testing.T) {
+	handler, db := setupBugTestHandler(t)
+	reporter := createTestUser(t, db)
+	app := createTestApplication(t, db)
+	bug := createTestBugReport(t, db, app, reporter)
+
+	company := createTestCompany(t, db, true)
+	member := &models.User{ID: uuid.New(), Email: "dev@testcompany.com", DisplayName: "Developer"}
+	require.NoError(t, db.Create(member).Error)
+	createTestCompanyMember(t, db, company.ID, member.ID, "member")
+	require.NoError(t, db.Model(bug).Update("assigned_company_id", company.ID).Error)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PATCH("/bugs/:id/status", mockAuthMiddleware(member.ID), handler.UpdateBugStatus)
+	router.PATCH("/bugs/:id/priority", mockAuthMiddleware(member.ID), handler.UpdateBugPriority)
+	router.PATCH("/reporter/bugs/:id/priority", mockAuthMiddleware(reporter.ID), handler.UpdateBugPriority)
+
+	patch := func(path string, body map[string]string) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(body)
+		req, _ := http.NewRequest("PATCH", path, bytes.NewBuffer(payload))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("status changes are recorded as events", func(t *testing.T) {
+		w := patch("/bugs/"+bug.ID.String()+"/status", map[string]string{"status": models.BugStatusReviewing})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		events := loadBugEvents(t, db, bug.ID)
+		require.Len(t, events, 1)
+		assert.Equal(t, models.BugEventStatusChanged, events[0].EventType)
+		assert.Equal(t, int64(1), events[0].Sequence)
+		assert.JSONEq(t, `{"from":"open","to":"reviewing"}`, string(events[0].Payload))
+		require.NotNil(t, events[0].ActorID)
+		assert.Equal(t, member.ID, *events[0].ActorID)
+	})
+
+	t.Run("priority changes are recorded and projected", func(t *testing.T) {
+		w := patch("/bugs/"+bug.ID.String()+"/priority", map[string]string{"priority": models.BugPriorityCritical})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Bug models.BugReport `json:"bug"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, models.BugPriorityCritical, response.Bug.Priority)
+		assert.Equal(t, models.BugStatusReviewing, response.Bug.Status)
+
+		events := loadBugEvents(t, db, bug.ID)
+		require.Len(t, events, 2)
+		assert.Equal(t, models.BugEventPriorityChanged, events[1].EventType)
+		assert.JSONEq(t, `{"from":"medium","to":"critical"}`, string(events[1].Payload))
+
+		var stored models.BugReport
+		require.NoError(t, db.First(&stored, bug.ID).Error)
+		assert.Equal(t, models.BugPriorityCritical, stored.Priority)
+		assert.Equal(t, int64(2), stored.EventSequence)
+
+		var auditLog models.AuditLog
+		require.NoError(t, db.Where("action = ? AND resource_id = ?", models.AuditActionBugPriorityChange, bug.ID).First(&auditLog).Error)
+	})
+
+	t.Run("rejects an invalid priority", func(t *testing.T) {
+		w := patch("/bugs/"+bug.ID.String()+"/priority", map[string]string{"priority": "urgent"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_PRIORITY")
+	})
+
+	t.Run("only company members can change priority", func(t *testing.T) {
+		w := patch("/reporter/bugs/"+bug.ID.String()+"/priority", map[string]string{"priority": models.BugPriorityLow})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Len(t, loadBugEvents(t, db, bug.ID), 2)
+	})
+}
+
+func TestAdminHandler_RebuildBugProjection(t *testing.T) {
+	handler, db := setupAdminTestHandler(t)
+	admin := createTestAdmin(t, db)
+	user := createTestUser(t, db)
+	app := createTestApplication(t, db)
+	bug := createTestBugReport(t, db, app, user)
+
+	for _, change := range []struct {
+		eventType string
+		from, to  string
+	}{
+		{models.BugEventStatusChanged, models.BugStatusOpen, models.BugStatusFixed},
+		{models.BugEventPriorityChanged, models.BugPriorityMedium, models.BugPriorityHigh},
+		{models.BugEventStatusChanged, models.BugStatusFixed, models.BugStatusReviewing},
+	} {
+		_, err := models.AppendBugEvent(db, bug.ID, change.eventType, &admin.ID, models.BugFieldChange{From: change.from, To: change.to})
+		require.NoError(t, err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(mockAdminAuthMiddleware(admin.ID))
+	router.POST("/admin/bugs/:id/rebuild-projection", handler.RebuildBugProjection)
+
+	rebuild := func(bugID string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/admin/bugs/"+bugID+"/rebuild-projection", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Replaying twice gives the same bug
+	for i := 0; i < 2; i++ {
+		w := rebuild(bug.ID.String())
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var stored models.BugReport
+		require.NoError(t, db.First(&stored, bug.ID).Error)
+		assert.Equal(t, models.BugStatusReviewing, stored.Status)
+		assert.Equal(t, models.BugPriorityHigh, stored.Priority)
+		assert.Nil(t, stored.ResolvedAt)
+		assert.Equal(t, int64(3), stored.EventSequence)
+	}
+
+	var rebuilds int64
+	db.Model(&models.AuditLog{}).Where("action = ? AND resource_id = ?", models.AuditActionBugProjectionRebuild, bug.ID).Count(&rebuilds)
+	assert.Equal(t, int64(2), rebuilds)
+
+	t.Run("unknown bug", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, rebuild(uuid.New().String()).Code)
+	})
+}
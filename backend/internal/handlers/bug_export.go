@@ -0,0 +1,339 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Bug export formats
+const (
+	BugExportFormatCSV  = "csv"
+	BugExportFormatJSON = "json"
+)
+
+// bugExportBatchSize is the number of rows written between flushes of an export
+const bugExportBatchSize = 100
+
+// bugExportColumns are the columns of a CSV bug export, in order
+var bugExportColumns = []string{
+	"id", "title", "description", "status", "priority", "tags",
+	"application_id", "application_name", "assigned_company_id", "company_name", "reporter_email",
+	"os_name", "custom_fields", "vote_count", "comment_count", "created_at", "updated_at", "resolved_at",
+}
+
+// bugExportSelect selects the bugExportRow columns from a buildBugQuery query
+const bugExportSelect = "bug_reports.id, bug_reports.title, bug_reports.description, bug_reports.status, " +
+	"bug_reports.priority, bug_reports.tags, bug_reports.application_id, applications.name AS application_name, " +
+	"bug_reports.assigned_company_id, companies.name AS company_name, reporters.email AS reporter_email, " +
+	"bug_reports.environment_info->>'os_name' AS os_name, bug_reports.custom_fields, bug_reports.vote_count, " +
+	"bug_reports.comment_count, bug_reports.created_at, bug_reports.updated_at, bug_reports.resolved_at"
+
+// ExportBugsRequest represents the query parameters of a bug export: the bug list
+// filters, without pagination, and the file format
+type ExportBugsRequest struct {
+	ListBugsRequest
+	Format string `form:"format,default=csv"`
+}
+
+// bugExportRow is a bug as exported
+type bugExportRow struct {
+	ID                uuid.UUID      `json:"id"`
+	Title             string         `json:"title"`
+	Description       string         `json:"description"`
+	Status            string         `json:"status"`
+	Priority          string         `json:"priority"`
+	Tags              pq.StringArray `json:"tags" gorm:"type:text[]"`
+	ApplicationID     uuid.UUID      `json:"application_id"`
+	ApplicationName   *string        `json:"application_name"`
+	AssignedCompanyID *uuid.UUID     `json:"assigned_company_id"`
+	CompanyName       *string        `json:"company_name"`
+	ReporterEmail     *string        `json:"reporter_email"`
+	OSName            *string        `json:"os_name"`
+	CustomFields      datatypes.JSON `json:"custom_fields"`
+	VoteCount         int            `json:"vote_count"`
+	CommentCount      int            `json:"comment_count"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	ResolvedAt        *time.Time     `json:"resolved_at"`
+}
+
+// values returns the row's cells in bugExportColumns order, escaped with
+// escapeCSVFormula
+func (r bugExportRow) values() []string {
+	companyID := ""
+	if r.AssignedCompanyID != nil {
+		companyID = r.AssignedCompanyID.String()
+	}
+	resolvedAt := ""
+	if r.ResolvedAt != nil {
+		resolvedAt = r.ResolvedAt.UTC().Format(time.RFC3339)
+	}
+
+	values := []string{
+		r.ID.String(),
+		r.Title,
+		r.Description,
+		r.Status,
+		r.Priority,
+		strings.Join(r.Tags, ","),
+		r.ApplicationID.String(),
+		stringValue(r.ApplicationName),
+		companyID,
+		stringValue(r.CompanyName),
+		stringValue(r.ReporterEmail),
+		stringValue(r.OSName),
+		string(r.CustomFields),
+		fmt.Sprint(r.VoteCount),
+		fmt.Sprint(r.CommentCount),
+		r.CreatedAt.UTC().Format(time.RFC3339),
+		r.UpdatedAt.UTC().Format(time.RFC3339),
+		resolvedAt,
+	}
+	for i, value := range values {
+		values[i] = escapeCSVFormula(value)
+	}
+	return values
+}
+
+// escapeCSVFormula prefixes value with a quote if it starts with a character that
+// makes spreadsheet applications evaluate it as a formula, so a bug title like
+// =HYPERLINK(...) is shown as text when the export is opened
+func escapeCSVFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// ExportBugs handles exporting every bug matching the bug list filters as CSV or
+// JSON (admin only)
+func (h *AdminHandler) ExportBugs(c *gin.Context) {
+	opts, format, ok := bugExportFilters(c, h.db, h.spamScoreThreshold)
+	if !ok {
+		return
+	}
+
+	exported, ok := streamBugExport(c, h.db, opts, format, "bugs")
+	if !ok {
+		return
+	}
+
+	details := fmt.Sprintf("Exported %d bugs as %s", exported, format)
+	if err := createAuditLog(h.db, c, models.AuditActionBugExport, models.AuditResourceBug, nil, details, nil, nil); err != nil {
+		// Log error but don't fail the request since the export was already sent
+		logger.FromContext(c.Request.Context()).Error("Failed to log audit action", err)
+	}
+}
+
+// ExportCompanyBugs handles exporting every bug assigned to the company that matches
+// the bug list filters as CSV or JSON (company members only)
+func (h *CompanyHandler) ExportCompanyBugs(c *gin.Context) {
+	companyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid company ID format").Response(c)
+		return
+	}
+
+	opts, format, ok := bugExportFilters(c, h.db, h.spamScoreThreshold)
+	if !ok {
+		return
+	}
+	opts.CompanyID = &companyID
+
+	var company models.Company
+	if err := h.db.First(&company, "id = ?", companyID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrCompanyNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch company").Response(c)
+		return
+	}
+
+	exported, ok := streamBugExport(c, h.db, opts, format, "bugs-"+exportFilenamePart(company.Name))
+	if !ok {
+		return
+	}
+
+	details := fmt.Sprintf("Exported %d bugs of %s as %s", exported, company.Name, format)
+	if err := createAuditLog(h.db, c, models.AuditActionBugExport, models.AuditResourceCompany, &companyID, details, nil, nil); err != nil {
+		// Log error but don't fail the request since the export was already sent
+		logger.FromContext(c.Request.Context()).Error("Failed to log audit action", err)
+	}
+}
+
+// bugExportFilters returns the filters and format of an export request. It writes
+// the error response and returns false when they are invalid.
+func bugExportFilters(c *gin.Context, db *gorm.DB, spamScoreThreshold float64) (BugQueryOptions, string, bool) {
+	var req ExportBugsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		errors.ErrValidationError.WithMessage("Invalid query parameters").WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return BugQueryOptions{}, "", false
+	}
+
+	if req.Format != BugExportFormatCSV && req.Format != BugExportFormatJSON {
+		errors.ErrInvalidFormat.Response(c)
+		return BugQueryOptions{}, "", false
+	}
+
+	opts, ok := bugListFilters(c, db, req.ListBugsRequest, spamScoreThreshold)
+	return opts, req.Format, ok
+}
+
+// streamBugExport writes the bugs matching opts, newest first, as an attachment named
+// after filenamePrefix and the date. Rows are written as they are read, so the export
+// is never held in memory. It returns how many bugs were exported, or false when the
+// export failed.
+func streamBugExport(c *gin.Context, db *gorm.DB, opts BugQueryOptions, format, filenamePrefix string) (int, bool) {
+	rows, err := buildBugQuery(db, opts).
+		Joins("LEFT JOIN users reporters ON reporters.id = bug_reports.reporter_id").
+		Select(bugExportSelect).
+		Order("bug_reports.created_at DESC").
+		Order("bug_reports.id DESC").
+		Rows()
+	if err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to fetch bug reports").Response(c)
+		return 0, false
+	}
+	defer rows.Close()
+
+	filename := fmt.Sprintf("%s-%s.%s", filenamePrefix, time.Now().UTC().Format("2006-01-02"), format)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	if format == BugExportFormatJSON {
+		c.Header("Content-Type", "application/json")
+	} else {
+		c.Header("Content-Type", "text/csv")
+	}
+	c.Status(http.StatusOK)
+
+	var writer bugExportWriter
+	if format == BugExportFormatJSON {
+		writer = &jsonBugExportWriter{}
+	} else {
+		writer = &csvBugExportWriter{}
+	}
+
+	exported, exportErr := writeBugExport(c, db, rows, writer)
+	if exportErr != nil {
+		// The headers are already sent, so the export is cut short rather than
+		// replaced with an error response
+		logger.FromContext(c.Request.Context()).Error("Failed to export bugs", exportErr, logger.Fields{
+			"format":   format,
+			"exported": exported,
+		})
+		return exported, false
+	}
+	return exported, true
+}
+
+// writeBugExport writes the rows to the response, flushing after each batch. It
+// stops early when the client disconnects.
+func writeBugExport(c *gin.Context, db *gorm.DB, rows *sql.Rows, writer bugExportWriter) (int, error) {
+	if err := writer.begin(c.Writer); err != nil {
+		return 0, err
+	}
+
+	ctx := c.Request.Context()
+	exported := 0
+	for rows.Next() {
+		var row bugExportRow
+		if err := db.ScanRows(rows, &row); err != nil {
+			return exported, err
+		}
+		if err := writer.write(c.Writer, row); err != nil {
+			return exported, err
+		}
+		exported++
+
+		if exported%bugExportBatchSize == 0 {
+			c.Writer.Flush()
+			if err := ctx.Err(); err != nil {
+				return exported, err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return exported, err
+	}
+
+	if err := writer.end(c.Writer); err != nil {
+		return exported, err
+	}
+	c.Writer.Flush()
+	return exported, nil
+}
+
+// bugExportWriter writes the rows of a bug export in a file format
+type bugExportWriter interface {
+	begin(w io.Writer) error
+	write(w io.Writer, row bugExportRow) error
+	end(w io.Writer) error
+}
+
+// csvBugExportWriter writes a header line and then a line per bug
+type csvBugExportWriter struct{}
+
+func (csvBugExportWriter) begin(w io.Writer) error {
+	return writeCSVRecord(w, bugExportColumns)
+}
+
+func (csvBugExportWriter) write(w io.Writer, row bugExportRow) error {
+	return writeCSVRecord(w, row.values())
+}
+
+func (csvBugExportWriter) end(w io.Writer) error {
+	return nil
+}
+
+// writeCSVRecord writes a CSV line to w
+func writeCSVRecord(w io.Writer, record []string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(record); err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// jsonBugExportWriter writes a JSON array of bugs, one element at a time
+type jsonBugExportWriter struct {
+	written bool
+}
+
+func (*jsonBugExportWriter) begin(w io.Writer) error {
+	_, err := io.WriteString(w, "[")
+	return err
+}
+
+func (j *jsonBugExportWriter) write(w io.Writer, row bugExportRow) error {
+	if j.written {
+		if _, err := io.WriteString(w, ","); err != nil {
+			return err
+		}
+	}
+	j.written = true
+	return json.NewEncoder(w).Encode(row)
+}
+
+func (*jsonBugExportWriter) end(w io.Writer) error {
+	_, err := io.WriteString(w, "]\n")
+	return err
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/middleware"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// createExportBug creates a bug with the export's joined and JSON columns filled in
+func createExportBug(t *testing.T, db *gorm.DB, app *models.Application, reporter *models.User, title, status string, companyID *uuid.UUID, createdAt time.Time) *models.BugReport {
+	bug := &models.BugReport{
+		ID:                uuid.New(),
+		Title:             title,
+		Description:       "Export test bug, with a comma",
+		Status:            status,
+		Priority:          models.BugPriorityHigh,
+		Tags:              pq.StringArray{"ui", "crash"},
+		EnvironmentInfo:   datatypes.JSON(`{"os_name":"macOS"}`),
+		ApplicationID:     app.ID,
+		ReporterID:        &reporter.ID,
+		AssignedCompanyID: companyID,
+		VoteCount:         3,
+		CreatedAt:         createdAt,
+	}
+	require.NoError(t, db.Create(bug).Error)
+	return bug
+}
+
+func TestAdminHandler_ExportBugs(t *testing.T) {
+	handler, db := setupAdminTestHandler(t)
+	admin := createTestAdmin(t, db)
+	reporter := createTestUser(t, db)
+	app := createTestApplication(t, db)
+	company := createTestVerifiedCompany(t, db)
+
+	now := time.Now()
+	older := createExportBug(t, db, app, reporter, "Crash on save", models.BugStatusOpen, &company.ID, now.Add(-2*time.Hour))
+	newer := createExportBug(t, db, app, reporter, "Button misaligned", models.BugStatusOpen, nil, now.Add(-time.Hour))
+	createExportBug(t, db, app, reporter, "Fixed crash", models.BugStatusFixed, nil, now)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(mockAuthMiddleware(admin.ID))
+	router.GET("/admin/bugs/export", handler.ExportBugs)
+
+	export := func(query string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/admin/bugs/export"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		w := export("?status=open")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Content-Disposition"), `attachment; filename="bugs-`)
+		assert.Contains(t, w.Header().Get("Content-Disposition"), `.csv"`)
+
+		records, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+		assert.Equal(t, bugExportColumns, records[0])
+
+		// Newest first, with the reporter, application and company joined in
+		row := map[string]string{}
+		for i, column := range records[0] {
+			row[column] = records[2][i]
+		}
+		assert.Equal(t, newer.ID.String(), records[1][0])
+		assert.Equal(t, older.ID.String(), row["id"])
+		assert.Equal(t, "Export test bug, with a comma", row["description"])
+		assert.Equal(t, app.Name, row["application_name"])
+		assert.Equal(t, company.ID.String(), row["assigned_company_id"])
+		assert.Equal(t, company.Name, row["company_name"])
+		assert.Equal(t, reporter.Email, row["reporter_email"])
+		assert.Equal(t, "macOS", row["os_name"])
+		assert.Equal(t, "3", row["vote_count"])
+		assert.Empty(t, row["resolved_at"])
+	})
+
+	t.Run("json", func(t *testing.T) {
+		w := export("?format=json&company=" + company.Name)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+		var bugs []bugExportRow
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bugs))
+		require.Len(t, bugs, 1)
+		assert.Equal(t, older.ID, bugs[0].ID)
+		require.NotNil(t, bugs[0].ReporterEmail)
+		assert.Equal(t, reporter.Email, *bugs[0].ReporterEmail)
+	})
+
+	t.Run("json with no bugs", func(t *testing.T) {
+		w := export("?format=json&status=fixed&company=" + company.Name)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var bugs []bugExportRow
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bugs))
+		assert.Empty(t, bugs)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		w := export("?format=xml")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_FORMAT")
+	})
+
+	// Every completed export is audited
+	var auditLogs []models.AuditLog
+	require.NoError(t, db.Where("action = ?", models.AuditActionBugExport).Find(&auditLogs).Error)
+	var details []string
+	for _, auditLog := range auditLogs {
+		assert.Equal(t, admin.ID, auditLog.UserID)
+		details = append(details, auditLog.Details)
+	}
+	assert.ElementsMatch(t, []string{"Exported 2 bugs as csv", "Exported 1 bugs as json", "Exported 0 bugs as json"}, details)
+}
+
+func TestCompanyHandler_ExportCompanyBugs(t *testing.T) {
+	handler, db := setupCompanyTestHandler(t)
+	reporter := createTestUser(t, db)
+	company := createTestCompany(t, db, true)
+	app := createTestApplication(t, db)
+
+	member := &models.User{ID: uuid.New(), Email: "member@testcompany.com", DisplayName: "Company Member"}
+	require.NoError(t, db.Create(member).Error)
+	createTestCompanyMember(t, db, company.ID, member.ID, "member")
+
+	outsider := &models.User{ID: uuid.New(), Email: "outsider@example.org", DisplayName: "Outsider"}
+	require.NoError(t, db.Create(outsider).Error)
+
+	otherCompany := &models.Company{ID: uuid.New(), Name: "Other", Domain: "other.com"}
+	require.NoError(t, db.Create(otherCompany).Error)
+
+	now := time.Now()
+	bug := createExportBug(t, db, app, reporter, "Crash on save", models.BugStatusOpen, &company.ID, now)
+	// Bugs assigned to other companies, or to none, are never exported
+	createExportBug(t, db, app, reporter, "Other crash", models.BugStatusOpen, &otherCompany.ID, now)
+	createExportBug(t, db, app, reporter, "Unassigned crash", models.BugStatusOpen, nil, now)
+
+	gin.SetMode(gin.TestMode)
+	exportAs := func(userID uuid.UUID, query string) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(mockAuthMiddleware(userID))
+		router.GET("/companies/:id/bugs/export", middleware.NewCompanyMiddleware(db).RequireCompanyMember(), handler.ExportCompanyBugs)
+
+		req, _ := http.NewRequest("GET", "/companies/"+company.ID.String()+"/bugs/export"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("exports only the company's bugs", func(t *testing.T) {
+		// The company filter cannot widen the export to another company
+		w := exportAs(member.ID, "?format=json&company="+otherCompany.Name)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Contains(t, w.Header().Get("Content-Disposition"), `filename="bugs-`)
+
+		var bugs []bugExportRow
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bugs))
+		assert.Empty(t, bugs)
+
+		w = exportAs(member.ID, "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		records, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, bug.ID.String(), records[1][0])
+	})
+
+	t.Run("rejects non-members", func(t *testing.T) {
+		w := exportAs(outsider.ID, "")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "INSUFFICIENT_PERMISSIONS")
+	})
+
+	var auditLog models.AuditLog
+	require.NoError(t, db.Where("action = ? AND resource_id = ?", models.AuditActionBugExport, company.ID).First(&auditLog).Error)
+	assert.Equal(t, models.AuditResourceCompany, auditLog.Resource)
+}
+
+func TestBugExportRow_ValuesEscapeFormulas(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "=HYPERLINK(\"https://evil.example\")", want: "'=HYPERLINK(\"https://evil.example\")"},
+		{value: "+1 crashes", want: "'+1 crashes"},
+		{value: "-2+3", want: "'-2+3"},
+		{value: "@SUM(A1:A2)", want: "'@SUM(A1:A2)"},
+		{value: "\tindented", want: "'\tindented"},
+		{value: "\rreturn", want: "'\rreturn"},
+		{value: "Login = broken", want: "Login = broken"},
+		{value: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			email := tt.value
+			row := bugExportRow{ID: uuid.New(), Title: tt.value, Description: tt.value, Tags: pq.StringArray{tt.value}, ReporterEmail: &email}
+			values := row.values()
+			assert.Equal(t, tt.want, values[1], "title")
+			assert.Equal(t, tt.want, values[2], "description")
+			assert.Equal(t, tt.want, values[5], "tags")
+			assert.Equal(t, tt.want, values[10], "reporter_email")
+			assert.Equal(t, row.ID.String(), values[0])
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"sync/atomic"
+
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// fetchCounts counts database fetches and how many of them shared another
+// request's query
+type fetchCounts struct {
+	total  atomic.Int64
+	shared atomic.Int64
+}
+
+// record counts a fetch and returns the share of all fetches that were deduplicated
+func (f *fetchCounts) record(shared bool) float64 {
+	total := f.total.Add(1)
+	deduplicated := f.shared.Load()
+	if shared {
+		deduplicated = f.shared.Add(1)
+	}
+	return float64(deduplicated) / float64(total)
+}
+
+// fetchBug loads a bug's scalar fields from the database and caches them. Concurrent
+// fetches of the same bug, such as when a popular bug's cache entry expires, share a
+// single query. A missing bug is cached as not found for 30 seconds.
+func (h *BugHandler) fetchBug(ctx context.Context, bugID uuid.UUID) (models.BugReport, error) {
+	key := bugID.String()
+
+	result, err, shared := h.bugFetches.Do(key, func() (interface{}, error) {
+		var bug models.BugReport
+		if err := h.db.First(&bug, bugID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				if err := h.cache.SetBugNotFound(ctx, key); err != nil {
+					// Log cache error but don't fail the request
+					logger.FromContext(ctx).Error("Failed to cache missing bug", err, logger.Fields{"bug_id": key})
+				}
+			}
+			return nil, err
+		}
+
+		// Cache the result for future requests
+		if err := h.cache.SetBug(ctx, key, bug); err != nil {
+			// Log cache error but don't fail the request
+			logger.FromContext(ctx).Error("Failed to cache bug", err, logger.Fields{"bug_id": key})
+		}
+		return bug, nil
+	})
+
+	ratio := h.bugFetchCounts.record(shared)
+	logger.FromContext(ctx).Debug("Fetched bug from database", logger.Fields{
+		"bug_id":      key,
+		"shared":      shared,
+		"dedup_ratio": ratio,
+	})
+
+	if err != nil {
+		return models.BugReport{}, err
+	}
+	return result.(models.BugReport), nil
+}
+
+// FindBug returns a bug's scalar fields from the cache, fetching them from the
+// database on a miss. A bug that does not exist returns gorm.ErrRecordNotFound.
+func (h *BugHandler) FindBug(ctx context.Context, bugID uuid.UUID) (models.BugReport, error) {
+	key := bugID.String()
+
+	var bug models.BugReport
+	if err := h.cache.GetBug(ctx, key, &bug); err == nil {
+		return bug, nil
+	}
+	if h.cache.IsBugNotFound(ctx, key) {
+		return models.BugReport{}, gorm.ErrRecordNotFound
+	}
+	return h.fetchBug(ctx, bugID)
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// countBugQueries counts queries against bug_reports, delaying each by delay so
+// concurrent requests overlap
+func countBugQueries(t *testing.T, db *gorm.DB, delay time.Duration) *atomic.Int64 {
+	var queries atomic.Int64
+	require.NoError(t, db.Callback().Query().Before("gorm:query").Register("test:count_bug_queries", func(tx *gorm.DB) {
+		if tx.Statement.Table == "bug_reports" {
+			queries.Add(1)
+			time.Sleep(delay)
+		}
+	}))
+	return &queries
+}
+
+func TestBugHandler_GetBug_SharesConcurrentFetches(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	// Every connection to an in-memory SQLite database opens a new, empty database
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	user := createTestUser(t, db)
+	app := createTestApplication(t, db)
+	bug := createTestBugReport(t, db, app, user)
+	queries := countBugQueries(t, db, 100*time.Millisecond)
+
+	router := gin.New()
+	router.GET("/bugs/:id", handler.GetBug)
+
+	const requests = 50
+	var (
+		start sync.WaitGroup
+		done  sync.WaitGroup
+		codes [requests]int
+	)
+	start.Add(1)
+	for i := 0; i < requests; i++ {
+		done.Add(1)
+		go func(i int) {
+			defer done.Done()
+			start.Wait()
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/bugs/"+bug.ID.String(), nil))
+			codes[i] = w.Code
+		}(i)
+	}
+	start.Done()
+	done.Wait()
+
+	for _, code := range codes {
+		assert.Equal(t, http.StatusOK, code)
+	}
+	assert.Equal(t, int64(1), queries.Load())
+	assert.Equal(t, int64(requests), handler.bugFetchCounts.total.Load())
+	assert.Equal(t, int64(requests), handler.bugFetchCounts.shared.Load())
+}
+
+func TestBugHandler_GetBug_CachesMissingBugs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupBugTestDB(t)
+	redisClient, mock := newMockRedisClient()
+	handler := NewBugHandler(db, redisClient)
+	queries := countBugQueries(t, db, 0)
+
+	router := gin.New()
+	router.GET("/bugs/:id", handler.GetBug)
+
+	missingID := uuid.New().String()
+	get := func() int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/bugs/"+missingID, nil))
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusNotFound, get())
+	assert.Equal(t, int64(1), queries.Load())
+	assert.Equal(t, 30*time.Second, mock.ttls["bug:"+missingID+":not_found"])
+
+	// Later lookups are answered from the cache
+	assert.Equal(t, http.StatusNotFound, get())
+	assert.Equal(t, int64(1), queries.Load())
+
+	// Invalidating the bug, as restoring it does, clears the negative entry
+	require.NoError(t, handler.cache.InvalidateBug(context.Background(), missingID))
+	bug := &models.BugReport{
+		ID:            uuid.MustParse(missingID),
+		Title:         "Restored Bug",
+		Description:   "A bug that was missing from the database",
+		Status:        models.BugStatusOpen,
+		Priority:      models.BugPriorityMedium,
+		ApplicationID: createTestApplication(t, db).ID,
+	}
+	require.NoError(t, db.Create(bug).Error)
+	assert.Equal(t, http.StatusOK, get())
+}
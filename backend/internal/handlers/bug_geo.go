@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"net"
+	"net/http"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/geoip"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// geoStatsCacheKey is the statistics cache key of GetGeoStats
+const geoStatsCacheKey = "bugs:geo"
+
+// GeoStatsResponse represents the number of bugs submitted from each country
+type GeoStatsResponse struct {
+	// Countries maps ISO 3166-1 alpha-2 country codes to bug counts
+	Countries map[string]int64 `json:"countries"`
+	// Unknown counts bugs that were not located
+	Unknown int64 `json:"unknown"`
+}
+
+// SetGeoIPLookup sets the lookup that locates bug submitters. Without one, bugs are
+// not located.
+func (h *BugHandler) SetGeoIPLookup(lookup geoip.LookupFunc) {
+	h.geoLookup = lookup
+}
+
+// locateBug sets the country and region the bug was submitted from, looked up from
+// the client's IP. Failed lookups leave the bug unlocated.
+func (h *BugHandler) locateBug(c *gin.Context, bug *models.BugReport) {
+	if h.geoLookup == nil {
+		return
+	}
+
+	location, err := h.geoLookup(net.ParseIP(c.ClientIP()))
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Warn("Failed to locate bug submitter", logger.Fields{"error": err.Error()})
+		return
+	}
+
+	if location.CountryCode != "" {
+		bug.CountryCode = &location.CountryCode
+	}
+	if location.Region != "" {
+		bug.Region = &location.Region
+	}
+}
+
+// GetGeoStats returns how many bugs were submitted from each country. Spam is not
+// counted.
+//
+// @Summary     Bugs by country
+// @Description Returns the number of bugs submitted from each country, located from the submitter's IP when the bug was created. Spam is not counted. Results are cached for 30 minutes.
+// @Tags        admin
+// @Produce     json
+// @Security    BearerAuth
+// @Success     200 {object} GeoStatsResponse
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /bugs/stats/geo [get]
+func (h *BugHandler) GetGeoStats(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var cached GeoStatsResponse
+	if err := h.cache.GetStats(ctx, geoStatsCacheKey, &cached); err == nil {
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+
+	var counts []struct {
+		CountryCode *string
+		Count       int64
+	}
+	if err := h.db.Model(&models.BugReport{}).
+		Select("country_code, COUNT(*) AS count").
+		Where("is_spam = ?", false).
+		Group("country_code").
+		Scan(&counts).Error; err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to fetch bug locations").Response(c)
+		return
+	}
+
+	response := GeoStatsResponse{Countries: make(map[string]int64, len(counts))}
+	for _, count := range counts {
+		if count.CountryCode == nil || *count.CountryCode == "" {
+			response.Unknown += count.Count
+			continue
+		}
+		response.Countries[*count.CountryCode] = count.Count
+	}
+
+	if err := h.cache.SetStats(ctx, geoStatsCacheKey, response); err != nil {
+		// Log cache error but don't fail the request
+		logger.FromContext(ctx).Error("Failed to cache bug locations", err)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bugrelay-backend/internal/geoip"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBugHandler_CreateBug_Location(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	user := createTestUser(t, db)
+
+	var lookedUp []string
+	locations := map[string]geoip.Location{
+		"203.0.113.7": {CountryCode: "DE", Region: "Bavaria"},
+		"203.0.113.8": {CountryCode: "SG"},
+		"203.0.113.9": {},
+	}
+	handler.SetGeoIPLookup(func(ip net.IP) (geoip.Location, error) {
+		lookedUp = append(lookedUp, ip.String())
+		location, ok := locations[ip.String()]
+		if !ok {
+			return geoip.Location{}, fmt.Errorf("lookup failed")
+		}
+		return location, nil
+	})
+
+	submit := func(ip string, userID *uuid.UUID) models.BugReport {
+		title := fmt.Sprintf("Located bug from %s", ip)
+		body, err := json.Marshal(map[string]interface{}{
+			"title":            title,
+			"description":      "This bug report is located from the submitter's address",
+			"application_name": "Location App",
+		})
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/bugs", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request.RemoteAddr = ip + ":4321"
+		if userID != nil {
+			mockAuthMiddleware(*userID)(c)
+		}
+
+		handler.CreateBug(c)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		assert.NotContains(t, w.Body.String(), "country_code")
+
+		var bug models.BugReport
+		require.NoError(t, db.Where("title = ?", title).First(&bug).Error)
+		return bug
+	}
+
+	// Anonymous and signed-in submissions are both located
+	bug := submit("203.0.113.7", nil)
+	require.NotNil(t, bug.CountryCode)
+	assert.Equal(t, "DE", *bug.CountryCode)
+	require.NotNil(t, bug.Region)
+	assert.Equal(t, "Bavaria", *bug.Region)
+
+	bug = submit("203.0.113.8", &user.ID)
+	require.NotNil(t, bug.CountryCode)
+	assert.Equal(t, "SG", *bug.CountryCode)
+	assert.Nil(t, bug.Region)
+
+	// Unknown addresses and failed lookups leave the bug unlocated
+	for _, ip := range []string{"203.0.113.9", "203.0.113.10"} {
+		bug = submit(ip, nil)
+		assert.Nil(t, bug.CountryCode, ip)
+		assert.Nil(t, bug.Region, ip)
+	}
+
+	assert.Equal(t, []string{"203.0.113.7", "203.0.113.8", "203.0.113.9", "203.0.113.10"}, lookedUp)
+}
+
+func TestBugHandler_GetGeoStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	user := createTestUser(t, db)
+	app := createTestApplication(t, db)
+
+	for _, fields := range []map[string]interface{}{
+		{"country_code": "US"},
+		{"country_code": "US"},
+		{"country_code": "FR"},
+		{"country_code": nil},
+		// Spam is not counted
+		{"country_code": "US", "is_spam": true},
+	} {
+		bug := createTestBugReport(t, db, app, user)
+		require.NoError(t, db.Model(bug).Updates(fields).Error)
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/bugs/stats/geo", nil)
+	mockAdminAuthMiddleware(user.ID)(c)
+
+	handler.GetGeoStats(c)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response GeoStatsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, map[string]int64{"US": 2, "FR": 1}, response.Countries)
+	assert.Equal(t, int64(1), response.Unknown)
+}
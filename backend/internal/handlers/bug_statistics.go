@@ -0,0 +1,304 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// bugStatisticsTopReporters is how many reporters are listed in bug statistics
+	bugStatisticsTopReporters = 5
+	// bugStatisticsWeeks is how many weeks, the current one included, bugs over time covers
+	bugStatisticsWeeks = 12
+)
+
+// Vote distribution buckets
+const (
+	VoteBucketNone = "0"
+	VoteBucketFew  = "1-5"
+	VoteBucketSome = "6-20"
+	VoteBucketMany = "21+"
+)
+
+// ReporterSummary is a user and how many bugs they reported
+type ReporterSummary struct {
+	ID          uuid.UUID `json:"id"`
+	DisplayName string    `json:"display_name"`
+	BugCount    int64     `json:"bug_count"`
+}
+
+// WeeklyBugCount is how many bugs were reported in the week starting on WeekStart
+type WeeklyBugCount struct {
+	WeekStart time.Time `json:"week_start"`
+	Count     int64     `json:"count"`
+}
+
+// BugStatisticsResponse represents the bug statistics of an application, or of every
+// application a company owns
+type BugStatisticsResponse struct {
+	TotalBugs          int64             `json:"total_bugs"`
+	ByStatus           map[string]int64  `json:"by_status"`
+	ByPriority         map[string]int64  `json:"by_priority"`
+	AvgResolutionHours *float64          `json:"avg_resolution_hours"`
+	TopReporters       []ReporterSummary `json:"top_reporters"`
+	VoteDistribution   map[string]int64  `json:"vote_distribution"`
+	BugsPerWeek        []WeeklyBugCount  `json:"bugs_per_week"`
+}
+
+// GetApplicationStats returns an application's bug statistics
+//
+// @Summary     Application bug statistics
+// @Description Returns an application's bugs by status and priority, average resolution time, top reporters, vote distribution and bugs reported per week over the last 12 weeks. Spam is not counted. Results are cached for 10 minutes.
+// @Tags        applications
+// @Produce     json
+// @Param       id path string true "Application ID" format(uuid)
+// @Success     200 {object} BugStatisticsResponse
+// @Failure     400 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /applications/{id}/stats [get]
+func (h *ApplicationHandler) GetApplicationStats(c *gin.Context) {
+	applicationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid application ID format").Response(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	var cached BugStatisticsResponse
+	if err := h.cache.GetApplicationStats(ctx, applicationID.String(), &cached); err == nil {
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+
+	var application models.Application
+	if err := h.db.First(&application, "id = ?", applicationID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrApplicationNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch application").Response(c)
+		return
+	}
+
+	stats, err := loadBugStatistics(h.db.WithContext(ctx), func(db *gorm.DB) *gorm.DB {
+		return db.Where("bug_reports.application_id = ?", applicationID)
+	}, time.Now())
+	if err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to compute application statistics").Response(c)
+		return
+	}
+
+	if err := h.cache.SetApplicationStats(ctx, applicationID.String(), stats); err != nil {
+		// Log cache error but don't fail the request
+		logger.FromContext(ctx).Error("Failed to cache application stats", err, logger.Fields{"application_id": applicationID.String()})
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetCompanyStats returns the bug statistics of every application a company owns
+//
+// @Summary     Company bug statistics
+// @Description Returns the same statistics as GET /applications/{id}/stats, aggregated across every application the company owns. Results are cached for 10 minutes.
+// @Tags        companies
+// @Produce     json
+// @Param       id path string true "Company ID" format(uuid)
+// @Success     200 {object} BugStatisticsResponse
+// @Failure     400 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /companies/{id}/stats [get]
+func (h *CompanyHandler) GetCompanyStats(c *gin.Context) {
+	companyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid company ID format").Response(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	var cached BugStatisticsResponse
+	if err := h.cache.GetCompanyStats(ctx, companyID.String(), &cached); err == nil {
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+
+	var company models.Company
+	if err := h.db.First(&company, "id = ?", companyID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrCompanyNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch company").Response(c)
+		return
+	}
+
+	stats, err := loadBugStatistics(h.db.WithContext(ctx), func(db *gorm.DB) *gorm.DB {
+		return db.Where("bug_reports.application_id IN (?)",
+			db.Session(&gorm.Session{NewDB: true}).Model(&models.Application{}).Select("id").Where("company_id = ?", companyID))
+	}, time.Now())
+	if err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to compute company statistics").Response(c)
+		return
+	}
+
+	if err := h.cache.SetCompanyStats(ctx, companyID.String(), stats); err != nil {
+		// Log cache error but don't fail the request
+		logger.FromContext(ctx).Error("Failed to cache company stats", err, logger.Fields{"company_id": companyID.String()})
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// loadBugStatistics aggregates the bugs selected by scope. The queries run in one
+// read-only transaction so the figures are taken from the same snapshot. Spam is not
+// counted.
+func loadBugStatistics(db *gorm.DB, scope func(*gorm.DB) *gorm.DB, now time.Time) (*BugStatisticsResponse, error) {
+	stats := &BugStatisticsResponse{
+		ByStatus: map[string]int64{
+			models.BugStatusOpen:      0,
+			models.BugStatusReviewing: 0,
+			models.BugStatusFixed:     0,
+			models.BugStatusWontFix:   0,
+		},
+		ByPriority: map[string]int64{
+			models.BugPriorityLow:      0,
+			models.BugPriorityMedium:   0,
+			models.BugPriorityHigh:     0,
+			models.BugPriorityCritical: 0,
+		},
+		TopReporters: make([]ReporterSummary, 0),
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		bugs := func() *gorm.DB {
+			return scope(tx.Model(&models.BugReport{}).Where("bug_reports.is_spam = ?", false))
+		}
+
+		var statusCounts []struct {
+			Status string
+			Count  int64
+		}
+		if err := bugs().Select("status, COUNT(*) AS count").Group("status").Scan(&statusCounts).Error; err != nil {
+			return err
+		}
+		for _, count := range statusCounts {
+			stats.ByStatus[count.Status] = count.Count
+			stats.TotalBugs += count.Count
+		}
+
+		var priorityCounts []struct {
+			Priority string
+			Count    int64
+		}
+		if err := bugs().Select("priority, COUNT(*) AS count").Group("priority").Scan(&priorityCounts).Error; err != nil {
+			return err
+		}
+		for _, count := range priorityCounts {
+			stats.ByPriority[count.Priority] = count.Count
+		}
+
+		var resolution struct {
+			AvgResolutionHours *float64
+		}
+		if err := bugs().
+			Select("AVG(EXTRACT(EPOCH FROM resolved_at - created_at)) / 3600 AS avg_resolution_hours").
+			Where("status IN ? AND resolved_at IS NOT NULL", []string{models.BugStatusFixed, models.BugStatusWontFix}).
+			Scan(&resolution).Error; err != nil {
+			return err
+		}
+		stats.AvgResolutionHours = resolution.AvgResolutionHours
+
+		if err := bugs().
+			Select("users.id, users.display_name, COUNT(*) AS bug_count").
+			Joins("JOIN users ON users.id = bug_reports.reporter_id").
+			Group("users.id, users.display_name").
+			Order("bug_count DESC, users.display_name ASC").
+			Limit(bugStatisticsTopReporters).
+			Scan(&stats.TopReporters).Error; err != nil {
+			return err
+		}
+
+		var votes struct {
+			VotesNone int64
+			VotesFew  int64
+			VotesSome int64
+			VotesMany int64
+		}
+		if err := bugs().
+			Select("COALESCE(SUM(CASE WHEN vote_count <= 0 THEN 1 ELSE 0 END), 0) AS votes_none, " +
+				"COALESCE(SUM(CASE WHEN vote_count BETWEEN 1 AND 5 THEN 1 ELSE 0 END), 0) AS votes_few, " +
+				"COALESCE(SUM(CASE WHEN vote_count BETWEEN 6 AND 20 THEN 1 ELSE 0 END), 0) AS votes_some, " +
+				"COALESCE(SUM(CASE WHEN vote_count > 20 THEN 1 ELSE 0 END), 0) AS votes_many").
+			Scan(&votes).Error; err != nil {
+			return err
+		}
+		stats.VoteDistribution = map[string]int64{
+			VoteBucketNone: votes.VotesNone,
+			VoteBucketFew:  votes.VotesFew,
+			VoteBucketSome: votes.VotesSome,
+			VoteBucketMany: votes.VotesMany,
+		}
+
+		weeks := bugStatisticsWeekStarts(now)
+		var reported []WeeklyBugCount
+		if err := bugs().
+			Select("date_trunc('week', created_at AT TIME ZONE 'UTC') AS week_start, COUNT(*) AS count").
+			Where("created_at >= ?", weeks[0]).
+			Group("week_start").
+			Scan(&reported).Error; err != nil {
+			return err
+		}
+		stats.BugsPerWeek = weeklyBugCounts(weeks, reported)
+
+		return nil
+	}, &sql.TxOptions{ReadOnly: true, Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// bugStatisticsWeekStarts returns the start, midnight UTC on Monday, of the current
+// week and of each of the weeks before it that bug statistics cover, oldest first
+func bugStatisticsWeekStarts(now time.Time) []time.Time {
+	now = now.UTC()
+	daysSinceMonday := (int(now.Weekday()) + 6) % 7
+	current := time.Date(now.Year(), now.Month(), now.Day()-daysSinceMonday, 0, 0, 0, 0, time.UTC)
+
+	weeks := make([]time.Time, bugStatisticsWeeks)
+	for i := range weeks {
+		weeks[i] = current.AddDate(0, 0, -7*(bugStatisticsWeeks-1-i))
+	}
+	return weeks
+}
+
+// weeklyBugCounts lists the count of each week starting at weeks, taking the counts
+// from reported and zero for weeks without bugs
+func weeklyBugCounts(weeks []time.Time, reported []WeeklyBugCount) []WeeklyBugCount {
+	byWeek := make(map[time.Time]int64, len(reported))
+	for _, week := range reported {
+		start := week.WeekStart
+		byWeek[time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)] = week.Count
+	}
+
+	counts := make([]WeeklyBugCount, len(weeks))
+	for i, start := range weeks {
+		counts[i] = WeeklyBugCount{WeekStart: start, Count: byWeek[start]}
+	}
+	return counts
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/cache"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// addStatisticsBug creates a bug in the application with the given fields
+func addStatisticsBug(t *testing.T, db *gorm.DB, app *models.Application, reporter *models.User, fields map[string]interface{}) {
+	bug := createTestBugReport(t, db, app, reporter)
+	require.NoError(t, db.Model(bug).Updates(fields).Error)
+}
+
+func TestBugStatisticsWeekStarts(t *testing.T) {
+	// Wednesday
+	weeks := bugStatisticsWeekStarts(time.Date(2024, 3, 13, 15, 30, 0, 0, time.UTC))
+	require.Len(t, weeks, bugStatisticsWeeks)
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), weeks[len(weeks)-1])
+	assert.Equal(t, time.Date(2023, 12, 25, 0, 0, 0, 0, time.UTC), weeks[0])
+
+	// A Sunday belongs to the week that started the Monday before
+	weeks = bugStatisticsWeekStarts(time.Date(2024, 3, 17, 23, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), weeks[len(weeks)-1])
+
+	// Weeks truncated by the database come back without a time zone
+	counts := weeklyBugCounts(weeks, []WeeklyBugCount{
+		{WeekStart: time.Date(2023, 12, 25, 0, 0, 0, 0, time.FixedZone("", 0)), Count: 2},
+		{WeekStart: weeks[len(weeks)-1], Count: 1},
+	})
+	require.Len(t, counts, bugStatisticsWeeks)
+	assert.Equal(t, WeeklyBugCount{WeekStart: weeks[0], Count: 2}, counts[0])
+	assert.Equal(t, WeeklyBugCount{WeekStart: weeks[1], Count: 0}, counts[1])
+	assert.Equal(t, int64(1), counts[len(counts)-1].Count)
+}
+
+func TestApplicationHandler_GetApplicationStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupBugTestDB(t)
+	redisClient, mock := newMockRedisClient()
+	handler := NewApplicationHandler(db, redisClient)
+	app := createTestApplication(t, db)
+
+	alice := &models.User{ID: uuid.New(), Email: "alice@example.com", DisplayName: "Alice"}
+	bob := &models.User{ID: uuid.New(), Email: "bob@example.com", DisplayName: "Bob"}
+	require.NoError(t, db.Create(alice).Error)
+	require.NoError(t, db.Create(bob).Error)
+
+	now := time.Now()
+	addStatisticsBug(t, db, app, alice, map[string]interface{}{
+		"status": models.BugStatusFixed, "priority": models.BugPriorityHigh,
+		"created_at": now.Add(-10 * time.Hour), "resolved_at": now, "vote_count": 3,
+	})
+	addStatisticsBug(t, db, app, alice, map[string]interface{}{
+		"status": models.BugStatusWontFix, "priority": models.BugPriorityLow,
+		"created_at": now.Add(-30 * time.Hour), "resolved_at": now, "vote_count": 25,
+	})
+	addStatisticsBug(t, db, app, bob, map[string]interface{}{
+		"status": models.BugStatusOpen, "priority": models.BugPriorityCritical,
+		"created_at": now.AddDate(0, 0, -200), "vote_count": 10,
+	})
+	// Spam is not counted
+	addStatisticsBug(t, db, app, bob, map[string]interface{}{"is_spam": true, "vote_count": 50})
+
+	getStats := func(appID string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/applications/"+appID+"/stats", nil)
+		c.Params = gin.Params{{Key: "id", Value: appID}}
+		handler.GetApplicationStats(c)
+		return w
+	}
+
+	w := getStats(app.ID.String())
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var stats BugStatisticsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+	assert.Equal(t, int64(3), stats.TotalBugs)
+	assert.Equal(t, map[string]int64{"open": 1, "reviewing": 0, "fixed": 1, "wont_fix": 1}, stats.ByStatus)
+	assert.Equal(t, map[string]int64{"low": 1, "medium": 0, "high": 1, "critical": 1}, stats.ByPriority)
+	require.NotNil(t, stats.AvgResolutionHours)
+	assert.InDelta(t, 20, *stats.AvgResolutionHours, 0.01)
+	assert.Equal(t, []ReporterSummary{
+		{ID: alice.ID, DisplayName: "Alice", BugCount: 2},
+		{ID: bob.ID, DisplayName: "Bob", BugCount: 1},
+	}, stats.TopReporters)
+	assert.Equal(t, map[string]int64{"0": 0, "1-5": 1, "6-20": 1, "21+": 1}, stats.VoteDistribution)
+
+	require.Len(t, stats.BugsPerWeek, bugStatisticsWeeks)
+	var recent int64
+	for _, week := range stats.BugsPerWeek {
+		recent += week.Count
+	}
+	// The bug reported 200 days ago is outside the last 12 weeks
+	assert.Equal(t, int64(2), recent)
+
+	// The result is cached for 10 minutes under app_stats:{id}
+	key := cache.ApplicationStatsCachePrefix + app.ID.String()
+	assert.Equal(t, cache.BugStatisticsCacheDuration, mock.ttls[key])
+
+	addStatisticsBug(t, db, app, bob, map[string]interface{}{"status": models.BugStatusOpen})
+	cached := getStats(app.ID.String())
+	assert.Equal(t, w.Body.String(), cached.Body.String())
+
+	t.Run("errors", func(t *testing.T) {
+		w := getStats("not-a-uuid")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_ID")
+
+		w = getStats(uuid.New().String())
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "APPLICATION_NOT_FOUND")
+	})
+}
+
+func TestCompanyHandler_GetCompanyStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupCompanyTestHandler(t)
+	user := createTestUser(t, db)
+	company := createTestCompany(t, db, true)
+	first := createTestCompanyApplication(t, db, company)
+	second := createTestCompanyApplication(t, db, company)
+	other := createTestApplication(t, db)
+
+	addStatisticsBug(t, db, first, user, map[string]interface{}{"status": models.BugStatusOpen})
+	addStatisticsBug(t, db, second, user, map[string]interface{}{"status": models.BugStatusFixed, "resolved_at": time.Now()})
+	addStatisticsBug(t, db, other, user, map[string]interface{}{"status": models.BugStatusOpen})
+
+	getStats := func(companyID string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/companies/"+companyID+"/stats", nil)
+		c.Params = gin.Params{{Key: "id", Value: companyID}}
+		handler.GetCompanyStats(c)
+		return w
+	}
+
+	w := getStats(company.ID.String())
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var stats BugStatisticsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+	// Bugs in applications the company does not own are not counted
+	assert.Equal(t, int64(2), stats.TotalBugs)
+	assert.Equal(t, int64(1), stats.ByStatus[models.BugStatusOpen])
+	assert.Equal(t, int64(1), stats.ByStatus[models.BugStatusFixed])
+	require.Len(t, stats.TopReporters, 1)
+	assert.Equal(t, int64(2), stats.TopReporters[0].BugCount)
+
+	w = getStats(uuid.New().String())
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "COMPANY_NOT_FOUND")
+}
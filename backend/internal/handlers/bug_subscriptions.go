@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/middleware"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm/clause"
+)
+
+// SubscribeToBug subscribes the current user to a bug's status changes, comments and
+// company responses. Subscribing to a bug twice has no effect.
+//
+// @Summary     Watch a bug
+// @Description Subscribes the current user to notifications about the bug's status changes, comments and company responses.
+// @Tags        bugs
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Bug ID" format(uuid)
+// @Success     200 {object} object{message=string,subscribed=bool}
+// @Failure     400 {object} errors.ErrorResponse
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /bugs/{id}/subscribe [post]
+func (h *BugHandler) SubscribeToBug(c *gin.Context) {
+	bugUUID, userUUID, ok := h.bugSubscriptionTarget(c)
+	if !ok {
+		return
+	}
+
+	subscription := models.BugSubscription{BugID: bugUUID, UserID: userUUID}
+	if err := h.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&subscription).Error; err != nil {
+		errors.ErrSubscriptionFailed.Response(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Subscribed to bug",
+		"subscribed": true,
+	})
+}
+
+// UnsubscribeFromBug stops the current user's notifications about a bug. Reporters
+// can unsubscribe from their own bugs.
+//
+// @Summary     Stop watching a bug
+// @Description Unsubscribes the current user from notifications about the bug.
+// @Tags        bugs
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Bug ID" format(uuid)
+// @Success     200 {object} object{message=string,subscribed=bool}
+// @Failure     400 {object} errors.ErrorResponse
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /bugs/{id}/subscribe [delete]
+func (h *BugHandler) UnsubscribeFromBug(c *gin.Context) {
+	bugUUID, userUUID, ok := h.bugSubscriptionTarget(c)
+	if !ok {
+		return
+	}
+
+	if err := h.db.Where("bug_id = ? AND user_id = ?", bugUUID, userUUID).Delete(&models.BugSubscription{}).Error; err != nil {
+		errors.ErrSubscriptionFailed.Response(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Unsubscribed from bug",
+		"subscribed": false,
+	})
+}
+
+// bugSubscriptionTarget returns the bug named by the id parameter and the current
+// user. Errors are written to the response and reported by returning false.
+func (h *BugHandler) bugSubscriptionTarget(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	bugUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid bug ID format").Response(c)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userIDStr, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		errors.ErrAuthRequired.WithMessage("Authentication required to watch bugs").Response(c)
+		return uuid.Nil, uuid.Nil, false
+	}
+	userUUID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		errors.ErrInvalidUser.Response(c)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	var count int64
+	if err := h.db.Model(&models.BugReport{}).Where("id = ?", bugUUID).Count(&count).Error; err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to verify bug report").Response(c)
+		return uuid.Nil, uuid.Nil, false
+	}
+	if count == 0 {
+		errors.ErrBugNotFound.Response(c)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return bugUUID, userUUID, true
+}
+
+// isSubscribedToBug reports whether the user watches the bug
+func (h *BugHandler) isSubscribedToBug(ctx context.Context, bugID, userID uuid.UUID) (bool, error) {
+	var count int64
+	err := h.db.WithContext(ctx).Model(&models.BugSubscription{}).
+		Where("bug_id = ? AND user_id = ?", bugID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// bugSubscribers returns the users watching the bug, other than the actor and users
+// with a block between them and the actor
+func (h *BugHandler) bugSubscribers(ctx context.Context, bugID, actorID uuid.UUID) ([]uuid.UUID, error) {
+	var subscribers []uuid.UUID
+	err := h.db.WithContext(ctx).Model(&models.BugSubscription{}).
+		Where("bug_id = ? AND user_id <> ?", bugID, actorID).
+		Where("user_id NOT IN (?)", h.db.Model(&models.UserBlock{}).Select("blocker_id").Where("blocked_id = ?", actorID)).
+		Where("user_id NOT IN (?)", h.db.Model(&models.UserBlock{}).Select("blocked_id").Where("blocker_id = ?", actorID)).
+		Pluck("user_id", &subscribers).Error
+	return subscribers, err
+}
+
+// notifyBugSubscribers creates an in-app notification for each user watching the
+// bug, unless they have turned off the notification type. It runs after the change
+// is saved, so a failure is only logged.
+func (h *BugHandler) notifyBugSubscribers(ctx context.Context, bug *models.BugReport, actorID uuid.UUID, notificationType, body string, payload gin.H) {
+	if err := h.createSubscriberNotifications(ctx, bug, actorID, notificationType, body, payload); err != nil {
+		logger.FromContext(ctx).Error("Failed to notify bug subscribers", err, logger.Fields{
+			"bug_id": bug.ID.String(),
+			"type":   notificationType,
+		})
+	}
+}
+
+// createSubscriberNotifications saves the notifications sent by notifyBugSubscribers
+func (h *BugHandler) createSubscriberNotifications(ctx context.Context, bug *models.BugReport, actorID uuid.UUID, notificationType, body string, payload gin.H) error {
+	subscribers, err := h.bugSubscribers(ctx, bug.ID, actorID)
+	if err != nil {
+		return err
+	}
+
+	var notifications []models.Notification
+	for _, subscriber := range subscribers {
+		preferences, err := loadNotificationPreferences(ctx, h.db, h.cache, subscriber)
+		if err != nil {
+			return err
+		}
+		if !preferences.Allows(notificationType) {
+			continue
+		}
+		notifications = append(notifications, models.Notification{
+			UserID:     subscriber,
+			Type:       notificationType,
+			ResourceID: &bug.ID,
+			Body:       body,
+		})
+	}
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	payload["bug_id"] = bug.ID
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	for i := range notifications {
+		notifications[i].Payload = datatypes.JSON(encoded)
+	}
+
+	return h.db.WithContext(ctx).Create(&notifications).Error
+}
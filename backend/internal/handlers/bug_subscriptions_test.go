@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bugSubscriptionRequest calls a subscription handler for the bug as the user
+func bugSubscriptionRequest(t *testing.T, handler gin.HandlerFunc, method string, bugID, userID uuid.UUID) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, fmt.Sprintf("/bugs/%s/subscribe", bugID), nil)
+	c.Params = gin.Params{{Key: "id", Value: bugID.String()}}
+	mockAuthMiddleware(userID)(c)
+
+	handler(c)
+	return w
+}
+
+func TestBugHandler_BugSubscriptions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	user := createTestUser(t, db)
+	app := createTestApplication(t, db)
+	bug := createTestBugReport(t, db, app, user)
+
+	getBug := func(userID *uuid.UUID) map[string]interface{} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", fmt.Sprintf("/bugs/%s", bug.ID), nil)
+		c.Params = gin.Params{{Key: "id", Value: bug.ID.String()}}
+		if userID != nil {
+			mockAuthMiddleware(*userID)(c)
+		}
+
+		handler.GetBug(c)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	assert.Equal(t, false, getBug(&user.ID)["subscribed"])
+	assert.NotContains(t, getBug(nil), "subscribed")
+
+	// Subscribing twice keeps a single subscription
+	for i := 0; i < 2; i++ {
+		w := bugSubscriptionRequest(t, handler.SubscribeToBug, "POST", bug.ID, user.ID)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	}
+	var count int64
+	require.NoError(t, db.Model(&models.BugSubscription{}).Where("bug_id = ?", bug.ID).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+	assert.Equal(t, true, getBug(&user.ID)["subscribed"])
+
+	w := bugSubscriptionRequest(t, handler.UnsubscribeFromBug, "DELETE", bug.ID, user.ID)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, false, getBug(&user.ID)["subscribed"])
+
+	w = bugSubscriptionRequest(t, handler.SubscribeToBug, "POST", uuid.New(), user.ID)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestBugHandler_CreateBugSubscribesReporter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	user := createTestUser(t, db)
+
+	body, err := json.Marshal(map[string]interface{}{
+		"title":            "Reporter subscription bug",
+		"description":      "This is a valid bug description with sufficient length",
+		"application_name": "Subscription App",
+	})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/bugs", bytes.NewBuffer(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	mockAuthMiddleware(user.ID)(c)
+
+	handler.CreateBug(c)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var created models.BugReport
+	require.NoError(t, db.Where("title = ?", "Reporter subscription bug").First(&created).Error)
+
+	var subscription models.BugSubscription
+	require.NoError(t, db.Where("bug_id = ?", created.ID).First(&subscription).Error)
+	assert.Equal(t, user.ID, subscription.UserID)
+}
+
+func TestBugHandler_NotifyBugSubscribers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	reporter := createTestUser(t, db)
+	app := createTestApplication(t, db)
+	bug := createTestBugReport(t, db, app, reporter)
+
+	newUser := func(name string) *models.User {
+		user := &models.User{ID: uuid.New(), Email: name + "@example.com", DisplayName: name}
+		require.NoError(t, db.Create(user).Error)
+		require.NoError(t, db.Create(&models.BugSubscription{BugID: bug.ID, UserID: user.ID}).Error)
+		return user
+	}
+	watcher := newUser("watcher")
+	muted := newUser("muted")
+	blocker := newUser("blocker")
+	admin := &models.User{ID: uuid.New(), Email: "admin@example.com", DisplayName: "Admin", IsAdmin: true}
+	require.NoError(t, db.Create(admin).Error)
+
+	preferences := models.DefaultNotificationPreferences(muted.ID)
+	require.NoError(t, db.Create(&preferences).Error)
+	require.NoError(t, db.Model(&preferences).Update("bug_status_change", false).Error)
+	require.NoError(t, db.Create(&models.UserBlock{BlockerID: blocker.ID, BlockedID: admin.ID}).Error)
+
+	notified := func(notificationType string) []uuid.UUID {
+		var users []uuid.UUID
+		require.NoError(t, db.Model(&models.Notification{}).
+			Where("resource_id = ? AND type = ?", bug.ID, notificationType).
+			Order("user_id").
+			Pluck("user_id", &users).Error)
+		return users
+	}
+
+	t.Run("status changes", func(t *testing.T) {
+		body, err := json.Marshal(gin.H{"status": models.BugStatusReviewing})
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("PATCH", fmt.Sprintf("/bugs/%s/status", bug.ID), bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "id", Value: bug.ID.String()}}
+		mockAdminAuthMiddleware(admin.ID)(c)
+
+		handler.UpdateBugStatus(c)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		// The muted subscriber turned status notifications off and the blocker blocked the admin
+		assert.Equal(t, []uuid.UUID{watcher.ID}, notified(models.NotificationTypeBugStatusChange))
+	})
+
+	t.Run("comments", func(t *testing.T) {
+		body, err := json.Marshal(gin.H{"content": "A comment from a subscriber"})
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", fmt.Sprintf("/bugs/%s/comments", bug.ID), bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "id", Value: bug.ID.String()}}
+		mockAuthMiddleware(watcher.ID)(c)
+
+		handler.CreateComment(c)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		// The commenter is not notified of their own comment
+		recipients := notified(models.NotificationTypeCommentOnMyBug)
+		assert.ElementsMatch(t, []uuid.UUID{muted.ID, blocker.ID}, recipients)
+
+		var notification models.Notification
+		require.NoError(t, db.Where("user_id = ? AND type = ?", muted.ID, models.NotificationTypeCommentOnMyBug).First(&notification).Error)
+		assert.Equal(t, "watcher commented on the bug report 'Test Bug' you are watching", notification.Body)
+	})
+}
@@ -1,91 +1,235 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
-	"net/url"
+	"regexp"
 	"strings"
 	"time"
 
 	"bugrelay-backend/internal/cache"
+	"bugrelay-backend/internal/captcha"
+	"bugrelay-backend/internal/database"
+	"bugrelay-backend/internal/email"
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/geoip"
+	"bugrelay-backend/internal/jobs"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/metrics"
 	"bugrelay-backend/internal/middleware"
 	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/notification"
+	"bugrelay-backend/internal/storage"
 	"bugrelay-backend/internal/utils"
+	"bugrelay-backend/internal/webhooks"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // BugHandler handles bug-related HTTP requests
 type BugHandler struct {
-	db              *gorm.DB
-	cache           *cache.CacheService
-	recaptchaSecret string
+	db        *gorm.DB
+	cache     *cache.CacheService
+	storage   storage.Backend
+	deepLinks *email.DeepLinkGenerator
+	projector *jobs.BugProjector
+
+	// captcha checks tokens on anonymous submissions; nil when they are not checked
+	captcha                 captcha.Verifier
+	recaptchaScoreThreshold float64
+
+	spamScoreThreshold float64
+	htmlRendering      bool
+
+	titleMinWords       int
+	descriptionMinWords int
+
+	tagConfig utils.TagConfig
+
+	voteMilestones []int
+
+	tagGroups map[string][]string
+
+	anonymousBugsPerEmailPerDay int
+
+	pagination PaginationConfig
+
+	trending TrendingConfig
+
+	// notifications receives real-time bug events; nil when they are disabled
+	notifications *notification.Hub
+
+	// webhookDispatcher posts bug events to company webhooks; nil when they are disabled
+	webhookDispatcher *webhooks.Dispatcher
+
+	// geoLookup locates bug submitters; nil when there is no GeoIP database
+	geoLookup geoip.LookupFunc
+
+	bugFetches     singleflight.Group
+	bugFetchCounts fetchCounts
 }
 
 // NewBugHandler creates a new bug handler
 func NewBugHandler(db *gorm.DB, redisClient *redis.Client) *BugHandler {
 	return &BugHandler{
-		db:              db,
-		cache:           cache.NewCacheService(redisClient),
-		recaptchaSecret: "", // Will be set from config in production
+		db:        db,
+		cache:     cache.NewCacheService(redisClient),
+		storage:   storage.NewLocalBackend(storage.DefaultLocalDir),
+		deepLinks: email.NewDeepLinkGenerator("http://localhost:3000", ""),
+		projector: jobs.NewBugProjector(db),
+
+		recaptchaScoreThreshold: defaultRecaptchaScoreThreshold,
+
+		spamScoreThreshold: defaultSpamScoreThreshold,
+
+		titleMinWords:       defaultTitleMinWords,
+		descriptionMinWords: defaultDescriptionMinWords,
+
+		tagConfig: utils.DefaultTagConfig(),
+
+		voteMilestones: defaultVoteMilestones,
+
+		tagGroups: defaultTagGroups,
+
+		anonymousBugsPerEmailPerDay: defaultAnonymousBugsPerEmailPerDay,
+
+		pagination: PaginationConfig{DefaultLimit: DefaultPageLimit, MaxLimit: defaultBugListMaxLimit},
+
+		trending: DefaultTrendingConfig(),
 	}
 }
 
-// SetRecaptchaSecret sets the reCAPTCHA secret key
-func (h *BugHandler) SetRecaptchaSecret(secret string) {
-	h.recaptchaSecret = secret
+// defaultRecaptchaScoreThreshold is the lowest reCAPTCHA v3 score accepted when no
+// threshold is configured
+const defaultRecaptchaScoreThreshold = 0.5
+
+// defaultSpamScoreThreshold is the spam score at which bugs are hidden from listings
+const defaultSpamScoreThreshold = 0.8
+
+// Default minimum word counts for bug submissions
+const (
+	defaultTitleMinWords       = 2
+	defaultDescriptionMinWords = 5
+)
+
+// adminOverrideHeader lets admins submit bugs that fail the word count checks
+const adminOverrideHeader = "X-Admin-Override"
+
+// SetBugProjector sets the projector that applies bug events to bug reports
+func (h *BugHandler) SetBugProjector(projector *jobs.BugProjector) {
+	h.projector = projector
 }
 
-// RecaptchaResponse represents the response from Google reCAPTCHA API
-type RecaptchaResponse struct {
-	Success     bool     `json:"success"`
-	Score       float64  `json:"score,omitempty"`
-	Action      string   `json:"action,omitempty"`
-	ChallengeTS string   `json:"challenge_ts,omitempty"`
-	Hostname    string   `json:"hostname,omitempty"`
-	ErrorCodes  []string `json:"error-codes,omitempty"`
+// SetCaptchaVerifier sets the verifier that checks captcha tokens on anonymous
+// submissions. A nil verifier accepts every submission.
+func (h *BugHandler) SetCaptchaVerifier(verifier captcha.Verifier) {
+	h.captcha = verifier
 }
 
-// validateRecaptcha validates reCAPTCHA token with Google's API
-func (h *BugHandler) validateRecaptcha(token string) (bool, error) {
-	if h.recaptchaSecret == "" || token == "" {
-		// Skip validation if no secret configured or no token provided
-		return true, nil
-	}
+// SetRecaptchaScoreThreshold sets the lowest reCAPTCHA v3 score a submission may have
+func (h *BugHandler) SetRecaptchaScoreThreshold(threshold float64) {
+	h.recaptchaScoreThreshold = threshold
+}
+
+// SetSpamScoreThreshold sets the spam score at which bugs are hidden from ListBugs
+func (h *BugHandler) SetSpamScoreThreshold(threshold float64) {
+	h.spamScoreThreshold = threshold
+}
+
+// SetMinWordCounts sets the fewest words a bug title and description may have. Zero
+// disables a check.
+func (h *BugHandler) SetMinWordCounts(titleMinWords, descriptionMinWords int) {
+	h.titleMinWords = titleMinWords
+	h.descriptionMinWords = descriptionMinWords
+}
+
+// SetTagConfig sets how many tags a bug may have and how long each tag may be
+func (h *BugHandler) SetTagConfig(cfg utils.TagConfig) {
+	h.tagConfig = cfg
+}
+
+// SetAnonymousBugsPerEmailPerDay sets how many bugs can be submitted anonymously with
+// the same contact email each day. Zero disables the limit.
+func (h *BugHandler) SetAnonymousBugsPerEmailPerDay(limit int) {
+	h.anonymousBugsPerEmailPerDay = limit
+}
 
-	// Prepare the request to Google's reCAPTCHA API
-	data := url.Values{}
-	data.Set("secret", h.recaptchaSecret)
-	data.Set("response", token)
+// SetPagination sets the page sizes of bug listings
+func (h *BugHandler) SetPagination(pagination PaginationConfig) {
+	h.pagination = pagination
+}
 
-	resp, err := http.PostForm("https://www.google.com/recaptcha/api/siteverify", data)
+// SetHTMLRendering sets whether ListBugs and GetBug render HTML for clients that ask
+// for text/html. The router must have the HTML templates loaded.
+func (h *BugHandler) SetHTMLRendering(enabled bool) {
+	h.htmlRendering = enabled
+}
+
+// SetDeepLinks sets the generator used for links in notification emails
+func (h *BugHandler) SetDeepLinks(deepLinks *email.DeepLinkGenerator) {
+	h.deepLinks = deepLinks
+}
+
+// SetStorage sets the backend used to generate attachment preview URLs
+func (h *BugHandler) SetStorage(backend storage.Backend) {
+	h.storage = backend
+}
+
+// setPreviewURLs fills in the preview URL of each attachment. URLs are generated on
+// every response rather than cached since pre-signed URLs expire.
+func (h *BugHandler) setPreviewURLs(attachments []models.FileAttachment) {
+	for i := range attachments {
+		attachments[i].PreviewURL = h.storage.GeneratePreviewURL(attachments[i])
+	}
+}
+
+// currentUserBlockList loads the block list of the authenticated user. It returns
+// false when the request is anonymous.
+func (h *BugHandler) currentUserBlockList(c *gin.Context) (*userBlockList, bool, error) {
+	userIDStr, exists := middleware.GetCurrentUserID(c)
+	if !exists {
+		return nil, false, nil
+	}
+	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		return false, err
+		return nil, false, nil
 	}
-	defer resp.Body.Close()
 
-	var recaptchaResp RecaptchaResponse
-	if err := json.NewDecoder(resp.Body).Decode(&recaptchaResp); err != nil {
-		return false, err
+	blocks, err := loadUserBlockList(c.Request.Context(), h.db, h.cache, userID)
+	if err != nil {
+		return nil, false, err
 	}
+	return blocks, true, nil
+}
 
-	// For reCAPTCHA v3, check both success and score (should be > 0.5)
-	// For reCAPTCHA v2, only check success
-	if recaptchaResp.Success {
-		if recaptchaResp.Score > 0 {
-			// reCAPTCHA v3 - check score
-			return recaptchaResp.Score >= 0.5, nil
-		}
-		// reCAPTCHA v2 - success is enough
+// validateCaptcha checks a captcha token with the configured provider. Requests that
+// take longer than the verifier's HTTP client timeout fail with an error.
+func (h *BugHandler) validateCaptcha(ctx context.Context, token string) (bool, error) {
+	if h.captcha == nil || token == "" {
+		// Skip validation if no provider is configured or no token provided
 		return true, nil
 	}
 
-	return false, nil
+	result, err := h.captcha.Verify(ctx, token)
+	if err != nil {
+		return false, err
+	}
+
+	// reCAPTCHA v3 scores every token; reCAPTCHA v2 and hCaptcha only report success
+	if result.Success && result.Score > 0 {
+		return result.Score >= h.recaptchaScoreThreshold, nil
+	}
+	return result.Success, nil
 }
 
 // CreateBugRequest represents the request payload for creating a bug
@@ -101,6 +245,10 @@ type CreateBugRequest struct {
 	AppVersion      *string `json:"app_version,omitempty"`
 	BrowserVersion  *string `json:"browser_version,omitempty"`
 
+	// Structured environment details. Fields left out are detected from the
+	// User-Agent and Accept-Language headers where possible.
+	EnvironmentInfo *EnvironmentInfoRequest `json:"environment_info,omitempty"`
+
 	// Application info
 	ApplicationName string  `json:"application_name" binding:"required,min=1,max=255"`
 	ApplicationURL  *string `json:"application_url,omitempty"`
@@ -108,23 +256,80 @@ type CreateBugRequest struct {
 	// Contact info (optional for anonymous submissions)
 	ContactEmail *string `json:"contact_email,omitempty"`
 
+	// Application-specific fields, validated against the application's schema
+	CustomFields json.RawMessage `json:"custom_fields,omitempty" swaggertype:"object"`
+
+	// ForceCreate submits the bug even if similar bugs are open in the application
+	ForceCreate bool `json:"force_create,omitempty"`
+
 	// Anti-spam measures
 	RecaptchaToken *string `json:"recaptcha_token,omitempty"`
+	Website        string  `json:"website,omitempty"` // honeypot, never filled in by real clients
+}
+
+// EnvironmentInfoRequest is the environment a bug was reported from, as submitted
+type EnvironmentInfoRequest struct {
+	OSName           string `json:"os_name" binding:"max=100"`
+	OSVersion        string `json:"os_version" binding:"max=100"`
+	BrowserName      string `json:"browser_name" binding:"max=100"`
+	BrowserVersion   string `json:"browser_version" binding:"max=100"`
+	ScreenResolution string `json:"screen_resolution" binding:"max=100"`
+	Language         string `json:"language" binding:"max=100"`
+	Timezone         string `json:"timezone" binding:"max=100"`
 }
 
 // CreateBug handles bug submission
+//
+// @Summary     Submit a bug report
+// @Description Submits a bug report, anonymously or as the current user. Submissions similar to open bugs in the same application are not created and get the similar bugs with code POSSIBLE_DUPLICATE, unless force_create is set. Retries with the same X-Idempotency-Key get the original response.
+// @Tags        bugs
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       X-Idempotency-Key header string false "Key identifying retries of the same submission"
+// @Param       request body CreateBugRequest true "Bug report"
+// @Success     200 {object} PossibleDuplicateResponse "Similar bugs are open; no bug was created"
+// @Success     201 {object} object{message=string,bug=models.BugReport}
+// @Failure     400 {object} errors.ErrorResponse "Invalid submission"
+// @Failure     409 {object} errors.ErrorResponse "A request with the idempotency key is in progress (IDEMPOTENCY_KEY_IN_USE)"
+// @Failure     413 {object} errors.ErrorResponse
+// @Failure     422 {object} errors.ErrorResponse "Title or description too short, or the application is archived"
+// @Failure     429 {object} errors.ErrorResponse "Rate limited"
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /bugs/ [post]
 func (h *BugHandler) CreateBug(c *gin.Context) {
+	// A retry of a request that already created a bug gets the original response
+	idempotencyKey := idempotencyKeyHash(c)
+	if !h.beginIdempotent(c, idempotencyKey) {
+		return
+	}
+
+	bug, ok := h.createBug(c, func(response PossibleDuplicateResponse) {
+		c.JSON(http.StatusOK, response)
+	})
+	if !ok {
+		h.abortIdempotent(c, idempotencyKey)
+		return
+	}
+
+	h.respondIdempotent(c, idempotencyKey, http.StatusCreated, gin.H{
+		"message": "Bug report created successfully",
+		"bug":     bug,
+	})
+}
+
+// createBug validates the submission in the request body and saves it, returning the
+// created bug with its relationships loaded. Errors are written to the response and
+// reported by returning false. A submission similar to open bugs is not created either;
+// respondDuplicates writes the suggested duplicates and false is returned.
+func (h *BugHandler) createBug(c *gin.Context, respondDuplicates func(PossibleDuplicateResponse)) (*models.BugReport, bool) {
 	var req CreateBugRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "VALIDATION_ERROR",
-				"message":   "Invalid request data",
-				"details":   err.Error(),
-				"timestamp": time.Now().UTC(),
-			},
-		})
-		return
+		if requestBodyTooLarge(c, err) {
+			return nil, false
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return nil, false
 	}
 
 	// Validate reCAPTCHA for anonymous submissions or if token is provided
@@ -135,105 +340,81 @@ func (h *BugHandler) CreateBug(c *gin.Context) {
 			token = *req.RecaptchaToken
 		}
 
-		isValid, err := h.validateRecaptcha(token)
+		isValid, err := h.validateCaptcha(c.Request.Context(), token)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": gin.H{
-					"code":      "RECAPTCHA_ERROR",
-					"message":   "Failed to validate reCAPTCHA",
-					"timestamp": time.Now().UTC(),
-				},
-			})
-			return
+			logger.FromContext(c.Request.Context()).Error("Failed to verify captcha token", err, nil)
+			errors.ErrRecaptchaError.Response(c)
+			return nil, false
 		}
 
 		if !isValid {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": gin.H{
-					"code":      "RECAPTCHA_FAILED",
-					"message":   "reCAPTCHA validation failed",
-					"timestamp": time.Now().UTC(),
-				},
-			})
-			return
+			errors.ErrRecaptchaFailed.Response(c)
+			return nil, false
 		}
 	}
 
 	// Sanitize and validate input fields
 	sanitizedTitle, titleValid := utils.ValidateString(req.Title, 5, 255)
 	if !titleValid {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_TITLE",
-				"message":   "Title must be between 5 and 255 characters and contain no malicious content",
-				"timestamp": time.Now().UTC(),
-			},
-		})
-		return
+		errors.ErrInvalidTitle.Response(c)
+		return nil, false
 	}
 
 	sanitizedDescription, descValid := utils.ValidateString(req.Description, 10, 5000)
 	if !descValid {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_DESCRIPTION",
-				"message":   "Description must be between 10 and 5000 characters and contain no malicious content",
-				"timestamp": time.Now().UTC(),
-			},
-		})
-		return
+		errors.ErrInvalidDescription.Response(c)
+		return nil, false
+	}
+
+	// Reject titles and descriptions like "broken" unless an admin overrides the check
+	if !(c.GetHeader(adminOverrideHeader) == "true" && middleware.IsCurrentUserAdmin(c)) {
+		if words := utils.CountWords(sanitizedTitle); words < h.titleMinWords {
+			errors.ErrTitleTooShort.WithDetails(gin.H{"min_words": h.titleMinWords, "current_words": words}).Response(c)
+			return nil, false
+		}
+		if words := utils.CountWords(sanitizedDescription); words < h.descriptionMinWords {
+			errors.ErrDescriptionTooShort.WithDetails(gin.H{"min_words": h.descriptionMinWords, "current_words": words}).Response(c)
+			return nil, false
+		}
 	}
 
 	sanitizedAppName, appNameValid := utils.ValidateString(req.ApplicationName, 1, 255)
 	if !appNameValid {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_APPLICATION_NAME",
-				"message":   "Application name must be between 1 and 255 characters and contain no malicious content",
-				"timestamp": time.Now().UTC(),
-			},
-		})
-		return
+		errors.ErrInvalidApplicationName.Response(c)
+		return nil, false
 	}
 
 	// Validate application URL if provided
 	if req.ApplicationURL != nil && *req.ApplicationURL != "" {
 		if !utils.ValidateURL(*req.ApplicationURL) {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": gin.H{
-					"code":      "INVALID_APPLICATION_URL",
-					"message":   "Invalid application URL format",
-					"timestamp": time.Now().UTC(),
-				},
-			})
-			return
+			errors.ErrInvalidApplicationURL.Response(c)
+			return nil, false
 		}
 	}
 
 	// Validate contact email if provided
+	var anonymousEmail string
 	if req.ContactEmail != nil && *req.ContactEmail != "" {
 		if !utils.ValidateEmail(*req.ContactEmail) {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": gin.H{
-					"code":      "INVALID_CONTACT_EMAIL",
-					"message":   "Invalid email format",
-					"timestamp": time.Now().UTC(),
-				},
-			})
-			return
+			errors.ErrInvalidContactEmail.Response(c)
+			return nil, false
+		}
+		if !isAuthenticated {
+			anonymousEmail = *req.ContactEmail
 		}
 	}
 
+	// Anonymous submitters can't get around the limit by clearing their session, as
+	// it follows the contact email
+	if anonymousEmail != "" && h.anonymousEmailLimitReached(c, anonymousEmail) {
+		errors.ErrAnonymousEmailLimitReached.WithDetails(gin.H{"limit": h.anonymousBugsPerEmailPerDay}).Response(c)
+		return nil, false
+	}
+
 	// Validate priority if provided
 	if req.Priority != "" && !utils.ValidatePriority(req.Priority) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_PRIORITY",
-				"message":   "Invalid priority value",
-				"timestamp": time.Now().UTC(),
-			},
-		})
-		return
+		errors.ErrInvalidPriority.Response(c)
+		return nil, false
 	}
 
 	// Set default priority if not provided
@@ -241,24 +422,33 @@ func (h *BugHandler) CreateBug(c *gin.Context) {
 		req.Priority = models.BugPriorityMedium
 	}
 
-	// Validate tags
-	if len(req.Tags) > 10 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "TOO_MANY_TAGS",
-				"message":   "Maximum 10 tags allowed",
-				"timestamp": time.Now().UTC(),
-			},
-		})
-		return
+	// Sanitize and validate tags
+	sanitizedTags, err := utils.ValidateTags(req.Tags, h.tagConfig)
+	if err != nil {
+		switch tagErr := err.(type) {
+		case *utils.TooManyTagsError:
+			errors.ErrTooManyTags.WithMessage(fmt.Sprintf("Maximum %d tags allowed", tagErr.Max)).
+				WithDetails(gin.H{"max_tags": tagErr.Max, "current_tags": tagErr.Count}).Response(c)
+		case *utils.InvalidTagsError:
+			errors.ErrInvalidTag.WithMessage("One or more tags are invalid").
+				WithDetails(gin.H{"tags": tagErr.Tags}).Response(c)
+		default:
+			errors.ErrValidationError.WithDetails(err.Error()).Response(c)
+		}
+		return nil, false
 	}
 
-	// Sanitize and validate tags
-	var sanitizedTags []string
-	for _, tag := range req.Tags {
-		tag = strings.ToLower(strings.TrimSpace(tag))
-		if tag != "" && utils.ValidateTag(tag) {
-			sanitizedTags = append(sanitizedTags, tag)
+	// Parse and sanitize custom fields
+	var customFields map[string]interface{}
+	if len(req.CustomFields) > 0 && string(req.CustomFields) != "null" {
+		if err := json.Unmarshal(req.CustomFields, &customFields); err != nil {
+			errors.ErrInvalidCustomFields.Response(c)
+			return nil, false
+		}
+		for name, value := range customFields {
+			if str, ok := value.(string); ok {
+				customFields[name] = utils.SanitizeInput(str)
+			}
 		}
 	}
 
@@ -285,6 +475,13 @@ func (h *BugHandler) CreateBug(c *gin.Context) {
 		}
 	}
 
+	var environmentJSON datatypes.JSON
+	if environment := environmentInfo(c, req.EnvironmentInfo); !environment.IsEmpty() {
+		if encoded, err := json.Marshal(environment); err == nil {
+			environmentJSON = datatypes.JSON(encoded)
+		}
+	}
+
 	// Get current user ID if authenticated
 	var reporterID *uuid.UUID
 	if isAuthenticated {
@@ -305,45 +502,59 @@ func (h *BugHandler) CreateBug(c *gin.Context) {
 	application, err := h.findOrCreateApplication(tx, sanitizedAppName, req.ApplicationURL)
 	if err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "APPLICATION_ERROR",
-				"message":   "Failed to process application",
-				"timestamp": time.Now().UTC(),
-			},
-		})
-		return
+		errors.ErrApplicationError.Response(c)
+		return nil, false
+	}
+
+	// Archived applications no longer accept bug reports
+	if application.IsArchived {
+		tx.Rollback()
+		errors.ErrApplicationArchived.WithMessage(fmt.Sprintf("Application '%s' has been archived and no longer accepts bug reports", application.Name)).Response(c)
+		return nil, false
 	}
 
 	// Create company if application doesn't have one
 	if application.CompanyID == nil {
-		companyHandler := NewCompanyHandler(h.db)
+		companyHandler := NewCompanyHandler(h.db, nil)
 		company, err := companyHandler.findOrCreateCompanyFromApplication(tx, sanitizedAppName, req.ApplicationURL)
 		if err != nil {
 			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": gin.H{
-					"code":      "COMPANY_ERROR",
-					"message":   "Failed to process company",
-					"timestamp": time.Now().UTC(),
-				},
-			})
-			return
+			errors.ErrCompanyError.Response(c)
+			return nil, false
 		}
 
 		// Associate application with company
 		application.CompanyID = &company.ID
 		if err := tx.Save(application).Error; err != nil {
 			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": gin.H{
-					"code":      "APPLICATION_UPDATE_ERROR",
-					"message":   "Failed to associate application with company",
-					"timestamp": time.Now().UTC(),
-				},
-			})
-			return
+			errors.ErrApplicationUpdateError.Response(c)
+			return nil, false
+		}
+	}
+
+	// Validate custom fields against the application's schema if one is defined
+	var fieldSchema models.CustomFieldSchema
+	if err := tx.Where("application_id = ?", application.ID).First(&fieldSchema).Error; err == nil {
+		if err := fieldSchema.Validate(customFields); err != nil {
+			tx.Rollback()
+			errors.ErrCustomFieldsInvalid.WithDetails(err.Error()).Response(c)
+			return nil, false
+		}
+	} else if err != gorm.ErrRecordNotFound {
+		tx.Rollback()
+		errors.ErrSchemaLookupFailed.Response(c)
+		return nil, false
+	}
+
+	var customFieldsJSON datatypes.JSON
+	if customFields != nil {
+		encoded, err := json.Marshal(customFields)
+		if err != nil {
+			tx.Rollback()
+			errors.ErrCustomFieldsEncodingFailed.Response(c)
+			return nil, false
 		}
+		customFieldsJSON = datatypes.JSON(encoded)
 	}
 
 	// Create bug report
@@ -357,88 +568,240 @@ func (h *BugHandler) CreateBug(c *gin.Context) {
 		DeviceType:      sanitizedDevice,
 		AppVersion:      sanitizedAppVersion,
 		BrowserVersion:  sanitizedBrowser,
+		EnvironmentInfo: environmentJSON,
+		CustomFields:    customFieldsJSON,
 		ApplicationID:   application.ID,
 		ReporterID:      reporterID,
 		VoteCount:       0,
 		CommentCount:    0,
 	}
+	h.locateBug(c, &bugReport)
+
+	// Anonymous submissions that fill in the hidden honeypot field are bots. They are
+	// accepted as normal so the bot cannot tell, but kept out of public listings.
+	if !isAuthenticated && req.Website != "" {
+		bugReport.IsSpam = true
+		bugReport.SpamScore = 1
+	}
+
+	// Suspected duplicates of unresolved bugs in the same application are suggested to
+	// the submitter with 200 instead of creating the bug, unless they confirm with
+	// force_create, which flags the bug for review. The check is advisory, so a failed
+	// check is rolled back and the bug created.
+	if !bugReport.IsSpam {
+		tx.SavePoint("duplicate_check")
+		similarBugs, err := h.FindSimilarBugs(tx, sanitizedTitle, sanitizedDescription, application.ID, duplicateSimilarityThreshold)
+		if err != nil {
+			tx.RollbackTo("duplicate_check")
+			logger.FromContext(c.Request.Context()).Error("Failed to check for duplicate bugs", err, logger.Fields{"application_id": application.ID.String()})
+		} else if len(similarBugs) > 0 {
+			if !req.ForceCreate {
+				tx.Rollback()
+				respondDuplicates(PossibleDuplicateResponse{
+					Code:                PossibleDuplicateCode,
+					Message:             "Similar bugs are already open for this application. Resubmit with force_create to report it anyway",
+					SuggestedDuplicates: similarBugs,
+				})
+				return nil, false
+			}
+
+			bugReport.DuplicateCheckSkipped = true
+			logger.FromContext(c.Request.Context()).Info("Bug submitted despite suspected duplicates", logger.Fields{
+				"application_id": application.ID.String(),
+				"similar_bugs":   len(similarBugs),
+			})
+		}
+	}
 
 	// Auto-assign to company if application has one
 	if application.CompanyID != nil {
 		bugReport.AssignedCompanyID = application.CompanyID
+
+		// Assign to a team member if one of the company's rules matches
+		assigneeID, err := h.matchAssignmentRule(tx, *application.CompanyID, &bugReport)
+		if err != nil {
+			tx.Rollback()
+			errors.ErrAssignmentRulesFailed.Response(c)
+			return nil, false
+		}
+		bugReport.AssignedMemberID = assigneeID
 	}
 
 	if err := tx.Create(&bugReport).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "CREATE_FAILED",
-				"message":   "Failed to create bug report",
-				"timestamp": time.Now().UTC(),
-			},
-		})
-		return
+		errors.ErrCreateFailed.WithMessage("Failed to create bug report").Response(c)
+		return nil, false
+	}
+
+	// Queue the webhook notification in the same transaction so it survives a restart.
+	// Spam is not announced.
+	if !bugReport.IsSpam {
+		if err := models.EnqueueOutboxEvent(tx, models.OutboxEventBugCreated, gin.H{
+			"bug_id":              bugReport.ID,
+			"title":               bugReport.Title,
+			"priority":            bugReport.Priority,
+			"application_id":      bugReport.ApplicationID,
+			"assigned_company_id": bugReport.AssignedCompanyID,
+			"created_at":          bugReport.CreatedAt,
+		}); err != nil {
+			tx.Rollback()
+			errors.ErrOutboxEnqueueFailed.WithMessage("Failed to queue bug report notification").Response(c)
+			return nil, false
+		}
 	}
 
 	// Update user's last active timestamp if authenticated
 	if reporterID != nil {
 		if err := tx.Model(&models.User{}).Where("id = ?", *reporterID).Update("last_active_at", time.Now()).Error; err != nil {
 			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": gin.H{
-					"code":      "ACTIVITY_UPDATE_FAILED",
-					"message":   "Failed to update user activity",
-					"timestamp": time.Now().UTC(),
-				},
-			})
-			return
+			errors.ErrActivityUpdateFailed.Response(c)
+			return nil, false
+		}
+
+		// Reporters watch their own bugs until they unsubscribe
+		if err := tx.Create(&models.BugSubscription{BugID: bugReport.ID, UserID: *reporterID}).Error; err != nil {
+			tx.Rollback()
+			errors.ErrSubscriptionFailed.WithMessage("Failed to subscribe reporter to bug report").Response(c)
+			return nil, false
 		}
 	}
 
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "COMMIT_FAILED",
-				"message":   "Failed to save bug report",
-				"timestamp": time.Now().UTC(),
-			},
-		})
-		return
+		errors.ErrCommitFailed.WithMessage("Failed to save bug report").Response(c)
+		return nil, false
+	}
+	metrics.BugsCreatedTotal.Inc()
+
+	if anonymousEmail != "" {
+		h.countAnonymousEmailSubmission(c, anonymousEmail)
 	}
 
 	// Invalidate bug list caches since we added a new bug
 	ctx := c.Request.Context()
 	if err := h.cache.DeletePattern(ctx, cache.BugListCachePrefix+"*"); err != nil {
 		// Log cache error but don't fail the request
-		fmt.Printf("Failed to invalidate bug list cache: %v\n", err)
+		logger.FromContext(ctx).Error("Failed to invalidate bug list cache", err)
+	}
+	if reporterID != nil {
+		if err := h.cache.InvalidateUserBugList(ctx, reporterID.String()); err != nil {
+			// Log cache error but don't fail the request
+			logger.FromContext(ctx).Error("Failed to invalidate user bug list cache", err, logger.Fields{"user_id": reporterID.String()})
+		}
 	}
 
 	// Load the created bug with relationships
 	var createdBug models.BugReport
 	if err := h.db.Preload("Application").Preload("Reporter").Preload("AssignedCompany").
 		First(&createdBug, bugReport.ID).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "LOAD_FAILED",
-				"message":   "Bug created but failed to load details",
-				"timestamp": time.Now().UTC(),
-			},
-		})
-		return
+		errors.ErrLoadFailed.WithMessage("Bug created but failed to load details").Response(c)
+		return nil, false
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"message": "Bug report created successfully",
-		"bug":     createdBug,
-	})
+	// Spam is not announced
+	if !createdBug.IsSpam {
+		h.dispatchWebhook(ctx, createdBug, webhooks.Payload{Event: models.WebhookEventBugCreated})
+	}
+
+	return &createdBug, true
+}
+
+// environmentInfo returns the submitted environment details, with the OS, browser
+// and language detected from the request headers when they were not given. The OS
+// and browser versions are only detected along with their names, so a submitted
+// name is never paired with the version of another.
+func environmentInfo(c *gin.Context, submitted *EnvironmentInfoRequest) models.EnvironmentInfo {
+	var environment models.EnvironmentInfo
+	if submitted != nil {
+		sanitize := func(value string) string {
+			if value == "" {
+				return ""
+			}
+			sanitized, _ := utils.ValidateString(value, 1, 100)
+			return sanitized
+		}
+		environment = models.EnvironmentInfo{
+			OSName:           sanitize(submitted.OSName),
+			OSVersion:        sanitize(submitted.OSVersion),
+			BrowserName:      sanitize(submitted.BrowserName),
+			BrowserVersion:   sanitize(submitted.BrowserVersion),
+			ScreenResolution: sanitize(submitted.ScreenResolution),
+			Language:         sanitize(submitted.Language),
+			Timezone:         sanitize(submitted.Timezone),
+		}
+	}
+
+	detected := utils.ParseUserAgent(c.Request.UserAgent())
+	if environment.OSName == "" {
+		environment.OSName = detected.OSName
+		if environment.OSVersion == "" {
+			environment.OSVersion = detected.OSVersion
+		}
+	}
+	if environment.BrowserName == "" {
+		environment.BrowserName = detected.BrowserName
+		if environment.BrowserVersion == "" {
+			environment.BrowserVersion = detected.BrowserVersion
+		}
+	}
+	if environment.Language == "" {
+		if language := utils.PrimaryLanguage(c.GetHeader("Accept-Language")); len(language) <= 100 {
+			environment.Language = language
+		}
+	}
+
+	// Recognised systems are stored under one name, e.g. "Mac OS X" as macOS, so
+	// listings can filter by os
+	environment.OSName = models.NormalizeOSName(environment.OSName)
+
+	return environment
+}
+
+// matchAssignmentRule returns the assignee of the first company assignment rule,
+// in priority_order, that matches the bug. It returns nil if no rule matches.
+func (h *BugHandler) matchAssignmentRule(tx *gorm.DB, companyID uuid.UUID, bug *models.BugReport) (*uuid.UUID, error) {
+	var rules []models.BugAssignmentRule
+	if err := tx.Where("company_id = ? AND application_id = ?", companyID, bug.ApplicationID).
+		Order("priority_order ASC, created_at ASC").
+		Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	for _, rule := range rules {
+		if rule.Matches(bug.ApplicationID, bug.Priority, bug.Tags) {
+			return rule.AssigneeUserID, nil
+		}
+	}
+
+	return nil, nil
 }
 
-// findOrCreateApplication finds an existing application or creates a new one
+// findOrCreateApplication finds an existing application or creates a new one.
+// Applications are matched by canonical URL first, so that variants of the same
+// URL resolve to one application, then by name, and then by exact URL for
+// applications that have no canonical URL.
 func (h *BugHandler) findOrCreateApplication(tx *gorm.DB, name string, url *string) (*models.Application, error) {
 	var application models.Application
 
-	// First try to find by exact name match
+	var canonicalURL *string
+	if url != nil && *url != "" {
+		if normalized, err := utils.NormalizeURL(*url); err == nil {
+			canonicalURL = &normalized
+		}
+	}
+
+	// First try to find by canonical URL
+	if canonicalURL != nil {
+		err := tx.Where("canonical_url = ?", *canonicalURL).First(&application).Error
+		if err == nil {
+			return &application, nil
+		}
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+	}
+
+	// Then try to find by exact name match
 	err := tx.Where("LOWER(name) = LOWER(?)", name).First(&application).Error
 	if err == nil {
 		return &application, nil
@@ -448,9 +811,10 @@ func (h *BugHandler) findOrCreateApplication(tx *gorm.DB, name string, url *stri
 		return nil, err
 	}
 
-	// If URL is provided, try to find by URL
+	// Applications created before canonical URLs were stored only match their
+	// URL exactly
 	if url != nil && *url != "" {
-		err = tx.Where("url = ?", *url).First(&application).Error
+		err = tx.Where("canonical_url IS NULL AND url = ?", *url).First(&application).Error
 		if err == nil {
 			return &application, nil
 		}
@@ -461,8 +825,9 @@ func (h *BugHandler) findOrCreateApplication(tx *gorm.DB, name string, url *stri
 
 	// Create new application
 	application = models.Application{
-		Name: name,
-		URL:  url,
+		Name:         name,
+		URL:          url,
+		CanonicalURL: canonicalURL,
 	}
 
 	if err := tx.Create(&application).Error; err != nil {
@@ -472,52 +837,416 @@ func (h *BugHandler) findOrCreateApplication(tx *gorm.DB, name string, url *stri
 	return &application, nil
 }
 
+// customFieldNamePattern restricts custom field filter names to safe identifiers
+var customFieldNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_]{1,64}$`)
+
+// customFieldCondition builds the WHERE condition for a custom field filter.
+// The name must already match customFieldNamePattern.
+func customFieldCondition(name string) string {
+	return fmt.Sprintf("bug_reports.custom_fields->>'%s' = ?", name)
+}
+
 // ListBugsRequest represents query parameters for listing bugs
 type ListBugsRequest struct {
-	Page        int    `form:"page,default=1"`
-	Limit       int    `form:"limit,default=20"`
-	Search      string `form:"search"`
-	Status      string `form:"status"`
-	Priority    string `form:"priority"`
-	Tags        string `form:"tags"`
-	Application string `form:"application"`
-	Company     string `form:"company"`
-	Sort        string `form:"sort,default=recent"`
+	Page            int    `form:"page,default=1"`
+	Limit           int    `form:"limit,default=20"`
+	Search          string `form:"search"`
+	Status          string `form:"status"`
+	Priority        string `form:"priority"`
+	Tags            string `form:"tags"`
+	Application     string `form:"application"`
+	ApplicationID   string `form:"application_id"`
+	Company         string `form:"company"`
+	OS              string `form:"os"`
+	Sort            string `form:"sort,default=recent"`
+	HideBlocked     bool   `form:"hide_blocked"`
+	BoostByPriority bool   `form:"boost_by_priority,default=true"`
+
+	// Cursor pages through the recent and oldest sorts from a next_cursor or
+	// prev_cursor instead of by page number. Direction is "after" for the next
+	// page and "before" for the previous one.
+	Cursor    string `form:"cursor"`
+	Direction string `form:"direction,default=after"`
+}
+
+// BugQueryOptions are the filters shared by bug listings
+type BugQueryOptions struct {
+	Status             string
+	Priority           string
+	Tags               string // comma-separated, bugs must have all of them
+	Application        string // matched against the application name
+	ApplicationID      *uuid.UUID
+	Company            string // matched against the assigned company name
+	CompanyID          *uuid.UUID
+	AssigneeID         *uuid.UUID
+	SLAStatus          string
+	OS                 string // one of linux, windows, macos, ios, android
+	CustomFields       map[string]string
+	HiddenReporterIDs  []uuid.UUID
+	Search             string
+	SearchLanguage     string // text search configuration, models.DefaultSearchLanguage when empty
+	SpamScoreThreshold float64
+	CreatedAfter       *time.Time
+}
+
+// buildBugQuery returns a bug report query with the listing joins and filters applied.
+// Callers add preloads, ordering and pagination.
+func buildBugQuery(db *gorm.DB, opts BugQueryOptions) *gorm.DB {
+	query := db.Model(&models.BugReport{}).
+		Joins("LEFT JOIN applications ON applications.id = bug_reports.application_id").
+		Joins("LEFT JOIN companies ON companies.id = bug_reports.assigned_company_id")
+
+	// Exclude spam
+	query = query.Where("bug_reports.is_spam = ? AND bug_reports.spam_score < ?", false, opts.SpamScoreThreshold)
+
+	if opts.Status != "" && models.IsValidStatus(opts.Status) {
+		query = query.Where("bug_reports.status = ?", opts.Status)
+	}
+
+	if opts.Priority != "" && models.IsValidPriority(opts.Priority) {
+		query = query.Where("bug_reports.priority = ?", opts.Priority)
+	}
+
+	if opts.Tags != "" {
+		for _, tag := range strings.Split(opts.Tags, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag != "" {
+				query = query.Where("? = ANY(bug_reports.tags)", tag)
+			}
+		}
+	}
+
+	if opts.Application != "" {
+		query = query.Where("LOWER(applications.name) LIKE LOWER(?)", "%"+opts.Application+"%")
+	}
+
+	if opts.ApplicationID != nil {
+		query = query.Where("bug_reports.application_id = ?", *opts.ApplicationID)
+	}
+
+	if opts.CreatedAfter != nil {
+		query = query.Where("bug_reports.created_at > ?", *opts.CreatedAfter)
+	}
+
+	if opts.Company != "" {
+		query = query.Where("LOWER(companies.name) LIKE LOWER(?)", "%"+opts.Company+"%")
+	}
+
+	if opts.CompanyID != nil {
+		query = query.Where("bug_reports.assigned_company_id = ?", *opts.CompanyID)
+	}
+
+	if opts.AssigneeID != nil {
+		query = query.Where("bug_reports.assigned_member_id = ?", *opts.AssigneeID)
+	}
+
+	if opts.SLAStatus != "" && models.IsValidSLAStatus(opts.SLAStatus) {
+		condition, args := slaCondition(opts.SLAStatus, time.Now())
+		query = query.Where(condition, args...)
+	}
+
+	if osName, ok := models.OSNameForFilter(opts.OS); ok {
+		query = query.Where("bug_reports.environment_info->>'os_name' = ?", osName)
+	}
+
+	for name, value := range opts.CustomFields {
+		query = query.Where(customFieldCondition(name), value)
+	}
+
+	if len(opts.HiddenReporterIDs) > 0 {
+		query = query.Where("(bug_reports.reporter_id IS NULL OR bug_reports.reporter_id NOT IN ?)", opts.HiddenReporterIDs)
+	}
+
+	// Use PostgreSQL full-text search across bug content and application name
+	if searchTerm := strings.TrimSpace(opts.Search); searchTerm != "" {
+		language := searchLanguage(opts.SearchLanguage)
+		if opts.ApplicationID != nil && language == models.DefaultSearchLanguage {
+			// Every result shares the application, so its name cannot narrow them.
+			// Matching the stored vector alongside the application_id filter lets
+			// the planner use idx_bug_reports_application_search.
+			query = query.Where("bug_reports.search_vector @@ plainto_tsquery(?, ?)", language, searchTerm)
+		} else {
+			query = query.Where(
+				"to_tsvector(?, bug_reports.title || ' ' || bug_reports.description || ' ' || COALESCE(applications.name, '')) @@ plainto_tsquery(?, ?)",
+				language, language, searchTerm,
+			)
+		}
+	}
+
+	return query
+}
+
+// searchLanguage returns the text search configuration to search in, defaulting to
+// models.DefaultSearchLanguage
+func searchLanguage(language string) string {
+	if language == "" {
+		return models.DefaultSearchLanguage
+	}
+	return language
+}
+
+// searchRank is the full-text search relevance of a bug to the search term, taking
+// the search language twice and then the term
+const searchRank = "ts_rank(to_tsvector(?, bug_reports.title || ' ' || bug_reports.description || ' ' || COALESCE(applications.name, '')), plainto_tsquery(?, ?))"
+
+// orderBySearchRank orders search results by relevance in the given language, then
+// recency. With boostByPriority the relevance is multiplied by the priority weight,
+// so a critical bug ranks above a low priority one unless that is over four times
+// as relevant.
+func orderBySearchRank(query *gorm.DB, searchTerm, language string, boostByPriority bool) *gorm.DB {
+	language = searchLanguage(language)
+	query = query.Select("bug_reports.*, "+searchRank+" as relevance_rank", language, language, searchTerm)
+	if !boostByPriority {
+		return query.Order("relevance_rank DESC").Order("bug_reports.created_at DESC")
+	}
+
+	// An ORDER BY expression replaces rather than joins other ordering, so it
+	// includes the recency tiebreak
+	return query.Order(clause.OrderBy{Expression: clause.Expr{
+		SQL:  "(" + searchRank + " * " + priorityWeight + ") DESC, bug_reports.created_at DESC",
+		Vars: []interface{}{language, language, searchTerm},
+	}})
+}
+
+// slaCondition returns a SQL condition matching bugs with the given SLA status at now,
+// mirroring models.BugReport.SLAStatus
+func slaCondition(status string, now time.Time) (string, []interface{}) {
+	var breached, atRisk []string
+	var breachedArgs, atRiskArgs []interface{}
+	for _, priority := range []string{models.BugPriorityCritical, models.BugPriorityHigh, models.BugPriorityMedium, models.BugPriorityLow} {
+		target := models.SLATargets[priority]
+		warningStart := time.Duration(float64(target) * (1 - models.SLAWarningFraction))
+
+		breached = append(breached, "(bug_reports.priority = ? AND bug_reports.created_at <= ?)")
+		breachedArgs = append(breachedArgs, priority, now.Add(-target))
+		atRisk = append(atRisk, "(bug_reports.priority = ? AND bug_reports.created_at < ?)")
+		atRiskArgs = append(atRiskArgs, priority, now.Add(-warningStart))
+	}
+
+	unresolved := "bug_reports.status NOT IN (?, ?)"
+	unresolvedArgs := []interface{}{models.BugStatusFixed, models.BugStatusWontFix}
+	breachedCondition := "(" + strings.Join(breached, " OR ") + ")"
+	atRiskCondition := "(" + strings.Join(atRisk, " OR ") + ")"
+
+	switch status {
+	case models.SLAStatusBreached:
+		return unresolved + " AND " + breachedCondition, append(unresolvedArgs, breachedArgs...)
+	case models.SLAStatusWarning:
+		args := append(unresolvedArgs, atRiskArgs...)
+		return unresolved + " AND " + atRiskCondition + " AND NOT " + breachedCondition, append(args, breachedArgs...)
+	default:
+		return "NOT (" + unresolved + " AND " + atRiskCondition + ")", append(unresolvedArgs, atRiskArgs...)
+	}
+}
+
+// bugListFilters returns the listing filters requested by req. Searches limited to
+// one application use its search language. It writes the error response and returns
+// false when a filter is invalid.
+func bugListFilters(c *gin.Context, db *gorm.DB, req ListBugsRequest, spamScoreThreshold float64) (BugQueryOptions, bool) {
+	// Custom field filters are passed as custom_fields[field_name]=value
+	customFieldFilters := c.QueryMap("custom_fields")
+	for name := range customFieldFilters {
+		if !customFieldNamePattern.MatchString(name) {
+			errors.ErrInvalidCustomFieldFilter.WithMessage(fmt.Sprintf("Invalid custom field name: %s", name)).Response(c)
+			return BugQueryOptions{}, false
+		}
+	}
+
+	var applicationID *uuid.UUID
+	language := models.DefaultSearchLanguage
+	if req.ApplicationID != "" {
+		id, err := uuid.Parse(req.ApplicationID)
+		if err != nil {
+			errors.ErrInvalidID.WithMessage("Invalid application ID format").Response(c)
+			return BugQueryOptions{}, false
+		}
+		applicationID = &id
+
+		if language, err = applicationSearchLanguage(db, id); err != nil {
+			errors.ErrQueryFailed.WithMessage("Failed to fetch application").Response(c)
+			return BugQueryOptions{}, false
+		}
+	}
+
+	return BugQueryOptions{
+		Status:             req.Status,
+		Priority:           req.Priority,
+		Tags:               req.Tags,
+		Application:        req.Application,
+		ApplicationID:      applicationID,
+		Company:            req.Company,
+		OS:                 req.OS,
+		CustomFields:       customFieldFilters,
+		Search:             req.Search,
+		SearchLanguage:     language,
+		SpamScoreThreshold: spamScoreThreshold,
+	}, true
+}
+
+// applicationSearchLanguage returns the text search configuration of an application's
+// bugs, models.DefaultSearchLanguage when the application does not exist
+func applicationSearchLanguage(db *gorm.DB, applicationID uuid.UUID) (string, error) {
+	var application models.Application
+	if err := db.Select("search_language").First(&application, "id = ?", applicationID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return models.DefaultSearchLanguage, nil
+		}
+		return "", err
+	}
+	return application.SearchLanguage, nil
+}
+
+// orderBugList applies a listing sort to query. Searches sorted by recency are
+// ordered by relevance instead.
+func orderBugList(query *gorm.DB, sort, searchTerm, language string, boostByPriority bool) *gorm.DB {
+	if searchTerm != "" && (sort == "recent" || sort == "") {
+		// For search results, prioritize relevance then recency
+		return orderBySearchRank(query, searchTerm, language, boostByPriority)
+	}
+
+	switch sort {
+	case "recent":
+		return orderByBugCursor(query, nil, false, false)
+	case "popular":
+		return query.Order("bug_reports.weighted_vote_count DESC").Order("bug_reports.created_at DESC")
+	case "trending":
+		return orderByTrending(query)
+	case "oldest":
+		return orderByBugCursor(query, nil, true, false)
+	default:
+		return query.Order("bug_reports.created_at DESC")
+	}
+}
+
+// BugListQuery is a page of bugs to find with FindBugs
+type BugListQuery struct {
+	BugQueryOptions
+
+	Sort            string // recent, popular, trending or oldest
+	BoostByPriority bool
+	Page            int
+	Limit           int
+}
+
+// BugListPage is a page of bugs returned by FindBugs
+type BugListPage struct {
+	Bugs  []models.BugReport
+	Total int64
+	Page  int
+	Limit int
+}
+
+// FindBugs returns the page of bugs matching q the way ListBugs finds them, without
+// their relationships loaded or the list cache. The page and limit are clamped to
+// the listing's pagination settings.
+func (h *BugHandler) FindBugs(ctx context.Context, q BugListQuery) (BugListPage, error) {
+	page := BugListPage{Page: q.Page, Limit: h.pagination.Limit(q.Limit)}
+	if page.Page <= 0 {
+		page.Page = 1
+	}
+
+	opts := q.BugQueryOptions
+	opts.SpamScoreThreshold = h.spamScoreThreshold
+	if opts.ApplicationID != nil && opts.SearchLanguage == "" {
+		language, err := applicationSearchLanguage(h.db.WithContext(ctx), *opts.ApplicationID)
+		if err != nil {
+			return BugListPage{}, err
+		}
+		opts.SearchLanguage = language
+	}
+	if q.Sort == "trending" {
+		opts.CreatedAfter = h.trending.windowStart(time.Now())
+	}
+
+	db := h.db.WithContext(ctx)
+	if err := buildBugQuery(db, opts).Count(&page.Total).Error; err != nil {
+		return BugListPage{}, err
+	}
+
+	query := orderBugList(buildBugQuery(db, opts), q.Sort, strings.TrimSpace(opts.Search), opts.SearchLanguage, q.BoostByPriority)
+	if err := query.Offset((page.Page - 1) * page.Limit).Limit(page.Limit).Find(&page.Bugs).Error; err != nil {
+		return BugListPage{}, err
+	}
+	return page, nil
 }
 
 // ListBugs handles bug listing with search, filtering, and pagination
+//
+// @Summary     List bug reports
+// @Description Lists bug reports with search, filters and page or cursor pagination. Clients preferring text/html get an HTML page when HTML rendering is enabled.
+// @Tags        bugs
+// @Produce     json
+// @Produce     html
+// @Param       query query ListBugsRequest false "Filters and pagination"
+// @Success     200 {object} object{bugs=[]models.BugReport,pagination=object{page=int,limit=int,total=int,total_pages=int,has_next=bool,has_prev=bool,next_cursor=string,prev_cursor=string},_links=object}
+// @Failure     400 {object} errors.ErrorResponse "Invalid filter or cursor"
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /bugs/ [get]
 func (h *BugHandler) ListBugs(c *gin.Context) {
 	var req ListBugsRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "VALIDATION_ERROR",
-				"message":   "Invalid query parameters",
-				"details":   err.Error(),
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrValidationError.WithMessage("Invalid query parameters").WithDetails(utils.FormatValidationErrors(err)).Response(c)
 		return
 	}
 
 	// Validate and set limits
-	if req.Limit <= 0 || req.Limit > 100 {
-		req.Limit = 20
-	}
+	req.Limit = h.pagination.Limit(req.Limit)
 	if req.Page <= 0 {
 		req.Page = 1
 	}
 
+	// Cursor pages follow creation order, so they can't be combined with a search
+	// or a sort by votes
+	var cursor *bugCursor
+	if req.Cursor != "" {
+		if strings.TrimSpace(req.Search) != "" || (req.Sort != "recent" && req.Sort != "oldest") {
+			errors.ErrInvalidCursor.WithMessage("Cursor pagination is only supported for the recent and oldest sorts without a search").Response(c)
+			return
+		}
+		if req.Direction != CursorDirectionAfter && req.Direction != CursorDirectionBefore {
+			errors.ErrInvalidCursor.WithMessage("Cursor direction must be after or before").Response(c)
+			return
+		}
+		decoded, err := decodeBugCursor(req.Cursor)
+		if err != nil {
+			errors.ErrInvalidCursor.Response(c)
+			return
+		}
+		cursor = &decoded
+	}
+
+	queryOptions, ok := bugListFilters(c, h.db, req, h.spamScoreThreshold)
+	if !ok {
+		return
+	}
+	customFieldFilters := queryOptions.CustomFields
+	language := queryOptions.SearchLanguage
+
 	ctx := c.Request.Context()
 
+	// Bugs from users the current user has a block with can be hidden on request
+	var hiddenReporterIDs []uuid.UUID
+	if req.HideBlocked {
+		blocks, ok, err := h.currentUserBlockList(c)
+		if err != nil {
+			errors.ErrQueryFailed.WithMessage("Failed to check user blocks").Response(c)
+			return
+		}
+		if ok {
+			hiddenReporterIDs = blocks.hiddenUserIDs()
+		}
+	}
+
 	// Generate cache key based on request parameters
 	cacheKey := cache.GenerateCacheKey(
 		req.Page, req.Limit, req.Search, req.Status, req.Priority,
-		req.Tags, req.Application, req.Company, req.Sort,
+		req.Tags, req.Application, req.ApplicationID, req.Company, req.OS, req.Sort, customFieldFilters, req.BoostByPriority,
 	)
 
-	// Try to get from cache first (only for first page of common queries)
-	if req.Page == 1 && req.Search == "" {
+	// Try to get from cache first (only for first page of common queries). Lists
+	// filtered by a user's blocks are personal and never cached.
+	cacheable := req.Page == 1 && cursor == nil && req.Search == "" && len(hiddenReporterIDs) == 0
+	if cacheable {
 		type CachedResponse struct {
 			Bugs       []models.BugReport     `json:"bugs"`
 			Pagination map[string]interface{} `json:"pagination"`
@@ -525,148 +1254,58 @@ func (h *BugHandler) ListBugs(c *gin.Context) {
 
 		var cachedResp CachedResponse
 		if err := h.cache.GetBugList(ctx, cacheKey, &cachedResp); err == nil {
-			c.JSON(http.StatusOK, gin.H{
-				"bugs":       cachedResp.Bugs,
-				"pagination": cachedResp.Pagination,
-			})
+			h.respondBugList(c, req, cachedResp.Bugs, cachedResp.Pagination)
 			return
 		}
 	}
 
-	// Build query with necessary joins
-	query := h.db.Model(&models.BugReport{}).
-		Joins("LEFT JOIN applications ON applications.id = bug_reports.application_id").
-		Joins("LEFT JOIN companies ON companies.id = bug_reports.assigned_company_id").
-		Preload("Application").
-		Preload("Reporter").
-		Preload("AssignedCompany")
+	queryOptions.HiddenReporterIDs = hiddenReporterIDs
 
-	// Apply filters
-	if req.Status != "" && models.IsValidStatus(req.Status) {
-		query = query.Where("bug_reports.status = ?", req.Status)
+	// Trending lists only recent bugs, and the count covers the same bugs
+	now := time.Now()
+	if req.Sort == "trending" {
+		queryOptions.CreatedAfter = h.trending.windowStart(now)
 	}
 
-	if req.Priority != "" && models.IsValidPriority(req.Priority) {
-		query = query.Where("bug_reports.priority = ?", req.Priority)
-	}
-
-	if req.Tags != "" {
-		tags := strings.Split(req.Tags, ",")
-		for _, tag := range tags {
-			tag = strings.TrimSpace(tag)
-			if tag != "" {
-				query = query.Where("? = ANY(bug_reports.tags)", tag)
-			}
-		}
-	}
-
-	if req.Application != "" {
-		query = query.Where("LOWER(applications.name) LIKE LOWER(?)", "%"+req.Application+"%")
-	}
-
-	if req.Company != "" {
-		query = query.Where("LOWER(companies.name) LIKE LOWER(?)", "%"+req.Company+"%")
-	}
+	query := buildBugQuery(h.db, queryOptions).
+		Preload("Application").
+		Preload("Reporter").
+		Preload("AssignedCompany")
 
-	// Apply search using PostgreSQL full-text search
-	var hasSearch bool
-	if req.Search != "" {
-		searchTerm := strings.TrimSpace(req.Search)
-		if len(searchTerm) > 0 {
-			hasSearch = true
-			// Use PostgreSQL full-text search across bug content and application name
-			query = query.Where(
-				"to_tsvector('english', bug_reports.title || ' ' || bug_reports.description || ' ' || COALESCE(applications.name, '')) @@ plainto_tsquery('english', ?)",
-				searchTerm,
-			)
-		}
+	if cursor != nil {
+		h.listBugsByCursor(c, req, query, cursor)
+		return
 	}
 
 	// Apply sorting
-	if hasSearch && (req.Sort == "recent" || req.Sort == "") {
-		// For search results, prioritize relevance then recency
-		searchTerm := strings.TrimSpace(req.Search)
-		query = query.Select("bug_reports.*, ts_rank(to_tsvector('english', bug_reports.title || ' ' || bug_reports.description || ' ' || COALESCE(applications.name, '')), plainto_tsquery('english', ?)) as relevance_rank", searchTerm).
-			Order("relevance_rank DESC").
-			Order("bug_reports.created_at DESC")
-	} else {
-		switch req.Sort {
-		case "recent":
-			query = query.Order("bug_reports.created_at DESC")
-		case "popular":
-			query = query.Order("bug_reports.vote_count DESC").Order("bug_reports.created_at DESC")
-		case "trending":
-			// Trending: high vote count in recent time
-			query = query.Where("bug_reports.created_at > ?", time.Now().AddDate(0, 0, -30)).
-				Order("bug_reports.vote_count DESC").Order("bug_reports.created_at DESC")
-		case "oldest":
-			query = query.Order("bug_reports.created_at ASC")
-		default:
-			query = query.Order("bug_reports.created_at DESC")
-		}
-	}
+	hasSearch := strings.TrimSpace(req.Search) != ""
+	query = orderBugList(query, req.Sort, strings.TrimSpace(req.Search), language, req.BoostByPriority)
 
-	// Get total count (need to select distinct bug_reports.id due to joins)
+	// Get total count with the same filters
 	var total int64
-	countQuery := h.db.Model(&models.BugReport{}).
-		Joins("LEFT JOIN applications ON applications.id = bug_reports.application_id").
-		Joins("LEFT JOIN companies ON companies.id = bug_reports.assigned_company_id")
-
-	// Apply the same filters to count query
-	if req.Status != "" && models.IsValidStatus(req.Status) {
-		countQuery = countQuery.Where("bug_reports.status = ?", req.Status)
-	}
-	if req.Priority != "" && models.IsValidPriority(req.Priority) {
-		countQuery = countQuery.Where("bug_reports.priority = ?", req.Priority)
-	}
-	if req.Tags != "" {
-		tags := strings.Split(req.Tags, ",")
-		for _, tag := range tags {
-			tag = strings.TrimSpace(tag)
-			if tag != "" {
-				countQuery = countQuery.Where("? = ANY(bug_reports.tags)", tag)
-			}
-		}
-	}
-	if req.Application != "" {
-		countQuery = countQuery.Where("LOWER(applications.name) LIKE LOWER(?)", "%"+req.Application+"%")
-	}
-	if req.Company != "" {
-		countQuery = countQuery.Where("LOWER(companies.name) LIKE LOWER(?)", "%"+req.Company+"%")
-	}
-	if hasSearch {
-		searchTerm := strings.TrimSpace(req.Search)
-		countQuery = countQuery.Where(
-			"to_tsvector('english', bug_reports.title || ' ' || bug_reports.description || ' ' || COALESCE(applications.name, '')) @@ plainto_tsquery('english', ?)",
-			searchTerm,
-		)
-	}
+	countQuery := buildBugQuery(h.db, queryOptions)
 
 	if err := countQuery.Count(&total).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "COUNT_FAILED",
-				"message":   "Failed to count bug reports",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrCountFailed.WithMessage("Failed to count bug reports").Response(c)
 		return
 	}
 
+	// Log each search once rather than once per page fetched
+	if hasSearch && req.Page == 1 {
+		h.logSearchQuery(c, req.Search, total)
+	}
+
 	// Apply pagination
 	offset := (req.Page - 1) * req.Limit
 	query = query.Offset(offset).Limit(req.Limit)
 
+	// Log the query plan when debugging slow listings
+	database.ExplainQuery(h.db, "ListBugs", query, &[]models.BugReport{})
+
 	// Execute query
 	var bugs []models.BugReport
 	if err := query.Find(&bugs).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "QUERY_FAILED",
-				"message":   "Failed to fetch bug reports",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrQueryFailed.WithMessage("Failed to fetch bug reports").Response(c)
 		return
 	}
 
@@ -684,111 +1323,272 @@ func (h *BugHandler) ListBugs(c *gin.Context) {
 		"has_prev":    hasPrev,
 	}
 
-	// Cache the result for first page of common queries
-	if req.Page == 1 && req.Search == "" {
-		type CachedResponse struct {
-			Bugs       []models.BugReport     `json:"bugs"`
-			Pagination map[string]interface{} `json:"pagination"`
+	// Pages in creation order can be continued with cursors
+	if !hasSearch && (req.Sort == "recent" || req.Sort == "oldest") {
+		setBugCursors(paginationInfo, bugs, hasNext, hasPrev)
+	}
+
+	// Cache the result for first page of common queries
+	if cacheable {
+		type CachedResponse struct {
+			Bugs       []models.BugReport     `json:"bugs"`
+			Pagination map[string]interface{} `json:"pagination"`
+		}
+
+		cachedResp := CachedResponse{
+			Bugs:       bugs,
+			Pagination: paginationInfo,
+		}
+
+		setBugList := h.cache.SetBugList
+		if req.Sort == "trending" {
+			setBugList = h.cache.SetTrendingBugList
+		}
+		if err := setBugList(ctx, cacheKey, cachedResp); err != nil {
+			// Log cache error but don't fail the request
+			logger.FromContext(ctx).Error("Failed to cache bug list", err, logger.Fields{"cache_key": cacheKey})
+		}
+	}
+
+	h.respondBugList(c, req, bugs, paginationInfo)
+}
+
+// GetBug handles retrieving a single bug report by ID
+//
+// @Summary     Get a bug report
+// @Description Returns a bug report, with the relationships named by include. Clients preferring text/html get an HTML page when HTML rendering is enabled.
+// @Tags        bugs
+// @Produce     json
+// @Produce     html
+// @Param       id path string true "Bug ID" format(uuid)
+// @Param       include query string false "Comma-separated relationships: application, reporter, company, comments, votes"
+// @Success     200 {object} object{bug=models.BugReport,_links=object,subscribed=bool} "subscribed is only returned to signed-in users"
+// @Failure     400 {object} errors.ErrorResponse "Invalid ID or include"
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /bugs/{id} [get]
+func (h *BugHandler) GetBug(c *gin.Context) {
+	bug, ok := h.loadBug(c)
+	if !ok {
+		return
+	}
+
+	links := bugLinks(c.Request.URL.Path)
+	links["tag_feed"] = tagFeedLinks(bug.Tags)
+
+	response := gin.H{
+		"bug":    bug,
+		"_links": links,
+	}
+
+	// Signed-in users see whether they watch the bug
+	if userIDStr, exists := middleware.GetCurrentUserID(c); exists {
+		if userUUID, err := uuid.Parse(userIDStr); err == nil {
+			subscribed, err := h.isSubscribedToBug(c.Request.Context(), bug.ID, userUUID)
+			if err != nil {
+				errors.ErrQueryFailed.WithMessage("Failed to check bug subscription").Response(c)
+				return
+			}
+			response["subscribed"] = subscribed
+		}
+	}
+
+	h.respondNegotiated(c, response, bugTemplate, func() interface{} {
+		return newBugPage(c.Request.URL.Path, bug)
+	})
+}
+
+// loadBug loads the bug named by the id parameter with the relationships asked for
+// by the include parameter, hiding comments from blocked users. Errors are written
+// to the response and reported by returning false.
+func (h *BugHandler) loadBug(c *gin.Context) (models.BugReport, bool) {
+	bugID := c.Param("id")
+
+	bugUUID, err := uuid.Parse(bugID)
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid bug ID format").Response(c)
+		return models.BugReport{}, false
+	}
+
+	includes, err := parseBugIncludes(c.Query("include"))
+	if err != nil {
+		errors.ErrInvalidInclude.WithDetails(err.Error()).Response(c)
+		return models.BugReport{}, false
+	}
+
+	ctx := c.Request.Context()
+
+	// The base bug comes from the cache, or from the database on a miss
+	bug, err := h.FindBug(ctx, bugUUID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrBugNotFound.Response(c)
+			return bug, false
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch bug report").Response(c)
+		return bug, false
+	}
+
+	// Load requested relationships, each cached separately
+	for _, include := range includes {
+		if err := h.loadBugInclude(ctx, &bug, include); err != nil {
+			errors.ErrQueryFailed.WithMessage(fmt.Sprintf("Failed to fetch bug %s", include)).Response(c)
+			return bug, false
+		}
+	}
+
+	// Attachments are counted on every request since uploads and deletions don't
+	// invalidate the cached bug
+	var attachmentCount int64
+	if err := h.db.Model(&models.FileAttachment{}).Where("bug_id = ?", bug.ID).Count(&attachmentCount).Error; err != nil {
+		errors.ErrCountFailed.WithMessage("Failed to count bug attachments").Response(c)
+		return bug, false
+	}
+	bug.AttachmentCount = &attachmentCount
+
+	// Hide comments between the current user and users they have a block with
+	if len(bug.Comments) > 0 {
+		blocks, ok, err := h.currentUserBlockList(c)
+		if err != nil {
+			errors.ErrQueryFailed.WithMessage("Failed to check user blocks").Response(c)
+			return bug, false
+		}
+		if ok {
+			bug.Comments = blocks.visibleComments(bug.Comments)
+		}
+	}
+
+	return bug, true
+}
+
+// bugIncludeOptions lists the relationships GetBug can load via the include parameter.
+// A bug can have many attachments, so they are listed by ListBugAttachments instead.
+var bugIncludeOptions = []string{"application", "reporter", "company", "comments", "votes"}
+
+// parseBugIncludes parses a comma-separated include parameter
+func parseBugIncludes(raw string) ([]string, error) {
+	var includes []string
+	seen := make(map[string]bool)
+
+	for _, include := range strings.Split(raw, ",") {
+		include = strings.ToLower(strings.TrimSpace(include))
+		if include == "" || seen[include] {
+			continue
 		}
 
-		cachedResp := CachedResponse{
-			Bugs:       bugs,
-			Pagination: paginationInfo,
+		valid := false
+		for _, option := range bugIncludeOptions {
+			if include == option {
+				valid = true
+				break
+			}
 		}
-
-		if err := h.cache.SetBugList(ctx, cacheKey, cachedResp); err != nil {
-			// Log cache error but don't fail the request
-			fmt.Printf("Failed to cache bug list %s: %v\n", cacheKey, err)
+		if !valid {
+			return nil, fmt.Errorf("unsupported include: %s", include)
 		}
+
+		seen[include] = true
+		includes = append(includes, include)
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"bugs":       bugs,
-		"pagination": paginationInfo,
-	})
+	return includes, nil
 }
 
-// GetBug handles retrieving a single bug report by ID
-func (h *BugHandler) GetBug(c *gin.Context) {
-	bugID := c.Param("id")
-
-	bugUUID, err := uuid.Parse(bugID)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_ID",
-				"message":   "Invalid bug ID format",
-				"timestamp": time.Now().UTC(),
-			},
-		})
-		return
+// bugLinks builds HAL-style links for a bug and its includable relationships
+func bugLinks(selfPath string) gin.H {
+	links := gin.H{
+		"self": gin.H{"href": selfPath},
+	}
+	for _, include := range bugIncludeOptions {
+		links[include] = gin.H{"href": selfPath + "?include=" + include}
 	}
+	links["attachments"] = gin.H{"href": selfPath + "/attachments"}
+	return links
+}
 
-	ctx := c.Request.Context()
-	var bug models.BugReport
+// loadBugInclude loads a single relationship onto the bug, using its own cache entry
+func (h *BugHandler) loadBugInclude(ctx context.Context, bug *models.BugReport, include string) error {
+	bugID := bug.ID.String()
 
-	// Try to get from cache first
-	err = h.cache.GetBug(ctx, bugID, &bug)
-	if err == nil {
-		c.JSON(http.StatusOK, gin.H{
-			"bug": bug,
+	switch include {
+	case "application":
+		return h.cachedBugRelation(ctx, bugID, include, &bug.Application, func() error {
+			return h.db.First(&bug.Application, "id = ?", bug.ApplicationID).Error
+		})
+	case "reporter":
+		if bug.ReporterID == nil {
+			return nil
+		}
+		return h.cachedBugRelation(ctx, bugID, include, &bug.Reporter, func() error {
+			return h.db.First(&bug.Reporter, "id = ?", *bug.ReporterID).Error
+		})
+	case "company":
+		if bug.AssignedCompanyID == nil {
+			return nil
+		}
+		return h.cachedBugRelation(ctx, bugID, include, &bug.AssignedCompany, func() error {
+			return h.db.First(&bug.AssignedCompany, "id = ?", *bug.AssignedCompanyID).Error
+		})
+	case "comments":
+		return h.cachedBugRelation(ctx, bugID, include, &bug.Comments, func() error {
+			// Deleted comments are included, without content, so replies keep their place
+			return preloadCommentReplies(h.db.Unscoped().Preload("User"), bugCommentReplyDepth).
+				Where("bug_id = ? AND parent_id IS NULL", bug.ID).
+				Order("created_at ASC").
+				Find(&bug.Comments).Error
+		})
+	case "votes":
+		return h.cachedBugRelation(ctx, bugID, include, &bug.Votes, func() error {
+			return h.db.Where("bug_id = ?", bug.ID).Find(&bug.Votes).Error
 		})
-		return
 	}
 
-	// Cache miss or error, fetch from database
-	if err := h.db.Preload("Application").
-		Preload("Reporter").
-		Preload("AssignedCompany").
-		Preload("Attachments").
-		Preload("Comments").
-		Preload("Comments.User").
-		First(&bug, bugUUID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": gin.H{
-					"code":      "BUG_NOT_FOUND",
-					"message":   "Bug report not found",
-					"timestamp": time.Now().UTC(),
-				},
-			})
-			return
-		}
+	return nil
+}
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "QUERY_FAILED",
-				"message":   "Failed to fetch bug report",
-				"timestamp": time.Now().UTC(),
-			},
-		})
-		return
+// cachedBugRelation fills dest from the relationship cache, falling back to load on a miss
+func (h *BugHandler) cachedBugRelation(ctx context.Context, bugID, relation string, dest interface{}, load func() error) error {
+	if err := h.cache.GetBugRelation(ctx, bugID, relation, dest); err == nil {
+		return nil
+	}
+
+	if err := load(); err != nil {
+		return err
 	}
 
-	// Cache the result for future requests
-	if err := h.cache.SetBug(ctx, bugID, bug); err != nil {
+	if err := h.cache.SetBugRelation(ctx, bugID, relation, dest); err != nil {
 		// Log cache error but don't fail the request
-		fmt.Printf("Failed to cache bug %s: %v\n", bugID, err)
+		logger.FromContext(ctx).Error("Failed to cache bug relation", err, logger.Fields{"bug_id": bugID, "relation": relation})
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"bug": bug,
-	})
+	return nil
 }
 
 // UploadBugAttachment handles file upload for bug reports
+//
+// @Summary     Upload an attachment
+// @Description Uploads an image or document of up to 10 MB to a bug report.
+// @Tags        bugs
+// @Accept      multipart/form-data
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Bug ID" format(uuid)
+// @Param       file formData file true "File to attach"
+// @Success     201 {object} object{message=string,attachment=models.FileAttachment}
+// @Failure     400 {object} errors.ErrorResponse "Invalid ID, missing file, file too large or unsupported type"
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     413 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /bugs/{id}/attachments [post]
 func (h *BugHandler) UploadBugAttachment(c *gin.Context) {
 	bugID := c.Param("id")
 
 	bugUUID, err := uuid.Parse(bugID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_ID",
-				"message":   "Invalid bug ID format",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInvalidID.WithMessage("Invalid bug ID format").Response(c)
 		return
 	}
 
@@ -796,89 +1596,51 @@ func (h *BugHandler) UploadBugAttachment(c *gin.Context) {
 	var bug models.BugReport
 	if err := h.db.First(&bug, bugUUID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": gin.H{
-					"code":      "BUG_NOT_FOUND",
-					"message":   "Bug report not found",
-					"timestamp": time.Now().UTC(),
-				},
-			})
+			errors.ErrBugNotFound.Response(c)
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "QUERY_FAILED",
-				"message":   "Failed to verify bug report",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrQueryFailed.WithMessage("Failed to verify bug report").Response(c)
 		return
 	}
 
 	// Check if user can upload to this bug (owner or admin)
+	var userUUID uuid.UUID
 	if userIDStr, exists := middleware.GetCurrentUserID(c); exists {
-		userUUID, _ := uuid.Parse(userIDStr)
+		userUUID, _ = uuid.Parse(userIDStr)
 		isAdmin := middleware.IsCurrentUserAdmin(c)
 
 		if !isAdmin && (bug.ReporterID == nil || *bug.ReporterID != userUUID) {
-			c.JSON(http.StatusForbidden, gin.H{
-				"error": gin.H{
-					"code":      "UPLOAD_FORBIDDEN",
-					"message":   "You can only upload files to your own bug reports",
-					"timestamp": time.Now().UTC(),
-				},
-			})
+			errors.ErrUploadForbidden.Response(c)
 			return
 		}
 	} else {
 		// Anonymous users can't upload files to existing bugs
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": gin.H{
-				"code":      "AUTH_REQUIRED",
-				"message":   "Authentication required for file uploads",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrAuthRequired.WithMessage("Authentication required for file uploads").Response(c)
 		return
 	}
 
 	// Get uploaded file
 	file, err := c.FormFile("file")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "NO_FILE",
-				"message":   "No file uploaded",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrNoFile.Response(c)
 		return
 	}
 
 	// Validate file size (max 10MB)
 	maxSize := int64(10 * 1024 * 1024) // 10MB
 	if file.Size > maxSize {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "FILE_TOO_LARGE",
-				"message":   "File size exceeds 10MB limit",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrFileTooLarge.Response(c)
 		return
 	}
 
 	// Open file to check content type
 	src, err := file.Open()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "FILE_READ_ERROR",
-				"message":   "Failed to read uploaded file",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrFileReadError.Response(c)
 		return
 	}
 	defer src.Close()
@@ -887,13 +1649,7 @@ func (h *BugHandler) UploadBugAttachment(c *gin.Context) {
 	buffer := make([]byte, 512)
 	_, err = src.Read(buffer)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "FILE_READ_ERROR",
-				"message":   "Failed to read file content",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrFileReadError.WithMessage("Failed to read file content").Response(c)
 		return
 	}
 
@@ -901,13 +1657,7 @@ func (h *BugHandler) UploadBugAttachment(c *gin.Context) {
 
 	// Validate file type using utility function
 	if !utils.ValidateFileType(file.Filename, contentType) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_FILE_TYPE",
-				"message":   "Only image files are allowed (JPEG, PNG, GIF, WebP)",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInvalidFileType.Response(c)
 		return
 	}
 
@@ -926,43 +1676,41 @@ func (h *BugHandler) UploadBugAttachment(c *gin.Context) {
 
 	uniqueFilename := fmt.Sprintf("%s_%d%s", bugUUID.String(), time.Now().Unix(), fileExt)
 
-	// For now, we'll store files locally in uploads directory
-	// In production, this should be replaced with cloud storage (S3, etc.)
-	uploadDir := "uploads/bugs"
-	filePath := fmt.Sprintf("%s/%s", uploadDir, uniqueFilename)
-
-	// Create upload directory if it doesn't exist
-	if err := c.SaveUploadedFile(file, filePath); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "SAVE_FAILED",
-				"message":   "Failed to save uploaded file",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+	// Upload from the start of the file, before the bytes read to detect its type
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		errors.ErrFileReadError.Response(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+	fileURL, err := h.storage.Upload(ctx, "bugs/"+uniqueFilename, src, file.Size, contentType)
+	if err != nil {
+		logger.FromContext(ctx).Error("Failed to store attachment", err, logger.Fields{"bug_id": bugID})
+		errors.ErrSaveFailed.Response(c)
 		return
 	}
 
 	// Create file attachment record
 	attachment := models.FileAttachment{
-		BugID:    bugUUID,
-		Filename: file.Filename,
-		FileURL:  filePath, // In production, this would be the full URL
-		FileSize: &[]int{int(file.Size)}[0],
-		MimeType: &contentType,
+		BugID:        bugUUID,
+		Filename:     file.Filename,
+		FileURL:      fileURL,
+		FileSize:     &[]int{int(file.Size)}[0],
+		MimeType:     &contentType,
+		UploadedByID: &userUUID,
 	}
 
 	if err := h.db.Create(&attachment).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "DB_ERROR",
-				"message":   "Failed to save file attachment record",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		// Remove the stored file so it isn't orphaned
+		if err := h.storage.Delete(ctx, attachment); err != nil {
+			logger.FromContext(ctx).Error("Failed to delete attachment file", err, logger.Fields{"file_url": fileURL})
+		}
+		errors.ErrDBError.Response(c)
 		return
 	}
 
+	attachment.PreviewURL = h.storage.GeneratePreviewURL(attachment)
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message":    "File uploaded successfully",
 		"attachment": attachment,
@@ -970,43 +1718,40 @@ func (h *BugHandler) UploadBugAttachment(c *gin.Context) {
 }
 
 // VoteBug handles voting on bug reports
+//
+// @Summary     Vote on a bug report
+// @Description Toggles the current user's vote. A new vote is weighted by the voter's reputation.
+// @Tags        bugs
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Bug ID" format(uuid)
+// @Success     200 {object} object{message=string,voted=bool} "Vote removed"
+// @Success     201 {object} object{message=string,voted=bool,vote_weight=number} "Vote added"
+// @Failure     400 {object} errors.ErrorResponse
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     429 {object} errors.ErrorResponse "Rate limited"
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /bugs/{id}/vote [post]
 func (h *BugHandler) VoteBug(c *gin.Context) {
 	bugID := c.Param("id")
 
 	bugUUID, err := uuid.Parse(bugID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_ID",
-				"message":   "Invalid bug ID format",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInvalidID.WithMessage("Invalid bug ID format").Response(c)
 		return
 	}
 
 	// Get current user ID (authentication required)
 	userIDStr, exists := middleware.GetCurrentUserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": gin.H{
-				"code":      "AUTH_REQUIRED",
-				"message":   "Authentication required for voting",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrAuthRequired.WithMessage("Authentication required for voting").Response(c)
 		return
 	}
 
 	userUUID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_USER",
-				"message":   "Invalid user ID",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInvalidUser.Response(c)
 		return
 	}
 
@@ -1014,23 +1759,11 @@ func (h *BugHandler) VoteBug(c *gin.Context) {
 	var bug models.BugReport
 	if err := h.db.First(&bug, bugUUID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": gin.H{
-					"code":      "BUG_NOT_FOUND",
-					"message":   "Bug report not found",
-					"timestamp": time.Now().UTC(),
-				},
-			})
+			errors.ErrBugNotFound.Response(c)
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "QUERY_FAILED",
-				"message":   "Failed to verify bug report",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrQueryFailed.WithMessage("Failed to verify bug report").Response(c)
 		return
 	}
 
@@ -1049,58 +1782,41 @@ func (h *BugHandler) VoteBug(c *gin.Context) {
 
 		if err := tx.Delete(&existingVote).Error; err != nil {
 			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": gin.H{
-					"code":      "VOTE_REMOVE_FAILED",
-					"message":   "Failed to remove vote",
-					"timestamp": time.Now().UTC(),
-				},
-			})
+			errors.ErrVoteRemoveFailed.Response(c)
 			return
 		}
 
-		// Decrement vote count
-		if err := tx.Model(&bug).Update("vote_count", gorm.Expr("vote_count - 1")).Error; err != nil {
+		// Decrement vote counts, by the weight the vote was cast with
+		if err := tx.Model(&bug).Updates(map[string]interface{}{
+			"vote_count":          gorm.Expr("vote_count - 1"),
+			"weighted_vote_count": gorm.Expr("weighted_vote_count - ?", existingVote.VoteWeight),
+		}).Error; err != nil {
 			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": gin.H{
-					"code":      "COUNT_UPDATE_FAILED",
-					"message":   "Failed to update vote count",
-					"timestamp": time.Now().UTC(),
-				},
-			})
+			errors.ErrCountUpdateFailed.WithMessage("Failed to update vote count").Response(c)
 			return
 		}
 
 		// Update user's last active timestamp
 		if err := tx.Model(&models.User{}).Where("id = ?", userUUID).Update("last_active_at", time.Now()).Error; err != nil {
 			tx.Rollback()
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": gin.H{
-					"code":      "ACTIVITY_UPDATE_FAILED",
-					"message":   "Failed to update user activity",
-					"timestamp": time.Now().UTC(),
-				},
-			})
+			errors.ErrActivityUpdateFailed.Response(c)
 			return
 		}
 
 		// Commit transaction
 		if err := tx.Commit().Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": gin.H{
-					"code":      "COMMIT_FAILED",
-					"message":   "Failed to save vote removal",
-					"timestamp": time.Now().UTC(),
-				},
-			})
+			errors.ErrCommitFailed.WithMessage("Failed to save vote removal").Response(c)
 			return
 		}
 
 		// Invalidate cache for this bug
 		ctx := c.Request.Context()
 		if err := h.cache.InvalidateBug(ctx, bugID); err != nil {
-			fmt.Printf("Failed to invalidate bug cache: %v\n", err)
+			logger.FromContext(ctx).Error("Failed to invalidate bug cache", err, logger.Fields{"bug_id": bugID})
+		}
+		if err := h.cache.RemoveBugVote(ctx, bugID, time.Now()); err != nil {
+			// Log cache error but don't fail the request
+			logger.FromContext(ctx).Error("Failed to update recent vote count", err, logger.Fields{"bug_id": bugID})
 		}
 
 		c.JSON(http.StatusOK, gin.H{
@@ -1111,16 +1827,18 @@ func (h *BugHandler) VoteBug(c *gin.Context) {
 	}
 
 	if err != gorm.ErrRecordNotFound {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "VOTE_CHECK_FAILED",
-				"message":   "Failed to check existing vote",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrVoteCheckFailed.Response(c)
 		return
 	}
 
+	// Votes from users with more reputation weigh more
+	reputation, err := userReputation(h.db, userUUID)
+	if err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to fetch voter reputation").Response(c)
+		return
+	}
+	weight := voteWeight(reputation)
+
 	// Start transaction for vote creation
 	tx := h.db.Begin()
 	defer func() {
@@ -1131,128 +1849,162 @@ func (h *BugHandler) VoteBug(c *gin.Context) {
 
 	// Create new vote
 	vote := models.BugVote{
-		BugID:  bugUUID,
-		UserID: userUUID,
+		BugID:      bugUUID,
+		UserID:     userUUID,
+		VoteWeight: weight,
 	}
 
 	if err := tx.Create(&vote).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "VOTE_CREATE_FAILED",
-				"message":   "Failed to create vote",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrVoteCreateFailed.Response(c)
 		return
 	}
 
-	// Increment vote count
-	if err := tx.Model(&bug).Update("vote_count", gorm.Expr("vote_count + 1")).Error; err != nil {
+	// Increment vote counts
+	if err := tx.Model(&bug).Updates(map[string]interface{}{
+		"vote_count":          gorm.Expr("vote_count + 1"),
+		"weighted_vote_count": gorm.Expr("weighted_vote_count + ?", weight),
+	}).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "COUNT_UPDATE_FAILED",
-				"message":   "Failed to update vote count",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrCountUpdateFailed.WithMessage("Failed to update vote count").Response(c)
+		return
+	}
+
+	// Read back the new count, which other votes may have changed since bug was loaded
+	if err := tx.Model(&bug).Select("vote_count", "weighted_vote_count").First(&bug).Error; err != nil {
+		tx.Rollback()
+		errors.ErrCountUpdateFailed.WithMessage("Failed to update vote count").Response(c)
 		return
 	}
 
 	// Update user's last active timestamp
 	if err := tx.Model(&models.User{}).Where("id = ?", userUUID).Update("last_active_at", time.Now()).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "ACTIVITY_UPDATE_FAILED",
-				"message":   "Failed to update user activity",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrActivityUpdateFailed.Response(c)
 		return
 	}
 
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "COMMIT_FAILED",
-				"message":   "Failed to save vote",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrCommitFailed.WithMessage("Failed to save vote").Response(c)
 		return
 	}
+	metrics.VotesTotal.Inc()
 
 	// Invalidate cache for this bug
 	ctx := c.Request.Context()
 	if err := h.cache.InvalidateBug(ctx, bugID); err != nil {
-		fmt.Printf("Failed to invalidate bug cache: %v\n", err)
+		logger.FromContext(ctx).Error("Failed to invalidate bug cache", err, logger.Fields{"bug_id": bugID})
+	}
+	// Counted towards automatic priority escalation
+	if err := h.cache.RecordBugVote(ctx, bugID, time.Now()); err != nil {
+		// Log cache error but don't fail the request
+		logger.FromContext(ctx).Error("Failed to update recent vote count", err, logger.Fields{"bug_id": bugID})
+	}
+
+	// Let the reporter know their bug is gaining traction
+	if milestone, ok := reachedVoteMilestone(h.voteMilestones, bug.VoteCount); ok {
+		if err := h.notifyVoteMilestone(ctx, &bug, milestone); err != nil {
+			// Log error but don't fail the request since the vote was already saved
+			logger.FromContext(ctx).Error("Failed to create vote milestone notification", err, logger.Fields{"bug_id": bugID})
+		}
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
-		"message": "Vote added successfully",
-		"voted":   true,
+		"message":     "Vote added successfully",
+		"voted":       true,
+		"vote_weight": weight,
 	})
 }
 
 // CreateCommentRequest represents the request payload for creating a comment
 type CreateCommentRequest struct {
-	Content string `json:"content" binding:"required,min=1,max=2000"`
+	Content  string     `json:"content" binding:"required,comment_min,max=2000"`
+	ParentID *uuid.UUID `json:"parent_id"` // Set to reply to a comment on the same bug
+}
+
+// commentTooShort responds with COMMENT_TOO_SHORT when binding failed because the
+// comment has fewer non-whitespace characters than the comment_min tag requires
+func commentTooShort(c *gin.Context, bindErr error, content string) bool {
+	if !utils.HasValidationTag(bindErr, "comment_min") {
+		return false
+	}
+
+	errors.ErrCommentTooShort.WithDetails(gin.H{
+		"min_chars":     utils.CommentMinChars(),
+		"current_chars": utils.CountNonWhitespace(content),
+	}).Response(c)
+	return true
+}
+
+// commentMostlyWhitespace rejects comments that are mostly whitespace, such as a few
+// words padded with blank lines
+func commentMostlyWhitespace(c *gin.Context, content string) bool {
+	if !utils.IsMostlyWhitespace(content) {
+		return false
+	}
+
+	errors.ErrInvalidContent.WithMessage("Comment content must not be mostly whitespace").WithDetails(gin.H{
+		"whitespace_ratio":     utils.WhitespaceRatio(content),
+		"max_whitespace_ratio": utils.MaxCommentWhitespaceRatio,
+	}).Response(c)
+	return true
 }
 
 // CreateComment handles creating comments on bug reports
+//
+// @Summary     Comment on a bug report
+// @Description Adds a comment, or a reply when parent_id is set. Comments by members of the assigned company are marked as company responses.
+// @Tags        bugs
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Bug ID" format(uuid)
+// @Param       request body CreateCommentRequest true "Comment"
+// @Success     201 {object} object{message=string,comment=models.Comment}
+// @Failure     400 {object} errors.ErrorResponse "Invalid ID, content or parent comment"
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse "Blocked by the reporter"
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     413 {object} errors.ErrorResponse
+// @Failure     422 {object} errors.ErrorResponse "Too few non-whitespace characters"
+// @Failure     429 {object} errors.ErrorResponse "Rate limited"
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /bugs/{id}/comments [post]
 func (h *BugHandler) CreateComment(c *gin.Context) {
 	bugID := c.Param("id")
 
 	bugUUID, err := uuid.Parse(bugID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_ID",
-				"message":   "Invalid bug ID format",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInvalidID.WithMessage("Invalid bug ID format").Response(c)
 		return
 	}
 
 	var req CreateCommentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "VALIDATION_ERROR",
-				"message":   "Invalid request data",
-				"details":   err.Error(),
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		if commentTooShort(c, err, req.Content) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+	if commentMostlyWhitespace(c, req.Content) {
 		return
 	}
 
 	// Get current user ID (authentication required)
 	userIDStr, exists := middleware.GetCurrentUserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": gin.H{
-				"code":      "AUTH_REQUIRED",
-				"message":   "Authentication required for commenting",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrAuthRequired.WithMessage("Authentication required for commenting").Response(c)
 		return
 	}
 
 	userUUID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_USER",
-				"message":   "Invalid user ID",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInvalidUser.Response(c)
 		return
 	}
 
@@ -1260,26 +2012,41 @@ func (h *BugHandler) CreateComment(c *gin.Context) {
 	var bug models.BugReport
 	if err := h.db.First(&bug, bugUUID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": gin.H{
-					"code":      "BUG_NOT_FOUND",
-					"message":   "Bug report not found",
-					"timestamp": time.Now().UTC(),
-				},
-			})
+			errors.ErrBugNotFound.Response(c)
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "QUERY_FAILED",
-				"message":   "Failed to verify bug report",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrQueryFailed.WithMessage("Failed to verify bug report").Response(c)
 		return
 	}
 
+	// Replies must answer a comment on the same bug
+	if req.ParentID != nil {
+		var parent models.Comment
+		err := h.db.Select("id", "bug_id").First(&parent, "id = ?", *req.ParentID).Error
+		if err == gorm.ErrRecordNotFound || (err == nil && parent.BugID != bugUUID) {
+			errors.ErrInvalidParentComment.Response(c)
+			return
+		}
+		if err != nil {
+			errors.ErrQueryFailed.WithMessage("Failed to verify parent comment").Response(c)
+			return
+		}
+	}
+
+	// Reporters can block users from commenting on their bugs
+	if bug.ReporterID != nil && *bug.ReporterID != userUUID {
+		reporterBlocks, err := loadUserBlockList(c.Request.Context(), h.db, h.cache, *bug.ReporterID)
+		if err != nil {
+			errors.ErrQueryFailed.WithMessage("Failed to check user blocks").Response(c)
+			return
+		}
+		if reporterBlocks.hasBlocked(userUUID) {
+			errors.ErrUserBlocked.Response(c)
+			return
+		}
+	}
+
 	// Check if this is a company response
 	isCompanyResponse := false
 	if bug.AssignedCompanyID != nil {
@@ -1303,13 +2070,7 @@ func (h *BugHandler) CreateComment(c *gin.Context) {
 	// Sanitize and validate comment content
 	sanitizedContent, contentValid := utils.ValidateString(req.Content, 1, 2000)
 	if !contentValid {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_CONTENT",
-				"message":   "Comment content must be between 1 and 2000 characters and contain no malicious content",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInvalidContent.WithMessage("Comment content must be between 1 and 2000 characters and contain no malicious content").Response(c)
 		return
 	}
 
@@ -1319,71 +2080,63 @@ func (h *BugHandler) CreateComment(c *gin.Context) {
 		UserID:            userUUID,
 		Content:           sanitizedContent,
 		IsCompanyResponse: isCompanyResponse,
+		ParentID:          req.ParentID,
 	}
 
 	if err := tx.Create(&comment).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "COMMENT_CREATE_FAILED",
-				"message":   "Failed to create comment",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrCommentCreateFailed.Response(c)
 		return
 	}
 
-	// Increment comment count
-	if err := tx.Model(&bug).Update("comment_count", gorm.Expr("comment_count + 1")).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "COUNT_UPDATE_FAILED",
-				"message":   "Failed to update comment count",
-				"timestamp": time.Now().UTC(),
-			},
-		})
-		return
+	// Only top-level comments are counted; replies are listed under them
+	if comment.ParentID == nil {
+		if err := tx.Model(&bug).Update("comment_count", gorm.Expr("comment_count + 1")).Error; err != nil {
+			tx.Rollback()
+			errors.ErrCountUpdateFailed.WithMessage("Failed to update comment count").Response(c)
+			return
+		}
 	}
 
 	// Update user's last active timestamp
 	if err := tx.Model(&models.User{}).Where("id = ?", userUUID).Update("last_active_at", time.Now()).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "ACTIVITY_UPDATE_FAILED",
-				"message":   "Failed to update user activity",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrActivityUpdateFailed.Response(c)
 		return
 	}
 
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "COMMIT_FAILED",
-				"message":   "Failed to save comment",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrCommitFailed.WithMessage("Failed to save comment").Response(c)
 		return
 	}
+	metrics.CommentsTotal.Inc()
+
+	h.publishBugEvent(c.Request.Context(), notification.Event{
+		Type:    notification.EventCommentCreated,
+		BugID:   bugUUID,
+		ActorID: userUUID,
+		Data:    gin.H{"comment_id": comment.ID, "is_company_response": isCompanyResponse},
+	})
 
 	// Load the created comment with user info
 	var createdComment models.Comment
 	if err := h.db.Preload("User").First(&createdComment, comment.ID).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "LOAD_FAILED",
-				"message":   "Comment created but failed to load details",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrLoadFailed.WithMessage("Comment created but failed to load details").Response(c)
 		return
 	}
 
+	// Replies are sent as reply notifications, so they follow that preference
+	notificationType := models.NotificationTypeCommentOnMyBug
+	if comment.ParentID != nil {
+		notificationType = models.NotificationTypeCommentReply
+	}
+	h.notifyBugSubscribers(c.Request.Context(), &bug, userUUID, notificationType,
+		fmt.Sprintf("%s commented on the bug report '%s' you are watching", createdComment.User.DisplayName, bug.Title),
+		gin.H{"comment_id": comment.ID})
+
+	h.dispatchWebhook(c.Request.Context(), bug, webhooks.Payload{Event: models.WebhookEventCommentCreated, Comment: &createdComment})
+
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Comment created successfully",
 		"comment": createdComment,
@@ -1391,18 +2144,29 @@ func (h *BugHandler) CreateComment(c *gin.Context) {
 }
 
 // UpdateBugStatus handles updating bug status (company users only)
+//
+// @Summary     Update a bug's status
+// @Description Sets the status of a bug. Only admins and members of the assigned company can change it.
+// @Tags        bugs
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Bug ID" format(uuid)
+// @Param       request body object{status=string} true "New status: open, reviewing, fixed or wont_fix"
+// @Success     200 {object} object{message=string,bug=models.BugReport}
+// @Failure     400 {object} errors.ErrorResponse "Invalid ID or status"
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     413 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /bugs/{id}/status [patch]
 func (h *BugHandler) UpdateBugStatus(c *gin.Context) {
 	bugID := c.Param("id")
 
 	bugUUID, err := uuid.Parse(bugID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_ID",
-				"message":   "Invalid bug ID format",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInvalidID.WithMessage("Invalid bug ID format").Response(c)
 		return
 	}
 
@@ -1412,51 +2176,29 @@ func (h *BugHandler) UpdateBugStatus(c *gin.Context) {
 
 	var req UpdateStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "VALIDATION_ERROR",
-				"message":   "Invalid request data",
-				"details":   err.Error(),
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
 		return
 	}
 
 	// Validate status
 	if !utils.ValidateStatus(req.Status) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_STATUS",
-				"message":   "Invalid status value",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInvalidStatus.Response(c)
 		return
 	}
 
 	// Get current user ID (authentication required)
 	userIDStr, exists := middleware.GetCurrentUserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": gin.H{
-				"code":      "AUTH_REQUIRED",
-				"message":   "Authentication required",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrAuthRequired.Response(c)
 		return
 	}
 
 	userUUID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_USER",
-				"message":   "Invalid user ID",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInvalidUser.Response(c)
 		return
 	}
 
@@ -1464,153 +2206,265 @@ func (h *BugHandler) UpdateBugStatus(c *gin.Context) {
 	var bug models.BugReport
 	if err := h.db.Preload("AssignedCompany").First(&bug, bugUUID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": gin.H{
-					"code":      "BUG_NOT_FOUND",
-					"message":   "Bug report not found",
-					"timestamp": time.Now().UTC(),
-				},
-			})
+			errors.ErrBugNotFound.Response(c)
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "QUERY_FAILED",
-				"message":   "Failed to fetch bug report",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrQueryFailed.WithMessage("Failed to fetch bug report").Response(c)
 		return
 	}
 
 	// Check permissions - only company members or admins can update status
-	isAdmin := middleware.IsCurrentUserAdmin(c)
-	canUpdate := isAdmin
+	if !h.canManageBug(c, &bug, userUUID) {
+		errors.ErrInsufficientPermissions.WithMessage("Only company members can update bug status").Response(c)
+		return
+	}
 
-	if !canUpdate && bug.AssignedCompanyID != nil {
-		// Check if user is a member of the assigned company
-		var membership models.CompanyMember
-		err := h.db.Where("company_id = ? AND user_id = ?", *bug.AssignedCompanyID, userUUID).
-			First(&membership).Error
-		if err == nil {
-			canUpdate = true
+	// Capture the bug state before the status change for the audit log
+	beforeState := newBugAuditState(&bug)
+
+	// Record the change as an event and project it onto the bug report
+	if err := h.recordBugEvent(c.Request.Context(), bugUUID, models.BugEventStatusChanged, userUUID, models.BugFieldChange{
+		From: bug.Status,
+		To:   req.Status,
+	}); err != nil {
+		errors.ErrUpdateFailed.WithMessage("Failed to update bug status").Response(c)
+		return
+	}
+
+	// Load updated bug
+	if err := h.db.Preload("Application").Preload("AssignedCompany").
+		First(&bug, bugUUID).Error; err != nil {
+		errors.ErrLoadFailed.WithMessage("Status updated but failed to load bug details").Response(c)
+		return
+	}
+
+	// Record the status change with before/after snapshots
+	details := fmt.Sprintf("Bug status changed from %s to %s", beforeState.Status, bug.Status)
+	afterState := newBugAuditState(&bug)
+	if err := createAuditLog(h.db, c, models.AuditActionBugStatusChange, models.AuditResourceBug, &bugUUID, details, beforeState, afterState); err != nil {
+		// Log error but don't fail the request since the status was already updated
+		logger.FromContext(c.Request.Context()).Error("Failed to log audit action", err)
+	}
+
+	// Let the reporter know their bug was fixed
+	if bug.Status == models.BugStatusFixed && beforeState.Status != models.BugStatusFixed && bug.ReporterID != nil {
+		if err := queueNotificationEmail(c.Request.Context(), h.db, h.cache, *bug.ReporterID, models.NotificationTypeBugStatusChange, func(recipient models.User) models.EmailPayload {
+			return email.BugFixedEmail(h.deepLinks, recipient, bug)
+		}); err != nil {
+			// Log error but don't fail the request since the status was already updated
+			logger.FromContext(c.Request.Context()).Error("Failed to queue bug fixed email", err)
 		}
 	}
 
-	if !canUpdate {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": gin.H{
-				"code":      "INSUFFICIENT_PERMISSIONS",
-				"message":   "Only company members can update bug status",
-				"timestamp": time.Now().UTC(),
-			},
+	if bug.Status != beforeState.Status {
+		h.publishBugEvent(c.Request.Context(), notification.Event{
+			Type:    notification.EventBugStatusChanged,
+			BugID:   bugUUID,
+			ActorID: userUUID,
+			Data:    gin.H{"from": beforeState.Status, "to": bug.Status},
 		})
+		h.notifyBugSubscribers(c.Request.Context(), &bug, userUUID, models.NotificationTypeBugStatusChange,
+			fmt.Sprintf("The bug report '%s' you are watching is now %s", bug.Title, bug.Status),
+			gin.H{"from": beforeState.Status, "to": bug.Status})
+		h.dispatchWebhook(c.Request.Context(), bug, webhooks.Payload{Event: models.WebhookEventBugStatusChanged})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Bug status updated successfully",
+		"bug":     bug,
+	})
+}
+
+// canManageBug reports whether the current user may change a bug's status and
+// priority: admins and members of the bug's assigned company
+func (h *BugHandler) canManageBug(c *gin.Context, bug *models.BugReport, userID uuid.UUID) bool {
+	if middleware.IsCurrentUserAdmin(c) {
+		return true
+	}
+	if bug.AssignedCompanyID == nil {
+		return false
+	}
+
+	// Check if user is a member of the assigned company
+	var membership models.CompanyMember
+	err := h.db.Where("company_id = ? AND user_id = ?", *bug.AssignedCompanyID, userID).
+		First(&membership).Error
+	return err == nil
+}
+
+// recordBugEvent appends an event to the bug's event stream and applies it to the bug
+// report projection
+func (h *BugHandler) recordBugEvent(ctx context.Context, bugID uuid.UUID, eventType string, actorID uuid.UUID, change models.BugFieldChange) error {
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		_, err := models.AppendBugEvent(tx, bugID, eventType, &actorID, change)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if _, err := h.projector.Project(ctx, bugID); err != nil {
+		return err
+	}
+
+	if err := h.cache.InvalidateBug(ctx, bugID.String()); err != nil {
+		// Log cache error but don't fail the request
+		logger.FromContext(ctx).Error("Failed to invalidate bug cache", err, logger.Fields{"bug_id": bugID.String()})
+	}
+	return nil
+}
+
+// UpdateBugPriorityRequest represents the request to change a bug's priority
+type UpdateBugPriorityRequest struct {
+	Priority string `json:"priority" binding:"required"`
+}
+
+// UpdateBugPriority handles updating bug priority (company users only)
+//
+// @Summary     Update a bug's priority
+// @Description Sets the priority of a bug. Only admins and members of the assigned company can change it.
+// @Tags        bugs
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Bug ID" format(uuid)
+// @Param       request body UpdateBugPriorityRequest true "New priority: low, medium, high or critical"
+// @Success     200 {object} object{message=string,bug=models.BugReport}
+// @Failure     400 {object} errors.ErrorResponse "Invalid ID or priority"
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     413 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /bugs/{id}/priority [patch]
+func (h *BugHandler) UpdateBugPriority(c *gin.Context) {
+	bugUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid bug ID format").Response(c)
+		return
+	}
+
+	var req UpdateBugPriorityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+
+	if !models.IsValidPriority(req.Priority) {
+		errors.ErrInvalidPriority.Response(c)
 		return
 	}
 
-	// Update status
-	updates := map[string]interface{}{
-		"status":     req.Status,
-		"updated_at": time.Now(),
+	userUUID, ok := currentUserUUID(c)
+	if !ok {
+		return
 	}
 
-	// Set resolved_at timestamp if status is fixed or won't fix
-	if req.Status == models.BugStatusFixed || req.Status == models.BugStatusWontFix {
-		if bug.ResolvedAt == nil {
-			updates["resolved_at"] = time.Now()
+	var bug models.BugReport
+	if err := h.db.First(&bug, bugUUID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrBugNotFound.Response(c)
+			return
 		}
-	} else {
-		// Clear resolved_at if status is changed back to open/reviewing
-		updates["resolved_at"] = nil
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch bug report").Response(c)
+		return
 	}
 
-	if err := h.db.Model(&bug).Updates(updates).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "UPDATE_FAILED",
-				"message":   "Failed to update bug status",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+	if !h.canManageBug(c, &bug, userUUID) {
+		errors.ErrInsufficientPermissions.WithMessage("Only company members can update bug priority").Response(c)
+		return
+	}
+
+	beforeState := newBugAuditState(&bug)
+
+	if err := h.recordBugEvent(c.Request.Context(), bugUUID, models.BugEventPriorityChanged, userUUID, models.BugFieldChange{
+		From: bug.Priority,
+		To:   req.Priority,
+	}); err != nil {
+		errors.ErrUpdateFailed.WithMessage("Failed to update bug priority").Response(c)
 		return
 	}
 
-	// Load updated bug
 	if err := h.db.Preload("Application").Preload("AssignedCompany").
 		First(&bug, bugUUID).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "LOAD_FAILED",
-				"message":   "Status updated but failed to load bug details",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrLoadFailed.WithMessage("Priority updated but failed to load bug details").Response(c)
 		return
 	}
 
+	details := fmt.Sprintf("Bug priority changed from %s to %s", beforeState.Priority, bug.Priority)
+	if err := createAuditLog(h.db, c, models.AuditActionBugPriorityChange, models.AuditResourceBug, &bugUUID, details, beforeState, newBugAuditState(&bug)); err != nil {
+		// Log error but don't fail the request since the priority was already updated
+		logger.FromContext(c.Request.Context()).Error("Failed to log audit action", err)
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Bug status updated successfully",
+		"message": "Bug priority updated successfully",
 		"bug":     bug,
 	})
 }
 
 // AddCompanyResponseRequest represents the request to add a company response
 type AddCompanyResponseRequest struct {
-	Content string `json:"content" binding:"required,min=1,max=2000"`
+	Content string `json:"content" binding:"required,comment_min,max=2000"`
 }
 
 // AddCompanyResponse handles adding company responses to bug reports
+//
+// @Summary     Add a company response
+// @Description Adds a comment marked as the company's response. Only admins and members of the assigned company can respond. The reporter is notified.
+// @Tags        bugs
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Bug ID" format(uuid)
+// @Param       request body AddCompanyResponseRequest true "Response"
+// @Success     201 {object} object{message=string,comment=models.Comment}
+// @Failure     400 {object} errors.ErrorResponse "Invalid ID or content"
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     413 {object} errors.ErrorResponse
+// @Failure     422 {object} errors.ErrorResponse "Too few non-whitespace characters"
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /bugs/{id}/company-response [post]
 func (h *BugHandler) AddCompanyResponse(c *gin.Context) {
 	bugID := c.Param("id")
 
 	bugUUID, err := uuid.Parse(bugID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_ID",
-				"message":   "Invalid bug ID format",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInvalidID.WithMessage("Invalid bug ID format").Response(c)
 		return
 	}
 
 	var req AddCompanyResponseRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "VALIDATION_ERROR",
-				"message":   "Invalid request data",
-				"details":   err.Error(),
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		if commentTooShort(c, err, req.Content) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+	if commentMostlyWhitespace(c, req.Content) {
 		return
 	}
 
 	// Get current user ID (authentication required)
 	userIDStr, exists := middleware.GetCurrentUserID(c)
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": gin.H{
-				"code":      "AUTH_REQUIRED",
-				"message":   "Authentication required",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrAuthRequired.Response(c)
 		return
 	}
 
 	userUUID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_USER",
-				"message":   "Invalid user ID",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInvalidUser.Response(c)
 		return
 	}
 
@@ -1618,23 +2472,11 @@ func (h *BugHandler) AddCompanyResponse(c *gin.Context) {
 	var bug models.BugReport
 	if err := h.db.Preload("AssignedCompany").First(&bug, bugUUID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": gin.H{
-					"code":      "BUG_NOT_FOUND",
-					"message":   "Bug report not found",
-					"timestamp": time.Now().UTC(),
-				},
-			})
+			errors.ErrBugNotFound.Response(c)
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "QUERY_FAILED",
-				"message":   "Failed to fetch bug report",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrQueryFailed.WithMessage("Failed to fetch bug report").Response(c)
 		return
 	}
 
@@ -1653,13 +2495,7 @@ func (h *BugHandler) AddCompanyResponse(c *gin.Context) {
 	}
 
 	if !canRespond {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": gin.H{
-				"code":      "INSUFFICIENT_PERMISSIONS",
-				"message":   "Only company members can add company responses",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInsufficientPermissions.WithMessage("Only company members can add company responses").Response(c)
 		return
 	}
 
@@ -1674,13 +2510,7 @@ func (h *BugHandler) AddCompanyResponse(c *gin.Context) {
 	// Sanitize and validate response content
 	sanitizedContent, contentValid := utils.ValidateString(req.Content, 1, 2000)
 	if !contentValid {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_CONTENT",
-				"message":   "Response content must be between 1 and 2000 characters and contain no malicious content",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInvalidContent.WithMessage("Response content must be between 1 and 2000 characters and contain no malicious content").Response(c)
 		return
 	}
 
@@ -1694,63 +2524,60 @@ func (h *BugHandler) AddCompanyResponse(c *gin.Context) {
 
 	if err := tx.Create(&comment).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "CREATE_FAILED",
-				"message":   "Failed to create company response",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrCreateFailed.WithMessage("Failed to create company response").Response(c)
 		return
 	}
 
 	// Update bug comment count
 	if err := tx.Model(&bug).Update("comment_count", gorm.Expr("comment_count + 1")).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "COUNT_UPDATE_FAILED",
-				"message":   "Failed to update comment count",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrCountUpdateFailed.WithMessage("Failed to update comment count").Response(c)
 		return
 	}
 
 	// Update user's last active timestamp
 	if err := tx.Model(&models.User{}).Where("id = ?", userUUID).Update("last_active_at", time.Now()).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "ACTIVITY_UPDATE_FAILED",
-				"message":   "Failed to update user activity",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrActivityUpdateFailed.Response(c)
 		return
 	}
 
+	// Let the reporter know the company responded
+	if bug.ReporterID != nil && *bug.ReporterID != userUUID && bug.AssignedCompany != nil {
+		if err := queueNotificationEmail(c.Request.Context(), tx, h.cache, *bug.ReporterID, models.NotificationTypeCompanyResponse, func(recipient models.User) models.EmailPayload {
+			return email.CompanyResponseEmail(h.deepLinks, recipient, bug, bug.AssignedCompany.Name, sanitizedContent)
+		}); err != nil {
+			tx.Rollback()
+			errors.ErrOutboxEnqueueFailed.WithMessage("Failed to queue company response notification").Response(c)
+			return
+		}
+	}
+
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "COMMIT_FAILED",
-				"message":   "Failed to save company response",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrCommitFailed.WithMessage("Failed to save company response").Response(c)
 		return
 	}
+	metrics.CommentsTotal.Inc()
+
+	h.publishBugEvent(c.Request.Context(), notification.Event{
+		Type:    notification.EventCompanyResponse,
+		BugID:   bugUUID,
+		ActorID: userUUID,
+		Data:    gin.H{"comment_id": comment.ID},
+	})
+
+	responder := "The assigned company"
+	if bug.AssignedCompany != nil {
+		responder = bug.AssignedCompany.Name
+	}
+	h.notifyBugSubscribers(c.Request.Context(), &bug, userUUID, models.NotificationTypeCompanyResponse,
+		fmt.Sprintf("%s responded to the bug report '%s' you are watching", responder, bug.Title),
+		gin.H{"comment_id": comment.ID})
 
 	// Load created comment with user details
 	if err := h.db.Preload("User").First(&comment, comment.ID).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "LOAD_FAILED",
-				"message":   "Response created but failed to load details",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrLoadFailed.WithMessage("Response created but failed to load details").Response(c)
 		return
 	}
 
@@ -2,12 +2,19 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"bugrelay-backend/internal/captcha"
+	"bugrelay-backend/internal/jobs"
 	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -23,10 +30,11 @@ func TestBugHandler_CreateBug_ValidationLogic(t *testing.T) {
 	user := createTestUser(t, db)
 
 	tests := []struct {
-		name           string
-		requestBody    map[string]interface{}
-		expectedStatus int
-		expectedError  string
+		name            string
+		requestBody     map[string]interface{}
+		expectedStatus  int
+		expectedError   string
+		expectedDetails map[string]interface{}
 	}{
 		{
 			name: "valid bug creation",
@@ -46,8 +54,9 @@ func TestBugHandler_CreateBug_ValidationLogic(t *testing.T) {
 				"description":      "This is a valid bug description with sufficient length",
 				"application_name": "Test Application",
 			},
-			expectedStatus: http.StatusBadRequest,
-			expectedError:  "VALIDATION_ERROR",
+			expectedStatus:  http.StatusBadRequest,
+			expectedError:   "VALIDATION_ERROR",
+			expectedDetails: map[string]interface{}{"title": "must be at least 5 characters"},
 		},
 		{
 			name: "title too long",
@@ -56,8 +65,9 @@ func TestBugHandler_CreateBug_ValidationLogic(t *testing.T) {
 				"description":      "This is a valid bug description with sufficient length",
 				"application_name": "Test Application",
 			},
-			expectedStatus: http.StatusBadRequest,
-			expectedError:  "VALIDATION_ERROR",
+			expectedStatus:  http.StatusBadRequest,
+			expectedError:   "VALIDATION_ERROR",
+			expectedDetails: map[string]interface{}{"title": "must be at most 255 characters"},
 		},
 		{
 			name: "description too short",
@@ -66,8 +76,9 @@ func TestBugHandler_CreateBug_ValidationLogic(t *testing.T) {
 				"description":      "Short",
 				"application_name": "Test Application",
 			},
-			expectedStatus: http.StatusBadRequest,
-			expectedError:  "VALIDATION_ERROR",
+			expectedStatus:  http.StatusBadRequest,
+			expectedError:   "VALIDATION_ERROR",
+			expectedDetails: map[string]interface{}{"description": "must be at least 10 characters"},
 		},
 		{
 			name: "missing application name",
@@ -75,8 +86,9 @@ func TestBugHandler_CreateBug_ValidationLogic(t *testing.T) {
 				"title":       "Valid Bug Title",
 				"description": "This is a valid bug description with sufficient length",
 			},
-			expectedStatus: http.StatusBadRequest,
-			expectedError:  "VALIDATION_ERROR",
+			expectedStatus:  http.StatusBadRequest,
+			expectedError:   "VALIDATION_ERROR",
+			expectedDetails: map[string]interface{}{"application_name": "field is required"},
 		},
 		{
 			name: "invalid priority",
@@ -140,6 +152,9 @@ func TestBugHandler_CreateBug_ValidationLogic(t *testing.T) {
 				assert.Contains(t, response, "error")
 				errorData := response["error"].(map[string]interface{})
 				assert.Equal(t, tt.expectedError, errorData["code"])
+				if tt.expectedDetails != nil {
+					assert.Equal(t, tt.expectedDetails, errorData["details"])
+				}
 			} else {
 				assert.Contains(t, response, "bug")
 			}
@@ -273,6 +288,64 @@ func TestBugHandler_ApplicationAutoDiscovery(t *testing.T) {
 	}
 }
 
+// TestBugHandler_ApplicationURLDeduplication tests that URL variants resolve to one application
+func TestBugHandler_ApplicationURLDeduplication(t *testing.T) {
+	handler, db := setupBugTestHandler(t)
+
+	app, err := handler.findOrCreateApplication(db, "Acme", stringPtr("https://www.acme.com/"))
+	require.NoError(t, err)
+	require.NotNil(t, app.CanonicalURL)
+	assert.Equal(t, "https://acme.com", *app.CanonicalURL)
+	assert.Equal(t, "https://www.acme.com/", *app.URL)
+
+	variants := []string{
+		"https://acme.com",
+		"http://acme.com/",
+		"HTTP://WWW.ACME.COM:80",
+		"https://acme.com:443/?utm_source=newsletter",
+		"acme.com",
+	}
+	for _, variant := range variants {
+		t.Run(variant, func(t *testing.T) {
+			found, err := handler.findOrCreateApplication(db, "Acme Corp "+variant, stringPtr(variant))
+			require.NoError(t, err)
+			assert.Equal(t, app.ID, found.ID)
+		})
+	}
+
+	t.Run("different path is a different application", func(t *testing.T) {
+		other, err := handler.findOrCreateApplication(db, "Acme Mobile", stringPtr("https://acme.com/mobile/"))
+		require.NoError(t, err)
+		assert.NotEqual(t, app.ID, other.ID)
+		assert.Equal(t, "https://acme.com/mobile", *other.CanonicalURL)
+	})
+
+	t.Run("falls back to name", func(t *testing.T) {
+		found, err := handler.findOrCreateApplication(db, "acme", stringPtr("https://acme.io"))
+		require.NoError(t, err)
+		assert.Equal(t, app.ID, found.ID)
+	})
+
+	t.Run("invalid URL is stored without canonical URL", func(t *testing.T) {
+		created, err := handler.findOrCreateApplication(db, "FTP App", stringPtr("ftp://files.example.com"))
+		require.NoError(t, err)
+		assert.Nil(t, created.CanonicalURL)
+	})
+
+	t.Run("matches applications without canonical URL by exact URL", func(t *testing.T) {
+		legacy := &models.Application{ID: uuid.New(), Name: "Legacy App", URL: stringPtr("https://legacy.example.com/")}
+		require.NoError(t, db.Create(legacy).Error)
+
+		found, err := handler.findOrCreateApplication(db, "Legacy", stringPtr("https://legacy.example.com/"))
+		require.NoError(t, err)
+		assert.Equal(t, legacy.ID, found.ID)
+	})
+
+	var count int64
+	require.NoError(t, db.Model(&models.Application{}).Where("canonical_url = ?", "https://acme.com").Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
 // TestBugHandler_AnonymousBugSubmission tests anonymous bug submission functionality
 func TestBugHandler_AnonymousBugSubmission(t *testing.T) {
 	gin.SetMode(gin.TestMode)
@@ -309,4 +382,783 @@ func TestBugHandler_AnonymousBugSubmission(t *testing.T) {
 	
 	// Verify reporter is nil for anonymous submission
 	assert.Nil(t, bug["reporter"])
-}
\ No newline at end of file
+}
+// TestBugHandler_CreateBug_CustomFields tests custom field validation against application schemas
+func TestBugHandler_CreateBug_CustomFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	app := createTestApplication(t, db)
+
+	schema := &models.CustomFieldSchema{
+		ApplicationID: app.ID,
+		Fields: []models.FieldDefinition{
+			{Name: "game_version", Type: models.CustomFieldTypeString, Required: true, ValidationRegex: `^\d+\.\d+$`},
+			{Name: "level_id", Type: models.CustomFieldTypeNumber},
+		},
+	}
+	require.NoError(t, db.Create(schema).Error)
+
+	tests := []struct {
+		name           string
+		customFields   string
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "valid custom fields",
+			customFields:   `{"game_version": "1.4", "level_id": 12}`,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "missing required field",
+			customFields:   `{"level_id": 12}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "CUSTOM_FIELDS_INVALID",
+		},
+		{
+			name:           "invalid field format",
+			customFields:   `{"game_version": "latest"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "CUSTOM_FIELDS_INVALID",
+		},
+		{
+			name:           "custom fields not an object",
+			customFields:   `["game_version"]`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "INVALID_CUSTOM_FIELDS",
+		},
+		{
+			name:           "malformed JSON",
+			customFields:   `{"game_version": }`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "VALIDATION_ERROR",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"title": "Crash on level load", "description": "The game crashes when loading the level", ` +
+				`"application_name": "` + app.Name + `", "custom_fields": ` + tt.customFields + `}`
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("POST", "/bugs", bytes.NewBufferString(body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			handler.CreateBug(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+			if tt.expectedError != "" {
+				errorData := response["error"].(map[string]interface{})
+				assert.Equal(t, tt.expectedError, errorData["code"])
+			} else {
+				bug := response["bug"].(map[string]interface{})
+				customFields := bug["custom_fields"].(map[string]interface{})
+				assert.Equal(t, "1.4", customFields["game_version"])
+				assert.Equal(t, float64(12), customFields["level_id"])
+			}
+		})
+	}
+}
+
+// TestBugHandler_CreateBug_AssignmentRules tests assigning new bugs with company assignment rules
+func TestBugHandler_CreateBug_AssignmentRules(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	user := createTestUser(t, db)
+	company := createTestCompany(t, db, true)
+	app := createTestCompanyApplication(t, db, company)
+
+	uiOwner := &models.User{ID: uuid.New(), Email: "ui@testcompany.com", DisplayName: "UI Owner"}
+	crashOwner := &models.User{ID: uuid.New(), Email: "crash@testcompany.com", DisplayName: "Crash Owner"}
+	require.NoError(t, db.Create(uiOwner).Error)
+	require.NoError(t, db.Create(crashOwner).Error)
+
+	rules := []models.BugAssignmentRule{
+		// Checked last
+		{CompanyID: company.ID, ApplicationID: app.ID, Tags: []string{"ui"}, AssigneeUserID: &uiOwner.ID, PriorityOrder: 10},
+		// Checked first, but requires both tags and critical priority
+		{CompanyID: company.ID, ApplicationID: app.ID, Tags: []string{"ui", "crash"}, AssigneeUserID: &crashOwner.ID, PriorityFilter: models.BugPriorityCritical, PriorityOrder: 1},
+	}
+	require.NoError(t, db.Create(&rules).Error)
+
+	tests := []struct {
+		name             string
+		priority         string
+		tags             []string
+		expectedAssignee *uuid.UUID
+	}{
+		{
+			name:             "lowest priority_order wins when several rules match",
+			priority:         models.BugPriorityCritical,
+			tags:             []string{"ui", "crash"},
+			expectedAssignee: &crashOwner.ID,
+		},
+		{
+			name:             "rule is skipped when priority does not match",
+			priority:         models.BugPriorityHigh,
+			tags:             []string{"ui", "crash"},
+			expectedAssignee: &uiOwner.ID,
+		},
+		{
+			name:             "partial tag match falls through to next rule",
+			priority:         models.BugPriorityCritical,
+			tags:             []string{"ui"},
+			expectedAssignee: &uiOwner.ID,
+		},
+		{
+			name:             "no matching rule leaves bug unassigned",
+			priority:         models.BugPriorityCritical,
+			tags:             []string{"crash"},
+			expectedAssignee: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := json.Marshal(map[string]interface{}{
+				"title":            "Assignment rule bug",
+				"description":      "This is a valid bug description with sufficient length",
+				"application_name": app.Name,
+				"priority":         tt.priority,
+				"tags":             tt.tags,
+			})
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("POST", "/bugs", bytes.NewBuffer(body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			mockAuthMiddleware(user.ID)(c)
+
+			handler.CreateBug(c)
+
+			require.Equal(t, http.StatusCreated, w.Code)
+
+			var response struct {
+				Bug models.BugReport `json:"bug"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+			require.NotNil(t, response.Bug.AssignedCompanyID)
+			assert.Equal(t, company.ID, *response.Bug.AssignedCompanyID)
+			assert.Equal(t, tt.expectedAssignee, response.Bug.AssignedMemberID)
+		})
+	}
+}
+
+func TestBugHandler_CreateBug_OutboxDelivery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	user := createTestUser(t, db)
+
+	var deliveries [][]byte
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, models.OutboxEventBugCreated, r.Header.Get("X-BugRelay-Event"))
+		body, _ := io.ReadAll(r.Body)
+		deliveries = append(deliveries, body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer webhook.Close()
+
+	body, err := json.Marshal(map[string]interface{}{
+		"title":            "Outbox bug",
+		"description":      "This is a valid bug description with sufficient length",
+		"application_name": "Outbox App",
+	})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/bugs", bytes.NewBuffer(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	mockAuthMiddleware(user.ID)(c)
+
+	handler.CreateBug(c)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var response struct {
+		Bug models.BugReport `json:"bug"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	// The event is committed with the bug, before any delivery is attempted
+	var event models.OutboxEvent
+	require.NoError(t, db.Where("event_type = ?", models.OutboxEventBugCreated).First(&event).Error)
+	assert.Equal(t, models.OutboxStatusPending, event.Status)
+	assert.Empty(t, deliveries)
+
+	// A processor started after a restart delivers the pending event
+	processor := jobs.NewOutboxProcessor(db)
+	processor.Handle(models.OutboxEventBugCreated, jobs.NewWebhookHandler(webhook.Client(), webhook.URL))
+	require.NoError(t, processor.ProcessPending(context.Background()))
+
+	require.Len(t, deliveries, 1)
+	var payload map[string]interface{}
+	require.NoError(t, json.Unmarshal(deliveries[0], &payload))
+	assert.Equal(t, response.Bug.ID.String(), payload["bug_id"])
+	assert.Equal(t, "Outbox bug", payload["title"])
+
+	require.NoError(t, db.First(&event, "id = ?", event.ID).Error)
+	assert.Equal(t, models.OutboxStatusProcessed, event.Status)
+	assert.NotNil(t, event.ProcessedAt)
+}
+
+func TestBugHandler_CreateBug_Honeypot(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	adminHandler := NewAdminHandler(db, nil)
+	user := createTestUser(t, db)
+
+	submit := func(title string, userID *uuid.UUID) uuid.UUID {
+		body, err := json.Marshal(map[string]interface{}{
+			"title":            title,
+			"description":      "This is a bug description with sufficient length",
+			"application_name": "Honeypot App",
+			"website":          "http://spam.example.com",
+		})
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/bugs", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		if userID != nil {
+			mockAuthMiddleware(*userID)(c)
+		}
+
+		handler.CreateBug(c)
+
+		// Honeypot submissions look successful to the sender
+		require.Equal(t, http.StatusCreated, w.Code)
+		assert.NotContains(t, w.Body.String(), "spam")
+
+		var response struct {
+			Bug models.BugReport `json:"bug"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.Bug.ID
+	}
+
+	spamID := submit("Cheap watches for sale", nil)
+	userBugID := submit("Signed in user bug", &user.ID)
+
+	var spamBug models.BugReport
+	require.NoError(t, db.First(&spamBug, "id = ?", spamID).Error)
+	assert.True(t, spamBug.IsSpam)
+
+	// The honeypot only applies to anonymous submissions
+	var userBug models.BugReport
+	require.NoError(t, db.First(&userBug, "id = ?", userBugID).Error)
+	assert.False(t, userBug.IsSpam)
+
+	// Spam does not trigger notifications
+	var events int64
+	db.Model(&models.OutboxEvent{}).Where("event_type = ?", models.OutboxEventBugCreated).Count(&events)
+	assert.Equal(t, int64(1), events)
+
+	listIDs := func(serve func(*gin.Context), path string) []uuid.UUID {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", path, nil)
+		serve(c)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Bugs []models.BugReport `json:"bugs"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+		var ids []uuid.UUID
+		for _, bug := range response.Bugs {
+			ids = append(ids, bug.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []uuid.UUID{userBugID}, listIDs(handler.ListBugs, "/bugs"))
+	assert.Equal(t, []uuid.UUID{spamID}, listIDs(adminHandler.ListBugsForModeration, "/admin/bugs?spam_only=true"))
+	assert.ElementsMatch(t, []uuid.UUID{spamID, userBugID}, listIDs(adminHandler.ListBugsForModeration, "/admin/bugs"))
+
+	// Bugs scored above the configured threshold are hidden as well
+	require.NoError(t, db.Model(&models.BugReport{}).Where("id = ?", userBugID).Update("spam_score", 0.9).Error)
+	assert.Empty(t, listIDs(handler.ListBugs, "/bugs"))
+	handler.SetSpamScoreThreshold(0.95)
+	assert.Equal(t, []uuid.UUID{userBugID}, listIDs(handler.ListBugs, "/bugs"))
+}
+
+func TestBugHandler_CreateBug_Idempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupBugTestDB(t)
+	redisClient, mock := newMockRedisClient()
+	handler := NewBugHandler(db, redisClient)
+	user := createTestUser(t, db)
+
+	submit := func(title, idempotencyKey, remoteAddr string, userID *uuid.UUID) *httptest.ResponseRecorder {
+		body, err := json.Marshal(map[string]interface{}{
+			"title":            title,
+			"description":      "This is a bug description with sufficient length",
+			"application_name": "Idempotent App",
+		})
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/bugs", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Request.RemoteAddr = remoteAddr
+		if idempotencyKey != "" {
+			c.Request.Header.Set("X-Idempotency-Key", idempotencyKey)
+		}
+		if userID != nil {
+			mockAuthMiddleware(*userID)(c)
+		}
+
+		handler.CreateBug(c)
+		return w
+	}
+
+	countBugs := func(title string) int64 {
+		var count int64
+		db.Model(&models.BugReport{}).Where("title = ?", title).Count(&count)
+		return count
+	}
+
+	t.Run("retry replays the original response", func(t *testing.T) {
+		first := submit("Retried bug", "key-1", "203.0.113.1:1234", &user.ID)
+		require.Equal(t, http.StatusCreated, first.Code)
+		assert.Empty(t, first.Header().Get("X-Idempotency-Replayed"))
+
+		keys := mock.keysWithPrefix("idem:")
+		require.Len(t, keys, 1)
+		assert.Equal(t, 24*time.Hour, mock.ttls[keys[0]])
+
+		retry := submit("Retried bug", "key-1", "203.0.113.1:1234", &user.ID)
+		assert.Equal(t, first.Code, retry.Code)
+		assert.Equal(t, first.Body.String(), retry.Body.String())
+		assert.Equal(t, first.Header().Get("Content-Type"), retry.Header().Get("Content-Type"))
+		assert.Equal(t, "true", retry.Header().Get("X-Idempotency-Replayed"))
+		assert.Equal(t, int64(1), countBugs("Retried bug"))
+	})
+
+	t.Run("keys are scoped to the user", func(t *testing.T) {
+		other := &models.User{ID: uuid.New(), Email: "other@example.com", DisplayName: "Other User"}
+		require.NoError(t, db.Create(other).Error)
+
+		w := submit("Retried bug", "key-1", "203.0.113.1:1234", &other.ID)
+		require.Equal(t, http.StatusCreated, w.Code)
+		assert.Empty(t, w.Header().Get("X-Idempotency-Replayed"))
+		assert.Equal(t, int64(2), countBugs("Retried bug"))
+	})
+
+	t.Run("anonymous keys are scoped to the client IP", func(t *testing.T) {
+		first := submit("Anonymous retried bug", "key-2", "203.0.113.5:1234", nil)
+		require.Equal(t, http.StatusCreated, first.Code)
+
+		retry := submit("Anonymous retried bug", "key-2", "203.0.113.5:4321", nil)
+		assert.Equal(t, "true", retry.Header().Get("X-Idempotency-Replayed"))
+		assert.Equal(t, first.Body.String(), retry.Body.String())
+
+		otherIP := submit("Anonymous retried bug", "key-2", "203.0.113.6:1234", nil)
+		require.Equal(t, http.StatusCreated, otherIP.Code)
+		assert.Empty(t, otherIP.Header().Get("X-Idempotency-Replayed"))
+		assert.Equal(t, int64(2), countBugs("Anonymous retried bug"))
+	})
+
+	t.Run("requests without a key are not deduplicated", func(t *testing.T) {
+		require.Equal(t, http.StatusCreated, submit("Unkeyed bug", "", "203.0.113.1:1234", &user.ID).Code)
+		require.Equal(t, http.StatusCreated, submit("Unkeyed bug", "", "203.0.113.1:1234", &user.ID).Code)
+		assert.Equal(t, int64(2), countBugs("Unkeyed bug"))
+	})
+
+	t.Run("retry while the first request is in progress is rejected", func(t *testing.T) {
+		// The first request has reserved the key but not yet created its bug
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("POST", "/bugs", nil)
+		c.Request.Header.Set("X-Idempotency-Key", "key-4")
+		mockAuthMiddleware(user.ID)(c)
+		keyHash := idempotencyKeyHash(c)
+		reserved, err := handler.cache.ReserveIdempotencyKey(context.Background(), keyHash, idempotentResponse{InProgress: true})
+		require.NoError(t, err)
+		require.True(t, reserved)
+		assert.Equal(t, time.Minute, mock.ttls["idem:"+keyHash])
+
+		retry := submit("Slow bug", "key-4", "203.0.113.1:1234", &user.ID)
+		assert.Equal(t, http.StatusConflict, retry.Code)
+		assert.Contains(t, retry.Body.String(), "IDEMPOTENCY_KEY_IN_USE")
+		assert.Equal(t, int64(0), countBugs("Slow bug"))
+	})
+
+	t.Run("failed requests are not stored", func(t *testing.T) {
+		w := submit("Bad", "key-3", "203.0.113.1:1234", &user.ID)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+
+		retry := submit("Fixed bug title", "key-3", "203.0.113.1:1234", &user.ID)
+		require.Equal(t, http.StatusCreated, retry.Code)
+		assert.Empty(t, retry.Header().Get("X-Idempotency-Replayed"))
+	})
+}
+
+func TestBugHandler_CreateBug_MinWordCounts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	user := createTestUser(t, db)
+
+	submit := func(title, description string, auth gin.HandlerFunc, override bool) (*httptest.ResponseRecorder, map[string]interface{}) {
+		body, err := json.Marshal(map[string]interface{}{
+			"title":            title,
+			"description":      description,
+			"application_name": "Word Count App",
+		})
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/bugs", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		if override {
+			c.Request.Header.Set("X-Admin-Override", "true")
+		}
+		if auth != nil {
+			auth(c)
+		}
+
+		handler.CreateBug(c)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w, response
+	}
+
+	errorOf := func(response map[string]interface{}) (string, map[string]interface{}) {
+		body := response["error"].(map[string]interface{})
+		details, _ := body["details"].(map[string]interface{})
+		return body["code"].(string), details
+	}
+
+	t.Run("single word description", func(t *testing.T) {
+		w, response := submit("Editor crashes", "brokenbrokenbroken", nil, false)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		code, details := errorOf(response)
+		assert.Equal(t, "DESCRIPTION_TOO_SHORT", code)
+		assert.Equal(t, float64(5), details["min_words"])
+		assert.Equal(t, float64(1), details["current_words"])
+	})
+
+	t.Run("single word title", func(t *testing.T) {
+		w, response := submit("Crashing", "The editor crashes whenever I save a draft", nil, false)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		code, details := errorOf(response)
+		assert.Equal(t, "TITLE_TOO_SHORT", code)
+		assert.Equal(t, float64(2), details["min_words"])
+		assert.Equal(t, float64(1), details["current_words"])
+	})
+
+	t.Run("multi-space sequences are not words", func(t *testing.T) {
+		w, response := submit("Editor crashes", "crash     crash\t\t\tcrash \n\n crash", nil, false)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		_, details := errorOf(response)
+		assert.Equal(t, float64(4), details["current_words"])
+	})
+
+	t.Run("punctuation separates words", func(t *testing.T) {
+		w, response := submit("Editor crashes", "crash!crash...crash", nil, false)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		_, details := errorOf(response)
+		assert.Equal(t, float64(3), details["current_words"])
+
+		w, _ = submit("Éditeur bloqué", "l'écran—reste bloqué…après démarrage", nil, false)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("admin override skips the checks", func(t *testing.T) {
+		admin := &models.User{ID: uuid.New(), Email: "admin@example.com", DisplayName: "Admin", IsAdmin: true}
+		require.NoError(t, db.Create(admin).Error)
+
+		w, _ := submit("Crashing", "brokenbrokenbroken", mockAdminAuthMiddleware(admin.ID), true)
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		// Admins without the header are checked like everyone else
+		w, response := submit("Crashing", "brokenbrokenbroken", mockAdminAuthMiddleware(admin.ID), false)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		code, _ := errorOf(response)
+		assert.Equal(t, "TITLE_TOO_SHORT", code)
+	})
+
+	t.Run("override header is ignored for non-admins", func(t *testing.T) {
+		w, response := submit("Crashing", "brokenbrokenbroken", mockAuthMiddleware(user.ID), true)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		code, _ := errorOf(response)
+		assert.Equal(t, "TITLE_TOO_SHORT", code)
+	})
+
+	t.Run("configured minimums", func(t *testing.T) {
+		handler.SetMinWordCounts(0, 10)
+		t.Cleanup(func() { handler.SetMinWordCounts(defaultTitleMinWords, defaultDescriptionMinWords) })
+
+		w, response := submit("Crashing", "The editor crashes whenever I save a draft", nil, false)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		code, details := errorOf(response)
+		assert.Equal(t, "DESCRIPTION_TOO_SHORT", code)
+		assert.Equal(t, float64(10), details["min_words"])
+		assert.Equal(t, float64(8), details["current_words"])
+	})
+}
+
+func TestBugHandler_CreateBug_RecaptchaTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, _ := setupBugTestHandler(t)
+	verifier := captcha.NewRecaptchaVerifier("test-secret", &http.Client{Timeout: 50 * time.Millisecond})
+	handler.SetCaptchaVerifier(verifier)
+
+	var delay atomic.Int64
+	delay.Store(int64(5 * time.Second))
+	release := make(chan struct{})
+	recaptcha := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "test-secret", r.FormValue("secret"))
+		select {
+		case <-release:
+		case <-time.After(time.Duration(delay.Load())):
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+	}))
+	defer recaptcha.Close()
+	defer close(release)
+	verifier.SetVerifyURL(recaptcha.URL)
+
+	submit := func(title string) *httptest.ResponseRecorder {
+		body, err := json.Marshal(map[string]interface{}{
+			"title":            title,
+			"description":      "This is an anonymous bug report with sufficient length",
+			"application_name": "Recaptcha App",
+			"contact_email":    "reporter@example.com",
+			"recaptcha_token":  "token",
+		})
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/bugs", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.CreateBug(c)
+		return w
+	}
+
+	// A verification slower than the timeout is abandoned and the bug is rejected
+	start := time.Now()
+	_, err := handler.validateCaptcha(context.Background(), "token")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	w := submit("Slow reCAPTCHA bug")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "RECAPTCHA_ERROR")
+
+	// Verifications within the timeout succeed
+	delay.Store(0)
+	w = submit("Fast reCAPTCHA bug")
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+// stubVerifier is a captcha verifier that returns a fixed result
+type stubVerifier struct {
+	result captcha.Result
+}
+
+func (v stubVerifier) Verify(ctx context.Context, token string) (captcha.Result, error) {
+	return v.result, nil
+}
+
+func TestBugHandler_ValidateCaptcha_ScoreThreshold(t *testing.T) {
+	handler, _ := setupBugTestHandler(t)
+
+	tests := []struct {
+		name      string
+		result    captcha.Result
+		threshold float64
+		expected  bool
+	}{
+		{"score above default threshold", captcha.Result{Success: true, Score: 0.6}, defaultRecaptchaScoreThreshold, true},
+		{"score below default threshold", captcha.Result{Success: true, Score: 0.4}, defaultRecaptchaScoreThreshold, false},
+		{"score below configured threshold", captcha.Result{Success: true, Score: 0.6}, 0.7, false},
+		{"score at configured threshold", captcha.Result{Success: true, Score: 0.3}, 0.3, true},
+		{"unscored success", captcha.Result{Success: true}, 0.9, true},
+		{"failure", captcha.Result{Success: false, Score: 0.9}, 0.5, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler.SetCaptchaVerifier(stubVerifier{result: tt.result})
+			handler.SetRecaptchaScoreThreshold(tt.threshold)
+
+			valid, err := handler.validateCaptcha(context.Background(), "token")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, valid)
+		})
+	}
+
+	// Without a verifier tokens are not checked
+	handler.SetCaptchaVerifier(nil)
+	valid, err := handler.validateCaptcha(context.Background(), "token")
+	require.NoError(t, err)
+	assert.True(t, valid)
+}
+
+// TestBugHandler_CreateBug_EnvironmentInfo tests environment detection from request headers
+func TestBugHandler_CreateBug_EnvironmentInfo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	app := createTestApplication(t, db)
+
+	const chromeOnMac = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+
+	tests := []struct {
+		name        string
+		environment string
+		userAgent   string
+		expected    models.EnvironmentInfo
+	}{
+		{
+			name:      "detected from headers",
+			userAgent: chromeOnMac,
+			expected: models.EnvironmentInfo{
+				OSName: "macOS", OSVersion: "10.15.7", BrowserName: "Chrome", BrowserVersion: "120.0.0.0", Language: "en-GB",
+			},
+		},
+		{
+			name:        "submitted fields win",
+			environment: `{"os_name": "Mac OS X", "browser_name": "Arc", "screen_resolution": "1920x1080", "timezone": "Europe/London"}`,
+			userAgent:   chromeOnMac,
+			expected: models.EnvironmentInfo{
+				OSName: "macOS", BrowserName: "Arc", ScreenResolution: "1920x1080", Language: "en-GB", Timezone: "Europe/London",
+			},
+		},
+		{
+			name:      "unrecognised client",
+			userAgent: "curl/8.4.0",
+			expected:  models.EnvironmentInfo{Language: "en-GB"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"title": "Layout broken", "description": "The sidebar overlaps the content area", ` +
+				`"application_name": "` + app.Name + `"`
+			if tt.environment != "" {
+				body += `, "environment_info": ` + tt.environment
+			}
+			body += `}`
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("POST", "/bugs", bytes.NewBufferString(body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Request.Header.Set("User-Agent", tt.userAgent)
+			c.Request.Header.Set("Accept-Language", "en-GB,en;q=0.9")
+
+			handler.CreateBug(c)
+			require.Equal(t, http.StatusCreated, w.Code)
+
+			var response struct {
+				Bug struct {
+					ID uuid.UUID `json:"id"`
+				} `json:"bug"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+			var bug models.BugReport
+			require.NoError(t, db.First(&bug, "id = ?", response.Bug.ID).Error)
+
+			var environment models.EnvironmentInfo
+			require.NoError(t, json.Unmarshal(bug.EnvironmentInfo, &environment))
+			assert.Equal(t, tt.expected, environment)
+		})
+	}
+}
+
+func TestBugHandler_CreateBug_TagLimits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+
+	submit := func(tags []string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		body, err := json.Marshal(map[string]interface{}{
+			"title":            "Editor crashes on save",
+			"description":      "The editor crashes whenever I save a long draft",
+			"application_name": "Tag Limit App",
+			"tags":             tags,
+		})
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/bugs", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		handler.CreateBug(c)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w, response
+	}
+
+	invalidTags := func(response map[string]interface{}) []interface{} {
+		body := response["error"].(map[string]interface{})
+		assert.Equal(t, "INVALID_TAG", body["code"])
+		return body["details"].(map[string]interface{})["tags"].([]interface{})
+	}
+
+	t.Run("valid tags are normalized", func(t *testing.T) {
+		w, response := submit([]string{" Editor ", "Crash"})
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		var bug models.BugReport
+		require.NoError(t, db.First(&bug, "id = ?", response["bug"].(map[string]interface{})["id"]).Error)
+		assert.Equal(t, []string{"editor", "crash"}, []string(bug.Tags))
+	})
+
+	t.Run("invalid tags are reported with their reasons", func(t *testing.T) {
+		w, response := submit([]string{"editor", "x", "c++"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"tag": "x", "reason": "too_short"},
+			map[string]interface{}{"tag": "c++", "reason": "invalid_characters"},
+		}, invalidTags(response))
+	})
+
+	t.Run("limits are configurable", func(t *testing.T) {
+		handler.SetTagConfig(utils.TagConfig{MaxTags: 2, MinLength: 1, MaxLength: 6})
+		defer handler.SetTagConfig(utils.DefaultTagConfig())
+
+		w, response := submit([]string{"ui", "editor", "crash"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		body := response["error"].(map[string]interface{})
+		assert.Equal(t, "TOO_MANY_TAGS", body["code"])
+		assert.Equal(t, map[string]interface{}{"max_tags": float64(2), "current_tags": float64(3)}, body["details"])
+
+		w, _ = submit([]string{"x", "editor"})
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		w, response = submit([]string{"crashes"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"tag": "crashes", "reason": "too_long"},
+		}, invalidTags(response))
+	})
+}
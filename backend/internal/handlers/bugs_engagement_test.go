@@ -2,13 +2,17 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -233,6 +237,53 @@ func TestBugHandler_VotingSystem(t *testing.T) {
 }
 
 // TestBugHandler_CreateComment tests basic commenting functionality
+func TestBugHandler_VoteBug_RecentVotes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupBugTestDB(t)
+	redisClient, _ := newMockRedisClient()
+	handler := NewBugHandler(db, redisClient)
+	app := createTestApplication(t, db)
+	bug := createTestBugReport(t, db, app, createTestUser(t, db))
+
+	vote := func(userID uuid.UUID) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/bugs/"+bug.ID.String()+"/vote", nil)
+		c.Params = gin.Params{{Key: "id", Value: bug.ID.String()}}
+		mockAuthMiddleware(userID)(c)
+		handler.VoteBug(c)
+		require.Contains(t, []int{http.StatusCreated, http.StatusOK}, w.Code, w.Body.String())
+	}
+	recentVotes := func() int64 {
+		count, err := handler.cache.CountRecentBugVotes(context.Background(), bug.ID.String(), time.Now())
+		require.NoError(t, err)
+		return count
+	}
+
+	voters := make([]uuid.UUID, 3)
+	for i := range voters {
+		voter := &models.User{ID: uuid.New(), Email: uuid.NewString() + "@example.com", DisplayName: "Voter"}
+		require.NoError(t, db.Create(voter).Error)
+		voters[i] = voter.ID
+		vote(voter.ID)
+	}
+	assert.Equal(t, int64(3), recentVotes())
+
+	// Withdrawing a vote uncounts it, so toggling cannot push a bug up
+	for i := 0; i < 5; i++ {
+		vote(voters[0])
+		vote(voters[0])
+	}
+	vote(voters[0])
+	assert.Equal(t, int64(2), recentVotes())
+
+	// Votes from more than a day ago are not counted
+	count, err := handler.cache.CountRecentBugVotes(context.Background(), bug.ID.String(), time.Now().Add(25*time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
 func TestBugHandler_CreateComment(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	
@@ -341,6 +392,61 @@ func TestBugHandler_CreateComment(t *testing.T) {
 	}
 }
 
+func TestBugHandler_CreateComment_ContentRules(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	user := createTestUser(t, db)
+	app := createTestApplication(t, db)
+	bug := createTestBugReport(t, db, app, user)
+
+	comment := func(content string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		body, err := json.Marshal(map[string]interface{}{"content": content})
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", fmt.Sprintf("/bugs/%s/comments", bug.ID), bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "id", Value: bug.ID.String()}}
+		mockAuthMiddleware(user.ID)(c)
+		handler.CreateComment(c)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w, response
+	}
+
+	t.Run("rejects comments with too few characters", func(t *testing.T) {
+		w, response := comment("  +1 same  ")
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		errorData := response["error"].(map[string]interface{})
+		assert.Equal(t, "COMMENT_TOO_SHORT", errorData["code"])
+		details := errorData["details"].(map[string]interface{})
+		assert.Equal(t, float64(utils.DefaultCommentMinChars), details["min_chars"])
+		assert.Equal(t, float64(6), details["current_chars"])
+	})
+
+	t.Run("minimum is configurable", func(t *testing.T) {
+		defer utils.SetCommentMinChars(utils.CommentMinChars())
+		utils.SetCommentMinChars(4)
+
+		w, _ := comment("+1 same")
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("rejects comments that are mostly whitespace", func(t *testing.T) {
+		w, response := comment("Reproduced" + strings.Repeat(" \n", 21))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "INVALID_CONTENT", response["error"].(map[string]interface{})["code"])
+
+		// Exactly 80% whitespace is allowed
+		w, _ = comment("Reproduced" + strings.Repeat(" \n", 20))
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+}
+
 // TestBugHandler_CommentingSystem tests comprehensive commenting functionality
 func TestBugHandler_CommentingSystem(t *testing.T) {
 	gin.SetMode(gin.TestMode)
@@ -418,8 +524,8 @@ func TestBugHandler_CommentingSystem(t *testing.T) {
 			name:           "whitespace only content",
 			userID:         user.ID,
 			content:        "   \n\t   ",
-			expectedStatus: http.StatusBadRequest,
-			expectedError:  "VALIDATION_ERROR",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedError:  "COMMENT_TOO_SHORT",
 		},
 	}
 
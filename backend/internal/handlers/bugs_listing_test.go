@@ -11,6 +11,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -70,6 +71,93 @@ func TestBugHandler_GetBug(t *testing.T) {
 	}
 }
 
+// TestBugHandler_GetBug_Includes tests loading bug relationships on request
+func TestBugHandler_GetBug_Includes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	user := createTestUser(t, db)
+	app := createTestApplication(t, db)
+	bug := createTestBugReport(t, db, app, user)
+	require.NoError(t, db.Model(bug).Update("tags", pq.StringArray{"crash"}).Error)
+
+	comment := &models.Comment{ID: uuid.New(), BugID: bug.ID, UserID: user.ID, Content: "I can reproduce this on every launch"}
+	require.NoError(t, db.Create(comment).Error)
+	attachment := &models.FileAttachment{ID: uuid.New(), BugID: bug.ID, Filename: "crash.png", FileURL: "/uploads/bugs/crash.png"}
+	require.NoError(t, db.Create(attachment).Error)
+
+	getBug := func(query string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", fmt.Sprintf("/bugs/%s%s", bug.ID, query), nil)
+		c.Params = gin.Params{{Key: "id", Value: bug.ID.String()}}
+
+		handler.GetBug(c)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w, response
+	}
+
+	t.Run("base response omits relationships", func(t *testing.T) {
+		w, response := getBug("")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		bugData := response["bug"].(map[string]interface{})
+		assert.NotContains(t, bugData, "comments")
+		assert.NotContains(t, bugData, "attachments")
+		assert.Equal(t, app.ID.String(), bugData["application_id"])
+
+		links := response["_links"].(map[string]interface{})
+		commentsLink := links["comments"].(map[string]interface{})
+		assert.Equal(t, fmt.Sprintf("/bugs/%s?include=comments", bug.ID), commentsLink["href"])
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"tag": "crash", "href": "/api/v1/tags/crash/bugs"},
+		}, links["tag_feed"])
+	})
+
+	t.Run("comments only", func(t *testing.T) {
+		w, response := getBug("?include=comments")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		bugData := response["bug"].(map[string]interface{})
+		assert.Len(t, bugData["comments"], 1)
+		assert.NotContains(t, bugData, "attachments")
+	})
+
+	t.Run("attachments are counted rather than loaded", func(t *testing.T) {
+		w, response := getBug("")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		bugData := response["bug"].(map[string]interface{})
+		assert.Equal(t, float64(1), bugData["attachment_count"])
+		assert.NotContains(t, bugData, "attachments")
+
+		links := response["_links"].(map[string]interface{})
+		attachmentsLink := links["attachments"].(map[string]interface{})
+		assert.Equal(t, fmt.Sprintf("/bugs/%s/attachments", bug.ID), attachmentsLink["href"])
+
+		w, _ = getBug("?include=attachments")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("base response is smaller than full response", func(t *testing.T) {
+		baseW, _ := getBug("")
+		fullW, _ := getBug("?include=application,reporter,company,comments,votes")
+		require.Equal(t, http.StatusOK, fullW.Code)
+
+		assert.Less(t, baseW.Body.Len(), fullW.Body.Len())
+	})
+
+	t.Run("unsupported include", func(t *testing.T) {
+		w, response := getBug("?include=secrets")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		errorData := response["error"].(map[string]interface{})
+		assert.Equal(t, "INVALID_INCLUDE", errorData["code"])
+	})
+}
+
 // TestBugHandler_ListBugs_Filtering tests bug listing with various filters
 func TestBugHandler_ListBugs_Filtering(t *testing.T) {
 	gin.SetMode(gin.TestMode)
@@ -467,4 +555,141 @@ func TestBugHandler_PaginationLogic(t *testing.T) {
 			assert.Equal(t, tt.hasPrev, pagination["has_prev"])
 		})
 	}
-}
\ No newline at end of file
+}
+// TestBugHandler_ListBugs_CustomFieldFilter tests filtering by custom field values
+func TestBugHandler_ListBugs_CustomFieldFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	user := createTestUser(t, db)
+	app := createTestApplication(t, db)
+
+	bug1 := createTestBugReport(t, db, app, user)
+	require.NoError(t, db.Model(bug1).Update("custom_fields", `{"level_id": "7", "platform": "pc"}`).Error)
+
+	bug2 := createTestBugReport(t, db, app, user)
+	require.NoError(t, db.Model(bug2).Update("custom_fields", `{"level_id": "8", "platform": "pc"}`).Error)
+
+	tests := []struct {
+		name           string
+		queryParams    string
+		expectedCount  int
+		expectedStatus int
+	}{
+		{
+			name:           "single custom field filter",
+			queryParams:    "?custom_fields[level_id]=7",
+			expectedCount:  1,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "shared custom field value",
+			queryParams:    "?custom_fields[platform]=pc",
+			expectedCount:  2,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "combined custom field filters",
+			queryParams:    "?custom_fields[platform]=pc&custom_fields[level_id]=8",
+			expectedCount:  1,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid custom field name",
+			queryParams:    "?custom_fields[level'id]=7",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/bugs"+tt.queryParams, nil)
+
+			handler.ListBugs(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			if w.Code == http.StatusOK {
+				var response map[string]interface{}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+				bugs := response["bugs"].([]interface{})
+				assert.Equal(t, tt.expectedCount, len(bugs))
+			}
+		})
+	}
+}
+
+func TestBugHandler_ListBugs_OSFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	user := createTestUser(t, db)
+	app := createTestApplication(t, db)
+
+	macBug := createTestBugReport(t, db, app, user)
+	require.NoError(t, db.Model(macBug).Update("environment_info", `{"os_name": "macOS", "os_version": "14.1"}`).Error)
+
+	windowsBug := createTestBugReport(t, db, app, user)
+	require.NoError(t, db.Model(windowsBug).Update("environment_info", `{"os_name": "Windows", "os_version": "11"}`).Error)
+
+	createTestBugReport(t, db, app, user)
+
+	tests := []struct {
+		name          string
+		queryParams   string
+		expectedCount int
+	}{
+		{name: "macos", queryParams: "?os=macos", expectedCount: 1},
+		{name: "case insensitive", queryParams: "?os=Windows", expectedCount: 1},
+		{name: "no matches", queryParams: "?os=android", expectedCount: 0},
+		{name: "unknown os is ignored", queryParams: "?os=beos", expectedCount: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/bugs"+tt.queryParams, nil)
+
+			handler.ListBugs(c)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Len(t, response["bugs"], tt.expectedCount)
+		})
+	}
+}
+
+func TestBugHandler_ListBugs_TagFeedLinks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	user := createTestUser(t, db)
+	app := createTestApplication(t, db)
+
+	bug1 := createTestBugReport(t, db, app, user)
+	require.NoError(t, db.Model(bug1).Update("tags", pq.StringArray{"crash", "ios"}).Error)
+	bug2 := createTestBugReport(t, db, app, user)
+	require.NoError(t, db.Model(bug2).Update("tags", pq.StringArray{"ios"}).Error)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/bugs?sort=oldest", nil)
+
+	handler.ListBugs(c)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	// Each tag on the page is linked once
+	links := response["_links"].(map[string]interface{})
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"tag": "crash", "href": "/api/v1/tags/crash/bugs"},
+		map[string]interface{}{"tag": "ios", "href": "/api/v1/tags/ios/bugs"},
+	}, links["tag_feed"])
+}
@@ -475,4 +475,42 @@ func TestBugHandler_StatusManagement_ValidationErrors(t *testing.T) {
 			assert.Equal(t, tt.expectedError, errorData["code"])
 		})
 	}
-}
\ No newline at end of file
+}
+// TestBugHandler_StatusManagement_AuditStates tests that status changes record before/after states
+func TestBugHandler_StatusManagement_AuditStates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	user := createTestUser(t, db)
+	app := createTestApplication(t, db)
+	bug := createTestBugReport(t, db, app, user)
+
+	body, err := json.Marshal(map[string]interface{}{"status": models.BugStatusFixed})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("PATCH", fmt.Sprintf("/bugs/%s/status", bug.ID.String()), bytes.NewBuffer(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: bug.ID.String()}}
+	c.Set("user_id", user.ID.String())
+	c.Set("is_admin", true)
+
+	handler.UpdateBugStatus(c)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var auditLog models.AuditLog
+	require.NoError(t, db.Where("action = ? AND resource_id = ?", models.AuditActionBugStatusChange, bug.ID).First(&auditLog).Error)
+	require.NotNil(t, auditLog.BeforeState)
+	require.NotNil(t, auditLog.AfterState)
+
+	changes, err := auditStateDiff(auditLog.BeforeState, auditLog.AfterState)
+	require.NoError(t, err)
+
+	assert.Equal(t, models.BugStatusOpen, changes["status"].Before)
+	assert.Equal(t, models.BugStatusFixed, changes["status"].After)
+	assert.Nil(t, changes["resolved_at"].Before)
+	assert.NotNil(t, changes["resolved_at"].After)
+	assert.NotContains(t, changes, "title")
+	assert.NotContains(t, changes, "priority")
+}
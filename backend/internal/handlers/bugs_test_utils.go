@@ -1,12 +1,21 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"path"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"bugrelay-backend/internal/models"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/require"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -14,7 +23,12 @@ import (
 
 // setupBugTestDB creates an in-memory SQLite database for testing
 func setupBugTestDB(t *testing.T) *gorm.DB {
-	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+	return openBugTestDB(t, sqlite.Open(":memory:"))
+}
+
+// openBugTestDB opens a test database with dialector and migrates the schema
+func openBugTestDB(t testing.TB, dialector gorm.Dialector) *gorm.DB {
+	db, err := gorm.Open(dialector, &gorm.Config{
 		DisableForeignKeyConstraintWhenMigrating: true,
 	})
 	require.NoError(t, err)
@@ -27,9 +41,25 @@ func setupBugTestDB(t *testing.T) *gorm.DB {
 		&models.BugReport{},
 		&models.BugVote{},
 		&models.Comment{},
+		&models.CommentEdit{},
+		&models.BugEditHistory{},
 		&models.CompanyMember{},
 		&models.FileAttachment{},
 		&models.AuditLog{},
+		&models.CustomFieldSchema{},
+		&models.BugAssignmentRule{},
+		&models.CompanyWebhook{},
+		&models.OutboxEvent{},
+		&models.CompanyInvitation{},
+		&models.CompanyInviteLink{},
+		&models.UserBlock{},
+		&models.NotificationPreferences{},
+		&models.SecurityEvent{},
+		&models.ModerationQueueEntry{},
+		&models.BugEvent{},
+		&models.SearchQuery{},
+		&models.Notification{},
+		&models.BugSubscription{},
 	)
 	require.NoError(t, err)
 
@@ -105,3 +135,136 @@ func mockAdminAuthMiddleware(userID uuid.UUID) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// mockRedis is a go-redis hook that serves the string commands used by the cache
+// service from memory instead of a Redis server
+type mockRedis struct {
+	mu     sync.Mutex
+	values map[string]string
+	ttls   map[string]time.Duration
+}
+
+// newMockRedisClient creates a Redis client backed by an in-memory mockRedis
+func newMockRedisClient() (*redis.Client, *mockRedis) {
+	mock := &mockRedis{values: make(map[string]string), ttls: make(map[string]time.Duration)}
+	client := redis.NewClient(&redis.Options{Addr: "mock:6379"})
+	client.AddHook(mock)
+	return client, mock
+}
+
+func (m *mockRedis) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, errors.New("mock redis does not dial")
+	}
+}
+
+func (m *mockRedis) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		m.process(cmd)
+		return cmd.Err()
+	}
+}
+
+func (m *mockRedis) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		for _, cmd := range cmds {
+			m.process(cmd)
+		}
+		return nil
+	}
+}
+
+func (m *mockRedis) process(cmd redis.Cmder) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	args := cmd.Args()
+	switch cmd.Name() {
+	case "get":
+		value, ok := m.values[args[1].(string)]
+		if !ok {
+			cmd.SetErr(redis.Nil)
+			return
+		}
+		cmd.(*redis.StringCmd).SetVal(value)
+	case "set":
+		key := args[1].(string)
+		setNX := args[len(args)-1] == "nx"
+		if _, exists := m.values[key]; exists && setNX {
+			cmd.(*redis.BoolCmd).SetVal(false)
+			return
+		}
+		switch value := args[2].(type) {
+		case []byte:
+			m.values[key] = string(value)
+		default:
+			m.values[key] = fmt.Sprint(value)
+		}
+		if len(args) > 4 && args[3] == "px" {
+			m.ttls[key] = time.Duration(args[4].(int64)) * time.Millisecond
+		} else if len(args) > 4 && args[3] == "ex" {
+			m.ttls[key] = time.Duration(args[4].(int64)) * time.Second
+		}
+		if setNX {
+			cmd.(*redis.BoolCmd).SetVal(true)
+			return
+		}
+		cmd.(*redis.StatusCmd).SetVal("OK")
+	case "incr", "decr":
+		key := args[1].(string)
+		count, _ := strconv.ParseInt(m.values[key], 10, 64)
+		if cmd.Name() == "incr" {
+			count++
+		} else {
+			count--
+		}
+		m.values[key] = strconv.FormatInt(count, 10)
+		cmd.(*redis.IntCmd).SetVal(count)
+	case "expire":
+		key := args[1].(string)
+		m.ttls[key] = time.Duration(args[2].(int64)) * time.Second
+		cmd.(*redis.BoolCmd).SetVal(true)
+	case "keys":
+		var keys []string
+		for key := range m.values {
+			if matched, _ := path.Match(args[1].(string), key); matched {
+				keys = append(keys, key)
+			}
+		}
+		cmd.(*redis.StringSliceCmd).SetVal(keys)
+	case "del":
+		var deleted int64
+		for _, arg := range args[1:] {
+			if _, ok := m.values[arg.(string)]; ok {
+				delete(m.values, arg.(string))
+				delete(m.ttls, arg.(string))
+				deleted++
+			}
+		}
+		cmd.(*redis.IntCmd).SetVal(deleted)
+	case "exists":
+		var count int64
+		for _, arg := range args[1:] {
+			if _, ok := m.values[arg.(string)]; ok {
+				count++
+			}
+		}
+		cmd.(*redis.IntCmd).SetVal(count)
+	default:
+		cmd.SetErr(fmt.Errorf("mock redis does not support %s", cmd.Name()))
+	}
+}
+
+// keysWithPrefix returns the stored keys starting with prefix
+func (m *mockRedis) keysWithPrefix(prefix string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var keys []string
+	for key := range m.values {
+		if matched, _ := path.Match(prefix+"*", key); matched {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"net/http"
+	"path"
+	"strings"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// V2Response is the envelope of every successful /api/v2 response. Errors keep the
+// format shared with v1.
+type V2Response struct {
+	Data  interface{} `json:"data"`
+	Meta  interface{} `json:"meta,omitempty"`
+	Links interface{} `json:"links,omitempty"`
+}
+
+// ListBugsV2Request represents query parameters for listing bugs in API v2. Pages
+// are requested with the opaque cursor returned by the previous page.
+type ListBugsV2Request struct {
+	Cursor      string `form:"cursor"`
+	Limit       int    `form:"limit,default=20"`
+	Search      string `form:"search"`
+	Status      string `form:"status"`
+	Priority    string `form:"priority"`
+	Tags        string `form:"tags"`
+	Application string `form:"application"`
+	Company     string `form:"company"`
+	HideBlocked bool   `form:"hide_blocked"`
+}
+
+// ListBugsV2 lists bugs newest first, a page at a time. Unlike offset pages, a
+// cursor page does not shift when bugs are submitted while a client pages through.
+func (h *BugHandler) ListBugsV2(c *gin.Context) {
+	var req ListBugsV2Request
+	if err := c.ShouldBindQuery(&req); err != nil {
+		errors.ErrValidationError.WithMessage("Invalid query parameters").WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+
+	req.Limit = h.pagination.Limit(req.Limit)
+
+	var cursor *bugCursor
+	if req.Cursor != "" {
+		decoded, err := decodeBugCursor(req.Cursor)
+		if err != nil {
+			errors.ErrInvalidCursor.Response(c)
+			return
+		}
+		cursor = &decoded
+	}
+
+	// Bugs from users the current user has a block with can be hidden on request
+	var hiddenReporterIDs []uuid.UUID
+	if req.HideBlocked {
+		blocks, ok, err := h.currentUserBlockList(c)
+		if err != nil {
+			errors.ErrQueryFailed.WithMessage("Failed to check user blocks").Response(c)
+			return
+		}
+		if ok {
+			hiddenReporterIDs = blocks.hiddenUserIDs()
+		}
+	}
+
+	query := buildBugQuery(h.db, BugQueryOptions{
+		Status:             req.Status,
+		Priority:           req.Priority,
+		Tags:               req.Tags,
+		Application:        req.Application,
+		Company:            req.Company,
+		HiddenReporterIDs:  hiddenReporterIDs,
+		Search:             req.Search,
+		SpamScoreThreshold: h.spamScoreThreshold,
+	}).
+		Preload("Application").
+		Preload("Reporter").
+		Preload("AssignedCompany")
+	query = orderByBugCursor(query, cursor, false, false)
+
+	// Fetch one extra bug to tell whether there is a next page
+	bugs := make([]models.BugReport, 0, req.Limit+1)
+	if err := query.Limit(req.Limit + 1).Find(&bugs).Error; err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to fetch bug reports").Response(c)
+		return
+	}
+
+	hasMore := len(bugs) > req.Limit
+	if hasMore {
+		bugs = bugs[:req.Limit]
+	}
+
+	// Log each search once rather than once per page fetched
+	if search := strings.TrimSpace(req.Search); search != "" && cursor == nil {
+		h.logSearchQuery(c, search, int64(len(bugs)))
+	}
+
+	meta := gin.H{
+		"limit":       req.Limit,
+		"has_more":    hasMore,
+		"next_cursor": nil,
+	}
+	links := gin.H{
+		"self": gin.H{"href": c.Request.URL.RequestURI()},
+	}
+	if hasMore {
+		nextCursor := encodeBugCursor(bugs[len(bugs)-1])
+		meta["next_cursor"] = nextCursor
+
+		next := *c.Request.URL
+		params := next.Query()
+		params.Set("cursor", nextCursor)
+		next.RawQuery = params.Encode()
+		links["next"] = gin.H{"href": next.RequestURI()}
+	}
+
+	c.JSON(http.StatusOK, V2Response{Data: bugs, Meta: meta, Links: links})
+}
+
+// GetBugV2 returns a single bug report, with the same include parameter as v1
+func (h *BugHandler) GetBugV2(c *gin.Context) {
+	bug, ok := h.loadBug(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, V2Response{Data: bug, Links: bugLinks(c.Request.URL.Path)})
+}
+
+// CreateBugV2 handles bug submission, accepting the same payload and idempotency
+// key as v1. Suggested duplicates are returned as the data of a 200 response.
+func (h *BugHandler) CreateBugV2(c *gin.Context) {
+	// A retry of a request that already created a bug gets the original response
+	idempotencyKey := idempotencyKeyHash(c)
+	if !h.beginIdempotent(c, idempotencyKey) {
+		return
+	}
+
+	bug, ok := h.createBug(c, func(response PossibleDuplicateResponse) {
+		c.JSON(http.StatusOK, V2Response{Data: response})
+	})
+	if !ok {
+		h.abortIdempotent(c, idempotencyKey)
+		return
+	}
+
+	h.respondIdempotent(c, idempotencyKey, http.StatusCreated, V2Response{
+		Data:  bug,
+		Links: bugLinks(path.Join(c.Request.URL.Path, bug.ID.String())),
+	})
+}
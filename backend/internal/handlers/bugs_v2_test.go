@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// v2ListResponse is the body of a ListBugsV2 response
+type v2ListResponse struct {
+	Data []models.BugReport `json:"data"`
+	Meta struct {
+		Limit      int     `json:"limit"`
+		HasMore    bool    `json:"has_more"`
+		NextCursor *string `json:"next_cursor"`
+	} `json:"meta"`
+	Links map[string]map[string]string `json:"links"`
+}
+
+func TestBugHandler_ListBugsV2(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	user := createTestUser(t, db)
+	app := createTestApplication(t, db)
+
+	// Five bugs, three of them created at the same instant so pages split ties by ID
+	base := time.Now().Add(-time.Hour).UTC().Truncate(time.Microsecond)
+	createdAt := []time.Time{base, base, base, base.Add(time.Minute), base.Add(2 * time.Minute)}
+	for _, at := range createdAt {
+		bug := createTestBugReport(t, db, app, user)
+		require.NoError(t, db.Model(bug).Update("created_at", at).Error)
+	}
+	spam := createTestBugReport(t, db, app, user)
+	require.NoError(t, db.Model(spam).Update("is_spam", true).Error)
+
+	var expected []models.BugReport
+	require.NoError(t, db.Where("is_spam = ?", false).Order("created_at DESC").Order("id DESC").Find(&expected).Error)
+	require.Len(t, expected, 5)
+
+	router := gin.New()
+	router.GET("/api/v2/bugs", handler.ListBugsV2)
+
+	list := func(query string) (int, v2ListResponse, map[string]interface{}) {
+		req, _ := http.NewRequest("GET", "/api/v2/bugs?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response v2ListResponse
+		var raw map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &raw))
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		}
+		return w.Code, response, raw
+	}
+
+	t.Run("pages through every bug once, newest first", func(t *testing.T) {
+		var seen []uuid.UUID
+		query := "limit=2"
+		for pages := 0; pages < 5; pages++ {
+			status, response, _ := list(query)
+			require.Equal(t, http.StatusOK, status)
+			assert.Equal(t, 2, response.Meta.Limit)
+			assert.Contains(t, response.Links, "self")
+
+			for _, bug := range response.Data {
+				seen = append(seen, bug.ID)
+			}
+			if !response.Meta.HasMore {
+				assert.Nil(t, response.Meta.NextCursor)
+				assert.NotContains(t, response.Links, "next")
+				break
+			}
+
+			require.NotNil(t, response.Meta.NextCursor)
+			next, err := url.Parse(response.Links["next"]["href"])
+			require.NoError(t, err)
+			assert.Equal(t, *response.Meta.NextCursor, next.Query().Get("cursor"))
+			query = next.RawQuery
+		}
+
+		var expectedIDs []uuid.UUID
+		for _, bug := range expected {
+			expectedIDs = append(expectedIDs, bug.ID)
+		}
+		assert.Equal(t, expectedIDs, seen)
+	})
+
+	t.Run("bugs submitted while paging do not shift later pages", func(t *testing.T) {
+		_, first, _ := list("limit=2")
+		require.NotNil(t, first.Meta.NextCursor)
+
+		newBug := createTestBugReport(t, db, app, user)
+		t.Cleanup(func() {
+			db.Delete(newBug)
+		})
+
+		_, second, _ := list("limit=2&cursor=" + *first.Meta.NextCursor)
+		require.Len(t, second.Data, 2)
+		assert.Equal(t, expected[2].ID, second.Data[0].ID)
+		assert.Equal(t, expected[3].ID, second.Data[1].ID)
+	})
+
+	t.Run("filters apply to every page", func(t *testing.T) {
+		require.NoError(t, db.Model(&models.BugReport{}).Where("id = ?", expected[1].ID).Update("status", models.BugStatusFixed).Error)
+		t.Cleanup(func() {
+			db.Model(&models.BugReport{}).Where("id = ?", expected[1].ID).Update("status", models.BugStatusOpen)
+		})
+
+		status, response, _ := list(fmt.Sprintf("status=%s", models.BugStatusFixed))
+		require.Equal(t, http.StatusOK, status)
+		require.Len(t, response.Data, 1)
+		assert.Equal(t, expected[1].ID, response.Data[0].ID)
+		assert.False(t, response.Meta.HasMore)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		for _, cursor := range []string{"not-base64!", "bm90LWpzb24"} {
+			status, _, raw := list("cursor=" + cursor)
+			assert.Equal(t, http.StatusBadRequest, status)
+			assert.Equal(t, "INVALID_CURSOR", raw["error"].(map[string]interface{})["code"])
+		}
+	})
+}
+
+func TestBugCursor_RoundTrip(t *testing.T) {
+	bug := models.BugReport{ID: uuid.New(), CreatedAt: time.Date(2026, 3, 1, 12, 30, 0, 123456000, time.UTC)}
+
+	cursor, err := decodeBugCursor(encodeBugCursor(bug))
+	require.NoError(t, err)
+	assert.Equal(t, bug.ID, cursor.ID)
+	assert.True(t, bug.CreatedAt.Equal(cursor.CreatedAt))
+}
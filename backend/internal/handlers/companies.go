@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"context"
 	"crypto/rand"
+	"crypto/subtle"
 	"encoding/hex"
 	"fmt"
 	"net/http"
@@ -10,26 +12,87 @@ import (
 	"strings"
 	"time"
 
+	"bugrelay-backend/internal/cache"
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/jobs"
+	"bugrelay-backend/internal/logger"
 	"bugrelay-backend/internal/middleware"
 	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/storage"
+	"bugrelay-backend/internal/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // CompanyHandler handles company-related HTTP requests
 type CompanyHandler struct {
-	db *gorm.DB
+	db                    *gorm.DB
+	cache                 *cache.CacheService
+	materializedDashboard bool
+	frontendURL           string
+	spamScoreThreshold    float64
+	pagination            PaginationConfig
+	storage               storage.Backend
+	inviteLinkMaxBypasses int
+	backgroundQueue       *jobs.Queue
 }
 
 // NewCompanyHandler creates a new company handler
-func NewCompanyHandler(db *gorm.DB) *CompanyHandler {
+func NewCompanyHandler(db *gorm.DB, redisClient *redis.Client) *CompanyHandler {
 	return &CompanyHandler{
-		db: db,
+		db:                    db,
+		cache:                 cache.NewCacheService(redisClient),
+		frontendURL:           "http://localhost:3000",
+		spamScoreThreshold:    defaultSpamScoreThreshold,
+		pagination:            PaginationConfig{DefaultLimit: DefaultPageLimit, MaxLimit: defaultCompanyListMaxLimit},
+		storage:               storage.NewLocalBackend(storage.DefaultLocalDir),
+		inviteLinkMaxBypasses: defaultInviteLinkMaxBypasses,
 	}
 }
 
+// SetInviteLinkMaxBypasses sets how many people outside the company's domain an
+// invite link created to bypass the domain check can admit
+func (h *CompanyHandler) SetInviteLinkMaxBypasses(max int) {
+	h.inviteLinkMaxBypasses = max
+}
+
+// SetStorage sets the backend company logos are stored in
+func (h *CompanyHandler) SetStorage(backend storage.Backend) {
+	h.storage = backend
+}
+
+// SetPagination sets the page sizes of company listings, including company bug lists
+func (h *CompanyHandler) SetPagination(pagination PaginationConfig) {
+	h.pagination = pagination
+}
+
+// SetSpamScoreThreshold sets the spam score at which bugs are hidden from company bug lists
+func (h *CompanyHandler) SetSpamScoreThreshold(threshold float64) {
+	h.spamScoreThreshold = threshold
+}
+
+// SetFrontendURL sets the base URL used for links in invitation emails
+func (h *CompanyHandler) SetFrontendURL(frontendURL string) {
+	h.frontendURL = strings.TrimSuffix(frontendURL, "/")
+}
+
+// SetBackgroundQueue sets the low-priority queue that warms dashboard caches after
+// a company is verified
+func (h *CompanyHandler) SetBackgroundQueue(queue *jobs.Queue) {
+	h.backgroundQueue = queue
+}
+
+// SetMaterializedDashboard enables reading dashboard statistics from the
+// bug_stats_by_company materialized view
+func (h *CompanyHandler) SetMaterializedDashboard(enabled bool) {
+	h.materializedDashboard = enabled
+}
+
 // extractDomainFromURL extracts domain from URL or application name
 func (h *CompanyHandler) extractDomainFromURL(input string) string {
 	// If it looks like a URL, parse it
@@ -57,9 +120,15 @@ func (h *CompanyHandler) extractDomainFromURL(input string) string {
 // findOrCreateCompanyFromApplication creates a company from application info
 func (h *CompanyHandler) findOrCreateCompanyFromApplication(tx *gorm.DB, appName string, appURL *string) (*models.Company, error) {
 	// Extract domain from URL or application name
+	// URLs are matched by their canonical host, so that http and https or www.
+	// variants of a URL resolve to one company
 	var domain string
 	if appURL != nil && *appURL != "" {
-		domain = h.extractDomainFromURL(*appURL)
+		if host, err := utils.CanonicalHost(*appURL); err == nil {
+			domain = host
+		} else {
+			domain = h.extractDomainFromURL(*appURL)
+		}
 	} else {
 		domain = h.extractDomainFromURL(appName)
 	}
@@ -79,12 +148,8 @@ func (h *CompanyHandler) findOrCreateCompanyFromApplication(tx *gorm.DB, appName
 	companyName := appName
 	if appURL != nil && *appURL != "" {
 		// If we have a URL, try to extract a cleaner company name
-		if parsedURL, err := url.Parse(*appURL); err == nil && parsedURL.Host != "" {
-			// Remove www. and .com/.org etc for cleaner name
-			host := strings.ToLower(parsedURL.Host)
-			if strings.HasPrefix(host, "www.") {
-				host = host[4:]
-			}
+		if host, err := utils.CanonicalHost(*appURL); err == nil {
+			// Remove .com/.org etc for cleaner name
 			if dotIndex := strings.Index(host, "."); dotIndex > 0 {
 				companyName = strings.Title(host[:dotIndex])
 			}
@@ -112,58 +177,84 @@ type ListCompaniesRequest struct {
 	Verified *bool  `form:"verified"`
 }
 
+// companyListQuery returns a company query with the listing filters in req applied
+func companyListQuery(db *gorm.DB, req ListCompaniesRequest) *gorm.DB {
+	query := db.Model(&models.Company{})
+
+	if req.Verified != nil {
+		query = query.Where("is_verified = ?", *req.Verified)
+	}
+
+	if searchTerm := strings.TrimSpace(req.Search); searchTerm != "" {
+		query = query.Where("LOWER(name) LIKE LOWER(?) OR LOWER(domain) LIKE LOWER(?)",
+			"%"+searchTerm+"%", "%"+searchTerm+"%")
+	}
+
+	return query
+}
+
+// CompanyListPage is a page of companies returned by FindCompanies
+type CompanyListPage struct {
+	Companies []models.Company
+	Total     int64
+	Page      int
+	Limit     int
+}
+
+// FindCompanies returns the page of companies matching req the way ListCompanies
+// finds them, without their relationships loaded
+func (h *CompanyHandler) FindCompanies(ctx context.Context, req ListCompaniesRequest) (CompanyListPage, error) {
+	page := CompanyListPage{Page: req.Page, Limit: h.pagination.Limit(req.Limit)}
+	if page.Page <= 0 {
+		page.Page = 1
+	}
+
+	db := h.db.WithContext(ctx)
+	if err := companyListQuery(db, req).Count(&page.Total).Error; err != nil {
+		return CompanyListPage{}, err
+	}
+
+	offset := (page.Page - 1) * page.Limit
+	if err := companyListQuery(db, req).Offset(offset).Limit(page.Limit).Order("created_at DESC").Find(&page.Companies).Error; err != nil {
+		return CompanyListPage{}, err
+	}
+	return page, nil
+}
+
 // ListCompanies handles company listing with search and pagination
+//
+// @Summary     List companies
+// @Description Lists companies, optionally filtered by a name or domain search and by verification.
+// @Tags        companies
+// @Produce     json
+// @Param       query query ListCompaniesRequest false "Filters and pagination"
+// @Success     200 {object} object{companies=[]models.Company,pagination=object{page=int,limit=int,total=int,total_pages=int,has_next=bool,has_prev=bool}}
+// @Failure     400 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /companies/ [get]
 func (h *CompanyHandler) ListCompanies(c *gin.Context) {
 	var req ListCompaniesRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "VALIDATION_ERROR",
-				"message":   "Invalid query parameters",
-				"details":   err.Error(),
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrValidationError.WithMessage("Invalid query parameters").WithDetails(utils.FormatValidationErrors(err)).Response(c)
 		return
 	}
 
 	// Validate and set limits
-	if req.Limit <= 0 || req.Limit > 100 {
-		req.Limit = 20
-	}
+	req.Limit = h.pagination.Limit(req.Limit)
 	if req.Page <= 0 {
 		req.Page = 1
 	}
 
 	// Build query
-	query := h.db.Model(&models.Company{}).
+	query := companyListQuery(h.db, req).
 		Preload("Applications").
 		Preload("Members").
 		Preload("Members.User")
 
-	// Apply filters
-	if req.Verified != nil {
-		query = query.Where("is_verified = ?", *req.Verified)
-	}
-
-	if req.Search != "" {
-		searchTerm := strings.TrimSpace(req.Search)
-		if len(searchTerm) > 0 {
-			query = query.Where("LOWER(name) LIKE LOWER(?) OR LOWER(domain) LIKE LOWER(?)",
-				"%"+searchTerm+"%", "%"+searchTerm+"%")
-		}
-	}
-
 	// Get total count
 	var total int64
 	if err := query.Count(&total).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "COUNT_FAILED",
-				"message":   "Failed to count companies",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrCountFailed.WithMessage("Failed to count companies").Response(c)
 		return
 	}
 
@@ -171,13 +262,7 @@ func (h *CompanyHandler) ListCompanies(c *gin.Context) {
 	var companies []models.Company
 	offset := (req.Page - 1) * req.Limit
 	if err := query.Offset(offset).Limit(req.Limit).Order("created_at DESC").Find(&companies).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "QUERY_FAILED",
-				"message":   "Failed to fetch companies",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrQueryFailed.WithMessage("Failed to fetch companies").Response(c)
 		return
 	}
 
@@ -200,18 +285,23 @@ func (h *CompanyHandler) ListCompanies(c *gin.Context) {
 }
 
 // GetCompany handles retrieving a single company by ID
+//
+// @Summary     Get a company
+// @Description Returns a company with its applications, members and assigned bugs.
+// @Tags        companies
+// @Produce     json
+// @Param       id path string true "Company ID" format(uuid)
+// @Success     200 {object} object{company=models.Company}
+// @Failure     400 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /companies/{id} [get]
 func (h *CompanyHandler) GetCompany(c *gin.Context) {
 	companyID := c.Param("id")
 
 	// Validate UUID
 	if _, err := uuid.Parse(companyID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_ID",
-				"message":   "Invalid company ID format",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInvalidID.WithMessage("Invalid company ID format").Response(c)
 		return
 	}
 
@@ -222,23 +312,11 @@ func (h *CompanyHandler) GetCompany(c *gin.Context) {
 		Preload("AssignedBugs").
 		First(&company, "id = ?", companyID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": gin.H{
-					"code":      "COMPANY_NOT_FOUND",
-					"message":   "Company not found",
-					"timestamp": time.Now().UTC(),
-				},
-			})
+			errors.ErrCompanyNotFound.Response(c)
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "QUERY_FAILED",
-				"message":   "Failed to fetch company",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrQueryFailed.WithMessage("Failed to fetch company").Response(c)
 		return
 	}
 
@@ -291,31 +369,37 @@ type ClaimCompanyRequest struct {
 }
 
 // InitiateCompanyClaim handles the initiation of company claiming process
+//
+// @Summary     Claim a company
+// @Description Starts claiming a company by emailing a verification token to an address on the company's domain.
+// @Tags        companies
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Company ID" format(uuid)
+// @Param       request body ClaimCompanyRequest true "Address on the company's domain"
+// @Success     200 {object} object{message=string,verification_token=string}
+// @Failure     400 {object} errors.ErrorResponse "Invalid request, domain mismatch, or the company is already verified"
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     413 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /companies/{id}/claim [post]
 func (h *CompanyHandler) InitiateCompanyClaim(c *gin.Context) {
 	companyID := c.Param("id")
 
 	// Validate UUID
 	if _, err := uuid.Parse(companyID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_ID",
-				"message":   "Invalid company ID format",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInvalidID.WithMessage("Invalid company ID format").Response(c)
 		return
 	}
 
 	var req ClaimCompanyRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "VALIDATION_ERROR",
-				"message":   "Invalid request data",
-				"details":   err.Error(),
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
 		return
 	}
 
@@ -323,13 +407,7 @@ func (h *CompanyHandler) InitiateCompanyClaim(c *gin.Context) {
 	userIDStr, _ := middleware.GetCurrentUserID(c)
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": gin.H{
-				"code":      "UNAUTHORIZED",
-				"message":   "Authentication required",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrUnauthorized.Response(c)
 		return
 	}
 
@@ -337,35 +415,17 @@ func (h *CompanyHandler) InitiateCompanyClaim(c *gin.Context) {
 	var company models.Company
 	if err := h.db.First(&company, "id = ?", companyID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": gin.H{
-					"code":      "COMPANY_NOT_FOUND",
-					"message":   "Company not found",
-					"timestamp": time.Now().UTC(),
-				},
-			})
+			errors.ErrCompanyNotFound.Response(c)
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "QUERY_FAILED",
-				"message":   "Failed to fetch company",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrQueryFailed.WithMessage("Failed to fetch company").Response(c)
 		return
 	}
 
 	// Check if company is already verified
 	if company.IsVerified {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "ALREADY_VERIFIED",
-				"message":   "Company is already verified",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrAlreadyVerified.Response(c)
 		return
 	}
 
@@ -373,56 +433,40 @@ func (h *CompanyHandler) InitiateCompanyClaim(c *gin.Context) {
 	var existingMember models.CompanyMember
 	err = h.db.Where("company_id = ? AND user_id = ?", companyID, userID).First(&existingMember).Error
 	if err == nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "ALREADY_MEMBER",
-				"message":   "User is already a member of this company",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrAlreadyMember.Response(c)
 		return
 	}
 
 	// Validate email domain matches company domain
 	if !h.isEmailFromDomain(req.Email, company.Domain) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_DOMAIN",
-				"message":   fmt.Sprintf("Email must be from domain: %s", company.Domain),
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInvalidDomain.WithMessage(fmt.Sprintf("Email must be from domain: %s", company.Domain)).Response(c)
 		return
 	}
 
 	// Generate verification token
 	token, err := h.generateVerificationToken()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "TOKEN_GENERATION_FAILED",
-				"message":   "Failed to generate verification token",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrTokenGenerationFailed.WithMessage("Failed to generate verification token").Response(c)
 		return
 	}
 
 	// Update company with verification details
+	expiresAt := time.Now().Add(models.CompanyVerificationTokenTTL)
 	if err := h.db.Model(&company).Updates(models.Company{
-		VerificationToken: &token,
-		VerificationEmail: &req.Email,
+		VerificationToken:          &token,
+		VerificationEmail:          &req.Email,
+		VerificationTokenExpiresAt: &expiresAt,
 	}).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "UPDATE_FAILED",
-				"message":   "Failed to initiate verification process",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrUpdateFailed.WithMessage("Failed to initiate verification process").Response(c)
 		return
 	}
 
+	details := fmt.Sprintf("Company claim initiated with verification email %s", req.Email)
+	if err := createAuditLog(h.db, c, models.AuditActionCompanyClaimInitiate, models.AuditResourceCompany, &company.ID, details, nil, nil); err != nil {
+		// Log error but don't fail the request since the claim was already started
+		logger.FromContext(c.Request.Context()).Error("Failed to log audit action", err)
+	}
+
 	// TODO: Send verification email with token
 	// For now, we'll return the token in the response for testing
 	// In production, this should be sent via email service
@@ -439,31 +483,36 @@ type VerifyCompanyRequest struct {
 }
 
 // CompleteCompanyVerification handles the completion of company verification
+//
+// @Summary     Complete a company claim
+// @Description Verifies a company with the token from the claim email. The claimant becomes its admin, and the company's bugs are assigned to it.
+// @Tags        companies
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Company ID" format(uuid)
+// @Param       request body VerifyCompanyRequest true "Verification token"
+// @Success     200 {object} object{message=string,company=models.Company,dashboard_cache_warmed=bool}
+// @Failure     400 {object} errors.ErrorResponse "Invalid or expired token, or the company is already verified"
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     413 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /companies/{id}/verify [post]
 func (h *CompanyHandler) CompleteCompanyVerification(c *gin.Context) {
 	companyID := c.Param("id")
 
 	// Validate UUID
 	if _, err := uuid.Parse(companyID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_ID",
-				"message":   "Invalid company ID format",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInvalidID.WithMessage("Invalid company ID format").Response(c)
 		return
 	}
 
 	var req VerifyCompanyRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "VALIDATION_ERROR",
-				"message":   "Invalid request data",
-				"details":   err.Error(),
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
 		return
 	}
 
@@ -471,13 +520,7 @@ func (h *CompanyHandler) CompleteCompanyVerification(c *gin.Context) {
 	userIDStr, _ := middleware.GetCurrentUserID(c)
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": gin.H{
-				"code":      "UNAUTHORIZED",
-				"message":   "Authentication required",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrUnauthorized.Response(c)
 		return
 	}
 
@@ -494,74 +537,68 @@ func (h *CompanyHandler) CompleteCompanyVerification(c *gin.Context) {
 	if err := tx.Where("id = ? AND verification_token = ?", companyID, req.Token).First(&company).Error; err != nil {
 		tx.Rollback()
 		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": gin.H{
-					"code":      "INVALID_TOKEN",
-					"message":   "Invalid or expired verification token",
-					"timestamp": time.Now().UTC(),
-				},
-			})
+			// Admin verification clears the token, so say why it no longer works
+			var adminVerified int64
+			if h.db.Model(&models.Company{}).Where("id = ? AND admin_verified = ?", companyID, true).Count(&adminVerified).Error == nil && adminVerified > 0 {
+				errors.ErrAlreadyVerified.WithMessage("Company was verified by an administrator").Response(c)
+				return
+			}
+
+			errors.ErrInvalidToken.WithMessage("Invalid or expired verification token").Response(c)
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "QUERY_FAILED",
-				"message":   "Failed to verify token",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrQueryFailed.WithMessage("Failed to verify token").Response(c)
 		return
 	}
 
 	// Check if already verified
 	if company.IsVerified {
 		tx.Rollback()
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "ALREADY_VERIFIED",
-				"message":   "Company is already verified",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrAlreadyVerified.Response(c)
 		return
 	}
 
-	// Mark company as verified
 	now := time.Now()
-	if err := tx.Model(&company).Updates(models.Company{
-		IsVerified:        true,
-		VerifiedAt:        &now,
-		VerificationToken: nil, // Clear the token
+	if company.IsVerificationTokenExpired(now) {
+		tx.Rollback()
+		errors.ErrTokenExpired.Response(c)
+		return
+	}
+
+	beforeState := newCompanyVerificationAuditState(&company)
+
+	// Mark company as verified and clear the token
+	if err := tx.Model(&company).Updates(map[string]interface{}{
+		"is_verified":                   true,
+		"verified_at":                   now,
+		"verification_token":            nil,
+		"verification_token_expires_at": nil,
 	}).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "VERIFICATION_FAILED",
-				"message":   "Failed to complete verification",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrVerificationFailed.Response(c)
 		return
 	}
 
-	// Add user as company admin
-	companyMember := models.CompanyMember{
-		CompanyID: company.ID,
-		UserID:    userID,
-		Role:      "admin",
-		AddedAt:   now,
+	// Add user as company admin, promoting them if they are already a member
+	// (e.g. when re-verifying after a domain change)
+	var companyMember models.CompanyMember
+	err = tx.Where("company_id = ? AND user_id = ?", company.ID, userID).First(&companyMember).Error
+	if err == nil {
+		err = tx.Model(&companyMember).Update("role", "admin").Error
+	} else if err == gorm.ErrRecordNotFound {
+		companyMember = models.CompanyMember{
+			CompanyID: company.ID,
+			UserID:    userID,
+			Role:      "admin",
+			AddedAt:   now,
+		}
+		err = tx.Create(&companyMember).Error
 	}
 
-	if err := tx.Create(&companyMember).Error; err != nil {
+	if err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "MEMBER_CREATION_FAILED",
-				"message":   "Failed to add user as company member",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrMemberCreationFailed.WithMessage("Failed to add user as company member").Response(c)
 		return
 	}
 
@@ -571,41 +608,23 @@ func (h *CompanyHandler) CompleteCompanyVerification(c *gin.Context) {
 			"%"+company.Domain+"%", "%"+company.Name+"%").
 		Update("company_id", company.ID).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "APPLICATION_UPDATE_FAILED",
-				"message":   "Failed to associate applications with company",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrApplicationUpdateFailed.Response(c)
 		return
 	}
 
 	// Update bug reports to be assigned to this company
+	companyApplications := tx.Model(&models.Application{}).Select("id").Where("company_id = ?", company.ID)
 	if err := tx.Model(&models.BugReport{}).
-		Joins("JOIN applications ON applications.id = bug_reports.application_id").
-		Where("applications.company_id = ? AND bug_reports.assigned_company_id IS NULL", company.ID).
+		Where("application_id IN (?) AND assigned_company_id IS NULL", companyApplications).
 		Update("assigned_company_id", company.ID).Error; err != nil {
 		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "BUG_ASSIGNMENT_FAILED",
-				"message":   "Failed to assign bug reports to company",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrBugAssignmentFailed.Response(c)
 		return
 	}
 
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "COMMIT_FAILED",
-				"message":   "Failed to complete verification process",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrCommitFailed.WithMessage("Failed to complete verification process").Response(c)
 		return
 	}
 
@@ -614,22 +633,500 @@ func (h *CompanyHandler) CompleteCompanyVerification(c *gin.Context) {
 		Preload("Members").
 		Preload("Members.User").
 		First(&company, company.ID).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "LOAD_FAILED",
-				"message":   "Verification completed but failed to load company details",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrLoadFailed.WithMessage("Verification completed but failed to load company details").Response(c)
 		return
 	}
 
+	details := fmt.Sprintf("Company claim completed, user %s added as admin", userID)
+	if err := createAuditLog(h.db, c, models.AuditActionCompanyClaimComplete, models.AuditResourceCompany, &company.ID, details,
+		beforeState, newCompanyVerificationAuditState(&company)); err != nil {
+		// Log error but don't fail the request since the company was already verified
+		logger.FromContext(c.Request.Context()).Error("Failed to log audit action", err)
+	}
+
+	// Newly verified companies usually open their dashboard straight away, so warm
+	// its statistics in the background. The frontend polls the dashboard until
+	// bug_stats_cached is true when the cache is not warm yet.
+	h.enqueueDashboardCacheWarm(company.ID.String())
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Company verification completed successfully",
-		"company": company,
+		"message":                "Company verification completed successfully",
+		"company":                company,
+		"dashboard_cache_warmed": h.dashboardStatsCached(c.Request.Context(), company.ID.String()),
+	})
+}
+
+// domainPattern matches a bare, lowercase domain name such as example.com
+var domainPattern = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,}$`)
+
+// memberDomain returns the domain new team members must belong to. While a domain
+// change is pending, members are checked against the pending domain.
+func (h *CompanyHandler) memberDomain(company *models.Company) string {
+	if company.PendingDomain != nil {
+		return *company.PendingDomain
+	}
+	return company.Domain
+}
+
+// ResendVerification issues a new verification token for a pending company claim and
+// emails it to the address the claim was started with. The address must still belong
+// to the company's domain.
+//
+// @Summary     Resend a claim verification
+// @Description Issues a new verification token for a pending claim and emails it to the address the claim was started with.
+// @Tags        companies
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Company ID" format(uuid)
+// @Success     200 {object} object{message=string,expires_at=string}
+// @Failure     400 {object} errors.ErrorResponse "No pending claim, or the company is already verified"
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /companies/{id}/resend-verification [post]
+func (h *CompanyHandler) ResendVerification(c *gin.Context) {
+	companyID := c.Param("id")
+
+	// Validate UUID
+	if _, err := uuid.Parse(companyID); err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid company ID format").Response(c)
+		return
+	}
+
+	if _, ok := currentUserUUID(c); !ok {
+		return
+	}
+
+	var company models.Company
+	if err := h.db.First(&company, "id = ?", companyID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrCompanyNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch company").Response(c)
+		return
+	}
+
+	if company.IsVerified {
+		errors.ErrAlreadyVerified.Response(c)
+		return
+	}
+
+	if company.VerificationEmail == nil {
+		errors.ErrNoPendingVerification.Response(c)
+		return
+	}
+
+	// The domain may have changed since the claim was started
+	if !h.isEmailFromDomain(*company.VerificationEmail, company.Domain) {
+		errors.ErrInvalidDomain.WithMessage(fmt.Sprintf("Email must be from domain: %s", company.Domain)).Response(c)
+		return
+	}
+
+	token, err := h.generateVerificationToken()
+	if err != nil {
+		errors.ErrTokenGenerationFailed.WithMessage("Failed to generate verification token").Response(c)
+		return
+	}
+	expiresAt := time.Now().Add(models.CompanyVerificationTokenTTL)
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&company).Updates(map[string]interface{}{
+			"verification_token":            token,
+			"verification_token_expires_at": expiresAt,
+		}).Error; err != nil {
+			return err
+		}
+		return models.EnqueueOutboxEvent(tx, models.OutboxEventEmail, h.verificationEmail(&company, token, expiresAt))
+	})
+	if err != nil {
+		errors.ErrResendFailed.Response(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Verification email sent. Please check your email and follow the instructions.",
+		"expires_at": expiresAt.UTC(),
+	})
+}
+
+// verificationEmail builds the email sent to complete a company claim
+func (h *CompanyHandler) verificationEmail(company *models.Company, token string, expiresAt time.Time) models.EmailPayload {
+	verifyURL := fmt.Sprintf("%s/companies/%s/verify?token=%s", h.frontendURL, company.ID, url.QueryEscape(token))
+
+	return models.EmailPayload{
+		To:      []string{*company.VerificationEmail},
+		Subject: fmt.Sprintf("Verify your claim to %s on BugRelay", company.Name),
+		Body: fmt.Sprintf("Confirm that you manage %s on BugRelay.\n\n"+
+			"Verify your claim: %s\n\n"+
+			"This link expires on %s.\n",
+			company.Name, verifyURL, expiresAt.UTC().Format("January 2, 2006 15:04 MST")),
+	}
+}
+
+// DomainChangeRequest represents the request to change a company's domain
+type DomainChangeRequest struct {
+	NewDomain string `json:"new_domain" binding:"required"`
+	// Email is an address at the new domain the confirmation token is sent to
+	Email string `json:"email" binding:"required,email"`
+}
+
+// InitiateDomainChange starts changing a company's domain. A token is emailed to an
+// address at the new domain, and the new domain replaces the current one once it is
+// confirmed with that token.
+//
+// @Summary     Change a company's domain
+// @Description Starts changing a company's domain. Company admins only. A confirmation token is emailed to the given address at the new domain, and the new domain replaces the current one once confirmed with it. The token expires after 24 hours.
+// @Tags        companies
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Company ID" format(uuid)
+// @Param       request body DomainChangeRequest true "New domain"
+// @Success     200 {object} object{message=string,pending_domain=string,expires_at=string}
+// @Failure     400 {object} errors.ErrorResponse "Invalid or unchanged domain, or an email outside the new domain"
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     409 {object} errors.ErrorResponse "Domain used by another company"
+// @Failure     413 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /companies/{id}/domain-change [post]
+func (h *CompanyHandler) InitiateDomainChange(c *gin.Context) {
+	companyID := c.Param("id")
+
+	// Validate UUID
+	if _, err := uuid.Parse(companyID); err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid company ID format").Response(c)
+		return
+	}
+
+	var req DomainChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+
+	newDomain := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(req.NewDomain)), "www.")
+	if !domainPattern.MatchString(newDomain) {
+		errors.ErrInvalidDomain.WithMessage("New domain must be a valid domain name, e.g. example.com").Response(c)
+		return
+	}
+
+	if !h.isEmailFromDomain(req.Email, newDomain) {
+		errors.ErrInvalidDomain.WithMessage(fmt.Sprintf("Email must be from domain: %s", newDomain)).Response(c)
+		return
+	}
+
+	company, ok := h.loadCompanyForAdmin(c, companyID, "Only company admins can change the company domain")
+	if !ok {
+		return
+	}
+
+	if newDomain == strings.ToLower(company.Domain) {
+		errors.ErrSameDomain.Response(c)
+		return
+	}
+
+	// The new domain must not belong to, or be pending for, another company
+	var conflicts int64
+	if err := h.db.Model(&models.Company{}).
+		Where("id <> ? AND (LOWER(domain) = ? OR LOWER(pending_domain) = ?)", company.ID, newDomain, newDomain).
+		Count(&conflicts).Error; err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to check domain availability").Response(c)
+		return
+	}
+	if conflicts > 0 {
+		errors.ErrDomainTaken.WithMessage(fmt.Sprintf("Domain %s is already registered to another company", newDomain)).Response(c)
+		return
+	}
+
+	// Generate verification token
+	token, err := h.generateVerificationToken()
+	if err != nil {
+		errors.ErrTokenGenerationFailed.WithMessage("Failed to generate verification token").Response(c)
+		return
+	}
+
+	expiresAt := time.Now().Add(models.CompanyVerificationTokenTTL)
+
+	// The token is only sent to the new domain, so confirming it proves control of it
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(company).Updates(models.Company{
+			PendingDomain:                      &newDomain,
+			PendingDomainEmail:                 &req.Email,
+			PendingDomainVerificationToken:     &token,
+			PendingDomainVerificationExpiresAt: &expiresAt,
+		}).Error; err != nil {
+			return err
+		}
+		return models.EnqueueOutboxEvent(tx, models.OutboxEventEmail, h.domainChangeEmail(company, token, expiresAt))
+	})
+	if err != nil {
+		errors.ErrUpdateFailed.WithMessage("Failed to initiate domain change").Response(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":        fmt.Sprintf("Domain change initiated. Confirm it with the token sent to %s.", req.Email),
+		"pending_domain": newDomain,
+		"expires_at":     expiresAt.UTC(),
+	})
+}
+
+// domainChangeEmail builds the email sent to the new domain to confirm a pending
+// domain change
+func (h *CompanyHandler) domainChangeEmail(company *models.Company, token string, expiresAt time.Time) models.EmailPayload {
+	confirmURL := fmt.Sprintf("%s/companies/%s/domain-change/confirm?token=%s", h.frontendURL, company.ID, url.QueryEscape(token))
+
+	return models.EmailPayload{
+		To:      []string{*company.PendingDomainEmail},
+		Subject: fmt.Sprintf("Confirm %s as the domain of %s on BugRelay", *company.PendingDomain, company.Name),
+		Body: fmt.Sprintf("An admin of %s on BugRelay asked to change its domain to %s.\n\n"+
+			"Confirm the change: %s\n\n"+
+			"This link expires on %s. If you did not expect this email, you can ignore it.\n",
+			company.Name, *company.PendingDomain, confirmURL, expiresAt.UTC().Format("January 2, 2006 15:04 MST")),
+	}
+}
+
+// ConfirmDomainChangeRequest represents the request to confirm a domain change
+type ConfirmDomainChangeRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ConfirmDomainChange swaps in a company's pending domain. The company becomes
+// unverified and must complete the verification flow again for the new domain.
+//
+// @Summary     Confirm a domain change
+// @Description Swaps in the pending domain. Company admins only. The token is the one emailed to the new domain. The company has to complete verification again for the new domain, with a verification email sent to the same address.
+// @Tags        companies
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Company ID" format(uuid)
+// @Param       request body ConfirmDomainChangeRequest true "Token from the domain change"
+// @Success     200 {object} object{message=string,previous_domain=string,domain=string,expires_at=string}
+// @Failure     400 {object} errors.ErrorResponse "Invalid request or token, or an expired token"
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     413 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /companies/{id}/domain-change/confirm [post]
+func (h *CompanyHandler) ConfirmDomainChange(c *gin.Context) {
+	companyID := c.Param("id")
+
+	// Validate UUID
+	if _, err := uuid.Parse(companyID); err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid company ID format").Response(c)
+		return
+	}
+
+	var req ConfirmDomainChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+
+	company, ok := h.loadCompanyForAdmin(c, companyID, "Only company admins can change the company domain")
+	if !ok {
+		return
+	}
+
+	if company.PendingDomain == nil || company.PendingDomainEmail == nil || company.PendingDomainVerificationToken == nil ||
+		subtle.ConstantTimeCompare([]byte(*company.PendingDomainVerificationToken), []byte(req.Token)) != 1 {
+		errors.ErrInvalidToken.WithMessage("Invalid or expired domain change token").Response(c)
+		return
+	}
+	if company.IsPendingDomainTokenExpired(time.Now()) {
+		errors.ErrTokenExpired.WithMessage("Domain change token has expired. Start the domain change again").Response(c)
+		return
+	}
+
+	// Start the verification flow again for the new domain
+	verificationToken, err := h.generateVerificationToken()
+	if err != nil {
+		errors.ErrTokenGenerationFailed.WithMessage("Failed to generate verification token").Response(c)
+		return
+	}
+
+	previousDomain := company.Domain
+	newDomain := *company.PendingDomain
+	verificationEmail := *company.PendingDomainEmail
+	expiresAt := time.Now().Add(models.CompanyVerificationTokenTTL)
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(company).Updates(map[string]interface{}{
+			"domain":                                 newDomain,
+			"pending_domain":                         nil,
+			"pending_domain_email":                   nil,
+			"pending_domain_verification_token":      nil,
+			"pending_domain_verification_expires_at": nil,
+			"is_verified":                            false,
+			"verified_at":                            nil,
+			"admin_verified":                         false,
+			"admin_verified_by":                      nil,
+			"verification_email":                     verificationEmail,
+			"verification_token":                     verificationToken,
+			"verification_token_expires_at":          expiresAt,
+			"updated_at":                             time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+		return models.EnqueueOutboxEvent(tx, models.OutboxEventEmail, h.verificationEmail(company, verificationToken, expiresAt))
+	})
+	if err != nil {
+		errors.ErrUpdateFailed.WithMessage("Failed to confirm domain change").Response(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":         fmt.Sprintf("Domain changed. Complete verification with the email sent to %s.", verificationEmail),
+		"previous_domain": previousDomain,
+		"domain":          newDomain,
+		"expires_at":      expiresAt.UTC(),
 	})
 }
 
+// CreateAssignmentRuleRequest represents the request to create a bug assignment rule
+type CreateAssignmentRuleRequest struct {
+	ApplicationID  uuid.UUID  `json:"application_id" binding:"required"`
+	Tags           []string   `json:"tags"`
+	AssigneeUserID *uuid.UUID `json:"assignee_user_id"`
+	PriorityFilter string     `json:"priority_filter"`
+	PriorityOrder  int        `json:"priority_order"`
+}
+
+// CreateAssignmentRule creates a rule that assigns new bugs for one of the
+// company's applications to a team member
+//
+// @Summary     Create an assignment rule
+// @Description Creates a rule that assigns new bugs for one of the company's applications to a team member. Company admins only.
+// @Tags        companies
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Company ID" format(uuid)
+// @Param       request body CreateAssignmentRuleRequest true "Rule"
+// @Success     201 {object} object{message=string,rule=models.BugAssignmentRule}
+// @Failure     400 {object} errors.ErrorResponse "Invalid application, assignee or priority"
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     413 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /companies/{id}/assignment-rules [post]
+func (h *CompanyHandler) CreateAssignmentRule(c *gin.Context) {
+	companyID := c.Param("id")
+
+	// Validate UUID
+	if _, err := uuid.Parse(companyID); err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid company ID format").Response(c)
+		return
+	}
+
+	var req CreateAssignmentRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+
+	if req.PriorityFilter != "" && !models.IsValidPriority(req.PriorityFilter) {
+		errors.ErrInvalidPriority.WithMessage("Invalid priority filter").Response(c)
+		return
+	}
+
+	company, ok := h.loadCompanyForAdmin(c, companyID, "Only company admins can configure assignment rules")
+	if !ok {
+		return
+	}
+
+	// The application must belong to the company
+	var application models.Application
+	if err := h.db.Where("id = ? AND company_id = ?", req.ApplicationID, company.ID).First(&application).Error; err != nil {
+		errors.ErrInvalidApplication.Response(c)
+		return
+	}
+
+	// The assignee must be a member of the company
+	if req.AssigneeUserID != nil {
+		var assignee models.CompanyMember
+		if err := h.db.Where("company_id = ? AND user_id = ?", company.ID, *req.AssigneeUserID).First(&assignee).Error; err != nil {
+			errors.ErrInvalidAssignee.Response(c)
+			return
+		}
+	}
+
+	tags := make([]string, 0, len(req.Tags))
+	for _, tag := range req.Tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	rule := models.BugAssignmentRule{
+		CompanyID:      company.ID,
+		ApplicationID:  application.ID,
+		Tags:           pq.StringArray(tags),
+		AssigneeUserID: req.AssigneeUserID,
+		PriorityFilter: req.PriorityFilter,
+		PriorityOrder:  req.PriorityOrder,
+	}
+
+	if err := h.db.Create(&rule).Error; err != nil {
+		errors.ErrRuleCreationFailed.Response(c)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Assignment rule created successfully",
+		"rule":    rule,
+	})
+}
+
+// loadCompanyForAdmin loads a company and checks that the current user is one of
+// its admins. It writes the error response and returns false on failure.
+func (h *CompanyHandler) loadCompanyForAdmin(c *gin.Context, companyID, forbiddenMessage string) (*models.Company, bool) {
+	// Get current user
+	userIDStr, _ := middleware.GetCurrentUserID(c)
+	currentUserID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		errors.ErrUnauthorized.Response(c)
+		return nil, false
+	}
+
+	// Check if current user is admin of the company
+	var currentMember models.CompanyMember
+	if err := h.db.Where("company_id = ? AND user_id = ? AND role = ?",
+		companyID, currentUserID, "admin").First(&currentMember).Error; err != nil {
+		errors.ErrInsufficientPermissions.WithMessage(forbiddenMessage).Response(c)
+		return nil, false
+	}
+
+	// Find company
+	var company models.Company
+	if err := h.db.First(&company, "id = ?", companyID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrCompanyNotFound.Response(c)
+			return nil, false
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch company").Response(c)
+		return nil, false
+	}
+
+	return &company, true
+}
+
 // AddTeamMemberRequest represents the request to add a team member
 type AddTeamMemberRequest struct {
 	Email string `json:"email" binding:"required,email"`
@@ -637,31 +1134,38 @@ type AddTeamMemberRequest struct {
 }
 
 // AddTeamMember handles adding a team member to a company
+//
+// @Summary     Add a team member
+// @Description Adds a registered user with an address on the company's domain to the company. Company admins only.
+// @Tags        companies
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Company ID" format(uuid)
+// @Param       request body AddTeamMemberRequest true "Member email and role"
+// @Success     201 {object} object{message=string,member=models.CompanyMember}
+// @Failure     400 {object} errors.ErrorResponse "Invalid request, role or domain, or already a member"
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse "Company or user not found"
+// @Failure     413 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /companies/{id}/members [post]
 func (h *CompanyHandler) AddTeamMember(c *gin.Context) {
 	companyID := c.Param("id")
 
 	// Validate UUID
 	if _, err := uuid.Parse(companyID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_ID",
-				"message":   "Invalid company ID format",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInvalidID.WithMessage("Invalid company ID format").Response(c)
 		return
 	}
 
 	var req AddTeamMemberRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "VALIDATION_ERROR",
-				"message":   "Invalid request data",
-				"details":   err.Error(),
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
 		return
 	}
 
@@ -669,13 +1173,7 @@ func (h *CompanyHandler) AddTeamMember(c *gin.Context) {
 	userIDStr, _ := middleware.GetCurrentUserID(c)
 	currentUserID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": gin.H{
-				"code":      "UNAUTHORIZED",
-				"message":   "Authentication required",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrUnauthorized.Response(c)
 		return
 	}
 
@@ -683,13 +1181,7 @@ func (h *CompanyHandler) AddTeamMember(c *gin.Context) {
 	var currentMember models.CompanyMember
 	if err := h.db.Where("company_id = ? AND user_id = ? AND role = ?",
 		companyID, currentUserID, "admin").First(&currentMember).Error; err != nil {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": gin.H{
-				"code":      "INSUFFICIENT_PERMISSIONS",
-				"message":   "Only company admins can add team members",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInsufficientPermissions.WithMessage("Only company admins can add team members").Response(c)
 		return
 	}
 
@@ -697,35 +1189,19 @@ func (h *CompanyHandler) AddTeamMember(c *gin.Context) {
 	var company models.Company
 	if err := h.db.First(&company, "id = ?", companyID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": gin.H{
-					"code":      "COMPANY_NOT_FOUND",
-					"message":   "Company not found",
-					"timestamp": time.Now().UTC(),
-				},
-			})
+			errors.ErrCompanyNotFound.Response(c)
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "QUERY_FAILED",
-				"message":   "Failed to fetch company",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrQueryFailed.WithMessage("Failed to fetch company").Response(c)
 		return
 	}
 
-	// Validate email domain matches company domain (for verified companies)
-	if company.IsVerified && !h.isEmailFromDomain(req.Email, company.Domain) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_DOMAIN",
-				"message":   fmt.Sprintf("Email must be from domain: %s", company.Domain),
-				"timestamp": time.Now().UTC(),
-			},
-		})
+	// Validate email domain matches company domain (for verified companies, or the
+	// pending domain while a domain change is in progress)
+	memberDomain := h.memberDomain(&company)
+	if (company.IsVerified || company.PendingDomain != nil) && !h.isEmailFromDomain(req.Email, memberDomain) {
+		errors.ErrInvalidDomain.WithMessage(fmt.Sprintf("Email must be from domain: %s", memberDomain)).Response(c)
 		return
 	}
 
@@ -733,23 +1209,11 @@ func (h *CompanyHandler) AddTeamMember(c *gin.Context) {
 	var user models.User
 	if err := h.db.Where("email = ?", req.Email).First(&user).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": gin.H{
-					"code":      "USER_NOT_FOUND",
-					"message":   "User with this email not found. They must register first.",
-					"timestamp": time.Now().UTC(),
-				},
-			})
+			errors.ErrUserNotFound.WithMessage("User with this email not found. They must register first.").Response(c)
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "QUERY_FAILED",
-				"message":   "Failed to find user",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrQueryFailed.WithMessage("Failed to find user").Response(c)
 		return
 	}
 
@@ -757,13 +1221,7 @@ func (h *CompanyHandler) AddTeamMember(c *gin.Context) {
 	var existingMember models.CompanyMember
 	err = h.db.Where("company_id = ? AND user_id = ?", companyID, user.ID).First(&existingMember).Error
 	if err == nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "ALREADY_MEMBER",
-				"message":   "User is already a member of this company",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrAlreadyMember.Response(c)
 		return
 	}
 
@@ -775,13 +1233,7 @@ func (h *CompanyHandler) AddTeamMember(c *gin.Context) {
 
 	// Validate role
 	if role != "admin" && role != "member" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_ROLE",
-				"message":   "Role must be 'admin' or 'member'",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInvalidRole.WithMessage("Role must be 'admin' or 'member'").Response(c)
 		return
 	}
 
@@ -794,25 +1246,20 @@ func (h *CompanyHandler) AddTeamMember(c *gin.Context) {
 	}
 
 	if err := h.db.Create(&companyMember).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "MEMBER_CREATION_FAILED",
-				"message":   "Failed to add team member",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrMemberCreationFailed.Response(c)
 		return
 	}
 
+	details := fmt.Sprintf("User %s added to the company as %s", user.ID, role)
+	if err := createAuditLog(h.db, c, models.AuditActionMemberAdd, models.AuditResourceCompanyMember, &companyMember.ID, details,
+		nil, memberRoleAuditState{Role: role}); err != nil {
+		// Log error but don't fail the request since the member was already added
+		logger.FromContext(c.Request.Context()).Error("Failed to log audit action", err)
+	}
+
 	// Load member with user details
 	if err := h.db.Preload("User").First(&companyMember, companyMember.ID).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "LOAD_FAILED",
-				"message":   "Member added but failed to load details",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrLoadFailed.WithMessage("Member added but failed to load details").Response(c)
 		return
 	}
 
@@ -828,44 +1275,45 @@ type RemoveTeamMemberRequest struct {
 }
 
 // RemoveTeamMember handles removing a team member from a company
+//
+// @Summary     Remove a team member
+// @Description Removes a member from the company. Company admins can remove anyone, and members can remove themselves. The last admin cannot be removed.
+// @Tags        companies
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Company ID" format(uuid)
+// @Param       request body RemoveTeamMemberRequest true "Member to remove"
+// @Success     200 {object} object{message=string}
+// @Failure     400 {object} errors.ErrorResponse "Invalid request, or the member is the last admin"
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     413 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /companies/{id}/members [delete]
 func (h *CompanyHandler) RemoveTeamMember(c *gin.Context) {
 	companyID := c.Param("id")
 
 	// Validate UUID
 	if _, err := uuid.Parse(companyID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_ID",
-				"message":   "Invalid company ID format",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInvalidID.WithMessage("Invalid company ID format").Response(c)
 		return
 	}
 
 	var req RemoveTeamMemberRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "VALIDATION_ERROR",
-				"message":   "Invalid request data",
-				"details":   err.Error(),
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
 		return
 	}
 
 	// Validate target user UUID
 	targetUserID, err := uuid.Parse(req.UserID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_USER_ID",
-				"message":   "Invalid user ID format",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInvalidUserID.Response(c)
 		return
 	}
 
@@ -873,13 +1321,7 @@ func (h *CompanyHandler) RemoveTeamMember(c *gin.Context) {
 	userIDStr, _ := middleware.GetCurrentUserID(c)
 	currentUserID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": gin.H{
-				"code":      "UNAUTHORIZED",
-				"message":   "Authentication required",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrUnauthorized.Response(c)
 		return
 	}
 
@@ -887,25 +1329,13 @@ func (h *CompanyHandler) RemoveTeamMember(c *gin.Context) {
 	var currentMember models.CompanyMember
 	if err := h.db.Where("company_id = ? AND user_id = ?",
 		companyID, currentUserID).First(&currentMember).Error; err != nil {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": gin.H{
-				"code":      "NOT_MEMBER",
-				"message":   "User is not a member of this company",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrNotMember.Response(c)
 		return
 	}
 
 	// Only admins can remove others, but anyone can remove themselves
 	if currentUserID != targetUserID && currentMember.Role != "admin" {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": gin.H{
-				"code":      "INSUFFICIENT_PERMISSIONS",
-				"message":   "Only company admins can remove other team members",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInsufficientPermissions.WithMessage("Only company admins can remove other team members").Response(c)
 		return
 	}
 
@@ -914,23 +1344,11 @@ func (h *CompanyHandler) RemoveTeamMember(c *gin.Context) {
 	if err := h.db.Where("company_id = ? AND user_id = ?",
 		companyID, targetUserID).First(&memberToRemove).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": gin.H{
-					"code":      "MEMBER_NOT_FOUND",
-					"message":   "Team member not found",
-					"timestamp": time.Now().UTC(),
-				},
-			})
+			errors.ErrMemberNotFound.Response(c)
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "QUERY_FAILED",
-				"message":   "Failed to find team member",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrQueryFailed.WithMessage("Failed to find team member").Response(c)
 		return
 	}
 
@@ -940,58 +1358,215 @@ func (h *CompanyHandler) RemoveTeamMember(c *gin.Context) {
 		if err := h.db.Model(&models.CompanyMember{}).
 			Where("company_id = ? AND role = ?", companyID, "admin").
 			Count(&adminCount).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": gin.H{
-					"code":      "COUNT_FAILED",
-					"message":   "Failed to check admin count",
-					"timestamp": time.Now().UTC(),
-				},
-			})
+			errors.ErrCountFailed.WithMessage("Failed to check admin count").Response(c)
 			return
 		}
 
 		if adminCount <= 1 {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": gin.H{
-					"code":      "LAST_ADMIN",
-					"message":   "Cannot remove the last admin from the company",
-					"timestamp": time.Now().UTC(),
-				},
-			})
+			errors.ErrLastAdmin.WithMessage("Cannot remove the last admin from the company").Response(c)
 			return
 		}
 	}
 
 	// Remove the member
 	if err := h.db.Delete(&memberToRemove).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "REMOVAL_FAILED",
-				"message":   "Failed to remove team member",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrRemovalFailed.Response(c)
 		return
 	}
 
+	details := fmt.Sprintf("User %s removed from the company", targetUserID)
+	if currentUserID == targetUserID {
+		details = fmt.Sprintf("User %s left the company", targetUserID)
+	}
+	if err := createAuditLog(h.db, c, models.AuditActionMemberRemove, models.AuditResourceCompanyMember, &memberToRemove.ID, details,
+		memberRoleAuditState{Role: memberToRemove.Role}, nil); err != nil {
+		// Log error but don't fail the request since the member was already removed
+		logger.FromContext(c.Request.Context()).Error("Failed to log audit action", err)
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Team member removed successfully",
 	})
 }
 
+// UpdateMemberRoleRequest represents the request to change a team member's role
+type UpdateMemberRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// memberRoleAuditState is the audit log snapshot of a member's role
+type memberRoleAuditState struct {
+	Role string `json:"role"`
+}
+
+// UpdateMemberRole changes a team member's role. Only company admins can change
+// roles, the last admin cannot be demoted, and admins cannot change their own role.
+//
+// @Summary     Change a member's role
+// @Description Changes a team member's role. Company admins only. The last admin cannot be demoted, and admins cannot change their own role.
+// @Tags        companies
+// @Accept      json
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Company ID" format(uuid)
+// @Param       user_id path string true "Member's user ID" format(uuid)
+// @Param       request body UpdateMemberRoleRequest true "New role: admin or member"
+// @Success     200 {object} object{message=string,member=models.CompanyMember}
+// @Failure     400 {object} errors.ErrorResponse "Invalid request or role, or the member is the last admin"
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     413 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /companies/{id}/members/{user_id}/role [patch]
+func (h *CompanyHandler) UpdateMemberRole(c *gin.Context) {
+	companyID := c.Param("id")
+
+	// Validate UUIDs
+	if _, err := uuid.Parse(companyID); err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid company ID format").Response(c)
+		return
+	}
+
+	targetUserID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		errors.ErrInvalidUserID.Response(c)
+		return
+	}
+
+	var req UpdateMemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+
+	if !models.IsValidCompanyRole(req.Role) {
+		errors.ErrInvalidRole.WithMessage("Role must be 'admin', 'member' or 'viewer'").Response(c)
+		return
+	}
+
+	if _, ok := h.loadCompanyForAdmin(c, companyID, "Only company admins can change member roles"); !ok {
+		return
+	}
+	currentUserID, _ := currentUserUUID(c)
+
+	// Find the member to update
+	var member models.CompanyMember
+	if err := h.db.Where("company_id = ? AND user_id = ?", companyID, targetUserID).First(&member).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrMemberNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to find team member").Response(c)
+		return
+	}
+
+	if targetUserID == currentUserID {
+		errors.ErrCannotChangeOwnRole.Response(c)
+		return
+	}
+
+	previousRole := member.Role
+	if previousRole != req.Role {
+		// The admins are counted and the role changed in one transaction, with the admin
+		// rows locked, so two admins demoting each other at once cannot leave the company
+		// without one
+		lastAdmin := false
+		err := h.db.Transaction(func(tx *gorm.DB) error {
+			if previousRole == models.CompanyRoleAdmin {
+				var adminIDs []uuid.UUID
+				if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+					Model(&models.CompanyMember{}).
+					Where("company_id = ? AND role = ?", companyID, models.CompanyRoleAdmin).
+					Pluck("id", &adminIDs).Error; err != nil {
+					return err
+				}
+				if len(adminIDs) <= 1 {
+					lastAdmin = true
+					return nil
+				}
+			}
+			return tx.Model(&member).Update("role", req.Role).Error
+		})
+		if err != nil {
+			errors.ErrUpdateFailed.WithMessage("Failed to update member role").Response(c)
+			return
+		}
+		if lastAdmin {
+			errors.ErrLastAdmin.WithMessage("Cannot demote the last admin of the company").Response(c)
+			return
+		}
+
+		details := fmt.Sprintf("Company member role changed from %s to %s", previousRole, req.Role)
+		if err := createAuditLog(h.db, c, models.AuditActionMemberRoleChange, models.AuditResourceCompanyMember, &member.ID, details,
+			memberRoleAuditState{Role: previousRole}, memberRoleAuditState{Role: req.Role}); err != nil {
+			// Log error but don't fail the request since the role was already updated
+			logger.FromContext(c.Request.Context()).Error("Failed to log audit action", err)
+		}
+	}
+
+	// Load member with user details
+	if err := h.db.Preload("User").First(&member, "id = ?", member.ID).Error; err != nil {
+		errors.ErrLoadFailed.WithMessage("Role updated but failed to load member details").Response(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Member role updated successfully",
+		"member":  member,
+	})
+}
+
+// priorityWeight is a bug's priority as a number, from 4 for critical down to 1 for low
+var priorityWeight = fmt.Sprintf(
+	"CASE bug_reports.priority WHEN '%s' THEN 4 WHEN '%s' THEN 3 WHEN '%s' THEN 2 WHEN '%s' THEN 1 ELSE 0 END",
+	models.BugPriorityCritical, models.BugPriorityHigh, models.BugPriorityMedium, models.BugPriorityLow,
+)
+
+// priorityWeightOrder orders bugs from critical down to low priority
+var priorityWeightOrder = priorityWeight + " DESC"
+
 // GetCompanyDashboard handles retrieving company dashboard data
+//
+// @Summary     Get a company dashboard
+// @Description Returns a company's bug and application statistics with its most recent bugs. Company members only.
+// @Tags        companies
+// @Produce     json
+// @Security    BearerAuth
+// @Param       id path string true "Company ID" format(uuid)
+// @Param       period query string false "Statistics period: all, 7d or 30d" default(all)
+// @Param       sort query string false "Recent bugs order: recent, oldest, priority or popular" default(recent)
+// @Param       status_filter query string false "Only list recent bugs with this status"
+// @Success     200 {object} object{company=models.Company,user_role=string,bug_stats=object,bug_stats_cached=bool,stats_period=string,app_stats=object,recent_bugs=[]models.BugReport}
+// @Failure     400 {object} errors.ErrorResponse "Invalid ID, period or status"
+// @Failure     401 {object} errors.ErrorResponse
+// @Failure     403 {object} errors.ErrorResponse
+// @Failure     404 {object} errors.ErrorResponse
+// @Failure     500 {object} errors.ErrorResponse
+// @Router      /companies/{id}/dashboard [get]
 func (h *CompanyHandler) GetCompanyDashboard(c *gin.Context) {
 	companyID := c.Param("id")
 
 	// Validate UUID
 	if _, err := uuid.Parse(companyID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_ID",
-				"message":   "Invalid company ID format",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrInvalidID.WithMessage("Invalid company ID format").Response(c)
+		return
+	}
+
+	sort := c.DefaultQuery("sort", "recent")
+	statusFilter := c.Query("status_filter")
+	if statusFilter != "" && !models.IsValidStatus(statusFilter) {
+		errors.ErrInvalidStatus.WithMessage("Invalid status_filter value").Response(c)
+		return
+	}
+
+	period := c.DefaultQuery("period", dashboardPeriodAll)
+	if _, ok := dashboardStatsPeriods[period]; !ok {
+		errors.ErrInvalidPeriod.WithMessage("Period must be one of all, 7d, 30d").Response(c)
 		return
 	}
 
@@ -999,13 +1574,7 @@ func (h *CompanyHandler) GetCompanyDashboard(c *gin.Context) {
 	userIDStr, _ := middleware.GetCurrentUserID(c)
 	currentUserID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": gin.H{
-				"code":      "UNAUTHORIZED",
-				"message":   "Authentication required",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrUnauthorized.Response(c)
 		return
 	}
 
@@ -1013,13 +1582,7 @@ func (h *CompanyHandler) GetCompanyDashboard(c *gin.Context) {
 	var currentMember models.CompanyMember
 	if err := h.db.Where("company_id = ? AND user_id = ?",
 		companyID, currentUserID).First(&currentMember).Error; err != nil {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": gin.H{
-				"code":      "NOT_MEMBER",
-				"message":   "Access denied. User is not a member of this company",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrNotMember.WithMessage("Access denied. User is not a member of this company").Response(c)
 		return
 	}
 
@@ -1030,50 +1593,157 @@ func (h *CompanyHandler) GetCompanyDashboard(c *gin.Context) {
 		Preload("Members.User").
 		First(&company, "id = ?", companyID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": gin.H{
-					"code":      "COMPANY_NOT_FOUND",
-					"message":   "Company not found",
-					"timestamp": time.Now().UTC(),
-				},
-			})
+			errors.ErrCompanyNotFound.Response(c)
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "QUERY_FAILED",
-				"message":   "Failed to fetch company",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrQueryFailed.WithMessage("Failed to fetch company").Response(c)
+		return
+	}
+
+	// Get bug statistics, from the cache when they have been warmed
+	ctx := c.Request.Context()
+	var dashboardStats companyDashboardStats
+	statsCached := h.cache.GetCompanyDashboardStats(ctx, companyID, &dashboardStats) == nil
+	if !statsCached {
+		if dashboardStats, err = h.loadDashboardStats(companyID); err != nil {
+			errors.ErrStatsFailed.WithMessage("Failed to fetch bug statistics").Response(c)
+			return
+		}
+		if err := h.cache.SetCompanyDashboardStats(ctx, companyID, dashboardStats); err != nil {
+			logger.FromContext(ctx).Error("Failed to cache dashboard statistics", err)
+		}
+	}
+	bugStats := dashboardStats[period]
+
+	// Split application statistics into active and archived applications
+	appStats, err := h.loadApplicationStats(companyID, company.Applications)
+	if err != nil {
+		errors.ErrStatsFailed.WithMessage("Failed to fetch application statistics").Response(c)
+		return
+	}
+
+	// Get recent bugs (last 10). The sort and status filter apply only to this
+	// list; the statistics above always cover every bug.
+	recentBugsQuery := h.db.Where("assigned_company_id = ?", companyID).
+		Preload("Application").
+		Preload("Reporter")
+
+	switch sort {
+	case "oldest":
+		// Oldest unresolved work first
+		if statusFilter == "" {
+			statusFilter = models.BugStatusOpen
+		}
+		recentBugsQuery = recentBugsQuery.Order("created_at ASC")
+	case "priority":
+		recentBugsQuery = recentBugsQuery.Order(priorityWeightOrder).Order("created_at DESC")
+	case "popular":
+		recentBugsQuery = recentBugsQuery.Order("weighted_vote_count DESC").Order("created_at DESC")
+	default:
+		recentBugsQuery = recentBugsQuery.Order("created_at DESC")
+	}
+
+	if statusFilter != "" {
+		recentBugsQuery = recentBugsQuery.Where("status = ?", statusFilter)
+	}
+
+	var recentBugs []models.BugReport
+	if err := recentBugsQuery.Limit(10).Find(&recentBugs).Error; err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to fetch recent bugs").Response(c)
 		return
 	}
 
-	// Get bug statistics
-	var bugStats struct {
-		Total     int64 `json:"total"`
-		Open      int64 `json:"open"`
-		Reviewing int64 `json:"reviewing"`
-		Fixed     int64 `json:"fixed"`
-		WontFix   int64 `json:"wont_fix"`
+	c.JSON(http.StatusOK, gin.H{
+		"company":          company,
+		"user_role":        currentMember.Role,
+		"bug_stats":        bugStats,
+		"bug_stats_cached": statsCached,
+		"stats_period":     period,
+		"app_stats":        appStats,
+		"recent_bugs":      recentBugs,
+	})
+}
+
+// applicationStats counts applications and their bugs for one group of applications
+type applicationStats struct {
+	Applications int64 `json:"applications"`
+	Bugs         int64 `json:"bugs"`
+}
+
+// companyApplicationStats separates a company's active and archived applications
+type companyApplicationStats struct {
+	Active   applicationStats `json:"active"`
+	Archived applicationStats `json:"archived"`
+}
+
+// loadApplicationStats counts a company's applications and bugs, split by archive state
+func (h *CompanyHandler) loadApplicationStats(companyID string, applications []models.Application) (companyApplicationStats, error) {
+	var stats companyApplicationStats
+	for _, app := range applications {
+		if app.IsArchived {
+			stats.Archived.Applications++
+		} else {
+			stats.Active.Applications++
+		}
 	}
 
-	// Total bugs
+	bugCounts := []struct {
+		IsArchived bool
+		Count      int64
+	}{}
+
+	if err := h.db.Model(&models.BugReport{}).
+		Select("applications.is_archived, COUNT(*) as count").
+		Joins("JOIN applications ON applications.id = bug_reports.application_id").
+		Where("bug_reports.assigned_company_id = ?", companyID).
+		Group("applications.is_archived").
+		Scan(&bugCounts).Error; err != nil {
+		return stats, err
+	}
+
+	for _, bc := range bugCounts {
+		if bc.IsArchived {
+			stats.Archived.Bugs += bc.Count
+		} else {
+			stats.Active.Bugs += bc.Count
+		}
+	}
+
+	return stats, nil
+}
+
+// companyBugStats represents bug statistics shown on the company dashboard
+type companyBugStats struct {
+	Total     int64 `json:"total"`
+	Open      int64 `json:"open"`
+	Reviewing int64 `json:"reviewing"`
+	Fixed     int64 `json:"fixed"`
+	WontFix   int64 `json:"wont_fix"`
+}
+
+// addStatusCount adds a per-status count to the statistics
+func (s *companyBugStats) addStatusCount(status string, count int64) {
+	switch status {
+	case models.BugStatusOpen:
+		s.Open += count
+	case models.BugStatusReviewing:
+		s.Reviewing += count
+	case models.BugStatusFixed:
+		s.Fixed += count
+	case models.BugStatusWontFix:
+		s.WontFix += count
+	}
+}
+
+// loadBugStats counts a company's bugs directly from bug_reports
+func (h *CompanyHandler) loadBugStats(companyID string, stats *companyBugStats) error {
 	if err := h.db.Model(&models.BugReport{}).
 		Where("assigned_company_id = ?", companyID).
-		Count(&bugStats.Total).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "STATS_FAILED",
-				"message":   "Failed to fetch bug statistics",
-				"timestamp": time.Now().UTC(),
-			},
-		})
-		return
+		Count(&stats.Total).Error; err != nil {
+		return err
 	}
 
-	// Bugs by status
 	statusCounts := []struct {
 		Status string
 		Count  int64
@@ -1084,52 +1754,50 @@ func (h *CompanyHandler) GetCompanyDashboard(c *gin.Context) {
 		Where("assigned_company_id = ?", companyID).
 		Group("status").
 		Scan(&statusCounts).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "STATS_FAILED",
-				"message":   "Failed to fetch status statistics",
-				"timestamp": time.Now().UTC(),
-			},
-		})
-		return
+		return err
 	}
 
-	// Map status counts
 	for _, sc := range statusCounts {
-		switch sc.Status {
-		case "open":
-			bugStats.Open = sc.Count
-		case "reviewing":
-			bugStats.Reviewing = sc.Count
-		case "fixed":
-			bugStats.Fixed = sc.Count
-		case "wont_fix":
-			bugStats.WontFix = sc.Count
-		}
+		stats.addStatusCount(sc.Status, sc.Count)
 	}
 
-	// Get recent bugs (last 10)
-	var recentBugs []models.BugReport
-	if err := h.db.Where("assigned_company_id = ?", companyID).
-		Preload("Application").
-		Preload("Reporter").
-		Order("created_at DESC").
-		Limit(10).
-		Find(&recentBugs).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "QUERY_FAILED",
-				"message":   "Failed to fetch recent bugs",
-				"timestamp": time.Now().UTC(),
-			},
-		})
-		return
+	return nil
+}
+
+// loadBugStatsSince counts a company's bugs created since the given time
+func (h *CompanyHandler) loadBugStatsSince(companyID string, since time.Time, stats *companyBugStats) error {
+	statusCounts := []struct {
+		Status string
+		Count  int64
+	}{}
+
+	if err := h.db.Model(&models.BugReport{}).
+		Select("status, COUNT(*) as count").
+		Where("assigned_company_id = ? AND created_at >= ?", companyID, since).
+		Group("status").
+		Scan(&statusCounts).Error; err != nil {
+		return err
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"company":     company,
-		"user_role":   currentMember.Role,
-		"bug_stats":   bugStats,
-		"recent_bugs": recentBugs,
-	})
+	for _, sc := range statusCounts {
+		stats.Total += sc.Count
+		stats.addStatusCount(sc.Status, sc.Count)
+	}
+
+	return nil
+}
+
+// loadMaterializedBugStats reads a company's bug counts from the bug_stats_by_company view
+func (h *CompanyHandler) loadMaterializedBugStats(companyID string, stats *companyBugStats) error {
+	var rows []models.BugStatsByCompany
+	if err := h.db.Where("assigned_company_id = ?", companyID).Find(&rows).Error; err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		stats.Total += row.Count
+		stats.addStatusCount(row.Status, row.Count)
+	}
+
+	return nil
 }
@@ -5,7 +5,10 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"bugrelay-backend/internal/models"
 
@@ -19,7 +22,7 @@ import (
 // setupCompanyTestHandler creates a company handler with test database
 func setupCompanyTestHandler(t *testing.T) (*CompanyHandler, *gorm.DB) {
 	db := setupBugTestDB(t) // Reuse the existing test DB setup
-	handler := NewCompanyHandler(db)
+	handler := NewCompanyHandler(db, nil)
 	return handler, db
 }
 
@@ -253,8 +256,10 @@ func TestCompanyHandler_CompleteCompanyVerification(t *testing.T) {
 	company := createTestCompany(t, db, false)
 	token := "test-verification-token"
 	email := "admin@testcompany.com"
+	expiresAt := time.Now().Add(models.CompanyVerificationTokenTTL)
 	company.VerificationToken = &token
 	company.VerificationEmail = &email
+	company.VerificationTokenExpiresAt = &expiresAt
 	require.NoError(t, db.Save(company).Error)
 
 	tests := []struct {
@@ -333,6 +338,115 @@ func TestCompanyHandler_CompleteCompanyVerification(t *testing.T) {
 	}
 }
 
+func TestCompanyHandler_CompleteCompanyVerification_ExpiredToken(t *testing.T) {
+	handler, db := setupCompanyTestHandler(t)
+	user := createTestUser(t, db)
+	company := createTestCompany(t, db, false)
+
+	token := "expired-verification-token"
+	email := "admin@testcompany.com"
+	expiredAt := time.Now().Add(-time.Minute)
+	company.VerificationToken = &token
+	company.VerificationEmail = &email
+	company.VerificationTokenExpiresAt = &expiredAt
+	require.NoError(t, db.Save(company).Error)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(mockAuthMiddleware(user.ID))
+	router.POST("/companies/:id/verify", handler.CompleteCompanyVerification)
+
+	jsonBody, _ := json.Marshal(map[string]string{"token": token})
+	req, _ := http.NewRequest("POST", "/companies/"+company.ID.String()+"/verify", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "TOKEN_EXPIRED")
+
+	var unchanged models.Company
+	require.NoError(t, db.First(&unchanged, "id = ?", company.ID).Error)
+	assert.False(t, unchanged.IsVerified)
+}
+
+func TestCompanyHandler_ResendVerification(t *testing.T) {
+	handler, db := setupCompanyTestHandler(t)
+	user := createTestUser(t, db)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(mockAuthMiddleware(user.ID))
+	router.POST("/companies/:id/resend-verification", handler.ResendVerification)
+
+	resend := func(companyID uuid.UUID) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/companies/"+companyID.String()+"/resend-verification", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("issues a new token and emails it", func(t *testing.T) {
+		company := createTestCompany(t, db, false)
+		token := "old-verification-token"
+		email := "admin@testcompany.com"
+		expiredAt := time.Now().Add(-time.Hour)
+		company.VerificationToken = &token
+		company.VerificationEmail = &email
+		company.VerificationTokenExpiresAt = &expiredAt
+		require.NoError(t, db.Save(company).Error)
+
+		w := resend(company.ID)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "verification_token")
+
+		var updated models.Company
+		require.NoError(t, db.First(&updated, "id = ?", company.ID).Error)
+		require.NotNil(t, updated.VerificationToken)
+		assert.NotEqual(t, token, *updated.VerificationToken)
+		require.NotNil(t, updated.VerificationTokenExpiresAt)
+		assert.False(t, updated.IsVerificationTokenExpired(time.Now()))
+
+		var event models.OutboxEvent
+		require.NoError(t, db.Where("event_type = ?", models.OutboxEventEmail).First(&event).Error)
+		assert.Contains(t, string(event.Payload), email)
+		assert.Contains(t, string(event.Payload), *updated.VerificationToken)
+
+		require.NoError(t, db.Delete(company).Error)
+	})
+
+	t.Run("rejects emails outside the current domain", func(t *testing.T) {
+		company := createTestCompany(t, db, false)
+		email := "admin@previous-domain.com"
+		company.VerificationEmail = &email
+		require.NoError(t, db.Save(company).Error)
+
+		w := resend(company.ID)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_DOMAIN")
+
+		require.NoError(t, db.Delete(company).Error)
+	})
+
+	t.Run("requires a pending verification", func(t *testing.T) {
+		company := createTestCompany(t, db, false)
+
+		w := resend(company.ID)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "NO_PENDING_VERIFICATION")
+
+		require.NoError(t, db.Delete(company).Error)
+	})
+
+	t.Run("rejects verified companies", func(t *testing.T) {
+		company := createTestCompany(t, db, true)
+
+		w := resend(company.ID)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "ALREADY_VERIFIED")
+	})
+}
+
 func TestCompanyHandler_AddTeamMember(t *testing.T) {
 	handler, db := setupCompanyTestHandler(t)
 
@@ -588,6 +702,425 @@ func TestCompanyHandler_GetCompanyDashboard(t *testing.T) {
 	}
 }
 
+func TestCompanyHandler_GetCompanyDashboard_MaterializedStats(t *testing.T) {
+	handler, db := setupCompanyTestHandler(t)
+	handler.SetMaterializedDashboard(true)
+
+	user := createTestUser(t, db)
+	company := createTestCompany(t, db, true)
+	createTestCompanyMember(t, db, company.ID, user.ID, "admin")
+
+	// SQLite has no materialized views, so stand in a table with the same shape
+	require.NoError(t, db.AutoMigrate(&models.BugStatsByCompany{}))
+	require.NoError(t, db.Create(&[]models.BugStatsByCompany{
+		{AssignedCompanyID: &company.ID, Status: models.BugStatusOpen, Count: 3},
+		{AssignedCompanyID: &company.ID, Status: models.BugStatusWontFix, Count: 2},
+	}).Error)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(mockAuthMiddleware(user.ID))
+	router.GET("/companies/:id/dashboard", handler.GetCompanyDashboard)
+
+	req, _ := http.NewRequest("GET", "/companies/"+company.ID.String()+"/dashboard", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	bugStats := response["bug_stats"].(map[string]interface{})
+	assert.Equal(t, float64(5), bugStats["total"])
+	assert.Equal(t, float64(3), bugStats["open"])
+	assert.Equal(t, float64(2), bugStats["wont_fix"])
+}
+
+func TestCompanyHandler_GetCompanyDashboard_ArchivedApplications(t *testing.T) {
+	handler, db := setupCompanyTestHandler(t)
+
+	user := createTestUser(t, db)
+	company := createTestCompany(t, db, true)
+	createTestCompanyMember(t, db, company.ID, user.ID, "admin")
+
+	activeApp := createTestCompanyApplication(t, db, company)
+	archivedApp := createTestCompanyApplication(t, db, company)
+	require.NoError(t, db.Model(archivedApp).Update("is_archived", true).Error)
+
+	for _, app := range []*models.Application{activeApp, activeApp, archivedApp} {
+		bug := createTestBugReport(t, db, app, user)
+		require.NoError(t, db.Model(bug).Update("assigned_company_id", company.ID).Error)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(mockAuthMiddleware(user.ID))
+	router.GET("/companies/:id/dashboard", handler.GetCompanyDashboard)
+
+	req, _ := http.NewRequest("GET", "/companies/"+company.ID.String()+"/dashboard", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	appStats := response["app_stats"].(map[string]interface{})
+	active := appStats["active"].(map[string]interface{})
+	archived := appStats["archived"].(map[string]interface{})
+	assert.Equal(t, float64(1), active["applications"])
+	assert.Equal(t, float64(2), active["bugs"])
+	assert.Equal(t, float64(1), archived["applications"])
+	assert.Equal(t, float64(1), archived["bugs"])
+}
+
+func TestCompanyHandler_GetCompanyDashboard_RecentBugsSort(t *testing.T) {
+	handler, db := setupCompanyTestHandler(t)
+
+	user := createTestUser(t, db)
+	company := createTestCompany(t, db, true)
+	createTestCompanyMember(t, db, company.ID, user.ID, "admin")
+	app := createTestCompanyApplication(t, db, company)
+
+	base := time.Now().Add(-time.Hour)
+	bugs := map[string]*models.BugReport{}
+	for i, spec := range []struct {
+		title    string
+		status   string
+		priority string
+		votes    int
+	}{
+		{"oldest fixed", models.BugStatusFixed, models.BugPriorityCritical, 1},
+		{"old open", models.BugStatusOpen, models.BugPriorityLow, 7},
+		{"reviewing", models.BugStatusReviewing, models.BugPriorityHigh, 3},
+		{"new open", models.BugStatusOpen, models.BugPriorityMedium, 0},
+	} {
+		bug := createTestBugReport(t, db, app, user)
+		require.NoError(t, db.Model(bug).Updates(map[string]interface{}{
+			"title":               spec.title,
+			"status":              spec.status,
+			"priority":            spec.priority,
+			"vote_count":          spec.votes,
+			"weighted_vote_count": spec.votes,
+			"assigned_company_id": company.ID,
+			"created_at":          base.Add(time.Duration(i) * time.Minute),
+		}).Error)
+		bugs[spec.title] = bug
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(mockAuthMiddleware(user.ID))
+	router.GET("/companies/:id/dashboard", handler.GetCompanyDashboard)
+
+	dashboard := func(query string) (int, map[string]interface{}, []string) {
+		req, _ := http.NewRequest("GET", "/companies/"+company.ID.String()+"/dashboard?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+		var titles []string
+		if recent, ok := response["recent_bugs"].([]interface{}); ok {
+			for _, bug := range recent {
+				titles = append(titles, bug.(map[string]interface{})["title"].(string))
+			}
+		}
+		return w.Code, response, titles
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{"recent by default", "", []string{"new open", "reviewing", "old open", "oldest fixed"}},
+		{"recent", "sort=recent", []string{"new open", "reviewing", "old open", "oldest fixed"}},
+		{"oldest open bugs", "sort=oldest", []string{"old open", "new open"}},
+		{"priority", "sort=priority", []string{"oldest fixed", "reviewing", "new open", "old open"}},
+		{"popular", "sort=popular", []string{"old open", "reviewing", "oldest fixed", "new open"}},
+		{"unknown sort falls back to recent", "sort=random", []string{"new open", "reviewing", "old open", "oldest fixed"}},
+		{"status filter", "status_filter=reviewing", []string{"reviewing"}},
+		{"status filter replaces the oldest default", "sort=oldest&status_filter=fixed", []string{"oldest fixed"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, response, titles := dashboard(tt.query)
+			require.Equal(t, http.StatusOK, status)
+			assert.Equal(t, tt.expected, titles)
+
+			// Statistics always cover every bug
+			bugStats := response["bug_stats"].(map[string]interface{})
+			assert.Equal(t, float64(4), bugStats["total"])
+			assert.Equal(t, float64(2), bugStats["open"])
+			assert.Equal(t, float64(1), bugStats["reviewing"])
+			assert.Equal(t, float64(1), bugStats["fixed"])
+		})
+	}
+
+	t.Run("invalid status filter", func(t *testing.T) {
+		status, response, _ := dashboard("status_filter=closed")
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, "INVALID_STATUS", response["error"].(map[string]interface{})["code"])
+	})
+}
+
+func TestCompanyHandler_DomainChange(t *testing.T) {
+	handler, db := setupCompanyTestHandler(t)
+
+	company := createTestCompany(t, db, true)
+	otherCompany := &models.Company{ID: uuid.New(), Name: "Other Company", Domain: "taken.com", IsVerified: true}
+	require.NoError(t, db.Create(otherCompany).Error)
+
+	newUser := func(email string) *models.User {
+		user := &models.User{ID: uuid.New(), Email: email, DisplayName: email}
+		require.NoError(t, db.Create(user).Error)
+		return user
+	}
+	admin := newUser("admin@testcompany.com")
+	member := newUser("member@testcompany.com")
+	oldDomainUser := newUser("old@testcompany.com")
+	newDomainUser := newUser("new@newco.com")
+	createTestCompanyMember(t, db, company.ID, admin.ID, "admin")
+	createTestCompanyMember(t, db, company.ID, member.ID, "member")
+
+	gin.SetMode(gin.TestMode)
+	newRouter := func(userID uuid.UUID) *gin.Engine {
+		router := gin.New()
+		router.Use(mockAuthMiddleware(userID))
+		router.POST("/companies/:id/domain-change", handler.InitiateDomainChange)
+		router.POST("/companies/:id/domain-change/confirm", handler.ConfirmDomainChange)
+		router.POST("/companies/:id/members", handler.AddTeamMember)
+		router.POST("/companies/:id/verify", handler.CompleteCompanyVerification)
+		return router
+	}
+	adminRouter := newRouter(admin.ID)
+
+	post := func(router *gin.Engine, path string, body map[string]interface{}) (int, map[string]interface{}) {
+		data, _ := json.Marshal(body)
+		req, _ := http.NewRequest("POST", "/companies/"+company.ID.String()+path, bytes.NewBuffer(data))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w.Code, response
+	}
+	errorCode := func(response map[string]interface{}) interface{} {
+		return response["error"].(map[string]interface{})["code"]
+	}
+	// lastEmail returns the most recent email queued in the outbox
+	lastEmail := func() models.EmailPayload {
+		var event models.OutboxEvent
+		require.NoError(t, db.Where("event_type = ?", models.OutboxEventEmail).Order("created_at DESC").First(&event).Error)
+		var payload models.EmailPayload
+		require.NoError(t, json.Unmarshal(event.Payload, &payload))
+		return payload
+	}
+
+	t.Run("rejects invalid requests", func(t *testing.T) {
+		code, response := post(newRouter(member.ID), "/domain-change", map[string]interface{}{"new_domain": "newco.com", "email": "admin@newco.com"})
+		assert.Equal(t, http.StatusForbidden, code)
+		assert.Equal(t, "INSUFFICIENT_PERMISSIONS", errorCode(response))
+
+		code, response = post(adminRouter, "/domain-change", map[string]interface{}{"new_domain": "not a domain", "email": "admin@newco.com"})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "INVALID_DOMAIN", errorCode(response))
+
+		code, response = post(adminRouter, "/domain-change", map[string]interface{}{"new_domain": "newco.com", "email": "admin@testcompany.com"})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "INVALID_DOMAIN", errorCode(response))
+
+		code, response = post(adminRouter, "/domain-change", map[string]interface{}{"new_domain": "Taken.com", "email": "admin@taken.com"})
+		assert.Equal(t, http.StatusConflict, code)
+		assert.Equal(t, "DOMAIN_TAKEN", errorCode(response))
+	})
+
+	var changeToken string
+	t.Run("initiates domain change", func(t *testing.T) {
+		code, response := post(adminRouter, "/domain-change", map[string]interface{}{"new_domain": "newco.com", "email": "admin@newco.com"})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "newco.com", response["pending_domain"])
+		assert.NotContains(t, response, "verification_token")
+
+		var updated models.Company
+		require.NoError(t, db.First(&updated, company.ID).Error)
+		assert.Equal(t, "testcompany.com", updated.Domain)
+		require.NotNil(t, updated.PendingDomain)
+		assert.Equal(t, "newco.com", *updated.PendingDomain)
+		require.NotNil(t, updated.PendingDomainVerificationToken)
+		require.NotNil(t, updated.PendingDomainVerificationExpiresAt)
+		assert.True(t, updated.PendingDomainVerificationExpiresAt.After(time.Now()))
+		changeToken = *updated.PendingDomainVerificationToken
+
+		email := lastEmail()
+		assert.Equal(t, []string{"admin@newco.com"}, email.To)
+		assert.Contains(t, email.Body, url.QueryEscape(changeToken))
+	})
+
+	t.Run("pending domain is reserved", func(t *testing.T) {
+		var other models.CompanyMember
+		require.NoError(t, db.Create(&models.CompanyMember{CompanyID: otherCompany.ID, UserID: admin.ID, Role: "admin"}).Error)
+		defer db.Where("company_id = ?", otherCompany.ID).Delete(&other)
+
+		data, _ := json.Marshal(map[string]interface{}{"new_domain": "newco.com", "email": "admin@newco.com"})
+		req, _ := http.NewRequest("POST", "/companies/"+otherCompany.ID.String()+"/domain-change", bytes.NewBuffer(data))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		adminRouter.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("team members are checked against the pending domain", func(t *testing.T) {
+		code, response := post(adminRouter, "/members", map[string]interface{}{"email": oldDomainUser.Email})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "INVALID_DOMAIN", errorCode(response))
+
+		code, _ = post(adminRouter, "/members", map[string]interface{}{"email": newDomainUser.Email})
+		assert.Equal(t, http.StatusCreated, code)
+	})
+
+	var verificationToken string
+	t.Run("confirms domain change", func(t *testing.T) {
+		code, response := post(adminRouter, "/domain-change/confirm", map[string]interface{}{"token": "wrong-token"})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "INVALID_TOKEN", errorCode(response))
+
+		require.NoError(t, db.Model(&models.Company{}).Where("id = ?", company.ID).
+			Update("pending_domain_verification_expires_at", time.Now().Add(-time.Minute)).Error)
+		code, response = post(adminRouter, "/domain-change/confirm", map[string]interface{}{"token": changeToken})
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, "TOKEN_EXPIRED", errorCode(response))
+
+		require.NoError(t, db.Model(&models.Company{}).Where("id = ?", company.ID).
+			Update("pending_domain_verification_expires_at", time.Now().Add(time.Hour)).Error)
+		code, response = post(adminRouter, "/domain-change/confirm", map[string]interface{}{"token": changeToken})
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "testcompany.com", response["previous_domain"])
+		assert.Equal(t, "newco.com", response["domain"])
+		assert.NotContains(t, response, "verification_token")
+
+		var updated models.Company
+		require.NoError(t, db.First(&updated, company.ID).Error)
+		assert.Equal(t, "newco.com", updated.Domain)
+		assert.False(t, updated.IsVerified)
+		assert.Nil(t, updated.PendingDomain)
+		assert.Nil(t, updated.PendingDomainEmail)
+		assert.Nil(t, updated.PendingDomainVerificationToken)
+		assert.Nil(t, updated.PendingDomainVerificationExpiresAt)
+		require.NotNil(t, updated.VerificationToken)
+		verificationToken = *updated.VerificationToken
+
+		email := lastEmail()
+		assert.Equal(t, []string{"admin@newco.com"}, email.To)
+		assert.Contains(t, email.Body, url.QueryEscape(verificationToken))
+	})
+
+	t.Run("re-verification keeps existing admin membership", func(t *testing.T) {
+		code, _ := post(adminRouter, "/verify", map[string]interface{}{"token": verificationToken})
+		require.Equal(t, http.StatusOK, code)
+
+		var updated models.Company
+		require.NoError(t, db.First(&updated, company.ID).Error)
+		assert.True(t, updated.IsVerified)
+
+		var memberships int64
+		db.Model(&models.CompanyMember{}).Where("company_id = ? AND user_id = ?", company.ID, admin.ID).Count(&memberships)
+		assert.Equal(t, int64(1), memberships)
+	})
+}
+
+func TestCompanyHandler_CreateAssignmentRule(t *testing.T) {
+	handler, db := setupCompanyTestHandler(t)
+
+	admin := createTestUser(t, db)
+	company := createTestCompany(t, db, true)
+	createTestCompanyMember(t, db, company.ID, admin.ID, "admin")
+	app := createTestCompanyApplication(t, db, company)
+	otherApp := createTestApplication(t, db)
+
+	outsider := &models.User{ID: uuid.New(), Email: "outsider@example.com", DisplayName: "Outsider"}
+	require.NoError(t, db.Create(outsider).Error)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(mockAuthMiddleware(admin.ID))
+	router.POST("/companies/:id/assignment-rules", handler.CreateAssignmentRule)
+
+	tests := []struct {
+		name           string
+		body           map[string]interface{}
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name: "valid rule",
+			body: map[string]interface{}{
+				"application_id":   app.ID,
+				"tags":             []string{"ui", " crash "},
+				"assignee_user_id": admin.ID,
+				"priority_filter":  models.BugPriorityHigh,
+				"priority_order":   1,
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "application from another company",
+			body: map[string]interface{}{
+				"application_id": otherApp.ID,
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "INVALID_APPLICATION",
+		},
+		{
+			name: "assignee outside the company",
+			body: map[string]interface{}{
+				"application_id":   app.ID,
+				"assignee_user_id": outsider.ID,
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "INVALID_ASSIGNEE",
+		},
+		{
+			name: "invalid priority filter",
+			body: map[string]interface{}{
+				"application_id":  app.ID,
+				"priority_filter": "urgent",
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "INVALID_PRIORITY",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.body)
+			req, _ := http.NewRequest("POST", "/companies/"+company.ID.String()+"/assignment-rules", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			if tt.expectedError != "" {
+				assert.Equal(t, tt.expectedError, response["error"].(map[string]interface{})["code"])
+			}
+		})
+	}
+
+	var rule models.BugAssignmentRule
+	require.NoError(t, db.Where("company_id = ?", company.ID).First(&rule).Error)
+	assert.Equal(t, []string{"ui", "crash"}, []string(rule.Tags))
+	assert.Equal(t, 1, rule.PriorityOrder)
+}
+
 func TestCompanyHandler_ExtractDomainFromURL(t *testing.T) {
 	handler, _ := setupCompanyTestHandler(t)
 
@@ -631,6 +1164,26 @@ func TestCompanyHandler_ExtractDomainFromURL(t *testing.T) {
 	}
 }
 
+func TestCompanyHandler_FindOrCreateCompanyFromApplication_URLVariants(t *testing.T) {
+	handler, db := setupCompanyTestHandler(t)
+
+	company, err := handler.findOrCreateCompanyFromApplication(db, "Acme", stringPtr("https://www.acme.com/app"))
+	require.NoError(t, err)
+	assert.Equal(t, "acme.com", company.Domain)
+	assert.Equal(t, "Acme", company.Name)
+
+	for _, variant := range []string{"http://acme.com", "https://ACME.com:443/", "acme.com/pricing?utm_source=ads"} {
+		found, err := handler.findOrCreateCompanyFromApplication(db, "Acme "+variant, stringPtr(variant))
+		require.NoError(t, err)
+		assert.Equal(t, company.ID, found.ID, variant)
+	}
+
+	other, err := handler.findOrCreateCompanyFromApplication(db, "Acme Staging", stringPtr("https://acme.com:8443"))
+	require.NoError(t, err)
+	assert.NotEqual(t, company.ID, other.ID)
+	assert.Equal(t, "acme.com:8443", other.Domain)
+}
+
 func TestCompanyHandler_IsEmailFromDomain(t *testing.T) {
 	handler, _ := setupCompanyTestHandler(t)
 
@@ -846,6 +1399,22 @@ func TestCompanyBugManagement_AddCompanyResponse(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 			expectedError:  "VALIDATION_ERROR",
 		},
+		{
+			name:           "content too short",
+			bugID:          bug.ID.String(),
+			content:        "On it.",
+			userID:         user.ID,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedError:  "COMMENT_TOO_SHORT",
+		},
+		{
+			name:           "content mostly whitespace",
+			bugID:          bug.ID.String(),
+			content:        "Looking into it" + strings.Repeat("\n", 61),
+			userID:         user.ID,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "INVALID_CONTENT",
+		},
 		{
 			name:           "non-existent bug",
 			bugID:          uuid.New().String(),
@@ -969,3 +1538,181 @@ func TestCompanyCreationFromBugSubmission(t *testing.T) {
 		})
 	}
 }
+
+func TestCompanyHandler_UpdateMemberRole(t *testing.T) {
+	handler, db := setupCompanyTestHandler(t)
+	company := createTestCompany(t, db, true)
+
+	admin := &models.User{ID: uuid.New(), Email: "admin@testcompany.com", DisplayName: "Company Admin"}
+	require.NoError(t, db.Create(admin).Error)
+	createTestCompanyMember(t, db, company.ID, admin.ID, models.CompanyRoleAdmin)
+
+	member := &models.User{ID: uuid.New(), Email: "member@testcompany.com", DisplayName: "Company Member"}
+	require.NoError(t, db.Create(member).Error)
+	createTestCompanyMember(t, db, company.ID, member.ID, models.CompanyRoleMember)
+
+	gin.SetMode(gin.TestMode)
+	updateRole := func(actorID, targetID uuid.UUID, role string) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(mockAuthMiddleware(actorID))
+		router.PATCH("/companies/:id/members/:user_id/role", handler.UpdateMemberRole)
+
+		jsonBody, _ := json.Marshal(map[string]string{"role": role})
+		req, _ := http.NewRequest("PATCH", "/companies/"+company.ID.String()+"/members/"+targetID.String()+"/role", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	roleOf := func(userID uuid.UUID) string {
+		var companyMember models.CompanyMember
+		require.NoError(t, db.Where("company_id = ? AND user_id = ?", company.ID, userID).First(&companyMember).Error)
+		return companyMember.Role
+	}
+
+	t.Run("only admins can change roles", func(t *testing.T) {
+		w := updateRole(member.ID, admin.ID, models.CompanyRoleViewer)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "INSUFFICIENT_PERMISSIONS")
+	})
+
+	t.Run("rejects invalid roles", func(t *testing.T) {
+		w := updateRole(admin.ID, member.ID, "owner")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_ROLE")
+	})
+
+	t.Run("the only admin cannot change their own role", func(t *testing.T) {
+		w := updateRole(admin.ID, admin.ID, models.CompanyRoleMember)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "CANNOT_CHANGE_OWN_ROLE")
+		assert.Equal(t, models.CompanyRoleAdmin, roleOf(admin.ID))
+	})
+
+	t.Run("promotes a member to admin", func(t *testing.T) {
+		w := updateRole(admin.ID, member.ID, models.CompanyRoleAdmin)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Member models.CompanyMember `json:"member"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, models.CompanyRoleAdmin, response.Member.Role)
+		assert.Equal(t, member.Email, response.Member.User.Email)
+		assert.Equal(t, models.CompanyRoleAdmin, roleOf(member.ID))
+
+		var auditLog models.AuditLog
+		require.NoError(t, db.Where("action = ?", models.AuditActionMemberRoleChange).First(&auditLog).Error)
+		require.NotNil(t, auditLog.BeforeState)
+		require.NotNil(t, auditLog.AfterState)
+		assert.JSONEq(t, `{"role":"member"}`, string(*auditLog.BeforeState))
+		assert.JSONEq(t, `{"role":"admin"}`, string(*auditLog.AfterState))
+	})
+
+	t.Run("admins cannot change their own role", func(t *testing.T) {
+		// Another admin exists now, so this is not the last admin guard
+		w := updateRole(admin.ID, admin.ID, models.CompanyRoleViewer)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "CANNOT_CHANGE_OWN_ROLE")
+		assert.Equal(t, models.CompanyRoleAdmin, roleOf(admin.ID))
+	})
+
+	t.Run("admins demoting each other leave one admin", func(t *testing.T) {
+		// The member demotes the admin while the admin's request to demote the member
+		// is counting admins, as when both are sent at once
+		demoted := false
+		require.NoError(t, db.Callback().Query().Before("gorm:query").Register("test:concurrent_demotion", func(tx *gorm.DB) {
+			if demoted || tx.Statement.Table != "company_members" || tx.Statement.Clauses["FOR"].Expression == nil {
+				return
+			}
+			demoted = true
+			require.NoError(t, tx.Session(&gorm.Session{NewDB: true}).
+				Exec("UPDATE company_members SET role = ? WHERE company_id = ? AND user_id = ?", models.CompanyRoleMember, company.ID, admin.ID).Error)
+		}))
+
+		w := updateRole(admin.ID, member.ID, models.CompanyRoleMember)
+		assert.True(t, demoted)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "LAST_ADMIN")
+		assert.Equal(t, models.CompanyRoleAdmin, roleOf(member.ID))
+	})
+}
+
+func TestCompanyHandler_AuditTrail(t *testing.T) {
+	handler, db := setupCompanyTestHandler(t)
+	user := createTestUser(t, db)
+	company := createTestCompany(t, db, false)
+
+	developer := &models.User{ID: uuid.New(), Email: "dev@testcompany.com", DisplayName: "Developer"}
+	require.NoError(t, db.Create(developer).Error)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(mockAuthMiddleware(user.ID))
+	router.POST("/companies/:id/claim", handler.InitiateCompanyClaim)
+	router.POST("/companies/:id/verify", handler.CompleteCompanyVerification)
+	router.POST("/companies/:id/members", handler.AddTeamMember)
+	router.DELETE("/companies/:id/members", handler.RemoveTeamMember)
+
+	send := func(method, path string, body map[string]string) map[string]interface{} {
+		jsonBody, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, "/companies/"+company.ID.String()+path, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", "Audit-Test-Agent")
+		req.RemoteAddr = "203.0.113.7:4321"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Less(t, w.Code, 300, w.Body.String())
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	claim := send("POST", "/claim", map[string]string{"email": "admin@testcompany.com"})
+	send("POST", "/verify", map[string]string{"token": claim["verification_token"].(string)})
+	added := send("POST", "/members", map[string]string{"email": developer.Email})
+	send("DELETE", "/members", map[string]string{"user_id": developer.ID.String()})
+
+	memberID, err := uuid.Parse(added["member"].(map[string]interface{})["id"].(string))
+	require.NoError(t, err)
+
+	tests := []struct {
+		action     string
+		resource   string
+		resourceID uuid.UUID
+		details    string
+	}{
+		{models.AuditActionCompanyClaimInitiate, models.AuditResourceCompany, company.ID, "Company claim initiated with verification email admin@testcompany.com"},
+		{models.AuditActionCompanyClaimComplete, models.AuditResourceCompany, company.ID, "Company claim completed, user " + user.ID.String() + " added as admin"},
+		{models.AuditActionMemberAdd, models.AuditResourceCompanyMember, memberID, "User " + developer.ID.String() + " added to the company as member"},
+		{models.AuditActionMemberRemove, models.AuditResourceCompanyMember, memberID, "User " + developer.ID.String() + " removed from the company"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			var auditLog models.AuditLog
+			require.NoError(t, db.Where("action = ?", tt.action).First(&auditLog).Error)
+			assert.Equal(t, tt.resource, auditLog.Resource)
+			require.NotNil(t, auditLog.ResourceID)
+			assert.Equal(t, tt.resourceID, *auditLog.ResourceID)
+			assert.Equal(t, tt.details, auditLog.Details)
+			assert.Equal(t, user.ID, auditLog.UserID)
+			require.NotNil(t, auditLog.IPAddress)
+			assert.Equal(t, "203.0.113.7", *auditLog.IPAddress)
+			require.NotNil(t, auditLog.UserAgent)
+			assert.Equal(t, "Audit-Test-Agent", *auditLog.UserAgent)
+		})
+	}
+
+	// Verification and member changes record the state they changed
+	var completed models.AuditLog
+	require.NoError(t, db.Where("action = ?", models.AuditActionCompanyClaimComplete).First(&completed).Error)
+	require.NotNil(t, completed.AfterState)
+	assert.Contains(t, string(*completed.AfterState), `"is_verified":true`)
+
+	var removed models.AuditLog
+	require.NoError(t, db.Where("action = ?", models.AuditActionMemberRemove).First(&removed).Error)
+	require.NotNil(t, removed.BeforeState)
+	assert.JSONEq(t, `{"role":"member"}`, string(*removed.BeforeState))
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"bugrelay-backend/internal/cache"
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ListCompanyBugsRequest represents query parameters for listing a company's bugs
+type ListCompanyBugsRequest struct {
+	Page       int    `form:"page,default=1"`
+	Limit      int    `form:"limit,default=20"`
+	Status     string `form:"status"`
+	Priority   string `form:"priority"`
+	Tags       string `form:"tags"`
+	AssigneeID string `form:"assignee_id"`
+	SLAStatus  string `form:"sla_status"`
+	Sort       string `form:"sort,default=recent"`
+}
+
+// CompanyBug is a bug report as seen by the members of its assigned company
+type CompanyBug struct {
+	models.BugReport
+	SLAStatus string `json:"sla_status"`
+	// TimeUntilSLABreach is in seconds and negative once breached. It is null for
+	// bugs without an SLA deadline.
+	TimeUntilSLABreach *int64 `json:"time_until_sla_breach"`
+}
+
+// newCompanyBug annotates a bug report with its SLA status at now
+func newCompanyBug(bug models.BugReport, now time.Time) CompanyBug {
+	companyBug := CompanyBug{
+		BugReport: bug,
+		SLAStatus: bug.SLAStatus(now),
+	}
+	if deadline, ok := bug.SLADeadline(); ok {
+		seconds := int64(deadline.Sub(now) / time.Second)
+		companyBug.TimeUntilSLABreach = &seconds
+	}
+	return companyBug
+}
+
+// ListCompanyBugs lists the bugs assigned to a company with SLA information. Bugs
+// can be filtered by status, priority, tags, assignee and SLA status.
+func (h *CompanyHandler) ListCompanyBugs(c *gin.Context) {
+	companyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid company ID format").Response(c)
+		return
+	}
+
+	var req ListCompanyBugsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		errors.ErrValidationError.WithMessage("Invalid query parameters").WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+
+	// Validate and set limits
+	req.Limit = h.pagination.Limit(req.Limit)
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+
+	if req.SLAStatus != "" && !models.IsValidSLAStatus(req.SLAStatus) {
+		errors.ErrInvalidSLAStatus.Response(c)
+		return
+	}
+
+	queryOptions := BugQueryOptions{
+		Status:             req.Status,
+		Priority:           req.Priority,
+		Tags:               req.Tags,
+		CompanyID:          &companyID,
+		SLAStatus:          req.SLAStatus,
+		SpamScoreThreshold: h.spamScoreThreshold,
+	}
+
+	if req.AssigneeID != "" {
+		assigneeID, err := uuid.Parse(req.AssigneeID)
+		if err != nil {
+			errors.ErrInvalidID.WithMessage("Invalid assignee ID format").Response(c)
+			return
+		}
+		queryOptions.AssigneeID = &assigneeID
+	}
+
+	ctx := c.Request.Context()
+
+	type CachedResponse struct {
+		Bugs       []CompanyBug           `json:"bugs"`
+		Pagination map[string]interface{} `json:"pagination"`
+	}
+
+	cacheKey := cache.GenerateCacheKey(
+		req.Page, req.Limit, req.Status, req.Priority, req.Tags,
+		req.AssigneeID, req.SLAStatus, req.Sort,
+	)
+
+	var cachedResp CachedResponse
+	if err := h.cache.GetCompanyBugList(ctx, companyID.String(), cacheKey, &cachedResp); err == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"bugs":       cachedResp.Bugs,
+			"pagination": cachedResp.Pagination,
+		})
+		return
+	}
+
+	query := buildBugQuery(h.db, queryOptions).
+		Preload("Application").
+		Preload("Reporter")
+
+	switch req.Sort {
+	case "popular":
+		query = query.Order("bug_reports.weighted_vote_count DESC").Order("bug_reports.created_at DESC")
+	case "oldest":
+		query = query.Order("bug_reports.created_at ASC")
+	default:
+		query = query.Order("bug_reports.created_at DESC")
+	}
+
+	var total int64
+	if err := buildBugQuery(h.db, queryOptions).Count(&total).Error; err != nil {
+		errors.ErrCountFailed.WithMessage("Failed to count bug reports").Response(c)
+		return
+	}
+
+	var bugs []models.BugReport
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Offset(offset).Limit(req.Limit).Find(&bugs).Error; err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to fetch bug reports").Response(c)
+		return
+	}
+
+	now := time.Now()
+	companyBugs := make([]CompanyBug, 0, len(bugs))
+	for _, bug := range bugs {
+		companyBugs = append(companyBugs, newCompanyBug(bug, now))
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+	paginationInfo := gin.H{
+		"page":        req.Page,
+		"limit":       req.Limit,
+		"total":       total,
+		"total_pages": totalPages,
+		"has_next":    req.Page < totalPages,
+		"has_prev":    req.Page > 1,
+	}
+
+	if err := h.cache.SetCompanyBugList(ctx, companyID.String(), cacheKey, CachedResponse{
+		Bugs:       companyBugs,
+		Pagination: paginationInfo,
+	}); err != nil {
+		// Log cache error but don't fail the request
+		logger.FromContext(ctx).Error("Failed to cache company bug list", err, logger.Fields{"company_id": companyID.String()})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"bugs":       companyBugs,
+		"pagination": paginationInfo,
+	})
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/middleware"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompanyHandler_ListCompanyBugs(t *testing.T) {
+	handler, db := setupCompanyTestHandler(t)
+	reporter := createTestUser(t, db)
+	company := createTestCompany(t, db, true)
+	app := createTestApplication(t, db)
+
+	member := &models.User{ID: uuid.New(), Email: "member@testcompany.com", DisplayName: "Company Member"}
+	require.NoError(t, db.Create(member).Error)
+	createTestCompanyMember(t, db, company.ID, member.ID, "member")
+
+	outsider := &models.User{ID: uuid.New(), Email: "outsider@example.org", DisplayName: "Outsider"}
+	require.NoError(t, db.Create(outsider).Error)
+
+	now := time.Now()
+	createBug := func(title, priority, status string, age time.Duration, assignee *uuid.UUID) *models.BugReport {
+		bug := &models.BugReport{
+			ID:                uuid.New(),
+			Title:             title,
+			Description:       "SLA test bug",
+			Status:            status,
+			Priority:          priority,
+			ApplicationID:     app.ID,
+			ReporterID:        &reporter.ID,
+			AssignedCompanyID: &company.ID,
+			AssignedMemberID:  assignee,
+			CreatedAt:         now.Add(-age),
+		}
+		require.NoError(t, db.Create(bug).Error)
+		return bug
+	}
+
+	createBug("fresh critical", models.BugPriorityCritical, models.BugStatusOpen, time.Hour, nil)
+	createBug("critical near deadline", models.BugPriorityCritical, models.BugStatusOpen, 20*time.Hour, &member.ID)
+	createBug("breached high", models.BugPriorityHigh, models.BugStatusReviewing, 4*24*time.Hour, &member.ID)
+	createBug("fixed late", models.BugPriorityCritical, models.BugStatusFixed, 3*24*time.Hour, nil)
+
+	// Bugs assigned to other companies are never listed
+	otherCompany := &models.Company{ID: uuid.New(), Name: "Other", Domain: "other.com"}
+	require.NoError(t, db.Create(otherCompany).Error)
+	otherBug := createTestBugReport(t, db, app, reporter)
+	require.NoError(t, db.Model(otherBug).Update("assigned_company_id", otherCompany.ID).Error)
+
+	gin.SetMode(gin.TestMode)
+	listAs := func(userID uuid.UUID, query string) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(mockAuthMiddleware(userID))
+		router.GET("/companies/:id/bugs", middleware.NewCompanyMiddleware(db).RequireCompanyMember(), handler.ListCompanyBugs)
+
+		req, _ := http.NewRequest("GET", "/companies/"+company.ID.String()+"/bugs"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	type listResponse struct {
+		Bugs []struct {
+			Title              string `json:"title"`
+			SLAStatus          string `json:"sla_status"`
+			TimeUntilSLABreach *int64 `json:"time_until_sla_breach"`
+		} `json:"bugs"`
+		Pagination struct {
+			Total int64 `json:"total"`
+		} `json:"pagination"`
+	}
+
+	decode := func(w *httptest.ResponseRecorder) listResponse {
+		var response listResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	t.Run("lists company bugs with SLA status", func(t *testing.T) {
+		w := listAs(member.ID, "")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		response := decode(w)
+		assert.Equal(t, int64(4), response.Pagination.Total)
+		statuses := map[string]string{}
+		for _, bug := range response.Bugs {
+			statuses[bug.Title] = bug.SLAStatus
+			if bug.Title == "fixed late" {
+				assert.Nil(t, bug.TimeUntilSLABreach)
+			} else {
+				require.NotNil(t, bug.TimeUntilSLABreach)
+			}
+			if bug.Title == "breached high" {
+				assert.Less(t, *bug.TimeUntilSLABreach, int64(0))
+			}
+		}
+		assert.Equal(t, map[string]string{
+			"fresh critical":         models.SLAStatusOK,
+			"critical near deadline": models.SLAStatusWarning,
+			"breached high":          models.SLAStatusBreached,
+			"fixed late":             models.SLAStatusOK,
+		}, statuses)
+	})
+
+	t.Run("filters by SLA status", func(t *testing.T) {
+		tests := []struct {
+			slaStatus string
+			want      []string
+		}{
+			{models.SLAStatusOK, []string{"fixed late", "fresh critical"}},
+			{models.SLAStatusWarning, []string{"critical near deadline"}},
+			{models.SLAStatusBreached, []string{"breached high"}},
+		}
+		for _, tt := range tests {
+			w := listAs(member.ID, "?sla_status="+tt.slaStatus+"&sort=oldest")
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var titles []string
+			for _, bug := range decode(w).Bugs {
+				titles = append(titles, bug.Title)
+				assert.Equal(t, tt.slaStatus, bug.SLAStatus)
+			}
+			assert.Equal(t, tt.want, titles, tt.slaStatus)
+		}
+	})
+
+	t.Run("filters by assignee", func(t *testing.T) {
+		w := listAs(member.ID, "?assignee_id="+member.ID.String())
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, int64(2), decode(w).Pagination.Total)
+	})
+
+	t.Run("rejects invalid filters", func(t *testing.T) {
+		w := listAs(member.ID, "?sla_status=late")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_SLA_STATUS")
+
+		w = listAs(member.ID, "?assignee_id=not-a-uuid")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("requires company membership", func(t *testing.T) {
+		w := listAs(outsider.ID, "")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "INSUFFICIENT_PERMISSIONS")
+	})
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"bugrelay-backend/internal/jobs"
+	"bugrelay-backend/internal/logger"
+)
+
+// dashboardPeriodAll is the dashboard statistics period covering every bug
+const dashboardPeriodAll = "all"
+
+// dashboardStatsPeriods maps the company dashboard's statistics periods to their
+// length in days. The "all" period is not limited by creation date.
+var dashboardStatsPeriods = map[string]int{
+	dashboardPeriodAll: 0,
+	"7d":               7,
+	"30d":              30,
+}
+
+// companyDashboardStats holds a company's bug statistics for each dashboard period
+type companyDashboardStats map[string]companyBugStats
+
+// loadDashboardStats counts a company's bugs for every dashboard period
+func (h *CompanyHandler) loadDashboardStats(companyID string) (companyDashboardStats, error) {
+	loadStats := h.loadBugStats
+	if h.materializedDashboard {
+		loadStats = h.loadMaterializedBugStats
+	}
+
+	now := time.Now()
+	stats := make(companyDashboardStats, len(dashboardStatsPeriods))
+	for period, days := range dashboardStatsPeriods {
+		var periodStats companyBugStats
+		var err error
+		if days == 0 {
+			err = loadStats(companyID, &periodStats)
+		} else {
+			err = h.loadBugStatsSince(companyID, now.AddDate(0, 0, -days), &periodStats)
+		}
+		if err != nil {
+			return nil, err
+		}
+		stats[period] = periodStats
+	}
+
+	return stats, nil
+}
+
+// WarmDashboardCache loads a company's dashboard statistics for every period into
+// the cache
+func (h *CompanyHandler) WarmDashboardCache(ctx context.Context, companyID string) error {
+	stats, err := h.loadDashboardStats(companyID)
+	if err != nil {
+		return err
+	}
+	return h.cache.SetCompanyDashboardStats(ctx, companyID, stats)
+}
+
+// enqueueDashboardCacheWarm queues a WarmDashboardCache job for the company on the
+// background queue, if one is configured
+func (h *CompanyHandler) enqueueDashboardCacheWarm(companyID string) {
+	if h.backgroundQueue == nil {
+		return
+	}
+
+	h.backgroundQueue.Enqueue(jobs.Task{
+		Name: "warm_dashboard_cache",
+		Run: func(ctx context.Context) error {
+			return h.WarmDashboardCache(ctx, companyID)
+		},
+	})
+}
+
+// dashboardStatsCached reports whether a company's dashboard statistics are cached
+func (h *CompanyHandler) dashboardStatsCached(ctx context.Context, companyID string) bool {
+	cached, err := h.cache.HasCompanyDashboardStats(ctx, companyID)
+	if err != nil {
+		logger.FromContext(ctx).Error("Failed to check dashboard statistics cache", err)
+	}
+	return cached
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/cache"
+	"bugrelay-backend/internal/jobs"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompanyHandler_CompleteCompanyVerification_EnqueuesDashboardCacheWarm(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupBugTestDB(t)
+	redisClient, mock := newMockRedisClient()
+	handler := NewCompanyHandler(db, redisClient)
+	queue := jobs.NewQueue("low_priority", jobs.DefaultQueueSize)
+	handler.SetBackgroundQueue(queue)
+
+	user := createTestUser(t, db)
+	company := createTestCompany(t, db, false)
+	token := "test-verification-token"
+	expiresAt := time.Now().Add(models.CompanyVerificationTokenTTL)
+	company.VerificationToken = &token
+	company.VerificationTokenExpiresAt = &expiresAt
+	require.NoError(t, db.Save(company).Error)
+
+	router := gin.New()
+	router.Use(mockAuthMiddleware(user.ID))
+	router.POST("/companies/:id/verify", handler.CompleteCompanyVerification)
+
+	body, _ := json.Marshal(map[string]string{"token": token})
+	req, _ := http.NewRequest("POST", "/companies/"+company.ID.String()+"/verify", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, false, response["dashboard_cache_warmed"])
+
+	// The queue is not running, so the job is still waiting
+	assert.Equal(t, 1, queue.Len())
+	assert.NotContains(t, mock.values, cache.CompanyCachePrefix+company.ID.String()+":dashboard_stats")
+}
+
+func TestCompanyHandler_WarmDashboardCache(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupBugTestDB(t)
+	redisClient, mock := newMockRedisClient()
+	handler := NewCompanyHandler(db, redisClient)
+
+	user := createTestUser(t, db)
+	company := createTestCompany(t, db, true)
+	createTestCompanyMember(t, db, company.ID, user.ID, "admin")
+	app := createTestApplication(t, db)
+
+	for _, age := range []time.Duration{time.Hour, 10 * 24 * time.Hour, 60 * 24 * time.Hour} {
+		bug := createTestBugReport(t, db, app, user)
+		bug.AssignedCompanyID = &company.ID
+		bug.CreatedAt = time.Now().Add(-age)
+		require.NoError(t, db.Save(bug).Error)
+	}
+
+	key := cache.CompanyCachePrefix + company.ID.String() + ":dashboard_stats"
+	require.NoError(t, handler.WarmDashboardCache(t.Context(), company.ID.String()))
+	require.Contains(t, mock.values, key)
+	assert.Equal(t, cache.CompanyDashboardStatsCacheDuration, mock.ttls[key])
+
+	var cached companyDashboardStats
+	require.NoError(t, json.Unmarshal([]byte(mock.values[key]), &cached))
+	assert.Equal(t, int64(3), cached["all"].Total)
+	assert.Equal(t, int64(2), cached["30d"].Total)
+	assert.Equal(t, int64(1), cached["7d"].Total)
+
+	router := gin.New()
+	router.Use(mockAuthMiddleware(user.ID))
+	router.GET("/companies/:id/dashboard", handler.GetCompanyDashboard)
+
+	dashboard := func(query string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		req, _ := http.NewRequest("GET", "/companies/"+company.ID.String()+"/dashboard"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w, response
+	}
+
+	t.Run("serves warmed statistics", func(t *testing.T) {
+		// A new bug is not counted until the cached statistics expire
+		bug := createTestBugReport(t, db, app, user)
+		bug.AssignedCompanyID = &company.ID
+		require.NoError(t, db.Save(bug).Error)
+
+		w, response := dashboard("?period=30d")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, true, response["bug_stats_cached"])
+		assert.Equal(t, "30d", response["stats_period"])
+		assert.Equal(t, float64(2), response["bug_stats"].(map[string]interface{})["total"])
+	})
+
+	t.Run("loads and caches statistics on a miss", func(t *testing.T) {
+		delete(mock.values, key)
+
+		w, response := dashboard("")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, false, response["bug_stats_cached"])
+		assert.Equal(t, "all", response["stats_period"])
+		assert.Equal(t, float64(4), response["bug_stats"].(map[string]interface{})["total"])
+		assert.Contains(t, mock.values, key)
+	})
+
+	t.Run("rejects unknown periods", func(t *testing.T) {
+		w, response := dashboard("?period=90d")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "INVALID_PERIOD", response["error"].(map[string]interface{})["code"])
+	})
+}
@@ -0,0 +1,330 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/middleware"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+// dashboardExportColumns are the columns of a company dashboard export, in order
+var dashboardExportColumns = []string{
+	"bug_id", "title", "status", "priority", "reporter", "assignee", "created_at", "resolved_at", "days_open", "sla_status",
+}
+
+// dashboardExportSheet is the name of the worksheet holding the bugs in XLSX exports
+const dashboardExportSheet = "Bugs"
+
+// dashboardExportPriorityColors are the fill colors of priority cells in XLSX exports
+var dashboardExportPriorityColors = map[string]string{
+	models.BugPriorityCritical: "F4B6B6",
+	models.BugPriorityHigh:     "F9D4A8",
+	models.BugPriorityMedium:   "FFF2B3",
+	models.BugPriorityLow:      "CDE8C4",
+}
+
+// nonFilenameChars matches runs of characters left out of export filenames
+var nonFilenameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// dashboardExportRow is a bug as exported from the company dashboard
+type dashboardExportRow struct {
+	ID           uuid.UUID
+	Title        string
+	Status       string
+	Priority     string
+	ReporterName *string
+	AssigneeName *string
+	CreatedAt    time.Time
+	ResolvedAt   *time.Time
+}
+
+// values returns the row's cells in dashboardExportColumns order, escaped so that
+// titles and names are not evaluated as formulas
+func (r dashboardExportRow) values(now time.Time) []string {
+	bug := models.BugReport{Status: r.Status, Priority: r.Priority, CreatedAt: r.CreatedAt, ResolvedAt: r.ResolvedAt}
+
+	// Resolved bugs stopped being open when they were resolved
+	end := now
+	resolvedAt := ""
+	if r.ResolvedAt != nil {
+		end = *r.ResolvedAt
+		resolvedAt = r.ResolvedAt.UTC().Format(time.RFC3339)
+	}
+
+	values := []string{
+		r.ID.String(),
+		r.Title,
+		r.Status,
+		r.Priority,
+		stringValue(r.ReporterName),
+		stringValue(r.AssigneeName),
+		r.CreatedAt.UTC().Format(time.RFC3339),
+		resolvedAt,
+		fmt.Sprint(int(end.Sub(r.CreatedAt).Hours() / 24)),
+		bug.SLAStatus(now),
+	}
+	for i, value := range values {
+		values[i] = escapeCSVFormula(value)
+	}
+	return values
+}
+
+// stringValue returns the string s points to, or an empty string when s is nil
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// ExportCompanyDashboard exports the company's bugs as CSV or XLSX. The time_range
+// parameter (7d, 30d, 90d or all) limits the export to recently created bugs.
+func (h *CompanyHandler) ExportCompanyDashboard(c *gin.Context) {
+	companyID := c.Param("id")
+
+	// Validate UUID
+	companyUUID, err := uuid.Parse(companyID)
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid company ID format").Response(c)
+		return
+	}
+
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "xlsx" {
+		errors.ErrInvalidFormat.WithMessage("Format must be 'csv' or 'xlsx'").Response(c)
+		return
+	}
+
+	timeRange := c.DefaultQuery("time_range", "all")
+	days, ok := statsPeriods[timeRange]
+	if !ok && timeRange != "all" {
+		errors.ErrInvalidPeriod.WithMessage("time_range must be one of 7d, 30d, 90d, all").Response(c)
+		return
+	}
+
+	// Get current user
+	userIDStr, _ := middleware.GetCurrentUserID(c)
+	currentUserID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		errors.ErrUnauthorized.Response(c)
+		return
+	}
+
+	// Check if current user is member of the company
+	var currentMember models.CompanyMember
+	if err := h.db.Where("company_id = ? AND user_id = ?",
+		companyID, currentUserID).First(&currentMember).Error; err != nil {
+		errors.ErrNotMember.WithMessage("Access denied. User is not a member of this company").Response(c)
+		return
+	}
+
+	var company models.Company
+	if err := h.db.First(&company, "id = ?", companyID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrCompanyNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch company").Response(c)
+		return
+	}
+
+	now := time.Now()
+	query := h.db.Model(&models.BugReport{}).
+		Select("bug_reports.id, bug_reports.title, bug_reports.status, bug_reports.priority, "+
+			"reporters.display_name AS reporter_name, assignees.display_name AS assignee_name, "+
+			"bug_reports.created_at, bug_reports.resolved_at").
+		Joins("LEFT JOIN users reporters ON reporters.id = bug_reports.reporter_id").
+		Joins("LEFT JOIN users assignees ON assignees.id = bug_reports.assigned_member_id").
+		Where("bug_reports.assigned_company_id = ?", companyID).
+		Order("bug_reports.created_at DESC")
+	if timeRange != "all" {
+		query = query.Where("bug_reports.created_at >= ?", now.AddDate(0, 0, -days))
+	}
+
+	rows, err := query.Rows()
+	if err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to fetch bug reports").Response(c)
+		return
+	}
+	defer rows.Close()
+
+	// Rows are written as they are read, so the export is never held in memory
+	next := func() (*dashboardExportRow, error) {
+		if !rows.Next() {
+			return nil, rows.Err()
+		}
+		var row dashboardExportRow
+		if err := h.db.ScanRows(rows, &row); err != nil {
+			return nil, err
+		}
+		return &row, nil
+	}
+
+	filename := fmt.Sprintf("bugs-%s-%s.%s", exportFilenamePart(company.Name), now.UTC().Format("2006-01-02"), format)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	var exported int
+	if format == "xlsx" {
+		c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		exported, err = writeDashboardXLSX(c.Writer, next, now)
+	} else {
+		c.Header("Content-Type", "text/csv")
+		c.Status(http.StatusOK)
+		exported, err = writeDashboardCSV(c.Writer, next, now)
+	}
+	if err != nil {
+		// Headers may already be sent, so the export is cut short rather than replaced
+		// with an error response
+		logger.FromContext(c.Request.Context()).Error("Failed to export company dashboard", err, logger.Fields{
+			"company_id": companyID,
+			"format":     format,
+		})
+		if !c.Writer.Written() {
+			errors.ErrQueryFailed.WithMessage("Failed to export bug reports").Response(c)
+		}
+		return
+	}
+
+	details := fmt.Sprintf("Exported %d bugs of %s as %s (time range: %s)", exported, company.Name, format, timeRange)
+	if err := createAuditLog(h.db, c, models.AuditActionCompanyDashboardExport, models.AuditResourceCompany, &companyUUID, details, nil, nil); err != nil {
+		// Log error but don't fail the request since the export was already sent
+		logger.FromContext(c.Request.Context()).Error("Failed to log audit action", err)
+	}
+}
+
+// exportFilenamePart turns a name into lowercase words joined by dashes, safe to use
+// in a filename
+func exportFilenamePart(name string) string {
+	part := strings.Trim(nonFilenameChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if part == "" {
+		return "company"
+	}
+	return part
+}
+
+// writeDashboardCSV writes the rows returned by next as CSV and returns how many
+// were written
+func writeDashboardCSV(w io.Writer, next func() (*dashboardExportRow, error), now time.Time) (int, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(dashboardExportColumns); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for {
+		row, err := next()
+		if err != nil {
+			return count, err
+		}
+		if row == nil {
+			break
+		}
+		if err := writer.Write(row.values(now)); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	writer.Flush()
+	return count, writer.Error()
+}
+
+// writeDashboardXLSX writes the rows returned by next as an XLSX workbook with a
+// color-coded priority column and returns how many were written. The workbook is
+// sent once complete, so an error leaves the response unwritten.
+func writeDashboardXLSX(w io.Writer, next func() (*dashboardExportRow, error), now time.Time) (int, error) {
+	file := excelize.NewFile()
+	defer file.Close()
+
+	if err := file.SetSheetName(file.GetSheetName(0), dashboardExportSheet); err != nil {
+		return 0, err
+	}
+
+	headerStyle, err := file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return 0, err
+	}
+	priorityStyles := make(map[string]int, len(dashboardExportPriorityColors))
+	for priority, color := range dashboardExportPriorityColors {
+		style, err := file.NewStyle(&excelize.Style{
+			Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{color}},
+		})
+		if err != nil {
+			return 0, err
+		}
+		priorityStyles[priority] = style
+	}
+
+	stream, err := file.NewStreamWriter(dashboardExportSheet)
+	if err != nil {
+		return 0, err
+	}
+
+	header := make([]interface{}, len(dashboardExportColumns))
+	for i, column := range dashboardExportColumns {
+		header[i] = excelize.Cell{StyleID: headerStyle, Value: column}
+	}
+	if err := stream.SetRow("A1", header); err != nil {
+		return 0, err
+	}
+
+	priorityColumn := indexOf(dashboardExportColumns, "priority")
+	count := 0
+	for {
+		row, err := next()
+		if err != nil {
+			return count, err
+		}
+		if row == nil {
+			break
+		}
+
+		values := row.values(now)
+		cells := make([]interface{}, len(values))
+		for i, value := range values {
+			cells[i] = value
+		}
+		cells[priorityColumn] = excelize.Cell{StyleID: priorityStyles[row.Priority], Value: row.Priority}
+
+		cell, err := excelize.CoordinatesToCellName(1, count+2)
+		if err != nil {
+			return count, err
+		}
+		if err := stream.SetRow(cell, cells); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	if err := stream.Flush(); err != nil {
+		return count, err
+	}
+	if _, err := file.WriteTo(w); err != nil {
+		return count, err
+	}
+	return count, nil
+}
+
+// indexOf returns the index of value in values, or -1 if it is missing
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestCompanyHandler_ExportCompanyDashboard(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupCompanyTestHandler(t)
+	user := createTestUser(t, db)
+	company := createTestCompany(t, db, true)
+	createTestCompanyMember(t, db, company.ID, user.ID, "admin")
+
+	app := createTestApplication(t, db)
+	app.CompanyID = &company.ID
+	require.NoError(t, db.Save(app).Error)
+
+	recent := createTestBugReport(t, db, app, user)
+	recent.AssignedCompanyID = &company.ID
+	recent.Priority = models.BugPriorityCritical
+	recent.AssignedMemberID = &user.ID
+	require.NoError(t, db.Save(recent).Error)
+
+	createdAt := time.Now().Add(-40 * 24 * time.Hour)
+	resolvedAt := createdAt.Add(30 * 24 * time.Hour)
+	old := createTestBugReport(t, db, app, user)
+	old.AssignedCompanyID = &company.ID
+	old.Status = models.BugStatusFixed
+	old.Title = "Old Bug"
+	old.ResolvedAt = &resolvedAt
+	require.NoError(t, db.Save(old).Error)
+	require.NoError(t, db.Model(old).UpdateColumn("created_at", createdAt).Error)
+
+	// Bugs of other companies are left out
+	createTestBugReport(t, db, app, user)
+
+	export := func(userID uuid.UUID, query string) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(mockAuthMiddleware(userID))
+		router.GET("/companies/:id/dashboard/export", handler.ExportCompanyDashboard)
+
+		req, _ := http.NewRequest("GET", "/companies/"+company.ID.String()+"/dashboard/export?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	date := time.Now().UTC().Format("2006-01-02")
+
+	t.Run("exports CSV", func(t *testing.T) {
+		w := export(user.ID, "format=csv")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="bugs-test-company-`+date+`.csv"`, w.Header().Get("Content-Disposition"))
+
+		records, err := csv.NewReader(w.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+		assert.Equal(t, []string{
+			"bug_id", "title", "status", "priority", "reporter", "assignee", "created_at", "resolved_at", "days_open", "sla_status",
+		}, records[0])
+
+		// Newest bugs come first
+		assert.Equal(t, recent.ID.String(), records[1][0])
+		assert.Equal(t, models.BugPriorityCritical, records[1][3])
+		assert.Equal(t, user.DisplayName, records[1][4])
+		assert.Equal(t, user.DisplayName, records[1][5])
+		assert.Empty(t, records[1][7])
+		assert.Equal(t, "0", records[1][8])
+
+		assert.Equal(t, old.ID.String(), records[2][0])
+		assert.Empty(t, records[2][5])
+		assert.NotEmpty(t, records[2][7])
+		assert.Equal(t, "30", records[2][8])
+		assert.Equal(t, models.SLAStatusOK, records[2][9])
+	})
+
+	t.Run("limits the export to the time range", func(t *testing.T) {
+		w := export(user.ID, "time_range=30d")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		records, err := csv.NewReader(w.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, recent.ID.String(), records[1][0])
+	})
+
+	t.Run("exports XLSX", func(t *testing.T) {
+		w := export(user.ID, "format=xlsx")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, `attachment; filename="bugs-test-company-`+date+`.xlsx"`, w.Header().Get("Content-Disposition"))
+
+		file, err := excelize.OpenReader(bytes.NewReader(w.Body.Bytes()))
+		require.NoError(t, err)
+		defer file.Close()
+
+		assert.Contains(t, file.GetSheetList(), dashboardExportSheet)
+		rows, err := file.GetRows(dashboardExportSheet)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		assert.Equal(t, dashboardExportColumns, rows[0])
+		assert.Equal(t, recent.ID.String(), rows[1][0])
+	})
+
+	t.Run("writes an audit log", func(t *testing.T) {
+		var logs []models.AuditLog
+		require.NoError(t, db.Where("action = ?", models.AuditActionCompanyDashboardExport).Find(&logs).Error)
+		require.Len(t, logs, 3)
+		assert.Equal(t, user.ID, logs[0].UserID)
+		assert.Equal(t, company.ID, *logs[0].ResourceID)
+	})
+
+	t.Run("rejects invalid parameters", func(t *testing.T) {
+		w := export(user.ID, "format=pdf")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_FORMAT")
+
+		w = export(user.ID, "time_range=1y")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_PERIOD")
+	})
+
+	t.Run("requires company membership", func(t *testing.T) {
+		w := export(uuid.New(), "format=csv")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "NOT_MEMBER")
+	})
+}
+
+func TestDashboardExport_EscapesFormulas(t *testing.T) {
+	now := time.Now()
+	reporter := "@reporter"
+	assignee := "+assignee"
+	newRows := func() func() (*dashboardExportRow, error) {
+		rows := []*dashboardExportRow{{
+			ID:           uuid.New(),
+			Title:        `=HYPERLINK("https://evil.example","Click")`,
+			Status:       models.BugStatusOpen,
+			Priority:     models.BugPriorityHigh,
+			ReporterName: &reporter,
+			AssigneeName: &assignee,
+			CreatedAt:    now,
+		}}
+		return func() (*dashboardExportRow, error) {
+			if len(rows) == 0 {
+				return nil, nil
+			}
+			row := rows[0]
+			rows = rows[1:]
+			return row, nil
+		}
+	}
+	want := []string{`'=HYPERLINK("https://evil.example","Click")`, "'@reporter", "'+assignee"}
+
+	t.Run("CSV", func(t *testing.T) {
+		var buf bytes.Buffer
+		count, err := writeDashboardCSV(&buf, newRows(), now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		records, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, want, []string{records[1][1], records[1][4], records[1][5]})
+	})
+
+	t.Run("XLSX", func(t *testing.T) {
+		var buf bytes.Buffer
+		count, err := writeDashboardXLSX(&buf, newRows(), now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		file, err := excelize.OpenReader(&buf)
+		require.NoError(t, err)
+		defer file.Close()
+
+		rows, err := file.GetRows(dashboardExportSheet)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		assert.Equal(t, want, []string{rows[1][1], rows[1][4], rows[1][5]})
+		formula, err := file.GetCellFormula(dashboardExportSheet, "B2")
+		require.NoError(t, err)
+		assert.Empty(t, formula)
+	})
+}
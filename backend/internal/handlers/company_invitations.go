@@ -0,0 +1,295 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/middleware"
+	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxBulkInvitations is the most emails accepted by a single bulk invitation
+	maxBulkInvitations = 50
+	// invitationTTL is how long an invitation can be accepted for
+	invitationTTL = 7 * 24 * time.Hour
+)
+
+// BulkInviteMembersRequest represents the request to invite several team members
+type BulkInviteMembersRequest struct {
+	Emails []string `json:"emails" binding:"required,min=1"`
+	Role   string   `json:"role,omitempty"`
+}
+
+// BulkInviteMembersResponse reports the outcome for each invited email
+type BulkInviteMembersResponse struct {
+	Invited       int      `json:"invited"`
+	AlreadyMember []string `json:"already_member"`
+	InvalidDomain []string `json:"invalid_domain"`
+	InvalidEmail  []string `json:"invalid_email"`
+}
+
+// BulkInviteMembers invites a list of emails to join the company. Emails that
+// cannot be invited are reported back rather than failing the whole request.
+func (h *CompanyHandler) BulkInviteMembers(c *gin.Context) {
+	companyID := c.Param("id")
+
+	// Validate UUID
+	if _, err := uuid.Parse(companyID); err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid company ID format").Response(c)
+		return
+	}
+
+	var req BulkInviteMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+
+	if len(req.Emails) > maxBulkInvitations {
+		errors.ErrTooManyEmails.WithMessage(fmt.Sprintf("At most %d emails can be invited at once", maxBulkInvitations)).Response(c)
+		return
+	}
+
+	// Set default role if not provided
+	role := req.Role
+	if role == "" {
+		role = "member"
+	}
+
+	// Validate role
+	if role != "admin" && role != "member" {
+		errors.ErrInvalidRole.WithMessage("Role must be 'admin' or 'member'").Response(c)
+		return
+	}
+
+	company, ok := h.loadCompanyForAdmin(c, companyID, "Only company admins can invite team members")
+	if !ok {
+		return
+	}
+
+	userIDStr, _ := middleware.GetCurrentUserID(c)
+	currentUserID, _ := uuid.Parse(userIDStr)
+
+	response := BulkInviteMembersResponse{
+		AlreadyMember: []string{},
+		InvalidDomain: []string{},
+		InvalidEmail:  []string{},
+	}
+
+	// Validate email domains the same way as AddTeamMember
+	memberDomain := h.memberDomain(company)
+	checkDomain := company.IsVerified || company.PendingDomain != nil
+
+	tx := h.db.Begin()
+	seen := make(map[string]bool)
+	for _, rawEmail := range req.Emails {
+		email := strings.ToLower(strings.TrimSpace(rawEmail))
+		if seen[email] {
+			continue
+		}
+		seen[email] = true
+
+		if !h.isValidEmail(email) {
+			response.InvalidEmail = append(response.InvalidEmail, rawEmail)
+			continue
+		}
+
+		if checkDomain && !h.isEmailFromDomain(email, memberDomain) {
+			response.InvalidDomain = append(response.InvalidDomain, email)
+			continue
+		}
+
+		var memberCount int64
+		if err := tx.Model(&models.CompanyMember{}).
+			Joins("JOIN users ON users.id = company_members.user_id").
+			Where("company_members.company_id = ? AND LOWER(users.email) = ?", company.ID, email).
+			Count(&memberCount).Error; err != nil {
+			tx.Rollback()
+			h.respondInvitationFailed(c)
+			return
+		}
+		if memberCount > 0 {
+			response.AlreadyMember = append(response.AlreadyMember, email)
+			continue
+		}
+
+		invitation, err := h.findOrCreateInvitation(tx, company.ID, email, role, currentUserID)
+		if err != nil {
+			tx.Rollback()
+			h.respondInvitationFailed(c)
+			return
+		}
+
+		if err := models.EnqueueOutboxEvent(tx, models.OutboxEventEmail, h.invitationEmail(company, invitation)); err != nil {
+			tx.Rollback()
+			h.respondInvitationFailed(c)
+			return
+		}
+
+		response.Invited++
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		h.respondInvitationFailed(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// findOrCreateInvitation returns the company's pending invitation for email,
+// refreshing its role and expiry, or creates a new one. Reusing the invitation
+// keeps links from earlier invitation emails working.
+func (h *CompanyHandler) findOrCreateInvitation(tx *gorm.DB, companyID uuid.UUID, email, role string, invitedByID uuid.UUID) (*models.CompanyInvitation, error) {
+	expiresAt := time.Now().Add(invitationTTL)
+
+	var invitation models.CompanyInvitation
+	err := tx.Where("company_id = ? AND email = ? AND accepted_at IS NULL", companyID, email).First(&invitation).Error
+	if err == nil {
+		if err := tx.Model(&invitation).Updates(map[string]interface{}{
+			"role":          role,
+			"invited_by_id": invitedByID,
+			"expires_at":    expiresAt,
+		}).Error; err != nil {
+			return nil, err
+		}
+		invitation.Role = role
+		invitation.InvitedByID = invitedByID
+		invitation.ExpiresAt = expiresAt
+		return &invitation, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	token, err := h.generateVerificationToken()
+	if err != nil {
+		return nil, err
+	}
+
+	invitation = models.CompanyInvitation{
+		CompanyID:   companyID,
+		Email:       email,
+		Role:        role,
+		Token:       token,
+		InvitedByID: invitedByID,
+		ExpiresAt:   expiresAt,
+	}
+	if err := tx.Create(&invitation).Error; err != nil {
+		return nil, err
+	}
+
+	return &invitation, nil
+}
+
+// invitationEmail builds the email sent to an invited team member
+func (h *CompanyHandler) invitationEmail(company *models.Company, invitation *models.CompanyInvitation) models.EmailPayload {
+	acceptURL := fmt.Sprintf("%s/invite/accept?token=%s", h.frontendURL, url.QueryEscape(invitation.Token))
+
+	return models.EmailPayload{
+		To:      []string{invitation.Email},
+		Subject: fmt.Sprintf("You've been invited to join %s on BugRelay", company.Name),
+		Body: fmt.Sprintf("%s has invited you to join their team on BugRelay as a %s.\n\n"+
+			"Accept the invitation: %s\n\n"+
+			"This invitation expires on %s.\n",
+			company.Name, invitation.Role, acceptURL, invitation.ExpiresAt.UTC().Format("January 2, 2006")),
+	}
+}
+
+// respondInvitationFailed writes the error response for a failed bulk invitation
+func (h *CompanyHandler) respondInvitationFailed(c *gin.Context) {
+	errors.ErrInvitationFailed.Response(c)
+}
+
+// AcceptInvitation adds the invited user to the company. The invitation token is
+// the only credential, so the endpoint does not require authentication; the
+// invited email must however belong to a registered account.
+func (h *CompanyHandler) AcceptInvitation(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		errors.ErrMissingToken.WithMessage("Invitation token is required").Response(c)
+		return
+	}
+
+	var invitation models.CompanyInvitation
+	if err := h.db.Where("token = ?", token).First(&invitation).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrInvitationNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch invitation").Response(c)
+		return
+	}
+
+	if invitation.AcceptedAt != nil {
+		errors.ErrInvitationAlreadyAccepted.Response(c)
+		return
+	}
+
+	if invitation.IsExpired() {
+		errors.ErrInvitationExpired.Response(c)
+		return
+	}
+
+	// Find the invited user
+	var user models.User
+	if err := h.db.Where("LOWER(email) = ?", invitation.Email).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrUserNotFound.WithMessage(fmt.Sprintf("Register an account with %s to accept this invitation", invitation.Email)).Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to find user").Response(c)
+		return
+	}
+
+	tx := h.db.Begin()
+
+	// The user may have been added directly since the invitation was sent
+	var companyMember models.CompanyMember
+	err := tx.Where("company_id = ? AND user_id = ?", invitation.CompanyID, user.ID).First(&companyMember).Error
+	if err == gorm.ErrRecordNotFound {
+		companyMember = models.CompanyMember{
+			CompanyID: invitation.CompanyID,
+			UserID:    user.ID,
+			Role:      invitation.Role,
+			AddedAt:   time.Now(),
+		}
+		err = tx.Create(&companyMember).Error
+	}
+	if err != nil {
+		tx.Rollback()
+		errors.ErrMemberCreationFailed.Response(c)
+		return
+	}
+
+	if err := tx.Model(&invitation).Update("accepted_at", time.Now()).Error; err != nil {
+		tx.Rollback()
+		errors.ErrUpdateFailed.WithMessage("Failed to accept invitation").Response(c)
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		errors.ErrTransactionFailed.Response(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Invitation accepted successfully",
+		"member":  companyMember,
+	})
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompanyHandler_BulkInviteMembers(t *testing.T) {
+	handler, db := setupCompanyTestHandler(t)
+	company := createTestCompany(t, db, true)
+
+	companyAdmin := &models.User{ID: uuid.New(), Email: "admin@testcompany.com", DisplayName: "Company Admin"}
+	require.NoError(t, db.Create(companyAdmin).Error)
+	createTestCompanyMember(t, db, company.ID, companyAdmin.ID, "admin")
+
+	existingMember := &models.User{ID: uuid.New(), Email: "member@testcompany.com", DisplayName: "Existing Member"}
+	require.NoError(t, db.Create(existingMember).Error)
+	createTestCompanyMember(t, db, company.ID, existingMember.ID, "member")
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(mockAuthMiddleware(companyAdmin.ID))
+	router.POST("/companies/:id/members/bulk", handler.BulkInviteMembers)
+
+	invite := func(body map[string]interface{}) *httptest.ResponseRecorder {
+		payload, err := json.Marshal(body)
+		require.NoError(t, err)
+
+		req, _ := http.NewRequest("POST", "/companies/"+company.ID.String()+"/members/bulk", bytes.NewBuffer(payload))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("reports partial success", func(t *testing.T) {
+		w := invite(map[string]interface{}{
+			"emails": []string{
+				"alice@testcompany.com",
+				"Bob@TestCompany.com",
+				"member@testcompany.com",
+				"mallory@othercompany.com",
+				"not-an-email",
+			},
+		})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response BulkInviteMembersResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 2, response.Invited)
+		assert.Equal(t, []string{"member@testcompany.com"}, response.AlreadyMember)
+		assert.Equal(t, []string{"mallory@othercompany.com"}, response.InvalidDomain)
+		assert.Equal(t, []string{"not-an-email"}, response.InvalidEmail)
+
+		var invitations []models.CompanyInvitation
+		require.NoError(t, db.Where("company_id = ?", company.ID).Order("email").Find(&invitations).Error)
+		require.Len(t, invitations, 2)
+		assert.Equal(t, "alice@testcompany.com", invitations[0].Email)
+		assert.Equal(t, "bob@testcompany.com", invitations[1].Email)
+		assert.Equal(t, "member", invitations[0].Role)
+		assert.NotEmpty(t, invitations[0].Token)
+
+		// An invitation email is queued for each invited address
+		var emails int64
+		db.Model(&models.OutboxEvent{}).Where("event_type = ?", models.OutboxEventEmail).Count(&emails)
+		assert.Equal(t, int64(2), emails)
+	})
+
+	t.Run("re-inviting reuses the pending invitation", func(t *testing.T) {
+		var original models.CompanyInvitation
+		require.NoError(t, db.Where("email = ?", "alice@testcompany.com").First(&original).Error)
+
+		w := invite(map[string]interface{}{
+			"emails": []string{"alice@testcompany.com"},
+			"role":   "admin",
+		})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var invitations []models.CompanyInvitation
+		require.NoError(t, db.Where("email = ?", "alice@testcompany.com").Find(&invitations).Error)
+		require.Len(t, invitations, 1)
+		assert.Equal(t, original.Token, invitations[0].Token)
+		assert.Equal(t, "admin", invitations[0].Role)
+	})
+
+	t.Run("rejects more than 50 emails", func(t *testing.T) {
+		emails := make([]string, maxBulkInvitations+1)
+		for i := range emails {
+			emails[i] = fmt.Sprintf("user%d@testcompany.com", i)
+		}
+
+		w := invite(map[string]interface{}{"emails": emails})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		errorData := response["error"].(map[string]interface{})
+		assert.Equal(t, "TOO_MANY_EMAILS", errorData["code"])
+	})
+
+	t.Run("accepts exactly 50 emails", func(t *testing.T) {
+		emails := make([]string, maxBulkInvitations)
+		for i := range emails {
+			emails[i] = fmt.Sprintf("user%d@testcompany.com", i)
+		}
+
+		w := invite(map[string]interface{}{"emails": emails})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response BulkInviteMembersResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, maxBulkInvitations, response.Invited)
+	})
+}
+
+func TestCompanyHandler_BulkInviteMembers_RequiresAdmin(t *testing.T) {
+	handler, db := setupCompanyTestHandler(t)
+	company := createTestCompany(t, db, true)
+
+	member := &models.User{ID: uuid.New(), Email: "member@testcompany.com", DisplayName: "Company Member"}
+	require.NoError(t, db.Create(member).Error)
+	createTestCompanyMember(t, db, company.ID, member.ID, "member")
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(mockAuthMiddleware(member.ID))
+	router.POST("/companies/:id/members/bulk", handler.BulkInviteMembers)
+
+	payload, _ := json.Marshal(map[string]interface{}{"emails": []string{"alice@testcompany.com"}})
+	req, _ := http.NewRequest("POST", "/companies/"+company.ID.String()+"/members/bulk", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestCompanyHandler_AcceptInvitation(t *testing.T) {
+	handler, db := setupCompanyTestHandler(t)
+	company := createTestCompany(t, db, true)
+	inviter := createTestUser(t, db)
+
+	invitee := &models.User{ID: uuid.New(), Email: "alice@testcompany.com", DisplayName: "Alice"}
+	require.NoError(t, db.Create(invitee).Error)
+
+	createInvitation := func(email, token string, expiresAt time.Time) {
+		require.NoError(t, db.Create(&models.CompanyInvitation{
+			CompanyID:   company.ID,
+			Email:       email,
+			Role:        "admin",
+			Token:       token,
+			InvitedByID: inviter.ID,
+			ExpiresAt:   expiresAt,
+		}).Error)
+	}
+	createInvitation("alice@testcompany.com", "valid-token", time.Now().Add(time.Hour))
+	createInvitation("alice@testcompany.com", "expired-token", time.Now().Add(-time.Hour))
+	createInvitation("nobody@testcompany.com", "unregistered-token", time.Now().Add(time.Hour))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/invite/accept", handler.AcceptInvitation)
+
+	tests := []struct {
+		name           string
+		token          string
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "missing token",
+			token:          "",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "MISSING_TOKEN",
+		},
+		{
+			name:           "unknown token",
+			token:          "unknown-token",
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "INVITATION_NOT_FOUND",
+		},
+		{
+			name:           "expired invitation",
+			token:          "expired-token",
+			expectedStatus: http.StatusGone,
+			expectedError:  "INVITATION_EXPIRED",
+		},
+		{
+			name:           "invitee without an account",
+			token:          "unregistered-token",
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "USER_NOT_FOUND",
+		},
+		{
+			name:           "valid invitation",
+			token:          "valid-token",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invitation cannot be reused",
+			token:          "valid-token",
+			expectedStatus: http.StatusConflict,
+			expectedError:  "INVITATION_ALREADY_ACCEPTED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", "/invite/accept?token="+tt.token, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+			if tt.expectedError != "" {
+				errorData := response["error"].(map[string]interface{})
+				assert.Equal(t, tt.expectedError, errorData["code"])
+				return
+			}
+
+			var member models.CompanyMember
+			require.NoError(t, db.Where("company_id = ? AND user_id = ?", company.ID, invitee.ID).First(&member).Error)
+			assert.Equal(t, "admin", member.Role)
+		})
+	}
+}
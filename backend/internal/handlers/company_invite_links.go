@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// defaultInviteLinkMaxBypasses is how many people outside the company's domain an
+// invite link can admit unless configured otherwise
+const defaultInviteLinkMaxBypasses = 3
+
+// CreateInviteLinkRequest represents the request to create a company invite link
+type CreateInviteLinkRequest struct {
+	Role string `json:"role,omitempty"`
+	// BypassDomainCheck admits people whose email is not at the company's domain,
+	// such as hires who keep their previous work email. It cannot be changed later.
+	BypassDomainCheck bool `json:"bypass_domain_check"`
+}
+
+// inviteLinkBypassAuditState is the snapshot stored when someone joins through an
+// invite link without an email at the company's domain
+type inviteLinkBypassAuditState struct {
+	InviteLinkID  uuid.UUID `json:"invite_link_id"`
+	CompanyID     uuid.UUID `json:"company_id"`
+	CompanyDomain string    `json:"company_domain"`
+	EmailDomain   string    `json:"email_domain"`
+	BypassCount   int       `json:"bypass_count"`
+}
+
+// CreateInviteLink creates a shareable link for joining the company. Links are
+// valid for as long as invitations are.
+func (h *CompanyHandler) CreateInviteLink(c *gin.Context) {
+	companyID := c.Param("id")
+
+	// Validate UUID
+	if _, err := uuid.Parse(companyID); err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid company ID format").Response(c)
+		return
+	}
+
+	var req CreateInviteLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+
+	// Set default role if not provided
+	role := req.Role
+	if role == "" {
+		role = "member"
+	}
+
+	// Validate role
+	if role != "admin" && role != "member" {
+		errors.ErrInvalidRole.WithMessage("Role must be 'admin' or 'member'").Response(c)
+		return
+	}
+
+	company, ok := h.loadCompanyForAdmin(c, companyID, "Only company admins can create invite links")
+	if !ok {
+		return
+	}
+
+	currentUserID, ok := currentUserUUID(c)
+	if !ok {
+		return
+	}
+
+	token, err := h.generateVerificationToken()
+	if err != nil {
+		errors.ErrInvitationFailed.WithMessage("Failed to create invite link").Response(c)
+		return
+	}
+
+	link := models.CompanyInviteLink{
+		CompanyID:         company.ID,
+		Role:              role,
+		Token:             token,
+		CreatedByID:       currentUserID,
+		BypassDomainCheck: req.BypassDomainCheck,
+		ExpiresAt:         time.Now().Add(invitationTTL),
+	}
+	if err := h.db.Create(&link).Error; err != nil {
+		errors.ErrInvitationFailed.WithMessage("Failed to create invite link").Response(c)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":     "Invite link created successfully",
+		"invite_link": link,
+		"url":         fmt.Sprintf("%s/invite/link?token=%s", h.frontendURL, url.QueryEscape(link.Token)),
+	})
+}
+
+// UseInviteLink adds the current user to the company of an invite link. Verified
+// companies only admit emails at their domain, unless the link was created to
+// bypass that check. Each bypass is counted against the configured limit and
+// recorded in the audit log.
+func (h *CompanyHandler) UseInviteLink(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		errors.ErrMissingToken.WithMessage("Invite link token is required").Response(c)
+		return
+	}
+
+	currentUserID, ok := currentUserUUID(c)
+	if !ok {
+		return
+	}
+
+	var link models.CompanyInviteLink
+	if err := h.db.Where("token = ?", token).First(&link).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrInviteLinkNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch invite link").Response(c)
+		return
+	}
+
+	if link.IsExpired() {
+		errors.ErrInviteLinkExpired.Response(c)
+		return
+	}
+
+	var company models.Company
+	if err := h.db.First(&company, "id = ?", link.CompanyID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrCompanyNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch company").Response(c)
+		return
+	}
+
+	var user models.User
+	if err := h.db.First(&user, "id = ?", currentUserID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrUserNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to find user").Response(c)
+		return
+	}
+
+	var existingMember models.CompanyMember
+	err := h.db.Where("company_id = ? AND user_id = ?", company.ID, user.ID).First(&existingMember).Error
+	if err == nil {
+		errors.ErrAlreadyMember.Response(c)
+		return
+	}
+	if err != gorm.ErrRecordNotFound {
+		errors.ErrQueryFailed.WithMessage("Failed to check membership").Response(c)
+		return
+	}
+
+	// Validate the email domain the same way as AddTeamMember, unless the link allows
+	// people from other domains
+	memberDomain := h.memberDomain(&company)
+	bypass := (company.IsVerified || company.PendingDomain != nil) && !h.isEmailFromDomain(user.Email, memberDomain)
+	if bypass && !link.BypassDomainCheck {
+		errors.ErrInvalidDomain.WithMessage(fmt.Sprintf("Email must be from domain: %s", memberDomain)).Response(c)
+		return
+	}
+
+	tx := h.db.Begin()
+
+	if bypass {
+		// Claim a bypass in the update itself, so concurrent uses cannot exceed the limit
+		result := tx.Model(&models.CompanyInviteLink{}).
+			Where("id = ? AND bypass_count < ?", link.ID, h.inviteLinkMaxBypasses).
+			Update("bypass_count", gorm.Expr("bypass_count + 1"))
+		if result.Error != nil {
+			tx.Rollback()
+			errors.ErrUpdateFailed.WithMessage("Failed to update invite link").Response(c)
+			return
+		}
+		if result.RowsAffected == 0 {
+			tx.Rollback()
+			errors.ErrInviteLinkBypassLimitReached.WithMessage(fmt.Sprintf(
+				"This invite link has admitted the most members from outside %s it can. Ask a company admin for a new link.", memberDomain,
+			)).Response(c)
+			return
+		}
+		link.BypassCount++
+	}
+
+	companyMember := models.CompanyMember{
+		CompanyID: company.ID,
+		UserID:    user.ID,
+		Role:      link.Role,
+		AddedAt:   time.Now(),
+	}
+	if err := tx.Create(&companyMember).Error; err != nil {
+		tx.Rollback()
+		errors.ErrMemberCreationFailed.Response(c)
+		return
+	}
+
+	if bypass {
+		emailDomain := strings.ToLower(user.Email[strings.LastIndex(user.Email, "@")+1:])
+		details := fmt.Sprintf("Joined %s through an invite link that bypasses the domain check, with an email at %s rather than %s",
+			company.Name, emailDomain, memberDomain)
+		if err := createAuditLog(tx, c, models.AuditActionInviteLinkDomainBypass, models.AuditResourceCompanyMember, &companyMember.ID, details,
+			nil, inviteLinkBypassAuditState{
+				InviteLinkID:  link.ID,
+				CompanyID:     company.ID,
+				CompanyDomain: memberDomain,
+				EmailDomain:   emailDomain,
+				BypassCount:   link.BypassCount,
+			}); err != nil {
+			// A bypass must not go unrecorded
+			tx.Rollback()
+			errors.ErrTransactionFailed.Response(c)
+			return
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		errors.ErrTransactionFailed.Response(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Joined company successfully",
+		"member":  companyMember,
+	})
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// createTestInviteLink creates an invite link for the company through the handler,
+// as the given admin, and returns its token
+func createTestInviteLink(t *testing.T, handler *CompanyHandler, company *models.Company, adminID uuid.UUID, body map[string]interface{}) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.Use(mockAuthMiddleware(adminID))
+	router.POST("/companies/:id/invite-links", handler.CreateInviteLink)
+
+	payload, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	req, _ := http.NewRequest("POST", "/companies/"+company.ID.String()+"/invite-links", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// useTestInviteLink uses the invite link with the given token as the given user
+func useTestInviteLink(handler *CompanyHandler, userID uuid.UUID, token string) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.Use(mockAuthMiddleware(userID))
+	router.POST("/invite/link", handler.UseInviteLink)
+
+	req, _ := http.NewRequest("POST", "/invite/link?token="+url.QueryEscape(token), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// inviteLinkToken returns the token of the invite link created with response w
+func inviteLinkToken(t *testing.T, db *gorm.DB, w *httptest.ResponseRecorder) string {
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var response struct {
+		InviteLink models.CompanyInviteLink `json:"invite_link"`
+		URL        string                   `json:"url"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	var link models.CompanyInviteLink
+	require.NoError(t, db.First(&link, "id = ?", response.InviteLink.ID).Error)
+	assert.Contains(t, response.URL, "/invite/link?token="+url.QueryEscape(link.Token))
+	return link.Token
+}
+
+func TestCompanyHandler_CreateInviteLink(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupCompanyTestHandler(t)
+	company := createTestCompany(t, db, true)
+	admin := createTestUserWithEmail(t, db, "admin@testcompany.com")
+	createTestCompanyMember(t, db, company.ID, admin.ID, "admin")
+	member := createTestUserWithEmail(t, db, "member@testcompany.com")
+	createTestCompanyMember(t, db, company.ID, member.ID, "member")
+
+	t.Run("admin creates a link", func(t *testing.T) {
+		w := createTestInviteLink(t, handler, company, admin.ID, map[string]interface{}{"bypass_domain_check": true})
+		token := inviteLinkToken(t, db, w)
+
+		var link models.CompanyInviteLink
+		require.NoError(t, db.First(&link, "token = ?", token).Error)
+		assert.Equal(t, company.ID, link.CompanyID)
+		assert.Equal(t, "member", link.Role)
+		assert.Equal(t, admin.ID, link.CreatedByID)
+		assert.True(t, link.BypassDomainCheck)
+		assert.Zero(t, link.BypassCount)
+		assert.True(t, link.ExpiresAt.After(time.Now()))
+		assert.NotContains(t, w.Body.String(), `"token"`)
+	})
+
+	t.Run("members cannot create links", func(t *testing.T) {
+		w := createTestInviteLink(t, handler, company, member.ID, map[string]interface{}{})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("rejects unknown roles", func(t *testing.T) {
+		w := createTestInviteLink(t, handler, company, admin.ID, map[string]interface{}{"role": "owner"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_ROLE")
+	})
+}
+
+func TestCompanyHandler_UseInviteLink(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupCompanyTestHandler(t)
+	handler.SetInviteLinkMaxBypasses(2)
+	company := createTestCompany(t, db, true)
+	admin := createTestUserWithEmail(t, db, "admin@testcompany.com")
+	createTestCompanyMember(t, db, company.ID, admin.ID, "admin")
+
+	isMember := func(userID uuid.UUID) bool {
+		var count int64
+		db.Model(&models.CompanyMember{}).Where("company_id = ? AND user_id = ?", company.ID, userID).Count(&count)
+		return count == 1
+	}
+
+	t.Run("links without a bypass enforce the company domain", func(t *testing.T) {
+		token := inviteLinkToken(t, db, createTestInviteLink(t, handler, company, admin.ID, map[string]interface{}{}))
+
+		outsider := createTestUserWithEmail(t, db, "outsider@othercompany.com")
+		w := useTestInviteLink(handler, outsider.ID, token)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_DOMAIN")
+		assert.False(t, isMember(outsider.ID))
+
+		insider := createTestUserWithEmail(t, db, "insider@testcompany.com")
+		w = useTestInviteLink(handler, insider.ID, token)
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.True(t, isMember(insider.ID))
+	})
+
+	t.Run("bypass links admit other domains up to the limit", func(t *testing.T) {
+		token := inviteLinkToken(t, db, createTestInviteLink(t, handler, company, admin.ID, map[string]interface{}{
+			"bypass_domain_check": true,
+			"role":                "admin",
+		}))
+
+		// Members at the company's domain do not use up bypasses
+		colleague := createTestUserWithEmail(t, db, "colleague@testcompany.com")
+		require.Equal(t, http.StatusOK, useTestInviteLink(handler, colleague.ID, token).Code)
+
+		firstHire := createTestUserWithEmail(t, db, "first@previousjob.com")
+		w := useTestInviteLink(handler, firstHire.ID, token)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.True(t, isMember(firstHire.ID))
+
+		var member models.CompanyMember
+		require.NoError(t, db.First(&member, "company_id = ? AND user_id = ?", company.ID, firstHire.ID).Error)
+		assert.Equal(t, "admin", member.Role)
+
+		secondHire := createTestUserWithEmail(t, db, "second@Contractor.io")
+		require.Equal(t, http.StatusOK, useTestInviteLink(handler, secondHire.ID, token).Code)
+
+		thirdHire := createTestUserWithEmail(t, db, "third@previousjob.com")
+		w = useTestInviteLink(handler, thirdHire.ID, token)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "INVITE_LINK_BYPASS_LIMIT_REACHED")
+		assert.False(t, isMember(thirdHire.ID))
+
+		var link models.CompanyInviteLink
+		require.NoError(t, db.First(&link, "token = ?", token).Error)
+		assert.Equal(t, 2, link.BypassCount)
+
+		// Each bypass is audited with the domain it let in
+		var logs []models.AuditLog
+		require.NoError(t, db.Where("action = ?", models.AuditActionInviteLinkDomainBypass).Order("created_at").Find(&logs).Error)
+		require.Len(t, logs, 2)
+		assert.Equal(t, firstHire.ID, logs[0].UserID)
+		assert.Equal(t, models.AuditResourceCompanyMember, logs[0].Resource)
+		assert.Equal(t, member.ID, *logs[0].ResourceID)
+		require.NotNil(t, logs[0].AfterState)
+
+		var state inviteLinkBypassAuditState
+		require.NoError(t, json.Unmarshal(*logs[0].AfterState, &state))
+		assert.Equal(t, "previousjob.com", state.EmailDomain)
+		assert.Equal(t, "testcompany.com", state.CompanyDomain)
+		assert.Equal(t, link.ID, state.InviteLinkID)
+		assert.Equal(t, 1, state.BypassCount)
+
+		require.NoError(t, json.Unmarshal(*logs[1].AfterState, &state))
+		assert.Equal(t, "contractor.io", state.EmailDomain)
+		assert.Equal(t, 2, state.BypassCount)
+	})
+
+	t.Run("rejects existing members", func(t *testing.T) {
+		token := inviteLinkToken(t, db, createTestInviteLink(t, handler, company, admin.ID, map[string]interface{}{}))
+
+		w := useTestInviteLink(handler, admin.ID, token)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "ALREADY_MEMBER")
+	})
+
+	t.Run("rejects expired and unknown links", func(t *testing.T) {
+		token := inviteLinkToken(t, db, createTestInviteLink(t, handler, company, admin.ID, map[string]interface{}{}))
+		require.NoError(t, db.Model(&models.CompanyInviteLink{}).Where("token = ?", token).
+			Update("expires_at", time.Now().Add(-time.Hour)).Error)
+
+		user := createTestUserWithEmail(t, db, "late@testcompany.com")
+		w := useTestInviteLink(handler, user.ID, token)
+		assert.Equal(t, http.StatusGone, w.Code)
+		assert.Contains(t, w.Body.String(), "INVITE_LINK_EXPIRED")
+
+		w = useTestInviteLink(handler, user.ID, "not-a-token")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "INVITE_LINK_NOT_FOUND")
+		assert.False(t, isMember(user.ID))
+	})
+}
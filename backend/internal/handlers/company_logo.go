@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/middleware"
+	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	_ "golang.org/x/image/webp"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxLogoFileSize is the largest logo file that can be uploaded
+	maxLogoFileSize = 2 * 1024 * 1024
+	// minLogoDimension and maxLogoDimension bound an uploaded logo's width and height
+	minLogoDimension = 100
+	maxLogoDimension = 2000
+	// logoSize is the width and height logos are stored at
+	logoSize = 200
+)
+
+// logoContentTypes are the image types accepted as company logos
+var logoContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+// UploadCompanyLogo handles uploading a company's logo. The image is cropped to a
+// square, resized to 200x200 and stored as WebP at logos/<company_id>.webp.
+func (h *CompanyHandler) UploadCompanyLogo(c *gin.Context) {
+	companyID := c.Param("id")
+
+	// Validate UUID
+	if _, err := uuid.Parse(companyID); err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid company ID format").Response(c)
+		return
+	}
+
+	// Get current user
+	userIDStr, _ := middleware.GetCurrentUserID(c)
+	currentUserID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		errors.ErrUnauthorized.Response(c)
+		return
+	}
+
+	// Check if current user is admin of the company
+	var currentMember models.CompanyMember
+	if err := h.db.Where("company_id = ? AND user_id = ? AND role = ?",
+		companyID, currentUserID, "admin").First(&currentMember).Error; err != nil {
+		errors.ErrInsufficientPermissions.WithMessage("Only company admins can upload the company logo").Response(c)
+		return
+	}
+
+	// Find company
+	var company models.Company
+	if err := h.db.First(&company, "id = ?", companyID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrCompanyNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch company").Response(c)
+		return
+	}
+
+	// Get uploaded file
+	file, err := c.FormFile("file")
+	if err != nil {
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrNoFile.Response(c)
+		return
+	}
+
+	if file.Size > maxLogoFileSize {
+		errors.ErrFileTooLarge.WithMessage("Logo size exceeds 2MB limit").Response(c)
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		errors.ErrFileReadError.Response(c)
+		return
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, maxLogoFileSize))
+	if err != nil {
+		errors.ErrFileReadError.WithMessage("Failed to read file content").Response(c)
+		return
+	}
+
+	if !logoContentTypes[http.DetectContentType(data)] {
+		errors.ErrInvalidFileType.WithMessage("Only JPEG, PNG and WebP logos are allowed").Response(c)
+		return
+	}
+
+	// Check dimensions before decoding the whole image
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		errors.ErrInvalidFileType.WithMessage("Failed to decode image").Response(c)
+		return
+	}
+	if config.Width < minLogoDimension || config.Height < minLogoDimension ||
+		config.Width > maxLogoDimension || config.Height > maxLogoDimension {
+		errors.ErrInvalidImageDimensions.WithMessage(fmt.Sprintf(
+			"Logo must be between %dx%d and %dx%d pixels", minLogoDimension, minLogoDimension, maxLogoDimension, maxLogoDimension,
+		)).WithDetails(map[string]interface{}{
+			"width":  config.Width,
+			"height": config.Height,
+		}).Response(c)
+		return
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		errors.ErrInvalidFileType.WithMessage("Failed to decode image").Response(c)
+		return
+	}
+
+	var logo bytes.Buffer
+	if err := utils.EncodeWebP(&logo, utils.ResizeSquare(img, logoSize)); err != nil {
+		errors.ErrSaveFailed.WithMessage("Failed to encode logo").Response(c)
+		return
+	}
+
+	logoURL, err := h.storage.Put(c.Request.Context(), "logos/"+companyID+".webp", logo.Bytes(), "image/webp")
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("Failed to store company logo", err, logger.Fields{"company_id": companyID})
+		errors.ErrSaveFailed.WithMessage("Failed to save logo").Response(c)
+		return
+	}
+
+	// Every upload is stored under the same key, so the version busts cached copies
+	logoURL += "?v=" + strconv.FormatInt(time.Now().Unix(), 10)
+
+	if err := h.db.Model(&company).Update("logo_url", logoURL).Error; err != nil {
+		errors.ErrUpdateFailed.WithMessage("Failed to update company logo").Response(c)
+		return
+	}
+
+	if err := h.cache.InvalidateCompany(c.Request.Context(), companyID); err != nil {
+		// Log cache error but don't fail the request
+		logger.FromContext(c.Request.Context()).Error("Failed to invalidate company cache", err, logger.Fields{"company_id": companyID})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Company logo uploaded successfully",
+		"company": company,
+	})
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/webp"
+)
+
+// mockStorage records the objects put into it and the attachments deleted from it
+type mockStorage struct {
+	objects      map[string][]byte
+	contentTypes map[string]string
+	deleted      []string
+}
+
+func newMockStorage() *mockStorage {
+	return &mockStorage{objects: make(map[string][]byte), contentTypes: make(map[string]string)}
+}
+
+func (s *mockStorage) GeneratePreviewURL(attachment models.FileAttachment) string {
+	return "https://storage.example.com/" + attachment.FileURL
+}
+
+func (s *mockStorage) Put(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+	s.objects[key] = data
+	s.contentTypes[key] = contentType
+	return "https://storage.example.com/" + key, nil
+}
+
+func (s *mockStorage) Upload(ctx context.Context, key string, body io.Reader, size int64, contentType string) (string, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	return s.Put(ctx, key, data, contentType)
+}
+
+func (s *mockStorage) Delete(ctx context.Context, attachment models.FileAttachment) error {
+	s.deleted = append(s.deleted, attachment.FileURL)
+	return nil
+}
+
+// encodeTestImage encodes a solid width by height image in the given format
+func encodeTestImage(t *testing.T, format string, width, height int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: 30, G: 90, B: 200, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "png":
+		require.NoError(t, png.Encode(&buf, img))
+	case "jpeg":
+		require.NoError(t, jpeg.Encode(&buf, img, nil))
+	case "gif":
+		require.NoError(t, gif.Encode(&buf, img, nil))
+	}
+	return buf.Bytes()
+}
+
+func TestCompanyHandler_UploadCompanyLogo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupBugTestDB(t)
+	redisClient, mock := newMockRedisClient()
+	handler := NewCompanyHandler(db, redisClient)
+	store := newMockStorage()
+	handler.SetStorage(store)
+
+	company := createTestCompany(t, db, true)
+	admin := createTestUser(t, db)
+	createTestCompanyMember(t, db, company.ID, admin.ID, "admin")
+	member := &models.User{ID: uuid.New(), Email: "member@testcompany.com", DisplayName: "Member"}
+	require.NoError(t, db.Create(member).Error)
+	createTestCompanyMember(t, db, company.ID, member.ID, "member")
+
+	upload := func(userID uuid.UUID, filename string, data []byte) *httptest.ResponseRecorder {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("file", filename)
+		require.NoError(t, err)
+		_, err = part.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/companies/"+company.ID.String()+"/logo", body)
+		c.Request.Header.Set("Content-Type", writer.FormDataContentType())
+		c.Params = gin.Params{{Key: "id", Value: company.ID.String()}}
+		mockAuthMiddleware(userID)(c)
+
+		handler.UploadCompanyLogo(c)
+		return w
+	}
+	errorCode := func(w *httptest.ResponseRecorder) string {
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response["error"].(map[string]interface{})["code"].(string)
+	}
+
+	t.Run("stores a 200x200 WebP logo", func(t *testing.T) {
+		companyKey := "company:" + company.ID.String()
+		mock.values[companyKey] = "{}"
+
+		w := upload(admin.ID, "logo.jpg", encodeTestImage(t, "jpeg", 300, 150))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		key := "logos/" + company.ID.String() + ".webp"
+		require.Contains(t, store.objects, key)
+		assert.Equal(t, "image/webp", store.contentTypes[key])
+
+		logo, err := webp.Decode(bytes.NewReader(store.objects[key]))
+		require.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, 200, 200), logo.Bounds())
+
+		var updated models.Company
+		require.NoError(t, db.First(&updated, "id = ?", company.ID).Error)
+		require.NotNil(t, updated.LogoURL)
+		assert.True(t, strings.HasPrefix(*updated.LogoURL, "https://storage.example.com/"+key+"?v="))
+		assert.NotContains(t, mock.values, companyKey)
+
+		var response struct {
+			Company models.Company `json:"company"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, updated.LogoURL, response.Company.LogoURL)
+	})
+
+	t.Run("dimension boundaries", func(t *testing.T) {
+		tests := []struct {
+			width, height int
+			expectedCode  int
+		}{
+			{100, 100, http.StatusOK},
+			{2000, 2000, http.StatusOK},
+			{99, 100, http.StatusBadRequest},
+			{100, 99, http.StatusBadRequest},
+			{2001, 2000, http.StatusBadRequest},
+			{2000, 2001, http.StatusBadRequest},
+		}
+
+		for _, tt := range tests {
+			w := upload(admin.ID, "logo.png", encodeTestImage(t, "png", tt.width, tt.height))
+			require.Equal(t, tt.expectedCode, w.Code, "%dx%d", tt.width, tt.height)
+			if tt.expectedCode == http.StatusBadRequest {
+				assert.Equal(t, "INVALID_IMAGE_DIMENSIONS", errorCode(w))
+			}
+		}
+	})
+
+	t.Run("accepts WebP uploads", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, utils.EncodeWebP(&buf, image.NewNRGBA(image.Rect(0, 0, 120, 120))))
+
+		w := upload(admin.ID, "logo.webp", buf.Bytes())
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	})
+
+	t.Run("rejects other file types", func(t *testing.T) {
+		w := upload(admin.ID, "logo.gif", encodeTestImage(t, "gif", 150, 150))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "INVALID_FILE_TYPE", errorCode(w))
+	})
+
+	t.Run("rejects files over 2MB", func(t *testing.T) {
+		data := append(encodeTestImage(t, "png", 150, 150), make([]byte, 2*1024*1024)...)
+
+		w := upload(admin.ID, "logo.png", data)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "FILE_TOO_LARGE", errorCode(w))
+	})
+
+	t.Run("only company admins can upload", func(t *testing.T) {
+		w := upload(member.ID, "logo.png", encodeTestImage(t, "png", 150, 150))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "INSUFFICIENT_PERMISSIONS", errorCode(w))
+	})
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"bugrelay-backend/internal/auth"
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/middleware"
+	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/utils"
+	"bugrelay-backend/internal/webhooks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// CreateWebhookRequest represents the request to register a company webhook
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" binding:"required,url,max=2048"`
+	Events []string `json:"events" binding:"required,min=1"`
+	// Secret signs the deliveries. One is generated when it is not given.
+	Secret string `json:"secret,omitempty" binding:"omitempty,min=16,max=255"`
+}
+
+// CreateWebhook handles registering a webhook for the bugs assigned to a company
+// (company admins only). The secret is only returned here.
+func (h *CompanyHandler) CreateWebhook(c *gin.Context) {
+	companyID := c.Param("id")
+
+	// Validate UUID
+	if _, err := uuid.Parse(companyID); err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid company ID format").Response(c)
+		return
+	}
+
+	var req CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+
+	// Webhooks are posted from the server, so they may not point at its own network
+	if err := webhooks.ValidateURL(c.Request.Context(), req.URL); err != nil {
+		errors.ErrInvalidWebhookURL.Response(c)
+		return
+	}
+
+	events := make([]string, 0, len(req.Events))
+	for _, event := range req.Events {
+		event = strings.TrimSpace(event)
+		if !models.IsValidWebhookEvent(event) {
+			errors.ErrInvalidWebhookEvent.WithDetails(gin.H{"event": event}).Response(c)
+			return
+		}
+		events = append(events, event)
+	}
+
+	company, ok := h.loadCompanyForAdmin(c, companyID, "Only company admins can register webhooks")
+	if !ok {
+		return
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		var err error
+		if secret, err = auth.GenerateSecureToken(32); err != nil {
+			errors.ErrTokenGenerationFailed.Response(c)
+			return
+		}
+	}
+
+	// loadCompanyForAdmin has checked the user ID
+	userIDStr, _ := middleware.GetCurrentUserID(c)
+	createdBy, _ := uuid.Parse(userIDStr)
+
+	webhook := models.CompanyWebhook{
+		CompanyID: company.ID,
+		URL:       req.URL,
+		Secret:    secret,
+		Events:    pq.StringArray(events),
+		CreatedBy: createdBy,
+	}
+
+	if err := h.db.Create(&webhook).Error; err != nil {
+		errors.ErrWebhookCreationFailed.Response(c)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Webhook created successfully",
+		"webhook": webhook,
+		"secret":  secret,
+	})
+}
+
+// ListWebhooks handles listing a company's webhooks (company members only)
+func (h *CompanyHandler) ListWebhooks(c *gin.Context) {
+	companyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid company ID format").Response(c)
+		return
+	}
+
+	var webhooks []models.CompanyWebhook
+	if err := h.db.Where("company_id = ?", companyID).Order("created_at ASC").Find(&webhooks).Error; err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to fetch webhooks").Response(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"webhooks": webhooks,
+	})
+}
+
+// DeleteWebhook handles removing a company webhook (company admins only)
+func (h *CompanyHandler) DeleteWebhook(c *gin.Context) {
+	companyID := c.Param("id")
+
+	// Validate UUIDs
+	if _, err := uuid.Parse(companyID); err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid company ID format").Response(c)
+		return
+	}
+	webhookID, err := uuid.Parse(c.Param("webhook_id"))
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid webhook ID format").Response(c)
+		return
+	}
+
+	company, ok := h.loadCompanyForAdmin(c, companyID, "Only company admins can remove webhooks")
+	if !ok {
+		return
+	}
+
+	var webhook models.CompanyWebhook
+	if err := h.db.Where("id = ? AND company_id = ?", webhookID, company.ID).First(&webhook).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrWebhookNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch webhook").Response(c)
+		return
+	}
+
+	if err := h.db.Delete(&webhook).Error; err != nil {
+		errors.ErrDeleteFailed.WithMessage("Failed to delete webhook").Response(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook deleted successfully",
+	})
+}
+
+// SetWebhookDispatcher sets the dispatcher that bug creations, status changes and
+// comments are posted to company webhooks with
+func (h *BugHandler) SetWebhookDispatcher(dispatcher *webhooks.Dispatcher) {
+	h.webhookDispatcher = dispatcher
+}
+
+// dispatchWebhook posts the payload for the bug to the webhooks of the company it is
+// assigned to. It runs after the change is saved, so a failure is only logged.
+func (h *BugHandler) dispatchWebhook(ctx context.Context, bug models.BugReport, payload webhooks.Payload) {
+	if h.webhookDispatcher == nil || bug.AssignedCompanyID == nil {
+		return
+	}
+
+	payload.Bug = bug
+	if err := h.webhookDispatcher.Dispatch(ctx, *bug.AssignedCompanyID, payload); err != nil {
+		logger.FromContext(ctx).Error("Failed to dispatch webhook", err, logger.Fields{
+			"bug_id": bug.ID.String(),
+			"event":  payload.Event,
+		})
+	}
+}
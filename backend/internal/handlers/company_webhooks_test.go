@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/webhooks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// createTestWebhook registers a webhook for the company
+func createTestWebhook(t *testing.T, db *gorm.DB, companyID, createdBy uuid.UUID, url string, events ...string) *models.CompanyWebhook {
+	webhook := &models.CompanyWebhook{
+		ID:        uuid.New(),
+		CompanyID: companyID,
+		URL:       url,
+		Secret:    "test-secret-0123456789",
+		Events:    pq.StringArray(events),
+		CreatedBy: createdBy,
+	}
+	require.NoError(t, db.Create(webhook).Error)
+	return webhook
+}
+
+func TestCompanyHandler_CreateWebhook(t *testing.T) {
+	handler, db := setupCompanyTestHandler(t)
+
+	admin := createTestUser(t, db)
+	company := createTestCompany(t, db, true)
+	createTestCompanyMember(t, db, company.ID, admin.ID, "admin")
+
+	member := &models.User{ID: uuid.New(), Email: "member@example.com", DisplayName: "Member"}
+	require.NoError(t, db.Create(member).Error)
+	createTestCompanyMember(t, db, company.ID, member.ID, "member")
+
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		userID         uuid.UUID
+		body           map[string]interface{}
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:   "valid webhook",
+			userID: admin.ID,
+			body: map[string]interface{}{
+				"url":    "https://ci.example.com/hooks/bugrelay",
+				"events": []string{models.WebhookEventBugCreated, models.WebhookEventBugStatusChanged},
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "unknown event",
+			userID: admin.ID,
+			body: map[string]interface{}{
+				"url":    "https://ci.example.com/hooks/bugrelay",
+				"events": []string{"bug.deleted"},
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "INVALID_WEBHOOK_EVENT",
+		},
+		{
+			name:   "non-http url",
+			userID: admin.ID,
+			body: map[string]interface{}{
+				"url":    "ftp://ci.example.com/hooks",
+				"events": []string{models.WebhookEventBugCreated},
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "INVALID_WEBHOOK_URL",
+		},
+		{
+			name:   "loopback url",
+			userID: admin.ID,
+			body: map[string]interface{}{
+				"url":    "http://127.0.0.1:8080/hooks",
+				"events": []string{models.WebhookEventBugCreated},
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "INVALID_WEBHOOK_URL",
+		},
+		{
+			name:   "private network url",
+			userID: admin.ID,
+			body: map[string]interface{}{
+				"url":    "http://10.0.0.5/hooks",
+				"events": []string{models.WebhookEventBugCreated},
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "INVALID_WEBHOOK_URL",
+		},
+		{
+			name:   "cloud metadata url",
+			userID: admin.ID,
+			body: map[string]interface{}{
+				"url":    "http://169.254.169.254/latest/meta-data",
+				"events": []string{models.WebhookEventBugCreated},
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "INVALID_WEBHOOK_URL",
+		},
+		{
+			name:   "localhost url",
+			userID: admin.ID,
+			body: map[string]interface{}{
+				"url":    "http://localhost/hooks",
+				"events": []string{models.WebhookEventBugCreated},
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "INVALID_WEBHOOK_URL",
+		},
+		{
+			name:   "no events",
+			userID: admin.ID,
+			body: map[string]interface{}{
+				"url":    "https://ci.example.com/hooks/bugrelay",
+				"events": []string{},
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "VALIDATION_ERROR",
+		},
+		{
+			name:   "member who is not an admin",
+			userID: member.ID,
+			body: map[string]interface{}{
+				"url":    "https://ci.example.com/hooks/bugrelay",
+				"events": []string{models.WebhookEventBugCreated},
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "INSUFFICIENT_PERMISSIONS",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(mockAuthMiddleware(tt.userID))
+			router.POST("/companies/:id/webhooks", handler.CreateWebhook)
+
+			body, _ := json.Marshal(tt.body)
+			req, _ := http.NewRequest("POST", "/companies/"+company.ID.String()+"/webhooks", bytes.NewBuffer(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedError != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedError)
+				return
+			}
+
+			var response struct {
+				Webhook map[string]interface{} `json:"webhook"`
+				Secret  string                 `json:"secret"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+			// A secret is generated and only returned alongside the webhook
+			assert.Len(t, response.Secret, 64)
+			assert.NotContains(t, response.Webhook, "secret")
+
+			var webhook models.CompanyWebhook
+			require.NoError(t, db.First(&webhook, "company_id = ?", company.ID).Error)
+			assert.Equal(t, response.Secret, webhook.Secret)
+			assert.Equal(t, admin.ID, webhook.CreatedBy)
+			assert.ElementsMatch(t, []string{models.WebhookEventBugCreated, models.WebhookEventBugStatusChanged}, []string(webhook.Events))
+		})
+	}
+}
+
+func TestCompanyHandler_DeleteWebhook(t *testing.T) {
+	handler, db := setupCompanyTestHandler(t)
+
+	admin := createTestUser(t, db)
+	company := createTestCompany(t, db, true)
+	createTestCompanyMember(t, db, company.ID, admin.ID, "admin")
+	webhook := createTestWebhook(t, db, company.ID, admin.ID, "https://ci.example.com/hooks", models.WebhookEventBugCreated)
+
+	otherCompany := &models.Company{ID: uuid.New(), Name: "Other Company", Domain: "other.com"}
+	require.NoError(t, db.Create(otherCompany).Error)
+	otherWebhook := createTestWebhook(t, db, otherCompany.ID, admin.ID, "https://other.example.com/hooks", models.WebhookEventBugCreated)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(mockAuthMiddleware(admin.ID))
+	router.DELETE("/companies/:id/webhooks/:webhook_id", handler.DeleteWebhook)
+	router.GET("/companies/:id/webhooks", handler.ListWebhooks)
+
+	remove := func(webhookID uuid.UUID) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("DELETE", "/companies/"+company.ID.String()+"/webhooks/"+webhookID.String(), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Another company's webhook cannot be removed through this company
+	w := remove(otherWebhook.ID)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "WEBHOOK_NOT_FOUND")
+
+	list := func() []map[string]interface{} {
+		req, _ := http.NewRequest("GET", "/companies/"+company.ID.String()+"/webhooks", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Webhooks []map[string]interface{} `json:"webhooks"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.Webhooks
+	}
+
+	webhooks := list()
+	require.Len(t, webhooks, 1)
+	assert.Equal(t, webhook.ID.String(), webhooks[0]["id"])
+	assert.NotContains(t, webhooks[0], "secret")
+
+	w = remove(webhook.ID)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, list())
+}
+
+func TestBugHandler_DispatchesWebhooks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	dispatcher := webhooks.NewDispatcher(db, http.DefaultClient, 1, webhooks.DefaultQueueSize)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go dispatcher.Run(ctx)
+	handler.SetWebhookDispatcher(dispatcher)
+
+	received := make(chan *http.Request, 10)
+	bodies := make(chan []byte, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+	}))
+	defer receiver.Close()
+
+	user := createTestUser(t, db)
+	company := createTestCompany(t, db, true)
+	createTestCompanyMember(t, db, company.ID, user.ID, "member")
+	webhook := createTestWebhook(t, db, company.ID, user.ID, receiver.URL, models.WebhookEventBugStatusChanged)
+
+	app := createTestApplication(t, db)
+	bug := createTestBugReport(t, db, app, user)
+	require.NoError(t, db.Model(bug).Update("assigned_company_id", company.ID).Error)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("PUT", "/bugs/"+bug.ID.String()+"/status", bytes.NewBufferString(`{"status":"fixed"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: bug.ID.String()}}
+	c.Set("user_id", user.ID.String())
+	c.Set("is_admin", false)
+	handler.UpdateBugStatus(c)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	select {
+	case req := <-received:
+		body := <-bodies
+		assert.Equal(t, models.WebhookEventBugStatusChanged, req.Header.Get(webhooks.EventHeader))
+		assert.Equal(t, webhooks.Sign(webhook.Secret, body), req.Header.Get(webhooks.SignatureHeader))
+
+		var payload webhooks.Payload
+		require.NoError(t, json.Unmarshal(body, &payload))
+		assert.Equal(t, models.WebhookEventBugStatusChanged, payload.Event)
+		assert.Equal(t, bug.ID, payload.Bug.ID)
+		assert.Equal(t, models.BugStatusFixed, payload.Bug.Status)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no webhook delivered")
+	}
+}
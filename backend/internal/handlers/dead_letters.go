@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// DeadLetter is an outbox event that exhausted its retries, as shown to administrators
+type DeadLetter struct {
+	ID             uuid.UUID      `json:"id"`
+	EventType      string         `json:"event_type"`
+	Payload        datatypes.JSON `json:"payload"`
+	ErrorMessage   *string        `json:"error_message"`
+	RetryCount     int            `json:"retry_count"`
+	CreatedAt      time.Time      `json:"created_at"`
+	DeadLetteredAt *time.Time     `json:"dead_lettered_at"`
+}
+
+// newDeadLetter converts a dead-lettered outbox event for the admin API
+func newDeadLetter(event models.OutboxEvent) DeadLetter {
+	return DeadLetter{
+		ID:             event.ID,
+		EventType:      event.EventType,
+		Payload:        event.Payload,
+		ErrorMessage:   event.LastError,
+		RetryCount:     event.RetryCount,
+		CreatedAt:      event.CreatedAt,
+		DeadLetteredAt: event.DeadLetteredAt,
+	}
+}
+
+// ListDeadLetters returns dead-lettered outbox events with pagination, most recently
+// dead-lettered first. Events can be filtered by event_type.
+func (h *AdminHandler) ListDeadLetters(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	eventType := c.Query("event_type")
+
+	if page <= 0 {
+		page = 1
+	}
+	limit = h.pagination.WithDefault(50).Limit(limit)
+
+	query := h.db.Model(&models.OutboxEvent{}).Where("status = ?", models.OutboxStatusDeadLettered)
+	if eventType != "" {
+		query = query.Where("event_type = ?", eventType)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	var events []models.OutboxEvent
+	if err := query.Order("dead_lettered_at DESC").Order("created_at DESC").Offset(offset).Limit(limit).Find(&events).Error; err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to fetch dead letters").Response(c)
+		return
+	}
+
+	deadLetters := make([]DeadLetter, 0, len(events))
+	for _, event := range events {
+		deadLetters = append(deadLetters, newDeadLetter(event))
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	c.JSON(http.StatusOK, gin.H{
+		"dead_letters": deadLetters,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": totalPages,
+			"has_next":    page < totalPages,
+			"has_prev":    page > 1,
+		},
+	})
+}
+
+// loadDeadLetter fetches the dead-lettered event in the :id route parameter, writing
+// the error response itself when the event cannot be loaded
+func (h *AdminHandler) loadDeadLetter(c *gin.Context) (*models.OutboxEvent, bool) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid event ID format").Response(c)
+		return nil, false
+	}
+
+	var event models.OutboxEvent
+	if err := h.db.Where("id = ? AND status = ?", eventID, models.OutboxStatusDeadLettered).First(&event).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrDeadLetterNotFound.Response(c)
+			return nil, false
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch dead letter").Response(c)
+		return nil, false
+	}
+
+	return &event, true
+}
+
+// RetryDeadLetter re-queues a dead-lettered event so the outbox processor delivers it
+// again with a fresh set of retries
+func (h *AdminHandler) RetryDeadLetter(c *gin.Context) {
+	event, ok := h.loadDeadLetter(c)
+	if !ok {
+		return
+	}
+
+	if err := h.db.Model(event).Updates(map[string]interface{}{
+		"status":           models.OutboxStatusPending,
+		"retry_count":      0,
+		"dead_lettered_at": nil,
+	}).Error; err != nil {
+		errors.ErrRetryFailed.Response(c)
+		return
+	}
+
+	details := fmt.Sprintf("Dead-lettered %s event re-queued after %d attempts", event.EventType, event.RetryCount)
+	if err := h.logAuditAction(c, models.AuditActionOutboxRetry, models.AuditResourceOutboxEvent, &event.ID, details, nil, nil); err != nil {
+		// Log error but don't fail the request since the event was re-queued
+		logger.FromContext(c.Request.Context()).Error("Failed to log audit action", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Event re-queued successfully",
+		"event_id": event.ID,
+	})
+}
+
+// DeleteDeadLetter permanently discards a dead-lettered event
+func (h *AdminHandler) DeleteDeadLetter(c *gin.Context) {
+	event, ok := h.loadDeadLetter(c)
+	if !ok {
+		return
+	}
+
+	if err := h.db.Delete(event).Error; err != nil {
+		errors.ErrDeleteFailed.WithMessage("Failed to discard event").Response(c)
+		return
+	}
+
+	details := fmt.Sprintf("Dead-lettered %s event discarded", event.EventType)
+	if err := h.logAuditAction(c, models.AuditActionOutboxDiscard, models.AuditResourceOutboxEvent, &event.ID, details, nil, nil); err != nil {
+		// Log error but don't fail the request since the event was discarded
+		logger.FromContext(c.Request.Context()).Error("Failed to log audit action", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Event discarded successfully",
+		"event_id": event.ID,
+	})
+}
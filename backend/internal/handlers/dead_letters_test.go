@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bugrelay-backend/internal/jobs"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminHandler_DeadLetters(t *testing.T) {
+	handler, db := setupAdminTestHandler(t)
+	admin := createTestAdmin(t, db)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(mockAuthMiddleware(admin.ID))
+	router.GET("/admin/dead-letters", handler.ListDeadLetters)
+	router.POST("/admin/dead-letters/:id/retry", handler.RetryDeadLetter)
+	router.DELETE("/admin/dead-letters/:id", handler.DeleteDeadLetter)
+
+	list := func() []DeadLetter {
+		req, _ := http.NewRequest("GET", "/admin/dead-letters", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			DeadLetters []DeadLetter `json:"dead_letters"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.DeadLetters
+	}
+
+	// deadLetter enqueues an event and fails its delivery until it is dead-lettered
+	deadLetter := func(payload map[string]string) uuid.UUID {
+		require.NoError(t, models.EnqueueOutboxEvent(db, models.OutboxEventBugCreated, payload))
+		processor := jobs.NewOutboxProcessor(db)
+		processor.Handle(models.OutboxEventBugCreated, func(ctx context.Context, event models.OutboxEvent) error {
+			return errors.New("connection refused")
+		})
+		for i := 0; i < 6; i++ {
+			require.NoError(t, processor.ProcessPending(context.Background()))
+		}
+
+		var event models.OutboxEvent
+		require.NoError(t, db.Where("status = ?", models.OutboxStatusDeadLettered).Order("created_at DESC").First(&event).Error)
+		return event.ID
+	}
+
+	t.Run("lists events that exhausted their retries", func(t *testing.T) {
+		eventID := deadLetter(map[string]string{"title": "Crash"})
+		defer db.Delete(&models.OutboxEvent{}, "id = ?", eventID)
+
+		// Pending events are not dead letters
+		require.NoError(t, models.EnqueueOutboxEvent(db, models.OutboxEventEmail, models.EmailPayload{}))
+		defer db.Where("event_type = ?", models.OutboxEventEmail).Delete(&models.OutboxEvent{})
+
+		deadLetters := list()
+		require.Len(t, deadLetters, 1)
+		assert.Equal(t, eventID, deadLetters[0].ID)
+		assert.Equal(t, models.OutboxEventBugCreated, deadLetters[0].EventType)
+		assert.JSONEq(t, `{"title":"Crash"}`, string(deadLetters[0].Payload))
+		require.NotNil(t, deadLetters[0].ErrorMessage)
+		assert.Equal(t, "connection refused", *deadLetters[0].ErrorMessage)
+		assert.Equal(t, 6, deadLetters[0].RetryCount)
+		assert.NotNil(t, deadLetters[0].DeadLetteredAt)
+	})
+
+	t.Run("retry re-queues the event for delivery", func(t *testing.T) {
+		eventID := deadLetter(map[string]string{"title": "Retry me"})
+		defer db.Delete(&models.OutboxEvent{}, "id = ?", eventID)
+
+		req, _ := http.NewRequest("POST", "/admin/dead-letters/"+eventID.String()+"/retry", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var event models.OutboxEvent
+		require.NoError(t, db.First(&event, "id = ?", eventID).Error)
+		assert.Equal(t, models.OutboxStatusPending, event.Status)
+		assert.Equal(t, 0, event.RetryCount)
+		assert.Nil(t, event.DeadLetteredAt)
+		assert.Empty(t, list())
+
+		var auditLog models.AuditLog
+		require.NoError(t, db.Where("action = ? AND resource_id = ?", models.AuditActionOutboxRetry, eventID).First(&auditLog).Error)
+		assert.Equal(t, admin.ID, auditLog.UserID)
+
+		// The processor delivers the re-queued event once its destination recovers
+		var delivered []uuid.UUID
+		processor := jobs.NewOutboxProcessor(db)
+		processor.Handle(models.OutboxEventBugCreated, func(ctx context.Context, event models.OutboxEvent) error {
+			delivered = append(delivered, event.ID)
+			return nil
+		})
+		require.NoError(t, processor.ProcessPending(context.Background()))
+		assert.Equal(t, []uuid.UUID{eventID}, delivered)
+
+		require.NoError(t, db.First(&event, "id = ?", eventID).Error)
+		assert.Equal(t, models.OutboxStatusProcessed, event.Status)
+	})
+
+	t.Run("delete discards the event", func(t *testing.T) {
+		eventID := deadLetter(map[string]string{"title": "Discard me"})
+
+		req, _ := http.NewRequest("DELETE", "/admin/dead-letters/"+eventID.String(), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var remaining int64
+		db.Model(&models.OutboxEvent{}).Where("id = ?", eventID).Count(&remaining)
+		assert.Equal(t, int64(0), remaining)
+		assert.Empty(t, list())
+	})
+
+	t.Run("only dead-lettered events can be retried or discarded", func(t *testing.T) {
+		require.NoError(t, models.EnqueueOutboxEvent(db, models.OutboxEventEmail, models.EmailPayload{}))
+		var pending models.OutboxEvent
+		require.NoError(t, db.Where("event_type = ?", models.OutboxEventEmail).First(&pending).Error)
+
+		for _, req := range []*http.Request{
+			httptest.NewRequest("POST", "/admin/dead-letters/"+pending.ID.String()+"/retry", nil),
+			httptest.NewRequest("DELETE", "/admin/dead-letters/"+pending.ID.String(), nil),
+			httptest.NewRequest("POST", "/admin/dead-letters/"+uuid.New().String()+"/retry", nil),
+		} {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusNotFound, w.Code)
+		}
+
+		req := httptest.NewRequest("DELETE", "/admin/dead-letters/not-a-uuid", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
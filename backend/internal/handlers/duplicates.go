@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// duplicateSimilarityThreshold is the pg_trgm similarity, from 0 to 1, of the title
+	// or the description above which an unresolved bug in the same application is a
+	// suspected duplicate
+	duplicateSimilarityThreshold = 0.7
+
+	// maxSimilarBugs is the most suspected duplicates returned for a submission
+	maxSimilarBugs = 5
+
+	// PossibleDuplicateCode is the code of a submission answered with suggested
+	// duplicates instead of being created
+	PossibleDuplicateCode = "POSSIBLE_DUPLICATE"
+)
+
+// SimilarBug is an unresolved bug suspected to duplicate a submission. Similarity is
+// the higher of its title and description similarity to the submission.
+type SimilarBug struct {
+	ID            uuid.UUID `json:"id"`
+	Title         string    `json:"title"`
+	Status        string    `json:"status"`
+	ApplicationID uuid.UUID `json:"application_id"`
+	Similarity    float64   `json:"similarity"`
+}
+
+// PossibleDuplicateResponse answers a submission similar to unresolved bugs in the
+// same application. No bug is created; resubmitting with force_create creates it.
+type PossibleDuplicateResponse struct {
+	Code                string       `json:"code" example:"POSSIBLE_DUPLICATE"`
+	Message             string       `json:"message"`
+	SuggestedDuplicates []SimilarBug `json:"suggested_duplicates"`
+}
+
+// FindSimilarBugs returns the unresolved bugs in an application whose title or
+// description has a pg_trgm similarity to the submission's above threshold, most
+// similar first. Bugs in other applications are never matched. Results are cached
+// for 30 seconds per application and submission, so a submitter resending with
+// force_create is not checked twice.
+func (h *BugHandler) FindSimilarBugs(tx *gorm.DB, title, description string, applicationID uuid.UUID, threshold float64) ([]SimilarBug, error) {
+	ctx := tx.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	submissionHash := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(title)) + "\x00" + strings.ToLower(strings.TrimSpace(description))))
+	cacheKey := hex.EncodeToString(submissionHash[:])
+
+	var bugs []SimilarBug
+	if err := h.cache.GetSimilarBugs(ctx, applicationID.String(), cacheKey, &bugs); err == nil {
+		return bugs, nil
+	}
+
+	err := tx.Model(&models.BugReport{}).
+		Select("id, title, status, application_id, GREATEST(similarity(title, ?), similarity(description, ?)) AS similarity", title, description).
+		Where("application_id = ? AND is_spam = ?", applicationID, false).
+		Where("status NOT IN ?", []string{models.BugStatusFixed, models.BugStatusWontFix}).
+		Where("(similarity(title, ?) > ? OR similarity(description, ?) > ?)", title, threshold, description, threshold).
+		Order("similarity DESC").
+		Limit(maxSimilarBugs).
+		Scan(&bugs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.cache.SetSimilarBugs(ctx, applicationID.String(), cacheKey, bugs); err != nil {
+		// Log cache error but don't fail the request
+		logger.FromContext(ctx).Error("Failed to cache similar bugs", err, logger.Fields{"application_id": applicationID.String()})
+	}
+
+	return bugs, nil
+}
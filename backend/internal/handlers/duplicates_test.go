@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// trigramDriver is a SQLite driver with pg_trgm's similarity function and GREATEST
+const trigramDriver = "sqlite3_trigram"
+
+var registerTrigramDriver sync.Once
+
+// trigrams returns the pg_trgm trigrams of text: each lower-cased word padded with
+// two spaces in front and one behind
+func trigrams(text string) map[string]bool {
+	set := make(map[string]bool)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = true
+		}
+	}
+	return set
+}
+
+// trigramSimilarity mirrors pg_trgm's similarity: shared trigrams over all trigrams
+func trigramSimilarity(a, b string) float64 {
+	left, right := trigrams(a), trigrams(b)
+	shared := 0
+	for trigram := range left {
+		if right[trigram] {
+			shared++
+		}
+	}
+	total := len(left) + len(right) - shared
+	if total == 0 {
+		return 0
+	}
+	return float64(shared) / float64(total)
+}
+
+// setupDuplicateTestDB creates a test database that supports the duplicate check query
+func setupDuplicateTestDB(t *testing.T) *gorm.DB {
+	registerTrigramDriver.Do(func() {
+		sql.Register(trigramDriver, &sqlite3.SQLiteDriver{
+			ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+				if err := conn.RegisterFunc("greatest", math.Max, true); err != nil {
+					return err
+				}
+				return conn.RegisterFunc("similarity", trigramSimilarity, true)
+			},
+		})
+	})
+
+	return openBugTestDB(t, sqlite.New(sqlite.Config{DriverName: trigramDriver, DSN: ":memory:"}))
+}
+
+func TestTrigramSimilarity(t *testing.T) {
+	assert.Equal(t, float64(1), trigramSimilarity("Login fails", "login FAILS!"))
+	assert.Equal(t, float64(0), trigramSimilarity("Login fails", "Export hangs"))
+	assert.InDelta(t, 0.5, trigramSimilarity("word", "words"), 0.2)
+}
+
+func TestBugHandler_CreateBug_DuplicateDetection(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupDuplicateTestDB(t)
+	handler := NewBugHandler(db, nil)
+	user := createTestUser(t, db)
+
+	alpha := &models.Application{ID: uuid.New(), Name: "Alpha App"}
+	beta := &models.Application{ID: uuid.New(), Name: "Beta App"}
+	require.NoError(t, db.Create(alpha).Error)
+	require.NoError(t, db.Create(beta).Error)
+
+	existing := createTestBugReport(t, db, alpha, user)
+	require.NoError(t, db.Model(existing).Updates(map[string]interface{}{
+		"title":       "Login button does nothing on Safari",
+		"description": "Clicking the login button has no effect at all",
+	}).Error)
+
+	submit := func(title, description, application string, forceCreate bool) (*httptest.ResponseRecorder, map[string]interface{}) {
+		body, err := json.Marshal(map[string]interface{}{
+			"title":            title,
+			"description":      description,
+			"application_name": application,
+			"force_create":     forceCreate,
+		})
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/bugs", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		mockAuthMiddleware(user.ID)(c)
+
+		handler.CreateBug(c)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w, response
+	}
+
+	assertSuggested := func(t *testing.T, w *httptest.ResponseRecorder, response map[string]interface{}) {
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "POSSIBLE_DUPLICATE", response["code"])
+		similar := response["suggested_duplicates"].([]interface{})
+		require.Len(t, similar, 1)
+		assert.Equal(t, existing.ID.String(), similar[0].(map[string]interface{})["id"])
+		assert.Equal(t, alpha.ID.String(), similar[0].(map[string]interface{})["application_id"])
+		assert.Greater(t, similar[0].(map[string]interface{})["similarity"], duplicateSimilarityThreshold)
+
+		var count int64
+		db.Model(&models.BugReport{}).Where("application_id = ?", alpha.ID).Count(&count)
+		assert.Equal(t, int64(1), count)
+	}
+
+	t.Run("similar title in the same application is suggested", func(t *testing.T) {
+		w, response := submit("Login button does nothing in Safari", "Nothing happens after entering my password", "Alpha App", false)
+		assertSuggested(t, w, response)
+	})
+
+	t.Run("similar description in the same application is suggested", func(t *testing.T) {
+		w, response := submit("Cannot sign in", "Clicking the login button has no effect at all!", "Alpha App", false)
+		assertSuggested(t, w, response)
+	})
+
+	t.Run("similar title in another application is accepted", func(t *testing.T) {
+		w, response := submit("Login button does nothing in Safari", "Clicking the login button has no effect at all", "Beta App", false)
+		require.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, false, response["bug"].(map[string]interface{})["duplicate_check_skipped"])
+	})
+
+	t.Run("different title and description in the same application is accepted", func(t *testing.T) {
+		w, _ := submit("Export to CSV hangs forever", "The export spinner never stops for large projects", "Alpha App", false)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("force_create creates the bug and flags it", func(t *testing.T) {
+		w, response := submit("Login button does nothing in Safari", "Clicking the login button has no effect at all", "Alpha App", true)
+		require.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, true, response["bug"].(map[string]interface{})["duplicate_check_skipped"])
+	})
+
+	t.Run("resolved bugs are not duplicates", func(t *testing.T) {
+		fixed := createTestBugReport(t, db, alpha, user)
+		require.NoError(t, db.Model(fixed).Updates(map[string]interface{}{
+			"title":       "Dark mode toggle resets after reload",
+			"description": "Switching to dark mode is forgotten on every reload",
+			"status":      models.BugStatusFixed,
+		}).Error)
+
+		w, _ := submit("Dark mode toggle resets after a reload", "Switching to dark mode is forgotten on every reload", "Alpha App", false)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("moderation view lists flagged bugs", func(t *testing.T) {
+		admin := NewAdminHandler(db, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/admin/bugs?duplicate_check_skipped=true", nil)
+		admin.ListBugsForModeration(c)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Bugs []models.BugReport `json:"bugs"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Bugs, 1)
+		assert.True(t, response.Bugs[0].DuplicateCheckSkipped)
+		assert.Equal(t, alpha.ID, response.Bugs[0].ApplicationID)
+	})
+}
+
+func TestBugHandler_CreateBug_DuplicateCheckUnavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Plain SQLite has no similarity function, so the check fails and is skipped
+	handler, db := setupBugTestHandler(t)
+	user := createTestUser(t, db)
+	app := createTestApplication(t, db)
+	createTestBugReport(t, db, app, user)
+
+	body, err := json.Marshal(map[string]interface{}{
+		"title":            "Test Bug",
+		"description":      "The same bug as the one already reported",
+		"application_name": app.Name,
+	})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/bugs", bytes.NewBuffer(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	handler.CreateBug(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestBugHandler_FindSimilarBugs(t *testing.T) {
+	db := setupDuplicateTestDB(t)
+	redisClient, mock := newMockRedisClient()
+	handler := NewBugHandler(db, redisClient)
+	user := createTestUser(t, db)
+
+	alpha := &models.Application{ID: uuid.New(), Name: "Alpha App"}
+	beta := &models.Application{ID: uuid.New(), Name: "Beta App"}
+	require.NoError(t, db.Create(alpha).Error)
+	require.NoError(t, db.Create(beta).Error)
+
+	titles := map[*models.Application][]string{
+		alpha: {"Crash when uploading large files", "Crash when uploading files", "Typo on the pricing page"},
+		beta:  {"Crash when uploading large files"},
+	}
+	for app, appTitles := range titles {
+		for _, title := range appTitles {
+			bug := createTestBugReport(t, db, app, user)
+			require.NoError(t, db.Model(bug).Update("title", title).Error)
+		}
+	}
+
+	similar, err := handler.FindSimilarBugs(db, "Crash when uploading large files", "Uploads stop", alpha.ID, duplicateSimilarityThreshold)
+	require.NoError(t, err)
+	require.Len(t, similar, 2)
+	for _, bug := range similar {
+		assert.Equal(t, alpha.ID, bug.ApplicationID)
+	}
+	// Most similar first
+	assert.Equal(t, "Crash when uploading large files", similar[0].Title)
+	assert.Equal(t, "Crash when uploading files", similar[1].Title)
+
+	// The result is cached for 30 seconds per application and submission
+	keys := mock.keysWithPrefix("app:" + alpha.ID.String() + ":similar:")
+	require.Len(t, keys, 1)
+	assert.Equal(t, 30*time.Second, mock.ttls[keys[0]])
+
+	require.NoError(t, db.Where("application_id = ?", alpha.ID).Delete(&models.BugReport{}).Error)
+	cached, err := handler.FindSimilarBugs(db, "crash when uploading large files ", "uploads stop", alpha.ID, duplicateSimilarityThreshold)
+	require.NoError(t, err)
+	assert.Len(t, cached, 2)
+
+	// Other applications have their own entries
+	other, err := handler.FindSimilarBugs(db, "Crash when uploading large files", "Uploads stop", beta.ID, duplicateSimilarityThreshold)
+	require.NoError(t, err)
+	require.Len(t, other, 1)
+	assert.Equal(t, beta.ID, other[0].ApplicationID)
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"bugrelay-backend/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorCodeHandler documents the error codes returned by the API
+type ErrorCodeHandler struct{}
+
+// NewErrorCodeHandler creates a new error code handler
+func NewErrorCodeHandler() *ErrorCodeHandler {
+	return &ErrorCodeHandler{}
+}
+
+// ListErrorCodes returns every registered error code with its HTTP status,
+// description and the endpoints that can return it
+func (h *ErrorCodeHandler) ListErrorCodes(c *gin.Context) {
+	codes := errors.All()
+	c.JSON(http.StatusOK, gin.H{
+		"error_codes": codes,
+		"total":       len(codes),
+	})
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bugrelay-backend/internal/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorCodeHandler_ListErrorCodes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/error-codes", NewErrorCodeHandler().ListErrorCodes)
+
+	req, _ := http.NewRequest("GET", "/error-codes", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		ErrorCodes []errors.ErrorCode `json:"error_codes"`
+		Total      int                `json:"total"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, len(errors.All()), response.Total)
+	require.Len(t, response.ErrorCodes, response.Total)
+
+	var bugNotFound *errors.ErrorCode
+	for i, code := range response.ErrorCodes {
+		if code.Code == "BUG_NOT_FOUND" {
+			bugNotFound = &response.ErrorCodes[i]
+		}
+	}
+	require.NotNil(t, bugNotFound)
+	assert.Equal(t, http.StatusNotFound, bugNotFound.HTTP)
+	assert.Equal(t, "Bug report not found", bugNotFound.Desc)
+	assert.Contains(t, bugNotFound.Endpoints, "GET /api/v1/bugs/:id")
+}
@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// bugListTemplate and bugTemplate are the HTML templates for ListBugs and GetBug
+	bugListTemplate = "bug_list.html"
+	bugTemplate     = "bug.html"
+
+	// metaDescriptionLength is the longest meta description, the length search
+	// engines show in results
+	metaDescriptionLength = 160
+
+	// htmlSiteName is appended to HTML page titles
+	htmlSiteName = "BugRelay"
+)
+
+// LoadHTMLTemplates parses the HTML templates in dir
+func LoadHTMLTemplates(dir string) (*template.Template, error) {
+	return template.ParseGlob(filepath.Join(dir, "*.html"))
+}
+
+// htmlPage holds the metadata every HTML page renders in its head
+type htmlPage struct {
+	Title          string
+	Description    string
+	CanonicalURL   string
+	StructuredData interface{}
+}
+
+// htmlBug is a bug report as shown on HTML pages. Text is unescaped since it is
+// stored HTML-escaped and the templates escape it again.
+type htmlBug struct {
+	URL             string
+	Title           string
+	Summary         string
+	Description     string
+	Status          string
+	Priority        string
+	Tags            []string
+	VoteCount       int
+	CommentCount    int
+	ApplicationName string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// bugListPage is the data for the bug list template
+type bugListPage struct {
+	htmlPage
+	Heading string
+	Total   int64
+	Bugs    []htmlBug
+}
+
+// bugPage is the data for the bug template
+type bugPage struct {
+	htmlPage
+	Bug htmlBug
+}
+
+// newHTMLBug converts a bug report for HTML pages
+func newHTMLBug(bug models.BugReport, bugURL string) htmlBug {
+	description := html.UnescapeString(bug.Description)
+
+	return htmlBug{
+		URL:             bugURL,
+		Title:           html.UnescapeString(bug.Title),
+		Summary:         truncateText(description, metaDescriptionLength),
+		Description:     description,
+		Status:          bug.Status,
+		Priority:        bug.Priority,
+		Tags:            bug.Tags,
+		VoteCount:       bug.VoteCount,
+		CommentCount:    bug.CommentCount,
+		ApplicationName: html.UnescapeString(bug.Application.Name),
+		CreatedAt:       bug.CreatedAt,
+		UpdatedAt:       bug.UpdatedAt,
+	}
+}
+
+// truncateText collapses whitespace in text and shortens it to at most max runes,
+// ending with an ellipsis when shortened
+func truncateText(text string, max int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:max-1])) + "…"
+}
+
+// respondNegotiated writes data as JSON, or renders the named HTML template for
+// clients that prefer text/html when HTML rendering is enabled. The template data
+// is only built for HTML responses. Clients accepting neither format still get
+// JSON, as they did before HTML rendering existed.
+func (h *BugHandler) respondNegotiated(c *gin.Context, data gin.H, htmlName string, htmlData func() interface{}) {
+	if !h.htmlRendering {
+		c.JSON(http.StatusOK, data)
+		return
+	}
+
+	c.Header("Vary", "Accept")
+	offered := []string{gin.MIMEJSON, gin.MIMEHTML}
+	if c.NegotiateFormat(offered...) != gin.MIMEHTML {
+		c.JSON(http.StatusOK, data)
+		return
+	}
+
+	c.Negotiate(http.StatusOK, gin.Negotiate{
+		Offered:  offered,
+		HTMLName: htmlName,
+		HTMLData: htmlData(),
+		JSONData: data,
+	})
+}
+
+// respondBugList writes a page of ListBugs results. Only the first page, requested
+// without a cursor, is rendered as HTML.
+func (h *BugHandler) respondBugList(c *gin.Context, req ListBugsRequest, bugs []models.BugReport, pagination gin.H) {
+	var tags []string
+	for _, bug := range bugs {
+		tags = append(tags, bug.Tags...)
+	}
+
+	data := gin.H{
+		"bugs":       bugs,
+		"pagination": pagination,
+		"_links":     gin.H{"tag_feed": tagFeedLinks(tags)},
+	}
+	if req.Page != 1 || req.Cursor != "" {
+		c.JSON(http.StatusOK, data)
+		return
+	}
+
+	h.respondNegotiated(c, data, bugListTemplate, func() interface{} {
+		var total int64
+		switch value := pagination["total"].(type) {
+		case int64:
+			total = value
+		case float64:
+			// Cached pagination has been through JSON
+			total = int64(value)
+		}
+		return newBugListPage(c.Request.URL, strings.TrimSpace(req.Search), total, bugs)
+	})
+}
+
+// newBugListPage builds the HTML page for a list of bugs
+func newBugListPage(requestURL *url.URL, search string, total int64, bugs []models.BugReport) bugListPage {
+	requestURI := requestURL.RequestURI()
+
+	page := bugListPage{
+		Heading: "Bug reports",
+		Total:   total,
+		Bugs:    make([]htmlBug, 0, len(bugs)),
+	}
+	page.Description = fmt.Sprintf("Browse %d publicly reported bugs, with their status, priority and votes.", total)
+	if search != "" {
+		page.Heading = fmt.Sprintf("Bug reports matching %q", search)
+		page.Description = fmt.Sprintf("%d publicly reported bugs matching %q.", total, search)
+	}
+	page.Title = page.Heading + " | " + htmlSiteName
+	page.CanonicalURL = requestURI
+
+	items := make([]gin.H, 0, len(bugs))
+	for i, bug := range bugs {
+		htmlBug := newHTMLBug(bug, path.Join(requestURL.Path, bug.ID.String()))
+		page.Bugs = append(page.Bugs, htmlBug)
+		items = append(items, gin.H{
+			"@type":    "ListItem",
+			"position": i + 1,
+			"url":      htmlBug.URL,
+			"name":     htmlBug.Title,
+		})
+	}
+	page.StructuredData = gin.H{
+		"@context":        "https://schema.org",
+		"@type":           "ItemList",
+		"name":            page.Heading,
+		"numberOfItems":   total,
+		"itemListElement": items,
+	}
+
+	return page
+}
+
+// newBugPage builds the HTML page for a single bug
+func newBugPage(selfPath string, bug models.BugReport) bugPage {
+	htmlBug := newHTMLBug(bug, selfPath)
+
+	page := bugPage{Bug: htmlBug}
+	page.Title = htmlBug.Title + " | " + htmlSiteName
+	page.Description = htmlBug.Summary
+	page.CanonicalURL = selfPath
+
+	structuredData := gin.H{
+		"@context":      "https://schema.org",
+		"@type":         "DiscussionForumPosting",
+		"headline":      htmlBug.Title,
+		"text":          htmlBug.Description,
+		"url":           selfPath,
+		"datePublished": htmlBug.CreatedAt.Format(time.RFC3339),
+		"dateModified":  htmlBug.UpdatedAt.Format(time.RFC3339),
+		"interactionStatistic": gin.H{
+			"@type":                "InteractionCounter",
+			"interactionType":      "https://schema.org/LikeAction",
+			"userInteractionCount": htmlBug.VoteCount,
+		},
+	}
+	if len(htmlBug.Tags) > 0 {
+		structuredData["keywords"] = strings.Join(htmlBug.Tags, ", ")
+	}
+	if htmlBug.ApplicationName != "" {
+		structuredData["about"] = gin.H{"@type": "SoftwareApplication", "name": htmlBug.ApplicationName}
+	}
+	page.StructuredData = structuredData
+
+	return page
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupHTMLRenderingRouter serves ListBugs and GetBug with the repository's HTML
+// templates loaded
+func setupHTMLRenderingRouter(t *testing.T, handler *BugHandler) *gin.Engine {
+	templates, err := LoadHTMLTemplates("../../templates/html")
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.SetHTMLTemplate(templates)
+	router.GET("/api/v1/bugs", handler.ListBugs)
+	router.GET("/api/v1/bugs/:id", handler.GetBug)
+	return router
+}
+
+func TestBugHandler_HTMLRendering(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	user := createTestUser(t, db)
+	app := createTestApplication(t, db)
+	bug := createTestBugReport(t, db, app, user)
+	require.NoError(t, db.Model(bug).Updates(map[string]interface{}{
+		"title":       "Crash when saving &lt;drafts&gt;",
+		"description": strings.Repeat("Saving a draft crashes the editor. ", 10),
+		"vote_count":  7,
+	}).Error)
+
+	router := setupHTMLRenderingRouter(t, handler)
+
+	get := func(path, accept string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	const browserAccept = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+	bugPath := "/api/v1/bugs/" + bug.ID.String()
+
+	// JSON responses are the same whether HTML rendering is enabled or not
+	disabledList := get("/api/v1/bugs", "")
+	disabledBug := get(bugPath, "application/json")
+	require.Equal(t, http.StatusOK, disabledList.Code)
+	require.Equal(t, http.StatusOK, disabledBug.Code)
+
+	t.Run("disabled rendering always returns JSON", func(t *testing.T) {
+		w := get(bugPath, browserAccept)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	})
+
+	handler.SetHTMLRendering(true)
+
+	t.Run("bug list", func(t *testing.T) {
+		w := get("/api/v1/bugs", browserAccept)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+		assert.Equal(t, "Accept", w.Header().Get("Vary"))
+
+		body := w.Body.String()
+		assert.Contains(t, body, "<title>Bug reports | BugRelay</title>")
+		assert.Contains(t, body, `<meta name="description" content="Browse 1 publicly reported bugs`)
+		assert.Contains(t, body, "<h1>Bug reports</h1>")
+		assert.Contains(t, body, `<a href="`+bugPath+`">Crash when saving &lt;drafts&gt;</a>`)
+		assert.Contains(t, body, `<script type="application/ld+json">`)
+		assert.Contains(t, body, `"@type":"ItemList"`)
+	})
+
+	t.Run("bug page", func(t *testing.T) {
+		w := get(bugPath, "text/html")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+
+		body := w.Body.String()
+		// Stored text is escaped once for HTML, not twice
+		assert.Contains(t, body, "<h1>Crash when saving &lt;drafts&gt;</h1>")
+		assert.Contains(t, body, "<title>Crash when saving &lt;drafts&gt; | BugRelay</title>")
+		assert.Contains(t, body, `"@type":"DiscussionForumPosting"`)
+		assert.Contains(t, body, `"userInteractionCount":7`)
+
+		// The meta description is a shortened summary of the description
+		start := strings.Index(body, `<meta name="description" content="`) + len(`<meta name="description" content="`)
+		description := body[start : start+strings.Index(body[start:], `"`)]
+		assert.True(t, strings.HasPrefix(description, "Saving a draft crashes the editor."))
+		assert.LessOrEqual(t, len([]rune(description)), metaDescriptionLength)
+	})
+
+	t.Run("JSON responses are unchanged", func(t *testing.T) {
+		list := get("/api/v1/bugs", "")
+		assert.Equal(t, disabledList.Body.String(), list.Body.String())
+		assert.Contains(t, list.Header().Get("Content-Type"), "application/json")
+
+		single := get(bugPath, "application/json")
+		assert.Equal(t, disabledBug.Body.String(), single.Body.String())
+
+		// Clients accepting neither format keep getting JSON
+		other := get(bugPath, "application/xml")
+		assert.Equal(t, http.StatusOK, other.Code)
+		assert.Equal(t, disabledBug.Body.String(), other.Body.String())
+	})
+
+	t.Run("only the first page is rendered as HTML", func(t *testing.T) {
+		w := get("/api/v1/bugs?page=2", browserAccept)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	})
+
+	t.Run("errors stay JSON", func(t *testing.T) {
+		w := get("/api/v1/bugs/not-a-uuid", browserAccept)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	})
+}
+
+func TestTruncateText(t *testing.T) {
+	assert.Equal(t, "short text", truncateText("short   text\n", 20))
+	assert.Equal(t, "abcd…", truncateText("abcdefgh", 5))
+	assert.Equal(t, "ab…", truncateText("ab  cdefgh", 4))
+	assert.Equal(t, "", truncateText("", 5))
+	assert.Len(t, []rune(truncateText(strings.Repeat("é", 200), metaDescriptionLength)), metaDescriptionLength)
+}
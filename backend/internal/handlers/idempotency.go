@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// idempotencyKeyHeader is the request header clients set to make a POST safe to retry
+	idempotencyKeyHeader = "X-Idempotency-Key"
+
+	// idempotencyReplayedHeader marks responses answered from a stored response
+	idempotencyReplayedHeader = "X-Idempotency-Replayed"
+)
+
+// idempotentResponse is a response stored for an idempotency key. While the first
+// request with the key is being processed, the key holds a response with InProgress
+// set instead.
+type idempotentResponse struct {
+	StatusCode int             `json:"status_code"`
+	Body       json.RawMessage `json:"body"`
+	InProgress bool            `json:"in_progress,omitempty"`
+}
+
+// idempotencyKeyHash returns the hash a request's idempotency key is stored under, or
+// an empty string when the request has no key. Keys are scoped to the user, or to
+// the client IP for anonymous requests, so clients cannot replay each other's keys,
+// and to the route, so a v1 response is never replayed to a v2 request.
+func idempotencyKeyHash(c *gin.Context) string {
+	key := strings.TrimSpace(c.GetHeader(idempotencyKeyHeader))
+	if key == "" {
+		return ""
+	}
+
+	owner := c.ClientIP()
+	if userID, exists := middleware.GetCurrentUserID(c); exists {
+		owner = userID
+	}
+
+	hash := sha256.Sum256([]byte(key + ":" + owner + ":" + c.FullPath()))
+	return hex.EncodeToString(hash[:])
+}
+
+// beginIdempotent reserves keyHash for the request and reports whether the request
+// should be processed. A retry of a request that has finished is answered with its
+// stored response, and one of a request still in progress with 409
+// IDEMPOTENCY_KEY_IN_USE, so a retry sent after a timeout cannot create a second bug.
+// A request that is processed must end with respondIdempotent or abortIdempotent.
+func (h *BugHandler) beginIdempotent(c *gin.Context, keyHash string) bool {
+	if keyHash == "" {
+		return true
+	}
+
+	ctx := c.Request.Context()
+	reserved, err := h.cache.ReserveIdempotencyKey(ctx, keyHash, idempotentResponse{InProgress: true})
+	if err != nil {
+		// Without the cache requests cannot be deduplicated, but they still succeed
+		logger.FromContext(ctx).Error("Failed to reserve idempotency key", err)
+		return true
+	}
+	if reserved {
+		return true
+	}
+
+	var stored idempotentResponse
+	if err := h.cache.GetIdempotentResponse(ctx, keyHash, &stored); err != nil || stored.InProgress {
+		errors.ErrIdempotencyKeyInUse.Response(c)
+		return false
+	}
+
+	c.Header(idempotencyReplayedHeader, "true")
+	c.Data(stored.StatusCode, "application/json; charset=utf-8", stored.Body)
+	return false
+}
+
+// abortIdempotent releases keyHash after the request failed, so the client can retry
+// it with the same key
+func (h *BugHandler) abortIdempotent(c *gin.Context, keyHash string) {
+	if keyHash == "" {
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.cache.ReleaseIdempotencyKey(ctx, keyHash); err != nil {
+		logger.FromContext(ctx).Error("Failed to release idempotency key", err)
+	}
+}
+
+// respondIdempotent writes a JSON response and, when the request has an idempotency
+// key, stores it so a retry of the request gets the same status and body
+func (h *BugHandler) respondIdempotent(c *gin.Context, keyHash string, statusCode int, body interface{}) {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		c.JSON(statusCode, body)
+		return
+	}
+
+	if keyHash != "" {
+		ctx := c.Request.Context()
+		if err := h.cache.SetIdempotentResponse(ctx, keyHash, idempotentResponse{StatusCode: statusCode, Body: encoded}); err != nil {
+			// Log cache error but don't fail the request
+			logger.FromContext(ctx).Error("Failed to store idempotent response", err)
+		}
+	}
+
+	c.Data(statusCode, "application/json; charset=utf-8", encoded)
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/middleware"
+	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// BlockIPRequest represents a request to block an IP address or CIDR range
+type BlockIPRequest struct {
+	IP            string `json:"ip" binding:"required,max=50"`
+	Reason        string `json:"reason" binding:"required,min=1,max=500"`
+	DurationHours int    `json:"duration_hours" binding:"required,min=1,max=8760"`
+}
+
+// SetIPBlocker sets the IP blocker that blocks are added to and lifted from
+func (h *AdminHandler) SetIPBlocker(ipBlocker *middleware.IPBlocker) {
+	h.ipBlocker = ipBlocker
+}
+
+// BlockIP blocks an IP address or CIDR range for a number of hours. Blocking an
+// address again replaces its earlier block.
+func (h *AdminHandler) BlockIP(c *gin.Context) {
+	var req BlockIPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+
+	ip, err := middleware.ParseIPBlock(req.IP)
+	if err != nil {
+		errors.ErrInvalidIPBlock.WithDetails(err.Error()).Response(c)
+		return
+	}
+
+	if h.ipBlocker == nil {
+		errors.ErrIPBlockerUnavailable.Response(c)
+		return
+	}
+
+	adminID, ok := currentUserUUID(c)
+	if !ok {
+		return
+	}
+
+	duration := time.Duration(req.DurationHours) * time.Hour
+	block := models.IPBlock{
+		IP:          ip,
+		Reason:      utils.SanitizeInput(req.Reason),
+		BlockedByID: adminID,
+		ExpiresAt:   time.Now().Add(duration).UTC(),
+	}
+	if err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("ip = ?", ip).Delete(&models.IPBlock{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&block).Error
+	}); err != nil {
+		errors.ErrIPBlockFailed.Response(c)
+		return
+	}
+
+	if err := h.ipBlocker.Block(c.Request.Context(), ip, duration); err != nil {
+		errors.ErrIPBlockFailed.WithMessage("Failed to activate IP block").Response(c)
+		return
+	}
+
+	details := fmt.Sprintf("Blocked %s for %d hours. Reason: %s", ip, req.DurationHours, block.Reason)
+	if err := h.logAuditAction(c, models.AuditActionIPBlock, models.AuditResourceIPBlock, &block.ID, details, nil, block); err != nil {
+		// Log error but don't fail the request since the block is already active
+		logger.FromContext(c.Request.Context()).Error("Failed to log audit action", err)
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "IP address blocked successfully",
+		"ip_block": block,
+	})
+}
+
+// UnblockIP lifts the block on an IP address or CIDR range. Ranges can be given
+// unescaped, e.g. DELETE /admin/ip-blocks/192.168.0.0/16.
+func (h *AdminHandler) UnblockIP(c *gin.Context) {
+	ip, err := middleware.ParseIPBlock(strings.TrimPrefix(c.Param("ip"), "/"))
+	if err != nil {
+		errors.ErrInvalidIPBlock.WithDetails(err.Error()).Response(c)
+		return
+	}
+
+	if h.ipBlocker == nil {
+		errors.ErrIPBlockerUnavailable.Response(c)
+		return
+	}
+
+	var block models.IPBlock
+	if err := h.db.Where("ip = ? AND expires_at > ?", ip, time.Now()).First(&block).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrIPBlockNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to fetch IP block").Response(c)
+		return
+	}
+
+	// The record is deleted first so the block is not restored from it
+	if err := h.db.Where("ip = ?", ip).Delete(&models.IPBlock{}).Error; err != nil {
+		errors.ErrDeleteFailed.WithMessage("Failed to delete IP block").Response(c)
+		return
+	}
+	if err := h.ipBlocker.Unblock(c.Request.Context(), ip); err != nil {
+		errors.ErrDeleteFailed.WithMessage("Failed to lift IP block").Response(c)
+		return
+	}
+
+	details := fmt.Sprintf("Unblocked %s, blocked until %s. Reason: %s", ip, block.ExpiresAt.UTC().Format(time.RFC3339), block.Reason)
+	if err := h.logAuditAction(c, models.AuditActionIPUnblock, models.AuditResourceIPBlock, &block.ID, details, block, nil); err != nil {
+		// Log error but don't fail the request since the block was lifted
+		logger.FromContext(c.Request.Context()).Error("Failed to log audit action", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "IP address unblocked successfully",
+		"ip":      ip,
+	})
+}
+
+// ListIPBlocks returns the IP blocks that have not expired with pagination, most
+// recent first
+func (h *AdminHandler) ListIPBlocks(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	if page <= 0 {
+		page = 1
+	}
+	limit = h.pagination.WithDefault(50).Limit(limit)
+
+	query := h.db.Model(&models.IPBlock{}).Where("expires_at > ?", time.Now())
+
+	var total int64
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	blocks := make([]models.IPBlock, 0)
+	if err := query.Preload("BlockedBy").Order("created_at DESC").Offset(offset).Limit(limit).Find(&blocks).Error; err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to fetch IP blocks").Response(c)
+		return
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	c.JSON(http.StatusOK, gin.H{
+		"ip_blocks": blocks,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": totalPages,
+			"has_next":    page < totalPages,
+			"has_prev":    page > 1,
+		},
+	})
+}
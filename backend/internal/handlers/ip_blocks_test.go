@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/middleware"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminHandler_IPBlocks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupAdminTestHandler(t)
+	admin := createTestAdmin(t, db)
+	blocker := middleware.NewIPBlocker(nil, nil)
+	handler.SetIPBlocker(blocker)
+
+	adminRouter := gin.New()
+	adminRouter.Use(mockAdminAuthMiddleware(admin.ID))
+	adminRouter.GET("/admin/ip-blocks", handler.ListIPBlocks)
+	adminRouter.POST("/admin/ip-blocks", handler.BlockIP)
+	adminRouter.DELETE("/admin/ip-blocks/*ip", handler.UnblockIP)
+
+	publicRouter := gin.New()
+	publicRouter.Use(blocker.IPBlockMiddleware())
+	publicRouter.GET("/bugs", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"bugs": []string{}})
+	})
+
+	send := func(method, path string, body interface{}) (*httptest.ResponseRecorder, map[string]interface{}) {
+		var reader *bytes.Buffer
+		if body != nil {
+			encoded, err := json.Marshal(body)
+			require.NoError(t, err)
+			reader = bytes.NewBuffer(encoded)
+		} else {
+			reader = bytes.NewBuffer(nil)
+		}
+		req, _ := http.NewRequest(method, path, reader)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		adminRouter.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w, response
+	}
+
+	visit := func(ip string) int {
+		req, _ := http.NewRequest("GET", "/bugs", nil)
+		req.RemoteAddr = ip + ":12345"
+		w := httptest.NewRecorder()
+		publicRouter.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	auditActions := func(action string) []models.AuditLog {
+		var logs []models.AuditLog
+		require.NoError(t, db.Where("action = ?", action).Find(&logs).Error)
+		return logs
+	}
+
+	t.Run("blocks a range", func(t *testing.T) {
+		w, response := send("POST", "/admin/ip-blocks", map[string]interface{}{
+			"ip":             "198.51.100.23/24",
+			"reason":         "Spam bot rotating user agents",
+			"duration_hours": 24,
+		})
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		block := response["ip_block"].(map[string]interface{})
+		assert.Equal(t, "198.51.100.0/24", block["ip"])
+		assert.Equal(t, admin.ID.String(), block["blocked_by_id"])
+
+		assert.Equal(t, http.StatusForbidden, visit("198.51.100.200"))
+		assert.Equal(t, http.StatusOK, visit("198.51.101.1"))
+
+		logs := auditActions(models.AuditActionIPBlock)
+		require.Len(t, logs, 1)
+		assert.Equal(t, models.AuditResourceIPBlock, logs[0].Resource)
+		assert.Contains(t, logs[0].Details, "198.51.100.0/24")
+	})
+
+	t.Run("rejects invalid addresses", func(t *testing.T) {
+		for _, ip := range []string{"not-an-ip", "10.0.0.0/4"} {
+			w, response := send("POST", "/admin/ip-blocks", map[string]interface{}{
+				"ip":             ip,
+				"reason":         "Testing",
+				"duration_hours": 1,
+			})
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, "INVALID_IP_BLOCK", response["error"].(map[string]interface{})["code"])
+		}
+
+		w, _ := send("POST", "/admin/ip-blocks", map[string]interface{}{
+			"ip":             "203.0.113.7",
+			"reason":         "Testing",
+			"duration_hours": 0,
+		})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("lists active blocks", func(t *testing.T) {
+		w, _ := send("POST", "/admin/ip-blocks", map[string]interface{}{
+			"ip":             "203.0.113.7",
+			"reason":         "Credential stuffing",
+			"duration_hours": 1,
+		})
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		// An expired block is left out
+		expired := models.IPBlock{IP: "203.0.113.8", Reason: "Old", BlockedByID: admin.ID, ExpiresAt: time.Now().Add(-time.Hour)}
+		require.NoError(t, db.Create(&expired).Error)
+
+		w, response := send("GET", "/admin/ip-blocks?limit=1", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		blocks := response["ip_blocks"].([]interface{})
+		require.Len(t, blocks, 1)
+		assert.Equal(t, "203.0.113.7", blocks[0].(map[string]interface{})["ip"])
+
+		pagination := response["pagination"].(map[string]interface{})
+		assert.Equal(t, float64(2), pagination["total"])
+		assert.Equal(t, true, pagination["has_next"])
+	})
+
+	t.Run("unblocks a range", func(t *testing.T) {
+		w, response := send("DELETE", "/admin/ip-blocks/198.51.100.0/24", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "198.51.100.0/24", response["ip"])
+		assert.Equal(t, http.StatusOK, visit("198.51.100.200"))
+
+		var count int64
+		require.NoError(t, db.Model(&models.IPBlock{}).Where("ip = ?", "198.51.100.0/24").Count(&count).Error)
+		assert.Zero(t, count)
+		assert.Len(t, auditActions(models.AuditActionIPUnblock), 1)
+
+		w, response = send("DELETE", "/admin/ip-blocks/198.51.100.0/24", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "IP_BLOCK_NOT_FOUND", response["error"].(map[string]interface{})["code"])
+	})
+}
@@ -60,6 +60,9 @@ type FrontendLogsPayload struct {
 func (h *LogsHandler) ReceiveFrontendLogs(c *gin.Context) {
 	var payload FrontendLogsPayload
 	if err := c.ShouldBindJSON(&payload); err != nil {
+		if requestBodyTooLarge(c, err) {
+			return
+		}
 		logger.WithRequest(c).WithFields(logger.Fields{
 			"error": err.Error(),
 		}).Warn("Invalid frontend logs payload")
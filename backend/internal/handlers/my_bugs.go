@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"net/http"
+
+	"bugrelay-backend/internal/cache"
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ListMyBugsRequest represents query parameters for listing the current user's bugs
+type ListMyBugsRequest struct {
+	Page     int    `form:"page,default=1"`
+	Limit    int    `form:"limit,default=20"`
+	Status   string `form:"status"`
+	Priority string `form:"priority"`
+	Sort     string `form:"sort,default=recent"`
+}
+
+// ListMyBugs lists the bugs reported by the current user, including anonymous
+// submissions they have since claimed. Bugs can be filtered by status and priority
+// and sorted by recent, oldest or popular.
+func (h *UserHandler) ListMyBugs(c *gin.Context) {
+	userID, ok := currentUserUUID(c)
+	if !ok {
+		return
+	}
+
+	var req ListMyBugsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		errors.ErrValidationError.WithMessage("Invalid query parameters").WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+
+	// Validate and set limits
+	req.Limit = h.pagination.Limit(req.Limit)
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+
+	if req.Status != "" && !models.IsValidStatus(req.Status) {
+		errors.ErrInvalidStatus.Response(c)
+		return
+	}
+	if req.Priority != "" && !models.IsValidPriority(req.Priority) {
+		errors.ErrInvalidPriority.Response(c)
+		return
+	}
+	switch req.Sort {
+	case "recent", "oldest", "popular":
+	default:
+		errors.ErrInvalidSort.WithMessage("Sort must be one of recent, oldest, popular").Response(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	type CachedResponse struct {
+		Bugs       []models.BugReport     `json:"bugs"`
+		Pagination map[string]interface{} `json:"pagination"`
+	}
+
+	cacheKey := cache.GenerateCacheKey(req.Page, req.Limit, req.Status, req.Priority, req.Sort)
+
+	var cachedResp CachedResponse
+	if err := h.cache.GetUserBugList(ctx, userID.String(), cacheKey, &cachedResp); err == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"bugs":       cachedResp.Bugs,
+			"pagination": cachedResp.Pagination,
+		})
+		return
+	}
+
+	// Claiming an anonymous bug makes the user its reporter, so claimed bugs are
+	// included. Bugs marked as spam are listed too, so reporters cannot tell their
+	// submissions were caught.
+	filtered := func() *gorm.DB {
+		query := h.db.Model(&models.BugReport{}).Where("reporter_id = ?", userID)
+		if req.Status != "" {
+			query = query.Where("status = ?", req.Status)
+		}
+		if req.Priority != "" {
+			query = query.Where("priority = ?", req.Priority)
+		}
+		return query
+	}
+
+	var total int64
+	if err := filtered().Count(&total).Error; err != nil {
+		errors.ErrCountFailed.WithMessage("Failed to count bug reports").Response(c)
+		return
+	}
+
+	query := filtered().Preload("Application").Preload("AssignedCompany")
+	switch req.Sort {
+	case "popular":
+		query = query.Order("weighted_vote_count DESC").Order("created_at DESC")
+	case "oldest":
+		query = query.Order("created_at ASC")
+	default:
+		query = query.Order("created_at DESC")
+	}
+
+	bugs := make([]models.BugReport, 0)
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Offset(offset).Limit(req.Limit).Find(&bugs).Error; err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to fetch bug reports").Response(c)
+		return
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+	paginationInfo := gin.H{
+		"page":        req.Page,
+		"limit":       req.Limit,
+		"total":       total,
+		"total_pages": totalPages,
+		"has_next":    req.Page < totalPages,
+		"has_prev":    req.Page > 1,
+	}
+
+	if err := h.cache.SetUserBugList(ctx, userID.String(), cacheKey, CachedResponse{
+		Bugs:       bugs,
+		Pagination: paginationInfo,
+	}); err != nil {
+		// Log cache error but don't fail the request
+		logger.FromContext(ctx).Error("Failed to cache user bug list", err, logger.Fields{"user_id": userID.String()})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"bugs":       bugs,
+		"pagination": paginationInfo,
+	})
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/cache"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserHandler_ListMyBugs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupBugTestDB(t)
+	redisClient, mock := newMockRedisClient()
+	handler := NewUserHandler(db, redisClient)
+	user := createTestUser(t, db)
+	other := &models.User{ID: uuid.New(), Email: "other@example.com", DisplayName: "Other User"}
+	require.NoError(t, db.Create(other).Error)
+	app := createTestApplication(t, db)
+
+	start := time.Now().Add(-time.Hour)
+	var mine []uuid.UUID
+	for i := 0; i < 3; i++ {
+		bug := createTestBugReport(t, db, app, user)
+		require.NoError(t, db.Model(bug).Update("created_at", start.Add(time.Duration(i)*time.Minute)).Error)
+		mine = append(mine, bug.ID)
+	}
+	createTestBugReport(t, db, app, other)
+
+	// An anonymous submission the user claimed afterwards
+	claimed := &models.BugReport{
+		ID:            uuid.New(),
+		Title:         "Anonymous bug",
+		Description:   "This is an anonymous bug report with sufficient length",
+		Status:        models.BugStatusFixed,
+		Priority:      models.BugPriorityHigh,
+		ApplicationID: app.ID,
+		CreatedAt:     start.Add(10 * time.Minute),
+	}
+	require.NoError(t, db.Create(claimed).Error)
+	require.NoError(t, db.Model(claimed).Update("reporter_id", user.ID).Error)
+
+	list := func(userID uuid.UUID, query string) (*httptest.ResponseRecorder, []models.BugReport, map[string]interface{}) {
+		router := gin.New()
+		router.Use(mockAuthMiddleware(userID))
+		router.GET("/me/bugs", handler.ListMyBugs)
+
+		req, _ := http.NewRequest("GET", "/me/bugs"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response struct {
+			Bugs       []models.BugReport     `json:"bugs"`
+			Pagination map[string]interface{} `json:"pagination"`
+		}
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		}
+		return w, response.Bugs, response.Pagination
+	}
+
+	ids := func(bugs []models.BugReport) []uuid.UUID {
+		result := make([]uuid.UUID, 0, len(bugs))
+		for _, bug := range bugs {
+			result = append(result, bug.ID)
+		}
+		return result
+	}
+
+	t.Run("lists the user's bugs including claimed ones", func(t *testing.T) {
+		w, bugs, pagination := list(user.ID, "")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		// Newest first, without the other user's bug
+		assert.Equal(t, []uuid.UUID{claimed.ID, mine[2], mine[1], mine[0]}, ids(bugs))
+		assert.Equal(t, float64(4), pagination["total"])
+	})
+
+	t.Run("filters and sorts", func(t *testing.T) {
+		w, bugs, _ := list(user.ID, "?status=fixed")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []uuid.UUID{claimed.ID}, ids(bugs))
+
+		w, bugs, _ = list(user.ID, "?sort=oldest&priority=medium")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, mine, ids(bugs))
+
+		w, _, _ = list(user.ID, "?sort=votes")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_SORT")
+
+		w, _, _ = list(user.ID, "?status=closed")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_STATUS")
+	})
+
+	t.Run("paginates", func(t *testing.T) {
+		w, bugs, pagination := list(user.ID, "?limit=3&page=2")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []uuid.UUID{mine[0]}, ids(bugs))
+		assert.Equal(t, float64(2), pagination["total_pages"])
+		assert.Equal(t, false, pagination["has_next"])
+		assert.Equal(t, true, pagination["has_prev"])
+	})
+
+	t.Run("excludes other users' bugs", func(t *testing.T) {
+		w, bugs, _ := list(other.ID, "")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Len(t, bugs, 1)
+		assert.Equal(t, other.ID, *bugs[0].ReporterID)
+	})
+
+	t.Run("caches pages until invalidated", func(t *testing.T) {
+		key := cache.UserCachePrefix + user.ID.String() + ":bugs:" + cache.GenerateCacheKey(1, 20, "", "", "recent")
+		assert.Equal(t, cache.UserBugListCacheDuration, mock.ttls[key])
+
+		createTestBugReport(t, db, app, user)
+		_, bugs, _ := list(user.ID, "")
+		assert.Len(t, bugs, 4)
+
+		require.NoError(t, handler.cache.InvalidateUserBugList(t.Context(), user.ID.String()))
+		_, bugs, _ = list(user.ID, "")
+		assert.Len(t, bugs, 5)
+
+		// Other users' pages are kept
+		otherKey := fmt.Sprintf("%s%s:bugs:%s", cache.UserCachePrefix, other.ID, cache.GenerateCacheKey(1, 20, "", "", "recent"))
+		assert.Contains(t, mock.values, otherKey)
+	})
+}
+
+func TestBugHandler_CreateBug_InvalidatesUserBugList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupBugTestDB(t)
+	redisClient, mock := newMockRedisClient()
+	bugHandler := NewBugHandler(db, redisClient)
+	user := createTestUser(t, db)
+
+	key := cache.UserCachePrefix + user.ID.String() + ":bugs:page"
+	require.NoError(t, bugHandler.cache.SetUserBugList(t.Context(), user.ID.String(), "page", gin.H{"bugs": []string{}}))
+	require.Contains(t, mock.values, key)
+
+	body, err := json.Marshal(map[string]interface{}{
+		"title":            "Cache invalidation bug",
+		"description":      "This is a valid bug description with sufficient length",
+		"application_name": "Cache App",
+	})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/bugs", bytes.NewBuffer(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	mockAuthMiddleware(user.ID)(c)
+	bugHandler.CreateBug(c)
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.NotContains(t, mock.values, key)
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/notification"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHeartbeatInterval is how often an idle notification stream sends a
+// comment line, so proxies don't close the connection
+const NotificationHeartbeatInterval = 30 * time.Second
+
+// NotificationStreamHandler streams real-time bug notifications to users as
+// Server-Sent Events
+type NotificationStreamHandler struct {
+	hub               *notification.Hub
+	heartbeatInterval time.Duration
+}
+
+// NewNotificationStreamHandler creates a notification stream handler for the hub's events
+func NewNotificationStreamHandler(hub *notification.Hub) *NotificationStreamHandler {
+	return &NotificationStreamHandler{
+		hub:               hub,
+		heartbeatInterval: NotificationHeartbeatInterval,
+	}
+}
+
+// StreamNotifications keeps the connection open and sends the current user an event
+// whenever a bug they reported, voted on or commented on changes. Each event is
+// named after its type and carries the notification.Event as JSON.
+func (h *NotificationStreamHandler) StreamNotifications(c *gin.Context) {
+	userID, ok := currentUserUUID(c)
+	if !ok {
+		return
+	}
+
+	events, unsubscribe := h.hub.Subscribe(userID)
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Stop nginx from buffering the stream
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			c.SSEvent(event.Type, event)
+		case <-heartbeat.C:
+			if _, err := io.WriteString(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+// SetNotificationHub sets the hub that bug status changes, comments and company
+// responses are published to
+func (h *BugHandler) SetNotificationHub(hub *notification.Hub) {
+	h.notifications = hub
+}
+
+// publishBugEvent sends a real-time event to the users involved with the bug. It
+// runs after the change is saved, so a failure is only logged.
+func (h *BugHandler) publishBugEvent(ctx context.Context, event notification.Event) {
+	if h.notifications == nil {
+		return
+	}
+	if err := h.notifications.PublishBugEvent(ctx, event); err != nil {
+		logger.FromContext(ctx).Error("Failed to publish bug notification", err, logger.Fields{
+			"bug_id": event.BugID.String(),
+			"type":   event.Type,
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/notification"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openNotificationStream connects userID to the notification stream and returns
+// the lines it receives
+func openNotificationStream(t *testing.T, hub *notification.Hub, userID uuid.UUID, heartbeat time.Duration) <-chan string {
+	handler := NewNotificationStreamHandler(hub)
+	handler.heartbeatInterval = heartbeat
+
+	router := gin.New()
+	router.GET("/notifications/stream", mockAuthMiddleware(userID), handler.StreamNotifications)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	resp, err := http.Get(server.URL + "/notifications/stream")
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "no-cache", resp.Header.Get("Cache-Control"))
+
+	lines := make(chan string, 100)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	return lines
+}
+
+// nextStreamEvent returns the name and data of the next event on the stream,
+// skipping heartbeats
+func nextStreamEvent(t *testing.T, lines <-chan string) (string, notification.Event) {
+	t.Helper()
+	var name string
+	for {
+		select {
+		case line, ok := <-lines:
+			require.True(t, ok, "stream closed")
+			switch {
+			case strings.HasPrefix(line, "event:"):
+				name = strings.TrimPrefix(line, "event:")
+			case strings.HasPrefix(line, "data:"):
+				var event notification.Event
+				require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data:")), &event))
+				return name, event
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("no event received")
+		}
+	}
+}
+
+func TestNotificationStreamHandler_Heartbeat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	lines := openNotificationStream(t, notification.NewHub(setupBugTestDB(t)), uuid.New(), 10*time.Millisecond)
+
+	select {
+	case line := <-lines:
+		assert.Equal(t, ": ping", line)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no heartbeat received")
+	}
+}
+
+func TestBugHandler_PublishesNotifications(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	hub := notification.NewHub(db)
+	handler.SetNotificationHub(hub)
+
+	reporter := createTestUser(t, db)
+	commenter := &models.User{ID: uuid.New(), Email: "commenter@example.com", DisplayName: "Commenter"}
+	require.NoError(t, db.Create(commenter).Error)
+	companyUser := &models.User{ID: uuid.New(), Email: "member@example.com", DisplayName: "Member"}
+	require.NoError(t, db.Create(companyUser).Error)
+
+	app := createTestApplication(t, db)
+	bug := createTestBugReport(t, db, app, reporter)
+	company := &models.Company{ID: uuid.New(), Name: "Test Company", Domain: "testcompany.com"}
+	require.NoError(t, db.Create(company).Error)
+	require.NoError(t, db.Create(&models.CompanyMember{ID: uuid.New(), CompanyID: company.ID, UserID: companyUser.ID, Role: "member"}).Error)
+	require.NoError(t, db.Model(bug).Update("assigned_company_id", company.ID).Error)
+
+	reporterStream := openNotificationStream(t, hub, reporter.ID, time.Hour)
+
+	call := func(action gin.HandlerFunc, path string, userID uuid.UUID, payload interface{}) {
+		body, err := json.Marshal(payload)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", fmt.Sprintf(path, bug.ID), bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "id", Value: bug.ID.String()}}
+		c.Set("user_id", userID.String())
+		c.Set("is_admin", false)
+
+		action(c)
+		require.Less(t, w.Code, 300, w.Body.String())
+	}
+
+	t.Run("comment", func(t *testing.T) {
+		call(handler.CreateComment, "/bugs/%s/comments", commenter.ID, map[string]string{"content": "Happens for me too on the latest version"})
+
+		name, event := nextStreamEvent(t, reporterStream)
+		assert.Equal(t, notification.EventCommentCreated, name)
+		assert.Equal(t, bug.ID, event.BugID)
+		assert.Equal(t, commenter.ID, event.ActorID)
+	})
+
+	// The commenter is now involved with the bug
+	commenterStream := openNotificationStream(t, hub, commenter.ID, time.Hour)
+
+	t.Run("status change", func(t *testing.T) {
+		call(handler.UpdateBugStatus, "/bugs/%s/status", companyUser.ID, map[string]string{"status": models.BugStatusFixed})
+
+		for _, stream := range []<-chan string{reporterStream, commenterStream} {
+			name, event := nextStreamEvent(t, stream)
+			assert.Equal(t, notification.EventBugStatusChanged, name)
+			assert.Equal(t, map[string]interface{}{"from": models.BugStatusOpen, "to": models.BugStatusFixed}, event.Data)
+		}
+	})
+
+	t.Run("company response", func(t *testing.T) {
+		call(handler.AddCompanyResponse, "/bugs/%s/company-response", companyUser.ID, map[string]string{"content": "Thanks, a fix ships in the next release"})
+
+		for _, stream := range []<-chan string{reporterStream, commenterStream} {
+			name, event := nextStreamEvent(t, stream)
+			assert.Equal(t, notification.EventCompanyResponse, name)
+			assert.Equal(t, companyUser.ID, event.ActorID)
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"bugrelay-backend/internal/cache"
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UpdateNotificationPreferencesRequest represents the request to change notification
+// preferences. Omitted fields are left unchanged.
+type UpdateNotificationPreferencesRequest struct {
+	BugStatusChange *bool   `json:"bug_status_change"`
+	CommentOnMyBug  *bool   `json:"comment_on_my_bug"`
+	CommentReply    *bool   `json:"comment_reply"`
+	VoteMilestone   *bool   `json:"vote_milestone"`
+	CompanyResponse *bool   `json:"company_response"`
+	Mention         *bool   `json:"mention"`
+	DigestFrequency *string `json:"digest_frequency"`
+	Marketing       *bool   `json:"marketing"`
+}
+
+// GetNotificationPreferences returns the current user's notification preferences
+func (h *UserHandler) GetNotificationPreferences(c *gin.Context) {
+	userID, ok := currentUserUUID(c)
+	if !ok {
+		return
+	}
+
+	preferences, err := loadNotificationPreferences(c.Request.Context(), h.db, h.cache, userID)
+	if err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to fetch notification preferences").Response(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"preferences": preferences,
+	})
+}
+
+// UpdateNotificationPreferences changes the current user's notification preferences
+func (h *UserHandler) UpdateNotificationPreferences(c *gin.Context) {
+	var req UpdateNotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+
+	if req.DigestFrequency != nil && !models.IsValidDigestFrequency(*req.DigestFrequency) {
+		errors.ErrInvalidDigestFrequency.Response(c)
+		return
+	}
+
+	userID, ok := currentUserUUID(c)
+	if !ok {
+		return
+	}
+
+	// Updates with a map so false is written rather than skipped as a zero value
+	updates := map[string]interface{}{}
+	flags := map[string]*bool{
+		models.NotificationTypeBugStatusChange: req.BugStatusChange,
+		models.NotificationTypeCommentOnMyBug:  req.CommentOnMyBug,
+		models.NotificationTypeCommentReply:    req.CommentReply,
+		models.NotificationTypeVoteMilestone:   req.VoteMilestone,
+		models.NotificationTypeCompanyResponse: req.CompanyResponse,
+		models.NotificationTypeMention:         req.Mention,
+		models.NotificationTypeMarketing:       req.Marketing,
+	}
+	for column, value := range flags {
+		if value != nil {
+			updates[column] = *value
+		}
+	}
+	if req.DigestFrequency != nil {
+		updates["digest_frequency"] = *req.DigestFrequency
+	}
+
+	preferences, err := h.saveNotificationPreferences(c.Request.Context(), userID, updates)
+	if err != nil {
+		errors.ErrUpdateFailed.WithMessage("Failed to update notification preferences").Response(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Notification preferences updated successfully",
+		"preferences": preferences,
+	})
+}
+
+// saveNotificationPreferences applies updates to a user's preferences, creating the
+// defaults first if the user has none, and clears the cached copy
+func (h *UserHandler) saveNotificationPreferences(ctx context.Context, userID uuid.UUID, updates map[string]interface{}) (*models.NotificationPreferences, error) {
+	var preferences models.NotificationPreferences
+	err := h.db.Where("user_id = ?", userID).
+		Attrs(models.DefaultNotificationPreferences(userID)).
+		FirstOrCreate(&preferences).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(updates) > 0 {
+		if err := h.db.Model(&preferences).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if err := h.cache.InvalidateNotificationPreferences(ctx, userID.String()); err != nil {
+		// Log cache error but don't fail the request
+		logger.FromContext(ctx).Error("Failed to invalidate notification preferences", err, logger.Fields{"user_id": userID.String()})
+	}
+
+	return &preferences, nil
+}
+
+// loadNotificationPreferences returns the user's notification preferences, using the
+// cache when available. Users without a record get the defaults.
+func loadNotificationPreferences(ctx context.Context, db *gorm.DB, cacheService *cache.CacheService, userID uuid.UUID) (*models.NotificationPreferences, error) {
+	var preferences models.NotificationPreferences
+	if err := cacheService.GetNotificationPreferences(ctx, userID.String(), &preferences); err == nil {
+		return &preferences, nil
+	}
+
+	err := db.Where("user_id = ?", userID).First(&preferences).Error
+	if err == gorm.ErrRecordNotFound {
+		preferences = models.DefaultNotificationPreferences(userID)
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := cacheService.SetNotificationPreferences(ctx, userID.String(), preferences); err != nil {
+		// Log cache error but don't fail the request
+		logger.FromContext(ctx).Error("Failed to cache notification preferences", err, logger.Fields{"user_id": userID.String()})
+	}
+
+	return &preferences, nil
+}
+
+// queueNotificationEmail queues an email for a user through the outbox, unless the
+// user has turned off the notification type. Pass the transaction of the change that
+// triggered the notification so both commit together.
+func queueNotificationEmail(ctx context.Context, tx *gorm.DB, cacheService *cache.CacheService, userID uuid.UUID, notificationType string, build func(recipient models.User) models.EmailPayload) error {
+	preferences, err := loadNotificationPreferences(ctx, tx, cacheService, userID)
+	if err != nil {
+		return err
+	}
+	if !preferences.Allows(notificationType) {
+		return nil
+	}
+
+	var recipient models.User
+	if err := tx.First(&recipient, "id = ?", userID).Error; err != nil {
+		return err
+	}
+
+	return models.EnqueueOutboxEvent(tx, models.OutboxEventEmail, build(recipient))
+}
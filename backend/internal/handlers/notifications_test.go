@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"bugrelay-backend/internal/cache"
+	"bugrelay-backend/internal/email"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBugHandler_UpdateBugStatus_NotifiesReporter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	handler.SetDeepLinks(email.NewDeepLinkGenerator("https://bugrelay.example.com", "secret"))
+	reporter := createTestUser(t, db)
+	app := createTestApplication(t, db)
+
+	admin := createTestUserWithEmail(t, db, "admin@example.com")
+	require.NoError(t, db.Model(admin).Update("is_admin", true).Error)
+
+	updateStatus := func(bug *models.BugReport, status string) {
+		body, _ := json.Marshal(map[string]string{"status": status})
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("PATCH", "/bugs/"+bug.ID.String()+"/status", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "id", Value: bug.ID.String()}}
+		c.Set("user_id", admin.ID.String())
+		c.Set("is_admin", true)
+
+		handler.UpdateBugStatus(c)
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+	queuedEmails := func() []models.EmailPayload {
+		var events []models.OutboxEvent
+		require.NoError(t, db.Where("event_type = ?", models.OutboxEventEmail).Find(&events).Error)
+
+		var payloads []models.EmailPayload
+		for _, event := range events {
+			var payload models.EmailPayload
+			require.NoError(t, json.Unmarshal(event.Payload, &payload))
+			payloads = append(payloads, payload)
+		}
+		return payloads
+	}
+
+	t.Run("fixed bug queues an email with deep links", func(t *testing.T) {
+		bug := createTestBugReport(t, db, app, reporter)
+		updateStatus(bug, models.BugStatusReviewing)
+		assert.Empty(t, queuedEmails())
+
+		updateStatus(bug, models.BugStatusFixed)
+		emails := queuedEmails()
+		require.Len(t, emails, 1)
+		assert.Equal(t, []string{reporter.Email}, emails[0].To)
+		assert.Contains(t, emails[0].Body, "https://bugrelay.example.com/bugs/"+bug.ID.String())
+		assert.Contains(t, emails[0].Body, "https://bugrelay.example.com/unsubscribe?")
+	})
+
+	t.Run("no email when the reporter opted out", func(t *testing.T) {
+		require.NoError(t, db.Where("event_type = ?", models.OutboxEventEmail).Delete(&models.OutboxEvent{}).Error)
+		preferences := models.NotificationPreferences{UserID: reporter.ID}
+		require.NoError(t, db.Create(&preferences).Error)
+		require.NoError(t, db.Model(&preferences).Update("bug_status_change", false).Error)
+
+		bug := createTestBugReport(t, db, app, reporter)
+		updateStatus(bug, models.BugStatusFixed)
+		assert.Empty(t, queuedEmails())
+	})
+}
+
+func TestUserHandler_Unsubscribe(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupBugTestDB(t)
+	links := email.NewDeepLinkGenerator("https://bugrelay.example.com", "secret")
+	handler := NewUserHandler(db, nil)
+	handler.SetDeepLinks(links)
+	user := createTestUser(t, db)
+
+	router := gin.New()
+	router.GET("/unsubscribe", handler.Unsubscribe)
+
+	unsubscribe := func(link string) *httptest.ResponseRecorder {
+		parsed, err := url.Parse(link)
+		require.NoError(t, err)
+
+		req, _ := http.NewRequest("GET", "/unsubscribe?"+parsed.RawQuery, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	preferences := func() models.NotificationPreferences {
+		var prefs models.NotificationPreferences
+		require.NoError(t, db.Where("user_id = ?", user.ID).First(&prefs).Error)
+		return prefs
+	}
+	errorCode := func(w *httptest.ResponseRecorder) interface{} {
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response["error"].(map[string]interface{})["code"]
+	}
+
+	t.Run("rejects a tampered signature", func(t *testing.T) {
+		link := links.GenerateUnsubscribeLink(user.ID, models.NotificationTypeMention)
+		link = strings.Replace(link, "type=mention", "type=all", 1)
+
+		w := unsubscribe(link)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "INVALID_SIGNATURE", errorCode(w))
+	})
+
+	t.Run("rejects an unknown type", func(t *testing.T) {
+		w := unsubscribe(links.GenerateUnsubscribeLink(user.ID, "newsletter"))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "INVALID_NOTIFICATION_TYPE", errorCode(w))
+	})
+
+	t.Run("rejects an unknown user", func(t *testing.T) {
+		w := unsubscribe(links.GenerateUnsubscribeLink(uuid.New(), models.NotificationTypeMention))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "USER_NOT_FOUND", errorCode(w))
+	})
+
+	t.Run("turns off a single type", func(t *testing.T) {
+		w := unsubscribe(links.GenerateUnsubscribeLink(user.ID, models.NotificationTypeMention))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		prefs := preferences()
+		assert.False(t, prefs.Mention)
+		assert.True(t, prefs.BugStatusChange)
+		assert.True(t, prefs.CompanyResponse)
+	})
+
+	t.Run("turns off every type", func(t *testing.T) {
+		w := unsubscribe(links.GenerateUnsubscribeLink(user.ID, models.NotificationTypeAll))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		prefs := preferences()
+		for _, notificationType := range models.NotificationTypes {
+			assert.False(t, prefs.Allows(notificationType), notificationType)
+		}
+	})
+}
+
+func TestUserHandler_NotificationPreferences(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupBugTestDB(t)
+	handler := NewUserHandler(db, nil)
+	user := createTestUser(t, db)
+
+	router := gin.New()
+	router.Use(mockAuthMiddleware(user.ID))
+	router.GET("/me/notification-preferences", handler.GetNotificationPreferences)
+	router.PATCH("/me/notification-preferences", handler.UpdateNotificationPreferences)
+
+	request := func(method string, body interface{}) (*httptest.ResponseRecorder, models.NotificationPreferences) {
+		var payload bytes.Buffer
+		if body != nil {
+			require.NoError(t, json.NewEncoder(&payload).Encode(body))
+		}
+		req, _ := http.NewRequest(method, "/me/notification-preferences", &payload)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response struct {
+			Preferences models.NotificationPreferences `json:"preferences"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w, response.Preferences
+	}
+
+	t.Run("defaults without a record", func(t *testing.T) {
+		w, prefs := request("GET", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, prefs.BugStatusChange)
+		assert.True(t, prefs.CommentOnMyBug)
+		assert.True(t, prefs.VoteMilestone)
+		assert.False(t, prefs.Marketing)
+		assert.Equal(t, models.DigestFrequencyNever, prefs.DigestFrequency)
+	})
+
+	t.Run("updates only the given fields", func(t *testing.T) {
+		w, prefs := request("PATCH", map[string]interface{}{
+			"comment_reply":    false,
+			"marketing":        true,
+			"digest_frequency": models.DigestFrequencyWeekly,
+		})
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.False(t, prefs.CommentReply)
+		assert.True(t, prefs.Marketing)
+		assert.True(t, prefs.BugStatusChange)
+		assert.Equal(t, models.DigestFrequencyWeekly, prefs.DigestFrequency)
+
+		_, prefs = request("GET", nil)
+		assert.False(t, prefs.CommentReply)
+		assert.True(t, prefs.Marketing)
+		assert.True(t, prefs.CommentOnMyBug)
+	})
+
+	t.Run("rejects an unknown digest frequency", func(t *testing.T) {
+		w, _ := request("PATCH", map[string]interface{}{"digest_frequency": "hourly"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_DIGEST_FREQUENCY")
+	})
+}
+
+func TestQueueNotificationEmail_RespectsPreferences(t *testing.T) {
+	db := setupBugTestDB(t)
+	cacheService := cache.NewCacheService(nil)
+	user := createTestUser(t, db)
+	newcomer := createTestUserWithEmail(t, db, "newcomer@example.com")
+
+	preferences := models.DefaultNotificationPreferences(user.ID)
+	require.NoError(t, db.Create(&preferences).Error)
+	require.NoError(t, db.Model(&preferences).Updates(map[string]interface{}{
+		models.NotificationTypeCommentOnMyBug: false,
+		models.NotificationTypeVoteMilestone:  false,
+	}).Error)
+
+	queue := func(userID uuid.UUID, notificationType string) bool {
+		var before, after int64
+		db.Model(&models.OutboxEvent{}).Count(&before)
+		require.NoError(t, queueNotificationEmail(context.Background(), db, cacheService, userID, notificationType, func(recipient models.User) models.EmailPayload {
+			return models.EmailPayload{To: []string{recipient.Email}, Subject: notificationType}
+		}))
+		db.Model(&models.OutboxEvent{}).Count(&after)
+		return after > before
+	}
+
+	expected := map[string]bool{
+		models.NotificationTypeBugStatusChange: true,
+		models.NotificationTypeCommentOnMyBug:  false,
+		models.NotificationTypeCommentReply:    true,
+		models.NotificationTypeVoteMilestone:   false,
+		models.NotificationTypeCompanyResponse: true,
+		models.NotificationTypeMention:         true,
+		models.NotificationTypeMarketing:       false,
+	}
+	for notificationType, queued := range expected {
+		t.Run(notificationType, func(t *testing.T) {
+			assert.Equal(t, queued, queue(user.ID, notificationType))
+			// Users without a record get the defaults
+			assert.Equal(t, notificationType != models.NotificationTypeMarketing, queue(newcomer.ID, notificationType))
+		})
+	}
+}
@@ -1,34 +1,95 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
 	"bugrelay-backend/internal/auth"
+	"bugrelay-backend/internal/errors"
 	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
 
+const (
+	// oauthFlowTTL is how long a user has to complete an OAuth login
+	oauthFlowTTL = 10 * time.Minute
+
+	// pkceVerifierCookie holds the PKCE code verifier when Redis is not configured
+	pkceVerifierCookie = "oauth_pkce_verifier"
+)
+
 // OAuthHandler handles OAuth authentication requests
 type OAuthHandler struct {
 	db           *gorm.DB
+	redis        *redis.Client
 	authService  *auth.Service
 	oauthService *auth.OAuthService
 }
 
 // NewOAuthHandler creates a new OAuth handler
-func NewOAuthHandler(db *gorm.DB, authService *auth.Service, oauthService *auth.OAuthService) *OAuthHandler {
+func NewOAuthHandler(db *gorm.DB, redisClient *redis.Client, authService *auth.Service, oauthService *auth.OAuthService) *OAuthHandler {
 	return &OAuthHandler{
 		db:           db,
+		redis:        redisClient,
 		authService:  authService,
 		oauthService: oauthService,
 	}
 }
 
+// pkceVerifierKey is the Redis key of the PKCE code verifier for an OAuth state
+func pkceVerifierKey(state string) string {
+	return fmt.Sprintf("oauth:pkce:%s", state)
+}
+
+// storePKCEVerifier keeps the code verifier for an OAuth flow until its callback. It
+// is stored in Redis keyed by state, or in an HttpOnly cookie without Redis.
+func (h *OAuthHandler) storePKCEVerifier(c *gin.Context, state, verifier string) error {
+	if h.redis == nil {
+		c.SetCookie(pkceVerifierCookie, verifier, int(oauthFlowTTL/time.Second), "/", "", false, true)
+		return nil
+	}
+	return h.redis.Set(c.Request.Context(), pkceVerifierKey(state), verifier, oauthFlowTTL).Err()
+}
+
+// takePKCEVerifier returns and removes the code verifier stored for an OAuth state,
+// so each verifier can be used for a single token exchange
+func (h *OAuthHandler) takePKCEVerifier(c *gin.Context, state string) (string, error) {
+	if h.redis == nil {
+		verifier, err := c.Cookie(pkceVerifierCookie)
+		if err != nil {
+			return "", err
+		}
+		c.SetCookie(pkceVerifierCookie, "", -1, "/", "", false, true)
+		return verifier, nil
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+	defer cancel()
+	return h.redis.GetDel(ctx, pkceVerifierKey(state)).Result()
+}
+
+// pkceVerifierFor loads the code verifier for the provider's token exchange, writing
+// the error response itself when the flow has no verifier
+func (h *OAuthHandler) pkceVerifierFor(c *gin.Context, provider auth.OAuthProvider, state string) (string, bool) {
+	if !h.oauthService.SupportsPKCE(provider) {
+		return "", true
+	}
+
+	verifier, err := h.takePKCEVerifier(c, state)
+	if err != nil || verifier == "" {
+		errors.ErrInvalidState.WithMessage("OAuth session expired or was already used").Response(c)
+		return "", false
+	}
+	return verifier, true
+}
+
 // OAuthLoginRequest represents the OAuth login initiation request
 type OAuthLoginRequest struct {
 	Provider    string `json:"provider" binding:"required,oneof=google github"`
@@ -41,90 +102,91 @@ type OAuthCallbackRequest struct {
 	State string `json:"state" binding:"required"`
 }
 
-// InitiateOAuth starts the OAuth flow
+// InitiateOAuth starts the OAuth flow, returning the provider's authorization URL
+// for clients that navigate to it themselves
 func (h *OAuthHandler) InitiateOAuth(c *gin.Context) {
+	authURL, state, ok := h.startOAuthFlow(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"auth_url": authURL,
+		"state":    state,
+	})
+}
+
+// RedirectToOAuthProvider starts the OAuth flow and redirects the browser to the
+// provider's authorization page, so a "Sign in with" link can point straight at it
+func (h *OAuthHandler) RedirectToOAuthProvider(c *gin.Context) {
+	authURL, _, ok := h.startOAuthFlow(c)
+	if !ok {
+		return
+	}
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// startOAuthFlow sets the state cookie and PKCE verifier of a new OAuth flow with
+// the provider in the path, and returns its authorization URL and state. It writes
+// the error response and returns false when the flow cannot start.
+func (h *OAuthHandler) startOAuthFlow(c *gin.Context) (string, string, bool) {
 	provider := c.Param("provider")
 	if provider == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "MISSING_PROVIDER",
-				"message":   "OAuth provider is required",
-				"timestamp": time.Now(),
-			},
-		})
-		return
+		errors.ErrMissingProvider.Response(c)
+		return "", "", false
 	}
 
 	oauthProvider, err := auth.ParseProvider(provider)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_PROVIDER",
-				"message":   fmt.Sprintf("Unsupported OAuth provider: %s", provider),
-				"timestamp": time.Now(),
-			},
-		})
-		return
+		errors.ErrInvalidProvider.WithMessage(fmt.Sprintf("Unsupported OAuth provider: %s", provider)).Response(c)
+		return "", "", false
 	}
 
 	// Generate state for CSRF protection
 	state, err := h.oauthService.GenerateState()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "STATE_GENERATION_FAILED",
-				"message":   "Failed to generate OAuth state",
-				"timestamp": time.Now(),
-			},
-		})
-		return
+		errors.ErrStateGenerationFailed.Response(c)
+		return "", "", false
 	}
 
 	// Store state in session/cookie for validation
-	c.SetCookie("oauth_state", state, 600, "/", "", false, true) // 10 minutes
+	c.SetCookie("oauth_state", state, int(oauthFlowTTL/time.Second), "/", "", false, true)
+
+	// Generate a PKCE code verifier so an intercepted code cannot be exchanged
+	var codeVerifier string
+	if h.oauthService.SupportsPKCE(oauthProvider) {
+		codeVerifier, err = auth.GeneratePKCEVerifier()
+		if err == nil {
+			err = h.storePKCEVerifier(c, state, codeVerifier)
+		}
+		if err != nil {
+			errors.ErrStateGenerationFailed.Response(c)
+			return "", "", false
+		}
+	}
 
 	// Get authorization URL
-	authURL, err := h.oauthService.GetAuthURL(oauthProvider, state)
+	authURL, err := h.oauthService.GetAuthURL(oauthProvider, state, codeVerifier)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "AUTH_URL_GENERATION_FAILED",
-				"message":   "Failed to generate authorization URL",
-				"timestamp": time.Now(),
-			},
-		})
-		return
+		errors.ErrAuthURLGenerationFailed.Response(c)
+		return "", "", false
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"auth_url": authURL,
-		"state":    state,
-	})
+	return authURL, state, true
 }
 
 // HandleOAuthCallback handles the OAuth callback
 func (h *OAuthHandler) HandleOAuthCallback(c *gin.Context) {
 	provider := c.Param("provider")
 	if provider == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "MISSING_PROVIDER",
-				"message":   "OAuth provider is required",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrMissingProvider.Response(c)
 		return
 	}
 
 	oauthProvider, err := auth.ParseProvider(provider)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_PROVIDER",
-				"message":   fmt.Sprintf("Unsupported OAuth provider: %s", provider),
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrInvalidProvider.WithMessage(fmt.Sprintf("Unsupported OAuth provider: %s", provider)).Response(c)
 		return
 	}
 
@@ -133,79 +195,48 @@ func (h *OAuthHandler) HandleOAuthCallback(c *gin.Context) {
 	state := c.Query("state")
 
 	if code == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "MISSING_CODE",
-				"message":   "Authorization code is required",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrMissingCode.Response(c)
 		return
 	}
 
 	if state == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "MISSING_STATE",
-				"message":   "State parameter is required",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrMissingState.Response(c)
 		return
 	}
 
 	// Validate state
 	expectedState, err := c.Cookie("oauth_state")
 	if err != nil || !h.oauthService.ValidateState(expectedState, state) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_STATE",
-				"message":   "Invalid state parameter",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrInvalidState.Response(c)
 		return
 	}
 
 	// Clear the state cookie
 	c.SetCookie("oauth_state", "", -1, "/", "", false, true)
 
+	codeVerifier, ok := h.pkceVerifierFor(c, oauthProvider, state)
+	if !ok {
+		return
+	}
+
 	// Exchange code for token
-	token, err := h.oauthService.ExchangeCodeForToken(c.Request.Context(), oauthProvider, code)
+	token, err := h.oauthService.ExchangeCodeForToken(c.Request.Context(), oauthProvider, code, codeVerifier)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "TOKEN_EXCHANGE_FAILED",
-				"message":   "Failed to exchange authorization code for token",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrTokenExchangeFailed.Response(c)
 		return
 	}
 
 	// Get user info from OAuth provider
 	userInfo, err := h.oauthService.GetUserInfo(c.Request.Context(), oauthProvider, token)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "USER_INFO_FAILED",
-				"message":   "Failed to get user information from OAuth provider",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrUserInfoFailed.Response(c)
 		return
 	}
 
 	// Find or create user
 	user, err := h.findOrCreateOAuthUser(userInfo)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "USER_CREATION_FAILED",
-				"message":   "Failed to create or update user account",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrUserCreationFailed.WithMessage("Failed to create or update user account").Response(c)
 		return
 	}
 
@@ -214,15 +245,9 @@ func (h *OAuthHandler) HandleOAuthCallback(c *gin.Context) {
 	h.db.Save(&user)
 
 	// Generate JWT tokens
-	accessToken, refreshToken, err := h.authService.GenerateTokens(user.ID.String(), user.Email, user.IsAdmin)
+	accessToken, refreshToken, err := h.authService.GenerateTokens(user.ID.String(), user.Email, user.IsAdmin, c.ClientIP())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "TOKEN_GENERATION_FAILED",
-				"message":   "Failed to generate authentication tokens",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrTokenGenerationFailed.WithMessage("Failed to generate authentication tokens").Response(c)
 		return
 	}
 
@@ -318,89 +343,54 @@ func (h *OAuthHandler) findOrCreateOAuthUser(userInfo *auth.OAuthUserInfo) (*mod
 func (h *OAuthHandler) LinkOAuthAccount(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": gin.H{
-				"code":      "UNAUTHORIZED",
-				"message":   "User not authenticated",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrUnauthorized.WithMessage("User not authenticated").Response(c)
 		return
 	}
 
 	provider := c.Param("provider")
 	if provider == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "MISSING_PROVIDER",
-				"message":   "OAuth provider is required",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrMissingProvider.Response(c)
 		return
 	}
 
 	oauthProvider, err := auth.ParseProvider(provider)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_PROVIDER",
-				"message":   fmt.Sprintf("Unsupported OAuth provider: %s", provider),
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrInvalidProvider.WithMessage(fmt.Sprintf("Unsupported OAuth provider: %s", provider)).Response(c)
 		return
 	}
 
 	var req OAuthCallbackRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "INVALID_REQUEST",
-				"message":   "Invalid request data",
-				"details":   err.Error(),
-				"timestamp": time.Now(),
-			},
-		})
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrInvalidRequest.WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+
+	codeVerifier, ok := h.pkceVerifierFor(c, oauthProvider, req.State)
+	if !ok {
 		return
 	}
 
 	// Exchange code for token
-	token, err := h.oauthService.ExchangeCodeForToken(c.Request.Context(), oauthProvider, req.Code)
+	token, err := h.oauthService.ExchangeCodeForToken(c.Request.Context(), oauthProvider, req.Code, codeVerifier)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "TOKEN_EXCHANGE_FAILED",
-				"message":   "Failed to exchange authorization code for token",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrTokenExchangeFailed.Response(c)
 		return
 	}
 
 	// Get user info from OAuth provider
 	userInfo, err := h.oauthService.GetUserInfo(c.Request.Context(), oauthProvider, token)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":      "USER_INFO_FAILED",
-				"message":   "Failed to get user information from OAuth provider",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrUserInfoFailed.Response(c)
 		return
 	}
 
 	// Get current user
 	var user models.User
 	if err := h.db.Where("id = ?", userID).First(&user).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": gin.H{
-				"code":      "USER_NOT_FOUND",
-				"message":   "User not found",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrUserNotFound.Response(c)
 		return
 	}
 
@@ -408,13 +398,7 @@ func (h *OAuthHandler) LinkOAuthAccount(c *gin.Context) {
 	var existingUser models.User
 	err = h.db.Where("auth_provider = ? AND auth_provider_id = ?", userInfo.Provider, userInfo.ID).First(&existingUser).Error
 	if err == nil && existingUser.ID != user.ID {
-		c.JSON(http.StatusConflict, gin.H{
-			"error": gin.H{
-				"code":      "OAUTH_ACCOUNT_LINKED",
-				"message":   "This OAuth account is already linked to another user",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrOAuthAccountLinked.Response(c)
 		return
 	}
 
@@ -426,13 +410,7 @@ func (h *OAuthHandler) LinkOAuthAccount(c *gin.Context) {
 	}
 
 	if err := h.db.Save(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":      "LINK_FAILED",
-				"message":   "Failed to link OAuth account",
-				"timestamp": time.Now(),
-			},
-		})
+		errors.ErrLinkFailed.Response(c)
 		return
 	}
 
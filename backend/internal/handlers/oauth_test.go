@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"bugrelay-backend/internal/auth"
@@ -24,7 +26,7 @@ func setupTestOAuthHandler(t *testing.T) *OAuthHandler {
 	}
 	
 	oauthService := auth.NewOAuthService(oauthConfig)
-	return NewOAuthHandler(db, handler.authService, oauthService)
+	return NewOAuthHandler(db, nil, handler.authService, oauthService)
 }
 
 func TestOAuthHandler_InitiateOAuth(t *testing.T) {
@@ -275,7 +277,7 @@ func TestOAuthService_GetAuthURL(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			authURL, err := service.GetAuthURL(tt.provider, "test-state")
+			authURL, err := service.GetAuthURL(tt.provider, "test-state", "")
 			
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -287,4 +289,102 @@ func TestOAuthService_GetAuthURL(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestOAuthHandler_GitHubPKCE(t *testing.T) {
+	handler := setupTestOAuthHandler(t)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/oauth/:provider", handler.InitiateOAuth)
+	router.GET("/oauth/callback/:provider", handler.HandleOAuthCallback)
+
+	req, _ := http.NewRequest("GET", "/oauth/github", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		AuthURL string `json:"auth_url"`
+		State   string `json:"state"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	var verifierCookie *http.Cookie
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == pkceVerifierCookie {
+			verifierCookie = cookie
+		}
+	}
+	require.NotNil(t, verifierCookie)
+	assert.True(t, verifierCookie.HttpOnly)
+
+	// The redirect carries the challenge for the stored verifier, never the verifier
+	authURL, err := url.Parse(response.AuthURL)
+	require.NoError(t, err)
+	assert.Equal(t, "S256", authURL.Query().Get("code_challenge_method"))
+	assert.True(t, auth.PKCEVerify(authURL.Query().Get("code_challenge"), verifierCookie.Value))
+	assert.NotContains(t, response.AuthURL, verifierCookie.Value)
+
+	t.Run("callback without the verifier is rejected", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/oauth/callback/github?code=test-code&state="+response.State, nil)
+		req.AddCookie(&http.Cookie{Name: "oauth_state", Value: response.State})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_STATE")
+	})
+
+	t.Run("google does not use PKCE", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/oauth/google", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		assert.NotContains(t, w.Body.String(), "code_challenge")
+		for _, cookie := range w.Result().Cookies() {
+			assert.NotEqual(t, pkceVerifierCookie, cookie.Name)
+		}
+	})
+}
+
+func TestOAuthHandler_RedirectToOAuthProvider(t *testing.T) {
+	handler := setupTestOAuthHandler(t)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/oauth/:provider/redirect", handler.RedirectToOAuthProvider)
+
+	for _, provider := range []string{"google", "github"} {
+		t.Run(provider, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/oauth/"+provider+"/redirect", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			require.Equal(t, http.StatusFound, w.Code)
+
+			var stateCookie *http.Cookie
+			for _, cookie := range w.Result().Cookies() {
+				if cookie.Name == "oauth_state" {
+					stateCookie = cookie
+				}
+			}
+			require.NotNil(t, stateCookie)
+			assert.True(t, stateCookie.HttpOnly)
+
+			// The browser is sent to the provider with the state the callback checks
+			authURL, err := url.Parse(w.Header().Get("Location"))
+			require.NoError(t, err)
+			assert.Equal(t, stateCookie.Value, authURL.Query().Get("state"))
+			assert.Equal(t, "test-"+provider+"-client-id", authURL.Query().Get("client_id"))
+		})
+	}
+
+	t.Run("invalid provider", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/oauth/invalid/redirect", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Empty(t, w.Header().Get("Location"))
+	})
+}
@@ -0,0 +1,33 @@
+package handlers
+
+// DefaultPageLimit is the page size of listings when none is requested
+const DefaultPageLimit = 20
+
+// Largest page sizes of each route group's listings, unless configured otherwise
+const (
+	defaultBugListMaxLimit     = 100
+	defaultCompanyListMaxLimit = 50
+	defaultAdminListMaxLimit   = 500
+)
+
+// PaginationConfig is the page size of a route group's listings. Requests without a
+// limit, or with one outside 1 to MaxLimit, get DefaultLimit.
+type PaginationConfig struct {
+	DefaultLimit int
+	MaxLimit     int
+}
+
+// Limit returns the page size to use for a requested limit
+func (p PaginationConfig) Limit(requested int) int {
+	if requested <= 0 || requested > p.MaxLimit {
+		return p.DefaultLimit
+	}
+	return requested
+}
+
+// WithDefault returns the configuration with a different default page size, for
+// listings in a route group that show more or fewer items by default
+func (p PaginationConfig) WithDefault(defaultLimit int) PaginationConfig {
+	p.DefaultLimit = defaultLimit
+	return p
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginationConfig_Limit(t *testing.T) {
+	pagination := PaginationConfig{DefaultLimit: 20, MaxLimit: 50}
+
+	assert.Equal(t, 20, pagination.Limit(0))
+	assert.Equal(t, 20, pagination.Limit(-5))
+	assert.Equal(t, 1, pagination.Limit(1))
+	assert.Equal(t, 50, pagination.Limit(50))
+	assert.Equal(t, 20, pagination.Limit(51))
+
+	withDefault := pagination.WithDefault(40)
+	assert.Equal(t, 40, withDefault.Limit(0))
+	assert.Equal(t, 40, withDefault.Limit(51))
+	assert.Equal(t, 50, withDefault.MaxLimit)
+	assert.Equal(t, 20, pagination.DefaultLimit)
+}
+
+// listingLimit calls a listing handler and returns the page size it used
+func listingLimit(t *testing.T, handler gin.HandlerFunc, path string) int {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", path, nil)
+
+	handler(c)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Pagination struct {
+			Limit int `json:"limit"`
+		} `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response.Pagination.Limit
+}
+
+func TestPagination_RouteGroupLimits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupAdminTestDB(t)
+	bugHandler := NewBugHandler(db, nil)
+	companyHandler := NewCompanyHandler(db, nil)
+	adminHandler := NewAdminHandler(db, nil)
+
+	t.Run("defaults", func(t *testing.T) {
+		assert.Equal(t, 100, listingLimit(t, bugHandler.ListBugs, "/bugs?limit=100"))
+		assert.Equal(t, 20, listingLimit(t, bugHandler.ListBugs, "/bugs?limit=101"))
+
+		assert.Equal(t, 50, listingLimit(t, companyHandler.ListCompanies, "/companies?limit=50"))
+		assert.Equal(t, 20, listingLimit(t, companyHandler.ListCompanies, "/companies?limit=51"))
+	})
+
+	t.Run("configured limits take effect", func(t *testing.T) {
+		bugHandler.SetPagination(PaginationConfig{DefaultLimit: DefaultPageLimit, MaxLimit: 30})
+		companyHandler.SetPagination(PaginationConfig{DefaultLimit: 5, MaxLimit: 10})
+
+		assert.Equal(t, 30, listingLimit(t, bugHandler.ListBugs, "/bugs?limit=30"))
+		assert.Equal(t, 20, listingLimit(t, bugHandler.ListBugs, "/bugs?limit=31"))
+
+		assert.Equal(t, 10, listingLimit(t, companyHandler.ListCompanies, "/companies?limit=10"))
+		assert.Equal(t, 5, listingLimit(t, companyHandler.ListCompanies, "/companies?limit=11"))
+		assert.Equal(t, 5, listingLimit(t, companyHandler.ListCompanies, "/companies?limit=0"))
+	})
+
+	t.Run("admin listings use the global override", func(t *testing.T) {
+		assert.Equal(t, 500, listingLimit(t, adminHandler.ListBugsForModeration, "/admin/bugs?limit=500"))
+		assert.Equal(t, 20, listingLimit(t, adminHandler.ListBugsForModeration, "/admin/bugs?limit=501"))
+		assert.Equal(t, 400, listingLimit(t, adminHandler.ListDeletedBugs, "/admin/bugs/deleted?limit=400"))
+
+		adminHandler.SetPagination(PaginationConfig{DefaultLimit: DefaultPageLimit, MaxLimit: 1000})
+		assert.Equal(t, 1000, listingLimit(t, adminHandler.ListBugsForModeration, "/admin/bugs?limit=1000"))
+	})
+}
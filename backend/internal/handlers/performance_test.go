@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"runtime"
 	"strings"
 	"testing"
@@ -17,6 +18,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gorm.io/driver/sqlite"
@@ -37,10 +39,25 @@ func setupPerformanceTestDB(t testing.TB) *gorm.DB {
 		&models.BugReport{},
 		&models.BugVote{},
 		&models.Comment{},
+		&models.CommentEdit{},
+		&models.BugEditHistory{},
 		&models.FileAttachment{},
 		&models.JWTBlacklist{},
 		&models.CompanyMember{},
 		&models.AuditLog{},
+		&models.CustomFieldSchema{},
+		&models.BugAssignmentRule{},
+		&models.CompanyWebhook{},
+		&models.OutboxEvent{},
+		&models.CompanyInvitation{},
+		&models.UserBlock{},
+		&models.NotificationPreferences{},
+		&models.SecurityEvent{},
+		&models.ModerationQueueEntry{},
+		&models.BugEvent{},
+		&models.SearchQuery{},
+		&models.Notification{},
+		&models.BugSubscription{},
 	)
 	require.NoError(t, err)
 
@@ -64,7 +81,7 @@ func setupPerformanceTestRouter(t testing.TB) (*gin.Engine, *gorm.DB) {
 	// Setup handlers
 	authHandler := NewAuthHandler(db, authService)
 	bugHandler := NewBugHandler(db, nil) // No Redis for performance tests
-	companyHandler := NewCompanyHandler(db)
+	companyHandler := NewCompanyHandler(db, nil)
 
 	// Setup middleware
 	security := middleware.NewSecurityMiddleware([]string{})
@@ -289,6 +306,55 @@ func BenchmarkBugHandler_SearchBugs(b *testing.B) {
 	}
 }
 
+// Performance test for searching one application's bugs, which needs PostgreSQL
+// full-text search. With LOG_LEVEL=debug the query plan is logged with buffer
+// usage before timing starts.
+func BenchmarkBugHandler_SearchApplicationBugs(b *testing.B) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupPostgresBugTestDB(b)
+	handler := NewBugHandler(db, nil)
+	router := gin.New()
+	router.GET("/api/v1/bugs", handler.ListBugs)
+
+	app := &models.Application{Name: fmt.Sprintf("Benchmark App %d", time.Now().UnixNano())}
+	require.NoError(b, db.Create(app).Error)
+	for i := 0; i < 100; i++ {
+		bug := &models.BugReport{
+			Title:         fmt.Sprintf("Searchable Bug %d", i),
+			Description:   "This bug contains searchable content for performance testing",
+			Status:        models.BugStatusOpen,
+			Priority:      models.BugPriorityMedium,
+			ApplicationID: app.ID,
+		}
+		require.NoError(b, db.Create(bug).Error)
+	}
+	b.Cleanup(func() {
+		db.Unscoped().Where("application_id = ?", app.ID).Delete(&models.BugReport{})
+		db.Delete(app)
+	})
+
+	path := "/api/v1/bugs?application_id=" + app.ID.String() + "&search=searchable&page=1&limit=20"
+	if os.Getenv("LOG_LEVEL") == "debug" {
+		previous := logrus.GetLevel()
+		logrus.SetLevel(logrus.DebugLevel)
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+		logrus.SetLevel(previous)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		req, _ := http.NewRequest("GET", path, nil)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			b.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+		}
+	}
+}
+
 // Performance test for bug voting
 func BenchmarkBugHandler_VoteBug(b *testing.B) {
 	router, db := setupPerformanceTestRouter(b)
@@ -551,3 +617,43 @@ func TestResponseTime(t *testing.T) {
 		})
 	}
 }
+
+// Performance test for the admin dashboard, comparing sequential and parallel count queries
+func BenchmarkGetAdminDashboard(b *testing.B) {
+	for _, parallel := range []bool{false, true} {
+		name := "sequential"
+		if parallel {
+			name = "parallel"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			db := setupPerformanceTestDB(b)
+			sqlDB, err := db.DB()
+			require.NoError(b, err)
+			sqlDB.SetMaxOpenConns(1)
+
+			user := createTestUserForPerf(b, db)
+			for i := 0; i < 100; i++ {
+				createTestBugForPerf(b, db, &user.ID)
+			}
+
+			handler := NewAdminHandler(db, nil)
+			handler.SetParallelDashboardQueries(parallel)
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/admin/dashboard", handler.GetAdminDashboard)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				req, _ := http.NewRequest("GET", "/admin/dashboard", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				if w.Code != http.StatusOK {
+					b.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"bugrelay-backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestBodyTooLarge responds with 413 when err came from a request body exceeding
+// the body size limit, reporting whether it did. Call it before reporting bind errors
+// as validation failures.
+func requestBodyTooLarge(c *gin.Context, err error) bool {
+	if !middleware.IsRequestBodyTooLarge(err) {
+		return false
+	}
+	middleware.AbortRequestBodyTooLarge(c)
+	return true
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/middleware"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// searchAnalyticsLimit is how many queries each search analytics list returns
+	searchAnalyticsLimit = 50
+
+	// maxSearchAnalyticsDays is the longest look-back window for search analytics
+	maxSearchAnalyticsDays = 365
+
+	// maxSearchQueryLength is the longest query text stored, matching the column size
+	maxSearchQueryLength = 255
+)
+
+// normalizeSearchQuery lowercases a query and collapses its whitespace so that
+// equivalent searches are counted together
+func normalizeSearchQuery(query string) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(query), " "))
+	if runes := []rune(normalized); len(runes) > maxSearchQueryLength {
+		normalized = string(runes[:maxSearchQueryLength])
+	}
+	return normalized
+}
+
+// logSearchQuery records a bug search in the background so logging never slows down
+// or fails the listing. The session is taken from the optional X-Session-ID header.
+func (h *BugHandler) logSearchQuery(c *gin.Context, query string, resultCount int64) {
+	searchQuery := models.SearchQuery{
+		QueryText:   normalizeSearchQuery(query),
+		ResultCount: int(resultCount),
+	}
+	if userIDStr, exists := middleware.GetCurrentUserID(c); exists {
+		if userID, err := uuid.Parse(userIDStr); err == nil {
+			searchQuery.UserID = &userID
+		}
+	}
+	if sessionID := strings.TrimSpace(c.GetHeader("X-Session-ID")); sessionID != "" && len(sessionID) <= 100 {
+		searchQuery.SessionID = &sessionID
+	}
+
+	// The request context is cancelled once the response is written, so the insert
+	// does not use it. Only its logger is kept.
+	requestLogger := logger.FromContext(c.Request.Context())
+	go func() {
+		if err := h.db.Create(&searchQuery).Error; err != nil {
+			requestLogger.Error("Failed to log search query", err, logger.Fields{"query": searchQuery.QueryText})
+		}
+	}()
+}
+
+// SearchQueryStats summarizes how often a query was searched and what it returned
+type SearchQueryStats struct {
+	QueryText          string  `json:"query_text"`
+	Count              int64   `json:"count"`
+	AverageResultCount float64 `json:"average_result_count"`
+}
+
+// ZeroResultQuery is a query that returned no results, with how often it did
+type ZeroResultQuery struct {
+	QueryText string `json:"query_text"`
+	Count     int64  `json:"count"`
+}
+
+// SearchAnalyticsResponse represents the response for search analytics
+type SearchAnalyticsResponse struct {
+	Days              int                `json:"days"`
+	TotalSearches     int64              `json:"total_searches"`
+	TopQueries        []SearchQueryStats `json:"top_queries"`
+	ZeroResultQueries []ZeroResultQuery  `json:"zero_result_queries"`
+}
+
+// GetSearchAnalytics returns the most frequent bug searches over the last `days` days
+// (default 30) with their average result count, and the searches that found nothing,
+// which point at missing or poorly worded bug reports
+func (h *AdminHandler) GetSearchAnalytics(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 || days > maxSearchAnalyticsDays {
+		errors.ErrInvalidDays.Response(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	var cached SearchAnalyticsResponse
+	if err := h.cache.GetSearchAnalytics(ctx, days, &cached); err == nil {
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	response := SearchAnalyticsResponse{
+		Days:              days,
+		TopQueries:        []SearchQueryStats{},
+		ZeroResultQueries: []ZeroResultQuery{},
+	}
+
+	if err := h.db.Model(&models.SearchQuery{}).
+		Where("created_at >= ?", since).
+		Count(&response.TotalSearches).Error; err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to fetch search analytics").Response(c)
+		return
+	}
+
+	if err := h.db.Model(&models.SearchQuery{}).
+		Select("query_text, COUNT(*) AS count, AVG(result_count) AS average_result_count").
+		Where("created_at >= ?", since).
+		Group("query_text").
+		Order("count DESC").Order("query_text").
+		Limit(searchAnalyticsLimit).
+		Scan(&response.TopQueries).Error; err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to fetch search analytics").Response(c)
+		return
+	}
+
+	if err := h.db.Model(&models.SearchQuery{}).
+		Select("query_text, COUNT(*) AS count").
+		Where("created_at >= ? AND result_count = 0", since).
+		Group("query_text").
+		Order("count DESC").Order("query_text").
+		Limit(searchAnalyticsLimit).
+		Scan(&response.ZeroResultQueries).Error; err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to fetch search analytics").Response(c)
+		return
+	}
+
+	if err := h.cache.SetSearchAnalytics(ctx, days, response); err != nil {
+		// Log cache error but don't fail the request
+		logger.FromContext(ctx).Error("Failed to cache search analytics", err, logger.Fields{"days": days})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
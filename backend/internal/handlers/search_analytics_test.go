@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// setupSearchLogTest creates a bug handler on a single-connection database, so the
+// background insert sees the same in-memory database as the test
+func setupSearchLogTest(t *testing.T) (*BugHandler, *gorm.DB) {
+	handler, db := setupBugTestHandler(t)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	return handler, db
+}
+
+// countSearchQueries counts the logged search queries
+func countSearchQueries(db *gorm.DB) int64 {
+	var count int64
+	db.Model(&models.SearchQuery{}).Count(&count)
+	return count
+}
+
+// ListBugs searches with PostgreSQL full-text functions that SQLite lacks, so these
+// tests call logSearchQuery from a handler standing in for ListBugs
+func TestBugHandler_LogSearchQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler, db := setupSearchLogTest(t)
+	user := createTestUser(t, db)
+
+	router := gin.New()
+	router.Use(mockAuthMiddleware(user.ID))
+	router.GET("/bugs", func(c *gin.Context) {
+		resultCount, _ := strconv.ParseInt(c.Query("results"), 10, 64)
+		handler.logSearchQuery(c, c.Query("search"), resultCount)
+		c.JSON(http.StatusOK, gin.H{"bugs": []models.BugReport{}})
+	})
+
+	search := func(query, results string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/bugs?search="+query+"&results="+results, nil)
+		req.Header.Set("X-Session-ID", "session-123")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("insert does not block the response", func(t *testing.T) {
+		// Hold search query inserts until the response has been written
+		release := make(chan struct{})
+		require.NoError(t, db.Callback().Create().Before("gorm:begin_transaction").Register("test:hold_search_queries", func(tx *gorm.DB) {
+			if tx.Statement.Table == "search_queries" {
+				<-release
+			}
+		}))
+		defer db.Callback().Create().Remove("test:hold_search_queries")
+
+		done := make(chan *httptest.ResponseRecorder)
+		go func() { done <- search("Login%20%20CRASH", "3") }()
+
+		select {
+		case w := <-done:
+			assert.Equal(t, http.StatusOK, w.Code)
+		case <-time.After(2 * time.Second):
+			close(release)
+			t.Fatal("response waited for the search query insert")
+		}
+		assert.Zero(t, countSearchQueries(db))
+
+		close(release)
+		require.Eventually(t, func() bool { return countSearchQueries(db) == 1 }, 2*time.Second, 10*time.Millisecond)
+
+		var logged models.SearchQuery
+		require.NoError(t, db.First(&logged).Error)
+		assert.Equal(t, "login crash", logged.QueryText)
+		assert.Equal(t, 3, logged.ResultCount)
+		require.NotNil(t, logged.UserID)
+		assert.Equal(t, user.ID, *logged.UserID)
+		require.NotNil(t, logged.SessionID)
+		assert.Equal(t, "session-123", *logged.SessionID)
+	})
+
+	t.Run("zero result searches are logged", func(t *testing.T) {
+		require.NoError(t, db.Where("1 = 1").Delete(&models.SearchQuery{}).Error)
+
+		search("dark%20mode", "0")
+		require.Eventually(t, func() bool { return countSearchQueries(db) == 1 }, 2*time.Second, 10*time.Millisecond)
+
+		var logged models.SearchQuery
+		require.NoError(t, db.First(&logged).Error)
+		assert.Equal(t, "dark mode", logged.QueryText)
+		assert.Zero(t, logged.ResultCount)
+	})
+
+	t.Run("insert failures are logged with the request logger", func(t *testing.T) {
+		require.NoError(t, db.Migrator().DropTable(&models.SearchQuery{}))
+		defer func() { require.NoError(t, db.AutoMigrate(&models.SearchQuery{})) }()
+
+		testLogger := logger.NewTestLogger()
+		req, _ := http.NewRequest("GET", "/bugs?search=export&results=2", nil)
+		req = req.WithContext(logger.NewContext(req.Context(), testLogger))
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		require.Eventually(t, func() bool {
+			return len(testLogger.EntriesWithMessage("Failed to log search query")) == 1
+		}, 2*time.Second, 10*time.Millisecond)
+		entry := testLogger.EntriesWithMessage("Failed to log search query")[0]
+		assert.Equal(t, "error", entry.Level)
+		assert.Error(t, entry.Err)
+		assert.Equal(t, "export", entry.Fields["query"])
+	})
+}
+
+func TestAdminHandler_GetSearchAnalytics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler, db := setupAdminTestHandler(t)
+	admin := createTestAdmin(t, db)
+
+	now := time.Now()
+	for _, query := range []models.SearchQuery{
+		{QueryText: "login crash", ResultCount: 0, CreatedAt: now.Add(-time.Hour)},
+		{QueryText: "login crash", ResultCount: 0, CreatedAt: now.Add(-2 * time.Hour)},
+		{QueryText: "login crash", ResultCount: 4, CreatedAt: now.Add(-3 * time.Hour)},
+		{QueryText: "dark mode", ResultCount: 0, CreatedAt: now.AddDate(0, 0, -5)},
+		{QueryText: "dark mode", ResultCount: 0, CreatedAt: now.AddDate(0, 0, -6)},
+		{QueryText: "export", ResultCount: 10, CreatedAt: now.AddDate(0, 0, -10)},
+		{QueryText: "old search", ResultCount: 0, CreatedAt: now.AddDate(0, 0, -40)},
+	} {
+		query.ID = uuid.New()
+		require.NoError(t, db.Create(&query).Error)
+	}
+
+	router := gin.New()
+	router.Use(mockAdminAuthMiddleware(admin.ID))
+	router.GET("/admin/search-analytics", handler.GetSearchAnalytics)
+
+	getAnalytics := func(t *testing.T, query string) (int, SearchAnalyticsResponse) {
+		req, _ := http.NewRequest("GET", "/admin/search-analytics"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response SearchAnalyticsResponse
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		}
+		return w.Code, response
+	}
+
+	t.Run("defaults to the last 30 days", func(t *testing.T) {
+		status, response := getAnalytics(t, "")
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, 30, response.Days)
+		assert.EqualValues(t, 6, response.TotalSearches)
+
+		require.Len(t, response.TopQueries, 3)
+		assert.Equal(t, "login crash", response.TopQueries[0].QueryText)
+		assert.EqualValues(t, 3, response.TopQueries[0].Count)
+		assert.InDelta(t, 4.0/3, response.TopQueries[0].AverageResultCount, 0.001)
+		assert.Equal(t, "dark mode", response.TopQueries[1].QueryText)
+		assert.Zero(t, response.TopQueries[1].AverageResultCount)
+		assert.Equal(t, "export", response.TopQueries[2].QueryText)
+		assert.InDelta(t, 10, response.TopQueries[2].AverageResultCount, 0.001)
+	})
+
+	t.Run("counts only the searches that found nothing", func(t *testing.T) {
+		status, response := getAnalytics(t, "?days=30")
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, []ZeroResultQuery{
+			{QueryText: "dark mode", Count: 2},
+			{QueryText: "login crash", Count: 2},
+		}, response.ZeroResultQueries)
+	})
+
+	t.Run("days limits the window", func(t *testing.T) {
+		status, response := getAnalytics(t, "?days=90")
+		require.Equal(t, http.StatusOK, status)
+		assert.EqualValues(t, 7, response.TotalSearches)
+		assert.Len(t, response.ZeroResultQueries, 3)
+
+		status, response = getAnalytics(t, "?days=1")
+		require.Equal(t, http.StatusOK, status)
+		assert.EqualValues(t, 3, response.TotalSearches)
+		assert.Equal(t, []ZeroResultQuery{{QueryText: "login crash", Count: 2}}, response.ZeroResultQueries)
+	})
+
+	t.Run("invalid days", func(t *testing.T) {
+		for _, days := range []string{"0", "366", "abc"} {
+			status, _ := getAnalytics(t, "?days="+days)
+			assert.Equal(t, http.StatusBadRequest, status, days)
+		}
+	})
+}
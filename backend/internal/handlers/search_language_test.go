@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// setupPostgresBugTestDB connects to the database in TEST_DATABASE_URL, skipping when unset
+func setupPostgresBugTestDB(t testing.TB) *gorm.DB {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set, skipping PostgreSQL test")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	require.NoError(t, err)
+
+	require.NoError(t, db.Exec(`CREATE EXTENSION IF NOT EXISTS "uuid-ossp"`).Error)
+	require.NoError(t, models.AutoMigrate(db))
+	require.NoError(t, models.CreateIndexes(db))
+
+	return db
+}
+
+// searchBugIDs lists the bugs of the application matching search through ListBugs,
+// all of them when search is empty
+func searchBugIDs(t *testing.T, handler *BugHandler, applicationID uuid.UUID, search string) []uuid.UUID {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/bugs?application_id="+applicationID.String()+"&search="+url.QueryEscape(search), nil)
+	handler.ListBugs(c)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var response struct {
+		Bugs []models.BugReport `json:"bugs"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	ids := make([]uuid.UUID, len(response.Bugs))
+	for i, bug := range response.Bugs {
+		ids[i] = bug.ID
+	}
+	return ids
+}
+
+func TestBugHandler_ListBugs_SearchLanguage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupPostgresBugTestDB(t)
+	handler := NewBugHandler(db, nil)
+
+	createApp := func(language string) (*models.Application, *models.BugReport) {
+		app := &models.Application{Name: "Recettes " + uuid.New().String(), SearchLanguage: language}
+		require.NoError(t, db.Create(app).Error)
+		bug := &models.BugReport{
+			Title:         "Les utilisateurs ont mangé toutes les données",
+			Description:   "Les données disparaissent après la synchronisation",
+			Status:        models.BugStatusOpen,
+			Priority:      models.BugPriorityMedium,
+			ApplicationID: app.ID,
+		}
+		require.NoError(t, db.Create(bug).Error)
+		t.Cleanup(func() {
+			db.Unscoped().Delete(bug)
+			db.Delete(app)
+		})
+		return app, bug
+	}
+
+	frenchApp, frenchBug := createApp("french")
+	englishApp, _ := createApp(models.DefaultSearchLanguage)
+
+	// French stemming matches "mangé" to "manger"; English stemming does not
+	assert.Equal(t, []uuid.UUID{frenchBug.ID}, searchBugIDs(t, handler, frenchApp.ID, "manger"))
+	assert.Empty(t, searchBugIDs(t, handler, englishApp.ID, "manger"))
+
+	// French drops its stop words, while English keeps "les" as a word to match
+	assert.Empty(t, searchBugIDs(t, handler, frenchApp.ID, "les"))
+	assert.NotEmpty(t, searchBugIDs(t, handler, englishApp.ID, "les"))
+}
+
+func TestBugHandler_ListBugs_ApplicationID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	user := createTestUser(t, db)
+	editor := createTestApplication(t, db)
+	viewer := createTestApplication(t, db)
+	editorBug := createTestBugReport(t, db, editor, user)
+	createTestBugReport(t, db, viewer, user)
+
+	// SQLite has no full-text search, so this only covers the filter
+	assert.Equal(t, []uuid.UUID{editorBug.ID}, searchBugIDs(t, handler, editor.ID, ""))
+	assert.Empty(t, searchBugIDs(t, handler, uuid.New(), ""))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/bugs?application_id=not-a-uuid", nil)
+	handler.ListBugs(c)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_ID")
+}
+
+func TestBuildBugQuery_ApplicationSearch(t *testing.T) {
+	db := setupBugTestDB(t)
+	applicationID := uuid.New()
+
+	sql := func(opts BugQueryOptions) string {
+		opts.Search = "crash"
+		stmt := buildBugQuery(db, opts).Session(&gorm.Session{DryRun: true}).Find(&[]models.BugReport{}).Statement
+		return stmt.SQL.String()
+	}
+
+	// Searches within an application match the stored vector
+	scoped := sql(BugQueryOptions{ApplicationID: &applicationID})
+	assert.Contains(t, scoped, "bug_reports.search_vector @@ plainto_tsquery")
+	assert.NotContains(t, scoped, "to_tsvector")
+
+	// The vector is English, so other languages and global searches, which also
+	// match the application name, search the expression
+	for _, opts := range []BugQueryOptions{
+		{ApplicationID: &applicationID, SearchLanguage: "french"},
+		{},
+	} {
+		query := sql(opts)
+		assert.Contains(t, query, "to_tsvector(")
+		assert.NotContains(t, query, "search_vector")
+	}
+}
+
+func TestBugHandler_ListBugs_ApplicationSearch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupPostgresBugTestDB(t)
+	handler := NewBugHandler(db, nil)
+
+	app := &models.Application{Name: "Checkout " + uuid.New().String()}
+	other := &models.Application{Name: "Storefront " + uuid.New().String()}
+	require.NoError(t, db.Create(app).Error)
+	require.NoError(t, db.Create(other).Error)
+
+	createBug := func(application *models.Application, title string) *models.BugReport {
+		bug := &models.BugReport{
+			Title:         title,
+			Description:   "Reported while paying for an order",
+			Status:        models.BugStatusOpen,
+			Priority:      models.BugPriorityMedium,
+			ApplicationID: application.ID,
+		}
+		require.NoError(t, db.Create(bug).Error)
+		t.Cleanup(func() { db.Unscoped().Delete(bug) })
+		return bug
+	}
+
+	crash := createBug(app, "Payment form crashes on submit")
+	createBug(app, "Totals are rounded incorrectly")
+	createBug(other, "Product page crashes on load")
+	t.Cleanup(func() {
+		db.Delete(app)
+		db.Delete(other)
+	})
+
+	// Only the application's bugs matching the search are listed, stemmed in English
+	assert.Equal(t, []uuid.UUID{crash.ID}, searchBugIDs(t, handler, app.ID, "crashing"))
+
+	// The search matches the stored vector rather than computing it for every bug
+	query := buildBugQuery(db, BugQueryOptions{ApplicationID: &app.ID, Search: "crashing"})
+	stmt := query.Session(&gorm.Session{DryRun: true}).Find(&[]models.BugReport{}).Statement
+	var plan []string
+	require.NoError(t, db.Raw("EXPLAIN "+stmt.SQL.String(), stmt.Vars...).Scan(&plan).Error)
+	assert.Contains(t, strings.Join(plan, "\n"), "search_vector @@")
+	assert.NotContains(t, strings.Join(plan, "\n"), "to_tsvector")
+}
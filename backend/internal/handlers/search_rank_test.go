@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// fullTextDriver is a SQLite driver with stand-ins for PostgreSQL's full-text
+// search functions
+const fullTextDriver = "sqlite3_fulltext"
+
+var registerFullTextDriver sync.Once
+
+// fakeTSRank ranks a document by how often it contains the query, an eighth per
+// occurrence so that weighted ranks compare exactly
+func fakeTSRank(document, query string) float64 {
+	return float64(strings.Count(document, query)) / 8
+}
+
+// setupSearchRankTestDB creates a test database that supports ranking search results
+func setupSearchRankTestDB(t testing.TB) *gorm.DB {
+	registerFullTextDriver.Do(func() {
+		sql.Register(fullTextDriver, &sqlite3.SQLiteDriver{
+			ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+				lower := func(config, text string) string { return strings.ToLower(text) }
+				if err := conn.RegisterFunc("to_tsvector", lower, true); err != nil {
+					return err
+				}
+				if err := conn.RegisterFunc("plainto_tsquery", lower, true); err != nil {
+					return err
+				}
+				return conn.RegisterFunc("ts_rank", fakeTSRank, true)
+			},
+		})
+	})
+
+	return openBugTestDB(t, sqlite.New(sqlite.Config{DriverName: fullTextDriver, DSN: ":memory:"}))
+}
+
+// createRankedBug creates a bug whose title mentions "crash" mentions times
+func createRankedBug(t testing.TB, db *gorm.DB, app *models.Application, priority string, mentions int, createdAt time.Time) *models.BugReport {
+	bug := &models.BugReport{
+		ID:            uuid.New(),
+		Title:         strings.TrimSpace(strings.Repeat("crash ", mentions)),
+		Description:   "Found while testing",
+		Status:        models.BugStatusOpen,
+		Priority:      priority,
+		ApplicationID: app.ID,
+		CreatedAt:     createdAt,
+	}
+	require.NoError(t, db.Create(bug).Error)
+	return bug
+}
+
+// searchRankedBugIDs returns the IDs of db's bugs in search result order
+func searchRankedBugIDs(t testing.TB, db *gorm.DB, searchTerm string, boostByPriority bool) []uuid.UUID {
+	query := db.Model(&models.BugReport{}).
+		Joins("LEFT JOIN applications ON applications.id = bug_reports.application_id")
+
+	var bugs []models.BugReport
+	require.NoError(t, orderBySearchRank(query, searchTerm, models.DefaultSearchLanguage, boostByPriority).Find(&bugs).Error)
+
+	ids := make([]uuid.UUID, len(bugs))
+	for i, bug := range bugs {
+		ids[i] = bug.ID
+	}
+	return ids
+}
+
+func TestOrderBySearchRank(t *testing.T) {
+	db := setupSearchRankTestDB(t)
+	app := &models.Application{ID: uuid.New(), Name: "Editor"}
+	require.NoError(t, db.Create(app).Error)
+
+	now := time.Now()
+	critical := createRankedBug(t, db, app, models.BugPriorityCritical, 1, now.Add(-time.Hour))
+	low := createRankedBug(t, db, app, models.BugPriorityLow, 3, now)
+
+	// A critical bug outranks a more relevant low priority bug
+	assert.Equal(t, []uuid.UUID{critical.ID, low.ID}, searchRankedBugIDs(t, db, "crash", true))
+
+	// Without the boost relevance alone decides
+	assert.Equal(t, []uuid.UUID{low.ID, critical.ID}, searchRankedBugIDs(t, db, "crash", false))
+}
+
+func TestOrderBySearchRank_PriorityBoundaries(t *testing.T) {
+	tests := []struct {
+		name string
+		// older is created first, so it loses ties on relevance
+		olderPriority    string
+		olderMentions    int
+		newerPriority    string
+		newerMentions    int
+		expectOlderFirst bool
+	}{
+		{"critical beats low under 4x the relevance", models.BugPriorityCritical, 1, models.BugPriorityLow, 3, true},
+		{"critical ties low at 4x the relevance", models.BugPriorityCritical, 1, models.BugPriorityLow, 4, false},
+		{"low beats critical over 4x the relevance", models.BugPriorityCritical, 1, models.BugPriorityLow, 5, false},
+		{"high beats low under 3x the relevance", models.BugPriorityHigh, 1, models.BugPriorityLow, 2, true},
+		{"high ties low at 3x the relevance", models.BugPriorityHigh, 1, models.BugPriorityLow, 3, false},
+		{"medium ties low at 2x the relevance", models.BugPriorityMedium, 1, models.BugPriorityLow, 2, false},
+		{"medium beats low under 2x the relevance", models.BugPriorityMedium, 2, models.BugPriorityLow, 3, true},
+		{"critical ties high at 4/3 the relevance", models.BugPriorityCritical, 3, models.BugPriorityHigh, 4, false},
+		{"critical beats high at equal relevance", models.BugPriorityCritical, 3, models.BugPriorityHigh, 3, true},
+		{"equal priority falls back to relevance", models.BugPriorityLow, 2, models.BugPriorityLow, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := setupSearchRankTestDB(t)
+			app := &models.Application{ID: uuid.New(), Name: "Editor"}
+			require.NoError(t, db.Create(app).Error)
+
+			now := time.Now()
+			older := createRankedBug(t, db, app, tt.olderPriority, tt.olderMentions, now.Add(-time.Hour))
+			newer := createRankedBug(t, db, app, tt.newerPriority, tt.newerMentions, now)
+
+			expected := []uuid.UUID{newer.ID, older.ID}
+			if tt.expectOlderFirst {
+				expected = []uuid.UUID{older.ID, newer.ID}
+			}
+			assert.Equal(t, expected, searchRankedBugIDs(t, db, "crash", true))
+		})
+	}
+}
+
+func BenchmarkOrderBySearchRank(b *testing.B) {
+	db := setupSearchRankTestDB(b)
+	app := &models.Application{ID: uuid.New(), Name: "Editor"}
+	require.NoError(b, db.Create(app).Error)
+
+	priorities := []string{models.BugPriorityCritical, models.BugPriorityHigh, models.BugPriorityMedium, models.BugPriorityLow}
+	now := time.Now()
+	for i := 0; i < 200; i++ {
+		createRankedBug(b, db, app, priorities[i%len(priorities)], i%7+1, now.Add(-time.Duration(i)*time.Minute))
+	}
+
+	for _, boost := range []bool{true, false} {
+		b.Run(fmt.Sprintf("boost_by_priority=%t", boost), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				searchRankedBugIDs(b, db, "crash", boost)
+			}
+		})
+	}
+}
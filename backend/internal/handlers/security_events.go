@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const (
+	// failedLoginAlertThreshold is how many failed logins an IP may make within
+	// failedLoginAlertWindow before it is raised for moderation
+	failedLoginAlertThreshold = 20
+	failedLoginAlertWindow    = 5 * time.Minute
+)
+
+// recordSecurityEvent logs a security event for the request. Failed logins also raise
+// a moderation queue entry when their IP exceeds the failed login threshold.
+func recordSecurityEvent(db *gorm.DB, c *gin.Context, userID *uuid.UUID, eventType string, details map[string]interface{}) error {
+	event := models.SecurityEvent{
+		UserID:    userID,
+		EventType: eventType,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.GetHeader("User-Agent"),
+	}
+	if details != nil {
+		data, err := json.Marshal(details)
+		if err != nil {
+			return fmt.Errorf("failed to encode security event details: %v", err)
+		}
+		event.Details = datatypes.JSON(data)
+	}
+
+	if err := db.Create(&event).Error; err != nil {
+		return err
+	}
+
+	if eventType == models.SecurityEventFailedLogin {
+		return raiseFailedLoginAlert(db, event.IPAddress)
+	}
+	return nil
+}
+
+// raiseFailedLoginAlert queues an IP for moderation once it exceeds the failed login
+// threshold, unless it already has an open entry
+func raiseFailedLoginAlert(db *gorm.DB, ipAddress string) error {
+	since := time.Now().Add(-failedLoginAlertWindow)
+
+	var failures int64
+	if err := db.Model(&models.SecurityEvent{}).
+		Where("event_type = ? AND ip_address = ? AND created_at >= ?", models.SecurityEventFailedLogin, ipAddress, since).
+		Count(&failures).Error; err != nil {
+		return err
+	}
+	if failures <= failedLoginAlertThreshold {
+		return nil
+	}
+
+	var open int64
+	if err := db.Model(&models.ModerationQueueEntry{}).
+		Where("entry_type = ? AND subject = ? AND resolved_at IS NULL", models.ModerationEntrySuspiciousIP, ipAddress).
+		Count(&open).Error; err != nil {
+		return err
+	}
+	if open > 0 {
+		return nil
+	}
+
+	details, err := json.Marshal(map[string]interface{}{
+		"failed_logins":  failures,
+		"window_minutes": int(failedLoginAlertWindow / time.Minute),
+	})
+	if err != nil {
+		return err
+	}
+
+	return db.Create(&models.ModerationQueueEntry{
+		EntryType: models.ModerationEntrySuspiciousIP,
+		Subject:   ipAddress,
+		Details:   datatypes.JSON(details),
+	}).Error
+}
+
+// ListSecurityEvents returns security events with pagination. Events can be filtered by
+// event_type, user_id, ip_address and date range (from_date inclusive, to_date exclusive).
+func (h *AdminHandler) ListSecurityEvents(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	eventType := c.Query("event_type")
+	userID := c.Query("user_id")
+	ipAddress := c.Query("ip_address")
+
+	if page <= 0 {
+		page = 1
+	}
+	limit = h.pagination.WithDefault(50).Limit(limit)
+
+	if eventType != "" && !models.IsValidSecurityEventType(eventType) {
+		errors.ErrInvalidEventType.Response(c)
+		return
+	}
+
+	query := h.db.Model(&models.SecurityEvent{})
+
+	if eventType != "" {
+		query = query.Where("event_type = ?", eventType)
+	}
+	if userID != "" {
+		userUUID, err := uuid.Parse(userID)
+		if err != nil {
+			errors.ErrInvalidID.WithMessage("Invalid user ID format").Response(c)
+			return
+		}
+		query = query.Where("user_id = ?", userUUID)
+	}
+	if ipAddress != "" {
+		query = query.Where("ip_address = ?", ipAddress)
+	}
+	for _, bound := range []struct {
+		param     string
+		condition string
+	}{
+		{"from_date", "created_at >= ?"},
+		{"to_date", "created_at < ?"},
+	} {
+		value := c.Query(bound.param)
+		if value == "" {
+			continue
+		}
+		date, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			errors.ErrInvalidDate.WithMessage(bound.param + " must be an RFC3339 timestamp").Response(c)
+			return
+		}
+		query = query.Where(bound.condition, date)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	offset := (page - 1) * limit
+	var events []models.SecurityEvent
+	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&events).Error; err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to fetch security events").Response(c)
+		return
+	}
+
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": totalPages,
+			"has_next":    page < totalPages,
+			"has_prev":    page > 1,
+		},
+	})
+}
+
+// ListModerationQueue returns the open entries in the moderation queue, newest first
+func (h *AdminHandler) ListModerationQueue(c *gin.Context) {
+	var entries []models.ModerationQueueEntry
+	if err := h.db.Where("resolved_at IS NULL").Order("created_at DESC").Find(&entries).Error; err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to fetch moderation queue").Response(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+	})
+}
@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthHandler_SecurityEvents(t *testing.T) {
+	handler, db := setupTestAuthHandler(t)
+
+	hashedPassword, _ := handler.authService.HashPassword("password123")
+	user := models.User{
+		Email:           "test@example.com",
+		DisplayName:     "Test User",
+		PasswordHash:    &hashedPassword,
+		AuthProvider:    "email",
+		IsEmailVerified: true,
+	}
+	require.NoError(t, db.Create(&user).Error)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/login", handler.Login)
+	router.POST("/refresh", handler.RefreshToken)
+	router.POST("/password-reset", handler.RequestPasswordReset)
+
+	post := func(path string, payload interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", "security-test")
+		req.RemoteAddr = "203.0.113.9:4321"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	latestEvent := func(eventType string) models.SecurityEvent {
+		var event models.SecurityEvent
+		require.NoError(t, db.Where("event_type = ?", eventType).Order("created_at DESC").First(&event).Error)
+		return event
+	}
+	clearEvents := func() {
+		require.NoError(t, db.Where("1 = 1").Delete(&models.SecurityEvent{}).Error)
+	}
+
+	t.Run("failed login with wrong password", func(t *testing.T) {
+		clearEvents()
+		w := post("/login", LoginRequest{Email: user.Email, Password: "wrongpassword"})
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+
+		event := latestEvent(models.SecurityEventFailedLogin)
+		require.NotNil(t, event.UserID)
+		assert.Equal(t, user.ID, *event.UserID)
+		assert.Equal(t, "security-test", event.UserAgent)
+		assert.Equal(t, "203.0.113.9", event.IPAddress)
+		assert.Contains(t, string(event.Details), "invalid_password")
+	})
+
+	t.Run("failed login with unknown email", func(t *testing.T) {
+		clearEvents()
+		w := post("/login", LoginRequest{Email: "nobody@example.com", Password: "password123"})
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+
+		event := latestEvent(models.SecurityEventFailedLogin)
+		assert.Nil(t, event.UserID)
+		assert.Contains(t, string(event.Details), "nobody@example.com")
+	})
+
+	t.Run("successful login records nothing", func(t *testing.T) {
+		clearEvents()
+		w := post("/login", LoginRequest{Email: user.Email, Password: "password123"})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var count int64
+		db.Model(&models.SecurityEvent{}).Count(&count)
+		assert.Zero(t, count)
+	})
+
+	t.Run("rejected refresh token", func(t *testing.T) {
+		clearEvents()
+		w := post("/refresh", RefreshTokenRequest{RefreshToken: "not-a-token"})
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+
+		latestEvent(models.SecurityEventTokenRejected)
+	})
+
+	t.Run("password reset request", func(t *testing.T) {
+		clearEvents()
+		w := post("/password-reset", PasswordResetRequest{Email: user.Email})
+		require.Equal(t, http.StatusOK, w.Code)
+
+		event := latestEvent(models.SecurityEventPasswordReset)
+		require.NotNil(t, event.UserID)
+		assert.Equal(t, user.ID, *event.UserID)
+	})
+
+	t.Run("repeated failed logins raise one moderation entry", func(t *testing.T) {
+		clearEvents()
+		for i := 0; i < failedLoginAlertThreshold; i++ {
+			post("/login", LoginRequest{Email: user.Email, Password: "wrongpassword"})
+		}
+
+		var entries int64
+		db.Model(&models.ModerationQueueEntry{}).Count(&entries)
+		assert.Zero(t, entries, "threshold reached but not exceeded")
+
+		post("/login", LoginRequest{Email: user.Email, Password: "wrongpassword"})
+		post("/login", LoginRequest{Email: user.Email, Password: "wrongpassword"})
+
+		var queued []models.ModerationQueueEntry
+		require.NoError(t, db.Find(&queued).Error)
+		require.Len(t, queued, 1)
+		assert.Equal(t, models.ModerationEntrySuspiciousIP, queued[0].EntryType)
+		assert.Equal(t, "203.0.113.9", queued[0].Subject)
+	})
+}
+
+func TestRecordSecurityEvent_AllTypes(t *testing.T) {
+	db := setupTestDB(t)
+	userID := uuid.New()
+
+	gin.SetMode(gin.TestMode)
+	for _, eventType := range []string{
+		models.SecurityEventFailedLogin,
+		models.SecurityEventAccountLocked,
+		models.SecurityEventTokenRejected,
+		models.SecurityEventPasswordReset,
+		models.SecurityEventEmailChange,
+	} {
+		t.Run(eventType, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("POST", "/", nil)
+			c.Request.RemoteAddr = "203.0.113.7:1234"
+
+			require.NoError(t, recordSecurityEvent(db, c, &userID, eventType, map[string]interface{}{"source": "test"}))
+
+			var event models.SecurityEvent
+			require.NoError(t, db.Where("event_type = ?", eventType).First(&event).Error)
+			assert.Equal(t, "203.0.113.7", event.IPAddress)
+			assert.Equal(t, userID, *event.UserID)
+			assert.JSONEq(t, `{"source":"test"}`, string(event.Details))
+		})
+	}
+}
+
+func TestAdminHandler_ListSecurityEvents(t *testing.T) {
+	handler, db := setupAdminTestHandler(t)
+	admin := createTestAdmin(t, db)
+	userID := uuid.New()
+	now := time.Now()
+
+	events := []models.SecurityEvent{
+		{UserID: &userID, EventType: models.SecurityEventFailedLogin, IPAddress: "198.51.100.1", CreatedAt: now.Add(-2 * time.Hour)},
+		{UserID: &userID, EventType: models.SecurityEventPasswordReset, IPAddress: "198.51.100.1", CreatedAt: now.Add(-time.Hour)},
+		{EventType: models.SecurityEventFailedLogin, IPAddress: "198.51.100.2", CreatedAt: now},
+	}
+	for i := range events {
+		require.NoError(t, db.Create(&events[i]).Error)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(mockAuthMiddleware(admin.ID))
+	router.GET("/admin/security-events", handler.ListSecurityEvents)
+
+	list := func(query string) (*httptest.ResponseRecorder, []models.SecurityEvent) {
+		req, _ := http.NewRequest("GET", "/admin/security-events"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response struct {
+			Events []models.SecurityEvent `json:"events"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w, response.Events
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []uuid.UUID
+	}{
+		{"all events newest first", "", []uuid.UUID{events[2].ID, events[1].ID, events[0].ID}},
+		{"by event type", "?event_type=failed_login", []uuid.UUID{events[2].ID, events[0].ID}},
+		{"by user", "?user_id=" + userID.String(), []uuid.UUID{events[1].ID, events[0].ID}},
+		{"by IP address", "?ip_address=198.51.100.2", []uuid.UUID{events[2].ID}},
+		{"by date range", "?from_date=" + now.Add(-90*time.Minute).UTC().Format(time.RFC3339) +
+			"&to_date=" + now.Add(-30*time.Minute).UTC().Format(time.RFC3339), []uuid.UUID{events[1].ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, listed := list(tt.query)
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var ids []uuid.UUID
+			for _, event := range listed {
+				ids = append(ids, event.ID)
+			}
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+
+	t.Run("rejects an unknown event type", func(t *testing.T) {
+		w, _ := list("?event_type=sudo")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("rejects an invalid date", func(t *testing.T) {
+		w, _ := list("?from_date=yesterday")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,454 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"bugrelay-backend/internal/cache"
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+const (
+	// defaultRelatedTagsLimit is how many related tags are returned when no limit is given
+	defaultRelatedTagsLimit = 10
+	// maxRelatedTagsLimit is the most related tags returned for a tag
+	maxRelatedTagsLimit = 50
+
+	// defaultPopularTagsLimit is how many popular tags are returned when no limit is given
+	defaultPopularTagsLimit = 50
+	// maxPopularTagsLimit is the most popular tags returned at once
+	maxPopularTagsLimit = 200
+
+	// otherTagGroup collects the popular tags that match no tag group
+	otherTagGroup = "Other"
+
+	// tagFeedPathPrefix is where the bugs carrying a tag are listed
+	tagFeedPathPrefix = "/api/v1/tags/"
+)
+
+// defaultTagGroups are the groups popular tags are sorted into, by tag pattern
+var defaultTagGroups = map[string][]string{
+	"Error":       {"*-bug", "*-issue", "*-error"},
+	"UI":          {"ui-*", "*-ui", "*-display"},
+	"Performance": {"perf-*", "*-slow"},
+}
+
+// SetTagGroups sets the groups popular tags are sorted into. Each group lists tag
+// patterns where * matches any characters.
+func (h *BugHandler) SetTagGroups(groups map[string][]string) {
+	h.tagGroups = groups
+}
+
+// RelatedTag is a tag that appears on bugs together with another tag
+type RelatedTag struct {
+	Tag               string  `json:"tag"`
+	CoOccurrenceCount int64   `json:"co_occurrence_count"`
+	OverlapPct        float64 `json:"overlap_pct"`
+}
+
+// RelatedTagsResponse represents the tags that appear on bugs together with a tag
+type RelatedTagsResponse struct {
+	Tag         string       `json:"tag"`
+	BugCount    int64        `json:"bug_count"`
+	RelatedTags []RelatedTag `json:"related_tags"`
+}
+
+// GetRelatedTags returns the tags most often found on the same bugs as a tag, with the
+// share of the tag's bugs that carry each. Results are cached for 30 minutes per tag.
+func (h *BugHandler) GetRelatedTags(c *gin.Context) {
+	tag := strings.ToLower(strings.TrimSpace(c.Param("tag")))
+	if !utils.ValidateTag(tag) {
+		errors.ErrInvalidTag.Response(c)
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultRelatedTagsLimit)))
+	if limit <= 0 || limit > maxRelatedTagsLimit {
+		limit = defaultRelatedTagsLimit
+	}
+
+	ctx := c.Request.Context()
+
+	var response RelatedTagsResponse
+	if err := h.cache.GetRelatedTags(ctx, tag, &response); err != nil {
+		// Tags are counted in Go rather than with unnest so the query runs on every database
+		var tagLists []pq.StringArray
+		if err := h.db.Model(&models.BugReport{}).
+			Where("is_spam = ? AND ? = ANY(tags)", false, tag).
+			Pluck("tags", &tagLists).Error; err != nil {
+			errors.ErrQueryFailed.WithMessage("Failed to fetch related tags").Response(c)
+			return
+		}
+
+		response = RelatedTagsResponse{
+			Tag:         tag,
+			BugCount:    int64(len(tagLists)),
+			RelatedTags: relatedTags(tag, tagLists),
+		}
+
+		if err := h.cache.SetRelatedTags(ctx, tag, response); err != nil {
+			// Log cache error but don't fail the request
+			logger.FromContext(ctx).Error("Failed to cache related tags", err, logger.Fields{"tag": tag})
+		}
+	}
+
+	if len(response.RelatedTags) > limit {
+		response.RelatedTags = response.RelatedTags[:limit]
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// relatedTags counts the other tags on bugs carrying tag and returns them most common
+// first, ties broken alphabetically. Each tag's overlap is the percentage of the bugs
+// carrying tag that also carry it.
+func relatedTags(tag string, tagLists []pq.StringArray) []RelatedTag {
+	counts := make(map[string]int64)
+	for _, tags := range tagLists {
+		seen := make(map[string]bool, len(tags))
+		for _, other := range tags {
+			if other == tag || seen[other] {
+				continue
+			}
+			seen[other] = true
+			counts[other]++
+		}
+	}
+
+	result := make([]RelatedTag, 0, len(counts))
+	for other, count := range counts {
+		result = append(result, RelatedTag{
+			Tag:               other,
+			CoOccurrenceCount: count,
+			OverlapPct:        float64(count) / float64(len(tagLists)) * 100,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].CoOccurrenceCount != result[j].CoOccurrenceCount {
+			return result[i].CoOccurrenceCount > result[j].CoOccurrenceCount
+		}
+		return result[i].Tag < result[j].Tag
+	})
+
+	return result
+}
+
+// TagStat is a tag, how many bugs carry it and when the latest of them was reported
+type TagStat struct {
+	Tag        string    `json:"tag"`
+	Count      int64     `json:"count"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+// TagGroup represents popular tags sorted into the same group
+type TagGroup struct {
+	GroupName  string    `json:"group_name"`
+	Tags       []TagStat `json:"tags"`
+	TotalCount int64     `json:"total_count"`
+}
+
+// bugTags is the tags of a bug and when it was reported
+type bugTags struct {
+	Tags      pq.StringArray `gorm:"type:text[]"`
+	CreatedAt time.Time
+}
+
+// GetPopularTags returns the most used tags, most common first. With group=true the
+// tags are sorted into the configured tag groups, so clients can load a group's bugs
+// when it is opened. Tag counts are cached for 20 minutes.
+func (h *BugHandler) GetPopularTags(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultPopularTagsLimit)))
+	if limit <= 0 || limit > maxPopularTagsLimit {
+		limit = defaultPopularTagsLimit
+	}
+	group, _ := strconv.ParseBool(c.DefaultQuery("group", "false"))
+
+	ctx := c.Request.Context()
+
+	var stats []TagStat
+	if err := h.cache.GetPopularTags(ctx, &stats); err != nil {
+		// Tags are counted in Go rather than with unnest so the query runs on every database
+		var bugs []bugTags
+		if err := h.db.Model(&models.BugReport{}).
+			Select("tags", "created_at").
+			Where("is_spam = ?", false).
+			Scan(&bugs).Error; err != nil {
+			errors.ErrQueryFailed.WithMessage("Failed to fetch popular tags").Response(c)
+			return
+		}
+
+		stats = tagStats(bugs)
+
+		if err := h.cache.SetPopularTags(ctx, stats); err != nil {
+			// Log cache error but don't fail the request
+			logger.FromContext(ctx).Error("Failed to cache popular tags", err, nil)
+		}
+	}
+
+	if len(stats) > limit {
+		stats = stats[:limit]
+	}
+
+	if !group {
+		c.JSON(http.StatusOK, gin.H{"tags": stats})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"groups": groupTags(stats, h.tagGroups)})
+}
+
+// tagStats counts the bugs carrying each tag and returns the tags most common first,
+// ties broken alphabetically
+func tagStats(bugs []bugTags) []TagStat {
+	byTag := make(map[string]*TagStat)
+	for _, bug := range bugs {
+		seen := make(map[string]bool, len(bug.Tags))
+		for _, tag := range bug.Tags {
+			if seen[tag] {
+				continue
+			}
+			seen[tag] = true
+
+			stat, ok := byTag[tag]
+			if !ok {
+				stat = &TagStat{Tag: tag}
+				byTag[tag] = stat
+			}
+			stat.Count++
+			if bug.CreatedAt.After(stat.LastUsedAt) {
+				stat.LastUsedAt = bug.CreatedAt
+			}
+		}
+	}
+
+	result := make([]TagStat, 0, len(byTag))
+	for _, stat := range byTag {
+		result = append(result, *stat)
+	}
+	sortTagStats(result)
+
+	return result
+}
+
+// groupTags sorts tags into the groups whose patterns they match. A tag matching
+// several groups goes into the first of them alphabetically, and tags matching none
+// go into the Other group. Groups are returned with the most used first and Other
+// last, and empty groups are left out.
+func groupTags(stats []TagStat, groups map[string][]string) []TagGroup {
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	byName := make(map[string]*TagGroup)
+	for _, stat := range stats {
+		name := tagGroupName(stat.Tag, names, groups)
+
+		tagGroup, ok := byName[name]
+		if !ok {
+			tagGroup = &TagGroup{GroupName: name, Tags: make([]TagStat, 0)}
+			byName[name] = tagGroup
+		}
+		tagGroup.Tags = append(tagGroup.Tags, stat)
+		tagGroup.TotalCount += stat.Count
+	}
+
+	result := make([]TagGroup, 0, len(byName))
+	for _, tagGroup := range byName {
+		sortTagStats(tagGroup.Tags)
+		result = append(result, *tagGroup)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if (result[i].GroupName == otherTagGroup) != (result[j].GroupName == otherTagGroup) {
+			return result[j].GroupName == otherTagGroup
+		}
+		if result[i].TotalCount != result[j].TotalCount {
+			return result[i].TotalCount > result[j].TotalCount
+		}
+		return result[i].GroupName < result[j].GroupName
+	})
+
+	return result
+}
+
+// tagGroupName returns the first of names whose patterns match tag, or Other
+func tagGroupName(tag string, names []string, groups map[string][]string) string {
+	for _, name := range names {
+		for _, pattern := range groups[name] {
+			if matched, _ := path.Match(pattern, tag); matched {
+				return name
+			}
+		}
+	}
+	return otherTagGroup
+}
+
+// sortTagStats orders tags most common first, ties broken alphabetically
+func sortTagStats(stats []TagStat) {
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Count != stats[j].Count {
+			return stats[i].Count > stats[j].Count
+		}
+		return stats[i].Tag < stats[j].Tag
+	})
+}
+
+// ListBugsByTagRequest represents query parameters for listing the bugs carrying a tag
+type ListBugsByTagRequest struct {
+	Page  int    `form:"page,default=1"`
+	Limit int    `form:"limit,default=20"`
+	Sort  string `form:"sort,default=recent"`
+}
+
+// TagApplicationCount is how many of the bugs carrying a tag belong to an application
+type TagApplicationCount struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// ListBugsByTag lists the bugs carrying a tag across every application, along with
+// how many of them each application has. Tags are matched in lowercase, and a tag no
+// bug carries is reported as not found. Pages are cached for 5 minutes.
+func (h *BugHandler) ListBugsByTag(c *gin.Context) {
+	tag := strings.ToLower(strings.TrimSpace(c.Param("tag")))
+	if !utils.ValidateTag(tag) {
+		errors.ErrInvalidTag.Response(c)
+		return
+	}
+
+	var req ListBugsByTagRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		errors.ErrValidationError.WithMessage("Invalid query parameters").WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+
+	// Validate and set limits
+	req.Limit = h.pagination.Limit(req.Limit)
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+
+	switch req.Sort {
+	case "recent", "oldest", "popular":
+	default:
+		errors.ErrInvalidSort.WithMessage("Sort must be one of recent, oldest, popular").Response(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	type CachedResponse struct {
+		Bugs         []models.BugReport     `json:"bugs"`
+		Applications []TagApplicationCount  `json:"applications"`
+		Pagination   map[string]interface{} `json:"pagination"`
+	}
+
+	cacheKey := cache.GenerateCacheKey(req.Page, req.Limit, req.Sort)
+
+	var cachedResp CachedResponse
+	if err := h.cache.GetTagBugList(ctx, tag, cacheKey, &cachedResp); err == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"bugs":         cachedResp.Bugs,
+			"applications": cachedResp.Applications,
+			"pagination":   cachedResp.Pagination,
+		})
+		return
+	}
+
+	queryOptions := BugQueryOptions{
+		Tags:               tag,
+		SpamScoreThreshold: h.spamScoreThreshold,
+	}
+
+	// The per-application counts add up to the total, so they are fetched in its place
+	applications := make([]TagApplicationCount, 0)
+	if err := buildBugQuery(h.db, queryOptions).
+		Select("applications.name AS name, COUNT(*) AS count").
+		Group("applications.name").
+		Order("count DESC").Order("applications.name ASC").
+		Scan(&applications).Error; err != nil {
+		errors.ErrCountFailed.WithMessage("Failed to count bug reports").Response(c)
+		return
+	}
+
+	var total int64
+	for _, application := range applications {
+		total += application.Count
+	}
+	if total == 0 {
+		errors.ErrTagNotFound.Response(c)
+		return
+	}
+
+	query := buildBugQuery(h.db, queryOptions).
+		Preload("Application").
+		Preload("Reporter").
+		Preload("AssignedCompany")
+	switch req.Sort {
+	case "popular":
+		query = query.Order("bug_reports.weighted_vote_count DESC").Order("bug_reports.created_at DESC")
+	case "oldest":
+		query = query.Order("bug_reports.created_at ASC")
+	default:
+		query = query.Order("bug_reports.created_at DESC")
+	}
+
+	bugs := make([]models.BugReport, 0)
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Offset(offset).Limit(req.Limit).Find(&bugs).Error; err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to fetch bug reports").Response(c)
+		return
+	}
+
+	totalPages := int((total + int64(req.Limit) - 1) / int64(req.Limit))
+	paginationInfo := gin.H{
+		"page":        req.Page,
+		"limit":       req.Limit,
+		"total":       total,
+		"total_pages": totalPages,
+		"has_next":    req.Page < totalPages,
+		"has_prev":    req.Page > 1,
+	}
+
+	if err := h.cache.SetTagBugList(ctx, tag, cacheKey, CachedResponse{
+		Bugs:         bugs,
+		Applications: applications,
+		Pagination:   paginationInfo,
+	}); err != nil {
+		// Log cache error but don't fail the request
+		logger.FromContext(ctx).Error("Failed to cache tag bug list", err, logger.Fields{"tag": tag})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"bugs":         bugs,
+		"applications": applications,
+		"pagination":   paginationInfo,
+	})
+}
+
+// tagFeedLinks links to the bug listing of each of tags, in the order first given
+func tagFeedLinks(tags []string) []gin.H {
+	links := make([]gin.H, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(tag)
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		links = append(links, gin.H{"tag": tag, "href": tagFeedPathPrefix + url.PathEscape(tag) + "/bugs"})
+	}
+	return links
+}
@@ -0,0 +1,418 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/cache"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestRelatedTags(t *testing.T) {
+	tagLists := []pq.StringArray{
+		{"ios", "crash", "login"},
+		{"ios", "crash"},
+		{"crash", "ios", "ui"},
+		{"ios", "login", "login"},
+	}
+
+	related := relatedTags("ios", tagLists)
+	require.Len(t, related, 3)
+
+	// The requested tag is never related to itself
+	for _, tag := range related {
+		assert.NotEqual(t, "ios", tag.Tag)
+	}
+
+	assert.Equal(t, RelatedTag{Tag: "crash", CoOccurrenceCount: 3, OverlapPct: 75}, related[0])
+	// A tag repeated on one bug counts once
+	assert.Equal(t, RelatedTag{Tag: "login", CoOccurrenceCount: 2, OverlapPct: 50}, related[1])
+	assert.Equal(t, RelatedTag{Tag: "ui", CoOccurrenceCount: 1, OverlapPct: 25}, related[2])
+
+	t.Run("ties are broken alphabetically", func(t *testing.T) {
+		related := relatedTags("ios", []pq.StringArray{{"ios", "zebra", "alpha"}, {"ios"}, {"ios"}})
+		require.Len(t, related, 2)
+		assert.Equal(t, "alpha", related[0].Tag)
+		assert.Equal(t, "zebra", related[1].Tag)
+		assert.InDelta(t, 33.33, related[0].OverlapPct, 0.01)
+	})
+
+	t.Run("an unused tag has no related tags", func(t *testing.T) {
+		related := relatedTags("unused", nil)
+		assert.NotNil(t, related)
+		assert.Empty(t, related)
+	})
+}
+
+func TestBugHandler_GetRelatedTags(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupBugTestDB(t)
+	redisClient, _ := newMockRedisClient()
+	handler := NewBugHandler(db, redisClient)
+
+	router := gin.New()
+	router.GET("/tags/:tag/related", handler.GetRelatedTags)
+
+	get := func(path string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w, response
+	}
+
+	t.Run("invalid tag", func(t *testing.T) {
+		w, response := get("/tags/not%20a%20tag!/related")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "INVALID_TAG", response["error"].(map[string]interface{})["code"])
+	})
+
+	t.Run("cached result is limited", func(t *testing.T) {
+		cached := RelatedTagsResponse{
+			Tag:      "ios",
+			BugCount: 4,
+			RelatedTags: []RelatedTag{
+				{Tag: "crash", CoOccurrenceCount: 3, OverlapPct: 75},
+				{Tag: "login", CoOccurrenceCount: 2, OverlapPct: 50},
+			},
+		}
+		require.NoError(t, handler.cache.SetRelatedTags(context.Background(), "ios", cached))
+
+		w, response := get("/tags/IOS/related?limit=1")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "ios", response["tag"])
+		assert.Equal(t, float64(4), response["bug_count"])
+
+		related := response["related_tags"].([]interface{})
+		require.Len(t, related, 1)
+		assert.Equal(t, "crash", related[0].(map[string]interface{})["tag"])
+		assert.Equal(t, float64(75), related[0].(map[string]interface{})["overlap_pct"])
+
+		w, response = get("/tags/ios/related")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Len(t, response["related_tags"], 2)
+	})
+}
+
+func TestGroupTags(t *testing.T) {
+	stats := []TagStat{
+		{Tag: "login-bug", Count: 9},
+		{Tag: "ui-glitch", Count: 7},
+		{Tag: "crash-error", Count: 5},
+		{Tag: "ios", Count: 4},
+		{Tag: "upload-slow", Count: 3},
+		{Tag: "checkout-issue", Count: 6},
+		{Tag: "ui-error", Count: 2},
+		{Tag: "android", Count: 4},
+	}
+
+	groups := groupTags(stats, defaultTagGroups)
+
+	tagsOf := func(group TagGroup) []string {
+		var tags []string
+		for _, stat := range group.Tags {
+			tags = append(tags, stat.Tag)
+		}
+		return tags
+	}
+
+	require.Len(t, groups, 4)
+
+	// Groups are ordered by total count, tags by count within each group
+	assert.Equal(t, "Error", groups[0].GroupName)
+	assert.Equal(t, []string{"login-bug", "checkout-issue", "crash-error", "ui-error"}, tagsOf(groups[0]))
+	assert.Equal(t, int64(22), groups[0].TotalCount)
+
+	assert.Equal(t, "UI", groups[1].GroupName)
+	assert.Equal(t, []string{"ui-glitch"}, tagsOf(groups[1]))
+
+	assert.Equal(t, "Performance", groups[2].GroupName)
+	assert.Equal(t, []string{"upload-slow"}, tagsOf(groups[2]))
+
+	// Ungrouped tags are listed last, even with a higher total
+	assert.Equal(t, otherTagGroup, groups[3].GroupName)
+	assert.Equal(t, []string{"android", "ios"}, tagsOf(groups[3]))
+	assert.Equal(t, int64(8), groups[3].TotalCount)
+
+	t.Run("without groups every tag is in Other", func(t *testing.T) {
+		groups := groupTags(stats, nil)
+		require.Len(t, groups, 1)
+		assert.Equal(t, otherTagGroup, groups[0].GroupName)
+		assert.Len(t, groups[0].Tags, len(stats))
+		assert.Equal(t, int64(40), groups[0].TotalCount)
+	})
+}
+
+func TestBugHandler_GetPopularTags(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupBugTestDB(t)
+	redisClient, mock := newMockRedisClient()
+	handler := NewBugHandler(db, redisClient)
+	user := createTestUser(t, db)
+	app := createTestApplication(t, db)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	createBug := func(createdAt time.Time, spam bool, tags ...string) {
+		bug := &models.BugReport{
+			ID:            uuid.New(),
+			Title:         "Tagged bug",
+			Description:   "This is a valid bug description with sufficient length",
+			Status:        models.BugStatusOpen,
+			Priority:      models.BugPriorityMedium,
+			ApplicationID: app.ID,
+			ReporterID:    &user.ID,
+			Tags:          pq.StringArray(tags),
+			IsSpam:        spam,
+			CreatedAt:     createdAt,
+		}
+		require.NoError(t, db.Create(bug).Error)
+	}
+	createBug(now.Add(-48*time.Hour), false, "login-bug", "ios")
+	createBug(now.Add(-time.Hour), false, "login-bug", "ui-glitch")
+	createBug(now, false, "ios", "login-bug")
+	createBug(now, true, "spam-bug", "spam-bug")
+
+	router := gin.New()
+	router.GET("/bugs/tags/popular", handler.GetPopularTags)
+
+	get := func(path string) map[string]interface{} {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	// Spam is not counted
+	response := get("/bugs/tags/popular")
+	tags := response["tags"].([]interface{})
+	require.Len(t, tags, 3)
+
+	first := tags[0].(map[string]interface{})
+	assert.Equal(t, "login-bug", first["tag"])
+	assert.Equal(t, float64(3), first["count"])
+	assert.Equal(t, now.Format(time.RFC3339), first["last_used_at"])
+	assert.Equal(t, "ios", tags[1].(map[string]interface{})["tag"])
+	assert.Equal(t, "ui-glitch", tags[2].(map[string]interface{})["tag"])
+	assert.Equal(t, cache.PopularTagsCacheDuration, mock.ttls[cache.TagCachePrefix+"popular"])
+
+	response = get("/bugs/tags/popular?limit=1")
+	assert.Len(t, response["tags"], 1)
+
+	response = get("/bugs/tags/popular?group=true")
+	groups := response["groups"].([]interface{})
+	require.Len(t, groups, 3)
+	assert.Equal(t, "Error", groups[0].(map[string]interface{})["group_name"])
+	assert.Equal(t, float64(3), groups[0].(map[string]interface{})["total_count"])
+	assert.Equal(t, "UI", groups[1].(map[string]interface{})["group_name"])
+	assert.Equal(t, otherTagGroup, groups[2].(map[string]interface{})["group_name"])
+
+	// Counts come from the cache until it expires
+	createBug(now, false, "new-tag")
+	response = get("/bugs/tags/popular")
+	assert.Len(t, response["tags"], 3)
+}
+
+// tagArrayDriver is a SQLite driver with a stand-in for PostgreSQL's array membership test
+const tagArrayDriver = "sqlite3_tag_arrays"
+
+var registerTagArrayDriver sync.Once
+
+// anyArrayPattern matches PostgreSQL's "? = ANY(column)", which SQLite cannot parse
+var anyArrayPattern = regexp.MustCompile(`\? = ANY\(([\w.]+)\)`)
+
+// arrayContains reports whether a text array, stored as by pq.StringArray, holds value
+func arrayContains(array, value string) (bool, error) {
+	var values pq.StringArray
+	if err := values.Scan(array); err != nil {
+		return false, err
+	}
+	for _, v := range values {
+		if v == value {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// anyArrayConn rewrites "? = ANY(column)" conditions into array_contains calls
+type anyArrayConn struct {
+	*sql.DB
+}
+
+func (c anyArrayConn) rewrite(query string) string {
+	return anyArrayPattern.ReplaceAllString(query, "array_contains($1, ?)")
+}
+
+func (c anyArrayConn) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return c.DB.PrepareContext(ctx, c.rewrite(query))
+}
+
+func (c anyArrayConn) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return c.DB.ExecContext(ctx, c.rewrite(query), args...)
+}
+
+func (c anyArrayConn) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return c.DB.QueryContext(ctx, c.rewrite(query), args...)
+}
+
+func (c anyArrayConn) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return c.DB.QueryRowContext(ctx, c.rewrite(query), args...)
+}
+
+// setupTagArrayTestDB creates a test database that supports filtering bugs by tag
+func setupTagArrayTestDB(t *testing.T) *gorm.DB {
+	registerTagArrayDriver.Do(func() {
+		sql.Register(tagArrayDriver, &sqlite3.SQLiteDriver{
+			ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+				return conn.RegisterFunc("array_contains", arrayContains, true)
+			},
+		})
+	})
+
+	conn, err := sql.Open(tagArrayDriver, ":memory:")
+	require.NoError(t, err)
+	// Every connection to :memory: opens its own database
+	conn.SetMaxOpenConns(1)
+	t.Cleanup(func() { conn.Close() })
+
+	return openBugTestDB(t, sqlite.New(sqlite.Config{Conn: anyArrayConn{conn}}))
+}
+
+func TestBugHandler_ListBugsByTag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupTagArrayTestDB(t)
+	redisClient, mock := newMockRedisClient()
+	handler := NewBugHandler(db, redisClient)
+	user := createTestUser(t, db)
+
+	alpha := &models.Application{ID: uuid.New(), Name: "Alpha App"}
+	beta := &models.Application{ID: uuid.New(), Name: "Beta App"}
+	require.NoError(t, db.Create(alpha).Error)
+	require.NoError(t, db.Create(beta).Error)
+
+	start := time.Now().Add(-time.Hour)
+	tagged := func(app *models.Application, minutes int, tags ...string) *models.BugReport {
+		bug := createTestBugReport(t, db, app, user)
+		require.NoError(t, db.Model(bug).Updates(map[string]interface{}{
+			"tags":       pq.StringArray(tags),
+			"created_at": start.Add(time.Duration(minutes) * time.Minute),
+		}).Error)
+		return bug
+	}
+
+	first := tagged(alpha, 0, "crash", "ios")
+	second := tagged(beta, 1, "crash")
+	third := tagged(alpha, 2, "login", "crash")
+	tagged(beta, 3, "ios")
+	spam := tagged(beta, 4, "crash")
+	require.NoError(t, db.Model(spam).Update("is_spam", true).Error)
+
+	router := gin.New()
+	router.GET("/tags/:tag/bugs", handler.ListBugsByTag)
+
+	get := func(path string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w, response
+	}
+
+	ids := func(response map[string]interface{}) []string {
+		var result []string
+		for _, bug := range response["bugs"].([]interface{}) {
+			result = append(result, bug.(map[string]interface{})["id"].(string))
+		}
+		return result
+	}
+
+	t.Run("lists bugs across applications", func(t *testing.T) {
+		w, response := get("/tags/crash/bugs")
+		require.Equal(t, http.StatusOK, w.Code)
+
+		assert.Equal(t, []string{third.ID.String(), second.ID.String(), first.ID.String()}, ids(response))
+		assert.Equal(t, float64(3), response["pagination"].(map[string]interface{})["total"])
+
+		// The application counts add up to the bugs listed
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"name": "Alpha App", "count": float64(2)},
+			map[string]interface{}{"name": "Beta App", "count": float64(1)},
+		}, response["applications"])
+	})
+
+	t.Run("normalizes the tag", func(t *testing.T) {
+		w, response := get("/tags/%20CRASH%20/bugs?sort=oldest")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{first.ID.String(), second.ID.String(), third.ID.String()}, ids(response))
+	})
+
+	t.Run("paginates with counts for every page", func(t *testing.T) {
+		w, response := get("/tags/crash/bugs?limit=2&page=2")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []string{first.ID.String()}, ids(response))
+		assert.Len(t, response["applications"], 2)
+		assert.Equal(t, false, response["pagination"].(map[string]interface{})["has_next"])
+	})
+
+	t.Run("unknown tag is not found", func(t *testing.T) {
+		w, response := get("/tags/unused/bugs")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "TAG_NOT_FOUND", response["error"].(map[string]interface{})["code"])
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		w, response := get("/tags/not%20a%20tag!/bugs")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "INVALID_TAG", response["error"].(map[string]interface{})["code"])
+
+		w, response = get("/tags/crash/bugs?sort=trending")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "INVALID_SORT", response["error"].(map[string]interface{})["code"])
+	})
+
+	t.Run("caches each page", func(t *testing.T) {
+		key := cache.TagCachePrefix + "crash:bugs:" + cache.GenerateCacheKey(1, 20, "recent")
+		assert.Equal(t, cache.TagBugListCacheDuration, mock.ttls[key])
+
+		tagged(beta, 5, "crash")
+		_, response := get("/tags/crash/bugs")
+		assert.Len(t, response["bugs"], 3)
+	})
+}
+
+func TestTagFeedLinks(t *testing.T) {
+	links := tagFeedLinks([]string{"crash", "Login Page", "crash"})
+	assert.Equal(t, []gin.H{
+		{"tag": "crash", "href": "/api/v1/tags/crash/bugs"},
+		{"tag": "login page", "href": "/api/v1/tags/login%20page/bugs"},
+	}, links)
+
+	assert.NotNil(t, tagFeedLinks(nil))
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultTrendingWindowDays is how many days of bugs the trending sort lists by default
+const DefaultTrendingWindowDays = 30
+
+// TrendingConfig tunes the trending sort. Bugs are ordered by the trending score
+// stored by the refresh_trending_scores job, and only bugs created in the last
+// WindowDays are listed; zero lists bugs of any age.
+type TrendingConfig struct {
+	WindowDays int
+}
+
+// DefaultTrendingConfig returns the default trending sort settings
+func DefaultTrendingConfig() TrendingConfig {
+	return TrendingConfig{
+		WindowDays: DefaultTrendingWindowDays,
+	}
+}
+
+// windowStart returns the creation time of the oldest bugs listed as trending at
+// now, or nil when bugs of any age are listed
+func (cfg TrendingConfig) windowStart(now time.Time) *time.Time {
+	if cfg.WindowDays <= 0 {
+		return nil
+	}
+	start := now.AddDate(0, 0, -cfg.WindowDays)
+	return &start
+}
+
+// SetTrending sets which bugs the trending sort lists
+func (h *BugHandler) SetTrending(cfg TrendingConfig) {
+	h.trending = cfg
+}
+
+// orderByTrending orders bugs by their stored trending score, then recency
+func orderByTrending(query *gorm.DB) *gorm.DB {
+	return query.Order("bug_reports.trending_score DESC").Order("bug_reports.created_at DESC")
+}
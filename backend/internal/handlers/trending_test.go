@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/cache"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// trendingDriver is a SQLite driver with stand-ins for the PostgreSQL functions
+// used by the trending score
+const trendingDriver = "sqlite3_trending"
+
+var registerTrendingDriver sync.Once
+
+// fakeDatePart supports DATE_PART('epoch', timestamp) on timestamps stored by SQLite
+func fakeDatePart(field, value string) (float64, error) {
+	for _, layout := range sqlite3.SQLiteTimestampFormats {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return float64(parsed.Unix()), nil
+		}
+	}
+	return 0, &time.ParseError{Value: value, Message: "unsupported timestamp"}
+}
+
+// setupTrendingTestDB creates a test database that supports sorting by trending score
+func setupTrendingTestDB(t testing.TB) *gorm.DB {
+	registerTrendingDriver.Do(func() {
+		sql.Register(trendingDriver, &sqlite3.SQLiteDriver{
+			ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+				if err := conn.RegisterFunc("date_part", fakeDatePart, true); err != nil {
+					return err
+				}
+				return conn.RegisterFunc("power", math.Pow, true)
+			},
+		})
+	})
+
+	return openBugTestDB(t, sqlite.New(sqlite.Config{DriverName: trendingDriver, DSN: ":memory:"}))
+}
+
+// createTrendingBug creates a bug with votes votes created age ago
+func createTrendingBug(t testing.TB, db *gorm.DB, app *models.Application, votes int, age time.Duration) *models.BugReport {
+	bug := &models.BugReport{
+		ID:            uuid.New(),
+		Title:         "Trending bug",
+		Description:   "Found while testing",
+		Status:        models.BugStatusOpen,
+		Priority:      models.BugPriorityMedium,
+		ApplicationID: app.ID,
+		VoteCount:     votes,
+		CreatedAt:     time.Now().Add(-age),
+	}
+	require.NoError(t, db.Create(bug).Error)
+	return bug
+}
+
+// refreshedTrendingBugs refreshes the trending scores of db's bugs with gravity and
+// returns the bugs in trending order
+func refreshedTrendingBugs(t testing.TB, db *gorm.DB, gravity float64) []models.BugReport {
+	require.NoError(t, models.RefreshTrendingScores(db, gravity, time.Now(), nil))
+	var bugs []models.BugReport
+	require.NoError(t, orderByTrending(db.Model(&models.BugReport{})).Find(&bugs).Error)
+	return bugs
+}
+
+func TestRefreshTrendingScores(t *testing.T) {
+	db := setupTrendingTestDB(t)
+	app := &models.Application{ID: uuid.New(), Name: "Editor"}
+	require.NoError(t, db.Create(app).Error)
+
+	week := 7 * 24 * time.Hour
+	weekOld := createTrendingBug(t, db, app, 100, week)
+	hourOld := createTrendingBug(t, db, app, 100, time.Hour)
+
+	bugs := refreshedTrendingBugs(t, db, 1.8)
+	require.Len(t, bugs, 2)
+
+	// A 1-hour-old bug outranks a 1-week-old bug with the same votes
+	assert.Equal(t, hourOld.ID, bugs[0].ID)
+	assert.Equal(t, weekOld.ID, bugs[1].ID)
+
+	assert.InDelta(t, 100/math.Pow(3, 1.8), bugs[0].TrendingScore, 0.01)
+	assert.InDelta(t, 100/math.Pow(7*24+2, 1.8), bugs[1].TrendingScore, 0.0001)
+}
+
+func TestRefreshTrendingScores_Gravity(t *testing.T) {
+	db := setupTrendingTestDB(t)
+	app := &models.Application{ID: uuid.New(), Name: "Editor"}
+	require.NoError(t, db.Create(app).Error)
+
+	// 300 votes over 2 days against 20 votes in the last hour
+	older := createTrendingBug(t, db, app, 300, 48*time.Hour)
+	newer := createTrendingBug(t, db, app, 20, time.Hour)
+
+	// Default gravity favours the new bug: 300/50^1.8 < 20/3^1.8
+	bugs := refreshedTrendingBugs(t, db, 1.8)
+	assert.Equal(t, []uuid.UUID{newer.ID, older.ID}, []uuid.UUID{bugs[0].ID, bugs[1].ID})
+
+	// Low gravity lets votes outweigh age: 300/50^0.5 > 20/3^0.5
+	bugs = refreshedTrendingBugs(t, db, 0.5)
+	assert.Equal(t, []uuid.UUID{older.ID, newer.ID}, []uuid.UUID{bugs[0].ID, bugs[1].ID})
+}
+
+func TestRefreshTrendingScores_CreatedAfter(t *testing.T) {
+	db := setupTrendingTestDB(t)
+	app := &models.Application{ID: uuid.New(), Name: "Editor"}
+	require.NoError(t, db.Create(app).Error)
+
+	recent := createTrendingBug(t, db, app, 10, time.Hour)
+	old := createTrendingBug(t, db, app, 10, 40*24*time.Hour)
+
+	now := time.Now()
+	windowStart := now.AddDate(0, 0, -30)
+	require.NoError(t, models.RefreshTrendingScores(db, 1.8, now, &windowStart))
+
+	// Bugs older than the window are not scored
+	var scores []float64
+	require.NoError(t, db.Model(&models.BugReport{}).Where("id IN ?", []uuid.UUID{recent.ID, old.ID}).
+		Order("created_at DESC").Pluck("trending_score", &scores).Error)
+	require.Len(t, scores, 2)
+	assert.Greater(t, scores[0], 0.0)
+	assert.Zero(t, scores[1])
+}
+
+func TestBugHandler_ListBugs_Trending(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupTrendingTestDB(t)
+	redisClient, mock := newMockRedisClient()
+	handler := NewBugHandler(db, redisClient)
+	handler.SetTrending(TrendingConfig{WindowDays: 14})
+
+	app := &models.Application{ID: uuid.New(), Name: "Editor"}
+	require.NoError(t, db.Create(app).Error)
+
+	weekOld := createTrendingBug(t, db, app, 100, 7*24*time.Hour)
+	hourOld := createTrendingBug(t, db, app, 100, time.Hour)
+	// Outside the window, however many votes it has
+	createTrendingBug(t, db, app, 10000, 20*24*time.Hour)
+	require.NoError(t, models.RefreshTrendingScores(db, 1.8, time.Now(), nil))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/bugs?sort=trending", nil)
+	handler.ListBugs(c)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Bugs       []models.BugReport     `json:"bugs"`
+		Pagination map[string]interface{} `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Bugs, 2)
+	assert.Equal(t, hourOld.ID, response.Bugs[0].ID)
+	assert.Equal(t, weekOld.ID, response.Bugs[1].ID)
+	assert.Greater(t, response.Bugs[0].TrendingScore, response.Bugs[1].TrendingScore)
+	assert.Equal(t, float64(2), response.Pagination["total"])
+
+	// The first page is cached for longer than other bug lists
+	keys := mock.keysWithPrefix(cache.BugListCachePrefix)
+	require.Len(t, keys, 1)
+	assert.Equal(t, cache.TrendingBugListCacheDuration, mock.ttls[keys[0]])
+}
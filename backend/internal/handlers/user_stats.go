@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// userStatsTopApplications is how many applications are listed in user statistics
+	userStatsTopApplications = 5
+	// userStatsTopTags is how many tags are listed in user statistics
+	userStatsTopTags = 10
+	// reputationPerFixedBug is the reputation earned for each reported bug that gets fixed
+	reputationPerFixedBug = 10
+)
+
+// ApplicationSummary is an application a user has reported bugs against
+type ApplicationSummary struct {
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	BugCount int64     `json:"bug_count"`
+}
+
+// TagCount is a tag and how many of a user's bugs carry it
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
+
+// UserStatsResponse represents a user's public contribution statistics
+type UserStatsResponse struct {
+	BugsSubmitted   int64                `json:"bugs_submitted"`
+	BugsFixed       int64                `json:"bugs_fixed"`
+	CommentsPosted  int64                `json:"comments_posted"`
+	VotesCast       int64                `json:"votes_cast"`
+	CompaniesJoined int64                `json:"companies_joined"`
+	Reputation      int64                `json:"reputation"`
+	MemberSince     time.Time            `json:"member_since"`
+	LastActive      time.Time            `json:"last_active"`
+	TopApplications []ApplicationSummary `json:"top_applications"`
+	TopTags         []TagCount           `json:"top_tags"`
+}
+
+// reputation is the reputation of a user whose bugs received votesReceived votes and
+// of which fixed were fixed
+func reputation(votesReceived, fixed int64) int64 {
+	return votesReceived + fixed*reputationPerFixedBug
+}
+
+// userReputation returns a user's reputation, as listed in their statistics
+func userReputation(db *gorm.DB, userID uuid.UUID) (int64, error) {
+	var totals struct {
+		Fixed         int64
+		VotesReceived int64
+	}
+	if err := db.Model(&models.BugReport{}).
+		Select("COALESCE(SUM(CASE WHEN status = ? THEN 1 ELSE 0 END), 0) AS fixed, "+
+			"COALESCE(SUM(vote_count), 0) AS votes_received", models.BugStatusFixed).
+		Where("reporter_id = ?", userID).
+		Scan(&totals).Error; err != nil {
+		return 0, err
+	}
+	return reputation(totals.VotesReceived, totals.Fixed), nil
+}
+
+// GetUserStats returns a user's public contribution statistics
+func (h *UserHandler) GetUserStats(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid user ID format").Response(c)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	var cached UserStatsResponse
+	if err := h.cache.GetUserStats(ctx, userID.String(), &cached); err == nil {
+		c.JSON(http.StatusOK, cached)
+		return
+	}
+
+	var user models.User
+	if err := h.db.First(&user, "id = ?", userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrUserNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to find user").Response(c)
+		return
+	}
+
+	stats, err := h.loadUserStats(&user)
+	if err != nil {
+		errors.ErrQueryFailed.WithMessage("Failed to fetch user statistics").Response(c)
+		return
+	}
+
+	if err := h.cache.SetUserStats(ctx, userID.String(), stats); err != nil {
+		// Log cache error but don't fail the request
+		logger.FromContext(ctx).Error("Failed to cache user stats", err, logger.Fields{"user_id": userID.String()})
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// loadUserStats aggregates a user's contributions across bugs, comments, votes and companies
+func (h *UserHandler) loadUserStats(user *models.User) (*UserStatsResponse, error) {
+	stats := &UserStatsResponse{
+		MemberSince:     user.CreatedAt,
+		LastActive:      user.LastActiveAt,
+		TopApplications: make([]ApplicationSummary, 0),
+		TopTags:         make([]TagCount, 0),
+	}
+
+	// Bug counts and votes received in a single pass over the user's reports
+	var bugTotals struct {
+		Submitted     int64
+		Fixed         int64
+		VotesReceived int64
+	}
+	if err := h.db.Model(&models.BugReport{}).
+		Select("COUNT(*) AS submitted, "+
+			"COALESCE(SUM(CASE WHEN status = ? THEN 1 ELSE 0 END), 0) AS fixed, "+
+			"COALESCE(SUM(vote_count), 0) AS votes_received", models.BugStatusFixed).
+		Where("reporter_id = ?", user.ID).
+		Scan(&bugTotals).Error; err != nil {
+		return nil, err
+	}
+	stats.BugsSubmitted = bugTotals.Submitted
+	stats.BugsFixed = bugTotals.Fixed
+	stats.Reputation = reputation(bugTotals.VotesReceived, bugTotals.Fixed)
+
+	if err := h.db.Model(&models.Comment{}).Where("user_id = ?", user.ID).Count(&stats.CommentsPosted).Error; err != nil {
+		return nil, err
+	}
+	if err := h.db.Model(&models.BugVote{}).Where("user_id = ?", user.ID).Count(&stats.VotesCast).Error; err != nil {
+		return nil, err
+	}
+	if err := h.db.Model(&models.CompanyMember{}).Where("user_id = ?", user.ID).Count(&stats.CompaniesJoined).Error; err != nil {
+		return nil, err
+	}
+
+	if err := h.db.Model(&models.BugReport{}).
+		Select("applications.id, applications.name, COUNT(*) AS bug_count").
+		Joins("JOIN applications ON applications.id = bug_reports.application_id").
+		Where("bug_reports.reporter_id = ?", user.ID).
+		Group("applications.id, applications.name").
+		Order("bug_count DESC, applications.name ASC").
+		Limit(userStatsTopApplications).
+		Scan(&stats.TopApplications).Error; err != nil {
+		return nil, err
+	}
+
+	if err := h.db.Raw("SELECT tag, COUNT(*) AS count FROM bug_reports CROSS JOIN unnest(tags) AS tag "+
+		"WHERE reporter_id = ? GROUP BY tag ORDER BY count DESC, tag ASC LIMIT ?", user.ID, userStatsTopTags).
+		Scan(&stats.TopTags).Error; err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
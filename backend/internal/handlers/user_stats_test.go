@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserHandler_GetUserStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupBugTestDB(t)
+	handler := NewUserHandler(db, nil)
+	user := createTestUser(t, db)
+	other := createTestUserWithEmail(t, db, "other@example.com")
+
+	apps := make([]*models.Application, 6)
+	for i := range apps {
+		apps[i] = &models.Application{ID: uuid.New(), Name: string(rune('A'+i)) + " App"}
+		require.NoError(t, db.Create(apps[i]).Error)
+	}
+
+	createBug := func(reporter *models.User, app *models.Application, status string, votes int, tags ...string) *models.BugReport {
+		bug := &models.BugReport{
+			ID:            uuid.New(),
+			Title:         "Contribution bug",
+			Description:   "This is a valid bug description with sufficient length",
+			Status:        status,
+			Priority:      models.BugPriorityMedium,
+			ApplicationID: app.ID,
+			ReporterID:    &reporter.ID,
+			VoteCount:     votes,
+			Tags:          pq.StringArray(tags),
+		}
+		require.NoError(t, db.Create(bug).Error)
+		return bug
+	}
+
+	// Three bugs against the first app, two against the second, one each against the rest
+	fixed := createBug(user, apps[0], models.BugStatusFixed, 4, "ui", "crash")
+	createBug(user, apps[0], models.BugStatusFixed, 1, "ui")
+	createBug(user, apps[0], models.BugStatusOpen, 0, "ui", "login")
+	createBug(user, apps[1], models.BugStatusOpen, 2, "crash")
+	createBug(user, apps[1], models.BugStatusReviewing, 0)
+	for _, app := range apps[2:] {
+		createBug(user, app, models.BugStatusOpen, 0)
+	}
+	othersBug := createBug(other, apps[0], models.BugStatusFixed, 7, "ui")
+
+	for _, bug := range []*models.BugReport{fixed, othersBug} {
+		require.NoError(t, db.Create(&models.Comment{ID: uuid.New(), BugID: bug.ID, UserID: user.ID, Content: "Same here"}).Error)
+		require.NoError(t, db.Create(&models.BugVote{ID: uuid.New(), BugID: bug.ID, UserID: user.ID}).Error)
+	}
+	require.NoError(t, db.Create(&models.Comment{ID: uuid.New(), BugID: fixed.ID, UserID: other.ID, Content: "Me too"}).Error)
+
+	company := createTestCompany(t, db, true)
+	createTestCompanyMember(t, db, company.ID, user.ID, "member")
+
+	router := gin.New()
+	router.GET("/users/:id/stats", handler.GetUserStats)
+
+	getStats := func(id string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/users/"+id+"/stats", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("aggregates contributions", func(t *testing.T) {
+		w := getStats(user.ID.String())
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var stats UserStatsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+		assert.Equal(t, int64(9), stats.BugsSubmitted)
+		assert.Equal(t, int64(2), stats.BugsFixed)
+		assert.Equal(t, int64(2), stats.CommentsPosted)
+		assert.Equal(t, int64(2), stats.VotesCast)
+		assert.Equal(t, int64(1), stats.CompaniesJoined)
+		assert.Equal(t, int64(7+2*reputationPerFixedBug), stats.Reputation)
+		assert.WithinDuration(t, user.CreatedAt, stats.MemberSince, 0)
+
+		require.Len(t, stats.TopApplications, userStatsTopApplications)
+		assert.Equal(t, apps[0].ID, stats.TopApplications[0].ID)
+		assert.Equal(t, int64(3), stats.TopApplications[0].BugCount)
+		assert.Equal(t, apps[1].ID, stats.TopApplications[1].ID)
+		assert.Equal(t, int64(2), stats.TopApplications[1].BugCount)
+
+		assert.Equal(t, []TagCount{
+			{Tag: "ui", Count: 3},
+			{Tag: "crash", Count: 2},
+			{Tag: "login", Count: 1},
+		}, stats.TopTags)
+	})
+
+	t.Run("user without contributions", func(t *testing.T) {
+		newcomer := createTestUserWithEmail(t, db, "newcomer@example.com")
+		w := getStats(newcomer.ID.String())
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var stats UserStatsResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+		assert.Zero(t, stats.BugsSubmitted)
+		assert.Zero(t, stats.Reputation)
+		assert.Empty(t, stats.TopApplications)
+		assert.Empty(t, stats.TopTags)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		w := getStats(uuid.New().String())
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("invalid ID", func(t *testing.T) {
+		w := getStats("not-a-uuid")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"bugrelay-backend/internal/cache"
+	"bugrelay-backend/internal/email"
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/middleware"
+	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// UserHandler handles requests about the current user's relationships with other users
+type UserHandler struct {
+	db        *gorm.DB
+	cache     *cache.CacheService
+	deepLinks *email.DeepLinkGenerator
+
+	pagination PaginationConfig
+}
+
+// NewUserHandler creates a new user handler
+func NewUserHandler(db *gorm.DB, redisClient *redis.Client) *UserHandler {
+	return &UserHandler{
+		db:        db,
+		cache:     cache.NewCacheService(redisClient),
+		deepLinks: email.NewDeepLinkGenerator("http://localhost:3000", ""),
+
+		pagination: PaginationConfig{DefaultLimit: DefaultPageLimit, MaxLimit: defaultBugListMaxLimit},
+	}
+}
+
+// SetDeepLinks sets the generator used to verify unsubscribe links
+func (h *UserHandler) SetDeepLinks(deepLinks *email.DeepLinkGenerator) {
+	h.deepLinks = deepLinks
+}
+
+// SetPagination sets the page sizes of the current user's bug listing
+func (h *UserHandler) SetPagination(pagination PaginationConfig) {
+	h.pagination = pagination
+}
+
+// BlockUserRequest represents the request to block a user
+type BlockUserRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+}
+
+// BlockUser blocks another user from commenting on the current user's bugs
+func (h *UserHandler) BlockUser(c *gin.Context) {
+	var req BlockUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if requestBodyTooLarge(c, err) {
+			return
+		}
+		errors.ErrValidationError.WithDetails(utils.FormatValidationErrors(err)).Response(c)
+		return
+	}
+
+	blockedID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid user ID format").Response(c)
+		return
+	}
+
+	currentUserID, ok := currentUserUUID(c)
+	if !ok {
+		return
+	}
+
+	if blockedID == currentUserID {
+		errors.ErrCannotBlockSelf.Response(c)
+		return
+	}
+
+	var blockedUser models.User
+	if err := h.db.First(&blockedUser, "id = ?", blockedID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			errors.ErrUserNotFound.Response(c)
+			return
+		}
+
+		errors.ErrQueryFailed.WithMessage("Failed to find user").Response(c)
+		return
+	}
+
+	// Admins must remain able to moderate every bug
+	if blockedUser.IsAdmin {
+		errors.ErrCannotBlockAdmin.Response(c)
+		return
+	}
+
+	var existing models.UserBlock
+	if err := h.db.Where("blocker_id = ? AND blocked_id = ?", currentUserID, blockedID).First(&existing).Error; err == nil {
+		errors.ErrAlreadyBlocked.Response(c)
+		return
+	}
+
+	block := models.UserBlock{
+		BlockerID: currentUserID,
+		BlockedID: blockedID,
+	}
+	if err := h.db.Create(&block).Error; err != nil {
+		errors.ErrBlockFailed.Response(c)
+		return
+	}
+
+	h.invalidateBlockLists(c.Request.Context(), currentUserID, blockedID)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "User blocked successfully",
+		"block":   block,
+	})
+}
+
+// UnblockUser removes a block the current user placed on another user
+func (h *UserHandler) UnblockUser(c *gin.Context) {
+	blockedID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid user ID format").Response(c)
+		return
+	}
+
+	currentUserID, ok := currentUserUUID(c)
+	if !ok {
+		return
+	}
+
+	result := h.db.Where("blocker_id = ? AND blocked_id = ?", currentUserID, blockedID).Delete(&models.UserBlock{})
+	if result.Error != nil {
+		errors.ErrUnblockFailed.Response(c)
+		return
+	}
+	if result.RowsAffected == 0 {
+		errors.ErrBlockNotFound.Response(c)
+		return
+	}
+
+	h.invalidateBlockLists(c.Request.Context(), currentUserID, blockedID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "User unblocked successfully",
+	})
+}
+
+// Unsubscribe turns off a notification type using a signed link from a notification email
+func (h *UserHandler) Unsubscribe(c *gin.Context) {
+	userID, err := uuid.Parse(c.Query("user_id"))
+	if err != nil {
+		errors.ErrInvalidID.WithMessage("Invalid user ID format").Response(c)
+		return
+	}
+
+	notificationType := c.Query("type")
+	if notificationType != models.NotificationTypeAll && !models.IsValidNotificationType(notificationType) {
+		errors.ErrInvalidNotificationType.Response(c)
+		return
+	}
+
+	if !h.deepLinks.VerifyUnsubscribeSignature(userID, notificationType, c.Query("signature")) {
+		errors.ErrInvalidSignature.Response(c)
+		return
+	}
+
+	var user models.User
+	if err := h.db.First(&user, "id = ?", userID).Error; err != nil {
+		errors.ErrUserNotFound.Response(c)
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if notificationType == models.NotificationTypeAll {
+		for _, notificationType := range models.NotificationTypes {
+			updates[notificationType] = false
+		}
+	} else {
+		updates[notificationType] = false
+	}
+
+	preferences, err := h.saveNotificationPreferences(c.Request.Context(), userID, updates)
+	if err != nil {
+		errors.ErrUnsubscribeFailed.Response(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Unsubscribed successfully",
+		"preferences": preferences,
+	})
+}
+
+// invalidateBlockLists clears the cached block lists of both users in a block
+func (h *UserHandler) invalidateBlockLists(ctx context.Context, blockerID, blockedID uuid.UUID) {
+	if err := h.cache.InvalidateUserBlocks(ctx, blockerID.String(), blockedID.String()); err != nil {
+		// Log cache error but don't fail the request
+		logger.FromContext(ctx).Error("Failed to invalidate block lists", err, logger.Fields{"blocker_id": blockerID.String(), "blocked_id": blockedID.String()})
+	}
+}
+
+// currentUserUUID returns the authenticated user's ID, writing an unauthorized
+// response and returning false if there is none
+func currentUserUUID(c *gin.Context) (uuid.UUID, bool) {
+	userIDStr, _ := middleware.GetCurrentUserID(c)
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		errors.ErrUnauthorized.Response(c)
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// userBlockList holds a user's block relationships in both directions
+type userBlockList struct {
+	Blocked   []uuid.UUID `json:"blocked"`    // users this user has blocked
+	BlockedBy []uuid.UUID `json:"blocked_by"` // users who have blocked this user
+}
+
+// hasBlocked reports whether the user has blocked userID
+func (b *userBlockList) hasBlocked(userID uuid.UUID) bool {
+	for _, id := range b.Blocked {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// hiddenUserIDs returns the users whose content is hidden from this user. Blocks
+// hide content in both directions.
+func (b *userBlockList) hiddenUserIDs() []uuid.UUID {
+	hidden := make([]uuid.UUID, 0, len(b.Blocked)+len(b.BlockedBy))
+	hidden = append(hidden, b.Blocked...)
+	return append(hidden, b.BlockedBy...)
+}
+
+// hides reports whether content from userID is hidden from this user
+func (b *userBlockList) hides(userID uuid.UUID) bool {
+	for _, id := range b.hiddenUserIDs() {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// loadUserBlockList returns the user's block relationships, using the cache when available
+func loadUserBlockList(ctx context.Context, db *gorm.DB, cacheService *cache.CacheService, userID uuid.UUID) (*userBlockList, error) {
+	var blocks userBlockList
+	if err := cacheService.GetUserBlocks(ctx, userID.String(), &blocks); err == nil {
+		return &blocks, nil
+	}
+
+	if err := db.Model(&models.UserBlock{}).Where("blocker_id = ?", userID).Pluck("blocked_id", &blocks.Blocked).Error; err != nil {
+		return nil, err
+	}
+	if err := db.Model(&models.UserBlock{}).Where("blocked_id = ?", userID).Pluck("blocker_id", &blocks.BlockedBy).Error; err != nil {
+		return nil, err
+	}
+
+	if err := cacheService.SetUserBlocks(ctx, userID.String(), blocks); err != nil {
+		// Log cache error but don't fail the request
+		logger.FromContext(ctx).Error("Failed to cache block list", err, logger.Fields{"user_id": userID.String()})
+	}
+
+	return &blocks, nil
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// createTestUserWithEmail creates an additional test user in the database
+func createTestUserWithEmail(t *testing.T, db *gorm.DB, email string) *models.User {
+	user := &models.User{ID: uuid.New(), Email: email, DisplayName: email}
+	require.NoError(t, db.Create(user).Error)
+	return user
+}
+
+func TestUserHandler_BlockUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := setupBugTestDB(t)
+	handler := NewUserHandler(db, nil)
+	blocker := createTestUser(t, db)
+	target := createTestUserWithEmail(t, db, "harasser@example.com")
+	admin := createTestUserWithEmail(t, db, "admin@example.com")
+	require.NoError(t, db.Model(admin).Update("is_admin", true).Error)
+
+	router := gin.New()
+	router.Use(mockAuthMiddleware(blocker.ID))
+	router.POST("/me/blocks", handler.BlockUser)
+	router.DELETE("/me/blocks/:user_id", handler.UnblockUser)
+
+	block := func(userID string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]string{"user_id": userID})
+		req, _ := http.NewRequest("POST", "/me/blocks", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	errorCode := func(w *httptest.ResponseRecorder) interface{} {
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response["error"].(map[string]interface{})["code"]
+	}
+
+	t.Run("blocks a user", func(t *testing.T) {
+		w := block(target.ID.String())
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var count int64
+		db.Model(&models.UserBlock{}).Where("blocker_id = ? AND blocked_id = ?", blocker.ID, target.ID).Count(&count)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("blocking twice conflicts", func(t *testing.T) {
+		w := block(target.ID.String())
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, "ALREADY_BLOCKED", errorCode(w))
+	})
+
+	t.Run("admins cannot be blocked", func(t *testing.T) {
+		w := block(admin.ID.String())
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "CANNOT_BLOCK_ADMIN", errorCode(w))
+	})
+
+	t.Run("cannot block self", func(t *testing.T) {
+		w := block(blocker.ID.String())
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "CANNOT_BLOCK_SELF", errorCode(w))
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		w := block(uuid.New().String())
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "USER_NOT_FOUND", errorCode(w))
+	})
+
+	t.Run("unblocks a user", func(t *testing.T) {
+		req, _ := http.NewRequest("DELETE", "/me/blocks/"+target.ID.String(), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		req, _ = http.NewRequest("DELETE", "/me/blocks/"+target.ID.String(), nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "BLOCK_NOT_FOUND", errorCode(w))
+	})
+}
+
+func TestBugHandler_UserBlocks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	reporter := createTestUser(t, db)
+	blocked := createTestUserWithEmail(t, db, "blocked@example.com")
+	bystander := createTestUserWithEmail(t, db, "bystander@example.com")
+	app := createTestApplication(t, db)
+	bug := createTestBugReport(t, db, app, reporter)
+
+	otherBug := &models.BugReport{
+		ID:            uuid.New(),
+		Title:         "Bug from blocked user",
+		Description:   "This is a valid bug description with sufficient length",
+		ApplicationID: app.ID,
+		ReporterID:    &blocked.ID,
+	}
+	require.NoError(t, db.Create(otherBug).Error)
+
+	for _, author := range []*models.User{reporter, blocked, bystander} {
+		require.NoError(t, db.Create(&models.Comment{
+			ID:      uuid.New(),
+			BugID:   bug.ID,
+			UserID:  author.ID,
+			Content: "Comment by " + author.Email,
+		}).Error)
+	}
+
+	require.NoError(t, db.Create(&models.UserBlock{BlockerID: reporter.ID, BlockedID: blocked.ID}).Error)
+
+	t.Run("blocked user cannot comment on the reporter's bug", func(t *testing.T) {
+		body, _ := json.Marshal(map[string]string{"content": "Still here, reading along"})
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/bugs/"+bug.ID.String()+"/comments", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "id", Value: bug.ID.String()}}
+		mockAuthMiddleware(blocked.ID)(c)
+
+		handler.CreateComment(c)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "USER_BLOCKED", response["error"].(map[string]interface{})["code"])
+	})
+
+	commentAuthors := func(viewer *models.User) []string {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", fmt.Sprintf("/bugs/%s?include=comments", bug.ID), nil)
+		c.Params = gin.Params{{Key: "id", Value: bug.ID.String()}}
+		if viewer != nil {
+			mockAuthMiddleware(viewer.ID)(c)
+		}
+
+		handler.GetBug(c)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Bug models.BugReport `json:"bug"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+		var authors []string
+		for _, comment := range response.Bug.Comments {
+			authors = append(authors, comment.UserID.String())
+		}
+		return authors
+	}
+
+	t.Run("blocker does not see the blocked user's comments", func(t *testing.T) {
+		authors := commentAuthors(reporter)
+		assert.Len(t, authors, 2)
+		assert.NotContains(t, authors, blocked.ID.String())
+	})
+
+	t.Run("blocked user does not see the blocker's comments", func(t *testing.T) {
+		authors := commentAuthors(blocked)
+		assert.Len(t, authors, 2)
+		assert.NotContains(t, authors, reporter.ID.String())
+	})
+
+	t.Run("other users see all comments", func(t *testing.T) {
+		assert.Len(t, commentAuthors(bystander), 3)
+		assert.Len(t, commentAuthors(nil), 3)
+	})
+
+	listBugIDs := func(viewer *models.User, query string) []uuid.UUID {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/bugs"+query, nil)
+		mockAuthMiddleware(viewer.ID)(c)
+
+		handler.ListBugs(c)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Bugs []models.BugReport `json:"bugs"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+		var ids []uuid.UUID
+		for _, listed := range response.Bugs {
+			ids = append(ids, listed.ID)
+		}
+		return ids
+	}
+
+	t.Run("hide_blocked filters bugs in both directions", func(t *testing.T) {
+		assert.ElementsMatch(t, []uuid.UUID{bug.ID, otherBug.ID}, listBugIDs(reporter, ""))
+		assert.Equal(t, []uuid.UUID{bug.ID}, listBugIDs(reporter, "?hide_blocked=true"))
+		assert.Equal(t, []uuid.UUID{otherBug.ID}, listBugIDs(blocked, "?hide_blocked=true"))
+		assert.Len(t, listBugIDs(bystander, "?hide_blocked=true"), 2)
+	})
+}
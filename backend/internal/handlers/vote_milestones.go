@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"bugrelay-backend/internal/models"
+
+	"gorm.io/datatypes"
+)
+
+// defaultVoteMilestones are the vote counts at which a bug's reporter is notified
+var defaultVoteMilestones = []int{10, 50, 100, 500}
+
+// SetVoteMilestones sets the vote counts at which a bug's reporter is notified
+func (h *BugHandler) SetVoteMilestones(milestones []int) {
+	h.voteMilestones = milestones
+}
+
+// reachedVoteMilestone returns the milestone a vote took a bug's count to, if any
+func reachedVoteMilestone(milestones []int, voteCount int) (int, bool) {
+	for _, milestone := range milestones {
+		if voteCount == milestone {
+			return milestone, true
+		}
+	}
+	return 0, false
+}
+
+// voteMilestoneSuffix ends the body of every vote milestone notification, so an
+// existing notification for a milestone can be found
+func voteMilestoneSuffix(milestone int) string {
+	return fmt.Sprintf("has reached %d votes!", milestone)
+}
+
+// notifyVoteMilestone tells a bug's reporter that it reached a vote milestone, unless
+// they have turned off vote milestone notifications. A bug that drops below a
+// milestone and reaches it again does not notify twice.
+func (h *BugHandler) notifyVoteMilestone(ctx context.Context, bug *models.BugReport, milestone int) error {
+	if bug.ReporterID == nil {
+		return nil
+	}
+
+	preferences, err := loadNotificationPreferences(ctx, h.db, h.cache, *bug.ReporterID)
+	if err != nil {
+		return err
+	}
+	if !preferences.Allows(models.NotificationTypeVoteMilestone) {
+		return nil
+	}
+
+	suffix := voteMilestoneSuffix(milestone)
+
+	var existing int64
+	if err := h.db.Model(&models.Notification{}).
+		Where("resource_id = ? AND type = ? AND body LIKE ?", bug.ID, models.NotificationTypeVoteMilestone, "%"+suffix).
+		Count(&existing).Error; err != nil {
+		return err
+	}
+	if existing > 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"bug_id":    bug.ID,
+		"milestone": milestone,
+	})
+	if err != nil {
+		return err
+	}
+
+	return h.db.Create(&models.Notification{
+		UserID:     *bug.ReporterID,
+		Type:       models.NotificationTypeVoteMilestone,
+		ResourceID: &bug.ID,
+		Body:       fmt.Sprintf("Your bug report '%s' %s", bug.Title, suffix),
+		Payload:    datatypes.JSON(payload),
+	}).Error
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReachedVoteMilestone(t *testing.T) {
+	for _, milestone := range defaultVoteMilestones {
+		reached, ok := reachedVoteMilestone(defaultVoteMilestones, milestone)
+		assert.True(t, ok)
+		assert.Equal(t, milestone, reached)
+	}
+
+	for _, count := range []int{0, 1, 9, 11, 49, 101, 1000} {
+		_, ok := reachedVoteMilestone(defaultVoteMilestones, count)
+		assert.False(t, ok, "count %d", count)
+	}
+}
+
+func TestBugHandler_VoteBug_Milestones(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	reporter := createTestUser(t, db)
+	app := createTestApplication(t, db)
+	bug := createTestBugReport(t, db, app, reporter)
+
+	newVoter := func() uuid.UUID {
+		voter := &models.User{ID: uuid.New(), Email: uuid.NewString() + "@example.com", DisplayName: "Voter"}
+		require.NoError(t, db.Create(voter).Error)
+		return voter.ID
+	}
+	vote := func(bugID, userID uuid.UUID) int {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/bugs/"+bugID.String()+"/vote", nil)
+		c.Params = gin.Params{{Key: "id", Value: bugID.String()}}
+		mockAuthMiddleware(userID)(c)
+
+		handler.VoteBug(c)
+		return w.Code
+	}
+	milestoneNotifications := func(bugID uuid.UUID) []models.Notification {
+		var notifications []models.Notification
+		require.NoError(t, db.Where("resource_id = ? AND type = ?", bugID, models.NotificationTypeVoteMilestone).
+			Order("created_at ASC").Find(&notifications).Error)
+		return notifications
+	}
+
+	t.Run("each milestone notifies the reporter once", func(t *testing.T) {
+		for i, milestone := range defaultVoteMilestones {
+			// Jump to just below the milestone rather than casting every vote
+			require.NoError(t, db.Model(bug).Update("vote_count", milestone-1).Error)
+			require.Equal(t, http.StatusCreated, vote(bug.ID, newVoter()))
+
+			notifications := milestoneNotifications(bug.ID)
+			require.Len(t, notifications, i+1)
+
+			notification := notifications[i]
+			assert.Equal(t, reporter.ID, notification.UserID)
+			assert.Equal(t, fmt.Sprintf("Your bug report 'Test Bug' has reached %d votes!", milestone), notification.Body)
+
+			var payload map[string]interface{}
+			require.NoError(t, json.Unmarshal(notification.Payload, &payload))
+			assert.Equal(t, float64(milestone), payload["milestone"])
+			assert.Equal(t, bug.ID.String(), payload["bug_id"])
+		}
+	})
+
+	t.Run("votes between milestones do not notify", func(t *testing.T) {
+		before := len(milestoneNotifications(bug.ID))
+		require.NoError(t, db.Model(bug).Update("vote_count", 10).Error)
+		require.Equal(t, http.StatusCreated, vote(bug.ID, newVoter()))
+		assert.Len(t, milestoneNotifications(bug.ID), before)
+	})
+
+	t.Run("reaching a milestone again does not notify twice", func(t *testing.T) {
+		other := createTestBugReport(t, db, app, reporter)
+		require.NoError(t, db.Model(other).Update("vote_count", 9).Error)
+
+		voter := newVoter()
+		require.Equal(t, http.StatusCreated, vote(other.ID, voter))
+		require.Len(t, milestoneNotifications(other.ID), 1)
+
+		// Removing the vote drops the bug below the milestone, voting again reaches it
+		require.Equal(t, http.StatusOK, vote(other.ID, voter))
+		require.Equal(t, http.StatusCreated, vote(other.ID, voter))
+
+		var updated models.BugReport
+		require.NoError(t, db.First(&updated, other.ID).Error)
+		assert.Equal(t, 10, updated.VoteCount)
+		assert.Len(t, milestoneNotifications(other.ID), 1)
+	})
+
+	t.Run("reporters who turned off vote milestones are not notified", func(t *testing.T) {
+		optedOut := &models.User{ID: uuid.New(), Email: "opted-out@example.com", DisplayName: "Opted Out"}
+		require.NoError(t, db.Create(optedOut).Error)
+		preferences := models.DefaultNotificationPreferences(optedOut.ID)
+		require.NoError(t, db.Create(&preferences).Error)
+		// Updated separately since Create skips false and writes the column default
+		require.NoError(t, db.Model(&preferences).Update("vote_milestone", false).Error)
+
+		quiet := createTestBugReport(t, db, app, optedOut)
+		require.NoError(t, db.Model(quiet).Update("vote_count", 9).Error)
+		require.Equal(t, http.StatusCreated, vote(quiet.ID, newVoter()))
+		assert.Empty(t, milestoneNotifications(quiet.ID))
+	})
+}
@@ -0,0 +1,21 @@
+package handlers
+
+import "math"
+
+const (
+	// reputationPerVoteWeightStep is the reputation that adds a tenth to a user's vote weight
+	reputationPerVoteWeightStep = 100
+	// maxVoteWeight is the most a single vote can weigh
+	maxVoteWeight = 2.0
+)
+
+// voteWeight returns the weight of a vote cast by a user with the given reputation:
+// 1.0, plus 0.1 per reputationPerVoteWeightStep, up to maxVoteWeight
+func voteWeight(reputation int64) float64 {
+	if reputation < 0 {
+		reputation = 0
+	}
+	// Dividing a count of tenths keeps weights exact to one decimal place
+	tenths := 10 + reputation/reputationPerVoteWeightStep
+	return math.Min(float64(tenths)/10, maxVoteWeight)
+}
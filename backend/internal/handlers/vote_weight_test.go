@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestVoteWeight(t *testing.T) {
+	tests := []struct {
+		reputation int64
+		expected   float64
+	}{
+		{0, 1.0},
+		{99, 1.0},
+		{100, 1.1},
+		{350, 1.3},
+		{999, 1.9},
+		{1000, 2.0},
+		{50000, 2.0},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, voteWeight(tt.reputation), "reputation %d", tt.reputation)
+	}
+}
+
+func TestBugHandler_VoteBug_Weighted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	app := createTestApplication(t, db)
+	newcomer := createTestUser(t, db)
+	powerUser := &models.User{ID: uuid.New(), Email: "power@example.com", DisplayName: "Power User"}
+	require.NoError(t, db.Create(powerUser).Error)
+
+	// The power user's reports have 250 votes between them, worth a weight of 1.2
+	reputationBug := createTestBugReport(t, db, app, powerUser)
+	require.NoError(t, db.Model(reputationBug).Update("vote_count", 250).Error)
+
+	bug := createTestBugReport(t, db, app, newcomer)
+
+	vote := func(user uuid.UUID) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/bugs/"+bug.ID.String()+"/vote", nil)
+		c.Params = gin.Params{{Key: "id", Value: bug.ID.String()}}
+		mockAuthMiddleware(user)(c)
+		handler.VoteBug(c)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w.Code, response
+	}
+
+	counts := func() (int, float64) {
+		var reloaded models.BugReport
+		require.NoError(t, db.First(&reloaded, "id = ?", bug.ID).Error)
+		return reloaded.VoteCount, reloaded.WeightedVoteCount
+	}
+
+	code, response := vote(powerUser.ID)
+	require.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, 1.2, response["vote_weight"])
+
+	var stored models.BugVote
+	require.NoError(t, db.Where("bug_id = ? AND user_id = ?", bug.ID, powerUser.ID).First(&stored).Error)
+	assert.Equal(t, 1.2, stored.VoteWeight)
+
+	voteCount, weighted := counts()
+	assert.Equal(t, 1, voteCount)
+	assert.InDelta(t, 1.2, weighted, 1e-9)
+
+	// New accounts vote with a weight of 1.0
+	code, response = vote(newcomer.ID)
+	require.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, 1.0, response["vote_weight"])
+
+	voteCount, weighted = counts()
+	assert.Equal(t, 2, voteCount)
+	assert.InDelta(t, 2.2, weighted, 1e-9)
+
+	// Removing a vote subtracts the weight it was cast with, even after the voter's
+	// reputation changed
+	require.NoError(t, db.Model(reputationBug).Update("vote_count", gorm.Expr("vote_count + 500")).Error)
+	code, response = vote(powerUser.ID)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, false, response["voted"])
+
+	voteCount, weighted = counts()
+	assert.Equal(t, 1, voteCount)
+	assert.InDelta(t, 1.0, weighted, 1e-9)
+}
+
+func TestBugHandler_ListBugs_PopularByWeightedVotes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler, db := setupBugTestHandler(t)
+	user := createTestUser(t, db)
+	app := createTestApplication(t, db)
+
+	// More votes, but from newer accounts
+	manyVotes := createTestBugReport(t, db, app, user)
+	require.NoError(t, db.Model(manyVotes).Updates(map[string]interface{}{"vote_count": 3, "weighted_vote_count": 3.0}).Error)
+	heavyVotes := createTestBugReport(t, db, app, user)
+	require.NoError(t, db.Model(heavyVotes).Updates(map[string]interface{}{"vote_count": 2, "weighted_vote_count": 3.6}).Error)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/bugs?sort=popular", nil)
+	handler.ListBugs(c)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Bugs []models.BugReport `json:"bugs"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Bugs, 2)
+	assert.Equal(t, heavyVotes.ID, response.Bugs[0].ID)
+	assert.Equal(t, 3.6, response.Bugs[0].WeightedVoteCount)
+	assert.Equal(t, manyVotes.ID, response.Bugs[1].ID)
+}
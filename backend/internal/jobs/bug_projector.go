@@ -0,0 +1,104 @@
+package jobs
+
+import (
+	"context"
+	"sync/atomic"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// bugProjectorQueueSize is the number of projection requests buffered for Run
+const bugProjectorQueueSize = 100
+
+// bugProjectionRequest asks the projector to project, or fully rebuild, a bug
+type bugProjectionRequest struct {
+	bugID   uuid.UUID
+	rebuild bool
+	done    chan bugProjectionResult
+}
+
+// bugProjectionResult is the outcome of a projection request
+type bugProjectionResult struct {
+	bug *models.BugReport
+	err error
+}
+
+// BugProjector applies bug events to the bug report projection. While Run is active,
+// requests are handed to its goroutine over a channel so each process updates
+// projections one at a time; otherwise they run on the caller's goroutine.
+type BugProjector struct {
+	db       *gorm.DB
+	requests chan bugProjectionRequest
+	running  atomic.Bool
+}
+
+// NewBugProjector creates a new bug projector
+func NewBugProjector(db *gorm.DB) *BugProjector {
+	return &BugProjector{
+		db:       db,
+		requests: make(chan bugProjectionRequest, bugProjectorQueueSize),
+	}
+}
+
+// Run consumes projection requests until the context is cancelled
+func (p *BugProjector) Run(ctx context.Context) {
+	p.running.Store(true)
+	defer p.running.Store(false)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case req := <-p.requests:
+			bug, err := p.apply(ctx, req.bugID, req.rebuild)
+			req.done <- bugProjectionResult{bug: bug, err: err}
+		}
+	}
+}
+
+// Project applies the bug's unapplied events and returns the projected bug
+func (p *BugProjector) Project(ctx context.Context, bugID uuid.UUID) (*models.BugReport, error) {
+	return p.submit(ctx, bugID, false)
+}
+
+// Rebuild replays the bug's whole event stream and returns the projected bug
+func (p *BugProjector) Rebuild(ctx context.Context, bugID uuid.UUID) (*models.BugReport, error) {
+	return p.submit(ctx, bugID, true)
+}
+
+// submit hands a request to Run, or applies it directly when Run is not active
+func (p *BugProjector) submit(ctx context.Context, bugID uuid.UUID, rebuild bool) (*models.BugReport, error) {
+	if !p.running.Load() {
+		return p.apply(ctx, bugID, rebuild)
+	}
+
+	req := bugProjectionRequest{
+		bugID:   bugID,
+		rebuild: rebuild,
+		done:    make(chan bugProjectionResult, 1),
+	}
+	select {
+	case p.requests <- req:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	select {
+	case result := <-req.done:
+		return result.bug, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// apply projects or rebuilds a bug
+func (p *BugProjector) apply(ctx context.Context, bugID uuid.UUID, rebuild bool) (*models.BugReport, error) {
+	db := p.db.WithContext(ctx)
+	if rebuild {
+		return models.RebuildBugProjection(db, bugID)
+	}
+	return models.ProjectBug(db, bugID)
+}
@@ -0,0 +1,182 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupBugProjectorTestDB creates an in-memory database with the bug report and bug
+// event tables
+func setupBugProjectorTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	// The models' postgres defaults cannot be migrated on sqlite
+	require.NoError(t, db.Exec(`CREATE TABLE bug_reports (
+		id TEXT PRIMARY KEY,
+		title TEXT NOT NULL,
+		description TEXT NOT NULL,
+		status TEXT DEFAULT 'open',
+		priority TEXT DEFAULT 'medium',
+		application_id TEXT NOT NULL,
+		event_sequence INTEGER DEFAULT 0,
+		created_at DATETIME,
+		updated_at DATETIME,
+		deleted_at DATETIME,
+		resolved_at DATETIME
+	)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE bug_events (
+		id TEXT PRIMARY KEY,
+		bug_id TEXT NOT NULL,
+		sequence INTEGER NOT NULL,
+		event_type TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		actor_id TEXT,
+		created_at DATETIME,
+		UNIQUE (bug_id, sequence)
+	)`).Error)
+
+	return db
+}
+
+func createProjectedBug(t *testing.T, db *gorm.DB) uuid.UUID {
+	bugID := uuid.New()
+	require.NoError(t, db.Exec(
+		`INSERT INTO bug_reports (id, title, description, status, priority, application_id, created_at, updated_at)
+		VALUES (?, 'Crash', 'It crashes', 'open', 'medium', ?, ?, ?)`,
+		bugID, uuid.New(), time.Now(), time.Now(),
+	).Error)
+	return bugID
+}
+
+func appendBugEvent(t *testing.T, db *gorm.DB, bugID uuid.UUID, eventType, from, to string) {
+	_, err := models.AppendBugEvent(db, bugID, eventType, nil, models.BugFieldChange{From: from, To: to})
+	require.NoError(t, err)
+}
+
+func loadProjectedBug(t *testing.T, db *gorm.DB, bugID uuid.UUID) models.BugReport {
+	var bug models.BugReport
+	require.NoError(t, db.First(&bug, "id = ?", bugID).Error)
+	return bug
+}
+
+func TestBugProjector_Project(t *testing.T) {
+	db := setupBugProjectorTestDB(t)
+	bugID := createProjectedBug(t, db)
+	projector := NewBugProjector(db)
+
+	appendBugEvent(t, db, bugID, models.BugEventStatusChanged, models.BugStatusOpen, models.BugStatusReviewing)
+	appendBugEvent(t, db, bugID, models.BugEventPriorityChanged, models.BugPriorityMedium, models.BugPriorityCritical)
+
+	bug, err := projector.Project(context.Background(), bugID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BugStatusReviewing, bug.Status)
+	assert.Equal(t, models.BugPriorityCritical, bug.Priority)
+	assert.Equal(t, int64(2), bug.EventSequence)
+
+	stored := loadProjectedBug(t, db, bugID)
+	assert.Equal(t, models.BugStatusReviewing, stored.Status)
+	assert.Equal(t, models.BugPriorityCritical, stored.Priority)
+	assert.Equal(t, int64(2), stored.EventSequence)
+
+	t.Run("projecting again is idempotent", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			_, err := projector.Project(context.Background(), bugID)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, stored, loadProjectedBug(t, db, bugID))
+	})
+
+	t.Run("only new events are applied", func(t *testing.T) {
+		appendBugEvent(t, db, bugID, models.BugEventStatusChanged, models.BugStatusReviewing, models.BugStatusFixed)
+
+		bug, err := projector.Project(context.Background(), bugID)
+		require.NoError(t, err)
+		assert.Equal(t, models.BugStatusFixed, bug.Status)
+		assert.Equal(t, models.BugPriorityCritical, bug.Priority)
+		assert.NotNil(t, bug.ResolvedAt)
+		assert.Equal(t, int64(3), bug.EventSequence)
+	})
+}
+
+func TestBugProjector_Rebuild(t *testing.T) {
+	db := setupBugProjectorTestDB(t)
+	bugID := createProjectedBug(t, db)
+	projector := NewBugProjector(db)
+
+	appendBugEvent(t, db, bugID, models.BugEventStatusChanged, models.BugStatusOpen, models.BugStatusFixed)
+	appendBugEvent(t, db, bugID, models.BugEventPriorityChanged, models.BugPriorityMedium, models.BugPriorityLow)
+	appendBugEvent(t, db, bugID, models.BugEventStatusChanged, models.BugStatusFixed, models.BugStatusReviewing)
+	_, err := projector.Project(context.Background(), bugID)
+	require.NoError(t, err)
+	expected := loadProjectedBug(t, db, bugID)
+
+	// Corrupt the projection as a manual edit would
+	require.NoError(t, db.Exec(`UPDATE bug_reports SET status = 'wont_fix', priority = 'high', resolved_at = ? WHERE id = ?`, time.Now(), bugID).Error)
+
+	bug, err := projector.Rebuild(context.Background(), bugID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BugStatusReviewing, bug.Status)
+	assert.Equal(t, models.BugPriorityLow, bug.Priority)
+	assert.Nil(t, bug.ResolvedAt)
+	assert.Equal(t, int64(3), bug.EventSequence)
+
+	rebuilt := loadProjectedBug(t, db, bugID)
+	assert.Equal(t, expected.Status, rebuilt.Status)
+	assert.Equal(t, expected.Priority, rebuilt.Priority)
+	assert.Equal(t, expected.ResolvedAt, rebuilt.ResolvedAt)
+
+	t.Run("rebuilding again is idempotent", func(t *testing.T) {
+		_, err := projector.Rebuild(context.Background(), bugID)
+		require.NoError(t, err)
+		assert.Equal(t, rebuilt, loadProjectedBug(t, db, bugID))
+	})
+
+	t.Run("bugs without events keep their state", func(t *testing.T) {
+		otherID := createProjectedBug(t, db)
+		bug, err := projector.Rebuild(context.Background(), otherID)
+		require.NoError(t, err)
+		assert.Equal(t, models.BugStatusOpen, bug.Status)
+		assert.Equal(t, models.BugPriorityMedium, bug.Priority)
+	})
+}
+
+func TestBugProjector_Run(t *testing.T) {
+	db := setupBugProjectorTestDB(t)
+	bugID := createProjectedBug(t, db)
+	projector := NewBugProjector(db)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go projector.Run(ctx)
+	require.Eventually(t, projector.running.Load, time.Second, time.Millisecond)
+
+	appendBugEvent(t, db, bugID, models.BugEventPriorityChanged, models.BugPriorityMedium, models.BugPriorityHigh)
+
+	// Requests are applied by the Run goroutine and the result handed back
+	bug, err := projector.Project(ctx, bugID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BugPriorityHigh, bug.Priority)
+	assert.Equal(t, models.BugPriorityHigh, loadProjectedBug(t, db, bugID).Priority)
+
+	// Once stopped, requests are applied on the caller's goroutine
+	cancel()
+	require.Eventually(t, func() bool { return !projector.running.Load() }, time.Second, time.Millisecond)
+
+	appendBugEvent(t, db, bugID, models.BugEventPriorityChanged, models.BugPriorityHigh, models.BugPriorityLow)
+	bug, err = projector.Project(context.Background(), bugID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BugPriorityLow, bug.Priority)
+}
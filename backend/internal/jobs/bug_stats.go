@@ -0,0 +1,21 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"bugrelay-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// NewRefreshBugStatsJob creates the daily job that refreshes the bug_stats_by_company view
+func NewRefreshBugStatsJob(db *gorm.DB) Job {
+	return Job{
+		Name:     "refresh_bug_stats",
+		Interval: 24 * time.Hour,
+		Run: func(ctx context.Context) error {
+			return models.RefreshBugStatsByCompany(db.WithContext(ctx))
+		},
+	}
+}
@@ -0,0 +1,23 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"bugrelay-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// NewCleanupExpiredVerificationsJob creates the daily job that clears expired company
+// verification tokens
+func NewCleanupExpiredVerificationsJob(db *gorm.DB) Job {
+	return Job{
+		Name:     "cleanup_expired_verifications",
+		Interval: 24 * time.Hour,
+		Run: func(ctx context.Context) error {
+			_, err := models.CleanupExpiredVerifications(db.WithContext(ctx), time.Now())
+			return err
+		},
+	}
+}
@@ -0,0 +1,92 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupCompanyTestDB creates an in-memory database with the companies table
+func setupCompanyTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	// The model's postgres defaults cannot be migrated on sqlite
+	require.NoError(t, db.Exec(`CREATE TABLE companies (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		domain TEXT NOT NULL UNIQUE,
+		is_verified BOOLEAN DEFAULT false,
+		verification_token TEXT,
+		verification_email TEXT,
+		verification_token_expires_at DATETIME,
+		verified_at DATETIME,
+		admin_verified BOOLEAN DEFAULT false,
+		admin_verified_by TEXT,
+		pending_domain TEXT,
+		pending_domain_email TEXT,
+		pending_domain_verification_token TEXT,
+		pending_domain_verification_expires_at DATETIME,
+		logo_url TEXT,
+		created_at DATETIME,
+		updated_at DATETIME
+	)`).Error)
+
+	return db
+}
+
+func TestCleanupExpiredVerificationsJob(t *testing.T) {
+	db := setupCompanyTestDB(t)
+
+	createCompany := func(domain string, verified bool, expiresAt time.Time) models.Company {
+		token := "token-" + domain
+		email := "admin@" + domain
+		company := models.Company{
+			ID:                         uuid.New(),
+			Name:                       domain,
+			Domain:                     domain,
+			IsVerified:                 verified,
+			VerificationToken:          &token,
+			VerificationEmail:          &email,
+			VerificationTokenExpiresAt: &expiresAt,
+		}
+		require.NoError(t, db.Create(&company).Error)
+		return company
+	}
+
+	now := time.Now()
+	expired := createCompany("expired.com", false, now.Add(-time.Hour))
+	pending := createCompany("pending.com", false, now.Add(time.Hour))
+	verified := createCompany("verified.com", true, now.Add(-time.Hour))
+
+	require.NoError(t, NewCleanupExpiredVerificationsJob(db).Run(context.Background()))
+
+	reload := func(company models.Company) models.Company {
+		var reloaded models.Company
+		require.NoError(t, db.First(&reloaded, "id = ?", company.ID).Error)
+		return reloaded
+	}
+
+	cleaned := reload(expired)
+	assert.Nil(t, cleaned.VerificationToken)
+	assert.Nil(t, cleaned.VerificationEmail)
+	assert.Nil(t, cleaned.VerificationTokenExpiresAt)
+
+	assert.NotNil(t, reload(pending).VerificationToken)
+
+	// Verified companies are never touched
+	untouched := reload(verified)
+	assert.NotNil(t, untouched.VerificationToken)
+	assert.NotNil(t, untouched.VerificationEmail)
+}
@@ -0,0 +1,242 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"bugrelay-backend/internal/config"
+	"bugrelay-backend/internal/email"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// outboxBatchSize is the number of pending events delivered per poll
+	outboxBatchSize = 50
+	// outboxMaxRetries is the number of retries before an event is dead-lettered
+	outboxMaxRetries = 5
+)
+
+// OutboxHandler delivers a single outbox event
+type OutboxHandler func(ctx context.Context, event models.OutboxEvent) error
+
+// OutboxProcessor delivers pending outbox events to the handler registered for their type
+type OutboxProcessor struct {
+	db           *gorm.DB
+	handlers     map[string]OutboxHandler
+	onDeadLetter func(ctx context.Context, event models.OutboxEvent)
+}
+
+// NewOutboxProcessor creates a new outbox processor
+func NewOutboxProcessor(db *gorm.DB) *OutboxProcessor {
+	return &OutboxProcessor{
+		db:       db,
+		handlers: make(map[string]OutboxHandler),
+	}
+}
+
+// Handle registers the handler that delivers events of the given type
+func (p *OutboxProcessor) Handle(eventType string, handler OutboxHandler) {
+	p.handlers[eventType] = handler
+}
+
+// OnDeadLetter sets the callback invoked when an event exceeds its retries
+func (p *OutboxProcessor) OnDeadLetter(fn func(ctx context.Context, event models.OutboxEvent)) {
+	p.onDeadLetter = fn
+}
+
+// ProcessPending delivers the oldest pending events. Delivered events are marked
+// processed; failed events are retried on later polls until they are dead-lettered.
+func (p *OutboxProcessor) ProcessPending(ctx context.Context) error {
+	var events []models.OutboxEvent
+	if err := p.db.WithContext(ctx).
+		Where("status = ?", models.OutboxStatusPending).
+		Order("created_at ASC").
+		Limit(outboxBatchSize).
+		Find(&events).Error; err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		if err := p.deliver(ctx, event); err != nil {
+			if err := p.recordFailure(ctx, event, err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		now := time.Now()
+		if err := p.db.WithContext(ctx).Model(&event).Updates(map[string]interface{}{
+			"status":       models.OutboxStatusProcessed,
+			"processed_at": now,
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deliver passes an event to the handler registered for its type
+func (p *OutboxProcessor) deliver(ctx context.Context, event models.OutboxEvent) error {
+	handler, exists := p.handlers[event.EventType]
+	if !exists {
+		return fmt.Errorf("no handler registered for event type %q", event.EventType)
+	}
+	return handler(ctx, event)
+}
+
+// retryable reports whether a failed delivery should be retried. Deliveries that
+// ran out of time, such as context.DeadlineExceeded from the client timeout, are
+// retried. A handler that timed out serving the event (http.ErrHandlerTimeout) would
+// time out again, so the event is not retried.
+func retryable(err error) bool {
+	return !errors.Is(err, http.ErrHandlerTimeout)
+}
+
+// recordFailure increments an event's retry count, dead-lettering it once the
+// retries are exhausted or the failure is not retryable
+func (p *OutboxProcessor) recordFailure(ctx context.Context, event models.OutboxEvent, deliveryErr error) error {
+	event.RetryCount++
+	lastError := deliveryErr.Error()
+	updates := map[string]interface{}{
+		"retry_count": event.RetryCount,
+		"last_error":  lastError,
+	}
+
+	deadLettered := event.RetryCount > outboxMaxRetries || !retryable(deliveryErr)
+	if deadLettered {
+		now := time.Now()
+		event.Status = models.OutboxStatusDeadLettered
+		event.DeadLetteredAt = &now
+		updates["status"] = event.Status
+		updates["dead_lettered_at"] = now
+	}
+
+	if err := p.db.WithContext(ctx).Model(&event).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	if deadLettered {
+		event.LastError = &lastError
+		logger.Error("Outbox event dead-lettered", deliveryErr, logger.Fields{
+			"event_id":    event.ID.String(),
+			"event_type":  event.EventType,
+			"retry_count": event.RetryCount,
+		})
+		if p.onDeadLetter != nil {
+			p.onDeadLetter(ctx, event)
+		}
+	}
+
+	return nil
+}
+
+// NewOutboxJob creates the job that polls for pending outbox events
+func NewOutboxJob(processor *OutboxProcessor, interval time.Duration) Job {
+	return Job{
+		Name:     "outbox_processor",
+		Interval: interval,
+		Run:      processor.ProcessPending,
+	}
+}
+
+// NewWebhookHandler posts event payloads as JSON to url. Events are acknowledged
+// without delivery when no url is configured.
+func NewWebhookHandler(client *http.Client, url string) OutboxHandler {
+	return func(ctx context.Context, event models.OutboxEvent) error {
+		if url == "" {
+			return nil
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(event.Payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-BugRelay-Event", event.EventType)
+		req.Header.Set("X-BugRelay-Delivery", event.ID.String())
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// NewEmailHandler sends email events over SMTP
+func NewEmailHandler(cfg config.SMTPConfig) OutboxHandler {
+	return func(ctx context.Context, event models.OutboxEvent) error {
+		var payload models.EmailPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("invalid email payload: %v", err)
+		}
+		return sendEmail(cfg, payload)
+	}
+}
+
+// NewDeadLetterNotifier emails adminEmail about dead-lettered events. The email is
+// sent directly rather than through the outbox so a failing mail server cannot
+// produce further dead letters.
+func NewDeadLetterNotifier(cfg config.SMTPConfig, adminEmail string) func(ctx context.Context, event models.OutboxEvent) {
+	return func(ctx context.Context, event models.OutboxEvent) {
+		if adminEmail == "" {
+			return
+		}
+
+		lastError := ""
+		if event.LastError != nil {
+			lastError = *event.LastError
+		}
+
+		payload := models.EmailPayload{
+			To:      []string{adminEmail},
+			Subject: fmt.Sprintf("[BugRelay] Outbox event %s dead-lettered", event.EventType),
+			Body: fmt.Sprintf("Outbox event %s (%s) failed %d times and will not be retried.\n\nLast error: %s\n",
+				event.ID, event.EventType, event.RetryCount, lastError),
+		}
+		if err := sendEmail(cfg, payload); err != nil {
+			logger.Error("Failed to send dead letter notification", err, logger.Fields{
+				"event_id": event.ID.String(),
+			})
+		}
+	}
+}
+
+// sendEmail sends a plain text email using the SMTP configuration
+func sendEmail(cfg config.SMTPConfig, payload models.EmailPayload) error {
+	if cfg.Host == "" {
+		return fmt.Errorf("SMTP is not configured")
+	}
+	if len(payload.To) == 0 {
+		return fmt.Errorf("email has no recipients")
+	}
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	message := "From: " + cfg.From + "\r\n" +
+		"To: " + strings.Join(payload.To, ", ") + "\r\n" +
+		"Subject: " + email.EncodeSubject(payload.Subject) + "\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" + payload.Body
+
+	return smtp.SendMail(cfg.Host+":"+cfg.Port, auth, cfg.From, payload.To, []byte(message))
+}
@@ -0,0 +1,176 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupOutboxTestDB creates an in-memory database with the outbox table
+func setupOutboxTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	// The model's postgres defaults cannot be migrated on sqlite
+	require.NoError(t, db.Exec(`CREATE TABLE outbox_events (
+		id TEXT PRIMARY KEY,
+		event_type TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		status TEXT DEFAULT 'pending',
+		retry_count INTEGER DEFAULT 0,
+		last_error TEXT,
+		created_at DATETIME,
+		processed_at DATETIME,
+		dead_lettered_at DATETIME
+	)`).Error)
+
+	return db
+}
+
+func loadOutboxEvent(t *testing.T, db *gorm.DB) models.OutboxEvent {
+	var event models.OutboxEvent
+	require.NoError(t, db.First(&event).Error)
+	return event
+}
+
+func TestOutboxProcessor_DeliversPendingEvents(t *testing.T) {
+	db := setupOutboxTestDB(t)
+	require.NoError(t, models.EnqueueOutboxEvent(db, models.OutboxEventBugCreated, map[string]string{"title": "Crash"}))
+
+	var delivered []models.OutboxEvent
+	processor := NewOutboxProcessor(db)
+	processor.Handle(models.OutboxEventBugCreated, func(ctx context.Context, event models.OutboxEvent) error {
+		delivered = append(delivered, event)
+		return nil
+	})
+
+	require.NoError(t, processor.ProcessPending(context.Background()))
+	require.Len(t, delivered, 1)
+	assert.JSONEq(t, `{"title":"Crash"}`, string(delivered[0].Payload))
+
+	event := loadOutboxEvent(t, db)
+	assert.Equal(t, models.OutboxStatusProcessed, event.Status)
+	assert.NotNil(t, event.ProcessedAt)
+
+	// Processed events are not delivered again
+	require.NoError(t, processor.ProcessPending(context.Background()))
+	assert.Len(t, delivered, 1)
+}
+
+func TestOutboxProcessor_RetriesFailedEvents(t *testing.T) {
+	db := setupOutboxTestDB(t)
+	require.NoError(t, models.EnqueueOutboxEvent(db, models.OutboxEventBugCreated, map[string]string{}))
+
+	processor := NewOutboxProcessor(db)
+	processor.Handle(models.OutboxEventBugCreated, func(ctx context.Context, event models.OutboxEvent) error {
+		return errors.New("connection refused")
+	})
+
+	require.NoError(t, processor.ProcessPending(context.Background()))
+
+	event := loadOutboxEvent(t, db)
+	assert.Equal(t, models.OutboxStatusPending, event.Status)
+	assert.Equal(t, 1, event.RetryCount)
+	require.NotNil(t, event.LastError)
+	assert.Equal(t, "connection refused", *event.LastError)
+}
+
+func TestOutboxProcessor_DeadLettersAfterMaxRetries(t *testing.T) {
+	db := setupOutboxTestDB(t)
+	require.NoError(t, models.EnqueueOutboxEvent(db, "unknown.event", map[string]string{}))
+
+	var deadLettered []models.OutboxEvent
+	processor := NewOutboxProcessor(db)
+	processor.OnDeadLetter(func(ctx context.Context, event models.OutboxEvent) {
+		deadLettered = append(deadLettered, event)
+	})
+
+	for i := 0; i <= outboxMaxRetries; i++ {
+		require.NoError(t, processor.ProcessPending(context.Background()))
+	}
+
+	event := loadOutboxEvent(t, db)
+	assert.Equal(t, models.OutboxStatusDeadLettered, event.Status)
+	assert.Equal(t, outboxMaxRetries+1, event.RetryCount)
+	assert.NotNil(t, event.DeadLetteredAt)
+	require.Len(t, deadLettered, 1)
+	assert.Equal(t, event.ID, deadLettered[0].ID)
+
+	// Dead-lettered events are no longer picked up
+	require.NoError(t, processor.ProcessPending(context.Background()))
+	assert.Len(t, deadLettered, 1)
+}
+
+func TestWebhookHandler_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-time.After(5 * time.Second):
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer webhook.Close()
+	defer close(release)
+
+	db := setupOutboxTestDB(t)
+	require.NoError(t, models.EnqueueOutboxEvent(db, models.OutboxEventBugCreated, map[string]string{}))
+
+	processor := NewOutboxProcessor(db)
+	processor.Handle(models.OutboxEventBugCreated, NewWebhookHandler(&http.Client{Timeout: 50 * time.Millisecond}, webhook.URL))
+
+	// The slow receiver is abandoned after the client timeout
+	event := loadOutboxEvent(t, db)
+	start := time.Now()
+	err := processor.deliver(context.Background(), event)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	// and the event is retried on a later poll
+	require.NoError(t, processor.ProcessPending(context.Background()))
+	event = loadOutboxEvent(t, db)
+	assert.Equal(t, models.OutboxStatusPending, event.Status)
+	assert.Equal(t, 1, event.RetryCount)
+	require.NotNil(t, event.LastError)
+	assert.Contains(t, *event.LastError, "Client.Timeout exceeded")
+}
+
+func TestOutboxProcessor_HandlerTimeoutIsNotRetried(t *testing.T) {
+	db := setupOutboxTestDB(t)
+	require.NoError(t, models.EnqueueOutboxEvent(db, models.OutboxEventBugCreated, map[string]string{}))
+
+	var deadLettered []models.OutboxEvent
+	processor := NewOutboxProcessor(db)
+	processor.Handle(models.OutboxEventBugCreated, func(ctx context.Context, event models.OutboxEvent) error {
+		return fmt.Errorf("delivering event: %w", http.ErrHandlerTimeout)
+	})
+	processor.OnDeadLetter(func(ctx context.Context, event models.OutboxEvent) {
+		deadLettered = append(deadLettered, event)
+	})
+
+	require.NoError(t, processor.ProcessPending(context.Background()))
+
+	event := loadOutboxEvent(t, db)
+	assert.Equal(t, models.OutboxStatusDeadLettered, event.Status)
+	assert.Equal(t, 1, event.RetryCount)
+	require.Len(t, deadLettered, 1)
+
+	assert.True(t, retryable(context.DeadlineExceeded))
+	assert.True(t, retryable(errors.New("connection refused")))
+	assert.False(t, retryable(http.ErrHandlerTimeout))
+}
@@ -0,0 +1,192 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"bugrelay-backend/internal/config"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// PriorityEscalationInterval is how often bugs gathering votes quickly are escalated
+const PriorityEscalationInterval = time.Hour
+
+// BugVoteCounter counts the votes bugs gained recently and clears the cached copies
+// of bugs whose priority changed. cache.CacheService implements it.
+type BugVoteCounter interface {
+	CountRecentBugVotes(ctx context.Context, bugID string, now time.Time) (int64, error)
+	InvalidateBug(ctx context.Context, bugID string) error
+}
+
+// priorityRanks orders the bug priorities from lowest to highest
+var priorityRanks = map[string]int{
+	models.BugPriorityLow:      1,
+	models.BugPriorityMedium:   2,
+	models.BugPriorityHigh:     3,
+	models.BugPriorityCritical: 4,
+}
+
+// BugEscalation is a bug whose priority was raised because of the votes it gained
+type BugEscalation struct {
+	BugID    uuid.UUID
+	From     string
+	To       string
+	Votes24h int64
+}
+
+// NewPriorityEscalationJob creates the job that raises the priority of unresolved bugs
+// gaining more votes in 24 hours than the configured thresholds
+func NewPriorityEscalationJob(db *gorm.DB, counter BugVoteCounter, projector *BugProjector, cfg config.EscalationConfig) Job {
+	return Job{
+		Name:     "escalate_bug_priorities",
+		Interval: PriorityEscalationInterval,
+		Run: func(ctx context.Context) error {
+			_, err := EscalateBugPriorities(ctx, db, counter, projector, cfg, time.Now())
+			return err
+		},
+	}
+}
+
+// EscalateBugPriorities raises unresolved bugs with more than HighVotes24h votes in
+// the last 24 hours to high priority, and those with more than CriticalVotes24h to
+// critical. Each escalation is recorded as a priority change by the system user,
+// written to the audit log and announced to the members of the assigned company.
+func EscalateBugPriorities(ctx context.Context, db *gorm.DB, counter BugVoteCounter, projector *BugProjector, cfg config.EscalationConfig, now time.Time) ([]BugEscalation, error) {
+	minVotes := cfg.HighVotes24h
+	if minVotes <= 0 || (cfg.CriticalVotes24h > 0 && cfg.CriticalVotes24h < minVotes) {
+		minVotes = cfg.CriticalVotes24h
+	}
+	if minVotes <= 0 {
+		return nil, nil
+	}
+
+	// A bug cannot have gained more votes in a day than it has in total, so only bugs
+	// with enough votes overall need their recent votes counted
+	var bugs []models.BugReport
+	if err := db.WithContext(ctx).
+		Where("is_spam = ? AND status IN ? AND priority <> ? AND vote_count > ?",
+			false, []string{models.BugStatusOpen, models.BugStatusReviewing}, models.BugPriorityCritical, minVotes).
+		Find(&bugs).Error; err != nil {
+		return nil, err
+	}
+
+	var escalations []BugEscalation
+	for i := range bugs {
+		bug := &bugs[i]
+		votes, err := counter.CountRecentBugVotes(ctx, bug.ID.String(), now)
+		if err != nil {
+			return escalations, err
+		}
+
+		target, ok := escalationTarget(bug.Priority, votes, cfg)
+		if !ok {
+			continue
+		}
+
+		escalation := BugEscalation{BugID: bug.ID, From: bug.Priority, To: target, Votes24h: votes}
+		if err := escalateBug(ctx, db, bug, escalation); err != nil {
+			return escalations, err
+		}
+		escalations = append(escalations, escalation)
+
+		if _, err := projector.Project(ctx, bug.ID); err != nil {
+			return escalations, err
+		}
+		if err := counter.InvalidateBug(ctx, bug.ID.String()); err != nil {
+			// The cached bug expires on its own
+			logger.FromContext(ctx).Error("Failed to invalidate bug cache", err, logger.Fields{"bug_id": bug.ID.String()})
+		}
+
+		logger.FromContext(ctx).Info("Escalated bug priority", logger.Fields{
+			"bug_id":    bug.ID.String(),
+			"from":      escalation.From,
+			"to":        escalation.To,
+			"votes_24h": votes,
+		})
+	}
+
+	return escalations, nil
+}
+
+// escalationTarget returns the priority a bug with the given number of votes in the
+// last 24 hours is raised to, if it is below it
+func escalationTarget(priority string, votes int64, cfg config.EscalationConfig) (string, bool) {
+	target := ""
+	switch {
+	case cfg.CriticalVotes24h > 0 && votes > int64(cfg.CriticalVotes24h):
+		target = models.BugPriorityCritical
+	case cfg.HighVotes24h > 0 && votes > int64(cfg.HighVotes24h):
+		target = models.BugPriorityHigh
+	default:
+		return "", false
+	}
+
+	if priorityRanks[priority] >= priorityRanks[target] {
+		return "", false
+	}
+	return target, true
+}
+
+// escalateBug appends the priority change to the bug's events, notifies the members
+// of the assigned company and writes the audit entry in one transaction
+func escalateBug(ctx context.Context, db *gorm.DB, bug *models.BugReport, escalation BugEscalation) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"bug_id":    bug.ID,
+		"from":      escalation.From,
+		"to":        escalation.To,
+		"votes_24h": escalation.Votes24h,
+	})
+	if err != nil {
+		return err
+	}
+
+	details := fmt.Sprintf("Bug priority automatically escalated from %s to %s after %d votes in 24 hours",
+		escalation.From, escalation.To, escalation.Votes24h)
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := models.AppendBugEvent(tx, bug.ID, models.BugEventPriorityChanged, &models.SystemUserID, models.BugFieldChange{
+			From: escalation.From,
+			To:   escalation.To,
+		}); err != nil {
+			return err
+		}
+
+		if bug.AssignedCompanyID != nil {
+			var memberIDs []uuid.UUID
+			if err := tx.Model(&models.CompanyMember{}).
+				Where("company_id = ?", *bug.AssignedCompanyID).
+				Pluck("user_id", &memberIDs).Error; err != nil {
+				return err
+			}
+
+			body := fmt.Sprintf("The bug report '%s' was escalated to %s priority after gaining %d votes in 24 hours",
+				bug.Title, escalation.To, escalation.Votes24h)
+			for _, memberID := range memberIDs {
+				if err := tx.Create(&models.Notification{
+					UserID:     memberID,
+					Type:       models.NotificationTypeBugAutoEscalated,
+					ResourceID: &bug.ID,
+					Body:       body,
+					Payload:    datatypes.JSON(payload),
+				}).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		return tx.Create(&models.AuditLog{
+			Action:     models.AuditActionBugAutoEscalate,
+			Resource:   models.AuditResourceBug,
+			ResourceID: &bug.ID,
+			Details:    details,
+			UserID:     models.SystemUserID,
+		}).Error
+	})
+}
@@ -0,0 +1,246 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/config"
+	"bugrelay-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeVoteCounter keeps the time of each vote cast on a bug and counts those in the
+// 24 hours before now
+type fakeVoteCounter struct {
+	votes       map[string][]time.Time
+	invalidated []string
+}
+
+func (f *fakeVoteCounter) vote(bugID uuid.UUID, at time.Time, count int) {
+	for i := 0; i < count; i++ {
+		f.votes[bugID.String()] = append(f.votes[bugID.String()], at)
+	}
+}
+
+func (f *fakeVoteCounter) CountRecentBugVotes(ctx context.Context, bugID string, now time.Time) (int64, error) {
+	var count int64
+	for _, at := range f.votes[bugID] {
+		if !at.After(now) && now.Sub(at) < 24*time.Hour {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (f *fakeVoteCounter) InvalidateBug(ctx context.Context, bugID string) error {
+	f.invalidated = append(f.invalidated, bugID)
+	return nil
+}
+
+// setupPriorityEscalationTestDB creates an in-memory database with the tables an
+// escalation writes to
+func setupPriorityEscalationTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	// The models' postgres defaults cannot be migrated on sqlite
+	for _, schema := range []string{
+		`CREATE TABLE bug_reports (
+			id TEXT PRIMARY KEY,
+			title TEXT NOT NULL,
+			description TEXT NOT NULL,
+			status TEXT DEFAULT 'open',
+			priority TEXT DEFAULT 'medium',
+			application_id TEXT NOT NULL,
+			assigned_company_id TEXT,
+			is_spam BOOLEAN DEFAULT false,
+			vote_count INTEGER DEFAULT 0,
+			event_sequence INTEGER DEFAULT 0,
+			created_at DATETIME,
+			updated_at DATETIME,
+			deleted_at DATETIME,
+			resolved_at DATETIME
+		)`,
+		`CREATE TABLE bug_events (
+			id TEXT PRIMARY KEY,
+			bug_id TEXT NOT NULL,
+			sequence INTEGER NOT NULL,
+			event_type TEXT NOT NULL,
+			payload TEXT NOT NULL,
+			actor_id TEXT,
+			created_at DATETIME,
+			UNIQUE (bug_id, sequence)
+		)`,
+		`CREATE TABLE company_members (id TEXT PRIMARY KEY, company_id TEXT NOT NULL, user_id TEXT NOT NULL, role TEXT, added_at DATETIME)`,
+		`CREATE TABLE notifications (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			type TEXT NOT NULL,
+			resource_id TEXT,
+			body TEXT NOT NULL,
+			payload TEXT,
+			read_at DATETIME,
+			created_at DATETIME
+		)`,
+		`CREATE TABLE audit_logs (
+			id TEXT PRIMARY KEY,
+			action TEXT NOT NULL,
+			resource TEXT NOT NULL,
+			resource_id TEXT,
+			details TEXT,
+			before_state TEXT,
+			after_state TEXT,
+			user_id TEXT NOT NULL,
+			ip_address TEXT,
+			user_agent TEXT,
+			created_at DATETIME
+		)`,
+	} {
+		require.NoError(t, db.Exec(schema).Error)
+	}
+
+	return db
+}
+
+func createEscalationBug(t *testing.T, db *gorm.DB, status, priority string, voteCount int, companyID *uuid.UUID) uuid.UUID {
+	bugID := uuid.New()
+	require.NoError(t, db.Exec(
+		`INSERT INTO bug_reports (id, title, description, status, priority, application_id, assigned_company_id, vote_count, created_at, updated_at)
+		VALUES (?, 'Checkout fails', 'Payment page errors', ?, ?, ?, ?, ?, ?, ?)`,
+		bugID, status, priority, uuid.New(), companyID, voteCount, time.Now(), time.Now(),
+	).Error)
+	return bugID
+}
+
+func TestEscalateBugPriorities(t *testing.T) {
+	ctx := context.Background()
+	db := setupPriorityEscalationTestDB(t)
+	projector := NewBugProjector(db)
+	counter := &fakeVoteCounter{votes: make(map[string][]time.Time)}
+	cfg := config.EscalationConfig{HighVotes24h: 50, CriticalVotes24h: 200}
+
+	companyID := uuid.New()
+	memberIDs := []uuid.UUID{uuid.New(), uuid.New()}
+	for _, memberID := range memberIDs {
+		require.NoError(t, db.Exec(`INSERT INTO company_members (id, company_id, user_id) VALUES (?, ?, ?)`,
+			uuid.New(), companyID, memberID).Error)
+	}
+
+	start := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	// Gains 40 votes an hour
+	surging := createEscalationBug(t, db, models.BugStatusOpen, models.BugPriorityMedium, 0, &companyID)
+	// Gains 60 votes, spread over three days
+	steady := createEscalationBug(t, db, models.BugStatusOpen, models.BugPriorityLow, 60, &companyID)
+	// Resolved bugs are never escalated
+	fixed := createEscalationBug(t, db, models.BugStatusFixed, models.BugPriorityLow, 300, nil)
+	for day := 0; day < 3; day++ {
+		counter.vote(steady, start.Add(time.Duration(day-2)*24*time.Hour), 20)
+	}
+	counter.vote(fixed, start, 300)
+
+	run := func(hour int) []BugEscalation {
+		now := start.Add(time.Duration(hour) * time.Hour)
+		counter.vote(surging, now.Add(-time.Minute), 40)
+		require.NoError(t, db.Exec(`UPDATE bug_reports SET vote_count = ? WHERE id = ?`,
+			len(counter.votes[surging.String()]), surging).Error)
+
+		escalations, err := EscalateBugPriorities(ctx, db, counter, projector, cfg, now)
+		require.NoError(t, err)
+		return escalations
+	}
+
+	// 40 votes: not yet
+	assert.Empty(t, run(0))
+
+	// 80 votes: medium to high
+	escalations := run(1)
+	require.Len(t, escalations, 1)
+	assert.Equal(t, BugEscalation{BugID: surging, From: models.BugPriorityMedium, To: models.BugPriorityHigh, Votes24h: 80}, escalations[0])
+	assert.Equal(t, models.BugPriorityHigh, loadProjectedBug(t, db, surging).Priority)
+	assert.Equal(t, []string{surging.String()}, counter.invalidated)
+
+	// 120 to 200 votes: already high
+	for hour := 2; hour <= 4; hour++ {
+		assert.Empty(t, run(hour), "hour %d", hour)
+	}
+
+	// 240 votes: high to critical
+	escalations = run(5)
+	require.Len(t, escalations, 1)
+	assert.Equal(t, models.BugPriorityCritical, escalations[0].To)
+	assert.Equal(t, models.BugPriorityCritical, loadProjectedBug(t, db, surging).Priority)
+
+	// Critical bugs go no higher
+	assert.Empty(t, run(6))
+
+	assert.Equal(t, models.BugPriorityLow, loadProjectedBug(t, db, steady).Priority)
+	assert.Equal(t, models.BugPriorityLow, loadProjectedBug(t, db, fixed).Priority)
+
+	// Each escalation is a priority change by the system user
+	var events []models.BugEvent
+	require.NoError(t, db.Where("bug_id = ?", surging).Order("sequence").Find(&events).Error)
+	require.Len(t, events, 2)
+	for _, event := range events {
+		assert.Equal(t, models.BugEventPriorityChanged, event.EventType)
+		require.NotNil(t, event.ActorID)
+		assert.Equal(t, models.SystemUserID, *event.ActorID)
+	}
+
+	var audits []models.AuditLog
+	require.NoError(t, db.Order("created_at").Find(&audits).Error)
+	require.Len(t, audits, 2)
+	assert.Equal(t, models.AuditActionBugAutoEscalate, audits[0].Action)
+	assert.Equal(t, models.SystemUserID, audits[0].UserID)
+	assert.Equal(t, surging, *audits[0].ResourceID)
+	assert.Contains(t, audits[0].Details, "from medium to high after 80 votes")
+
+	// Every member of the assigned company hears of both escalations
+	var notifications []models.Notification
+	require.NoError(t, db.Find(&notifications).Error)
+	assert.Len(t, notifications, 2*len(memberIDs))
+	for _, notification := range notifications {
+		assert.Equal(t, models.NotificationTypeBugAutoEscalated, notification.Type)
+		assert.Equal(t, surging, *notification.ResourceID)
+		assert.Contains(t, memberIDs, notification.UserID)
+	}
+}
+
+func TestEscalationTarget(t *testing.T) {
+	cfg := config.EscalationConfig{HighVotes24h: 50, CriticalVotes24h: 200}
+
+	tests := []struct {
+		priority string
+		votes    int64
+		target   string
+	}{
+		{models.BugPriorityLow, 50, ""},
+		{models.BugPriorityLow, 51, models.BugPriorityHigh},
+		{models.BugPriorityMedium, 200, models.BugPriorityHigh},
+		{models.BugPriorityMedium, 201, models.BugPriorityCritical},
+		{models.BugPriorityHigh, 120, ""},
+		{models.BugPriorityHigh, 201, models.BugPriorityCritical},
+		{models.BugPriorityCritical, 500, ""},
+	}
+	for _, tt := range tests {
+		target, ok := escalationTarget(tt.priority, tt.votes, cfg)
+		assert.Equal(t, tt.target != "", ok, "%s with %d votes", tt.priority, tt.votes)
+		assert.Equal(t, tt.target, target, "%s with %d votes", tt.priority, tt.votes)
+	}
+
+	// A zero threshold disables its level
+	target, ok := escalationTarget(models.BugPriorityLow, 500, config.EscalationConfig{HighVotes24h: 50})
+	assert.True(t, ok)
+	assert.Equal(t, models.BugPriorityHigh, target)
+	_, ok = escalationTarget(models.BugPriorityLow, 500, config.EscalationConfig{})
+	assert.False(t, ok)
+}
@@ -0,0 +1,67 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"bugrelay-backend/internal/logger"
+)
+
+// DefaultQueueSize is the number of tasks a queue buffers by default
+const DefaultQueueSize = 100
+
+// Task is a unit of work run by a Queue
+type Task struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Queue runs tasks one at a time on a single background goroutine, so work that
+// does not need to finish before a response is sent stays off the request path.
+// Queues are bounded: tasks enqueued while the queue is full are dropped.
+type Queue struct {
+	name  string
+	tasks chan Task
+}
+
+// NewQueue creates a queue that buffers up to size tasks
+func NewQueue(name string, size int) *Queue {
+	return &Queue{
+		name:  name,
+		tasks: make(chan Task, size),
+	}
+}
+
+// Enqueue adds a task to the queue without blocking. It reports whether the task
+// was accepted.
+func (q *Queue) Enqueue(task Task) bool {
+	select {
+	case q.tasks <- task:
+		return true
+	default:
+		logger.Warn("Job queue full, dropping task", logger.Fields{"queue": q.name, "task": task.Name})
+		return false
+	}
+}
+
+// Len returns the number of tasks waiting to run
+func (q *Queue) Len() int {
+	return len(q.tasks)
+}
+
+// Run consumes tasks until the context is cancelled
+func (q *Queue) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case task := <-q.tasks:
+			start := time.Now()
+			if err := task.Run(ctx); err != nil {
+				logger.Error("Queued job failed", err, logger.Fields{"queue": q.name, "task": task.Name})
+				continue
+			}
+			logger.Performance("job_"+task.Name, time.Since(start), nil)
+		}
+	}
+}
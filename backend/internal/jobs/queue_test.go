@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueue_RunsEnqueuedTasks(t *testing.T) {
+	var runs int32
+	queue := NewQueue("test", 10)
+	task := Task{
+		Name: "counter",
+		Run: func(ctx context.Context) error {
+			atomic.AddInt32(&runs, 1)
+			return nil
+		},
+	}
+
+	assert.True(t, queue.Enqueue(task))
+	assert.True(t, queue.Enqueue(Task{
+		Name: "failing",
+		Run:  func(ctx context.Context) error { return errors.New("job failed") },
+	}))
+	assert.True(t, queue.Enqueue(task))
+	assert.Equal(t, 3, queue.Len())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go queue.Run(ctx)
+
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&runs) == 2 && queue.Len() == 0
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestQueue_DropsTasksWhenFull(t *testing.T) {
+	queue := NewQueue("test", 1)
+	task := Task{Name: "noop", Run: func(ctx context.Context) error { return nil }}
+
+	assert.True(t, queue.Enqueue(task))
+	assert.False(t, queue.Enqueue(task))
+	assert.Equal(t, 1, queue.Len())
+}
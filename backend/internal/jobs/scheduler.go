@@ -0,0 +1,57 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"bugrelay-backend/internal/logger"
+)
+
+// Job represents a background task that runs on a fixed interval
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler runs registered jobs in the background
+type Scheduler struct {
+	jobs []Job
+}
+
+// NewScheduler creates a new job scheduler
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Register adds a job to the scheduler
+func (s *Scheduler) Register(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start runs every registered job on its interval until the context is cancelled
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		go s.runJob(ctx, job)
+	}
+}
+
+// runJob executes a single job on each tick of its interval
+func (s *Scheduler) runJob(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			start := time.Now()
+			if err := job.Run(ctx); err != nil {
+				logger.Error("Background job failed", err, logger.Fields{"job": job.Name})
+				continue
+			}
+			logger.Performance("job_"+job.Name, time.Since(start), nil)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduler_RunsJobsOnInterval(t *testing.T) {
+	var runs int32
+	scheduler := NewScheduler()
+	scheduler.Register(Job{
+		Name:     "counter",
+		Interval: 10 * time.Millisecond,
+		Run: func(ctx context.Context) error {
+			atomic.AddInt32(&runs, 1)
+			return nil
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	scheduler.Start(ctx)
+
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&runs) >= 2
+	}, time.Second, 5*time.Millisecond)
+
+	cancel()
+}
+
+func TestScheduler_ContinuesAfterFailure(t *testing.T) {
+	var runs int32
+	scheduler := NewScheduler()
+	scheduler.Register(Job{
+		Name:     "failing",
+		Interval: 10 * time.Millisecond,
+		Run: func(ctx context.Context) error {
+			atomic.AddInt32(&runs, 1)
+			return errors.New("job failed")
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scheduler.Start(ctx)
+
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&runs) >= 2
+	}, time.Second, 5*time.Millisecond)
+}
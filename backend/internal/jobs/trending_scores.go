@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"bugrelay-backend/internal/config"
+	"bugrelay-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// TrendingScoreRefreshInterval is how often bugs' trending scores are refreshed
+const TrendingScoreRefreshInterval = 10 * time.Minute
+
+// NewRefreshTrendingScoresJob creates the job that refreshes the trending scores of
+// the bugs the trending sort lists, so they decay as the bugs age
+func NewRefreshTrendingScoresJob(db *gorm.DB, cfg config.TrendingConfig) Job {
+	return Job{
+		Name:     "refresh_trending_scores",
+		Interval: TrendingScoreRefreshInterval,
+		Run: func(ctx context.Context) error {
+			now := time.Now()
+			var createdAfter *time.Time
+			if cfg.WindowDays > 0 {
+				start := now.AddDate(0, 0, -cfg.WindowDays)
+				createdAfter = &start
+			}
+			return models.RefreshTrendingScores(db.WithContext(ctx), cfg.Gravity, now, createdAfter)
+		},
+	}
+}
@@ -0,0 +1,23 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"bugrelay-backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// NewUserCleanupJob creates the daily job that cleans up the records deleted users
+// left behind
+func NewUserCleanupJob(db *gorm.DB) Job {
+	return Job{
+		Name:     "user_cleanup",
+		Interval: 24 * time.Hour,
+		Run: func(ctx context.Context) error {
+			_, err := models.CleanupDeletedUsers(db.WithContext(ctx), time.Now())
+			return err
+		},
+	}
+}
@@ -0,0 +1,139 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupUserCleanupTestDB creates an in-memory database with the tables a user's
+// records live in
+func setupUserCleanupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	// The models' postgres defaults cannot be migrated on sqlite
+	for _, schema := range []string{
+		`CREATE TABLE users (
+			id TEXT PRIMARY KEY,
+			email TEXT NOT NULL,
+			display_name TEXT NOT NULL,
+			deleted_at DATETIME,
+			cleanup_completed_at DATETIME
+		)`,
+		`CREATE TABLE bug_reports (
+			id TEXT PRIMARY KEY,
+			title TEXT NOT NULL,
+			status TEXT DEFAULT 'open',
+			reporter_id TEXT,
+			vote_count INTEGER DEFAULT 0,
+			weighted_vote_count REAL DEFAULT 0,
+			updated_at DATETIME,
+			deleted_at DATETIME
+		)`,
+		`CREATE TABLE bug_votes (id TEXT PRIMARY KEY, bug_id TEXT NOT NULL, user_id TEXT NOT NULL, vote_weight REAL DEFAULT 1)`,
+		`CREATE TABLE comments (
+			id TEXT PRIMARY KEY,
+			bug_id TEXT NOT NULL,
+			user_id TEXT,
+			content TEXT NOT NULL,
+			updated_at DATETIME
+		)`,
+		`CREATE TABLE company_members (id TEXT PRIMARY KEY, company_id TEXT NOT NULL, user_id TEXT NOT NULL)`,
+		`CREATE TABLE notification_preferences (id TEXT PRIMARY KEY, user_id TEXT NOT NULL)`,
+		`CREATE TABLE user_blocks (id TEXT PRIMARY KEY, blocker_id TEXT NOT NULL, blocked_id TEXT NOT NULL)`,
+	} {
+		require.NoError(t, db.Exec(schema).Error)
+	}
+
+	return db
+}
+
+func TestCleanupDeletedUsers(t *testing.T) {
+	db := setupUserCleanupTestDB(t)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	exec := func(sql string, args ...interface{}) {
+		require.NoError(t, db.Exec(sql, args...).Error)
+	}
+	count := func(table, where string, args ...interface{}) int64 {
+		var n int64
+		require.NoError(t, db.Table(table).Where(where, args...).Count(&n).Error)
+		return n
+	}
+
+	deleted, active, cleanedUp := uuid.New(), uuid.New(), uuid.New()
+	exec(`INSERT INTO users (id, email, display_name, deleted_at) VALUES (?, 'deleted@example.com', 'Deleted', ?)`, deleted, now.Add(-time.Hour))
+	exec(`INSERT INTO users (id, email, display_name) VALUES (?, 'active@example.com', 'Active')`, active)
+	exec(`INSERT INTO users (id, email, display_name, deleted_at, cleanup_completed_at) VALUES (?, 'done@example.com', 'Done', ?, ?)`,
+		cleanedUp, now.Add(-48*time.Hour), now.Add(-24*time.Hour))
+
+	openBug, fixedBug, activeBug := uuid.New(), uuid.New(), uuid.New()
+	exec(`INSERT INTO bug_reports (id, title, status, reporter_id, vote_count, weighted_vote_count) VALUES (?, 'Open', 'open', ?, 2, 2.5)`, openBug, deleted)
+	exec(`INSERT INTO bug_reports (id, title, status, reporter_id, vote_count, weighted_vote_count) VALUES (?, 'Fixed', 'fixed', ?, 1, 1)`, fixedBug, deleted)
+	exec(`INSERT INTO bug_reports (id, title, status, reporter_id, vote_count, weighted_vote_count) VALUES (?, 'Active', 'reviewing', ?, 1, 1.5)`, activeBug, active)
+
+	for _, userID := range []uuid.UUID{deleted, active, cleanedUp} {
+		exec(`INSERT INTO company_members (id, company_id, user_id) VALUES (?, ?, ?)`, uuid.New(), uuid.New(), userID)
+		exec(`INSERT INTO notification_preferences (id, user_id) VALUES (?, ?)`, uuid.New(), userID)
+		exec(`INSERT INTO comments (id, bug_id, user_id, content) VALUES (?, ?, ?, 'A comment')`, uuid.New(), activeBug, userID)
+	}
+	exec(`INSERT INTO bug_votes (id, bug_id, user_id, vote_weight) VALUES (?, ?, ?, 1.5)`, uuid.New(), openBug, deleted)
+	exec(`INSERT INTO bug_votes (id, bug_id, user_id) VALUES (?, ?, ?)`, uuid.New(), openBug, active)
+	exec(`INSERT INTO bug_votes (id, bug_id, user_id, vote_weight) VALUES (?, ?, ?, 1.5)`, uuid.New(), activeBug, deleted)
+	exec(`INSERT INTO user_blocks (id, blocker_id, blocked_id) VALUES (?, ?, ?)`, uuid.New(), deleted, active)
+	exec(`INSERT INTO user_blocks (id, blocker_id, blocked_id) VALUES (?, ?, ?)`, uuid.New(), active, deleted)
+	exec(`INSERT INTO user_blocks (id, blocker_id, blocked_id) VALUES (?, ?, ?)`, uuid.New(), active, cleanedUp)
+
+	cleaned, err := models.CleanupDeletedUsers(db, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cleaned)
+
+	// The deleted user's rows are removed and everyone else's are kept
+	for _, table := range []string{"bug_votes", "company_members", "notification_preferences"} {
+		assert.Zero(t, count(table, "user_id = ?", deleted), table)
+		assert.Equal(t, int64(1), count(table, "user_id = ?", active), table)
+	}
+	assert.Equal(t, int64(1), count("company_members", "user_id = ?", cleanedUp))
+	assert.Zero(t, count("user_blocks", "blocker_id = ? OR blocked_id = ?", deleted, deleted))
+	assert.Equal(t, int64(1), count("user_blocks", "blocker_id = ?", active))
+
+	// Comments are kept but anonymized
+	assert.Zero(t, count("comments", "user_id = ?", deleted))
+	assert.Equal(t, int64(1), count("comments", "user_id IS NULL AND content = ?", "[deleted]"))
+	assert.Equal(t, int64(2), count("comments", "content = ?", "A comment"))
+
+	// Unresolved bugs are detached from the user and vote counts drop with the votes
+	var bugs []models.BugReport
+	require.NoError(t, db.Unscoped().Select("id", "reporter_id", "vote_count", "weighted_vote_count").Find(&bugs).Error)
+	byID := make(map[uuid.UUID]models.BugReport)
+	for _, bug := range bugs {
+		byID[bug.ID] = bug
+	}
+	assert.Nil(t, byID[openBug].ReporterID)
+	assert.Equal(t, 1, byID[openBug].VoteCount)
+	assert.InDelta(t, 1.0, byID[openBug].WeightedVoteCount, 1e-9)
+	require.NotNil(t, byID[fixedBug].ReporterID)
+	assert.Equal(t, deleted, *byID[fixedBug].ReporterID)
+	require.NotNil(t, byID[activeBug].ReporterID)
+	assert.Equal(t, 0, byID[activeBug].VoteCount)
+	assert.InDelta(t, 0, byID[activeBug].WeightedVoteCount, 1e-9)
+
+	// Only the deleted user is marked as cleaned up, and running again does nothing
+	assert.Equal(t, int64(1), count("users", "id = ? AND cleanup_completed_at IS NOT NULL", deleted))
+	assert.Equal(t, int64(1), count("users", "cleanup_completed_at IS NULL"))
+
+	cleaned, err = models.CleanupDeletedUsers(db, now)
+	require.NoError(t, err)
+	assert.Zero(t, cleaned)
+}
@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Logger writes structured log entries. Request code gets a logger scoped to its
+// request from FromContext rather than using the package level functions.
+type Logger interface {
+	Debug(message string, fields ...Fields)
+	Info(message string, fields ...Fields)
+	Warn(message string, fields ...Fields)
+	Error(message string, err error, fields ...Fields)
+	Fatal(message string, err error, fields ...Fields)
+
+	// With returns a logger that adds fields to every entry
+	With(fields Fields) Logger
+}
+
+// LogrusLogger is a Logger writing through logrus, so it uses the level, format and
+// output set by Initialize
+type LogrusLogger struct {
+	entry *logrus.Entry
+}
+
+// NewLogrusLogger creates a Logger writing through the given logrus logger
+func NewLogrusLogger(l *logrus.Logger) *LogrusLogger {
+	return &LogrusLogger{entry: logrus.NewEntry(l)}
+}
+
+// Default returns a Logger writing through the global logrus logger configured by
+// Initialize
+func Default() Logger {
+	return NewLogrusLogger(logrus.StandardLogger())
+}
+
+// withFields adds the first of fields, if any, to the entry
+func (l *LogrusLogger) withFields(entry *logrus.Entry, fields []Fields) *logrus.Entry {
+	if len(fields) > 0 {
+		return entry.WithFields(logrus.Fields(fields[0]))
+	}
+	return entry
+}
+
+func (l *LogrusLogger) Debug(message string, fields ...Fields) {
+	l.withFields(l.entry, fields).Debug(message)
+}
+
+func (l *LogrusLogger) Info(message string, fields ...Fields) {
+	l.withFields(l.entry, fields).Info(message)
+}
+
+func (l *LogrusLogger) Warn(message string, fields ...Fields) {
+	l.withFields(l.entry, fields).Warn(message)
+}
+
+func (l *LogrusLogger) Error(message string, err error, fields ...Fields) {
+	l.withFields(l.entry.WithError(err), fields).Error(message)
+}
+
+func (l *LogrusLogger) Fatal(message string, err error, fields ...Fields) {
+	l.withFields(l.entry.WithError(err), fields).Fatal(message)
+}
+
+func (l *LogrusLogger) With(fields Fields) Logger {
+	return &LogrusLogger{entry: l.entry.WithFields(logrus.Fields(fields))}
+}
+
+// contextKey is the context key the logger is stored under
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying l
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the logger carried by ctx, or the default logger when there
+// is none
+func FromContext(ctx context.Context) Logger {
+	if l, ok := ctx.Value(contextKey{}).(Logger); ok {
+		return l
+	}
+	return Default()
+}
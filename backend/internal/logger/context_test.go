@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromContext(t *testing.T) {
+	t.Run("returns the default logger when none is set", func(t *testing.T) {
+		assert.IsType(t, &LogrusLogger{}, FromContext(context.Background()))
+	})
+
+	t.Run("returns the injected logger", func(t *testing.T) {
+		testLogger := NewTestLogger()
+		ctx := NewContext(context.Background(), testLogger)
+
+		FromContext(ctx).Info("hello", Fields{"key": "value"})
+
+		entries := testLogger.Entries()
+		require.Len(t, entries, 1)
+		assert.Equal(t, "info", entries[0].Level)
+		assert.Equal(t, "hello", entries[0].Message)
+		assert.Equal(t, Fields{"key": "value"}, entries[0].Fields)
+	})
+}
+
+func TestTestLogger(t *testing.T) {
+	testLogger := NewTestLogger()
+	requestLogger := testLogger.With(Fields{"request_id": "abc"})
+
+	requestLogger.Warn("slow query", Fields{"duration_ms": 1200})
+	requestLogger.Error("cache failed", errors.New("connection refused"))
+	testLogger.Debug("unscoped")
+	testLogger.Fatal("would exit", errors.New("boom"))
+
+	// Child loggers share entries with their parent and keep their own fields
+	entries := testLogger.Entries()
+	require.Len(t, entries, 4)
+	assert.Equal(t, Fields{"request_id": "abc", "duration_ms": 1200}, entries[0].Fields)
+	assert.Equal(t, "error", entries[1].Level)
+	assert.EqualError(t, entries[1].Err, "connection refused")
+	assert.Equal(t, Fields{"request_id": "abc"}, entries[1].Fields)
+	assert.Empty(t, entries[2].Fields)
+	assert.Equal(t, "fatal", entries[3].Level)
+
+	assert.Len(t, testLogger.EntriesWithMessage("cache failed"), 1)
+	assert.Empty(t, testLogger.EntriesWithMessage("missing"))
+}
+
+func TestLogrusLogger(t *testing.T) {
+	var output bytes.Buffer
+	base := logrus.New()
+	base.SetOutput(&output)
+	base.SetFormatter(&logrus.JSONFormatter{})
+
+	l := NewLogrusLogger(base).With(Fields{"request_id": "abc"})
+	l.Error("cache failed", errors.New("connection refused"), Fields{"bug_id": "123"})
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(output.Bytes(), &entry))
+	assert.Equal(t, "cache failed", entry["msg"])
+	assert.Equal(t, "error", entry["level"])
+	assert.Equal(t, "connection refused", entry["error"])
+	assert.Equal(t, "abc", entry["request_id"])
+	assert.Equal(t, "123", entry["bug_id"])
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/getsentry/sentry-go"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/natefinch/lumberjack.v2"
@@ -234,14 +235,37 @@ func Error(message string, err error, fields ...Fields) {
 	entry.Error(message)
 }
 
+// Fatal logs the error and exits, reporting it to Sentry first
 func Fatal(message string, err error, fields ...Fields) {
 	entry := logrus.WithError(err)
 	if len(fields) > 0 {
 		entry = entry.WithFields(logrus.Fields(fields[0]))
 	}
+
+	if err != nil {
+		details := sentry.Context{"message": message}
+		if len(fields) > 0 {
+			for k, v := range fields[0] {
+				details[k] = v
+			}
+		}
+		sentry.WithScope(func(scope *sentry.Scope) {
+			scope.SetLevel(sentry.LevelFatal)
+			scope.SetContext("log", details)
+			sentry.CaptureException(err)
+		})
+	}
+	// Exiting skips deferred calls, so wait for the event to be sent here
+	sentry.Flush(2 * time.Second)
+
 	entry.Fatal(message)
 }
 
+// IsDebugEnabled reports whether debug level logging is enabled
+func IsDebugEnabled() bool {
+	return logrus.IsLevelEnabled(logrus.DebugLevel)
+}
+
 func Debug(message string, fields ...Fields) {
 	if len(fields) > 0 {
 		logrus.WithFields(logrus.Fields(fields[0])).Debug(message)
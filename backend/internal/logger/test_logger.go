@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"sync"
+)
+
+// Entry is a log entry collected by TestLogger
+type Entry struct {
+	Level   string
+	Message string
+	Err     error
+	Fields  Fields
+}
+
+// TestLogger is a Logger that collects entries in memory so tests can assert on
+// them. Loggers created with With share their parent's entries. Fatal is recorded
+// like any other level and does not exit.
+type TestLogger struct {
+	fields Fields
+	store  *testLogStore
+}
+
+// testLogStore holds the entries shared by a TestLogger and its children
+type testLogStore struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// NewTestLogger creates an empty TestLogger
+func NewTestLogger() *TestLogger {
+	return &TestLogger{fields: Fields{}, store: &testLogStore{}}
+}
+
+// Entries returns the entries logged so far
+func (l *TestLogger) Entries() []Entry {
+	l.store.mu.Lock()
+	defer l.store.mu.Unlock()
+	return append([]Entry(nil), l.store.entries...)
+}
+
+// EntriesWithMessage returns the entries logged with message
+func (l *TestLogger) EntriesWithMessage(message string) []Entry {
+	var matching []Entry
+	for _, entry := range l.Entries() {
+		if entry.Message == message {
+			matching = append(matching, entry)
+		}
+	}
+	return matching
+}
+
+func (l *TestLogger) log(level, message string, err error, fields []Fields) {
+	entryFields := Fields{}
+	for k, v := range l.fields {
+		entryFields[k] = v
+	}
+	if len(fields) > 0 {
+		for k, v := range fields[0] {
+			entryFields[k] = v
+		}
+	}
+
+	l.store.mu.Lock()
+	defer l.store.mu.Unlock()
+	l.store.entries = append(l.store.entries, Entry{Level: level, Message: message, Err: err, Fields: entryFields})
+}
+
+func (l *TestLogger) Debug(message string, fields ...Fields) {
+	l.log("debug", message, nil, fields)
+}
+
+func (l *TestLogger) Info(message string, fields ...Fields) {
+	l.log("info", message, nil, fields)
+}
+
+func (l *TestLogger) Warn(message string, fields ...Fields) {
+	l.log("warn", message, nil, fields)
+}
+
+func (l *TestLogger) Error(message string, err error, fields ...Fields) {
+	l.log("error", message, err, fields)
+}
+
+func (l *TestLogger) Fatal(message string, err error, fields ...Fields) {
+	l.log("fatal", message, err, fields)
+}
+
+func (l *TestLogger) With(fields Fields) Logger {
+	merged := Fields{}
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &TestLogger{fields: merged, store: l.store}
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Metrics are registered with the default Prometheus registry, which promhttp.Handler
+// serves along with the Go runtime and process collectors.
+var (
+	// HTTPRequestsTotal counts handled requests by method, route and status code
+	HTTPRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "bugrelay_http_requests_total",
+		Help: "Number of HTTP requests handled, by method, route and status code.",
+	}, []string{"method", "path", "status"})
+
+	// HTTPRequestDuration observes how long requests take by method and route
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "bugrelay_http_request_duration_seconds",
+		Help:    "Time taken to handle HTTP requests, by method and route.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "path"})
+
+	// BugsCreatedTotal counts submitted bug reports
+	BugsCreatedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bugrelay_bugs_created_total",
+		Help: "Number of bug reports submitted.",
+	})
+
+	// VotesTotal counts votes cast on bug reports
+	VotesTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bugrelay_votes_total",
+		Help: "Number of votes cast on bug reports.",
+	})
+
+	// CommentsTotal counts comments posted on bug reports
+	CommentsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bugrelay_comments_total",
+		Help: "Number of comments posted on bug reports.",
+	})
+
+	// CacheHitsTotal counts cache lookups that found a value, by cache type
+	CacheHitsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "bugrelay_cache_hits_total",
+		Help: "Number of cache lookups that found a value, by cache type.",
+	}, []string{"cache_type"})
+
+	// CacheMissesTotal counts cache lookups that found nothing or failed, by cache type
+	CacheMissesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "bugrelay_cache_misses_total",
+		Help: "Number of cache lookups that found nothing or failed, by cache type.",
+	}, []string{"cache_type"})
+)
+
+// UnmatchedPath is the path label of requests that matched no route, so scanners
+// probing random URLs cannot create a label per URL
+const UnmatchedPath = "unmatched"
+
+// RecordCacheLookup counts a cache lookup of cacheType as a hit when err is nil and
+// as a miss otherwise
+func RecordCacheLookup(cacheType string, err error) {
+	if err == nil {
+		CacheHitsTotal.WithLabelValues(cacheType).Inc()
+		return
+	}
+	CacheMissesTotal.WithLabelValues(cacheType).Inc()
+}
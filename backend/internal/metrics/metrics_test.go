@@ -0,0 +1,21 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordCacheLookup(t *testing.T) {
+	hits := testutil.ToFloat64(CacheHitsTotal.WithLabelValues("test"))
+	misses := testutil.ToFloat64(CacheMissesTotal.WithLabelValues("test"))
+
+	RecordCacheLookup("test", nil)
+	RecordCacheLookup("test", nil)
+	RecordCacheLookup("test", errors.New("redis: nil"))
+
+	assert.Equal(t, hits+2, testutil.ToFloat64(CacheHitsTotal.WithLabelValues("test")))
+	assert.Equal(t, misses+1, testutil.ToFloat64(CacheMissesTotal.WithLabelValues("test")))
+}
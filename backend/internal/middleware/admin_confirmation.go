@@ -0,0 +1,246 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"bugrelay-backend/internal/auth"
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// ConfirmTokenHeader carries the confirmation token that executes a destructive
+// admin action
+const ConfirmTokenHeader = "X-Confirm-Token"
+
+// AdminConfirmationKeyPrefix is the Redis key prefix for pending confirmations
+const AdminConfirmationKeyPrefix = "admin_confirm:"
+
+// AdminConfirmationTTL is how long a confirmation token can be used
+const AdminConfirmationTTL = 5 * time.Minute
+
+// Reasons a new confirmation token is issued for a request that sent one
+const (
+	ConfirmationReasonExpired  = "expired"
+	ConfirmationReasonTampered = "tampered"
+)
+
+// confirmationPayload is the request a confirmation token executes. A token only
+// executes a request from the same admin to the same URL with the same body.
+type confirmationPayload struct {
+	Action     string `json:"action"`
+	RequestURI string `json:"request_uri"`
+	AdminID    string `json:"admin_id"`
+	BodySHA256 string `json:"body_sha256"`
+}
+
+// AdminConfirmer issues and checks the confirmation tokens of destructive admin
+// actions, which run in two steps: the first request only returns a token, and
+// repeating the request with the token in X-Confirm-Token executes it
+type AdminConfirmer struct {
+	redisClient *redis.Client
+	secret      []byte
+
+	// In-memory confirmations used when Redis is not available
+	mu      sync.Mutex
+	pending map[string]pendingConfirmation
+
+	now func() time.Time
+}
+
+// pendingConfirmation is a confirmation stored in memory
+type pendingConfirmation struct {
+	payload   []byte
+	expiresAt time.Time
+}
+
+// NewAdminConfirmer creates an admin confirmer. The secret signs confirmation tokens,
+// so a token cannot be reused for a different request.
+func NewAdminConfirmer(redisClient *redis.Client, secret string) *AdminConfirmer {
+	return &AdminConfirmer{
+		redisClient: redisClient,
+		secret:      []byte(secret),
+		pending:     make(map[string]pendingConfirmation),
+		now:         time.Now,
+	}
+}
+
+// issue stores a confirmation for the payload and returns its token. Tokens are
+// "<id>.<signature>", where the signature covers the id and the payload.
+func (a *AdminConfirmer) issue(ctx context.Context, payload confirmationPayload) (string, error) {
+	id, err := auth.GenerateSecureToken(16)
+	if err != nil {
+		return "", err
+	}
+
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	if a.redisClient != nil {
+		if err := a.redisClient.Set(ctx, AdminConfirmationKeyPrefix+id, encoded, AdminConfirmationTTL).Err(); err != nil {
+			return "", err
+		}
+	} else {
+		a.mu.Lock()
+		a.pending[id] = pendingConfirmation{payload: encoded, expiresAt: a.now().Add(AdminConfirmationTTL)}
+		a.mu.Unlock()
+	}
+
+	return id + "." + a.sign(id, encoded), nil
+}
+
+// consume removes the confirmation with the token's id and checks that the token
+// confirms the payload. It returns "" when the token is valid, or the reason it
+// is not.
+func (a *AdminConfirmer) consume(ctx context.Context, token string, payload confirmationPayload) (string, error) {
+	id, signature, ok := strings.Cut(token, ".")
+	if !ok || id == "" {
+		return ConfirmationReasonTampered, nil
+	}
+
+	stored, found, err := a.take(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return ConfirmationReasonExpired, nil
+	}
+
+	// The signature detects changes to the token or the stored payload, and the
+	// payload comparison a token used for a different request
+	if !hmac.Equal([]byte(signature), []byte(a.sign(id, stored))) {
+		return ConfirmationReasonTampered, nil
+	}
+	var confirmed confirmationPayload
+	if err := json.Unmarshal(stored, &confirmed); err != nil || confirmed != payload {
+		return ConfirmationReasonTampered, nil
+	}
+
+	return "", nil
+}
+
+// take removes and returns a stored confirmation, so each token is used once
+func (a *AdminConfirmer) take(ctx context.Context, id string) ([]byte, bool, error) {
+	if a.redisClient != nil {
+		stored, err := a.redisClient.GetDel(ctx, AdminConfirmationKeyPrefix+id).Bytes()
+		if err == redis.Nil {
+			return nil, false, nil
+		}
+		return stored, err == nil, err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	pending, ok := a.pending[id]
+	delete(a.pending, id)
+	if !ok || !a.now().Before(pending.expiresAt) {
+		return nil, false, nil
+	}
+	return pending.payload, true, nil
+}
+
+func (a *AdminConfirmer) sign(id string, payload []byte) string {
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte("admin_confirm:" + id + ":"))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// AdminConfirmationMiddleware requires confirmation of the destructive actions, given
+// as "METHOD /path" with the route's full path, e.g. "DELETE /api/v1/admin/bugs/:id".
+// Requests without a valid X-Confirm-Token get CONFIRMATION_REQUIRED with a new token
+// and make no changes. Other routes are not affected. It must run after the admin
+// check, since tokens are bound to the admin.
+func (a *AdminConfirmer) AdminConfirmationMiddleware(destructiveActions []string) gin.HandlerFunc {
+	destructive := make(map[string]bool, len(destructiveActions))
+	for _, action := range destructiveActions {
+		destructive[action] = true
+	}
+
+	return func(c *gin.Context) {
+		action := c.Request.Method + " " + c.FullPath()
+		if !destructive[action] {
+			c.Next()
+			return
+		}
+
+		// The body is part of the confirmed request, and is put back for the handler
+		var body []byte
+		if c.Request.Body != nil {
+			var err error
+			body, err = io.ReadAll(c.Request.Body)
+			if err != nil {
+				if IsRequestBodyTooLarge(err) {
+					AbortRequestBodyTooLarge(c)
+					return
+				}
+				errors.ErrInvalidRequest.WithMessage("Failed to read request body").Response(c)
+				c.Abort()
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		bodySum := sha256.Sum256(body)
+		payload := confirmationPayload{
+			Action:     action,
+			RequestURI: c.Request.URL.RequestURI(),
+			AdminID:    c.GetString("user_id"),
+			BodySHA256: hex.EncodeToString(bodySum[:]),
+		}
+
+		ctx := c.Request.Context()
+		var reason string
+		var err error
+		if token := c.GetHeader(ConfirmTokenHeader); token != "" {
+			reason, err = a.consume(ctx, token, payload)
+			if err != nil {
+				logger.FromContext(ctx).Error("Failed to check admin confirmation token", err, logger.Fields{"action": action})
+				errors.ErrConfirmationFailed.Response(c)
+				c.Abort()
+				return
+			}
+			if reason == "" {
+				c.Next()
+				return
+			}
+			logger.FromContext(ctx).Warn("Rejected admin confirmation token", logger.Fields{
+				"action":   action,
+				"reason":   reason,
+				"admin_id": payload.AdminID,
+			})
+		}
+
+		token, err := a.issue(ctx, payload)
+		if err != nil {
+			logger.FromContext(ctx).Error("Failed to issue admin confirmation token", err, logger.Fields{"action": action})
+			errors.ErrConfirmationFailed.Response(c)
+			c.Abort()
+			return
+		}
+
+		details := gin.H{
+			"confirmation_token": token,
+			"expires_in_seconds": int(AdminConfirmationTTL.Seconds()),
+			"action_summary":     c.Request.Method + " " + payload.RequestURI,
+		}
+		if reason != "" {
+			details["reason"] = reason
+		}
+		errors.ErrConfirmationRequired.WithDetails(details).Response(c)
+		c.Abort()
+	}
+}
@@ -0,0 +1,203 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminConfirmationMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	clock := func() time.Time { return now }
+	redisClient, mock := newMockExpiringRedisClient(clock)
+
+	for _, backend := range []struct {
+		name        string
+		redisClient *redis.Client
+	}{
+		{"redis", redisClient},
+		{"in-memory", nil},
+	} {
+		t.Run(backend.name, func(t *testing.T) {
+			now = start
+			confirmer := NewAdminConfirmer(backend.redisClient, "test-secret")
+			confirmer.now = clock
+
+			removed := map[string]string{}
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				c.Set("user_id", c.GetHeader("X-Test-Admin"))
+				c.Next()
+			})
+			router.Use(confirmer.AdminConfirmationMiddleware([]string{"DELETE /admin/bugs/:id"}))
+			router.DELETE("/admin/bugs/:id", func(c *gin.Context) {
+				var req struct {
+					Reason string `json:"reason"`
+				}
+				require.NoError(t, c.ShouldBindJSON(&req))
+				removed[c.Param("id")] = req.Reason
+				c.JSON(http.StatusOK, gin.H{"message": "removed"})
+			})
+			router.POST("/admin/bugs/:id/restore", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"message": "restored"})
+			})
+
+			request := func(method, path, body, adminID, token string) *httptest.ResponseRecorder {
+				req, _ := http.NewRequest(method, path, strings.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("X-Test-Admin", adminID)
+				if token != "" {
+					req.Header.Set(ConfirmTokenHeader, token)
+				}
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				return w
+			}
+
+			// confirmationRequired checks a CONFIRMATION_REQUIRED response and returns
+			// its details
+			confirmationRequired := func(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+				require.Equal(t, http.StatusPreconditionRequired, w.Code)
+				var response struct {
+					Error struct {
+						Code    string                 `json:"code"`
+						Details map[string]interface{} `json:"details"`
+					} `json:"error"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, "CONFIRMATION_REQUIRED", response.Error.Code)
+				assert.NotEmpty(t, response.Error.Details["confirmation_token"])
+				assert.Equal(t, float64(300), response.Error.Details["expires_in_seconds"])
+				return response.Error.Details
+			}
+
+			body := `{"reason":"spam"}`
+
+			t.Run("first call returns a token without running the action", func(t *testing.T) {
+				details := confirmationRequired(t, request("DELETE", "/admin/bugs/1", body, "admin-1", ""))
+				assert.Equal(t, "DELETE /admin/bugs/1", details["action_summary"])
+				assert.NotContains(t, details, "reason")
+				assert.Empty(t, removed)
+			})
+
+			t.Run("second call with the token runs the action once", func(t *testing.T) {
+				token := confirmationRequired(t, request("DELETE", "/admin/bugs/1", body, "admin-1", ""))["confirmation_token"].(string)
+
+				w := request("DELETE", "/admin/bugs/1", body, "admin-1", token)
+				require.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, "spam", removed["1"], "the handler still reads the body")
+
+				// Tokens are single use
+				details := confirmationRequired(t, request("DELETE", "/admin/bugs/1", body, "admin-1", token))
+				assert.Equal(t, ConfirmationReasonExpired, details["reason"])
+			})
+
+			t.Run("expired tokens are replaced", func(t *testing.T) {
+				delete(removed, "2")
+				token := confirmationRequired(t, request("DELETE", "/admin/bugs/2", body, "admin-1", ""))["confirmation_token"].(string)
+
+				now = now.Add(AdminConfirmationTTL + time.Second)
+				details := confirmationRequired(t, request("DELETE", "/admin/bugs/2", body, "admin-1", token))
+				assert.Equal(t, ConfirmationReasonExpired, details["reason"])
+				assert.NotEqual(t, token, details["confirmation_token"])
+				assert.NotContains(t, removed, "2")
+
+				// The fresh token works
+				w := request("DELETE", "/admin/bugs/2", body, "admin-1", details["confirmation_token"].(string))
+				assert.Equal(t, http.StatusOK, w.Code)
+			})
+
+			t.Run("tokens only confirm the request they were issued for", func(t *testing.T) {
+				tests := []struct {
+					name, path, body, adminID string
+				}{
+					{"different bug", "/admin/bugs/4", body, "admin-1"},
+					{"different body", "/admin/bugs/3", `{"reason":"duplicate"}`, "admin-1"},
+					{"different admin", "/admin/bugs/3", body, "admin-2"},
+					{"added query", "/admin/bugs/3?force=true", body, "admin-1"},
+				}
+				for _, tt := range tests {
+					t.Run(tt.name, func(t *testing.T) {
+						token := confirmationRequired(t, request("DELETE", "/admin/bugs/3", body, "admin-1", ""))["confirmation_token"].(string)
+
+						details := confirmationRequired(t, request("DELETE", tt.path, tt.body, tt.adminID, token))
+						assert.Equal(t, ConfirmationReasonTampered, details["reason"])
+						assert.NotContains(t, removed, "3")
+						assert.NotContains(t, removed, "4")
+					})
+				}
+			})
+
+			t.Run("tampered tokens are rejected", func(t *testing.T) {
+				token := confirmationRequired(t, request("DELETE", "/admin/bugs/5", body, "admin-1", ""))["confirmation_token"].(string)
+				id, signature, _ := strings.Cut(token, ".")
+
+				forged := id + "." + strings.Repeat("0", len(signature))
+				details := confirmationRequired(t, request("DELETE", "/admin/bugs/5", body, "admin-1", forged))
+				assert.Equal(t, ConfirmationReasonTampered, details["reason"])
+
+				details = confirmationRequired(t, request("DELETE", "/admin/bugs/5", body, "admin-1", "not-a-token"))
+				assert.Equal(t, ConfirmationReasonTampered, details["reason"])
+				assert.NotContains(t, removed, "5")
+			})
+
+			if backend.redisClient != nil {
+				t.Run("tampering with the stored payload is detected", func(t *testing.T) {
+					token := confirmationRequired(t, request("DELETE", "/admin/bugs/6", body, "admin-1", ""))["confirmation_token"].(string)
+					id, _, _ := strings.Cut(token, ".")
+
+					// Point the stored confirmation at another bug
+					key := AdminConfirmationKeyPrefix + id
+					mock.mu.Lock()
+					mock.values[key] = strings.Replace(mock.values[key], "/admin/bugs/6", "/admin/bugs/7", 1)
+					mock.mu.Unlock()
+
+					details := confirmationRequired(t, request("DELETE", "/admin/bugs/7", body, "admin-1", token))
+					assert.Equal(t, ConfirmationReasonTampered, details["reason"])
+					assert.NotContains(t, removed, "7")
+				})
+			}
+
+			t.Run("other admin actions need no confirmation", func(t *testing.T) {
+				w := request("POST", "/admin/bugs/1/restore", "", "admin-1", "")
+				assert.Equal(t, http.StatusOK, w.Code)
+			})
+		})
+	}
+}
+
+func TestAdminConfirmer_RedisUnavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// A client that cannot connect fails closed rather than running the action
+	redisClient := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 50 * time.Millisecond})
+	confirmer := NewAdminConfirmer(redisClient, "test-secret")
+
+	ran := false
+	router := gin.New()
+	router.Use(confirmer.AdminConfirmationMiddleware([]string{"DELETE /admin/bugs/purge"}))
+	router.DELETE("/admin/bugs/purge", func(c *gin.Context) { ran = true })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "DELETE", "/admin/bugs/purge", nil)
+	req.Header.Set(ConfirmTokenHeader, "abc.def")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "CONFIRMATION_FAILED")
+	assert.False(t, ran)
+}
@@ -2,18 +2,26 @@ package middleware
 
 import (
 	"context"
-	"net/http"
 	"strings"
+	"time"
 
 	"bugrelay-backend/internal/auth"
+	"bugrelay-backend/internal/errors"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // AuthMiddleware handles JWT authentication for protected routes
 type AuthMiddleware struct {
 	jwtService       *auth.JWTService
 	blacklistService *auth.BlacklistService
+
+	// Session IP binding, see SetSessionIPBinding
+	bindSessionToIP     bool
+	ipChangeGracePeriod time.Duration
+	ipChanges           *auth.IPChangeTracker
+	db                  *gorm.DB
 }
 
 // NewAuthMiddleware creates a new authentication middleware
@@ -29,26 +37,14 @@ func (a *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := a.extractToken(c)
 		if token == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": gin.H{
-					"code":      "MISSING_TOKEN",
-					"message":   "Authentication token is required",
-					"timestamp": gin.H{},
-				},
-			})
+			errors.ErrMissingAuthToken.Response(c)
 			c.Abort()
 			return
 		}
 
 		claims, err := a.jwtService.ValidateToken(token)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": gin.H{
-					"code":      "INVALID_TOKEN",
-					"message":   "Invalid or expired authentication token",
-					"timestamp": gin.H{},
-				},
-			})
+			errors.ErrInvalidAuthToken.Response(c)
 			c.Abort()
 			return
 		}
@@ -56,38 +52,27 @@ func (a *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 		// Check if token is blacklisted
 		isBlacklisted, err := a.blacklistService.IsTokenBlacklisted(context.Background(), claims.ID)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": gin.H{
-					"code":      "AUTH_CHECK_FAILED",
-					"message":   "Failed to verify authentication status",
-					"timestamp": gin.H{},
-				},
-			})
+			errors.ErrAuthCheckFailed.Response(c)
 			c.Abort()
 			return
 		}
 
 		if isBlacklisted {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": gin.H{
-					"code":      "TOKEN_REVOKED",
-					"message":   "Authentication token has been revoked",
-					"timestamp": gin.H{},
-				},
-			})
+			errors.ErrTokenRevoked.Response(c)
 			c.Abort()
 			return
 		}
 
 		// Ensure this is an access token
 		if claims.TokenType != "access" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": gin.H{
-					"code":      "INVALID_TOKEN_TYPE",
-					"message":   "Access token required",
-					"timestamp": gin.H{},
-				},
-			})
+			errors.ErrInvalidTokenType.Response(c)
+			c.Abort()
+			return
+		}
+
+		// Reject sessions used from an IP other than the one they are bound to
+		if !a.checkSessionIP(c, claims) {
+			errors.ErrSessionIPMismatch.Response(c)
 			c.Abort()
 			return
 		}
@@ -114,13 +99,7 @@ func (a *AuthMiddleware) RequireAdmin() gin.HandlerFunc {
 		// Check admin status
 		isAdmin, exists := c.Get("is_admin")
 		if !exists || !isAdmin.(bool) {
-			c.JSON(http.StatusForbidden, gin.H{
-				"error": gin.H{
-					"code":      "INSUFFICIENT_PRIVILEGES",
-					"message":   "Admin privileges required",
-					"timestamp": gin.H{},
-				},
-			})
+			errors.ErrInsufficientPrivileges.Response(c)
 			c.Abort()
 			return
 		}
@@ -152,8 +131,8 @@ func (a *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 			return
 		}
 
-		// Ensure this is an access token
-		if claims.TokenType != "access" {
+		// Ensure this is an access token from the IP the session is bound to
+		if claims.TokenType != "access" || !a.checkSessionIP(c, claims) {
 			c.Next()
 			return
 		}
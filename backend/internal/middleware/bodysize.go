@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	apierrors "bugrelay-backend/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AttachmentMaxRequestBodyBytes is the body size limit of attachment upload routes
+const AttachmentMaxRequestBodyBytes int64 = 10 << 20
+
+// originalBodyKey stores the unlimited request body so a route can replace the
+// global limit with its own
+const originalBodyKey = "original_request_body"
+
+// BodySizeLimit limits request bodies to maxBytes. Reading past the limit fails, so
+// bind calls return an error IsRequestBodyTooLarge recognizes and handlers respond
+// with AbortRequestBodyTooLarge. A limit applied to a route replaces one applied
+// earlier on the whole router. Content-Length is not checked up front, because the
+// router-wide limit runs before a route can raise it.
+func BodySizeLimit(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body io.ReadCloser
+		if original, exists := c.Get(originalBodyKey); exists {
+			body = original.(io.ReadCloser)
+		} else {
+			body = c.Request.Body
+			c.Set(originalBodyKey, body)
+		}
+
+		if body != nil {
+			c.Request.Body = http.MaxBytesReader(c.Writer, body, maxBytes)
+		}
+		c.Next()
+	}
+}
+
+// IsRequestBodyTooLarge reports whether err was caused by reading a request body past
+// its BodySizeLimit
+func IsRequestBodyTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
+
+// AbortRequestBodyTooLarge responds that the request body exceeded its size limit
+func AbortRequestBodyTooLarge(c *gin.Context) {
+	apierrors.ErrRequestEntityTooLarge.Response(c)
+	c.Abort()
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// jsonBodyOfSize returns a JSON object exactly size bytes long
+func jsonBodyOfSize(size int) string {
+	const wrapper = `{"data":""}`
+	return `{"data":"` + strings.Repeat("a", size-len(wrapper)) + `"}`
+}
+
+// bindHandler binds a JSON body the way handlers do, converting oversized bodies to 413
+func bindHandler(c *gin.Context) {
+	var payload map[string]interface{}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		if IsRequestBodyTooLarge(err) {
+			AbortRequestBodyTooLarge(c)
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "ok"})
+}
+
+func TestBodySizeLimit(t *testing.T) {
+	const maxBytes = 1024
+
+	router := setupTestRouter()
+	router.Use(BodySizeLimit(maxBytes))
+	router.POST("/test", bindHandler)
+	router.POST("/upload", BodySizeLimit(4*maxBytes), bindHandler)
+
+	tests := []struct {
+		name           string
+		path           string
+		bodySize       int
+		expectedStatus int
+	}{
+		{"just under the limit", "/test", maxBytes - 1, http.StatusOK},
+		{"exactly the limit", "/test", maxBytes, http.StatusOK},
+		{"just over the limit", "/test", maxBytes + 1, http.StatusRequestEntityTooLarge},
+		{"far over the limit", "/test", 100 * maxBytes, http.StatusRequestEntityTooLarge},
+		{"route limit replaces the global limit", "/upload", 4 * maxBytes, http.StatusOK},
+		{"just over the route limit", "/upload", 4*maxBytes + 1, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := jsonBodyOfSize(tt.bodySize)
+			assert.Len(t, body, tt.bodySize)
+
+			req, _ := http.NewRequest("POST", tt.path, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusRequestEntityTooLarge {
+				assert.Contains(t, w.Body.String(), "REQUEST_ENTITY_TOO_LARGE")
+			}
+		})
+	}
+
+	t.Run("body without a content length", func(t *testing.T) {
+		// A chunked body is only caught once it is read past the limit
+		req, _ := http.NewRequest("POST", "/test", io.NopCloser(strings.NewReader(jsonBodyOfSize(maxBytes+1))))
+		req.Header.Set("Content-Type", "application/json")
+		assert.Equal(t, int64(0), req.ContentLength)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+}
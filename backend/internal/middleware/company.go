@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CompanyMiddleware restricts company routes to the company's members
+type CompanyMiddleware struct {
+	db *gorm.DB
+}
+
+// NewCompanyMiddleware creates a new company middleware
+func NewCompanyMiddleware(db *gorm.DB) *CompanyMiddleware {
+	return &CompanyMiddleware{
+		db: db,
+	}
+}
+
+// RequireCompanyMember middleware that requires the current user to be a member of
+// the company in the :id route parameter. Must run after RequireAuth.
+func (m *CompanyMiddleware) RequireCompanyMember() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		companyID, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			errors.ErrInvalidID.WithMessage("Invalid company ID format").Response(c)
+			c.Abort()
+			return
+		}
+
+		userIDStr, _ := GetCurrentUserID(c)
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			errors.ErrUnauthorized.Response(c)
+			c.Abort()
+			return
+		}
+
+		var member models.CompanyMember
+		if err := m.db.Where("company_id = ? AND user_id = ?", companyID, userID).First(&member).Error; err != nil {
+			errors.ErrInsufficientPermissions.WithMessage("Access denied. User is not a member of this company").Response(c)
+			c.Abort()
+			return
+		}
+
+		// Store the membership role for handlers that need it
+		c.Set("company_role", member.Role)
+		c.Next()
+	}
+}
@@ -0,0 +1,10 @@
+package middleware
+
+import "net"
+
+// GeoIPLookup resolves the country an IP address is located in
+type GeoIPLookup interface {
+	// CountryCode returns the ISO 3166-1 alpha-2 code of the IP's country, or an
+	// empty string when the IP is not in the database
+	CountryCode(ip net.IP) (string, error)
+}
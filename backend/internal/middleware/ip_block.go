@@ -0,0 +1,239 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// IPBlockKeyPrefix is the Redis key prefix for blocked IP addresses and CIDR ranges
+const IPBlockKeyPrefix = "ipblock:"
+
+// Minimum CIDR prefix lengths that can be blocked, so a typo cannot block a large
+// share of the internet
+const (
+	MinIPv4BlockPrefix = 8
+	MinIPv6BlockPrefix = 16
+)
+
+// ipBlockRestoreInterval is how often the blocks recorded in the ip_blocks table are
+// restored, so blocks lost to a Redis flush come back within it
+const ipBlockRestoreInterval = time.Minute
+
+// IPBlocker keeps the IP addresses and CIDR ranges administrators have blocked and
+// rejects requests from them
+type IPBlocker struct {
+	redisClient *redis.Client
+	db          *gorm.DB
+
+	// In-memory blocks used when Redis is not available
+	mu     sync.RWMutex
+	blocks map[string]time.Time
+
+	restoreMu  sync.Mutex
+	restoredAt time.Time
+
+	now func() time.Time
+}
+
+// NewIPBlocker creates a new IP blocker. Blocks are restored from the ip_blocks table
+// in db, when given, by Restore and then every minute while requests are checked, so
+// they survive a restart or a Redis flush.
+func NewIPBlocker(redisClient *redis.Client, db *gorm.DB) *IPBlocker {
+	return &IPBlocker{
+		redisClient: redisClient,
+		db:          db,
+		blocks:      make(map[string]time.Time),
+		now:         time.Now,
+	}
+}
+
+// ParseIPBlock validates an IP address or CIDR range and returns it in canonical
+// form, e.g. 192.168.0.0/16 for "192.168.1.1/16". Ranges covering a single address
+// are returned as the address.
+func ParseIPBlock(value string) (string, error) {
+	value = strings.TrimSpace(value)
+
+	if !strings.Contains(value, "/") {
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return "", fmt.Errorf("invalid IP address: %s", value)
+		}
+		return ip.String(), nil
+	}
+
+	_, network, err := net.ParseCIDR(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid CIDR range: %s", value)
+	}
+
+	ones, bits := network.Mask.Size()
+	if ones == bits {
+		return network.IP.String(), nil
+	}
+	minPrefix := MinIPv6BlockPrefix
+	if bits == net.IPv4len*8 {
+		minPrefix = MinIPv4BlockPrefix
+	}
+	if ones < minPrefix {
+		return "", fmt.Errorf("CIDR range %s is wider than /%d", value, minPrefix)
+	}
+	return network.String(), nil
+}
+
+// Block blocks an IP address or CIDR range, in the form returned by ParseIPBlock,
+// for duration
+func (b *IPBlocker) Block(ctx context.Context, block string, duration time.Duration) error {
+	expiresAt := b.now().Add(duration)
+
+	if b.redisClient != nil {
+		return b.redisClient.Set(ctx, IPBlockKeyPrefix+block, expiresAt.UTC().Format(time.RFC3339), duration).Err()
+	}
+
+	b.mu.Lock()
+	b.blocks[block] = expiresAt
+	b.mu.Unlock()
+	return nil
+}
+
+// Unblock lifts the block on an IP address or CIDR range. Addresses inside a blocked
+// range stay blocked until the range itself is unblocked.
+func (b *IPBlocker) Unblock(ctx context.Context, block string) error {
+	if b.redisClient != nil {
+		return b.redisClient.Del(ctx, IPBlockKeyPrefix+block).Err()
+	}
+
+	b.mu.Lock()
+	delete(b.blocks, block)
+	b.mu.Unlock()
+	return nil
+}
+
+// Restore activates the unexpired blocks recorded in the ip_blocks table for the rest
+// of their duration
+func (b *IPBlocker) Restore(ctx context.Context) error {
+	if b.db == nil {
+		return nil
+	}
+
+	b.restoreMu.Lock()
+	defer b.restoreMu.Unlock()
+	return b.restore(ctx)
+}
+
+// restore activates the recorded blocks. The caller holds restoreMu.
+func (b *IPBlocker) restore(ctx context.Context) error {
+	now := b.now()
+	b.restoredAt = now
+
+	var blocks []models.IPBlock
+	if err := b.db.WithContext(ctx).Where("expires_at > ?", now).Find(&blocks).Error; err != nil {
+		return err
+	}
+
+	for _, block := range blocks {
+		if err := b.Block(ctx, block.IP, block.ExpiresAt.Sub(now)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreIfDue restores the recorded blocks if they have not been restored in the
+// last ipBlockRestoreInterval. Concurrent checks do not wait for a restore in progress.
+func (b *IPBlocker) restoreIfDue(ctx context.Context) error {
+	if b.db == nil || !b.restoreMu.TryLock() {
+		return nil
+	}
+	defer b.restoreMu.Unlock()
+
+	if !b.restoredAt.IsZero() && b.now().Sub(b.restoredAt) < ipBlockRestoreInterval {
+		return nil
+	}
+	return b.restore(ctx)
+}
+
+// IsBlocked checks whether an IP address, or a range containing it, is blocked
+func (b *IPBlocker) IsBlocked(ctx context.Context, clientIP string) (bool, error) {
+	candidates := ipBlockCandidates(clientIP)
+	if len(candidates) == 0 {
+		return false, nil
+	}
+
+	if err := b.restoreIfDue(ctx); err != nil {
+		logger.FromContext(ctx).Error("Failed to restore IP blocks", err)
+	}
+
+	if b.redisClient != nil {
+		keys := make([]string, 0, len(candidates))
+		for _, candidate := range candidates {
+			keys = append(keys, IPBlockKeyPrefix+candidate)
+		}
+		// Every range that could contain the address is looked up at once, so ranges
+		// need no separate index and expire with their keys
+		count, err := b.redisClient.Exists(ctx, keys...).Result()
+		return count > 0, err
+	}
+
+	now := b.now()
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, candidate := range candidates {
+		if expiresAt, ok := b.blocks[candidate]; ok && now.Before(expiresAt) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ipBlockCandidates returns the address and every blockable range containing it, in
+// the form returned by ParseIPBlock
+func ipBlockCandidates(clientIP string) []string {
+	ip := net.ParseIP(clientIP)
+	if ip == nil {
+		return nil
+	}
+
+	bits, minPrefix := net.IPv6len*8, MinIPv6BlockPrefix
+	if v4 := ip.To4(); v4 != nil {
+		ip, bits, minPrefix = v4, net.IPv4len*8, MinIPv4BlockPrefix
+	}
+
+	candidates := []string{ip.String()}
+	for prefix := bits - 1; prefix >= minPrefix; prefix-- {
+		network := net.IPNet{IP: ip.Mask(net.CIDRMask(prefix, bits)), Mask: net.CIDRMask(prefix, bits)}
+		candidates = append(candidates, network.String())
+	}
+	return candidates
+}
+
+// IPBlockMiddleware rejects requests from blocked IP addresses and ranges. Requests
+// are let through when the block list cannot be checked.
+func (b *IPBlocker) IPBlockMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		blocked, err := b.IsBlocked(c.Request.Context(), c.ClientIP())
+		if err != nil {
+			logger.FromContext(c.Request.Context()).Error("Failed to check IP block list", err, logger.Fields{
+				"client_ip": c.ClientIP(),
+			})
+		}
+		if blocked {
+			errors.ErrIPBlocked.Response(c)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
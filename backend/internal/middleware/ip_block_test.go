@@ -0,0 +1,304 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// mockExpiringRedis is a go-redis hook that serves the key commands used by the IP
+// blocker and admin confirmer from memory, expiring keys against now instead of the
+// wall clock
+type mockExpiringRedis struct {
+	mu      sync.Mutex
+	values  map[string]string
+	expires map[string]time.Time
+	now     func() time.Time
+}
+
+func newMockExpiringRedisClient(now func() time.Time) (*redis.Client, *mockExpiringRedis) {
+	mock := &mockExpiringRedis{values: make(map[string]string), expires: make(map[string]time.Time), now: now}
+	client := redis.NewClient(&redis.Options{Addr: "mock:6379"})
+	client.AddHook(mock)
+	return client, mock
+}
+
+func (m *mockExpiringRedis) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, errors.New("mock redis does not dial")
+	}
+}
+
+func (m *mockExpiringRedis) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		m.process(cmd)
+		return cmd.Err()
+	}
+}
+
+func (m *mockExpiringRedis) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		for _, cmd := range cmds {
+			m.process(cmd)
+		}
+		return nil
+	}
+}
+
+func (m *mockExpiringRedis) process(cmd redis.Cmder) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	args := cmd.Args()
+	switch cmd.Name() {
+	case "set":
+		var ttl time.Duration
+		if len(args) > 4 && args[3] == "ex" {
+			ttl = time.Duration(args[4].(int64)) * time.Second
+		} else if len(args) > 4 && args[3] == "px" {
+			ttl = time.Duration(args[4].(int64)) * time.Millisecond
+		}
+		switch value := args[2].(type) {
+		case []byte:
+			m.values[args[1].(string)] = string(value)
+		default:
+			m.values[args[1].(string)] = fmt.Sprint(value)
+		}
+		m.expires[args[1].(string)] = m.now().Add(ttl)
+		cmd.(*redis.StatusCmd).SetVal("OK")
+	case "getdel":
+		key := args[1].(string)
+		value, ok := m.values[key]
+		expiresAt := m.expires[key]
+		delete(m.values, key)
+		delete(m.expires, key)
+		if !ok || !m.now().Before(expiresAt) {
+			cmd.SetErr(redis.Nil)
+			return
+		}
+		cmd.(*redis.StringCmd).SetVal(value)
+	case "exists":
+		var count int64
+		for _, arg := range args[1:] {
+			if expiresAt, ok := m.expires[arg.(string)]; ok && m.now().Before(expiresAt) {
+				count++
+			}
+		}
+		cmd.(*redis.IntCmd).SetVal(count)
+	case "del":
+		var deleted int64
+		for _, arg := range args[1:] {
+			if _, ok := m.expires[arg.(string)]; ok {
+				delete(m.values, arg.(string))
+				delete(m.expires, arg.(string))
+				deleted++
+			}
+		}
+		cmd.(*redis.IntCmd).SetVal(deleted)
+	default:
+		cmd.SetErr(fmt.Errorf("mock redis: unsupported command %s", cmd.Name()))
+	}
+}
+
+func TestParseIPBlock(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "203.0.113.7", want: "203.0.113.7"},
+		{value: " 2001:DB8::1 ", want: "2001:db8::1"},
+		{value: "192.168.1.1/16", want: "192.168.0.0/16"},
+		{value: "10.0.0.5/32", want: "10.0.0.5"},
+		{value: "2001:db8::/32", want: "2001:db8::/32"},
+		{value: "10.0.0.0/7", wantErr: true},
+		{value: "2001::/15", wantErr: true},
+		{value: "not-an-ip", wantErr: true},
+		{value: "10.0.0.0/33", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseIPBlock(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIPBlocker(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	clock := func() time.Time { return now }
+	redisClient, _ := newMockExpiringRedisClient(clock)
+
+	for _, backend := range []struct {
+		name        string
+		redisClient *redis.Client
+	}{
+		{"redis", redisClient},
+		{"in-memory", nil},
+	} {
+		t.Run(backend.name, func(t *testing.T) {
+			now = start
+			ctx := context.Background()
+			blocker := NewIPBlocker(backend.redisClient, nil)
+			blocker.now = clock
+
+			router := gin.New()
+			router.Use(blocker.IPBlockMiddleware())
+			router.GET("/test", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"message": "success"})
+			})
+
+			request := func(ip string) *httptest.ResponseRecorder {
+				req, _ := http.NewRequest("GET", "/test", nil)
+				req.RemoteAddr = net.JoinHostPort(ip, "12345")
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				return w
+			}
+
+			require.NoError(t, blocker.Block(ctx, "203.0.113.7", time.Hour))
+			require.NoError(t, blocker.Block(ctx, "192.168.0.0/16", 2*time.Hour))
+			require.NoError(t, blocker.Block(ctx, "2001:db8::/32", time.Hour))
+
+			t.Run("blocked addresses get 403", func(t *testing.T) {
+				w := request("203.0.113.7")
+				assert.Equal(t, http.StatusForbidden, w.Code)
+				assert.Contains(t, w.Body.String(), "IP_BLOCKED")
+
+				assert.Equal(t, http.StatusOK, request("203.0.113.8").Code)
+			})
+
+			t.Run("ranges match every address inside them", func(t *testing.T) {
+				assert.Equal(t, http.StatusForbidden, request("192.168.0.1").Code)
+				assert.Equal(t, http.StatusForbidden, request("192.168.255.254").Code)
+				assert.Equal(t, http.StatusOK, request("192.169.0.1").Code)
+
+				assert.Equal(t, http.StatusForbidden, request("2001:db8:1::42").Code)
+				assert.Equal(t, http.StatusOK, request("2001:db9::42").Code)
+			})
+
+			t.Run("blocks expire", func(t *testing.T) {
+				now = start.Add(90 * time.Minute)
+				assert.Equal(t, http.StatusOK, request("203.0.113.7").Code)
+				assert.Equal(t, http.StatusForbidden, request("192.168.10.10").Code)
+
+				now = start.Add(2 * time.Hour)
+				assert.Equal(t, http.StatusOK, request("192.168.10.10").Code)
+			})
+
+			t.Run("unblocking lifts the block", func(t *testing.T) {
+				now = start
+				require.NoError(t, blocker.Unblock(ctx, "192.168.0.0/16"))
+				assert.Equal(t, http.StatusOK, request("192.168.0.1").Code)
+			})
+		})
+	}
+}
+
+func TestIPBlocker_Restore(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	clock := func() time.Time { return now }
+	redisClient, mock := newMockExpiringRedisClient(clock)
+
+	for _, backend := range []struct {
+		name        string
+		redisClient *redis.Client
+	}{
+		{"redis", redisClient},
+		{"in-memory", nil},
+	} {
+		t.Run(backend.name, func(t *testing.T) {
+			now = start
+			ctx := context.Background()
+
+			db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+			require.NoError(t, err)
+			// The model's postgres defaults cannot be migrated on sqlite
+			require.NoError(t, db.Exec(`CREATE TABLE ip_blocks (
+				id TEXT PRIMARY KEY,
+				ip TEXT NOT NULL,
+				reason TEXT NOT NULL,
+				blocked_by_id TEXT NOT NULL,
+				expires_at DATETIME NOT NULL,
+				created_at DATETIME
+			)`).Error)
+			require.NoError(t, db.Create(&[]models.IPBlock{
+				{IP: "203.0.113.7", Reason: "Spam", BlockedByID: uuid.New(), ExpiresAt: start.Add(time.Hour)},
+				{IP: "192.168.0.0/16", Reason: "Scraping", BlockedByID: uuid.New(), ExpiresAt: start.Add(-time.Minute)},
+			}).Error)
+
+			// A restarted server restores the recorded blocks
+			blocker := NewIPBlocker(backend.redisClient, db)
+			blocker.now = clock
+			require.NoError(t, blocker.Restore(ctx))
+
+			blocked, err := blocker.IsBlocked(ctx, "203.0.113.7")
+			require.NoError(t, err)
+			assert.True(t, blocked)
+
+			blocked, err = blocker.IsBlocked(ctx, "192.168.0.1")
+			require.NoError(t, err)
+			assert.False(t, blocked, "expired blocks are not restored")
+
+			// Lost blocks come back within a minute
+			mock.mu.Lock()
+			mock.values = make(map[string]string)
+			mock.expires = make(map[string]time.Time)
+			mock.mu.Unlock()
+			blocker.mu.Lock()
+			blocker.blocks = make(map[string]time.Time)
+			blocker.mu.Unlock()
+
+			now = start.Add(30 * time.Second)
+			blocked, err = blocker.IsBlocked(ctx, "203.0.113.7")
+			require.NoError(t, err)
+			assert.False(t, blocked)
+
+			now = start.Add(time.Minute)
+			blocked, err = blocker.IsBlocked(ctx, "203.0.113.7")
+			require.NoError(t, err)
+			assert.True(t, blocked)
+
+			// Restored blocks keep their expiry
+			now = start.Add(time.Hour)
+			blocked, err = blocker.IsBlocked(ctx, "203.0.113.7")
+			require.NoError(t, err)
+			assert.False(t, blocked)
+
+			// Blocks whose record is deleted are not restored
+			now = start
+			require.NoError(t, db.Where("ip = ?", "203.0.113.7").Delete(&models.IPBlock{}).Error)
+			require.NoError(t, blocker.Unblock(ctx, "203.0.113.7"))
+			now = start.Add(2 * time.Minute)
+			blocked, err = blocker.IsBlocked(ctx, "203.0.113.7")
+			require.NoError(t, err)
+			assert.False(t, blocked)
+		})
+	}
+}
@@ -26,6 +26,10 @@ func RequestLoggingMiddleware() gin.HandlerFunc {
 		c.Set("request_id", requestID)
 		c.Header("X-Request-ID", requestID)
 
+		// Give the rest of the chain a logger that tags its entries with the request ID
+		requestLogger := logger.FromContext(c.Request.Context()).With(logger.Fields{"request_id": requestID})
+		c.Request = c.Request.WithContext(logger.NewContext(c.Request.Context(), requestLogger))
+
 		// Start timer
 		start := time.Now()
 
@@ -127,9 +131,11 @@ func (w bodyLogWriter) Write(b []byte) (int, error) {
 	return w.ResponseWriter.Write(b)
 }
 
-// ErrorLoggingMiddleware logs panics and errors
+// ErrorLoggingMiddleware logs panics and errors, and reports panics to Sentry
 func ErrorLoggingMiddleware() gin.HandlerFunc {
 	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
+		capturePanic(c, recovered)
+
 		logger.WithRequest(c).WithFields(logger.Fields{
 			"panic": recovered,
 		}).Error("Panic recovered")
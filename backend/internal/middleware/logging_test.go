@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bugrelay-backend/internal/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLoggingMiddleware_InjectsRequestLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(RequestLoggingMiddleware())
+	router.GET("/test", func(c *gin.Context) {
+		logger.FromContext(c.Request.Context()).Info("handled", logger.Fields{"step": "handler"})
+		c.Status(http.StatusOK)
+	})
+
+	testLogger := logger.NewTestLogger()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	req = req.WithContext(logger.NewContext(req.Context(), testLogger))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	requestID := w.Header().Get("X-Request-ID")
+	require.NotEmpty(t, requestID)
+
+	entries := testLogger.EntriesWithMessage("handled")
+	require.Len(t, entries, 1)
+	assert.Equal(t, logger.Fields{"request_id": requestID, "step": "handler"}, entries[0].Fields)
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strconv"
+	"time"
+
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsTokenHeader carries the shared secret that authorizes scraping /metrics
+const MetricsTokenHeader = "X-Metrics-Token"
+
+// MetricsMiddleware records the count and duration of requests. Requests are
+// labelled with their route pattern rather than their URL, so /bugs/:id is one
+// series however many bugs are viewed.
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		path := c.FullPath()
+		if path == "" {
+			path = metrics.UnmatchedPath
+		}
+		method := c.Request.Method
+
+		metrics.HTTPRequestsTotal.WithLabelValues(method, path, strconv.Itoa(c.Writer.Status())).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(method, path).Observe(time.Since(start).Seconds())
+	}
+}
+
+// RequireMetricsToken only lets through requests whose X-Metrics-Token header
+// matches token
+func RequireMetricsToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided := c.GetHeader(MetricsTokenHeader)
+		if token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			errors.ErrUnauthorized.WithMessage("Invalid metrics token").Response(c)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
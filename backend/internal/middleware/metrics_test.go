@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bugrelay-backend/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	router := setupTestRouter()
+	router.Use(MetricsMiddleware())
+	router.GET("/bugs/:id", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"id": c.Param("id")})
+	})
+
+	requests := func(path, status string) float64 {
+		return testutil.ToFloat64(metrics.HTTPRequestsTotal.WithLabelValues("GET", path, status))
+	}
+	before := requests("/bugs/:id", "200")
+	beforeUnmatched := requests(metrics.UnmatchedPath, "404")
+
+	for _, path := range []string{"/bugs/1", "/bugs/2", "/nope"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+	}
+
+	// Requests are labelled with the route pattern, not the URL
+	assert.Equal(t, before+2, requests("/bugs/:id", "200"))
+	assert.Equal(t, beforeUnmatched+1, requests(metrics.UnmatchedPath, "404"))
+	assert.Equal(t, 0.0, requests("/bugs/1", "200"))
+}
+
+func TestRequireMetricsToken(t *testing.T) {
+	router := setupTestRouter()
+	router.GET("/metrics", RequireMetricsToken("secret"), func(c *gin.Context) {
+		c.String(http.StatusOK, "metrics")
+	})
+
+	tests := []struct {
+		name           string
+		token          string
+		expectedStatus int
+	}{
+		{"valid token", "secret", http.StatusOK},
+		{"wrong token", "guess", http.StatusUnauthorized},
+		{"missing token", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/metrics", nil)
+			if tt.token != "" {
+				req.Header.Set(MetricsTokenHeader, tt.token)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+
+	// An empty configured token lets no one in
+	router = setupTestRouter()
+	router.GET("/metrics", RequireMetricsToken(""), func(c *gin.Context) {
+		c.String(http.StatusOK, "metrics")
+	})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,215 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultStripNullsMaxBytes is the largest response NullFieldStripperMiddleware
+// processes by default
+const DefaultStripNullsMaxBytes = 512 << 10
+
+// NullStripperConfig configures which null fields NullFieldStripperMiddleware removes
+type NullStripperConfig struct {
+	// Keys limits stripping to fields with these names. When empty every null
+	// field is stripped.
+	Keys []string
+	// KeepKeys are fields that stay in the response when null, for clients that
+	// need to tell null apart from missing
+	KeepKeys []string
+	// MaxBytes is the largest response processed. Larger responses are sent as is.
+	MaxBytes int
+}
+
+// shouldStrip reports whether a null field named key is removed
+func (c NullStripperConfig) shouldStrip() func(key string) bool {
+	only := make(map[string]bool, len(c.Keys))
+	for _, key := range c.Keys {
+		only[key] = true
+	}
+	keep := make(map[string]bool, len(c.KeepKeys))
+	for _, key := range c.KeepKeys {
+		keep[key] = true
+	}
+
+	return func(key string) bool {
+		if keep[key] {
+			return false
+		}
+		return len(only) == 0 || only[key]
+	}
+}
+
+// NullFieldStripperMiddleware removes null fields from JSON responses, which GORM
+// models' optional pointer fields fill with. Field order and number formatting are
+// kept. Other content types, responses above MaxBytes and bodies that fail to parse
+// are sent unchanged.
+func NullFieldStripperMiddleware(config NullStripperConfig) gin.HandlerFunc {
+	if config.MaxBytes <= 0 {
+		config.MaxBytes = DefaultStripNullsMaxBytes
+	}
+	strip := config.shouldStrip()
+
+	return func(c *gin.Context) {
+		writer := &nullStripperWriter{ResponseWriter: c.Writer, maxBytes: config.MaxBytes}
+		c.Writer = writer
+		defer func() {
+			c.Writer = writer.ResponseWriter
+		}()
+
+		c.Next()
+
+		if writer.passthrough || writer.body.Len() == 0 {
+			return
+		}
+
+		body := writer.body.Bytes()
+		if stripped, err := stripNullFields(body, strip); err == nil {
+			body = stripped
+		}
+		if writer.Header().Get("Content-Length") != "" {
+			writer.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		}
+		writer.ResponseWriter.Write(body)
+	}
+}
+
+// nullStripperWriter holds back JSON response bodies so null fields can be removed
+// once the handler has finished
+type nullStripperWriter struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	maxBytes int
+	// decided is set on the first write, when the content type is known
+	decided bool
+	// passthrough writes straight to the response, for bodies that are not
+	// processed
+	passthrough bool
+}
+
+func (w *nullStripperWriter) Write(b []byte) (int, error) {
+	if !w.decided {
+		w.decided = true
+		mediaType, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type"))
+		w.passthrough = mediaType != "application/json"
+	}
+	if w.passthrough {
+		return w.ResponseWriter.Write(b)
+	}
+
+	if w.body.Len()+len(b) > w.maxBytes {
+		// Too large to process, so send what was held back and the rest as is
+		w.passthrough = true
+		if _, err := w.ResponseWriter.Write(w.body.Bytes()); err != nil {
+			return 0, err
+		}
+		w.body.Reset()
+		return w.ResponseWriter.Write(b)
+	}
+	return w.body.Write(b)
+}
+
+func (w *nullStripperWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// stripNullFields rewrites a JSON document without the object fields whose value is
+// null and whose name strip accepts. Null array elements are kept.
+func stripNullFields(body []byte, strip func(key string) bool) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var out bytes.Buffer
+	out.Grow(len(body))
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	if err := writeStrippedValue(decoder, &out, token, strip); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err == nil {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+
+	// Keep the trailing newline encoders add
+	if bytes.HasSuffix(body, []byte("\n")) {
+		out.WriteByte('\n')
+	}
+	return out.Bytes(), nil
+}
+
+// writeStrippedValue writes the value starting with token, reading the rest of an
+// object or array from decoder
+func writeStrippedValue(decoder *json.Decoder, out *bytes.Buffer, token json.Token, strip func(key string) bool) error {
+	switch value := token.(type) {
+	case json.Delim:
+		closing := json.Delim('}')
+		if value == '[' {
+			closing = ']'
+		}
+		out.WriteByte(byte(value))
+
+		first := true
+		for decoder.More() {
+			var key string
+			if value == '{' {
+				keyToken, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+				key = keyToken.(string)
+			}
+
+			element, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			if value == '{' && element == nil && strip(key) {
+				continue
+			}
+
+			if !first {
+				out.WriteByte(',')
+			}
+			first = false
+			if value == '{' {
+				if err := writeJSONString(out, key); err != nil {
+					return err
+				}
+				out.WriteByte(':')
+			}
+			if err := writeStrippedValue(decoder, out, element, strip); err != nil {
+				return err
+			}
+		}
+
+		if _, err := decoder.Token(); err != nil {
+			return err
+		}
+		out.WriteByte(byte(closing))
+	case string:
+		return writeJSONString(out, value)
+	case json.Number:
+		out.WriteString(value.String())
+	case bool:
+		out.WriteString(strconv.FormatBool(value))
+	case nil:
+		out.WriteString("null")
+	}
+	return nil
+}
+
+func writeJSONString(out *bytes.Buffer, s string) error {
+	encoded, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	out.Write(encoded)
+	return nil
+}
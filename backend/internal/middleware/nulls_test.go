@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveWithNullStripper returns the response body of handler with and without the
+// null stripper
+func serveWithNullStripper(t *testing.T, config NullStripperConfig, handler gin.HandlerFunc) (original, stripped *httptest.ResponseRecorder) {
+	serve := func(middleware ...gin.HandlerFunc) *httptest.ResponseRecorder {
+		router := setupTestRouter()
+		router.Use(middleware...)
+		router.GET("/test", handler)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+		return w
+	}
+	return serve(), serve(NullFieldStripperMiddleware(config))
+}
+
+// withoutNulls removes null object fields from a decoded JSON value, except keep
+func withoutNulls(value interface{}, keep map[string]bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{})
+		for key, field := range v {
+			if field == nil && !keep[key] {
+				continue
+			}
+			result[key] = withoutNulls(field, keep)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, element := range v {
+			result[i] = withoutNulls(element, keep)
+		}
+		return result
+	}
+	return value
+}
+
+func TestNullFieldStripperMiddleware(t *testing.T) {
+	// Mirrors a bug response whose optional fields are serialized as null
+	type bugResponse struct {
+		ID          uuid.UUID    `json:"id"`
+		Title       string       `json:"title"`
+		Description string       `json:"description"`
+		ReporterID  *uuid.UUID   `json:"reporter_id"`
+		Reporter    *models.User `json:"reporter"`
+		AssignedTo  *uuid.UUID   `json:"assigned_to"`
+		VoteCount   int          `json:"vote_count"`
+		Tags        []string     `json:"tags"`
+		CreatedAt   time.Time    `json:"created_at"`
+		ResolvedAt  *time.Time   `json:"resolved_at"`
+	}
+	bug := bugResponse{
+		ID:          uuid.New(),
+		Title:       "Crash on save",
+		Description: "The editor crashes when saving <large> files & folders",
+		VoteCount:   12345678901,
+		Tags:        []string{"editor", "crash"},
+		CreatedAt:   time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+	}
+	handler := func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{
+			"bug":     bug,
+			"related": []interface{}{nil, gin.H{"note": nil, "by": "release 1.2"}},
+		})
+	}
+	keep := []string{"resolved_at", "reporter"}
+
+	original, stripped := serveWithNullStripper(t, NullStripperConfig{KeepKeys: keep}, handler)
+	require.Equal(t, http.StatusCreated, stripped.Code)
+	assert.Equal(t, original.Header().Get("Content-Type"), stripped.Header().Get("Content-Type"))
+	assert.Less(t, stripped.Body.Len(), original.Body.Len())
+	t.Logf("response shrank from %d to %d bytes", original.Body.Len(), stripped.Body.Len())
+
+	var before, after map[string]interface{}
+	require.NoError(t, json.Unmarshal(original.Body.Bytes(), &before))
+	require.NoError(t, json.Unmarshal(stripped.Body.Bytes(), &after))
+
+	// Nothing but the null fields is lost
+	assert.Equal(t, withoutNulls(before, map[string]bool{"resolved_at": true, "reporter": true}), after)
+
+	strippedBug := after["bug"].(map[string]interface{})
+	assert.Contains(t, strippedBug, "resolved_at")
+	assert.Nil(t, strippedBug["resolved_at"])
+	assert.Contains(t, strippedBug, "reporter")
+	assert.NotContains(t, strippedBug, "reporter_id")
+	assert.NotContains(t, strippedBug, "assigned_to")
+
+	// Null array elements are kept, and fields stay in their original order
+	assert.Nil(t, after["related"].([]interface{})[0])
+	assert.Less(t, strings.Index(stripped.Body.String(), `"id"`), strings.Index(stripped.Body.String(), `"title"`))
+	assert.Contains(t, stripped.Body.String(), `"vote_count":12345678901`)
+	assert.Contains(t, stripped.Body.String(), `\u003clarge\u003e files \u0026 folders`)
+}
+
+func TestNullFieldStripperMiddleware_OnlyConfiguredKeys(t *testing.T) {
+	handler := func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"avatar_url": nil, "reporter_id": nil, "name": "Jane"})
+	}
+
+	_, stripped := serveWithNullStripper(t, NullStripperConfig{Keys: []string{"avatar_url"}}, handler)
+	assert.JSONEq(t, `{"reporter_id":null,"name":"Jane"}`, stripped.Body.String())
+
+	_, stripped = serveWithNullStripper(t, NullStripperConfig{Keys: []string{"avatar_url"}, KeepKeys: []string{"avatar_url"}}, handler)
+	assert.JSONEq(t, `{"avatar_url":null,"reporter_id":null,"name":"Jane"}`, stripped.Body.String())
+}
+
+func TestNullFieldStripperMiddleware_SendsOtherResponsesUnchanged(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  NullStripperConfig
+		handler gin.HandlerFunc
+	}{
+		{
+			name: "non-JSON content type",
+			handler: func(c *gin.Context) {
+				c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(`{"value":null}`))
+			},
+		},
+		{
+			name: "invalid JSON",
+			handler: func(c *gin.Context) {
+				c.Data(http.StatusOK, "application/json", []byte(`{"value":null`))
+			},
+		},
+		{
+			name: "multiple JSON values",
+			handler: func(c *gin.Context) {
+				c.Data(http.StatusOK, "application/json", []byte(`{"value":null}{"value":null}`))
+			},
+		},
+		{
+			name:   "response above the size limit",
+			config: NullStripperConfig{MaxBytes: 1024},
+			handler: func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"value": nil, "data": strings.Repeat("a", 2048)})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original, stripped := serveWithNullStripper(t, tt.config, tt.handler)
+			assert.Equal(t, original.Code, stripped.Code)
+			assert.Equal(t, original.Body.String(), stripped.Body.String())
+		})
+	}
+}
+
+func TestNullFieldStripperMiddleware_UpdatesContentLength(t *testing.T) {
+	body := []byte(`{"value":null,"name":"Jane"}`)
+	handler := func(c *gin.Context) {
+		c.Header("Content-Length", strconv.Itoa(len(body)))
+		c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+	}
+
+	_, stripped := serveWithNullStripper(t, NullStripperConfig{}, handler)
+	assert.Equal(t, `{"name":"Jane"}`, stripped.Body.String())
+	assert.Equal(t, strconv.Itoa(stripped.Body.Len()), stripped.Header().Get("Content-Length"))
+}
@@ -1,19 +1,55 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
-	"net/http"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"bugrelay-backend/internal/errors"
+	"bugrelay-backend/internal/geoip"
+
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"golang.org/x/time/rate"
 )
 
+// RateLimitExemptKeyPrefix is the Redis key prefix for temporary per-user rate limit exemptions
+const RateLimitExemptKeyPrefix = "ratelimit:exempt:"
+
+// GeoLimitDefault is the GeoRateLimit country limits key for countries without their
+// own limit, and for private or unknown IPs
+const GeoLimitDefault = "default"
+
 // RateLimiter provides rate limiting functionality
 type RateLimiter struct {
 	redisClient *redis.Client
 	limiter     *rate.Limiter
+
+	// In-memory exemptions used when Redis is not available
+	exemptMu   sync.RWMutex
+	exemptions map[string]time.Time
+
+	// In-memory request logs for sliding windows when Redis is not available. Logs of
+	// clients that have gone idle are swept at most every windowSweepInterval.
+	windowMu    sync.Mutex
+	windows     map[string]slidingWindowLog
+	windowSwept time.Time
+
+	// In-memory per-user counters when Redis is not available
+	counterMu sync.Mutex
+	counters  map[string]fixedWindowCounter
+
+	// Country lookup for GeoRateLimit, and whether the X-Country header hides it
+	geoLookup     GeoIPLookup
+	redactCountry bool
+
+	now func() time.Time
 }
 
 // NewRateLimiter creates a new rate limiter
@@ -21,12 +57,72 @@ func NewRateLimiter(redisClient *redis.Client, requestsPerMinute int) *RateLimit
 	return &RateLimiter{
 		redisClient: redisClient,
 		limiter:     rate.NewLimiter(rate.Every(time.Minute/time.Duration(requestsPerMinute)), requestsPerMinute),
+		exemptions:  make(map[string]time.Time),
+		windows:     make(map[string]slidingWindowLog),
+		counters:    make(map[string]fixedWindowCounter),
+		now:         time.Now,
+	}
+}
+
+// SetGeoIPLookup sets the lookup GeoRateLimit uses to find a client's country. Without
+// one every client gets the default limit.
+func (rl *RateLimiter) SetGeoIPLookup(lookup GeoIPLookup) {
+	rl.geoLookup = lookup
+}
+
+// SetRedactCountryHeader hides the country code in GeoRateLimit's X-Country header
+func (rl *RateLimiter) SetRedactCountryHeader(redact bool) {
+	rl.redactCountry = redact
+}
+
+// AddExemption exempts a user from rate limiting for the given duration
+func (rl *RateLimiter) AddExemption(ctx context.Context, userID string, duration time.Duration) error {
+	expiresAt := time.Now().Add(duration)
+
+	if rl.redisClient != nil {
+		return rl.redisClient.Set(ctx, RateLimitExemptKeyPrefix+userID, expiresAt.UTC().Format(time.RFC3339), duration).Err()
+	}
+
+	rl.exemptMu.Lock()
+	rl.exemptions[userID] = expiresAt
+	rl.exemptMu.Unlock()
+	return nil
+}
+
+// IsExempt checks whether a user currently has a rate limit exemption
+func (rl *RateLimiter) IsExempt(ctx context.Context, userID string) bool {
+	if rl.redisClient != nil {
+		exists, err := rl.redisClient.Exists(ctx, RateLimitExemptKeyPrefix+userID).Result()
+		return err == nil && exists > 0
+	}
+
+	rl.exemptMu.RLock()
+	expiresAt, exists := rl.exemptions[userID]
+	rl.exemptMu.RUnlock()
+	return exists && time.Now().Before(expiresAt)
+}
+
+// RateLimitBypassMiddleware applies the given rate limiter to everyone except admins,
+// who skip it so they can make rapid bulk changes
+func RateLimitBypassMiddleware(limiter gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if IsCurrentUserAdmin(c) {
+			c.Next()
+			return
+		}
+		limiter(c)
 	}
 }
 
 // RateLimit middleware that limits requests per IP
 func (rl *RateLimiter) RateLimit(requestsPerMinute int) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// Users with a temporary exemption are not counted
+		if userID, exists := GetCurrentUserID(c); exists && rl.IsExempt(c.Request.Context(), userID) {
+			c.Next()
+			return
+		}
+
 		clientIP := c.ClientIP()
 		key := fmt.Sprintf("rate_limit:%s", clientIP)
 
@@ -39,13 +135,7 @@ func (rl *RateLimiter) RateLimit(requestsPerMinute int) gin.HandlerFunc {
 			if err != nil && err.Error() != "redis: nil" {
 				// Redis error, fall back to in-memory limiter
 				if !rl.limiter.Allow() {
-					c.JSON(http.StatusTooManyRequests, gin.H{
-						"error": gin.H{
-							"code":      "RATE_LIMIT_EXCEEDED",
-							"message":   "Too many requests, please try again later",
-							"timestamp": time.Now().UTC(),
-						},
-					})
+					errors.ErrRateLimitExceeded.Response(c)
 					c.Abort()
 					return
 				}
@@ -54,13 +144,7 @@ func (rl *RateLimiter) RateLimit(requestsPerMinute int) gin.HandlerFunc {
 			}
 
 			if current >= requestsPerMinute {
-				c.JSON(http.StatusTooManyRequests, gin.H{
-					"error": gin.H{
-						"code":      "RATE_LIMIT_EXCEEDED",
-						"message":   "Too many requests, please try again later",
-						"timestamp": time.Now().UTC(),
-					},
-				})
+				errors.ErrRateLimitExceeded.Response(c)
 				c.Abort()
 				return
 			}
@@ -78,13 +162,7 @@ func (rl *RateLimiter) RateLimit(requestsPerMinute int) gin.HandlerFunc {
 		} else {
 			// Use in-memory rate limiter
 			if !rl.limiter.Allow() {
-				c.JSON(http.StatusTooManyRequests, gin.H{
-					"error": gin.H{
-						"code":      "RATE_LIMIT_EXCEEDED",
-						"message":   "Too many requests, please try again later",
-						"timestamp": time.Now().UTC(),
-					},
-				})
+				errors.ErrRateLimitExceeded.Response(c)
 				c.Abort()
 				return
 			}
@@ -105,3 +183,350 @@ func (rl *RateLimiter) GeneralRateLimit() gin.HandlerFunc {
 	return rl.RateLimit(60000) // 60 requests per minute per IP
 }
 
+// slidingWindowResult is the state of a client's sliding window after a request
+type slidingWindowResult struct {
+	allowed bool
+	count   int       // requests in the window, including this one if allowed
+	oldest  time.Time // when the oldest request in the window was made
+}
+
+// SlidingWindowLimiter limits each IP to maxRequests within any windowSeconds long
+// period. Unlike RateLimit's fixed window it does not allow bursts at window
+// boundaries. Request timestamps are kept in a Redis sorted set per IP, or in memory
+// when Redis is not available.
+func (rl *RateLimiter) SlidingWindowLimiter(windowSeconds, maxRequests int) gin.HandlerFunc {
+	window := time.Duration(windowSeconds) * time.Second
+
+	return func(c *gin.Context) {
+		// Users with a temporary exemption are not counted
+		if userID, exists := GetCurrentUserID(c); exists && rl.IsExempt(c.Request.Context(), userID) {
+			c.Next()
+			return
+		}
+
+		key := fmt.Sprintf("rate_limit:sliding:%d:%d:%s", windowSeconds, maxRequests, c.ClientIP())
+		rl.enforceSlidingWindow(c, key, window, maxRequests)
+	}
+}
+
+// UserSlidingWindowLimiter limits each user to maxRequests within any windowSeconds
+// long period on the route, for expensive requests such as exports. It must run
+// after authentication; requests without a user are limited by IP.
+func (rl *RateLimiter) UserSlidingWindowLimiter(windowSeconds, maxRequests int) gin.HandlerFunc {
+	window := time.Duration(windowSeconds) * time.Second
+
+	return func(c *gin.Context) {
+		userID, exists := GetCurrentUserID(c)
+
+		// Users with a temporary exemption are not counted
+		if exists && rl.IsExempt(c.Request.Context(), userID) {
+			c.Next()
+			return
+		}
+
+		client := "ip:" + c.ClientIP()
+		if exists {
+			client = "user:" + userID
+		}
+		key := fmt.Sprintf("rate_limit:user:%s:%d:%d:%s", c.FullPath(), windowSeconds, maxRequests, client)
+		rl.enforceSlidingWindow(c, key, window, maxRequests)
+	}
+}
+
+// PerUserRateLimit limits each authenticated user to requestsPerMinute requests.
+// They are counted per user rather than per IP, so users behind a shared address do
+// not limit each other. Anonymous requests are passed to the anonymous limiter. It
+// must run after OptionalAuth.
+func (rl *RateLimiter) PerUserRateLimit(requestsPerMinute int, anonymous gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := GetCurrentUserID(c)
+		if !exists {
+			anonymous(c)
+			return
+		}
+
+		rl.enforceUserLimit(c, userID, "user:"+userID+":rate", requestsPerMinute)
+	}
+}
+
+// WriteRateLimit limits each authenticated user to requestsPerMinute writes, such as
+// bug submissions, votes and comments, counted across every route it is added to.
+// Anonymous requests are not counted. It must run after OptionalAuth or RequireAuth.
+func (rl *RateLimiter) WriteRateLimit(requestsPerMinute int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := GetCurrentUserID(c)
+		if !exists {
+			c.Next()
+			return
+		}
+
+		rl.enforceUserLimit(c, userID, "user:"+userID+":write_rate", requestsPerMinute)
+	}
+}
+
+// fixedWindowCounter is an in-memory per-user request count
+type fixedWindowCounter struct {
+	count   int
+	resetAt time.Time
+}
+
+// enforceUserLimit counts the request in key's one minute window, setting the rate
+// limit headers, and aborts with RATE_LIMIT_EXCEEDED once the user has made more than
+// maxRequests. Users with an exemption are not counted.
+func (rl *RateLimiter) enforceUserLimit(c *gin.Context, userID, key string, maxRequests int) {
+	ctx := c.Request.Context()
+	if rl.IsExempt(ctx, userID) {
+		c.Next()
+		return
+	}
+
+	var count int
+	var resetIn time.Duration
+	var err error
+	if rl.redisClient != nil {
+		count, resetIn, err = rl.fixedWindowRedis(ctx, key, time.Minute)
+	}
+	if rl.redisClient == nil || err != nil {
+		// Redis unavailable or failing, fall back to the in-memory counter
+		count, resetIn = rl.fixedWindowMemory(key, time.Minute)
+	}
+
+	c.Header("X-RateLimit-Limit", strconv.Itoa(maxRequests))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(max(maxRequests-count, 0)))
+
+	if count > maxRequests {
+		retryAfter := int(math.Ceil(resetIn.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+		errors.ErrRateLimitExceeded.Response(c)
+		c.Abort()
+		return
+	}
+
+	c.Next()
+}
+
+// fixedWindowRedis increments key, starting its window with EXPIRE on the first
+// request, and returns the count and the time until the window resets
+func (rl *RateLimiter) fixedWindowRedis(ctx context.Context, key string, window time.Duration) (int, time.Duration, error) {
+	pipe := rl.redisClient.TxPipeline()
+	countCmd := pipe.Incr(ctx, key)
+	ttlCmd := pipe.TTL(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, 0, err
+	}
+
+	resetIn := ttlCmd.Val()
+	if resetIn < 0 {
+		// The first request of the window, or one whose EXPIRE failed
+		if err := rl.redisClient.Expire(ctx, key, window).Err(); err != nil {
+			return 0, 0, err
+		}
+		resetIn = window
+	}
+	return int(countCmd.Val()), resetIn, nil
+}
+
+// fixedWindowMemory is the in-memory equivalent of fixedWindowRedis
+func (rl *RateLimiter) fixedWindowMemory(key string, window time.Duration) (int, time.Duration) {
+	rl.counterMu.Lock()
+	defer rl.counterMu.Unlock()
+
+	now := rl.now()
+	counter := rl.counters[key]
+	if !now.Before(counter.resetAt) {
+		counter = fixedWindowCounter{resetAt: now.Add(window)}
+	}
+	counter.count++
+	rl.counters[key] = counter
+	return counter.count, counter.resetAt.Sub(now)
+}
+
+// GeoRateLimit limits each IP to the per-minute request limit of its country. Countries
+// without a limit, and private or unknown IPs, use the GeoLimitDefault entry; when
+// there is none they are not limited. The country is returned in the X-Country
+// header, redacted if SetRedactCountryHeader is set.
+func (rl *RateLimiter) GeoRateLimit(countryLimits map[string]int) gin.HandlerFunc {
+	limits := make(map[string]int, len(countryLimits))
+	for country, limit := range countryLimits {
+		if country != GeoLimitDefault {
+			country = strings.ToUpper(country)
+		}
+		limits[country] = limit
+	}
+
+	return func(c *gin.Context) {
+		country := rl.clientCountry(c)
+		switch {
+		case rl.redactCountry:
+			c.Header("X-Country", "redacted")
+		case country != "":
+			c.Header("X-Country", country)
+		default:
+			c.Header("X-Country", "unknown")
+		}
+
+		// Users with a temporary exemption are not counted
+		if userID, exists := GetCurrentUserID(c); exists && rl.IsExempt(c.Request.Context(), userID) {
+			c.Next()
+			return
+		}
+
+		limitKey := country
+		limit, ok := limits[country]
+		if !ok || country == "" {
+			limitKey = GeoLimitDefault
+			limit, ok = limits[GeoLimitDefault]
+		}
+		if !ok {
+			c.Next()
+			return
+		}
+
+		// Each route has its own window, so registering does not use up bug submissions
+		key := fmt.Sprintf("rate_limit:geo:%s:%s:%d:%s", c.FullPath(), limitKey, limit, c.ClientIP())
+		rl.enforceSlidingWindow(c, key, time.Minute, limit)
+	}
+}
+
+// clientCountry returns the country code of the client's IP, or an empty string for
+// private IPs and IPs that cannot be located
+func (rl *RateLimiter) clientCountry(c *gin.Context) string {
+	ip := net.ParseIP(c.ClientIP())
+	if rl.geoLookup == nil || !geoip.IsPublic(ip) {
+		return ""
+	}
+
+	country, err := rl.geoLookup.CountryCode(ip)
+	if err != nil {
+		// Lookup failures are treated like unknown IPs rather than failing the request
+		return ""
+	}
+	return strings.ToUpper(country)
+}
+
+// enforceSlidingWindow records the request in key's sliding window, setting the rate
+// limit headers, and aborts with RATE_LIMIT_EXCEEDED when the window is full
+func (rl *RateLimiter) enforceSlidingWindow(c *gin.Context, key string, window time.Duration, maxRequests int) {
+	now := rl.now()
+
+	var result slidingWindowResult
+	var err error
+	if rl.redisClient != nil {
+		result, err = rl.slidingWindowRedis(c.Request.Context(), key, now, window, maxRequests)
+	}
+	if rl.redisClient == nil || err != nil {
+		// Redis unavailable or failing, fall back to the in-memory window
+		result = rl.slidingWindowMemory(key, now, window, maxRequests)
+	}
+
+	resetAt := result.oldest.Add(window)
+	c.Header("X-RateLimit-Limit", strconv.Itoa(maxRequests))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(max(maxRequests-result.count, 0)))
+	c.Header("X-RateLimit-Reset", strconv.FormatInt(resetAt.Unix(), 10))
+
+	if !result.allowed {
+		retryAfter := int(math.Ceil(resetAt.Sub(now).Seconds()))
+		c.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+		errors.ErrRateLimitExceeded.Response(c)
+		c.Abort()
+		return
+	}
+
+	c.Next()
+}
+
+// slidingWindowRedis records a request in the key's sorted set, scored by time in
+// microseconds, unless the window is already full. The request is added and the
+// window counted in one transaction, so concurrent requests each see a distinct
+// count; a request that overflows the window removes itself again.
+func (rl *RateLimiter) slidingWindowRedis(ctx context.Context, key string, now time.Time, window time.Duration, maxRequests int) (slidingWindowResult, error) {
+	windowStart := now.Add(-window).UnixMicro()
+	member := uuid.NewString()
+
+	pipe := rl.redisClient.TxPipeline()
+	pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(windowStart, 10))
+	pipe.ZAdd(ctx, key, redis.Z{Score: float64(now.UnixMicro()), Member: member})
+	countCmd := pipe.ZCard(ctx, key)
+	oldestCmd := pipe.ZRangeWithScores(ctx, key, 0, 0)
+	pipe.Expire(ctx, key, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return slidingWindowResult{}, err
+	}
+
+	result := slidingWindowResult{
+		allowed: true,
+		count:   int(countCmd.Val()),
+		oldest:  now,
+	}
+	if oldest := oldestCmd.Val(); len(oldest) > 0 {
+		result.oldest = time.UnixMicro(int64(oldest[0].Score))
+	}
+
+	if result.count > maxRequests {
+		// Should the removal fail, the rejected request only counts until it expires
+		rl.redisClient.ZRem(ctx, key, member)
+		result.allowed = false
+		result.count--
+	}
+	return result, nil
+}
+
+// windowSweepInterval is how often idle in-memory sliding window logs are dropped
+const windowSweepInterval = time.Minute
+
+// slidingWindowLog is an in-memory sliding window, the times of the requests in it
+// and when the last of them leaves the window
+type slidingWindowLog struct {
+	requests  []time.Time
+	expiresAt time.Time
+}
+
+// slidingWindowMemory is the in-memory equivalent of slidingWindowRedis
+func (rl *RateLimiter) slidingWindowMemory(key string, now time.Time, window time.Duration, maxRequests int) slidingWindowResult {
+	rl.windowMu.Lock()
+	defer rl.windowMu.Unlock()
+
+	rl.sweepWindows(now)
+
+	windowStart := now.Add(-window)
+	requests := rl.windows[key].requests
+	for len(requests) > 0 && !requests[0].After(windowStart) {
+		requests = requests[1:]
+	}
+
+	result := slidingWindowResult{
+		count:  len(requests),
+		oldest: now,
+	}
+	if len(requests) > 0 {
+		result.oldest = requests[0]
+	}
+
+	if result.count < maxRequests {
+		requests = append(requests, now)
+		result.allowed = true
+		result.count++
+	}
+
+	if len(requests) == 0 {
+		delete(rl.windows, key)
+	} else {
+		rl.windows[key] = slidingWindowLog{requests: requests, expiresAt: requests[len(requests)-1].Add(window)}
+	}
+	return result
+}
+
+// sweepWindows drops the logs of clients with no requests left in their window, so
+// clients that stop making requests do not stay in memory. rl.windowMu must be held.
+func (rl *RateLimiter) sweepWindows(now time.Time) {
+	if now.Sub(rl.windowSwept) < windowSweepInterval {
+		return
+	}
+	rl.windowSwept = now
+
+	for key, log := range rl.windows {
+		if !log.expiresAt.After(now) {
+			delete(rl.windows, key)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockGeoIPLookup resolves countries from a fixed table and records the IPs looked up
+type mockGeoIPLookup struct {
+	countries map[string]string
+	err       error
+	lookedUp  []string
+}
+
+func (m *mockGeoIPLookup) CountryCode(ip net.IP) (string, error) {
+	m.lookedUp = append(m.lookedUp, ip.String())
+	if m.err != nil {
+		return "", m.err
+	}
+	return m.countries[ip.String()], nil
+}
+
+func TestGeoRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	lookup := &mockGeoIPLookup{countries: map[string]string{
+		"203.0.113.1": "CN",
+		"203.0.113.2": "DE",
+		"203.0.113.3": "cn",
+	}}
+
+	setup := func(limits map[string]int) (*RateLimiter, func(ip string) *httptest.ResponseRecorder) {
+		rateLimiter := NewRateLimiter(nil, 60)
+		rateLimiter.SetGeoIPLookup(lookup)
+		now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+		rateLimiter.now = func() time.Time { return now }
+
+		router := gin.New()
+		router.POST("/bugs", rateLimiter.GeoRateLimit(limits), func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"message": "success"})
+		})
+
+		return rateLimiter, func(ip string) *httptest.ResponseRecorder {
+			req, _ := http.NewRequest("POST", "/bugs", nil)
+			req.RemoteAddr = ip + ":12345"
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+	}
+
+	// allowed sends requests from ip until one is limited, returning how many succeeded
+	allowed := func(request func(string) *httptest.ResponseRecorder, ip string) int {
+		for i := 0; i < 100; i++ {
+			if w := request(ip); w.Code != http.StatusOK {
+				require.Equal(t, http.StatusTooManyRequests, w.Code)
+				assert.Contains(t, w.Body.String(), "RATE_LIMIT_EXCEEDED")
+				return i
+			}
+		}
+		return 100
+	}
+
+	t.Run("country limits", func(t *testing.T) {
+		_, request := setup(map[string]int{"CN": 2, "RU": 2, "default": 5})
+
+		w := request("203.0.113.1")
+		assert.Equal(t, "CN", w.Header().Get("X-Country"))
+		assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+		assert.Equal(t, 1, allowed(request, "203.0.113.1"))
+
+		// Country codes are matched regardless of case
+		assert.Equal(t, "CN", request("203.0.113.3").Header().Get("X-Country"))
+		assert.Equal(t, 1, allowed(request, "203.0.113.3"))
+
+		// Countries without their own limit use the default
+		assert.Equal(t, "DE", request("203.0.113.2").Header().Get("X-Country"))
+		assert.Equal(t, 4, allowed(request, "203.0.113.2"))
+	})
+
+	t.Run("private and unknown IPs use the default", func(t *testing.T) {
+		lookup.lookedUp = nil
+		_, request := setup(map[string]int{"CN": 2, "default": 3})
+
+		for _, ip := range []string{"10.0.0.1", "127.0.0.1", "192.168.1.20", "198.51.100.7"} {
+			w := request(ip)
+			assert.Equal(t, "unknown", w.Header().Get("X-Country"), ip)
+			assert.Equal(t, "3", w.Header().Get("X-RateLimit-Limit"), ip)
+			assert.Equal(t, 2, allowed(request, ip), ip)
+		}
+
+		// Private IPs are never looked up
+		for _, ip := range lookup.lookedUp {
+			assert.Equal(t, "198.51.100.7", ip)
+		}
+	})
+
+	t.Run("lookup failures use the default", func(t *testing.T) {
+		failing := &mockGeoIPLookup{err: errors.New("database closed")}
+		rateLimiter, request := setup(map[string]int{"CN": 1, "default": 2})
+		rateLimiter.SetGeoIPLookup(failing)
+
+		w := request("203.0.113.1")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "unknown", w.Header().Get("X-Country"))
+		assert.Equal(t, 1, allowed(request, "203.0.113.1"))
+	})
+
+	t.Run("no default leaves other countries unlimited", func(t *testing.T) {
+		_, request := setup(map[string]int{"CN": 1})
+
+		assert.Equal(t, 1, allowed(request, "203.0.113.1"))
+		w := request("203.0.113.2")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("X-RateLimit-Limit"))
+		assert.Equal(t, 100, allowed(request, "203.0.113.2"))
+	})
+
+	t.Run("country header is redacted", func(t *testing.T) {
+		rateLimiter, request := setup(map[string]int{"CN": 1, "default": 5})
+		rateLimiter.SetRedactCountryHeader(true)
+
+		w := request("203.0.113.1")
+		assert.Equal(t, "redacted", w.Header().Get("X-Country"))
+		assert.Equal(t, "1", w.Header().Get("X-RateLimit-Limit"))
+	})
+}
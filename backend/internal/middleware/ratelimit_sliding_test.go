@@ -0,0 +1,314 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSortedSetRedis is a go-redis hook that serves the sorted set commands used by
+// the sliding window limiter, and the counters of the per-user limits, from memory
+// instead of a Redis server
+type mockSortedSetRedis struct {
+	txMu     sync.Mutex    // held for a whole pipeline, as Redis runs MULTI atomically
+	latency  time.Duration // round trip time of each pipeline
+	mu       sync.Mutex
+	sets     map[string]map[string]float64
+	counters map[string]int64
+	ttls     map[string]time.Duration
+	commands []string
+}
+
+func newMockSortedSetRedisClient() (*redis.Client, *mockSortedSetRedis) {
+	mock := &mockSortedSetRedis{
+		sets:     make(map[string]map[string]float64),
+		counters: make(map[string]int64),
+		ttls:     make(map[string]time.Duration),
+	}
+	client := redis.NewClient(&redis.Options{Addr: "mock:6379"})
+	client.AddHook(mock)
+	return client, mock
+}
+
+func (m *mockSortedSetRedis) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, errors.New("mock redis does not dial")
+	}
+}
+
+func (m *mockSortedSetRedis) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		m.process(cmd)
+		return cmd.Err()
+	}
+}
+
+func (m *mockSortedSetRedis) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		time.Sleep(m.latency)
+		m.txMu.Lock()
+		defer m.txMu.Unlock()
+		for _, cmd := range cmds {
+			m.process(cmd)
+		}
+		return nil
+	}
+}
+
+func (m *mockSortedSetRedis) process(cmd redis.Cmder) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	args := cmd.Args()
+	m.commands = append(m.commands, cmd.Name())
+
+	switch cmd.Name() {
+	case "multi", "exec":
+	case "zremrangebyscore":
+		set := m.sets[args[1].(string)]
+		max, _ := strconv.ParseFloat(args[3].(string), 64)
+		var removed int64
+		for member, score := range set {
+			if score <= max {
+				delete(set, member)
+				removed++
+			}
+		}
+		cmd.(*redis.IntCmd).SetVal(removed)
+	case "zcard":
+		cmd.(*redis.IntCmd).SetVal(int64(len(m.sets[args[1].(string)])))
+	case "zrange":
+		// Only the lowest scored member is ever requested
+		var members []redis.Z
+		for member, score := range m.sets[args[1].(string)] {
+			members = append(members, redis.Z{Score: score, Member: member})
+		}
+		sort.Slice(members, func(i, j int) bool { return members[i].Score < members[j].Score })
+		if len(members) > 1 {
+			members = members[:1]
+		}
+		cmd.(*redis.ZSliceCmd).SetVal(members)
+	case "zrem":
+		key := args[1].(string)
+		var removed int64
+		if _, exists := m.sets[key][args[2].(string)]; exists {
+			delete(m.sets[key], args[2].(string))
+			removed = 1
+		}
+		cmd.(*redis.IntCmd).SetVal(removed)
+	case "zadd":
+		key := args[1].(string)
+		if m.sets[key] == nil {
+			m.sets[key] = make(map[string]float64)
+		}
+		m.sets[key][args[3].(string)] = args[2].(float64)
+		cmd.(*redis.IntCmd).SetVal(1)
+	case "expire":
+		seconds, _ := strconv.Atoi(fmt.Sprint(args[2]))
+		m.ttls[args[1].(string)] = time.Duration(seconds) * time.Second
+		cmd.(*redis.BoolCmd).SetVal(true)
+	case "incr":
+		m.counters[args[1].(string)]++
+		cmd.(*redis.IntCmd).SetVal(m.counters[args[1].(string)])
+	case "ttl":
+		ttl, exists := m.ttls[args[1].(string)]
+		if !exists {
+			ttl = -1
+		}
+		cmd.(*redis.DurationCmd).SetVal(ttl)
+	default:
+		cmd.SetErr(fmt.Errorf("mock redis: unsupported command %s", cmd.Name()))
+	}
+}
+
+func TestSlidingWindowLimiter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	redisClient, mock := newMockSortedSetRedisClient()
+
+	for _, backend := range []struct {
+		name        string
+		redisClient *redis.Client
+	}{
+		{"redis", redisClient},
+		{"in-memory", nil},
+	} {
+		t.Run(backend.name, func(t *testing.T) {
+			start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+			now := start
+			rateLimiter := NewRateLimiter(backend.redisClient, 60)
+			rateLimiter.now = func() time.Time { return now }
+
+			router := gin.New()
+			router.Use(rateLimiter.SlidingWindowLimiter(60, 3))
+			router.GET("/test", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"message": "success"})
+			})
+
+			request := func(ip string) *httptest.ResponseRecorder {
+				req, _ := http.NewRequest("GET", "/test", nil)
+				req.RemoteAddr = ip + ":12345"
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				return w
+			}
+
+			// A burst at the end of one minute fills the window
+			now = start.Add(50 * time.Second)
+			for i := 0; i < 3; i++ {
+				w := request("10.0.0.1")
+				require.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, "3", w.Header().Get("X-RateLimit-Limit"))
+				assert.Equal(t, strconv.Itoa(2-i), w.Header().Get("X-RateLimit-Remaining"))
+			}
+
+			// A fixed window would reset at the minute boundary and allow a second burst
+			now = start.Add(61 * time.Second)
+			w := request("10.0.0.1")
+			assert.Equal(t, http.StatusTooManyRequests, w.Code)
+			assert.Contains(t, w.Body.String(), "RATE_LIMIT_EXCEEDED")
+			assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+			assert.Equal(t, "49", w.Header().Get("Retry-After"))
+
+			// Other IPs have their own window
+			assert.Equal(t, http.StatusOK, request("10.0.0.2").Code)
+
+			// Once the burst leaves the window requests are allowed again
+			now = start.Add(110*time.Second + time.Millisecond)
+			w = request("10.0.0.1")
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "2", w.Header().Get("X-RateLimit-Remaining"))
+			assert.Empty(t, w.Header().Get("Retry-After"))
+		})
+	}
+
+	assert.Contains(t, mock.commands, "zremrangebyscore")
+	assert.Contains(t, mock.commands, "zcard")
+	assert.Contains(t, mock.commands, "zadd")
+	assert.Contains(t, mock.commands, "zrem")
+}
+
+func TestSlidingWindowLimiter_ConcurrentRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	redisClient, mock := newMockSortedSetRedisClient()
+	mock.latency = 5 * time.Millisecond
+
+	for _, backend := range []struct {
+		name        string
+		redisClient *redis.Client
+	}{
+		{"redis", redisClient},
+		{"in-memory", nil},
+	} {
+		t.Run(backend.name, func(t *testing.T) {
+			rateLimiter := NewRateLimiter(backend.redisClient, 60)
+
+			router := gin.New()
+			router.Use(rateLimiter.SlidingWindowLimiter(60, 5))
+			router.GET("/test", func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"message": "success"})
+			})
+
+			// Requests arriving together must not all pass the count check
+			var allowed int32
+			var wg sync.WaitGroup
+			for i := 0; i < 50; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					req, _ := http.NewRequest("GET", "/test", nil)
+					req.RemoteAddr = "10.0.0.1:12345"
+					w := httptest.NewRecorder()
+					router.ServeHTTP(w, req)
+					if w.Code == http.StatusOK {
+						atomic.AddInt32(&allowed, 1)
+					}
+				}()
+			}
+			wg.Wait()
+
+			assert.Equal(t, int32(5), allowed)
+		})
+	}
+}
+
+func TestSlidingWindowLimiter_SweepsIdleClients(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	rateLimiter := NewRateLimiter(nil, 60)
+
+	for i := 0; i < 100; i++ {
+		rateLimiter.slidingWindowMemory(fmt.Sprintf("client-%d", i), start, 10*time.Second, 5)
+	}
+	require.Len(t, rateLimiter.windows, 100)
+
+	// Once their windows have passed, the next request sweeps the idle clients
+	rateLimiter.slidingWindowMemory("active", start.Add(2*time.Minute), 10*time.Second, 5)
+	assert.Len(t, rateLimiter.windows, 1)
+	assert.Contains(t, rateLimiter.windows, "active")
+}
+
+func TestUserSlidingWindowLimiter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	rateLimiter := NewRateLimiter(nil, 60)
+	rateLimiter.now = func() time.Time { return now }
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if userID := c.GetHeader("X-Test-User"); userID != "" {
+			c.Set("user_id", userID)
+		}
+		c.Next()
+	})
+	limiter := rateLimiter.UserSlidingWindowLimiter(60, 1)
+	router.GET("/export", limiter, func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+	router.GET("/other-export", limiter, func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	request := func(path, userID string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		req.RemoteAddr = "10.0.0.1:12345"
+		req.Header.Set("X-Test-User", userID)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, request("/export", "user-1").Code)
+	w := request("/export", "user-1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Contains(t, w.Body.String(), "RATE_LIMIT_EXCEEDED")
+
+	// Users behind the same IP, and other routes, are counted separately
+	assert.Equal(t, http.StatusOK, request("/export", "user-2").Code)
+	assert.Equal(t, http.StatusOK, request("/other-export", "user-1").Code)
+
+	// Exempt users are not limited
+	require.NoError(t, rateLimiter.AddExemption(context.Background(), "user-3", time.Hour))
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, request("/export", "user-3").Code)
+	}
+
+	now = start.Add(61 * time.Second)
+	assert.Equal(t, http.StatusOK, request("/export", "user-1").Code)
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -371,4 +372,85 @@ func TestRateLimit_ConcurrentRequests(t *testing.T) {
 	// Should have exactly 5 successful requests and 5 rate limited
 	assert.Equal(t, 5, successCount, "Should have 5 successful requests")
 	assert.Equal(t, 5, rateLimitedCount, "Should have 5 rate limited requests")
-}
\ No newline at end of file
+}
+func TestRateLimitBypassMiddleware_Admin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	rateLimiter := NewRateLimiter(nil, 1)
+	newRouter := func(isAdmin bool) *gin.Engine {
+		router := gin.New()
+		router.Use(func(c *gin.Context) {
+			c.Set("user_id", "user-1")
+			c.Set("is_admin", isAdmin)
+			c.Next()
+		})
+		router.Use(RateLimitBypassMiddleware(rateLimiter.RateLimit(1)))
+		router.GET("/test", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"message": "success"})
+		})
+		return router
+	}
+
+	// Admins are never limited
+	adminRouter := newRouter(true)
+	for i := 0; i < 5; i++ {
+		req, _ := http.NewRequest("GET", "/test", nil)
+		w := httptest.NewRecorder()
+		adminRouter.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	// Regular users hit the limit
+	userRouter := newRouter(false)
+	req1, _ := http.NewRequest("GET", "/test", nil)
+	w1 := httptest.NewRecorder()
+	userRouter.ServeHTTP(w1, req1)
+	assert.Equal(t, http.StatusOK, w1.Code)
+
+	req2, _ := http.NewRequest("GET", "/test", nil)
+	w2 := httptest.NewRecorder()
+	userRouter.ServeHTTP(w2, req2)
+	assert.Equal(t, http.StatusTooManyRequests, w2.Code)
+}
+
+func TestRateLimit_ExemptionExpires(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	rateLimiter := NewRateLimiter(nil, 1)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", "user-1")
+		c.Next()
+	})
+	router.Use(rateLimiter.RateLimit(1))
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+
+	ctx := context.Background()
+	assert.NoError(t, rateLimiter.AddExemption(ctx, "user-1", 100*time.Millisecond))
+	assert.True(t, rateLimiter.IsExempt(ctx, "user-1"))
+	assert.False(t, rateLimiter.IsExempt(ctx, "user-2"))
+
+	// Exempt user is not counted
+	for i := 0; i < 3; i++ {
+		req, _ := http.NewRequest("GET", "/test", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	assert.False(t, rateLimiter.IsExempt(ctx, "user-1"))
+
+	// Once the exemption expires the limiter applies again
+	req1, _ := http.NewRequest("GET", "/test", nil)
+	w1 := httptest.NewRecorder()
+	router.ServeHTTP(w1, req1)
+	assert.Equal(t, http.StatusOK, w1.Code)
+
+	req2, _ := http.NewRequest("GET", "/test", nil)
+	w2 := httptest.NewRecorder()
+	router.ServeHTTP(w2, req2)
+	assert.Equal(t, http.StatusTooManyRequests, w2.Code)
+}
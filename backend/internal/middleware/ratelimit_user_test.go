@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupUserRateLimitRouter serves /test behind limiter, authenticating requests as
+// the user in their X-Test-User header
+func setupUserRateLimitRouter(limiter gin.HandlerFunc) *gin.Engine {
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if userID := c.GetHeader("X-Test-User"); userID != "" {
+			c.Set("user_id", userID)
+		}
+		c.Next()
+	})
+	router.Use(limiter)
+	router.GET("/test", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "success"})
+	})
+	return router
+}
+
+// requestAs makes a request from ip, as userID when it is not empty
+func requestAs(router *gin.Engine, ip, userID string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/test", nil)
+	req.RemoteAddr = ip + ":12345"
+	if userID != "" {
+		req.Header.Set("X-Test-User", userID)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestPerUserRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	redisClient, mock := newMockSortedSetRedisClient()
+
+	for _, backend := range []struct {
+		name        string
+		redisClient *redis.Client
+	}{
+		{"redis", redisClient},
+		{"in-memory", nil},
+	} {
+		t.Run(backend.name, func(t *testing.T) {
+			start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+			now := start
+			rateLimiter := NewRateLimiter(backend.redisClient, 60)
+			rateLimiter.now = func() time.Time { return now }
+
+			anonymous := rateLimiter.SlidingWindowLimiter(60, 2)
+			router := setupUserRateLimitRouter(rateLimiter.PerUserRateLimit(5, anonymous))
+
+			t.Run("anonymous requests are limited per IP", func(t *testing.T) {
+				for i := 0; i < 2; i++ {
+					require.Equal(t, http.StatusOK, requestAs(router, "10.0.0.1", "").Code)
+				}
+				w := requestAs(router, "10.0.0.1", "")
+				assert.Equal(t, http.StatusTooManyRequests, w.Code)
+				assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+			})
+
+			t.Run("authenticated users get their own higher limit", func(t *testing.T) {
+				// The IP's anonymous limit is used up, but users behind it are not affected
+				for i := 0; i < 5; i++ {
+					w := requestAs(router, "10.0.0.1", "user-1")
+					require.Equal(t, http.StatusOK, w.Code)
+					assert.Equal(t, "5", w.Header().Get("X-RateLimit-Limit"))
+				}
+
+				w := requestAs(router, "10.0.0.1", "user-1")
+				assert.Equal(t, http.StatusTooManyRequests, w.Code)
+				assert.Contains(t, w.Body.String(), "RATE_LIMIT_EXCEEDED")
+				assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+				assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+				// Other users on the same IP are counted separately
+				assert.Equal(t, http.StatusOK, requestAs(router, "10.0.0.1", "user-2").Code)
+			})
+
+			t.Run("exempt users are not counted", func(t *testing.T) {
+				if backend.redisClient != nil {
+					t.Skip("the mock Redis does not store exemptions")
+				}
+				require.NoError(t, rateLimiter.AddExemption(context.Background(), "user-3", time.Hour))
+				for i := 0; i < 10; i++ {
+					assert.Equal(t, http.StatusOK, requestAs(router, "10.0.0.1", "user-3").Code)
+				}
+			})
+		})
+	}
+
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	assert.Equal(t, int64(6), mock.counters["user:user-1:rate"])
+	assert.Equal(t, time.Minute, mock.ttls["user:user-1:rate"])
+}
+
+func TestWriteRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	rateLimiter := NewRateLimiter(nil, 60)
+	rateLimiter.now = func() time.Time { return now }
+	router := setupUserRateLimitRouter(rateLimiter.WriteRateLimit(2))
+
+	// Anonymous writes are left to the IP limits
+	for i := 0; i < 5; i++ {
+		require.Equal(t, http.StatusOK, requestAs(router, "10.0.0.1", "").Code)
+	}
+
+	now = start.Add(50 * time.Second)
+	for i := 0; i < 2; i++ {
+		require.Equal(t, http.StatusOK, requestAs(router, "10.0.0.1", "user-1").Code)
+	}
+	w := requestAs(router, "10.0.0.1", "user-1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+
+	// The user's writes are counted across IPs
+	assert.Equal(t, http.StatusTooManyRequests, requestAs(router, "10.0.0.2", "user-1").Code)
+
+	// The window resets a minute after the user's first write
+	now = start.Add(110 * time.Second)
+	assert.Equal(t, http.StatusOK, requestAs(router, "10.0.0.1", "user-1").Code)
+}
@@ -4,7 +4,8 @@ import (
 	"net/http"
 	"regexp"
 	"strings"
-	"time"
+
+	"bugrelay-backend/internal/errors"
 
 	"github.com/gin-gonic/gin"
 )
@@ -97,13 +98,7 @@ func (s *SecurityMiddleware) ValidateUserAgent() gin.HandlerFunc {
 		// Check against suspicious patterns
 		for _, pattern := range suspiciousPatterns {
 			if pattern.MatchString(userAgent) {
-				c.JSON(http.StatusForbidden, gin.H{
-					"error": gin.H{
-						"code":      "SUSPICIOUS_USER_AGENT",
-						"message":   "Access denied",
-						"timestamp": time.Now().UTC(),
-					},
-				})
+				errors.ErrSuspiciousUserAgent.Response(c)
 				c.Abort()
 				return
 			}
@@ -132,13 +127,7 @@ func (s *SecurityMiddleware) IPWhitelist(allowedIPs []string) gin.HandlerFunc {
 			}
 		}
 
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": gin.H{
-				"code":      "IP_NOT_ALLOWED",
-				"message":   "Access denied from this IP address",
-				"timestamp": time.Now().UTC(),
-			},
-		})
+		errors.ErrIPNotAllowed.Response(c)
 		c.Abort()
 	}
 }
@@ -147,13 +136,7 @@ func (s *SecurityMiddleware) IPWhitelist(allowedIPs []string) gin.HandlerFunc {
 func (s *SecurityMiddleware) RequestSizeLimit(maxSize int64) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if c.Request.ContentLength > maxSize {
-			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
-				"error": gin.H{
-					"code":      "REQUEST_TOO_LARGE",
-					"message":   "Request body too large",
-					"timestamp": time.Now().UTC(),
-				},
-			})
+			errors.ErrRequestTooLarge.Response(c)
 			c.Abort()
 			return
 		}
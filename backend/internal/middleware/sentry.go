@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"github.com/getsentry/sentry-go"
+	sentrygin "github.com/getsentry/sentry-go/gin"
+	"github.com/gin-gonic/gin"
+)
+
+// SentryMiddleware tags the Sentry events of a request with its request ID and,
+// once the request is authenticated, the current user. It must run after the
+// sentrygin middleware, which gives each request its own hub.
+func SentryMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hub := sentrygin.GetHubFromContext(c)
+		if hub == nil {
+			c.Next()
+			return
+		}
+
+		// Authentication happens further down the chain, so the user is read when
+		// an event is captured rather than now
+		hub.Scope().AddEventProcessor(func(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+			if requestID := c.GetString("request_id"); requestID != "" {
+				if event.Tags == nil {
+					event.Tags = make(map[string]string)
+				}
+				event.Tags["request_id"] = requestID
+			}
+			if userID, ok := GetCurrentUserID(c); ok {
+				event.User.ID = userID
+				event.User.Email, _ = GetCurrentUserEmail(c)
+			}
+			return event
+		})
+
+		c.Next()
+	}
+}
+
+// capturePanic reports a recovered panic to the request's Sentry hub
+func capturePanic(c *gin.Context, recovered interface{}) {
+	hub := sentrygin.GetHubFromContext(c)
+	if hub == nil {
+		return
+	}
+
+	hub.RecoverWithContext(c.Request.Context(), recovered)
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	sentrygin "github.com/getsentry/sentry-go/gin"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingTransport keeps the events sent to Sentry instead of sending them
+type recordingTransport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (t *recordingTransport) Configure(options sentry.ClientOptions) {}
+
+func (t *recordingTransport) Flush(timeout time.Duration) bool { return true }
+
+func (t *recordingTransport) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+
+func (t *recordingTransport) Events() []*sentry.Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*sentry.Event(nil), t.events...)
+}
+
+// setupSentryTestRouter binds a client recording events to the current hub and
+// returns a router with the Sentry middleware in the production order
+func setupSentryTestRouter(t *testing.T) (*gin.Engine, *recordingTransport) {
+	transport := &recordingTransport{}
+	client, err := sentry.NewClient(sentry.ClientOptions{Transport: transport})
+	require.NoError(t, err)
+
+	previous := sentry.CurrentHub().Client()
+	sentry.CurrentHub().BindClient(client)
+	t.Cleanup(func() { sentry.CurrentHub().BindClient(previous) })
+
+	router := setupTestRouter()
+	router.Use(sentrygin.New(sentrygin.Options{Repanic: false}))
+	router.Use(SentryMiddleware())
+	router.Use(RequestLoggingMiddleware())
+	router.Use(ErrorLoggingMiddleware())
+	return router, transport
+}
+
+func TestSentryMiddleware_CapturesPanicWithUser(t *testing.T) {
+	router, transport := setupSentryTestRouter(t)
+	router.GET("/panic", func(c *gin.Context) {
+		c.Set("user_id", "user-123")
+		c.Set("user_email", "user@example.com")
+		panic("something went wrong")
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/panic", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	events := transport.Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, sentry.LevelFatal, events[0].Level)
+	assert.Equal(t, "something went wrong", events[0].Message)
+	assert.Equal(t, "user-123", events[0].User.ID)
+	assert.Equal(t, "user@example.com", events[0].User.Email)
+	assert.Equal(t, w.Header().Get("X-Request-ID"), events[0].Tags["request_id"])
+	assert.NotEmpty(t, events[0].Tags["request_id"])
+}
+
+func TestSentryMiddleware_AnonymousRequests(t *testing.T) {
+	router, transport := setupSentryTestRouter(t)
+	router.GET("/panic", func(c *gin.Context) {
+		panic(http.ErrAbortHandler)
+	})
+	router.GET("/ok", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/ok", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, transport.Events())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/panic", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	events := transport.Events()
+	require.Len(t, events, 1)
+	require.NotEmpty(t, events[0].Exception)
+	assert.Equal(t, http.ErrAbortHandler.Error(), events[0].Exception[0].Value)
+	assert.Empty(t, events[0].User.ID)
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"encoding/json"
+	"time"
+
+	"bugrelay-backend/internal/auth"
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// sessionIPCheckedKey marks a request whose session IP mismatch was already recorded,
+// so OptionalAuth and RequireAuth on the same route record it once
+const sessionIPCheckedKey = "session_ip_checked"
+
+// SetSessionIPBinding rejects access tokens used from an IP other than the one in
+// their ip claim. A session may keep working from a new IP for gracePeriod after the
+// change is first seen, long enough for the client to refresh its tokens. Mismatches
+// are recorded as security events in db. Tokens without an ip claim, issued before
+// binding was enabled, are accepted until they expire. ipChanges is shared with the
+// auth service, which lets a session refresh from its new IP during the grace period.
+func (a *AuthMiddleware) SetSessionIPBinding(gracePeriod time.Duration, ipChanges *auth.IPChangeTracker, db *gorm.DB) {
+	a.bindSessionToIP = true
+	a.ipChangeGracePeriod = gracePeriod
+	a.ipChanges = ipChanges
+	a.db = db
+}
+
+// checkSessionIP reports whether the request may use the token's session from its IP
+func (a *AuthMiddleware) checkSessionIP(c *gin.Context, claims *auth.JWTClaims) bool {
+	if !a.bindSessionToIP || claims.IP == "" {
+		return true
+	}
+
+	tokenIP := auth.NormalizeIP(claims.IP)
+	clientIP := auth.NormalizeIP(c.ClientIP())
+	if tokenIP == clientIP {
+		return true
+	}
+
+	now := time.Now()
+	changedAt, firstSeen := a.ipChanges.Record(claims, now)
+	allowed := now.Sub(changedAt) < a.ipChangeGracePeriod
+
+	if _, recorded := c.Get(sessionIPCheckedKey); !recorded && (firstSeen || !allowed) {
+		c.Set(sessionIPCheckedKey, true)
+		a.recordSessionIPMismatch(c, claims, tokenIP, clientIP, allowed)
+	}
+
+	return allowed
+}
+
+// recordSessionIPMismatch logs a session IP mismatch as a security event, logging
+// rather than failing the request on error
+func (a *AuthMiddleware) recordSessionIPMismatch(c *gin.Context, claims *auth.JWTClaims, tokenIP, clientIP string, allowed bool) {
+	if a.db == nil {
+		return
+	}
+
+	details, err := json.Marshal(map[string]interface{}{
+		"token_id":            claims.ID,
+		"token_ip":            tokenIP,
+		"within_grace_period": allowed,
+	})
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("Failed to encode security event details", err)
+		return
+	}
+
+	event := models.SecurityEvent{
+		EventType: models.SecurityEventSessionIPMismatch,
+		IPAddress: clientIP,
+		UserAgent: c.GetHeader("User-Agent"),
+		Details:   datatypes.JSON(details),
+	}
+	if userID, err := uuid.Parse(claims.UserID); err == nil {
+		event.UserID = &userID
+	}
+
+	if err := a.db.Create(&event).Error; err != nil {
+		logger.FromContext(c.Request.Context()).Error("Failed to record security event", err, logger.Fields{
+			"event_type": models.SecurityEventSessionIPMismatch,
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/auth"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mattn/go-sqlite3"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// sqliteNowDriver is a SQLite driver with postgres' NOW(), used by the blacklist check
+const sqliteNowDriver = "sqlite3_now"
+
+var registerSQLiteNowDriver sync.Once
+
+// emptyRedis is a go-redis hook for a Redis with no keys, so no token is blacklisted
+type emptyRedis struct{}
+
+func (emptyRedis) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, errors.New("empty redis does not dial")
+	}
+}
+
+func (emptyRedis) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if intCmd, ok := cmd.(*redis.IntCmd); ok {
+			intCmd.SetVal(0)
+		}
+		return nil
+	}
+}
+
+func (emptyRedis) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+// setupSessionIPTest creates an auth middleware with session IP binding and the
+// database it records security events in
+func setupSessionIPTest(t *testing.T, gracePeriod time.Duration) (*AuthMiddleware, *auth.JWTService, *gorm.DB) {
+	registerSQLiteNowDriver.Do(func() {
+		sql.Register(sqliteNowDriver, &sqlite3.SQLiteDriver{
+			ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+				return conn.RegisterFunc("now", func() string {
+					return time.Now().UTC().Format("2006-01-02 15:04:05")
+				}, false)
+			},
+		})
+	})
+
+	db, err := gorm.Open(sqlite.New(sqlite.Config{DriverName: sqliteNowDriver, DSN: ":memory:"}), &gorm.Config{})
+	require.NoError(t, err)
+
+	// The models' postgres defaults cannot be migrated on sqlite
+	require.NoError(t, db.Exec(`CREATE TABLE jwt_blacklist (
+		token_jti TEXT PRIMARY KEY,
+		user_id TEXT,
+		expires_at DATETIME,
+		created_at DATETIME
+	)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE security_events (
+		id TEXT PRIMARY KEY,
+		user_id TEXT,
+		event_type TEXT NOT NULL,
+		ip_address TEXT,
+		user_agent TEXT,
+		details TEXT,
+		created_at DATETIME
+	)`).Error)
+
+	redisClient := redis.NewClient(&redis.Options{Addr: "empty:6379"})
+	redisClient.AddHook(emptyRedis{})
+
+	jwtService := auth.NewJWTService("test-secret", time.Hour, 24*time.Hour)
+	authMiddleware := NewAuthMiddleware(jwtService, auth.NewBlacklistService(db, redisClient))
+	authMiddleware.SetSessionIPBinding(gracePeriod, auth.NewIPChangeTracker(), db)
+	return authMiddleware, jwtService, db
+}
+
+// sessionIPRequest calls a route behind RequireAuth with the token from clientIP
+func sessionIPRequest(router *gin.Engine, token, clientIP string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.RemoteAddr = net.JoinHostPort(clientIP, "40000")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func newSessionIPRouter(authMiddleware *AuthMiddleware) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/protected", authMiddleware.RequireAuth(), func(c *gin.Context) {
+		userID, _ := GetCurrentUserID(c)
+		c.JSON(http.StatusOK, gin.H{"user_id": userID})
+	})
+	return router
+}
+
+func TestAuthMiddleware_SessionIPBinding(t *testing.T) {
+	authMiddleware, jwtService, db := setupSessionIPTest(t, 0)
+	router := newSessionIPRouter(authMiddleware)
+
+	userID := uuid.New()
+	token, _, err := jwtService.GenerateTokenPairForIP(userID.String(), "victim@example.com", false, "203.0.113.7")
+	require.NoError(t, err)
+
+	t.Run("the bound IP is accepted", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, sessionIPRequest(router, token, "203.0.113.7").Code)
+		// The same address written as an IPv4-mapped IPv6 address
+		assert.Equal(t, http.StatusOK, sessionIPRequest(router, token, "::ffff:203.0.113.7").Code)
+	})
+
+	t.Run("a stolen token is rejected from another IP", func(t *testing.T) {
+		w := sessionIPRequest(router, token, "198.51.100.23")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "SESSION_IP_MISMATCH", response["error"].(map[string]interface{})["code"])
+
+		var event models.SecurityEvent
+		require.NoError(t, db.Where("event_type = ?", models.SecurityEventSessionIPMismatch).First(&event).Error)
+		assert.Equal(t, "198.51.100.23", event.IPAddress)
+		require.NotNil(t, event.UserID)
+		assert.Equal(t, userID, *event.UserID)
+
+		var details map[string]interface{}
+		require.NoError(t, json.Unmarshal(event.Details, &details))
+		assert.Equal(t, "203.0.113.7", details["token_ip"])
+		assert.Equal(t, false, details["within_grace_period"])
+	})
+
+	t.Run("every rejected request is recorded", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, sessionIPRequest(router, token, "198.51.100.23").Code)
+
+		var events int64
+		require.NoError(t, db.Model(&models.SecurityEvent{}).Where("event_type = ?", models.SecurityEventSessionIPMismatch).Count(&events).Error)
+		assert.Equal(t, int64(2), events)
+	})
+
+	t.Run("IPv6 sessions match however the address is written", func(t *testing.T) {
+		ipv6Token, _, err := jwtService.GenerateTokenPairForIP(userID.String(), "victim@example.com", false, "2001:DB8:0:0:0:0:0:1")
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, sessionIPRequest(router, ipv6Token, "2001:db8::1").Code)
+		assert.Equal(t, http.StatusUnauthorized, sessionIPRequest(router, ipv6Token, "2001:db8::2").Code)
+	})
+
+	t.Run("tokens without an ip claim are accepted", func(t *testing.T) {
+		unbound, _, err := jwtService.GenerateTokenPair(userID.String(), "victim@example.com", false)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, sessionIPRequest(router, unbound, "198.51.100.23").Code)
+	})
+
+	t.Run("optional auth ignores a token from another IP", func(t *testing.T) {
+		optional := gin.New()
+		optional.GET("/protected", authMiddleware.OptionalAuth(), func(c *gin.Context) {
+			_, authenticated := GetCurrentUserID(c)
+			c.JSON(http.StatusOK, gin.H{"authenticated": authenticated})
+		})
+
+		var response map[string]interface{}
+		w := sessionIPRequest(optional, token, "198.51.100.23")
+		require.Equal(t, http.StatusOK, w.Code)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, false, response["authenticated"])
+	})
+}
+
+func TestAuthMiddleware_SessionIPGracePeriod(t *testing.T) {
+	authMiddleware, jwtService, db := setupSessionIPTest(t, time.Minute)
+	router := newSessionIPRouter(authMiddleware)
+
+	token, _, err := jwtService.GenerateTokenPairForIP(uuid.New().String(), "roaming@example.com", false, "203.0.113.7")
+	require.NoError(t, err)
+
+	// A mobile user switches from Wi-Fi to a cellular network
+	assert.Equal(t, http.StatusOK, sessionIPRequest(router, token, "198.51.100.23").Code)
+	assert.Equal(t, http.StatusOK, sessionIPRequest(router, token, "198.51.100.24").Code)
+	assert.Equal(t, http.StatusOK, sessionIPRequest(router, token, "203.0.113.7").Code)
+
+	// Only the start of the grace period is recorded
+	var events []models.SecurityEvent
+	require.NoError(t, db.Where("event_type = ?", models.SecurityEventSessionIPMismatch).Find(&events).Error)
+	require.Len(t, events, 1)
+	var details map[string]interface{}
+	require.NoError(t, json.Unmarshal(events[0].Details, &details))
+	assert.Equal(t, true, details["within_grace_period"])
+
+	// Once the grace period has passed, the session must be refreshed from the new IP
+	claims, err := jwtService.ValidateToken(token)
+	require.NoError(t, err)
+	authMiddleware.ipChanges = auth.NewIPChangeTracker()
+	authMiddleware.ipChanges.Record(claims, time.Now().Add(-2*time.Minute))
+	assert.Equal(t, http.StatusUnauthorized, sessionIPRequest(router, token, "198.51.100.23").Code)
+	assert.Equal(t, http.StatusOK, sessionIPRequest(router, token, "203.0.113.7").Code)
+}
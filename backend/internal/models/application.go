@@ -7,6 +7,10 @@ import (
 	"gorm.io/gorm"
 )
 
+// DefaultSearchLanguage is the text search configuration used for bug searches not
+// limited to one application
+const DefaultSearchLanguage = "english"
+
 // Application represents an application that can have bug reports
 type Application struct {
 	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
@@ -15,6 +19,17 @@ type Application struct {
 	CompanyID *uuid.UUID `json:"company_id,omitempty" gorm:"type:uuid"`
 	CreatedAt time.Time  `json:"created_at"`
 
+	// Normalized form of URL, so that variants of the same URL such as a trailing
+	// slash or http instead of https resolve to one application
+	CanonicalURL *string `json:"canonical_url,omitempty" gorm:"size:2048;uniqueIndex"`
+
+	// Archived applications no longer accept new bug reports
+	IsArchived bool       `json:"is_archived" gorm:"default:false"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+
+	// Text search configuration used when searching the application's bugs
+	SearchLanguage string `json:"search_language" gorm:"size:64;not null;default:'english'"`
+
 	// Relationships
 	Company    *Company    `json:"company,omitempty" gorm:"foreignKey:CompanyID"`
 	BugReports []BugReport `json:"bug_reports,omitempty" gorm:"foreignKey:ApplicationID"`
@@ -31,4 +46,4 @@ func (a *Application) BeforeCreate(tx *gorm.DB) error {
 // TableName returns the table name for the Application model
 func (Application) TableName() string {
 	return "applications"
-}
\ No newline at end of file
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
@@ -14,6 +15,10 @@ type AuditLog struct {
 	Resource string    `json:"resource" gorm:"size:100;not null"`
 	ResourceID *uuid.UUID `json:"resource_id,omitempty" gorm:"type:uuid"`
 	Details  string    `json:"details" gorm:"type:text"`
+
+	// Snapshots of the resource before and after the action, if captured
+	BeforeState *datatypes.JSON `json:"before_state,omitempty" gorm:"type:jsonb" swaggertype:"object"`
+	AfterState  *datatypes.JSON `json:"after_state,omitempty" gorm:"type:jsonb" swaggertype:"object"`
 	
 	// User who performed the action
 	UserID uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
@@ -48,10 +53,31 @@ const (
 	AuditActionBugRemove   = "bug_remove"
 	AuditActionBugMerge    = "bug_merge"
 	AuditActionBugRestore  = "bug_restore"
+	AuditActionBugPurge    = "bug_purge"
+	AuditActionBugStatusChange = "bug_status_change"
+	AuditActionBugPriorityChange = "bug_priority_change"
+	AuditActionBugProjectionRebuild = "bug_projection_rebuild"
+	AuditActionBugOwnerTransfer = "bug_owner_transfer"
+	AuditActionRateLimitExempt = "rate_limit_exempt"
 	AuditActionUserBan     = "user_ban"
 	AuditActionUserUnban   = "user_unban"
 	AuditActionCompanyVerify = "company_verify"
 	AuditActionCompanyUnverify = "company_unverify"
+	AuditActionMemberRoleChange = "member_role_change"
+	AuditActionMemberAdd = "member_add"
+	AuditActionMemberRemove = "member_remove"
+	AuditActionCompanyClaimInitiate = "company_claim_initiate"
+	AuditActionCompanyClaimComplete = "company_claim_complete"
+	AuditActionInviteLinkDomainBypass = "invite_link_domain_bypass"
+	AuditActionCompanyDashboardExport = "company_dashboard_export"
+	AuditActionBugExport = "bug_export"
+	AuditActionOutboxRetry = "outbox_retry"
+	AuditActionOutboxDiscard = "outbox_discard"
+	AuditActionIPBlock     = "ip_block"
+	AuditActionIPUnblock   = "ip_unblock"
+	AuditActionCommentDelete = "comment_delete"
+	AuditActionBugAutoEscalate = "bug_auto_escalate"
+	AuditActionBugEdit = "bug_edit"
 )
 
 // AuditResource constants
@@ -60,4 +86,7 @@ const (
 	AuditResourceUser    = "user"
 	AuditResourceCompany = "company"
 	AuditResourceComment = "comment"
+	AuditResourceCompanyMember = "company_member"
+	AuditResourceOutboxEvent = "outbox_event"
+	AuditResourceIPBlock = "ip_block"
 )
\ No newline at end of file
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// BugAssignmentRule assigns new bugs for an application to a company member
+// when the bug's tags and priority match the rule
+type BugAssignmentRule struct {
+	ID             uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	CompanyID      uuid.UUID      `json:"company_id" gorm:"type:uuid;not null;index"`
+	ApplicationID  uuid.UUID      `json:"application_id" gorm:"type:uuid;not null;index"`
+	Tags           pq.StringArray `json:"tags" gorm:"type:text[]"`
+	AssigneeUserID *uuid.UUID     `json:"assignee_user_id,omitempty" gorm:"type:uuid"`
+	PriorityFilter string         `json:"priority_filter,omitempty" gorm:"size:20"`
+	PriorityOrder  int            `json:"priority_order" gorm:"default:0"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+
+	// Relationships
+	Company     Company     `json:"company,omitempty" gorm:"foreignKey:CompanyID"`
+	Application Application `json:"application,omitempty" gorm:"foreignKey:ApplicationID"`
+	Assignee    *User       `json:"assignee,omitempty" gorm:"foreignKey:AssigneeUserID"`
+}
+
+// BeforeCreate hook to set ID if not provided
+func (r *BugAssignmentRule) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for the BugAssignmentRule model
+func (BugAssignmentRule) TableName() string {
+	return "bug_assignment_rules"
+}
+
+// Matches reports whether a bug with the given application, priority and tags
+// satisfies the rule. Every rule tag must be present on the bug; an empty
+// priority filter matches any priority.
+func (r *BugAssignmentRule) Matches(applicationID uuid.UUID, priority string, tags []string) bool {
+	if r.ApplicationID != applicationID {
+		return false
+	}
+
+	if r.PriorityFilter != "" && r.PriorityFilter != priority {
+		return false
+	}
+
+	bugTags := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		bugTags[strings.ToLower(tag)] = true
+	}
+	for _, tag := range r.Tags {
+		if !bugTags[strings.ToLower(tag)] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBugAssignmentRule_Matches(t *testing.T) {
+	appID := uuid.New()
+	rule := BugAssignmentRule{
+		ApplicationID:  appID,
+		Tags:           []string{"ui", "crash"},
+		PriorityFilter: BugPriorityHigh,
+	}
+
+	tests := []struct {
+		name     string
+		appID    uuid.UUID
+		priority string
+		tags     []string
+		want     bool
+	}{
+		{name: "all tags and priority match", appID: appID, priority: BugPriorityHigh, tags: []string{"crash", "ui", "login"}, want: true},
+		{name: "tags match case-insensitively", appID: appID, priority: BugPriorityHigh, tags: []string{"UI", "Crash"}, want: true},
+		{name: "partial tag match", appID: appID, priority: BugPriorityHigh, tags: []string{"ui"}, want: false},
+		{name: "no tags", appID: appID, priority: BugPriorityHigh, tags: nil, want: false},
+		{name: "different priority", appID: appID, priority: BugPriorityLow, tags: []string{"ui", "crash"}, want: false},
+		{name: "different application", appID: uuid.New(), priority: BugPriorityHigh, tags: []string{"ui", "crash"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, rule.Matches(tt.appID, tt.priority, tt.tags))
+		})
+	}
+
+	t.Run("empty rule matches any bug for the application", func(t *testing.T) {
+		catchAll := BugAssignmentRule{ApplicationID: appID}
+		assert.True(t, catchAll.Matches(appID, BugPriorityLow, nil))
+	})
+}
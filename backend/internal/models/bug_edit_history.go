@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BugEditHistory records a change to one field of a bug report made through
+// UpdateBug. Values are stored as text; tags are joined with commas and unset
+// fields are empty.
+type BugEditHistory struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	BugID     uuid.UUID `json:"bug_id" gorm:"type:uuid;not null;index"`
+	FieldName string    `json:"field_name" gorm:"size:50;not null"`
+	OldValue  string    `json:"old_value" gorm:"type:text"`
+	NewValue  string    `json:"new_value" gorm:"type:text"`
+	ChangedBy uuid.UUID `json:"changed_by" gorm:"type:uuid;not null"`
+	ChangedAt time.Time `json:"changed_at" gorm:"not null"`
+}
+
+// BeforeCreate hook to set ID if not provided
+func (e *BugEditHistory) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for the BugEditHistory model
+func (BugEditHistory) TableName() string {
+	return "bug_edit_history"
+}
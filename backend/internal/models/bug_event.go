@@ -0,0 +1,222 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// BugEvent is an append-only record of a change to a bug report. The bug report
+// columns covered by event types are a projection of the bug's events, kept up to date
+// by ProjectBug and rebuilt from scratch by RebuildBugProjection.
+type BugEvent struct {
+	ID        uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	BugID     uuid.UUID      `json:"bug_id" gorm:"type:uuid;not null;uniqueIndex:idx_bug_events_bug_sequence"`
+	Sequence  int64          `json:"sequence" gorm:"not null;uniqueIndex:idx_bug_events_bug_sequence"`
+	EventType string         `json:"event_type" gorm:"size:50;not null"`
+	Payload   datatypes.JSON `json:"payload" gorm:"type:jsonb;not null"`
+	ActorID   *uuid.UUID     `json:"actor_id,omitempty" gorm:"type:uuid"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// BeforeCreate hook to set ID if not provided
+func (e *BugEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for the BugEvent model
+func (BugEvent) TableName() string {
+	return "bug_events"
+}
+
+// BugEventType constants
+const (
+	BugEventStatusChanged   = "status_changed"
+	BugEventPriorityChanged = "priority_changed"
+)
+
+// BugFieldChange is the payload of events that change a single bug report field.
+// Recording the previous value lets a projection be rebuilt from the events alone.
+type BugFieldChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// AppendBugEvent appends an event to a bug's event stream. Run it in a transaction;
+// the unique (bug_id, sequence) index rejects concurrent appends of the same sequence.
+func AppendBugEvent(tx *gorm.DB, bugID uuid.UUID, eventType string, actorID *uuid.UUID, change BugFieldChange) (*BugEvent, error) {
+	payload, err := json.Marshal(change)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastSequence int64
+	if err := tx.Model(&BugEvent{}).Where("bug_id = ?", bugID).
+		Select("COALESCE(MAX(sequence), 0)").Scan(&lastSequence).Error; err != nil {
+		return nil, err
+	}
+
+	event := BugEvent{
+		BugID:     bugID,
+		Sequence:  lastSequence + 1,
+		EventType: eventType,
+		Payload:   datatypes.JSON(payload),
+		ActorID:   actorID,
+		CreatedAt: time.Now(),
+	}
+	if err := tx.Create(&event).Error; err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// ApplyBugEvent reduces an event into the bug's projected state. Events at or before
+// the bug's EventSequence have already been applied and are skipped, so replaying a
+// stream any number of times gives the same state.
+func (br *BugReport) ApplyBugEvent(event BugEvent) error {
+	if event.Sequence <= br.EventSequence {
+		return nil
+	}
+
+	var change BugFieldChange
+	if err := json.Unmarshal(event.Payload, &change); err != nil {
+		return fmt.Errorf("failed to decode bug event %d: %v", event.Sequence, err)
+	}
+
+	switch event.EventType {
+	case BugEventStatusChanged:
+		br.Status = change.To
+		if !br.IsResolved() {
+			br.ResolvedAt = nil
+		} else if br.ResolvedAt == nil {
+			resolvedAt := event.CreatedAt
+			br.ResolvedAt = &resolvedAt
+		}
+	case BugEventPriorityChanged:
+		br.Priority = change.To
+	default:
+		return fmt.Errorf("unknown bug event type %q", event.EventType)
+	}
+
+	br.EventSequence = event.Sequence
+	if event.CreatedAt.After(br.UpdatedAt) {
+		br.UpdatedAt = event.CreatedAt
+	}
+	return nil
+}
+
+// ProjectBug applies a bug's events that are not yet reflected in its projected
+// columns and returns the updated bug
+func ProjectBug(db *gorm.DB, bugID uuid.UUID) (*BugReport, error) {
+	var bug BugReport
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&bug, "id = ?", bugID).Error; err != nil {
+			return err
+		}
+		return applyPendingBugEvents(tx, &bug, bug.EventSequence)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &bug, nil
+}
+
+// RebuildBugProjection discards a bug's projected columns and replays its whole event
+// stream. The starting values come from the first event of each type, so bugs created
+// before their first event keep the values they were created with.
+func RebuildBugProjection(db *gorm.DB, bugID uuid.UUID) (*BugReport, error) {
+	var bug BugReport
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&bug, "id = ?", bugID).Error; err != nil {
+			return err
+		}
+		appliedSequence := bug.EventSequence
+
+		var events []BugEvent
+		if err := tx.Where("bug_id = ?", bugID).Order("sequence ASC").Find(&events).Error; err != nil {
+			return err
+		}
+
+		initialized := map[string]bool{}
+		for _, event := range events {
+			if initialized[event.EventType] {
+				continue
+			}
+			initialized[event.EventType] = true
+
+			var change BugFieldChange
+			if err := json.Unmarshal(event.Payload, &change); err != nil {
+				return fmt.Errorf("failed to decode bug event %d: %v", event.Sequence, err)
+			}
+			switch event.EventType {
+			case BugEventStatusChanged:
+				bug.Status = change.From
+				if !bug.IsResolved() {
+					bug.ResolvedAt = nil
+				}
+			case BugEventPriorityChanged:
+				bug.Priority = change.From
+			}
+		}
+
+		bug.EventSequence = 0
+		for _, event := range events {
+			if err := bug.ApplyBugEvent(event); err != nil {
+				return err
+			}
+		}
+		return saveBugProjection(tx, &bug, appliedSequence)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &bug, nil
+}
+
+// applyPendingBugEvents applies the events after the bug's EventSequence and saves
+// the projection
+func applyPendingBugEvents(tx *gorm.DB, bug *BugReport, appliedSequence int64) error {
+	var events []BugEvent
+	if err := tx.Where("bug_id = ? AND sequence > ?", bug.ID, bug.EventSequence).
+		Order("sequence ASC").Find(&events).Error; err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	for _, event := range events {
+		if err := bug.ApplyBugEvent(event); err != nil {
+			return err
+		}
+	}
+	return saveBugProjection(tx, bug, appliedSequence)
+}
+
+// saveBugProjection writes the projected columns, provided no other projection has
+// advanced the bug past appliedSequence in the meantime
+func saveBugProjection(tx *gorm.DB, bug *BugReport, appliedSequence int64) error {
+	result := tx.Model(&BugReport{}).
+		Where("id = ? AND event_sequence = ?", bug.ID, appliedSequence).
+		Updates(map[string]interface{}{
+			"status":         bug.Status,
+			"priority":       bug.Priority,
+			"resolved_at":    bug.ResolvedAt,
+			"event_sequence": bug.EventSequence,
+			"updated_at":     bug.UpdatedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("bug %s projection was updated concurrently", bug.ID)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func newTestBugEvent(t *testing.T, sequence int64, eventType, from, to string, at time.Time) BugEvent {
+	payload, err := json.Marshal(BugFieldChange{From: from, To: to})
+	require.NoError(t, err)
+	return BugEvent{
+		Sequence:  sequence,
+		EventType: eventType,
+		Payload:   datatypes.JSON(payload),
+		CreatedAt: at,
+	}
+}
+
+func TestBugReport_ApplyBugEvent(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	events := []BugEvent{
+		newTestBugEvent(t, 1, BugEventPriorityChanged, BugPriorityMedium, BugPriorityHigh, start),
+		newTestBugEvent(t, 2, BugEventStatusChanged, BugStatusOpen, BugStatusReviewing, start.Add(time.Hour)),
+		newTestBugEvent(t, 3, BugEventStatusChanged, BugStatusReviewing, BugStatusFixed, start.Add(2*time.Hour)),
+	}
+
+	replay := func(bug *BugReport) {
+		for _, event := range events {
+			require.NoError(t, bug.ApplyBugEvent(event))
+		}
+	}
+
+	bug := BugReport{Status: BugStatusOpen, Priority: BugPriorityMedium}
+	replay(&bug)
+
+	assert.Equal(t, BugStatusFixed, bug.Status)
+	assert.Equal(t, BugPriorityHigh, bug.Priority)
+	require.NotNil(t, bug.ResolvedAt)
+	assert.Equal(t, start.Add(2*time.Hour), *bug.ResolvedAt)
+	assert.Equal(t, int64(3), bug.EventSequence)
+	assert.Equal(t, start.Add(2*time.Hour), bug.UpdatedAt)
+
+	t.Run("replaying applied events changes nothing", func(t *testing.T) {
+		replayed := bug
+		replay(&replayed)
+		assert.Equal(t, bug, replayed)
+	})
+
+	t.Run("reopening clears the resolution", func(t *testing.T) {
+		reopened := bug
+		require.NoError(t, reopened.ApplyBugEvent(newTestBugEvent(t, 4, BugEventStatusChanged, BugStatusFixed, BugStatusOpen, start.Add(3*time.Hour))))
+		assert.Equal(t, BugStatusOpen, reopened.Status)
+		assert.Nil(t, reopened.ResolvedAt)
+	})
+
+	t.Run("unknown event types are rejected", func(t *testing.T) {
+		unknown := bug
+		err := unknown.ApplyBugEvent(newTestBugEvent(t, 4, "title_changed", "a", "b", start))
+		assert.Error(t, err)
+		assert.Equal(t, int64(3), unknown.EventSequence)
+	})
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
@@ -23,19 +24,52 @@ type BugReport struct {
 	AppVersion      *string `json:"app_version,omitempty" gorm:"size:50"`
 	BrowserVersion  *string `json:"browser_version,omitempty" gorm:"size:100"`
 
+	// EnvironmentInfo holds an EnvironmentInfo, from the submission or detected from
+	// its request headers
+	EnvironmentInfo datatypes.JSON `json:"environment_info,omitempty" gorm:"type:jsonb" swaggertype:"object"`
+
+	// Application-specific metadata
+	CustomFields datatypes.JSON `json:"custom_fields,omitempty" gorm:"type:jsonb" swaggertype:"object"`
+
 	// Associations
 	ApplicationID      uuid.UUID  `json:"application_id" gorm:"type:uuid;not null"`
 	ReporterID         *uuid.UUID `json:"reporter_id,omitempty" gorm:"type:uuid"` // null for anonymous
 	AssignedCompanyID  *uuid.UUID `json:"assigned_company_id,omitempty" gorm:"type:uuid"`
+	AssignedMemberID   *uuid.UUID `json:"assigned_member_id,omitempty" gorm:"type:uuid"` // set by assignment rules
+
+	// Spam detection, never exposed so bots cannot tell their submissions were caught
+	IsSpam    bool    `json:"-" gorm:"default:false;index"`
+	SpamScore float64 `json:"-" gorm:"default:0"`
+
+	// Where the bug was submitted from, looked up from the submitter's IP. Locations
+	// are only reported in aggregate so individual submitters are not exposed.
+	CountryCode *string `json:"-" gorm:"size:2;index"`
+	Region      *string `json:"-" gorm:"size:100"`
+
+	// DuplicateCheckSkipped marks bugs submitted with force_create although similar
+	// bugs were open in the same application, so moderators can review them
+	DuplicateCheckSkipped bool `json:"duplicate_check_skipped" gorm:"default:false"`
 
 	// Engagement metrics
 	VoteCount    int `json:"vote_count" gorm:"default:0"`
 	CommentCount int `json:"comment_count" gorm:"default:0"`
+	// WeightedVoteCount sums the weights of the bug's votes, see BugVote.VoteWeight
+	WeightedVoteCount float64 `json:"weighted_vote_count" gorm:"not null;default:0"`
+
+	// AttachmentCount is counted when a single bug is returned rather than stored
+	AttachmentCount *int64 `json:"attachment_count,omitempty" gorm:"-"`
+
+	// TrendingScore decays with the bug's age and is refreshed periodically, see
+	// RefreshTrendingScores
+	TrendingScore float64 `json:"trending_score,omitempty" gorm:"not null;default:0;index"`
+
+	// EventSequence is the sequence of the last BugEvent applied to the projected columns
+	EventSequence int64 `json:"-" gorm:"default:0"`
 
 	// Timestamps
 	CreatedAt  time.Time      `json:"created_at"`
 	UpdatedAt  time.Time      `json:"updated_at"`
-	DeletedAt  gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+	DeletedAt  gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index" swaggertype:"string" format:"date-time"`
 	ResolvedAt *time.Time     `json:"resolved_at,omitempty"`
 
 	// Relationships
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BugStatsByCompany represents a row of the bug_stats_by_company materialized view
+type BugStatsByCompany struct {
+	AssignedCompanyID  *uuid.UUID `json:"assigned_company_id" gorm:"type:uuid"`
+	Status             string     `json:"status"`
+	Count              int64      `json:"count"`
+	AvgResolutionHours *float64   `json:"avg_resolution_hours,omitempty"`
+}
+
+// TableName returns the view name for the BugStatsByCompany model
+func (BugStatsByCompany) TableName() string {
+	return "bug_stats_by_company"
+}
+
+// RefreshBugStatsByCompany refreshes the bug_stats_by_company materialized view
+// without blocking concurrent reads
+func RefreshBugStatsByCompany(db *gorm.DB) error {
+	return db.Exec("REFRESH MATERIALIZED VIEW CONCURRENTLY bug_stats_by_company").Error
+}
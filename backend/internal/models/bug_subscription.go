@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BugSubscription records that a user watches a bug and is notified when its status
+// changes, someone comments on it or its company responds. Reporters are subscribed
+// to their own bugs when they submit them.
+type BugSubscription struct {
+	BugID     uuid.UUID `json:"bug_id" gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey;index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName returns the table name for the BugSubscription model
+func (BugSubscription) TableName() string {
+	return "bug_subscriptions"
+}
@@ -14,6 +14,10 @@ type BugVote struct {
 	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
 	CreatedAt time.Time `json:"created_at"`
 
+	// VoteWeight is the weight the vote adds to the bug's weighted vote count, set
+	// from the voter's reputation when the vote was cast
+	VoteWeight float64 `json:"vote_weight" gorm:"not null;default:1"`
+
 	// Relationships
 	Bug  BugReport `json:"bug,omitempty" gorm:"foreignKey:BugID"`
 	User User      `json:"user,omitempty" gorm:"foreignKey:UserID"`
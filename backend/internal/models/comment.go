@@ -9,17 +9,56 @@ import (
 
 // Comment represents a comment on a bug report
 type Comment struct {
-	ID                uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
-	BugID             uuid.UUID `json:"bug_id" gorm:"type:uuid;not null"`
-	UserID            uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
-	Content           string    `json:"content" gorm:"type:text;not null"`
-	IsCompanyResponse bool      `json:"is_company_response" gorm:"default:false"`
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
+	ID                uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	BugID             uuid.UUID  `json:"bug_id" gorm:"type:uuid;not null"`
+	UserID            uuid.UUID  `json:"user_id" gorm:"type:uuid"`                   // NULL once a deleted user's comments are anonymized
+	ParentID          *uuid.UUID `json:"parent_id,omitempty" gorm:"type:uuid;index"` // NULL for top-level comments
+	Content           string     `json:"content" gorm:"type:text;not null"`
+	IsCompanyResponse bool       `json:"is_company_response" gorm:"default:false"`
+	EditedAt          *time.Time `json:"edited_at,omitempty"` // NULL until the author edits the comment
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	// DeletedAt is set when the author deletes the comment. Deleted comments stay in
+	// their thread, without content, so replies to them keep their place.
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// IsEdited and IsDeleted are derived from EditedAt and DeletedAt when the comment is loaded
+	IsEdited  bool `json:"is_edited" gorm:"-"`
+	IsDeleted bool `json:"is_deleted" gorm:"-"`
 
 	// Relationships
 	Bug  BugReport `json:"bug,omitempty" gorm:"foreignKey:BugID"`
 	User User      `json:"user,omitempty" gorm:"foreignKey:UserID"`
+
+	// Children are the replies to this comment
+	Children []Comment `json:"children,omitempty" gorm:"foreignKey:ParentID"`
+	// EditHistory holds the content the comment had before each edit, oldest first
+	EditHistory []CommentEdit `json:"edit_history,omitempty" gorm:"foreignKey:CommentID"`
+}
+
+// CommentEditWindow is how long after posting a comment its author can edit or
+// delete it
+const CommentEditWindow = 24 * time.Hour
+
+// CommentEdit records the content a comment had before an edit
+type CommentEdit struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	CommentID uuid.UUID `json:"comment_id" gorm:"type:uuid;not null;index"`
+	Content   string    `json:"content" gorm:"type:text;not null"`
+	EditedAt  time.Time `json:"edited_at" gorm:"not null"` // When the content was replaced
+}
+
+// BeforeCreate hook to set ID if not provided
+func (e *CommentEdit) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for the CommentEdit model
+func (CommentEdit) TableName() string {
+	return "comment_edits"
 }
 
 // BeforeCreate hook to set ID if not provided
@@ -30,7 +69,24 @@ func (c *Comment) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// AfterFind hook to derive IsEdited and IsDeleted. The content of deleted comments
+// is not returned.
+func (c *Comment) AfterFind(tx *gorm.DB) error {
+	c.IsEdited = c.EditedAt != nil
+	c.IsDeleted = c.DeletedAt.Valid
+	if c.IsDeleted {
+		c.Content = ""
+	}
+	return nil
+}
+
+// EditableBy reports whether the user can still edit or delete the comment as its
+// author
+func (c *Comment) EditableBy(userID uuid.UUID, now time.Time) bool {
+	return c.UserID == userID && now.Sub(c.CreatedAt) <= CommentEditWindow
+}
+
 // TableName returns the table name for the Comment model
 func (Comment) TableName() string {
 	return "comments"
-}
\ No newline at end of file
+}
@@ -13,11 +13,24 @@ type Company struct {
 	Name     string    `json:"name" gorm:"size:255;not null"`
 	Domain   string    `json:"domain" gorm:"size:255;uniqueIndex;not null"`
 	IsVerified bool    `json:"is_verified" gorm:"default:false"`
+	LogoURL    *string `json:"logo_url,omitempty" gorm:"size:500"`
 
 	// Verification
-	VerificationToken *string    `json:"-" gorm:"size:255"`
-	VerificationEmail *string    `json:"verification_email,omitempty" gorm:"size:255"`
-	VerifiedAt        *time.Time `json:"verified_at,omitempty"`
+	VerificationToken          *string    `json:"-" gorm:"size:255"`
+	VerificationEmail          *string    `json:"verification_email,omitempty" gorm:"size:255"`
+	VerificationTokenExpiresAt *time.Time `json:"verification_token_expires_at,omitempty"`
+	VerifiedAt                 *time.Time `json:"verified_at,omitempty"`
+
+	// Set when an administrator verified the company instead of the email flow
+	AdminVerified   bool       `json:"admin_verified" gorm:"default:false"`
+	AdminVerifiedBy *uuid.UUID `json:"admin_verified_by,omitempty" gorm:"type:uuid"`
+
+	// Pending domain change awaiting confirmation. The token is emailed to
+	// PendingDomainEmail, an address at the pending domain.
+	PendingDomain                      *string    `json:"pending_domain,omitempty" gorm:"size:255"`
+	PendingDomainEmail                 *string    `json:"pending_domain_email,omitempty" gorm:"size:255"`
+	PendingDomainVerificationToken     *string    `json:"-" gorm:"size:255"`
+	PendingDomainVerificationExpiresAt *time.Time `json:"pending_domain_verification_expires_at,omitempty"`
 
 	// Timestamps
 	CreatedAt time.Time `json:"created_at"`
@@ -42,6 +55,35 @@ func (Company) TableName() string {
 	return "companies"
 }
 
+// CompanyVerificationTokenTTL is how long a company verification token can be used for
+const CompanyVerificationTokenTTL = 24 * time.Hour
+
+// IsVerificationTokenExpired reports whether the company's verification token has
+// expired at now. Tokens without an expiry are treated as expired.
+func (c *Company) IsVerificationTokenExpired(now time.Time) bool {
+	return c.VerificationTokenExpiresAt == nil || !now.Before(*c.VerificationTokenExpiresAt)
+}
+
+// IsPendingDomainTokenExpired reports whether the token confirming the company's
+// pending domain change has expired at now. Tokens without an expiry are treated as
+// expired.
+func (c *Company) IsPendingDomainTokenExpired(now time.Time) bool {
+	return c.PendingDomainVerificationExpiresAt == nil || !now.Before(*c.PendingDomainVerificationExpiresAt)
+}
+
+// CleanupExpiredVerifications clears the verification token and email of unverified
+// companies whose token has expired, returning how many companies were cleaned up
+func CleanupExpiredVerifications(db *gorm.DB, now time.Time) (int64, error) {
+	result := db.Model(&Company{}).
+		Where("is_verified = ? AND verification_token_expires_at <= ?", false, now).
+		Updates(map[string]interface{}{
+			"verification_token":            nil,
+			"verification_email":            nil,
+			"verification_token_expires_at": nil,
+		})
+	return result.RowsAffected, result.Error
+}
+
 // CompanyMember represents the relationship between users and companies
 type CompanyMember struct {
 	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
@@ -55,6 +97,22 @@ type CompanyMember struct {
 	User    User    `json:"user,omitempty" gorm:"foreignKey:UserID"`
 }
 
+// CompanyMember role constants
+const (
+	CompanyRoleAdmin  = "admin"
+	CompanyRoleMember = "member"
+	CompanyRoleViewer = "viewer"
+)
+
+// IsValidCompanyRole checks if the provided company member role is valid
+func IsValidCompanyRole(role string) bool {
+	switch role {
+	case CompanyRoleAdmin, CompanyRoleMember, CompanyRoleViewer:
+		return true
+	}
+	return false
+}
+
 // BeforeCreate hook to set ID if not provided
 func (cm *CompanyMember) BeforeCreate(tx *gorm.DB) error {
 	if cm.ID == uuid.Nil {
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CompanyInvitation invites an email address to join a company. The recipient
+// does not need an account until the invitation is accepted.
+type CompanyInvitation struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	CompanyID   uuid.UUID  `json:"company_id" gorm:"type:uuid;not null;index"`
+	Email       string     `json:"email" gorm:"size:255;not null"`
+	Role        string     `json:"role" gorm:"size:20;default:'member'"`
+	Token       string     `json:"-" gorm:"size:255;uniqueIndex;not null"`
+	InvitedByID uuid.UUID  `json:"invited_by_id" gorm:"type:uuid;not null"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	AcceptedAt  *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// Relationships
+	Company Company `json:"company,omitempty" gorm:"foreignKey:CompanyID"`
+}
+
+// BeforeCreate hook to set ID if not provided
+func (ci *CompanyInvitation) BeforeCreate(tx *gorm.DB) error {
+	if ci.ID == uuid.Nil {
+		ci.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for the CompanyInvitation model
+func (CompanyInvitation) TableName() string {
+	return "company_invitations"
+}
+
+// IsExpired reports whether the invitation can no longer be accepted
+func (ci *CompanyInvitation) IsExpired() bool {
+	return time.Now().After(ci.ExpiresAt)
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CompanyInviteLink is a shareable link that adds whoever uses it to a company.
+// Unlike an invitation it is not addressed to an email, so it can be used by
+// several people until it expires.
+type CompanyInviteLink struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	CompanyID   uuid.UUID `json:"company_id" gorm:"type:uuid;not null;index"`
+	Role        string    `json:"role" gorm:"size:20;default:'member'"`
+	Token       string    `json:"-" gorm:"size:255;uniqueIndex;not null"`
+	CreatedByID uuid.UUID `json:"created_by_id" gorm:"type:uuid;not null"`
+	// BypassDomainCheck lets people whose email is not at the company's domain join
+	// through the link, such as hires who keep their previous work email. It is set
+	// when the link is created and BypassCount tracks how often it was used.
+	BypassDomainCheck bool      `json:"bypass_domain_check" gorm:"default:false"`
+	BypassCount       int       `json:"bypass_count" gorm:"default:0"`
+	ExpiresAt         time.Time `json:"expires_at"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+
+	// Relationships
+	Company Company `json:"company,omitempty" gorm:"foreignKey:CompanyID"`
+}
+
+// BeforeCreate hook to set ID if not provided
+func (l *CompanyInviteLink) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for the CompanyInviteLink model
+func (CompanyInviteLink) TableName() string {
+	return "company_invite_links"
+}
+
+// IsExpired reports whether the link can no longer be used
+func (l *CompanyInviteLink) IsExpired() bool {
+	return time.Now().After(l.ExpiresAt)
+}
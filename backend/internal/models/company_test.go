@@ -0,0 +1,22 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompany_IsVerificationTokenExpired(t *testing.T) {
+	expiresAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	company := Company{VerificationTokenExpiresAt: &expiresAt}
+
+	assert.False(t, company.IsVerificationTokenExpired(expiresAt.Add(-24*time.Hour)))
+	assert.False(t, company.IsVerificationTokenExpired(expiresAt.Add(-time.Nanosecond)))
+	assert.True(t, company.IsVerificationTokenExpired(expiresAt), "token expires at exactly its expiry time")
+	assert.True(t, company.IsVerificationTokenExpired(expiresAt.Add(time.Second)))
+
+	t.Run("tokens without an expiry are expired", func(t *testing.T) {
+		assert.True(t, (&Company{}).IsVerificationTokenExpired(expiresAt))
+	})
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// Events a company webhook can subscribe to
+const (
+	WebhookEventBugCreated       = "bug.created"
+	WebhookEventBugStatusChanged = "bug.status_changed"
+	WebhookEventCommentCreated   = "comment.created"
+)
+
+// IsValidWebhookEvent reports whether a company webhook can subscribe to the event
+func IsValidWebhookEvent(event string) bool {
+	switch event {
+	case WebhookEventBugCreated, WebhookEventBugStatusChanged, WebhookEventCommentCreated:
+		return true
+	}
+	return false
+}
+
+// CompanyWebhook posts events for the bugs assigned to a company to a URL of the
+// company's, such as its CI or issue tracker. Deliveries are signed with Secret.
+type CompanyWebhook struct {
+	ID        uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	CompanyID uuid.UUID      `json:"company_id" gorm:"type:uuid;not null;index"`
+	URL       string         `json:"url" gorm:"size:2048;not null"`
+	Secret    string         `json:"-" gorm:"size:255;not null"`
+	Events    pq.StringArray `json:"events" gorm:"type:text[]"`
+	CreatedBy uuid.UUID      `json:"created_by" gorm:"type:uuid;not null"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+
+	// Relationships
+	Company Company `json:"company,omitempty" gorm:"foreignKey:CompanyID"`
+}
+
+// BeforeCreate hook to set ID if not provided
+func (w *CompanyWebhook) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == uuid.Nil {
+		w.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for the CompanyWebhook model
+func (CompanyWebhook) TableName() string {
+	return "company_webhooks"
+}
+
+// Subscribes reports whether the webhook receives the event
+func (w *CompanyWebhook) Subscribes(event string) bool {
+	for _, subscribed := range w.Events {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// FieldDefinition describes a single application-specific custom field
+type FieldDefinition struct {
+	Name            string `json:"name"`
+	Type            string `json:"type"`
+	Required        bool   `json:"required"`
+	ValidationRegex string `json:"validation_regex,omitempty"`
+}
+
+// CustomFieldSchema represents the custom bug fields defined for an application
+type CustomFieldSchema struct {
+	ID            uuid.UUID                            `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	ApplicationID uuid.UUID                            `json:"application_id" gorm:"type:uuid;not null;uniqueIndex"`
+	Fields        datatypes.JSONSlice[FieldDefinition] `json:"fields" gorm:"type:jsonb"`
+	CreatedAt     time.Time                            `json:"created_at"`
+	UpdatedAt     time.Time                            `json:"updated_at"`
+
+	// Relationships
+	Application Application `json:"application,omitempty" gorm:"foreignKey:ApplicationID"`
+}
+
+// BeforeCreate hook to set ID if not provided
+func (cfs *CustomFieldSchema) BeforeCreate(tx *gorm.DB) error {
+	if cfs.ID == uuid.Nil {
+		cfs.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for the CustomFieldSchema model
+func (CustomFieldSchema) TableName() string {
+	return "custom_field_schemas"
+}
+
+// CustomFieldType constants
+const (
+	CustomFieldTypeString  = "string"
+	CustomFieldTypeNumber  = "number"
+	CustomFieldTypeBoolean = "boolean"
+)
+
+// Validate checks decoded custom field values against the schema
+func (cfs *CustomFieldSchema) Validate(values map[string]interface{}) error {
+	defined := make(map[string]bool, len(cfs.Fields))
+
+	for _, field := range cfs.Fields {
+		defined[field.Name] = true
+
+		value, ok := values[field.Name]
+		if !ok || value == nil || value == "" {
+			if field.Required {
+				return fmt.Errorf("custom field %q is required", field.Name)
+			}
+			continue
+		}
+
+		switch field.Type {
+		case CustomFieldTypeNumber:
+			if _, ok := value.(float64); !ok {
+				return fmt.Errorf("custom field %q must be a number", field.Name)
+			}
+		case CustomFieldTypeBoolean:
+			if _, ok := value.(bool); !ok {
+				return fmt.Errorf("custom field %q must be a boolean", field.Name)
+			}
+		default:
+			if _, ok := value.(string); !ok {
+				return fmt.Errorf("custom field %q must be a string", field.Name)
+			}
+		}
+
+		if field.ValidationRegex != "" {
+			re, err := regexp.Compile(field.ValidationRegex)
+			if err != nil {
+				return fmt.Errorf("custom field %q has an invalid validation pattern", field.Name)
+			}
+			if !re.MatchString(fmt.Sprint(value)) {
+				return fmt.Errorf("custom field %q has an invalid format", field.Name)
+			}
+		}
+	}
+
+	for name := range values {
+		if !defined[name] {
+			return fmt.Errorf("unknown custom field %q", name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomFieldSchema_Validate(t *testing.T) {
+	schema := CustomFieldSchema{
+		Fields: []FieldDefinition{
+			{Name: "game_version", Type: CustomFieldTypeString, Required: true, ValidationRegex: `^\d+\.\d+\.\d+$`},
+			{Name: "level_id", Type: CustomFieldTypeNumber},
+			{Name: "multiplayer", Type: CustomFieldTypeBoolean},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		values  map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:   "valid values",
+			values: map[string]interface{}{"game_version": "1.2.3", "level_id": float64(4), "multiplayer": true},
+		},
+		{
+			name:    "missing required field",
+			values:  map[string]interface{}{"level_id": float64(4)},
+			wantErr: `custom field "game_version" is required`,
+		},
+		{
+			name:    "wrong type",
+			values:  map[string]interface{}{"game_version": "1.2.3", "level_id": "four"},
+			wantErr: `custom field "level_id" must be a number`,
+		},
+		{
+			name:    "regex mismatch",
+			values:  map[string]interface{}{"game_version": "latest"},
+			wantErr: `custom field "game_version" has an invalid format`,
+		},
+		{
+			name:    "unknown field",
+			values:  map[string]interface{}{"game_version": "1.2.3", "server": "eu"},
+			wantErr: `unknown custom field "server"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.Validate(tt.values)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
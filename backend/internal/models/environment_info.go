@@ -0,0 +1,67 @@
+package models
+
+import "strings"
+
+// EnvironmentInfo describes the environment a bug was reported from. It is stored as
+// JSON in BugReport.EnvironmentInfo.
+type EnvironmentInfo struct {
+	OSName           string `json:"os_name,omitempty"`
+	OSVersion        string `json:"os_version,omitempty"`
+	BrowserName      string `json:"browser_name,omitempty"`
+	BrowserVersion   string `json:"browser_version,omitempty"`
+	ScreenResolution string `json:"screen_resolution,omitempty"`
+	Language         string `json:"language,omitempty"`
+	Timezone         string `json:"timezone,omitempty"`
+}
+
+// IsEmpty reports whether no environment details are known
+func (e EnvironmentInfo) IsEmpty() bool {
+	return e == EnvironmentInfo{}
+}
+
+// Names of the operating systems bug listings can be filtered by, as stored in
+// EnvironmentInfo.OSName
+const (
+	OSLinux   = "Linux"
+	OSWindows = "Windows"
+	OSMacOS   = "macOS"
+	OSIOS     = "iOS"
+	OSAndroid = "Android"
+)
+
+// osFilters maps the os filter values of bug listings to the OS name they match
+var osFilters = map[string]string{
+	"linux":   OSLinux,
+	"windows": OSWindows,
+	"macos":   OSMacOS,
+	"ios":     OSIOS,
+	"android": OSAndroid,
+}
+
+// OSNameForFilter returns the OS name matched by an os filter value such as "macos"
+func OSNameForFilter(filter string) (string, bool) {
+	name, ok := osFilters[strings.ToLower(filter)]
+	return name, ok
+}
+
+// NormalizeOSName returns the name bug listings filter by for a recognised operating
+// system, e.g. macOS for "Mac OS X", and other names unchanged
+func NormalizeOSName(name string) string {
+	lower := strings.ToLower(strings.TrimSpace(name))
+	switch {
+	case lower == "":
+		return name
+	// Android and iOS first, as their names can mention Linux and Mac OS X
+	case strings.Contains(lower, "android"):
+		return OSAndroid
+	case lower == "ios" || strings.HasPrefix(lower, "ios ") || strings.Contains(lower, "iphone") || strings.Contains(lower, "ipad"):
+		return OSIOS
+	case strings.Contains(lower, "mac") || strings.Contains(lower, "os x") || strings.Contains(lower, "darwin"):
+		return OSMacOS
+	case strings.HasPrefix(lower, "win"):
+		return OSWindows
+	case strings.Contains(lower, "linux") || strings.Contains(lower, "ubuntu") || strings.Contains(lower, "debian") || strings.Contains(lower, "fedora"):
+		return OSLinux
+	}
+	return name
+}
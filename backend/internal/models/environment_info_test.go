@@ -0,0 +1,38 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeOSName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "Mac OS X", want: OSMacOS},
+		{name: "macos", want: OSMacOS},
+		{name: "Windows 11", want: OSWindows},
+		{name: "Ubuntu", want: OSLinux},
+		{name: "Android", want: OSAndroid},
+		{name: "iPadOS", want: OSIOS},
+		{name: "Chrome OS", want: "Chrome OS"},
+		{name: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeOSName(tt.name))
+		})
+	}
+}
+
+func TestOSNameForFilter(t *testing.T) {
+	name, ok := OSNameForFilter("MacOS")
+	assert.True(t, ok)
+	assert.Equal(t, OSMacOS, name)
+
+	_, ok = OSNameForFilter("beos")
+	assert.False(t, ok)
+}
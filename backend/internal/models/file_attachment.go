@@ -17,8 +17,16 @@ type FileAttachment struct {
 	MimeType   *string   `json:"mime_type,omitempty" gorm:"size:100"`
 	UploadedAt time.Time `json:"uploaded_at"`
 
+	UploadedByID  *uuid.UUID     `json:"uploaded_by_id,omitempty" gorm:"type:uuid"`
+	DownloadCount int            `json:"download_count" gorm:"not null;default:0"`
+	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// PreviewURL is generated by the storage backend when the attachment is returned
+	PreviewURL string `json:"preview_url,omitempty" gorm:"-"`
+
 	// Relationships
-	Bug BugReport `json:"bug,omitempty" gorm:"foreignKey:BugID"`
+	Bug        BugReport `json:"bug,omitempty" gorm:"foreignKey:BugID"`
+	UploadedBy *User     `json:"-" gorm:"foreignKey:UploadedByID"`
 }
 
 // BeforeCreate hook to set ID if not provided
@@ -32,4 +40,4 @@ func (fa *FileAttachment) BeforeCreate(tx *gorm.DB) error {
 // TableName returns the table name for the FileAttachment model
 func (FileAttachment) TableName() string {
 	return "file_attachments"
-}
\ No newline at end of file
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// IPBlock records an administrator blocking an IP address or CIDR range until
+// ExpiresAt. Requests from blocked addresses are rejected before reaching a handler.
+type IPBlock struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	IP          string    `json:"ip" gorm:"type:varchar(50);not null;index"`
+	Reason      string    `json:"reason" gorm:"type:varchar(500);not null"`
+	BlockedByID uuid.UUID `json:"blocked_by_id" gorm:"type:uuid;not null"`
+	ExpiresAt   time.Time `json:"expires_at" gorm:"not null;index"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// Relationships
+	BlockedBy User `json:"blocked_by,omitempty" gorm:"foreignKey:BlockedByID"`
+}
+
+// BeforeCreate hook to set ID if not provided
+func (b *IPBlock) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for the IPBlock model
+func (IPBlock) TableName() string {
+	return "ip_blocks"
+}
@@ -14,9 +14,26 @@ func AllModels() []interface{} {
 		&BugReport{},
 		&BugVote{},
 		&Comment{},
+		&CommentEdit{},
 		&FileAttachment{},
 		&JWTBlacklist{},
 		&AuditLog{},
+		&CustomFieldSchema{},
+		&BugAssignmentRule{},
+		&CompanyWebhook{},
+		&OutboxEvent{},
+		&CompanyInvitation{},
+		&CompanyInviteLink{},
+		&UserBlock{},
+		&NotificationPreferences{},
+		&SecurityEvent{},
+		&ModerationQueueEntry{},
+		&BugEvent{},
+		&SearchQuery{},
+		&Notification{},
+		&BugSubscription{},
+		&IPBlock{},
+		&BugEditHistory{},
 	}
 }
 
@@ -72,5 +89,35 @@ func CreateIndexes(db *gorm.DB) error {
 		return err
 	}
 
+	// Indexes for common ListBugs query patterns
+	listBugsIndexes := []string{
+		"CREATE INDEX IF NOT EXISTS idx_bug_reports_status_created_at ON bug_reports(status, created_at DESC)",
+		"CREATE INDEX IF NOT EXISTS idx_bug_reports_priority_created_at ON bug_reports(priority, created_at DESC)",
+		"CREATE INDEX IF NOT EXISTS idx_bug_reports_application_status ON bug_reports(application_id, status)",
+		"CREATE INDEX IF NOT EXISTS idx_bug_reports_assigned_company_status ON bug_reports(assigned_company_id, status)",
+		"CREATE INDEX IF NOT EXISTS idx_bug_reports_reporter ON bug_reports(reporter_id)",
+		"CREATE INDEX IF NOT EXISTS idx_bug_reports_environment_os ON bug_reports((environment_info->>'os_name'))",
+		"CREATE INDEX IF NOT EXISTS idx_bug_votes_user ON bug_votes(user_id)",
+		"CREATE INDEX IF NOT EXISTS idx_company_members_user_company ON company_members(user_id, company_id)",
+	}
+	for _, stmt := range listBugsIndexes {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+
+	// Stored search vector for searches scoped to one application, see
+	// migrations/032_application_search_index.up.sql
+	searchVectorStatements := []string{
+		"ALTER TABLE bug_reports ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (to_tsvector('english', title || ' ' || description)) STORED",
+		"CREATE INDEX IF NOT EXISTS idx_bug_reports_active_search_vector ON bug_reports USING gin(search_vector) WHERE deleted_at IS NULL",
+		"CREATE INDEX IF NOT EXISTS idx_bug_reports_application_search ON bug_reports(application_id) INCLUDE (search_vector)",
+	}
+	for _, stmt := range searchVectorStatements {
+		if err := db.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// setupPostgresTestDB connects to the database in TEST_DATABASE_URL, skipping when unset
+func setupPostgresTestDB(t *testing.T) *gorm.DB {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set, skipping PostgreSQL test")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	require.NoError(t, err)
+
+	require.NoError(t, db.Exec(`CREATE EXTENSION IF NOT EXISTS "uuid-ossp"`).Error)
+	require.NoError(t, AutoMigrate(db))
+
+	return db
+}
+
+func TestCreateIndexes_ListBugsIndexes(t *testing.T) {
+	db := setupPostgresTestDB(t)
+
+	require.NoError(t, CreateIndexes(db))
+
+	expected := map[string]string{
+		"idx_bug_reports_status_created_at":       "bug_reports",
+		"idx_bug_reports_priority_created_at":     "bug_reports",
+		"idx_bug_reports_application_status":      "bug_reports",
+		"idx_bug_reports_assigned_company_status": "bug_reports",
+		"idx_bug_reports_reporter":                "bug_reports",
+		"idx_bug_votes_user":                      "bug_votes",
+		"idx_company_members_user_company":        "company_members",
+	}
+
+	for indexName, tableName := range expected {
+		var count int64
+		err := db.Raw(
+			"SELECT COUNT(*) FROM pg_indexes WHERE schemaname = current_schema() AND tablename = ? AND indexname = ?",
+			tableName, indexName,
+		).Scan(&count).Error
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count, "expected index %s on %s", indexName, tableName)
+	}
+}
+
+func TestRefreshBugStatsByCompany(t *testing.T) {
+	db := setupPostgresTestDB(t)
+	migration, err := os.ReadFile("../../migrations/004_bug_stats_materialized_view.up.sql")
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(string(migration)).Error)
+
+	company := Company{Name: "Stats Co", Domain: "stats-" + uuid.New().String() + ".com"}
+	require.NoError(t, db.Create(&company).Error)
+	app := Application{Name: "Stats App", CompanyID: &company.ID}
+	require.NoError(t, db.Create(&app).Error)
+
+	for _, status := range []string{BugStatusOpen, BugStatusOpen, BugStatusFixed} {
+		bug := BugReport{
+			Title:             "Stats bug",
+			Description:       "Bug used for materialized view stats",
+			Status:            status,
+			Priority:          BugPriorityMedium,
+			ApplicationID:     app.ID,
+			AssignedCompanyID: &company.ID,
+		}
+		require.NoError(t, db.Create(&bug).Error)
+	}
+
+	require.NoError(t, RefreshBugStatsByCompany(db))
+
+	var rows []BugStatsByCompany
+	require.NoError(t, db.Where("assigned_company_id = ?", company.ID).Find(&rows).Error)
+
+	counts := make(map[string]int64)
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	assert.Equal(t, int64(2), counts[BugStatusOpen])
+	assert.Equal(t, int64(1), counts[BugStatusFixed])
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// ModerationQueueEntry is an item raised automatically for administrators to review
+type ModerationQueueEntry struct {
+	ID         uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	EntryType  string         `json:"entry_type" gorm:"size:50;not null;index"`
+	Subject    string         `json:"subject" gorm:"size:255;not null"` // what the entry is about, e.g. an IP address
+	Details    datatypes.JSON `json:"details,omitempty" gorm:"type:jsonb"`
+	ResolvedAt *time.Time     `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+// BeforeCreate hook to set ID if not provided
+func (e *ModerationQueueEntry) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for the ModerationQueueEntry model
+func (ModerationQueueEntry) TableName() string {
+	return "moderation_queue"
+}
+
+// ModerationQueueEntry type constants
+const (
+	ModerationEntrySuspiciousIP = "suspicious_ip"
+)
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Notification is an in-app notification for a user, such as their bug reaching a
+// vote milestone. Type is one of the NotificationType constants and ResourceID the
+// bug or other record the notification is about.
+type Notification struct {
+	ID         uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID     uuid.UUID      `json:"user_id" gorm:"type:uuid;not null;index"`
+	Type       string         `json:"type" gorm:"size:30;not null"`
+	ResourceID *uuid.UUID     `json:"resource_id,omitempty" gorm:"type:uuid;index"`
+	Body       string         `json:"body" gorm:"type:text;not null"`
+	Payload    datatypes.JSON `json:"payload,omitempty" gorm:"type:jsonb"`
+	ReadAt     *time.Time     `json:"read_at,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+// BeforeCreate hook to set ID if not provided
+func (n *Notification) BeforeCreate(tx *gorm.DB) error {
+	if n.ID == uuid.Nil {
+		n.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for the Notification model
+func (Notification) TableName() string {
+	return "notifications"
+}
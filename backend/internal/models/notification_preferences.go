@@ -0,0 +1,132 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// NotificationPreferences records which notifications a user receives.
+// Users without a record receive every notification type except marketing.
+type NotificationPreferences struct {
+	ID              uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID          uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex"`
+	BugStatusChange bool      `json:"bug_status_change" gorm:"default:true"`
+	CommentOnMyBug  bool      `json:"comment_on_my_bug" gorm:"default:true"`
+	CommentReply    bool      `json:"comment_reply" gorm:"default:true"`
+	VoteMilestone   bool      `json:"vote_milestone" gorm:"default:true"`
+	CompanyResponse bool      `json:"company_response" gorm:"default:true"`
+	Mention         bool      `json:"mention" gorm:"default:true"`
+	DigestFrequency string    `json:"digest_frequency" gorm:"size:20;default:'never'"`
+	Marketing       bool      `json:"marketing" gorm:"default:false"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// BeforeCreate hook to set ID if not provided
+func (np *NotificationPreferences) BeforeCreate(tx *gorm.DB) error {
+	if np.ID == uuid.Nil {
+		np.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for the NotificationPreferences model
+func (NotificationPreferences) TableName() string {
+	return "notification_preferences"
+}
+
+// DefaultNotificationPreferences returns the preferences of a user who has not changed any
+func DefaultNotificationPreferences(userID uuid.UUID) NotificationPreferences {
+	return NotificationPreferences{
+		UserID:          userID,
+		BugStatusChange: true,
+		CommentOnMyBug:  true,
+		CommentReply:    true,
+		VoteMilestone:   true,
+		CompanyResponse: true,
+		Mention:         true,
+		DigestFrequency: DigestFrequencyNever,
+		Marketing:       false,
+	}
+}
+
+// NotificationType constants, matching the preference column names
+const (
+	NotificationTypeBugStatusChange = "bug_status_change"
+	NotificationTypeCommentOnMyBug  = "comment_on_my_bug"
+	NotificationTypeCommentReply    = "comment_reply"
+	NotificationTypeVoteMilestone   = "vote_milestone"
+	NotificationTypeCompanyResponse = "company_response"
+	NotificationTypeMention         = "mention"
+	NotificationTypeMarketing       = "marketing"
+	// NotificationTypeAll is only used to unsubscribe from every type at once
+	NotificationTypeAll = "all"
+)
+
+// NotificationTypeBugOwnershipTransfer tells a user an admin moved one of their bugs
+// to or from them. It cannot be turned off, so it has no preference column.
+const NotificationTypeBugOwnershipTransfer = "bug_ownership_transfer"
+
+// NotificationTypeBugAutoEscalated tells company members one of their bugs was raised
+// to a higher priority because it is gathering votes quickly. It cannot be turned off.
+const NotificationTypeBugAutoEscalated = "bug_auto_escalated"
+
+// NotificationTypes lists every notification type a user can turn off
+var NotificationTypes = []string{
+	NotificationTypeBugStatusChange,
+	NotificationTypeCommentOnMyBug,
+	NotificationTypeCommentReply,
+	NotificationTypeVoteMilestone,
+	NotificationTypeCompanyResponse,
+	NotificationTypeMention,
+	NotificationTypeMarketing,
+}
+
+// DigestFrequency constants
+const (
+	DigestFrequencyNever  = "never"
+	DigestFrequencyDaily  = "daily"
+	DigestFrequencyWeekly = "weekly"
+)
+
+// IsValidNotificationType checks if a notification type is valid
+func IsValidNotificationType(notificationType string) bool {
+	for _, valid := range NotificationTypes {
+		if notificationType == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidDigestFrequency checks if a digest frequency is valid
+func IsValidDigestFrequency(frequency string) bool {
+	switch frequency {
+	case DigestFrequencyNever, DigestFrequencyDaily, DigestFrequencyWeekly:
+		return true
+	}
+	return false
+}
+
+// Allows reports whether the user receives notifications of the given type
+func (np *NotificationPreferences) Allows(notificationType string) bool {
+	switch notificationType {
+	case NotificationTypeBugStatusChange:
+		return np.BugStatusChange
+	case NotificationTypeCommentOnMyBug:
+		return np.CommentOnMyBug
+	case NotificationTypeCommentReply:
+		return np.CommentReply
+	case NotificationTypeVoteMilestone:
+		return np.VoteMilestone
+	case NotificationTypeCompanyResponse:
+		return np.CompanyResponse
+	case NotificationTypeMention:
+		return np.Mention
+	case NotificationTypeMarketing:
+		return np.Marketing
+	}
+	return true
+}
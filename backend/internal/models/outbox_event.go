@@ -0,0 +1,73 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// OutboxEvent is a webhook or email queued in the same transaction as the change
+// that caused it, so it is delivered even if the server stops before sending
+type OutboxEvent struct {
+	ID             uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	EventType      string         `json:"event_type" gorm:"size:100;not null"`
+	Payload        datatypes.JSON `json:"payload" gorm:"type:jsonb;not null"`
+	Status         string         `json:"status" gorm:"size:20;default:'pending';index"`
+	RetryCount     int            `json:"retry_count" gorm:"default:0"`
+	LastError      *string        `json:"last_error,omitempty" gorm:"type:text"`
+	CreatedAt      time.Time      `json:"created_at"`
+	ProcessedAt    *time.Time     `json:"processed_at,omitempty"`
+	DeadLetteredAt *time.Time     `json:"dead_lettered_at,omitempty"`
+}
+
+// BeforeCreate hook to set ID if not provided
+func (e *OutboxEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for the OutboxEvent model
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
+
+// OutboxStatus constants
+const (
+	OutboxStatusPending      = "pending"
+	OutboxStatusProcessed    = "processed"
+	OutboxStatusDeadLettered = "dead_lettered"
+)
+
+// OutboxEventType constants
+const (
+	OutboxEventBugCreated = "bug.created"
+	OutboxEventEmail      = "email"
+)
+
+// EmailPayload is the payload of an OutboxEventEmail event
+type EmailPayload struct {
+	To      []string `json:"to"`
+	Subject string   `json:"subject"`
+	Body    string   `json:"body"`
+}
+
+// EnqueueOutboxEvent stores an event for delivery by the outbox processor. Pass the
+// transaction that writes the related change so both commit or roll back together.
+func EnqueueOutboxEvent(tx *gorm.DB, eventType string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	event := OutboxEvent{
+		EventType: eventType,
+		Payload:   datatypes.JSON(data),
+		Status:    OutboxStatusPending,
+	}
+	return tx.Create(&event).Error
+}
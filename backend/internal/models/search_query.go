@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SearchQuery records a bug search and how many results it returned, for search
+// analytics. Query text is stored normalized so repeated searches group together.
+type SearchQuery struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	QueryText   string     `json:"query_text" gorm:"size:255;not null;index"`
+	ResultCount int        `json:"result_count" gorm:"not null;default:0"`
+	UserID      *uuid.UUID `json:"user_id,omitempty" gorm:"type:uuid"`
+	SessionID   *string    `json:"session_id,omitempty" gorm:"size:100"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"index"`
+}
+
+// BeforeCreate hook to set ID if not provided
+func (sq *SearchQuery) BeforeCreate(tx *gorm.DB) error {
+	if sq.ID == uuid.Nil {
+		sq.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for the SearchQuery model
+func (SearchQuery) TableName() string {
+	return "search_queries"
+}
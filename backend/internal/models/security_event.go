@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// SecurityEvent records a suspicious or security-relevant authentication event.
+// Security events are never deleted, so the model has no DeletedAt.
+type SecurityEvent struct {
+	ID        uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	UserID    *uuid.UUID     `json:"user_id,omitempty" gorm:"type:uuid;index"`
+	EventType string         `json:"event_type" gorm:"size:30;not null;index"`
+	IPAddress string         `json:"ip_address" gorm:"size:45;index"`
+	UserAgent string         `json:"user_agent" gorm:"size:500"`
+	Details   datatypes.JSON `json:"details,omitempty" gorm:"type:jsonb"`
+	CreatedAt time.Time      `json:"created_at" gorm:"index"`
+}
+
+// BeforeCreate hook to set ID if not provided
+func (se *SecurityEvent) BeforeCreate(tx *gorm.DB) error {
+	if se.ID == uuid.Nil {
+		se.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for the SecurityEvent model
+func (SecurityEvent) TableName() string {
+	return "security_events"
+}
+
+// SecurityEventType constants
+const (
+	SecurityEventFailedLogin   = "failed_login"
+	SecurityEventAccountLocked = "account_locked"
+	SecurityEventTokenRejected = "token_rejected"
+	SecurityEventPasswordReset = "password_reset"
+	SecurityEventEmailChange   = "email_change"
+	// A session's access token was used from an IP other than the one it is bound to
+	SecurityEventSessionIPMismatch = "session_ip_mismatch"
+	// An anonymous bug submission was rejected for reaching its contact email's daily limit
+	SecurityEventAnonymousEmailLimit = "anonymous_email_limit"
+)
+
+// IsValidSecurityEventType checks if a security event type is valid
+func IsValidSecurityEventType(eventType string) bool {
+	switch eventType {
+	case SecurityEventFailedLogin, SecurityEventAccountLocked, SecurityEventTokenRejected,
+		SecurityEventPasswordReset, SecurityEventEmailChange, SecurityEventSessionIPMismatch,
+		SecurityEventAnonymousEmailLimit:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// SLATargets is how long a bug of each priority may stay unresolved
+var SLATargets = map[string]time.Duration{
+	BugPriorityCritical: 24 * time.Hour,
+	BugPriorityHigh:     3 * 24 * time.Hour,
+	BugPriorityMedium:   7 * 24 * time.Hour,
+	BugPriorityLow:      30 * 24 * time.Hour,
+}
+
+// SLAWarningFraction is the share of the SLA target remaining at which a bug is
+// reported as at risk of breaching
+const SLAWarningFraction = 0.25
+
+// SLAStatus constants
+const (
+	SLAStatusOK       = "ok"
+	SLAStatusWarning  = "warning"
+	SLAStatusBreached = "breached"
+)
+
+// IsValidSLAStatus checks if the provided SLA status is valid
+func IsValidSLAStatus(status string) bool {
+	switch status {
+	case SLAStatusOK, SLAStatusWarning, SLAStatusBreached:
+		return true
+	}
+	return false
+}
+
+// IsResolved reports whether the bug no longer needs work
+func (br *BugReport) IsResolved() bool {
+	return br.Status == BugStatusFixed || br.Status == BugStatusWontFix
+}
+
+// SLADeadline returns when the bug breaches its SLA. Resolved bugs and bugs
+// without an SLA target have no deadline.
+func (br *BugReport) SLADeadline() (time.Time, bool) {
+	target, ok := SLATargets[br.Priority]
+	if !ok || br.IsResolved() {
+		return time.Time{}, false
+	}
+	return br.CreatedAt.Add(target), true
+}
+
+// SLAStatus returns the bug's SLA status at the given time
+func (br *BugReport) SLAStatus(now time.Time) string {
+	deadline, ok := br.SLADeadline()
+	if !ok {
+		return SLAStatusOK
+	}
+
+	remaining := deadline.Sub(now)
+	switch {
+	case remaining <= 0:
+		return SLAStatusBreached
+	case remaining < time.Duration(float64(SLATargets[br.Priority])*SLAWarningFraction):
+		return SLAStatusWarning
+	default:
+		return SLAStatusOK
+	}
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBugReport_SLAStatus(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		priority string
+		status   string
+		age      time.Duration
+		want     string
+	}{
+		{name: "new critical bug", priority: BugPriorityCritical, status: BugStatusOpen, age: time.Hour, want: SLAStatusOK},
+		{name: "critical bug near deadline", priority: BugPriorityCritical, status: BugStatusOpen, age: 20 * time.Hour, want: SLAStatusWarning},
+		{name: "critical bug past deadline", priority: BugPriorityCritical, status: BugStatusReviewing, age: 25 * time.Hour, want: SLAStatusBreached},
+		{name: "low bug after a week", priority: BugPriorityLow, status: BugStatusOpen, age: 7 * 24 * time.Hour, want: SLAStatusOK},
+		{name: "fixed bug past deadline", priority: BugPriorityCritical, status: BugStatusFixed, age: 48 * time.Hour, want: SLAStatusOK},
+		{name: "won't fix bug past deadline", priority: BugPriorityHigh, status: BugStatusWontFix, age: 10 * 24 * time.Hour, want: SLAStatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bug := BugReport{Priority: tt.priority, Status: tt.status, CreatedAt: now.Add(-tt.age)}
+			assert.Equal(t, tt.want, bug.SLAStatus(now))
+		})
+	}
+
+	t.Run("resolved bugs have no deadline", func(t *testing.T) {
+		bug := BugReport{Priority: BugPriorityHigh, Status: BugStatusFixed, CreatedAt: now}
+		_, ok := bug.SLADeadline()
+		assert.False(t, ok)
+	})
+}
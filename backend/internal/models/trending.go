@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RefreshTrendingScores stores each bug's trending score at now,
+// vote_count / (hours_since_created + 2) ^ gravity, so a higher gravity makes votes
+// count for less as a bug ages. Only bugs created after createdAfter are scored
+// when it is set; older bugs keep the score they last had.
+func RefreshTrendingScores(db *gorm.DB, gravity float64, now time.Time, createdAfter *time.Time) error {
+	// The age is computed from now rather than NOW(), so every bug is scored at the
+	// same instant and the refresh can be tested
+	query := db.Session(&gorm.Session{AllowGlobalUpdate: true}).Model(&BugReport{})
+	if createdAfter != nil {
+		query = query.Where("created_at > ?", *createdAfter)
+	}
+	return query.UpdateColumn("trending_score", gorm.Expr(
+		"vote_count / POWER((? - DATE_PART('epoch', created_at)) / 3600.0 + 2, ?)",
+		float64(now.Unix()), gravity,
+	)).Error
+}
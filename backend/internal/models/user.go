@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -27,12 +28,19 @@ type User struct {
 	PasswordResetToken   *string    `json:"-" gorm:"size:255"`
 	PasswordResetExpires *time.Time `json:"-"`
 
+	// Refresh tokens issued before this are rejected
+	LastPasswordChangedAt *time.Time `json:"-"`
+
 	// Roles
 	IsAdmin bool `json:"is_admin" gorm:"default:false"`
 
 	// Timestamps
-	CreatedAt    time.Time `json:"created_at"`
-	LastActiveAt time.Time `json:"last_active_at" gorm:"default:now()"`
+	CreatedAt    time.Time      `json:"created_at"`
+	LastActiveAt time.Time      `json:"last_active_at" gorm:"default:now()"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Set once the records a deleted user left behind have been cleaned up
+	CleanupCompletedAt *time.Time `json:"-"`
 
 	// Relationships
 	SubmittedBugs     []BugReport       `json:"submitted_bugs,omitempty" gorm:"foreignKey:ReporterID"`
@@ -42,6 +50,11 @@ type User struct {
 	JWTBlacklist      []JWTBlacklist    `json:"-" gorm:"foreignKey:UserID"`
 }
 
+// SystemUserID is the user that changes made by background jobs, such as automatic
+// priority escalations, are attributed to. Migration 043 creates the user; it has no
+// password, so nobody can sign in as it.
+var SystemUserID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
 // BeforeCreate hook to set ID if not provided
 func (u *User) BeforeCreate(tx *gorm.DB) error {
 	if u.ID == uuid.Nil {
@@ -53,4 +66,67 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 // TableName returns the table name for the User model
 func (User) TableName() string {
 	return "users"
-}
\ No newline at end of file
+}
+
+// CleanupDeletedUsers removes the records soft-deleted users left behind and marks
+// them cleaned up, returning how many users were cleaned up. Each user is cleaned
+// up in its own transaction, so a failure leaves earlier users done.
+func CleanupDeletedUsers(db *gorm.DB, now time.Time) (int, error) {
+	var userIDs []uuid.UUID
+	if err := db.Unscoped().Model(&User{}).
+		Where("deleted_at IS NOT NULL AND cleanup_completed_at IS NULL").
+		Pluck("id", &userIDs).Error; err != nil {
+		return 0, err
+	}
+
+	for i, userID := range userIDs {
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			return cleanupDeletedUser(tx, userID, now)
+		}); err != nil {
+			return i, fmt.Errorf("failed to clean up user %s: %w", userID, err)
+		}
+	}
+	return len(userIDs), nil
+}
+
+// cleanupDeletedUser deletes a user's votes, company memberships, notification
+// preferences and blocks, anonymizes their comments and detaches them from their
+// unresolved bugs
+func cleanupDeletedUser(tx *gorm.DB, userID uuid.UUID, now time.Time) error {
+	// Keep vote counts in step with the votes being removed
+	var votes []BugVote
+	if err := tx.Select("bug_id", "vote_weight").Where("user_id = ?", userID).Find(&votes).Error; err != nil {
+		return err
+	}
+	for _, vote := range votes {
+		if err := tx.Unscoped().Model(&BugReport{}).Where("id = ?", vote.BugID).Updates(map[string]interface{}{
+			"vote_count":          gorm.Expr("vote_count - 1"),
+			"weighted_vote_count": gorm.Expr("weighted_vote_count - ?", vote.VoteWeight),
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, model := range []interface{}{&BugVote{}, &CompanyMember{}, &NotificationPreferences{}} {
+		if err := tx.Where("user_id = ?", userID).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	if err := tx.Where("blocker_id = ? OR blocked_id = ?", userID, userID).Delete(&UserBlock{}).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Unscoped().Model(&Comment{}).Where("user_id = ?", userID).
+		Updates(map[string]interface{}{"user_id": nil, "content": "[deleted]"}).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Unscoped().Model(&BugReport{}).
+		Where("reporter_id = ? AND status IN ?", userID, []string{BugStatusOpen, BugStatusReviewing}).
+		Update("reporter_id", nil).Error; err != nil {
+		return err
+	}
+
+	return tx.Unscoped().Model(&User{}).Where("id = ?", userID).
+		Update("cleanup_completed_at", now).Error
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UserBlock records that one user has blocked another. Blocked users cannot
+// comment on the blocker's bugs, and neither user sees the other's comments.
+type UserBlock struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	BlockerID uuid.UUID `json:"blocker_id" gorm:"type:uuid;not null;uniqueIndex:idx_user_blocks_pair"`
+	BlockedID uuid.UUID `json:"blocked_id" gorm:"type:uuid;not null;uniqueIndex:idx_user_blocks_pair;index"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relationships
+	Blocker User `json:"-" gorm:"foreignKey:BlockerID"`
+	Blocked User `json:"blocked,omitempty" gorm:"foreignKey:BlockedID"`
+}
+
+// BeforeCreate hook to set ID if not provided
+func (ub *UserBlock) BeforeCreate(tx *gorm.DB) error {
+	if ub.ID == uuid.Nil {
+		ub.ID = uuid.New()
+	}
+	return nil
+}
+
+// TableName returns the table name for the UserBlock model
+func (UserBlock) TableName() string {
+	return "user_blocks"
+}
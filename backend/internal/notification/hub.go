@@ -0,0 +1,157 @@
+package notification
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Types of real-time bug events
+const (
+	EventBugStatusChanged = "bug.status_changed"
+	EventCommentCreated   = "bug.comment_created"
+	EventCompanyResponse  = "bug.company_response"
+)
+
+// SubscriberBufferSize is the number of events held for a connection that is not
+// keeping up. Further events for it are dropped until it catches up.
+const SubscriberBufferSize = 16
+
+// Event is a change to a bug sent to the users involved with it
+type Event struct {
+	Type      string      `json:"type"`
+	BugID     uuid.UUID   `json:"bug_id"`
+	ActorID   uuid.UUID   `json:"actor_id"`
+	Data      interface{} `json:"data,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// Hub fans bug events out to the open notification streams of the users involved
+// with the bug: its reporter, voters and commenters. Streams are held in memory, so
+// each instance only notifies the users connected to it.
+type Hub struct {
+	db *gorm.DB
+
+	mu          sync.RWMutex
+	subscribers map[uuid.UUID]map[chan Event]struct{}
+}
+
+// NewHub creates a notification hub that looks up who is involved with a bug in db
+func NewHub(db *gorm.DB) *Hub {
+	return &Hub{
+		db:          db,
+		subscribers: make(map[uuid.UUID]map[chan Event]struct{}),
+	}
+}
+
+// Subscribe opens a stream of the user's events. A user can have several streams
+// open, e.g. one per browser tab. The returned function closes the stream.
+func (h *Hub) Subscribe(userID uuid.UUID) (<-chan Event, func()) {
+	events := make(chan Event, SubscriberBufferSize)
+
+	h.mu.Lock()
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[chan Event]struct{})
+	}
+	h.subscribers[userID][events] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers[userID], events)
+			if len(h.subscribers[userID]) == 0 {
+				delete(h.subscribers, userID)
+			}
+			h.mu.Unlock()
+			close(events)
+		})
+	}
+	return events, unsubscribe
+}
+
+// PublishBugEvent sends the event to the connected users involved with its bug,
+// except the user who caused it. The database is only queried when someone other
+// than the actor is connected.
+func (h *Hub) PublishBugEvent(ctx context.Context, event Event) error {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	if !h.hasSubscribersOtherThan(event.ActorID) {
+		return nil
+	}
+
+	participants, err := h.bugParticipants(ctx, event.BugID)
+	if err != nil {
+		return err
+	}
+
+	h.send(participants, event.ActorID, event)
+	return nil
+}
+
+// hasSubscribersOtherThan reports whether a user other than exclude has an open stream
+func (h *Hub) hasSubscribersOtherThan(exclude uuid.UUID) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for userID := range h.subscribers {
+		if userID != exclude {
+			return true
+		}
+	}
+	return false
+}
+
+// bugParticipants returns the users who reported, voted on or commented on the bug.
+// They are matched against the connected users in memory, so the query does not
+// grow with the number of open streams.
+func (h *Hub) bugParticipants(ctx context.Context, bugID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := h.db.WithContext(ctx).Raw(`
+		SELECT DISTINCT user_id FROM (
+			SELECT reporter_id AS user_id FROM bug_reports WHERE id = ?
+			UNION SELECT user_id FROM bug_votes WHERE bug_id = ?
+			UNION SELECT user_id FROM comments WHERE bug_id = ?
+		) participants WHERE user_id IS NOT NULL`,
+		bugID, bugID, bugID,
+	).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var participants []uuid.UUID
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		participants = append(participants, userID)
+	}
+	return participants, rows.Err()
+}
+
+// send delivers the event to every stream of the connected users among users, other
+// than exclude, without blocking
+func (h *Hub) send(users []uuid.UUID, exclude uuid.UUID, event Event) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, userID := range users {
+		if userID == exclude {
+			continue
+		}
+		for events := range h.subscribers[userID] {
+			select {
+			case events <- event:
+			default:
+				// The stream is not keeping up, so it misses this event
+			}
+		}
+	}
+}
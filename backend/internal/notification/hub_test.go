@@ -0,0 +1,163 @@
+package notification
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupHubTestDB creates an in-memory database with the tables that relate users to bugs
+func setupHubTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	// The models' postgres defaults cannot be migrated on sqlite
+	require.NoError(t, db.Exec(`CREATE TABLE bug_reports (id TEXT PRIMARY KEY, reporter_id TEXT)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE bug_votes (id TEXT PRIMARY KEY, bug_id TEXT NOT NULL, user_id TEXT NOT NULL)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE comments (id TEXT PRIMARY KEY, bug_id TEXT NOT NULL, user_id TEXT)`).Error)
+	return db
+}
+
+// receive returns the next event on the stream, or fails if none arrives
+func receive(t *testing.T, events <-chan Event) Event {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+		return Event{}
+	}
+}
+
+// assertNoEvent fails if the stream has an event waiting
+func assertNoEvent(t *testing.T, events <-chan Event) {
+	t.Helper()
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected event %+v", event)
+	default:
+	}
+}
+
+func TestHub_PublishBugEvent(t *testing.T) {
+	db := setupHubTestDB(t)
+	hub := NewHub(db)
+
+	bugID := uuid.New()
+	reporter, voter, commenter, actor, bystander := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	require.NoError(t, db.Exec("INSERT INTO bug_reports (id, reporter_id) VALUES (?, ?)", bugID, reporter).Error)
+	require.NoError(t, db.Exec("INSERT INTO bug_votes (id, bug_id, user_id) VALUES (?, ?, ?)", uuid.New(), bugID, voter).Error)
+	require.NoError(t, db.Exec("INSERT INTO comments (id, bug_id, user_id) VALUES (?, ?, ?)", uuid.New(), bugID, commenter).Error)
+	require.NoError(t, db.Exec("INSERT INTO comments (id, bug_id, user_id) VALUES (?, ?, ?)", uuid.New(), bugID, actor).Error)
+	// Involved with another bug only
+	require.NoError(t, db.Exec("INSERT INTO bug_votes (id, bug_id, user_id) VALUES (?, ?, ?)", uuid.New(), uuid.New(), bystander).Error)
+
+	streams := map[uuid.UUID]<-chan Event{}
+	for _, userID := range []uuid.UUID{reporter, voter, commenter, actor, bystander} {
+		events, unsubscribe := hub.Subscribe(userID)
+		defer unsubscribe()
+		streams[userID] = events
+	}
+
+	// A second tab of the reporter gets the event too
+	reporterTab, unsubscribe := hub.Subscribe(reporter)
+	defer unsubscribe()
+
+	require.NoError(t, hub.PublishBugEvent(context.Background(), Event{
+		Type:    EventBugStatusChanged,
+		BugID:   bugID,
+		ActorID: actor,
+		Data:    map[string]string{"to": "fixed"},
+	}))
+
+	for _, events := range []<-chan Event{streams[reporter], reporterTab, streams[voter], streams[commenter]} {
+		event := receive(t, events)
+		assert.Equal(t, EventBugStatusChanged, event.Type)
+		assert.Equal(t, bugID, event.BugID)
+		assert.False(t, event.CreatedAt.IsZero())
+	}
+
+	// Not sent to the user who made the change or to users not involved
+	assertNoEvent(t, streams[actor])
+	assertNoEvent(t, streams[bystander])
+}
+
+func TestHub_QueryDoesNotGrowWithSubscribers(t *testing.T) {
+	db := setupHubTestDB(t)
+	var queryVars []int
+	require.NoError(t, db.Callback().Row().After("gorm:row").Register("test:count_vars", func(tx *gorm.DB) {
+		queryVars = append(queryVars, len(tx.Statement.Vars))
+	}))
+	hub := NewHub(db)
+
+	bugID, reporter := uuid.New(), uuid.New()
+	require.NoError(t, db.Exec("INSERT INTO bug_reports (id, reporter_id) VALUES (?, ?)", bugID, reporter).Error)
+
+	reporterEvents, unsubscribe := hub.Subscribe(reporter)
+	defer unsubscribe()
+	for i := 0; i < 1000; i++ {
+		_, unsubscribe := hub.Subscribe(uuid.New())
+		defer unsubscribe()
+	}
+
+	require.NoError(t, hub.PublishBugEvent(context.Background(), Event{Type: EventCommentCreated, BugID: bugID, ActorID: uuid.New()}))
+	receive(t, reporterEvents)
+	assert.Equal(t, []int{3}, queryVars)
+}
+
+func TestHub_NoSubscribers(t *testing.T) {
+	// Without open streams the database is not queried, so a closed one is fine
+	db := setupHubTestDB(t)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+
+	hub := NewHub(db)
+	assert.NoError(t, hub.PublishBugEvent(context.Background(), Event{Type: EventCommentCreated, BugID: uuid.New()}))
+
+	// Only the actor is connected
+	actor := uuid.New()
+	_, unsubscribe := hub.Subscribe(actor)
+	defer unsubscribe()
+	assert.NoError(t, hub.PublishBugEvent(context.Background(), Event{Type: EventCommentCreated, BugID: uuid.New(), ActorID: actor}))
+}
+
+func TestHub_Unsubscribe(t *testing.T) {
+	hub := NewHub(setupHubTestDB(t))
+	userID := uuid.New()
+
+	events, unsubscribe := hub.Subscribe(userID)
+	unsubscribe()
+	unsubscribe()
+
+	_, open := <-events
+	assert.False(t, open, "the stream is closed")
+	assert.False(t, hub.hasSubscribersOtherThan(uuid.Nil))
+}
+
+func TestHub_SlowSubscriberDropsEvents(t *testing.T) {
+	db := setupHubTestDB(t)
+	hub := NewHub(db)
+
+	bugID, reporter := uuid.New(), uuid.New()
+	require.NoError(t, db.Exec("INSERT INTO bug_reports (id, reporter_id) VALUES (?, ?)", bugID, reporter).Error)
+
+	events, unsubscribe := hub.Subscribe(reporter)
+	defer unsubscribe()
+
+	// Publishing never blocks on a full stream
+	for i := 0; i < SubscriberBufferSize+5; i++ {
+		require.NoError(t, hub.PublishBugEvent(context.Background(), Event{Type: EventCommentCreated, BugID: bugID}))
+	}
+	assert.Len(t, events, SubscriberBufferSize)
+}
@@ -0,0 +1,59 @@
+package router
+
+import (
+	"io"
+
+	"bugrelay-backend/internal/config"
+	"bugrelay-backend/internal/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newEngine creates the Gin engine in the configured mode. Release mode recovers
+// panics that escape the rest of the chain, debug mode logs requests and registered
+// routes to the structured logger, and test mode adds nothing so Gin stays quiet.
+func newEngine(server config.ServerConfig) *gin.Engine {
+	mode := server.GinMode
+	switch mode {
+	case gin.DebugMode, gin.ReleaseMode, gin.TestMode:
+	default:
+		logger.Warn("Unknown Gin mode, using release mode", logger.Fields{"gin_mode": mode})
+		mode = gin.ReleaseMode
+	}
+
+	if server.Environment == "production" && mode != gin.ReleaseMode {
+		logger.Warn("Production should run Gin in release mode", logger.Fields{"gin_mode": mode})
+	}
+
+	gin.SetMode(mode)
+	r := gin.New()
+
+	switch mode {
+	case gin.ReleaseMode:
+		r.Use(gin.Recovery())
+	case gin.DebugMode:
+		gin.DebugPrintRouteFunc = func(method, path, handler string, handlers int) {
+			logger.Debug("Route registered", logger.Fields{
+				"method":   method,
+				"path":     path,
+				"handler":  handler,
+				"handlers": handlers,
+			})
+		}
+		r.Use(gin.LoggerWithConfig(gin.LoggerConfig{
+			Output: io.Discard,
+			Formatter: func(param gin.LogFormatterParams) string {
+				logger.Debug("Gin request", logger.Fields{
+					"method":      param.Method,
+					"path":        param.Path,
+					"status_code": param.StatusCode,
+					"duration_ms": param.Latency.Milliseconds(),
+					"client_ip":   param.ClientIP,
+				})
+				return ""
+			},
+		}))
+	}
+
+	return r
+}
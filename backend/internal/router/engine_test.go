@@ -0,0 +1,105 @@
+package router
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"bugrelay-backend/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureOutput sends structured logs at debug level and Gin's own output to the
+// returned buffers until the test ends
+func captureOutput(t *testing.T) (logs, ginOutput *bytes.Buffer) {
+	logs, ginOutput = &bytes.Buffer{}, &bytes.Buffer{}
+
+	previousLevel, previousOutput := logrus.GetLevel(), logrus.StandardLogger().Out
+	previousWriter, previousRouteFunc := gin.DefaultWriter, gin.DebugPrintRouteFunc
+	logrus.SetLevel(logrus.DebugLevel)
+	logrus.SetOutput(logs)
+	gin.DefaultWriter = ginOutput
+	t.Cleanup(func() {
+		logrus.SetLevel(previousLevel)
+		logrus.SetOutput(previousOutput)
+		gin.DefaultWriter = previousWriter
+		gin.DebugPrintRouteFunc = previousRouteFunc
+		gin.SetMode(gin.TestMode)
+	})
+	return logs, ginOutput
+}
+
+// serve registers a route that responds or panics and requests it
+func serve(r *gin.Engine, panics bool) *httptest.ResponseRecorder {
+	r.GET("/ping", func(c *gin.Context) {
+		if panics {
+			panic("handler failed")
+		}
+		c.String(http.StatusOK, "pong")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/ping", nil))
+	return w
+}
+
+func TestNewEngine_DebugMode(t *testing.T) {
+	logs, _ := captureOutput(t)
+
+	r := newEngine(config.ServerConfig{Environment: "development", GinMode: gin.DebugMode})
+	assert.Equal(t, gin.DebugMode, gin.Mode())
+
+	w := serve(r, false)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// Registered routes and requests go to the structured logger
+	assert.Contains(t, logs.String(), "Route registered")
+	assert.Contains(t, logs.String(), "Gin request")
+	assert.Contains(t, logs.String(), "/ping")
+	assert.NotContains(t, logs.String(), "release mode")
+}
+
+func TestNewEngine_ReleaseMode(t *testing.T) {
+	logs, ginOutput := captureOutput(t)
+
+	r := newEngine(config.ServerConfig{Environment: "production", GinMode: gin.ReleaseMode})
+	assert.Equal(t, gin.ReleaseMode, gin.Mode())
+
+	// Panics are recovered with a 500
+	w := serve(r, true)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, logs.String(), "Route registered")
+	assert.NotContains(t, logs.String(), "Gin request")
+	assert.NotContains(t, ginOutput.String(), "[GIN-debug]")
+}
+
+func TestNewEngine_TestMode(t *testing.T) {
+	logs, ginOutput := captureOutput(t)
+
+	r := newEngine(config.ServerConfig{Environment: "development", GinMode: gin.TestMode})
+	assert.Equal(t, gin.TestMode, gin.Mode())
+
+	w := serve(r, false)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, logs.String())
+	assert.Empty(t, ginOutput.String())
+}
+
+func TestNewEngine_ProductionWarning(t *testing.T) {
+	logs, _ := captureOutput(t)
+
+	newEngine(config.ServerConfig{Environment: "production", GinMode: gin.DebugMode})
+	assert.Contains(t, logs.String(), "Production should run Gin in release mode")
+
+	// Unknown modes fall back to release mode
+	logs.Reset()
+	newEngine(config.ServerConfig{Environment: "production", GinMode: "verbose"})
+	assert.Equal(t, gin.ReleaseMode, gin.Mode())
+	assert.Contains(t, logs.String(), "Unknown Gin mode")
+	assert.NotContains(t, logs.String(), "Production should run Gin in release mode")
+}
@@ -1,28 +1,48 @@
 package router
 
 import (
+	"context"
 	"net/http"
 	"time"
 
+	_ "bugrelay-backend/internal/apidocs"
 	"bugrelay-backend/internal/auth"
+	"bugrelay-backend/internal/captcha"
 	"bugrelay-backend/internal/config"
+	"bugrelay-backend/internal/database"
+	"bugrelay-backend/internal/email"
+	"bugrelay-backend/internal/geoip"
+	"bugrelay-backend/internal/graph"
 	"bugrelay-backend/internal/handlers"
+	"bugrelay-backend/internal/jobs"
 	"bugrelay-backend/internal/logger"
 	"bugrelay-backend/internal/middleware"
+	"bugrelay-backend/internal/notification"
+	"bugrelay-backend/internal/routes"
+	"bugrelay-backend/internal/storage"
+	"bugrelay-backend/internal/utils"
+	"bugrelay-backend/internal/webhooks"
 
+	sentrygin "github.com/getsentry/sentry-go/gin"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/v9"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
 	"gorm.io/gorm"
 )
 
-func Setup(db *gorm.DB, redisClient *redis.Client, cfg *config.Config) *gin.Engine {
-	// Set Gin mode based on environment
-	if cfg.Server.Environment == "production" {
-		gin.SetMode(gin.ReleaseMode)
-	}
+func Setup(db *gorm.DB, redisClient *redis.Client, cfg *config.Config, bugProjector *jobs.BugProjector, backgroundQueue *jobs.Queue, webhookDispatcher *webhooks.Dispatcher) *gin.Engine {
+	r := newEngine(cfg.Server)
+
+	// Report panics to Sentry. ErrorLoggingMiddleware recovers handler panics and
+	// responds, so this only catches what escapes it and need not repanic.
+	r.Use(sentrygin.New(sentrygin.Options{Repanic: false}))
+	r.Use(middleware.SentryMiddleware())
 
-	r := gin.Default()
+	// Count and time every request for Prometheus
+	r.Use(middleware.MetricsMiddleware())
 
 	// Initialize security middleware
 	securityMiddleware := middleware.NewSecurityMiddleware([]string{})
@@ -35,12 +55,28 @@ func Setup(db *gorm.DB, redisClient *redis.Client, cfg *config.Config) *gin.Engi
 	// Apply security headers
 	r.Use(securityMiddleware.SecurityHeaders())
 
-	// Request size limit (10MB for file uploads, 1MB for regular requests)
-	r.Use(securityMiddleware.RequestSizeLimit(10 * 1024 * 1024))
+	// Request size limit (10MB for file uploads, configurable for regular requests)
+	r.Use(middleware.BodySizeLimit(cfg.Server.MaxRequestBodyBytes))
+
+	// Leave null fields out of JSON responses
+	if cfg.Server.StripNullsEnabled {
+		r.Use(middleware.NullFieldStripperMiddleware(middleware.NullStripperConfig{
+			Keys:     cfg.Server.StripNullsKeys,
+			KeepKeys: cfg.Server.StripNullsKeepKeys,
+			MaxBytes: cfg.Server.StripNullsMaxBytes,
+		}))
+	}
 
 	// Input sanitization
 	r.Use(securityMiddleware.InputSanitization())
 
+	// Reject requests from IP addresses and ranges blocked by administrators
+	ipBlocker := middleware.NewIPBlocker(redisClient, db)
+	if err := ipBlocker.Restore(context.Background()); err != nil {
+		logger.Error("Failed to restore IP blocks", err)
+	}
+	r.Use(ipBlocker.IPBlockMiddleware())
+
 	// User agent validation (skip for development)
 	if cfg.Server.Environment == "production" {
 		r.Use(securityMiddleware.ValidateUserAgent())
@@ -92,11 +128,16 @@ func Setup(db *gorm.DB, redisClient *redis.Client, cfg *config.Config) *gin.Engi
 			"Authorization",
 			"X-Requested-With",
 			"X-Request-ID",
+			"X-Session-ID",
+			"X-Idempotency-Key",
+			"X-Admin-Override",
 		},
 		ExposeHeaders: []string{
 			"X-Request-ID",
 			"X-RateLimit-Remaining",
 			"X-RateLimit-Reset",
+			"X-Country",
+			"X-Idempotency-Replayed",
 		},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
@@ -108,9 +149,15 @@ func Setup(db *gorm.DB, redisClient *redis.Client, cfg *config.Config) *gin.Engi
 		JWTSecret:       cfg.JWT.Secret,
 		AccessTokenTTL:  cfg.JWT.AccessTokenTTL,
 		RefreshTokenTTL: cfg.JWT.RefreshTokenTTL,
+		BindSessionToIP: cfg.Auth.BindSessionToIP,
+
+		IPChangeGracePeriod: time.Duration(cfg.Auth.IPChangeGracePeriodMinutes) * time.Minute,
 	}
 	authService := auth.NewService(authConfig, db, redisClient)
 	authMiddleware := middleware.NewAuthMiddleware(authService.GetJWTService(), authService.GetBlacklistService())
+	if cfg.Auth.BindSessionToIP {
+		authMiddleware.SetSessionIPBinding(authConfig.IPChangeGracePeriod, authService.GetIPChangeTracker(), db)
+	}
 
 	// Initialize OAuth service
 	oauthConfig := auth.OAuthConfig{
@@ -122,17 +169,121 @@ func Setup(db *gorm.DB, redisClient *redis.Client, cfg *config.Config) *gin.Engi
 	}
 	oauthService := auth.NewOAuthService(oauthConfig)
 
+	// Links in notification emails, with unsubscribe links signed by the JWT secret
+	deepLinks := email.NewDeepLinkGenerator(cfg.Server.FrontendURL, cfg.JWT.Secret)
+
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(db, authService)
-	oauthHandler := handlers.NewOAuthHandler(db, authService, oauthService)
+	authHandler.SetBackgroundQueue(backgroundQueue)
+	if cfg.SMTP.Host != "" {
+		emailService, err := email.NewSMTPService(email.Config{
+			Host:        cfg.SMTP.Host,
+			Port:        cfg.SMTP.Port,
+			Username:    cfg.SMTP.Username,
+			Password:    cfg.SMTP.Password,
+			From:        cfg.SMTP.From,
+			TemplateDir: cfg.SMTP.TemplateDir,
+		}, deepLinks)
+		if err != nil {
+			// Without an email service accounts are verified on registration
+			logger.Error("Failed to set up the email service", err, logger.Fields{
+				"template_dir": cfg.SMTP.TemplateDir,
+			})
+		} else {
+			authHandler.SetEmailService(emailService)
+		}
+	}
+	oauthHandler := handlers.NewOAuthHandler(db, redisClient, authService, oauthService)
 	bugHandler := handlers.NewBugHandler(db, redisClient)
-	bugHandler.SetRecaptchaSecret(cfg.Recaptcha.SecretKey)
-	companyHandler := handlers.NewCompanyHandler(db)
-	adminHandler := handlers.NewAdminHandler(db)
+	bugHandler.SetCaptchaVerifier(captcha.New(cfg.Captcha, &http.Client{Timeout: time.Duration(cfg.External.HTTPClientTimeoutSeconds) * time.Second}))
+	bugHandler.SetRecaptchaScoreThreshold(cfg.Captcha.RecaptchaScoreThreshold)
+	fileStorage := storage.New(cfg.Storage)
+	bugHandler.SetStorage(fileStorage)
+	bugHandler.SetSpamScoreThreshold(cfg.Spam.ScoreThreshold)
+	bugHandler.SetMinWordCounts(cfg.Validation.BugTitleMinWords, cfg.Validation.BugDescriptionMinWords)
+	utils.SetCommentMinChars(cfg.Validation.CommentMinChars)
+	bugHandler.SetTagConfig(utils.TagConfig{
+		MaxTags:   cfg.Validation.MaxTagsPerBug,
+		MinLength: cfg.Validation.MinTagLength,
+		MaxLength: cfg.Validation.MaxTagLength,
+	})
+	bugHandler.SetAnonymousBugsPerEmailPerDay(cfg.RateLimit.AnonymousBugsPerEmailPerDay)
+	geoReader, err := geoip.Open(cfg.GeoIP.DBPath)
+	if err != nil {
+		// Bugs are submitted without a location rather than failing startup
+		logger.Error("Failed to open GeoIP database", err, logger.Fields{
+			"path": cfg.GeoIP.DBPath,
+		})
+	} else if geoReader != nil {
+		bugHandler.SetGeoIPLookup(geoReader.Lookup)
+	}
+	bugHandler.SetVoteMilestones(cfg.Notifications.VoteMilestones)
+	bugHandler.SetTagGroups(cfg.Tags.TagGroups)
+	bugHandler.SetPagination(handlers.PaginationConfig{DefaultLimit: handlers.DefaultPageLimit, MaxLimit: cfg.Pagination.BugListMaxLimit})
+	bugHandler.SetTrending(handlers.TrendingConfig{WindowDays: cfg.Trending.WindowDays})
+	bugHandler.SetDeepLinks(deepLinks)
+	bugHandler.SetBugProjector(bugProjector)
+	// Bug changes are pushed to the users involved over Server-Sent Events
+	notificationHub := notification.NewHub(db)
+	bugHandler.SetNotificationHub(notificationHub)
+	bugHandler.SetWebhookDispatcher(webhookDispatcher)
+	if cfg.Server.HTMLRenderingEnabled {
+		templates, err := handlers.LoadHTMLTemplates(cfg.Server.TemplatesDir)
+		if err != nil {
+			// Without templates every client gets JSON
+			logger.Error("Failed to load HTML templates", err, logger.Fields{
+				"path": cfg.Server.TemplatesDir,
+			})
+		} else {
+			r.SetHTMLTemplate(templates)
+			bugHandler.SetHTMLRendering(true)
+		}
+	}
+	attachmentHandler := handlers.NewAttachmentHandler(storage.NewLocalBackend(storage.DefaultLocalDir))
+	companyHandler := handlers.NewCompanyHandler(db, redisClient)
+	applicationHandler := handlers.NewApplicationHandler(db, redisClient)
+	if languages, err := database.SearchLanguages(db); err != nil {
+		// Without the list applications can only search in the default language
+		logger.Error("Failed to load text search configurations", err)
+	} else {
+		applicationHandler.SetSearchLanguages(languages)
+	}
+	companyHandler.SetMaterializedDashboard(cfg.Features.MaterializedDashboard)
+	companyHandler.SetFrontendURL(cfg.Server.FrontendURL)
+	companyHandler.SetSpamScoreThreshold(cfg.Spam.ScoreThreshold)
+	companyHandler.SetPagination(handlers.PaginationConfig{DefaultLimit: handlers.DefaultPageLimit, MaxLimit: cfg.Pagination.CompanyListMaxLimit})
+	companyHandler.SetStorage(fileStorage)
+	companyHandler.SetInviteLinkMaxBypasses(cfg.Companies.InviteLinkMaxBypasses)
+	companyHandler.SetBackgroundQueue(backgroundQueue)
+	adminHandler := handlers.NewAdminHandler(db, redisClient)
+	adminHandler.SetParallelDashboardQueries(cfg.Features.ParallelDashboardQueries)
+	adminHandler.SetSpamScoreThreshold(cfg.Spam.ScoreThreshold)
+	adminHandler.SetStorage(fileStorage)
+	adminHandler.SetBugProjector(bugProjector)
+	adminHandler.SetPagination(handlers.PaginationConfig{DefaultLimit: handlers.DefaultPageLimit, MaxLimit: cfg.Pagination.GlobalOverrideMaxLimit})
+	userHandler := handlers.NewUserHandler(db, redisClient)
+	userHandler.SetDeepLinks(deepLinks)
+	userHandler.SetPagination(handlers.PaginationConfig{DefaultLimit: handlers.DefaultPageLimit, MaxLimit: cfg.Pagination.BugListMaxLimit})
 	logsHandler := handlers.NewLogsHandler()
+	errorCodeHandler := handlers.NewErrorCodeHandler()
+	notificationStreamHandler := handlers.NewNotificationStreamHandler(notificationHub)
 
 	// Initialize rate limiter
 	rateLimiter := middleware.NewRateLimiter(redisClient, 60)
+	anonymousRateLimit := rateLimiter.SlidingWindowLimiter(cfg.RateLimit.General.WindowSeconds, cfg.RateLimit.General.MaxRequests)
+	generalRateLimit := rateLimiter.PerUserRateLimit(cfg.RateLimit.AuthenticatedPerMinute, anonymousRateLimit)
+	writeRateLimit := rateLimiter.WriteRateLimit(cfg.RateLimit.WritePerMinute)
+	bugSubmissionRateLimit := rateLimiter.SlidingWindowLimiter(cfg.RateLimit.BugSubmission.WindowSeconds, cfg.RateLimit.BugSubmission.MaxRequests)
+	exportRateLimit := rateLimiter.UserSlidingWindowLimiter(cfg.RateLimit.Export.WindowSeconds, cfg.RateLimit.Export.MaxRequests)
+	if geoReader != nil {
+		// Without the database every client gets the default geographic limit
+		rateLimiter.SetGeoIPLookup(geoReader)
+	}
+	rateLimiter.SetRedactCountryHeader(cfg.Server.Environment == "production")
+	geoRateLimit := rateLimiter.GeoRateLimit(cfg.RateLimit.GeoLimits)
+	companyMiddleware := middleware.NewCompanyMiddleware(db)
+	adminHandler.SetRateLimiter(rateLimiter)
+	adminHandler.SetIPBlocker(ipBlocker)
 
 	// Health check endpoint
 	r.GET("/health", func(c *gin.Context) {
@@ -142,132 +293,49 @@ func Setup(db *gorm.DB, redisClient *redis.Client, cfg *config.Config) *gin.Engi
 		})
 	})
 
-	// API v1 routes
-	v1 := r.Group("/api/v1")
-	v1.Use(rateLimiter.GeneralRateLimit()) // Apply general rate limiting to all API routes
-	{
-		// Public routes
-		v1.GET("/status", func(c *gin.Context) {
-			c.JSON(http.StatusOK, gin.H{
-				"message": "BugRelay API v1 is running",
-				"version": "1.0.0",
-			})
-		})
-
-		// Authentication routes
-		auth := v1.Group("/auth")
-		{
-			// Public authentication endpoints
-			auth.POST("/register", authHandler.Register)
-			auth.POST("/login", authHandler.Login)
-			auth.POST("/refresh", authHandler.RefreshToken)
-			auth.GET("/verify-email", authHandler.VerifyEmail)
-
-			// Password reset endpoints
-			auth.POST("/password-reset", authHandler.RequestPasswordReset)
-			auth.POST("/password-reset/confirm", authHandler.ResetPassword)
-
-			// OAuth endpoints
-			oauth := auth.Group("/oauth")
-			{
-				oauth.GET("/:provider", oauthHandler.InitiateOAuth)
-				oauth.GET("/callback/:provider", oauthHandler.HandleOAuthCallback)
-				oauth.POST("/link/:provider", authMiddleware.RequireAuth(), oauthHandler.LinkOAuthAccount)
-			}
-
-			// Protected authentication endpoints
-			auth.POST("/logout", authMiddleware.RequireAuth(), authHandler.Logout)
-			auth.POST("/logout-all", authMiddleware.RequireAuth(), authHandler.LogoutAll)
-			auth.GET("/profile", authMiddleware.RequireAuth(), authHandler.GetProfile)
-			auth.PUT("/profile", authMiddleware.RequireAuth(), authHandler.UpdateProfile)
-		}
-
-		// Protected routes examples
-		protected := v1.Group("/protected")
-		protected.Use(authMiddleware.RequireAuth())
-		{
-			protected.GET("/test", func(c *gin.Context) {
-				userID, _ := middleware.GetCurrentUserID(c)
-				c.JSON(http.StatusOK, gin.H{
-					"message": "This is a protected endpoint",
-					"user_id": userID,
-				})
-			})
-		}
-
-		// Bug routes
-		bugs := v1.Group("/bugs")
-		{
-			// Public bug endpoints
-			bugs.GET("/", bugHandler.ListBugs)
-			bugs.GET("/:id", bugHandler.GetBug)
-			bugs.POST("/", rateLimiter.BugSubmissionRateLimit(), authMiddleware.OptionalAuth(), bugHandler.CreateBug)
-
-			// Protected bug endpoints
-			bugs.POST("/:id/vote", authMiddleware.RequireAuth(), bugHandler.VoteBug)
-			bugs.POST("/:id/comments", authMiddleware.RequireAuth(), bugHandler.CreateComment)
-			bugs.POST("/:id/attachments", authMiddleware.RequireAuth(), bugHandler.UploadBugAttachment)
-			bugs.PATCH("/:id/status", authMiddleware.RequireAuth(), bugHandler.UpdateBugStatus)
-			bugs.POST("/:id/company-response", authMiddleware.RequireAuth(), bugHandler.AddCompanyResponse)
-		}
-
-		// Company routes
-		companies := v1.Group("/companies")
-		{
-			// Public company endpoints
-			companies.GET("/", companyHandler.ListCompanies)
-			companies.GET("/:id", companyHandler.GetCompany)
-
-			// Protected company endpoints
-			companies.POST("/:id/claim", authMiddleware.RequireAuth(), companyHandler.InitiateCompanyClaim)
-			companies.POST("/:id/verify", authMiddleware.RequireAuth(), companyHandler.CompleteCompanyVerification)
-			companies.GET("/:id/dashboard", authMiddleware.RequireAuth(), companyHandler.GetCompanyDashboard)
-			companies.POST("/:id/members", authMiddleware.RequireAuth(), companyHandler.AddTeamMember)
-			companies.DELETE("/:id/members", authMiddleware.RequireAuth(), companyHandler.RemoveTeamMember)
-		}
-
-		// Admin routes with additional security
-		admin := v1.Group("/admin")
-		admin.Use(authMiddleware.RequireAdmin())
-		// Add IP whitelist for admin routes in production
-		if cfg.Server.Environment == "production" {
-			// Configure allowed admin IPs in production
-			adminIPs := []string{} // Add your admin IPs here
-			admin.Use(securityMiddleware.IPWhitelist(adminIPs))
-		}
-		{
-			// Dashboard and statistics
-			admin.GET("/dashboard", adminHandler.GetAdminDashboard)
-
-			// Bug moderation
-			admin.GET("/bugs", adminHandler.ListBugsForModeration)
-			admin.POST("/bugs/:id/flag", adminHandler.FlagBug)
-			admin.DELETE("/bugs/:id", adminHandler.RemoveBug)
-			admin.POST("/bugs/:id/restore", adminHandler.RestoreBug)
-			admin.POST("/bugs/merge", adminHandler.MergeBugs)
-
-			// Audit logs
-			admin.GET("/audit-logs", adminHandler.GetAuditLogs)
-		}
+	// Prometheus metrics, served only when scrapers have a token to authenticate with
+	if cfg.Metrics.Token != "" {
+		r.GET("/metrics", middleware.RequireMetricsToken(cfg.Metrics.Token), gin.WrapH(promhttp.Handler()))
+	}
 
-		// Logging routes
-		logs := v1.Group("/logs")
-		{
-			// Health check for logging system
-			logs.GET("/health", logsHandler.GetLogsHealth)
+	// OpenAPI spec and Swagger UI generated from the handler annotations
+	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	// Locally stored attachments and company logos, served outside the API rate limits
+	// so previews load freely
+	r.GET(storage.LocalServePath+":filename", attachmentHandler.ServeAttachment)
+	r.Static(storage.LocalPublicPath, storage.DefaultLocalPublicDir)
+
+	deps := &routes.Dependencies{
+		Config:                    cfg,
+		AuthHandler:               authHandler,
+		OAuthHandler:              oauthHandler,
+		BugHandler:                bugHandler,
+		CompanyHandler:            companyHandler,
+		ApplicationHandler:        applicationHandler,
+		AdminHandler:              adminHandler,
+		UserHandler:               userHandler,
+		LogsHandler:               logsHandler,
+		ErrorCodeHandler:          errorCodeHandler,
+		NotificationStreamHandler: notificationStreamHandler,
+		GraphQLHandler:            graph.NewHandler(db, bugHandler, companyHandler),
+		AuthMiddleware:            authMiddleware,
+		CompanyMiddleware:         companyMiddleware,
+		SecurityMiddleware:        securityMiddleware,
+		AdminConfirmer:            middleware.NewAdminConfirmer(redisClient, cfg.JWT.Secret),
+		GeneralRateLimit:          generalRateLimit,
+		BugSubmissionRateLimit:    bugSubmissionRateLimit,
+		GeoRateLimit:              geoRateLimit,
+		ExportRateLimit:           exportRateLimit,
+		WriteRateLimit:            writeRateLimit,
+	}
 
-			// Frontend logs endpoint (with API key protection)
-			logs.POST("/frontend", func(c *gin.Context) {
-				// Simple API key check for development
-				apiKey := c.GetHeader("X-API-Key")
-				if cfg.Server.Environment == "production" && apiKey != cfg.Server.LogsAPIKey {
-					c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
-					return
-				}
-				logsHandler.ReceiveFrontendLogs(c)
-			})
-		}
+	// v1 is always served so existing clients keep working after v2 ships
+	routes.V1(r.Group("/api/v1"), deps)
+	if cfg.Server.APIVersion == routes.VersionV2 {
+		routes.V2(r.Group("/api/v2"), deps)
 	}
+	routes.GraphQL(r.Group("/api"), deps)
 
 	return r
 }
@@ -0,0 +1,345 @@
+package routes
+
+import (
+	"net/http"
+
+	"bugrelay-backend/internal/config"
+	"bugrelay-backend/internal/graph"
+	"bugrelay-backend/internal/handlers"
+	"bugrelay-backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API versions
+const (
+	VersionV1 = "v1"
+	VersionV2 = "v2"
+)
+
+// Dependencies are the handlers and middleware the versioned route groups are
+// built from. Every version shares them, so v1 and v2 read and write the same data.
+type Dependencies struct {
+	Config *config.Config
+
+	AuthHandler        *handlers.AuthHandler
+	OAuthHandler       *handlers.OAuthHandler
+	BugHandler         *handlers.BugHandler
+	CompanyHandler     *handlers.CompanyHandler
+	ApplicationHandler *handlers.ApplicationHandler
+	AdminHandler       *handlers.AdminHandler
+	UserHandler        *handlers.UserHandler
+	LogsHandler        *handlers.LogsHandler
+	ErrorCodeHandler   *handlers.ErrorCodeHandler
+
+	NotificationStreamHandler *handlers.NotificationStreamHandler
+	GraphQLHandler            *graph.Handler
+
+	AuthMiddleware     *middleware.AuthMiddleware
+	CompanyMiddleware  *middleware.CompanyMiddleware
+	SecurityMiddleware *middleware.SecurityMiddleware
+	AdminConfirmer     *middleware.AdminConfirmer
+
+	GeneralRateLimit       gin.HandlerFunc
+	BugSubmissionRateLimit gin.HandlerFunc
+	GeoRateLimit           gin.HandlerFunc
+	// ExportRateLimit limits each user's bug exports and runs after authentication
+	ExportRateLimit gin.HandlerFunc
+	// WriteRateLimit limits each user's bug submissions, votes and comments
+	WriteRateLimit gin.HandlerFunc
+}
+
+// DestructiveAdminActions are the irreversible admin actions that must be confirmed
+// with an X-Confirm-Token before they run
+var DestructiveAdminActions = []string{
+	"DELETE /api/v1/admin/bugs/:id",
+	"DELETE /api/v1/admin/bugs/purge",
+}
+
+// V1 registers the v1 API on rg, which is mounted at /api/v1
+func V1(rg *gin.RouterGroup, deps *Dependencies) {
+	authMiddleware := deps.AuthMiddleware
+
+	// Admin routes with additional security. The group is created before the
+	// general rate limiter is added so admins skip it when making bulk changes.
+	admin := rg.Group("/admin")
+	admin.Use(authMiddleware.RequireAdmin())
+	admin.Use(middleware.RateLimitBypassMiddleware(deps.GeneralRateLimit))
+	// Add IP whitelist for admin routes in production
+	if deps.Config.Server.Environment == "production" {
+		// Configure allowed admin IPs in production
+		adminIPs := []string{} // Add your admin IPs here
+		admin.Use(deps.SecurityMiddleware.IPWhitelist(adminIPs))
+	}
+	admin.Use(deps.AdminConfirmer.AdminConfirmationMiddleware(DestructiveAdminActions))
+	{
+		adminHandler := deps.AdminHandler
+
+		// Dashboard and statistics
+		admin.GET("/dashboard", adminHandler.GetAdminDashboard)
+		admin.GET("/stats", adminHandler.GetAdminStats)
+		admin.POST("/stats/refresh", adminHandler.RefreshBugStats)
+		admin.GET("/search-analytics", adminHandler.GetSearchAnalytics)
+
+		// Bug moderation
+		admin.GET("/bugs", adminHandler.ListBugsForModeration)
+		admin.GET("/bugs/export", deps.ExportRateLimit, adminHandler.ExportBugs)
+		admin.POST("/bugs/:id/flag", adminHandler.FlagBug)
+		admin.PATCH("/bugs/:id/owner", adminHandler.TransferBugOwnership)
+		admin.DELETE("/bugs/:id", adminHandler.RemoveBug)
+		admin.POST("/bugs/:id/restore", adminHandler.RestoreBug)
+		admin.POST("/bugs/:id/rebuild-projection", adminHandler.RebuildBugProjection)
+		admin.POST("/bugs/merge", adminHandler.MergeBugs)
+		admin.GET("/bugs/deleted", adminHandler.ListDeletedBugs)
+		admin.POST("/bugs/restore-all", adminHandler.RestoreAllDeletedBugs)
+		admin.DELETE("/bugs/purge", adminHandler.PurgeDeletedBugs)
+
+		// Users
+		admin.GET("/users", adminHandler.ListUsers)
+
+		// Company verification for companies that cannot complete the email flow
+		admin.POST("/companies/:id/verify", adminHandler.AdminVerifyCompany)
+
+		// Rate limits
+		admin.POST("/rate-limits/exempt", adminHandler.ExemptUserFromRateLimits)
+
+		// IP blocks. Unblocking takes the address or range as the rest of the path, so
+		// ranges need no escaping.
+		admin.GET("/ip-blocks", adminHandler.ListIPBlocks)
+		admin.POST("/ip-blocks", adminHandler.BlockIP)
+		admin.DELETE("/ip-blocks/*ip", adminHandler.UnblockIP)
+
+		// Audit logs
+		admin.GET("/audit-logs", adminHandler.GetAuditLogs)
+		admin.GET("/audit-logs/:id", adminHandler.GetAuditLog)
+
+		// Security events and automatically raised moderation entries
+		admin.GET("/security-events", adminHandler.ListSecurityEvents)
+		admin.GET("/moderation-queue", adminHandler.ListModerationQueue)
+
+		// Outbox events that exhausted their retries
+		admin.GET("/dead-letters", adminHandler.ListDeadLetters)
+		admin.POST("/dead-letters/:id/retry", adminHandler.RetryDeadLetter)
+		admin.DELETE("/dead-letters/:id", adminHandler.DeleteDeadLetter)
+	}
+
+	v1 := rg.Group("")
+	v1.Use(authMiddleware.OptionalAuth()) // Identify users so rate limit exemptions can be applied
+	v1.Use(deps.GeneralRateLimit)         // Apply general rate limiting to all API routes
+	{
+		// Public routes
+		v1.GET("/status", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{
+				"message": "BugRelay API v1 is running",
+				"version": "1.0.0",
+			})
+		})
+
+		// Error codes clients can receive, with the endpoints that return them
+		v1.GET("/error-codes", deps.ErrorCodeHandler.ListErrorCodes)
+
+		// Authentication routes
+		auth := v1.Group("/auth")
+		{
+			authHandler := deps.AuthHandler
+			oauthHandler := deps.OAuthHandler
+
+			// Public authentication endpoints
+			auth.POST("/register", deps.GeoRateLimit, authHandler.Register)
+			auth.POST("/login", authHandler.Login)
+			auth.POST("/refresh", authHandler.RefreshToken)
+			auth.GET("/verify-email", authHandler.VerifyEmail)
+			auth.POST("/resend-verification", authHandler.ResendVerification)
+
+			// Password reset endpoints
+			auth.POST("/password-reset", authHandler.RequestPasswordReset)
+			auth.POST("/password-reset/confirm", authHandler.ResetPassword)
+
+			// OAuth endpoints
+			oauth := auth.Group("/oauth")
+			{
+				oauth.GET("/:provider", oauthHandler.InitiateOAuth)
+				oauth.GET("/:provider/redirect", oauthHandler.RedirectToOAuthProvider)
+				oauth.GET("/callback/:provider", oauthHandler.HandleOAuthCallback)
+				oauth.POST("/link/:provider", authMiddleware.RequireAuth(), oauthHandler.LinkOAuthAccount)
+			}
+
+			// Protected authentication endpoints
+			auth.POST("/logout", authMiddleware.RequireAuth(), authHandler.Logout)
+			auth.POST("/logout-all", authMiddleware.RequireAuth(), authHandler.LogoutAll)
+			auth.GET("/profile", authMiddleware.RequireAuth(), authHandler.GetProfile)
+			auth.PUT("/profile", authMiddleware.RequireAuth(), authHandler.UpdateProfile)
+		}
+
+		// Protected routes examples
+		protected := v1.Group("/protected")
+		protected.Use(authMiddleware.RequireAuth())
+		{
+			protected.GET("/test", func(c *gin.Context) {
+				userID, _ := middleware.GetCurrentUserID(c)
+				c.JSON(http.StatusOK, gin.H{
+					"message": "This is a protected endpoint",
+					"user_id": userID,
+				})
+			})
+		}
+
+		userHandler := deps.UserHandler
+
+		// Signed unsubscribe links from notification emails, usable without logging in
+		v1.GET("/unsubscribe", userHandler.Unsubscribe)
+
+		// Public user profile routes
+		v1.GET("/users/:id/stats", userHandler.GetUserStats)
+
+		// Current user routes
+		me := v1.Group("/me")
+		me.Use(authMiddleware.RequireAuth())
+		{
+			me.POST("/change-password", deps.AuthHandler.ChangePassword)
+			me.GET("/bugs", userHandler.ListMyBugs)
+			me.POST("/blocks", userHandler.BlockUser)
+			me.DELETE("/blocks/:user_id", userHandler.UnblockUser)
+			me.GET("/notification-preferences", userHandler.GetNotificationPreferences)
+			me.PATCH("/notification-preferences", userHandler.UpdateNotificationPreferences)
+		}
+
+		// Real-time notifications about bugs the user is involved with, as Server-Sent Events
+		v1.GET("/notifications/stream", authMiddleware.RequireAuth(), deps.NotificationStreamHandler.StreamNotifications)
+
+		// Bug routes
+		bugs := v1.Group("/bugs")
+		{
+			bugHandler := deps.BugHandler
+
+			// Public bug endpoints
+			bugs.GET("/", bugHandler.ListBugs)
+			bugs.GET("/:id", bugHandler.GetBug)
+			bugs.POST("/batch", bugHandler.BatchGetBugs)
+			bugs.GET("/tags/popular", bugHandler.GetPopularTags)
+			bugs.GET("/stats/geo", authMiddleware.RequireAdmin(), bugHandler.GetGeoStats)
+			bugs.GET("/:id/attachments", bugHandler.ListBugAttachments)
+			bugs.GET("/:id/comments", bugHandler.ListBugComments)
+			bugs.POST("/", deps.BugSubmissionRateLimit, deps.GeoRateLimit, authMiddleware.OptionalAuth(), deps.WriteRateLimit, bugHandler.CreateBug)
+
+			// Protected bug endpoints
+			bugs.POST("/:id/vote", authMiddleware.RequireAuth(), deps.WriteRateLimit, bugHandler.VoteBug)
+			bugs.POST("/:id/comments", authMiddleware.RequireAuth(), deps.WriteRateLimit, bugHandler.CreateComment)
+			bugs.PUT("/:id/comments/:comment_id", authMiddleware.RequireAuth(), bugHandler.UpdateComment)
+			bugs.DELETE("/:id/comments/:comment_id", authMiddleware.RequireAuth(), bugHandler.DeleteComment)
+			bugs.POST("/:id/subscribe", authMiddleware.RequireAuth(), bugHandler.SubscribeToBug)
+			bugs.DELETE("/:id/subscribe", authMiddleware.RequireAuth(), bugHandler.UnsubscribeFromBug)
+			bugs.POST("/:id/attachments", middleware.BodySizeLimit(middleware.AttachmentMaxRequestBodyBytes), authMiddleware.RequireAuth(), bugHandler.UploadBugAttachment)
+			bugs.DELETE("/:id/attachments/:attachment_id", authMiddleware.RequireAuth(), bugHandler.DeleteBugAttachment)
+			bugs.PATCH("/:id", authMiddleware.RequireAuth(), bugHandler.UpdateBug)
+			bugs.PATCH("/:id/status", authMiddleware.RequireAuth(), bugHandler.UpdateBugStatus)
+			bugs.PATCH("/:id/priority", authMiddleware.RequireAuth(), bugHandler.UpdateBugPriority)
+			bugs.POST("/:id/company-response", authMiddleware.RequireAuth(), bugHandler.AddCompanyResponse)
+		}
+
+		// Tag routes
+		v1.GET("/tags/:tag/bugs", deps.BugHandler.ListBugsByTag)
+		v1.GET("/tags/:tag/related", deps.BugHandler.GetRelatedTags)
+
+		companyHandler := deps.CompanyHandler
+
+		// Company routes
+		companies := v1.Group("/companies")
+		{
+			// Public company endpoints
+			companies.GET("/", companyHandler.ListCompanies)
+			companies.GET("/:id", companyHandler.GetCompany)
+			companies.GET("/:id/stats", companyHandler.GetCompanyStats)
+
+			// Protected company endpoints
+			companies.POST("/:id/claim", authMiddleware.RequireAuth(), companyHandler.InitiateCompanyClaim)
+			companies.POST("/:id/verify", authMiddleware.RequireAuth(), companyHandler.CompleteCompanyVerification)
+			companies.POST("/:id/resend-verification", authMiddleware.RequireAuth(), companyHandler.ResendVerification)
+			companies.POST("/:id/domain-change", authMiddleware.RequireAuth(), companyHandler.InitiateDomainChange)
+			companies.POST("/:id/domain-change/confirm", authMiddleware.RequireAuth(), companyHandler.ConfirmDomainChange)
+			companies.POST("/:id/logo", authMiddleware.RequireAuth(), companyHandler.UploadCompanyLogo)
+			companies.GET("/:id/dashboard", authMiddleware.RequireAuth(), companyHandler.GetCompanyDashboard)
+			companies.GET("/:id/dashboard/export", authMiddleware.RequireAuth(), companyHandler.ExportCompanyDashboard)
+			companies.POST("/:id/members", authMiddleware.RequireAuth(), companyHandler.AddTeamMember)
+			companies.POST("/:id/members/bulk", authMiddleware.RequireAuth(), companyHandler.BulkInviteMembers)
+			companies.POST("/:id/invite-links", authMiddleware.RequireAuth(), companyHandler.CreateInviteLink)
+			companies.DELETE("/:id/members", authMiddleware.RequireAuth(), companyHandler.RemoveTeamMember)
+			companies.PATCH("/:id/members/:user_id/role", authMiddleware.RequireAuth(), companyHandler.UpdateMemberRole)
+			companies.POST("/:id/assignment-rules", authMiddleware.RequireAuth(), companyHandler.CreateAssignmentRule)
+			companies.POST("/:id/webhooks", authMiddleware.RequireAuth(), companyHandler.CreateWebhook)
+			companies.GET("/:id/webhooks", authMiddleware.RequireAuth(), deps.CompanyMiddleware.RequireCompanyMember(), companyHandler.ListWebhooks)
+			companies.DELETE("/:id/webhooks/:webhook_id", authMiddleware.RequireAuth(), companyHandler.DeleteWebhook)
+			companies.GET("/:id/bugs", authMiddleware.RequireAuth(), deps.CompanyMiddleware.RequireCompanyMember(), companyHandler.ListCompanyBugs)
+			companies.GET("/:id/bugs/export", authMiddleware.RequireAuth(), deps.CompanyMiddleware.RequireCompanyMember(), deps.ExportRateLimit, companyHandler.ExportCompanyBugs)
+		}
+
+		// Company invitation routes
+		v1.POST("/invite/accept", companyHandler.AcceptInvitation)
+		v1.POST("/invite/link", authMiddleware.RequireAuth(), companyHandler.UseInviteLink)
+
+		// Application routes
+		applications := v1.Group("/applications")
+		{
+			applicationHandler := deps.ApplicationHandler
+
+			applications.GET("/:id/health", applicationHandler.GetApplicationHealth)
+			applications.GET("/:id/stats", applicationHandler.GetApplicationStats)
+			applications.PATCH("/:id", authMiddleware.RequireAuth(), applicationHandler.UpdateApplication)
+			applications.POST("/:id/archive", authMiddleware.RequireAuth(), applicationHandler.ArchiveApplication)
+			applications.POST("/:id/unarchive", authMiddleware.RequireAuth(), applicationHandler.UnarchiveApplication)
+		}
+
+		// Logging routes
+		logs := v1.Group("/logs")
+		{
+			logsHandler := deps.LogsHandler
+
+			// Health check for logging system
+			logs.GET("/health", logsHandler.GetLogsHealth)
+
+			// Frontend logs endpoint (with API key protection)
+			logs.POST("/frontend", func(c *gin.Context) {
+				// Simple API key check for development
+				apiKey := c.GetHeader("X-API-Key")
+				if deps.Config.Server.Environment == "production" && apiKey != deps.Config.Server.LogsAPIKey {
+					c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+					return
+				}
+				logsHandler.ReceiveFrontendLogs(c)
+			})
+		}
+	}
+}
+
+// V2 registers the v2 API on rg, which is mounted at /api/v2. v2 responses use the
+// data/meta/links envelope and lists are paginated with cursors. Routes not yet
+// ported stay on v1.
+func V2(rg *gin.RouterGroup, deps *Dependencies) {
+	authMiddleware := deps.AuthMiddleware
+
+	v2 := rg.Group("")
+	v2.Use(authMiddleware.OptionalAuth()) // Identify users so rate limit exemptions can be applied
+	v2.Use(deps.GeneralRateLimit)         // Apply general rate limiting to all API routes
+	{
+		// Bug routes
+		bugs := v2.Group("/bugs")
+		{
+			bugHandler := deps.BugHandler
+
+			bugs.GET("/", bugHandler.ListBugsV2)
+			bugs.GET("/:id", bugHandler.GetBugV2)
+			bugs.GET("/:id/attachments", bugHandler.ListBugAttachments)
+			bugs.POST("/", deps.BugSubmissionRateLimit, deps.GeoRateLimit, deps.WriteRateLimit, bugHandler.CreateBugV2)
+		}
+	}
+}
+
+// GraphQL registers the GraphQL API on rg, which is mounted at /api. Mutations run
+// behind the rate limits of the REST routes they share.
+func GraphQL(rg *gin.RouterGroup, deps *Dependencies) {
+	graphHandler := deps.GraphQLHandler
+	graphHandler.SetCreateBugMiddleware(deps.BugSubmissionRateLimit, deps.GeoRateLimit, deps.WriteRateLimit)
+	graphHandler.SetVoteBugMiddleware(deps.WriteRateLimit)
+
+	rg.POST("/graphql", deps.AuthMiddleware.OptionalAuth(), deps.GeneralRateLimit, graphHandler.ServeGraphQL)
+}
@@ -0,0 +1,197 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/auth"
+	"bugrelay-backend/internal/config"
+	"bugrelay-backend/internal/handlers"
+	"bugrelay-backend/internal/middleware"
+	"bugrelay-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// setupVersionedRouter mounts v1 and v2 on one router backed by a single in-memory
+// SQLite database. Rate limits are left out; only the bug routes are exercised.
+func setupVersionedRouter(t *testing.T) (*gin.Engine, *gorm.DB) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, autoMigrateSQLite(db,
+		&models.User{},
+		&models.Company{},
+		&models.Application{},
+		&models.BugReport{},
+		&models.Comment{},
+		&models.CommentEdit{},
+		&models.BugEditHistory{},
+		&models.FileAttachment{},
+		&models.AuditLog{},
+		&models.CustomFieldSchema{},
+		&models.BugAssignmentRule{},
+		&models.CompanyWebhook{},
+		&models.OutboxEvent{},
+		&models.UserBlock{},
+		&models.BugEvent{},
+		&models.SearchQuery{},
+		&models.Notification{},
+		&models.BugSubscription{},
+	))
+
+	passthrough := func(c *gin.Context) { c.Next() }
+	jwtService := auth.NewJWTService("test-secret", 15*time.Minute, time.Hour)
+
+	deps := &Dependencies{
+		Config:                 &config.Config{Server: config.ServerConfig{Environment: "test"}},
+		BugHandler:             handlers.NewBugHandler(db, nil),
+		ErrorCodeHandler:       handlers.NewErrorCodeHandler(),
+		AuthMiddleware:         middleware.NewAuthMiddleware(jwtService, nil),
+		GeneralRateLimit:       passthrough,
+		BugSubmissionRateLimit: passthrough,
+		GeoRateLimit:           passthrough,
+		ExportRateLimit:        passthrough,
+		WriteRateLimit:         passthrough,
+	}
+
+	router := gin.New()
+	V1(router.Group("/api/v1"), deps)
+	V2(router.Group("/api/v2"), deps)
+	return router, db
+}
+
+// autoMigrateSQLite migrates models into a SQLite database, swapping the Postgres
+// column defaults SQLite can't parse for equivalents first. The parsed schemas are
+// cached per database, so other databases keep the Postgres defaults.
+func autoMigrateSQLite(db *gorm.DB, values ...interface{}) error {
+	for _, value := range values {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(value); err != nil {
+			return err
+		}
+		for _, field := range stmt.Schema.Fields {
+			switch field.DefaultValue {
+			case "uuid_generate_v4()":
+				field.DefaultValue = "(lower(hex(randomblob(16))))"
+			case "now()":
+				field.DefaultValue = "CURRENT_TIMESTAMP"
+			}
+		}
+	}
+	return db.AutoMigrate(values...)
+}
+
+func TestVersionedRoutes_ShareData(t *testing.T) {
+	router, db := setupVersionedRouter(t)
+
+	request := func(method, path string, body interface{}) (*httptest.ResponseRecorder, map[string]interface{}) {
+		var reader *bytes.Reader
+		if body != nil {
+			encoded, err := json.Marshal(body)
+			require.NoError(t, err)
+			reader = bytes.NewReader(encoded)
+		} else {
+			reader = bytes.NewReader(nil)
+		}
+
+		req, _ := http.NewRequest(method, path, reader)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response), w.Body.String())
+		return w, response
+	}
+
+	submission := func(title string) gin.H {
+		return gin.H{
+			"title":            title,
+			"description":      "The export button does nothing when clicked",
+			"application_name": "Versioned App",
+		}
+	}
+
+	// A bug submitted through v1 keeps the v1 response format
+	w, v1Created := request("POST", "/api/v1/bugs/", submission("Export fails in v1"))
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "Bug report created successfully", v1Created["message"])
+	v1BugID := v1Created["bug"].(map[string]interface{})["id"].(string)
+
+	// A bug submitted through v2 is wrapped in the envelope
+	w, v2Created := request("POST", "/api/v2/bugs/", submission("Export fails in v2"))
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.NotContains(t, v2Created, "message")
+	v2BugID := v2Created["data"].(map[string]interface{})["id"].(string)
+	links := v2Created["links"].(map[string]interface{})
+	assert.Equal(t, "/api/v2/bugs/"+v2BugID, links["self"].(map[string]interface{})["href"])
+
+	var count int64
+	require.NoError(t, db.Model(&models.BugReport{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+
+	t.Run("each version reads bugs created through the other", func(t *testing.T) {
+		w, v1Bug := request("GET", "/api/v1/bugs/"+v2BugID, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "Export fails in v2", v1Bug["bug"].(map[string]interface{})["title"])
+
+		w, v2Bug := request("GET", "/api/v2/bugs/"+v1BugID, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "Export fails in v1", v2Bug["data"].(map[string]interface{})["title"])
+	})
+
+	t.Run("listings return the same bugs", func(t *testing.T) {
+		w, v1List := request("GET", "/api/v1/bugs/", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Len(t, v1List["bugs"], 2)
+		assert.Equal(t, float64(2), v1List["pagination"].(map[string]interface{})["total"])
+
+		w, v2List := request("GET", "/api/v2/bugs/?limit=1", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Len(t, v2List["data"], 1)
+		meta := v2List["meta"].(map[string]interface{})
+		assert.Equal(t, true, meta["has_more"])
+
+		w, v2Next := request("GET", "/api/v2/bugs/?limit=1&cursor="+meta["next_cursor"].(string), nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Len(t, v2Next["data"], 1)
+		assert.Equal(t, false, v2Next["meta"].(map[string]interface{})["has_more"])
+
+		first := v2List["data"].([]interface{})[0].(map[string]interface{})["id"]
+		second := v2Next["data"].([]interface{})[0].(map[string]interface{})["id"]
+		assert.ElementsMatch(t, []interface{}{v1BugID, v2BugID}, []interface{}{first, second})
+	})
+
+	t.Run("errors use the same format in both versions", func(t *testing.T) {
+		w1, v1Error := request("GET", "/api/v1/bugs/not-a-uuid", nil)
+		w2, v2Error := request("GET", "/api/v2/bugs/not-a-uuid", nil)
+		assert.Equal(t, http.StatusBadRequest, w1.Code)
+		assert.Equal(t, w1.Code, w2.Code)
+		assert.Equal(t, "INVALID_ID", v1Error["error"].(map[string]interface{})["code"])
+		assert.Equal(t, "INVALID_ID", v2Error["error"].(map[string]interface{})["code"])
+	})
+
+	t.Run("routes not ported to v2 stay on v1", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/v2/error-codes", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		req, _ = http.NewRequest("GET", "/api/v1/error-codes", nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
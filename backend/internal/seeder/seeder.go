@@ -6,20 +6,43 @@ import (
 
 	"bugrelay-backend/internal/logger"
 	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/utils"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
 // Seeder handles database seeding for development and testing
 type Seeder struct {
-	db *gorm.DB
+	db        *gorm.DB
+	tagConfig utils.TagConfig
 }
 
 // New creates a new seeder instance
 func New(db *gorm.DB) *Seeder {
-	return &Seeder{db: db}
+	return &Seeder{db: db, tagConfig: utils.DefaultTagConfig()}
+}
+
+// SetTagConfig sets the tag limits seeded bugs are kept within
+func (s *Seeder) SetTagConfig(cfg utils.TagConfig) {
+	s.tagConfig = cfg
+}
+
+// seedTags returns the tags that fit the tag limits, so seeded bugs could have been
+// submitted through the API
+func (s *Seeder) seedTags(tags ...string) pq.StringArray {
+	valid := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if s.tagConfig.MaxTags > 0 && len(valid) == s.tagConfig.MaxTags {
+			break
+		}
+		if normalized, reason := utils.CheckTag(tag, s.tagConfig); reason == "" {
+			valid = append(valid, normalized)
+		}
+	}
+	return pq.StringArray(valid)
 }
 
 // SeedAll runs all seeders
@@ -165,6 +188,9 @@ func (s *Seeder) SeedApplications() error {
 	}
 
 	for _, app := range applications {
+		if canonicalURL, err := utils.NormalizeURL(*app.URL); err == nil {
+			app.CanonicalURL = &canonicalURL
+		}
 		if err := s.db.Create(&app).Error; err != nil {
 			return fmt.Errorf("failed to create application %s: %w", app.Name, err)
 		}
@@ -275,64 +301,74 @@ func (s *Seeder) SeedBugs() error {
 
 	bugs := []models.BugReport{
 		{
-			ID:            uuid.New(),
-			Title:         "Login button not working on mobile",
-			Description:   "When trying to log in on mobile devices, the login button appears to be unresponsive. This affects both iOS and Android users.",
-			Status:        models.BugStatusOpen,
-			Priority:      models.BugPriorityHigh,
-			ApplicationID: applications[0].ID,
-			ReporterID:    &users[0].ID,
-			VoteCount:     15,
-			CreatedAt:     time.Now().Add(-48 * time.Hour),
-			UpdatedAt:     time.Now().Add(-24 * time.Hour),
+			ID:                uuid.New(),
+			Title:             "Login button not working on mobile",
+			Description:       "When trying to log in on mobile devices, the login button appears to be unresponsive. This affects both iOS and Android users.",
+			Status:            models.BugStatusOpen,
+			Priority:          models.BugPriorityHigh,
+			Tags:              s.seedTags("mobile", "login", "ios", "android"),
+			ApplicationID:     applications[0].ID,
+			ReporterID:        &users[0].ID,
+			VoteCount:         15,
+			WeightedVoteCount: 15,
+			CreatedAt:         time.Now().Add(-48 * time.Hour),
+			UpdatedAt:         time.Now().Add(-24 * time.Hour),
 		},
 		{
-			ID:            uuid.New(),
-			Title:         "Page loading performance issue",
-			Description:   "The dashboard page takes too long to load, especially with large datasets. Users are experiencing timeouts.",
-			Status:        models.BugStatusReviewing,
-			Priority:      models.BugPriorityMedium,
-			ApplicationID: applications[1].ID,
-			ReporterID:    &users[1].ID,
-			VoteCount:     8,
-			CreatedAt:     time.Now().Add(-72 * time.Hour),
-			UpdatedAt:     time.Now().Add(-12 * time.Hour),
+			ID:                uuid.New(),
+			Title:             "Page loading performance issue",
+			Description:       "The dashboard page takes too long to load, especially with large datasets. Users are experiencing timeouts.",
+			Status:            models.BugStatusReviewing,
+			Priority:          models.BugPriorityMedium,
+			Tags:              s.seedTags("performance", "dashboard"),
+			ApplicationID:     applications[1].ID,
+			ReporterID:        &users[1].ID,
+			VoteCount:         8,
+			WeightedVoteCount: 8,
+			CreatedAt:         time.Now().Add(-72 * time.Hour),
+			UpdatedAt:         time.Now().Add(-12 * time.Hour),
 		},
 		{
-			ID:            uuid.New(),
-			Title:         "Data export feature missing CSV format",
-			Description:   "Users can export data in JSON and XML formats, but CSV export option is missing from the dropdown.",
-			Status:        models.BugStatusOpen,
-			Priority:      models.BugPriorityLow,
-			ApplicationID: applications[2].ID,
-			ReporterID:    &users[2].ID,
-			VoteCount:     3,
-			CreatedAt:     time.Now().Add(-24 * time.Hour),
-			UpdatedAt:     time.Now().Add(-24 * time.Hour),
+			ID:                uuid.New(),
+			Title:             "Data export feature missing CSV format",
+			Description:       "Users can export data in JSON and XML formats, but CSV export option is missing from the dropdown.",
+			Status:            models.BugStatusOpen,
+			Priority:          models.BugPriorityLow,
+			Tags:              s.seedTags("export", "csv"),
+			ApplicationID:     applications[2].ID,
+			ReporterID:        &users[2].ID,
+			VoteCount:         3,
+			WeightedVoteCount: 3,
+			CreatedAt:         time.Now().Add(-24 * time.Hour),
+			UpdatedAt:         time.Now().Add(-24 * time.Hour),
 		},
 		{
-			ID:            uuid.New(),
-			Title:         "Security vulnerability in password reset",
-			Description:   "Password reset tokens don't expire and can be reused multiple times, creating a security risk.",
-			Status:        models.BugStatusOpen,
-			Priority:      models.BugPriorityCritical,
-			ApplicationID: applications[0].ID,
-			ReporterID:    &users[0].ID,
-			VoteCount:     25,
-			CreatedAt:     time.Now().Add(-6 * time.Hour),
-			UpdatedAt:     time.Now().Add(-6 * time.Hour),
+			ID:                uuid.New(),
+			Title:             "Security vulnerability in password reset",
+			Description:       "Password reset tokens don't expire and can be reused multiple times, creating a security risk.",
+			Status:            models.BugStatusOpen,
+			Priority:          models.BugPriorityCritical,
+			Tags:              s.seedTags("security", "authentication", "password reset"),
+			ApplicationID:     applications[0].ID,
+			ReporterID:        &users[0].ID,
+			VoteCount:         25,
+			WeightedVoteCount: 25,
+			CreatedAt:         time.Now().Add(-6 * time.Hour),
+			UpdatedAt:         time.Now().Add(-6 * time.Hour),
 		},
 		{
-			ID:            uuid.New(),
-			Title:         "UI text overlapping on small screens",
-			Description:   "On screens smaller than 768px, text in the navigation menu overlaps with icons.",
-			Status:        models.BugStatusFixed,
-			Priority:      models.BugPriorityMedium,
-			ApplicationID: applications[1].ID,
-			ReporterID:    &users[1].ID,
-			VoteCount:     12,
-			CreatedAt:     time.Now().Add(-120 * time.Hour),
-			UpdatedAt:     time.Now().Add(-48 * time.Hour),
+			ID:                uuid.New(),
+			Title:             "UI text overlapping on small screens",
+			Description:       "On screens smaller than 768px, text in the navigation menu overlaps with icons.",
+			Status:            models.BugStatusFixed,
+			Priority:          models.BugPriorityMedium,
+			Tags:              s.seedTags("ui", "responsive", "navigation"),
+			ApplicationID:     applications[1].ID,
+			ReporterID:        &users[1].ID,
+			VoteCount:         12,
+			WeightedVoteCount: 12,
+			CreatedAt:         time.Now().Add(-120 * time.Hour),
+			UpdatedAt:         time.Now().Add(-48 * time.Hour),
 		},
 	}
 
@@ -355,10 +391,84 @@ func (s *Seeder) SeedBugs() error {
 		}
 	}
 
-	logger.Info("Successfully seeded bugs", logger.Fields{"count": len(bugs)})
+	profileBugs := healthProfileBugs(applications, users[0].ID)
+	for _, bug := range profileBugs {
+		if err := s.db.Create(&bug).Error; err != nil {
+			return fmt.Errorf("failed to create bug %s: %w", bug.Title, err)
+		}
+	}
+
+	logger.Info("Successfully seeded bugs", logger.Fields{"count": len(bugs) + len(profileBugs)})
 	return nil
 }
 
+// healthProfile describes the extra bugs seeded for an application so that the
+// seeded applications show a range of health scores
+type healthProfile struct {
+	open     map[string]int // open bugs by priority
+	resolved int            // bugs fixed in the last 30 days
+}
+
+// healthProfiles are the extra bugs per seeded application. Together with the bugs
+// above they give roughly: BugRelay Web App F, TechCorp Mobile App B,
+// StartupIO Platform C and E-Commerce Store A.
+var healthProfiles = map[string]healthProfile{
+	"BugRelay Web App":    {open: map[string]int{models.BugPriorityCritical: 2}},
+	"TechCorp Mobile App": {open: map[string]int{models.BugPriorityHigh: 3}, resolved: 1},
+	"StartupIO Platform":  {open: map[string]int{models.BugPriorityHigh: 2, models.BugPriorityMedium: 5}},
+	"E-Commerce Store":    {open: map[string]int{models.BugPriorityLow: 2}, resolved: 5},
+}
+
+// healthProfileBugs generates the health profile bugs for the seeded applications
+func healthProfileBugs(applications []models.Application, reporterID uuid.UUID) []models.BugReport {
+	priorities := []string{models.BugPriorityCritical, models.BugPriorityHigh, models.BugPriorityMedium, models.BugPriorityLow}
+	now := time.Now()
+
+	var bugs []models.BugReport
+	for _, app := range applications {
+		profile, ok := healthProfiles[app.Name]
+		if !ok {
+			continue
+		}
+
+		for _, priority := range priorities {
+			for i := 0; i < profile.open[priority]; i++ {
+				createdAt := now.Add(-time.Duration(i+1) * 24 * time.Hour)
+				bugs = append(bugs, models.BugReport{
+					ID:            uuid.New(),
+					Title:         fmt.Sprintf("Sample %s priority issue %d", priority, i+1),
+					Description:   fmt.Sprintf("Sample open bug seeded to give %s a distinct health score.", app.Name),
+					Status:        models.BugStatusOpen,
+					Priority:      priority,
+					ApplicationID: app.ID,
+					ReporterID:    &reporterID,
+					CreatedAt:     createdAt,
+					UpdatedAt:     createdAt,
+				})
+			}
+		}
+
+		for i := 0; i < profile.resolved; i++ {
+			createdAt := now.Add(-time.Duration(i+10) * 24 * time.Hour)
+			resolvedAt := createdAt.Add(3 * 24 * time.Hour)
+			bugs = append(bugs, models.BugReport{
+				ID:            uuid.New(),
+				Title:         fmt.Sprintf("Sample resolved issue %d", i+1),
+				Description:   fmt.Sprintf("Sample fixed bug seeded to give %s a distinct health score.", app.Name),
+				Status:        models.BugStatusFixed,
+				Priority:      models.BugPriorityMedium,
+				ApplicationID: app.ID,
+				ReporterID:    &reporterID,
+				CreatedAt:     createdAt,
+				UpdatedAt:     resolvedAt,
+				ResolvedAt:    &resolvedAt,
+			})
+		}
+	}
+
+	return bugs
+}
+
 // SeedForTesting creates minimal test data
 func (s *Seeder) SeedForTesting() error {
 	logger.Info("Seeding minimal test data")
@@ -409,12 +519,15 @@ func (s *Seeder) SeedForTesting() error {
 func (s *Seeder) Clear() error {
 	logger.Info("Clearing seeded data")
 
+	if err := s.ClearBugs(); err != nil {
+		return err
+	}
+	if err := s.ClearCompanies(); err != nil {
+		return err
+	}
+
 	// Delete in reverse order of dependencies
 	tables := []interface{}{
-		&models.Comment{},
-		&models.BugReport{},
-		&models.CompanyMember{},
-		&models.Company{},
 		&models.Application{},
 		&models.User{},
 		&models.AuditLog{},
@@ -429,3 +542,108 @@ func (s *Seeder) Clear() error {
 	logger.Info("Successfully cleared seeded data")
 	return nil
 }
+
+// ClearBugs removes all bug reports along with their votes, comments, attachments and
+// events
+func (s *Seeder) ClearBugs() error {
+	logger.Info("Clearing bugs")
+
+	// Delete in reverse order of dependencies
+	tables := []interface{}{
+		&models.BugVote{},
+		&models.Comment{},
+		&models.FileAttachment{},
+		&models.BugEvent{},
+		&models.BugReport{},
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for _, table := range tables {
+			if err := tx.Unscoped().Where("1 = 1").Delete(table).Error; err != nil {
+				return fmt.Errorf("failed to clear table: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Successfully cleared bugs")
+	return nil
+}
+
+// ClearUsers anonymizes and soft deletes all users. The rows are kept so bug reports,
+// votes and comments keep referencing them.
+func (s *Seeder) ClearUsers() error {
+	logger.Info("Clearing users")
+
+	var users []models.User
+	if err := s.db.Find(&users).Error; err != nil {
+		return fmt.Errorf("failed to fetch users: %w", err)
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for _, user := range users {
+			err := tx.Model(&models.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+				"email":                    fmt.Sprintf("deleted-%s@deleted.invalid", user.ID),
+				"display_name":             "Deleted User",
+				"avatar_url":               nil,
+				"password_hash":            nil,
+				"auth_provider_id":         nil,
+				"email_verification_token": nil,
+				"password_reset_token":     nil,
+				"password_reset_expires":   nil,
+				"deleted_at":               time.Now(),
+			}).Error
+			if err != nil {
+				return fmt.Errorf("failed to anonymize user %s: %w", user.ID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Successfully cleared users", logger.Fields{"count": len(users)})
+	return nil
+}
+
+// ClearCompanies removes all companies after unassigning their bugs and applications
+func (s *Seeder) ClearCompanies() error {
+	logger.Info("Clearing companies")
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.BugReport{}).Where("assigned_company_id IS NOT NULL").
+			Updates(map[string]interface{}{"assigned_company_id": nil, "assigned_member_id": nil}).Error; err != nil {
+			return fmt.Errorf("failed to unassign bugs: %w", err)
+		}
+		if err := tx.Model(&models.Application{}).Where("company_id IS NOT NULL").
+			Update("company_id", nil).Error; err != nil {
+			return fmt.Errorf("failed to unassign applications: %w", err)
+		}
+
+		// Delete in reverse order of dependencies
+		tables := []interface{}{
+			&models.BugAssignmentRule{},
+			&models.CompanyWebhook{},
+			&models.CompanyInvitation{},
+			&models.CompanyInviteLink{},
+			&models.CompanyMember{},
+			&models.Company{},
+		}
+		for _, table := range tables {
+			if err := tx.Unscoped().Where("1 = 1").Delete(table).Error; err != nil {
+				return fmt.Errorf("failed to clear table: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Successfully cleared companies")
+	return nil
+}
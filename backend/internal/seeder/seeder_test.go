@@ -0,0 +1,353 @@
+package seeder
+
+import (
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/models"
+	"bugrelay-backend/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// seederTestSchema creates the seeded tables with their foreign keys. The models'
+// postgres defaults cannot be migrated on sqlite.
+var seederTestSchema = []string{
+	`CREATE TABLE users (
+		id TEXT PRIMARY KEY,
+		email TEXT NOT NULL UNIQUE,
+		display_name TEXT NOT NULL,
+		avatar_url TEXT,
+		password_hash TEXT,
+		auth_provider TEXT DEFAULT 'email',
+		auth_provider_id TEXT,
+		is_email_verified BOOLEAN DEFAULT false,
+		email_verification_token TEXT,
+		password_reset_token TEXT,
+		password_reset_expires DATETIME,
+		last_password_changed_at DATETIME,
+		is_admin BOOLEAN DEFAULT false,
+		created_at DATETIME,
+		last_active_at DATETIME,
+		deleted_at DATETIME,
+		cleanup_completed_at DATETIME
+	)`,
+	`CREATE TABLE companies (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		domain TEXT NOT NULL UNIQUE,
+		is_verified BOOLEAN DEFAULT false,
+		verification_token TEXT,
+		verification_email TEXT,
+		verification_token_expires_at DATETIME,
+		verified_at DATETIME,
+		admin_verified BOOLEAN DEFAULT false,
+		admin_verified_by TEXT,
+		pending_domain TEXT,
+		pending_domain_email TEXT,
+		pending_domain_verification_token TEXT,
+		pending_domain_verification_expires_at DATETIME,
+		logo_url TEXT,
+		created_at DATETIME,
+		updated_at DATETIME
+	)`,
+	`CREATE TABLE company_members (
+		id TEXT PRIMARY KEY,
+		company_id TEXT NOT NULL REFERENCES companies(id),
+		user_id TEXT NOT NULL REFERENCES users(id),
+		role TEXT DEFAULT 'member',
+		added_at DATETIME
+	)`,
+	`CREATE TABLE company_invitations (
+		id TEXT PRIMARY KEY,
+		company_id TEXT NOT NULL REFERENCES companies(id),
+		email TEXT NOT NULL,
+		role TEXT DEFAULT 'member',
+		token TEXT NOT NULL UNIQUE,
+		invited_by_id TEXT NOT NULL REFERENCES users(id),
+		expires_at DATETIME,
+		accepted_at DATETIME,
+		created_at DATETIME,
+		updated_at DATETIME
+	)`,
+	`CREATE TABLE company_invite_links (
+		id TEXT PRIMARY KEY,
+		company_id TEXT NOT NULL REFERENCES companies(id),
+		role TEXT DEFAULT 'member',
+		token TEXT NOT NULL UNIQUE,
+		created_by_id TEXT NOT NULL REFERENCES users(id),
+		bypass_domain_check BOOLEAN DEFAULT FALSE,
+		bypass_count INTEGER DEFAULT 0,
+		expires_at DATETIME,
+		created_at DATETIME,
+		updated_at DATETIME
+	)`,
+	`CREATE TABLE applications (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		url TEXT,
+		canonical_url TEXT UNIQUE,
+		company_id TEXT REFERENCES companies(id),
+		created_at DATETIME,
+		is_archived BOOLEAN DEFAULT false,
+		archived_at DATETIME,
+		search_language TEXT NOT NULL DEFAULT 'english'
+	)`,
+	`CREATE TABLE bug_assignment_rules (
+		id TEXT PRIMARY KEY,
+		company_id TEXT NOT NULL REFERENCES companies(id),
+		application_id TEXT NOT NULL REFERENCES applications(id),
+		tags TEXT,
+		assignee_user_id TEXT REFERENCES users(id),
+		priority_filter TEXT,
+		priority_order INTEGER DEFAULT 0,
+		created_at DATETIME,
+		updated_at DATETIME
+	)`,
+	`CREATE TABLE company_webhooks (
+		id TEXT PRIMARY KEY,
+		company_id TEXT NOT NULL REFERENCES companies(id),
+		url TEXT NOT NULL,
+		secret TEXT NOT NULL,
+		events TEXT,
+		created_by TEXT NOT NULL REFERENCES users(id),
+		created_at DATETIME,
+		updated_at DATETIME
+	)`,
+	`CREATE TABLE bug_reports (
+		id TEXT PRIMARY KEY,
+		title TEXT NOT NULL,
+		description TEXT NOT NULL,
+		status TEXT DEFAULT 'open',
+		priority TEXT DEFAULT 'medium',
+		tags TEXT,
+		operating_system TEXT,
+		device_type TEXT,
+		app_version TEXT,
+		browser_version TEXT,
+		environment_info TEXT,
+		custom_fields TEXT,
+		application_id TEXT NOT NULL REFERENCES applications(id),
+		reporter_id TEXT REFERENCES users(id),
+		assigned_company_id TEXT REFERENCES companies(id),
+		assigned_member_id TEXT REFERENCES users(id),
+		is_spam BOOLEAN DEFAULT false,
+		spam_score REAL DEFAULT 0,
+		country_code TEXT,
+		region TEXT,
+		duplicate_check_skipped BOOLEAN DEFAULT false,
+		vote_count INTEGER DEFAULT 0,
+		weighted_vote_count REAL NOT NULL DEFAULT 0,
+		trending_score REAL NOT NULL DEFAULT 0,
+		comment_count INTEGER DEFAULT 0,
+		event_sequence INTEGER DEFAULT 0,
+		created_at DATETIME,
+		updated_at DATETIME,
+		deleted_at DATETIME,
+		resolved_at DATETIME
+	)`,
+	`CREATE TABLE bug_votes (
+		id TEXT PRIMARY KEY,
+		bug_id TEXT NOT NULL REFERENCES bug_reports(id),
+		user_id TEXT NOT NULL REFERENCES users(id),
+		vote_weight REAL NOT NULL DEFAULT 1,
+		created_at DATETIME
+	)`,
+	`CREATE TABLE comments (
+		id TEXT PRIMARY KEY,
+		bug_id TEXT NOT NULL REFERENCES bug_reports(id),
+		user_id TEXT NOT NULL REFERENCES users(id),
+		content TEXT NOT NULL,
+		is_company_response BOOLEAN DEFAULT false,
+		created_at DATETIME,
+		updated_at DATETIME
+	)`,
+	`CREATE TABLE file_attachments (
+		id TEXT PRIMARY KEY,
+		bug_id TEXT NOT NULL REFERENCES bug_reports(id),
+		filename TEXT NOT NULL,
+		file_url TEXT NOT NULL,
+		file_size INTEGER,
+		mime_type TEXT,
+		uploaded_at DATETIME,
+		uploaded_by_id TEXT,
+		download_count INTEGER NOT NULL DEFAULT 0,
+		deleted_at DATETIME
+	)`,
+	`CREATE TABLE bug_events (
+		id TEXT PRIMARY KEY,
+		bug_id TEXT NOT NULL REFERENCES bug_reports(id),
+		sequence INTEGER NOT NULL,
+		event_type TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		actor_id TEXT REFERENCES users(id),
+		created_at DATETIME,
+		UNIQUE (bug_id, sequence)
+	)`,
+	`CREATE TABLE audit_logs (
+		id TEXT PRIMARY KEY,
+		action TEXT NOT NULL,
+		resource TEXT NOT NULL,
+		resource_id TEXT,
+		details TEXT,
+		user_id TEXT NOT NULL,
+		ip_address TEXT,
+		user_agent TEXT,
+		created_at DATETIME
+	)`,
+}
+
+// setupSeededDB creates a database with foreign keys enforced and seeds it, adding a
+// vote, an attachment and an event so clearing bugs has to cascade to them
+func setupSeededDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:?_foreign_keys=on"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	// Each connection to an in-memory database gets its own empty database
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	for _, statement := range seederTestSchema {
+		require.NoError(t, db.Exec(statement).Error)
+	}
+
+	require.NoError(t, New(db).SeedAll())
+
+	var bug models.BugReport
+	require.NoError(t, db.First(&bug).Error)
+	var user models.User
+	require.NoError(t, db.Where("is_admin = ?", false).First(&user).Error)
+	var company models.Company
+	require.NoError(t, db.First(&company).Error)
+
+	require.NoError(t, db.Create(&models.BugVote{ID: uuid.New(), BugID: bug.ID, UserID: user.ID, CreatedAt: time.Now()}).Error)
+	require.NoError(t, db.Create(&models.FileAttachment{ID: uuid.New(), BugID: bug.ID, Filename: "crash.png", FileURL: "/attachments/crash.png", UploadedAt: time.Now()}).Error)
+	_, err = models.AppendBugEvent(db, bug.ID, models.BugEventStatusChanged, &user.ID, models.BugFieldChange{From: bug.Status, To: models.BugStatusReviewing})
+	require.NoError(t, err)
+	require.NoError(t, db.Model(&bug).Updates(map[string]interface{}{"assigned_company_id": company.ID, "assigned_member_id": user.ID}).Error)
+
+	return db
+}
+
+// countRows counts a table's rows, including soft deleted ones
+func countRows(t *testing.T, db *gorm.DB, model interface{}) int64 {
+	var count int64
+	require.NoError(t, db.Unscoped().Model(model).Count(&count).Error)
+	return count
+}
+
+func TestSeeder_ClearBugs(t *testing.T) {
+	db := setupSeededDB(t)
+	users := countRows(t, db, &models.User{})
+	companies := countRows(t, db, &models.Company{})
+	applications := countRows(t, db, &models.Application{})
+
+	require.NoError(t, New(db).ClearBugs())
+
+	for _, model := range []interface{}{&models.BugReport{}, &models.BugVote{}, &models.Comment{}, &models.FileAttachment{}, &models.BugEvent{}} {
+		assert.Zero(t, countRows(t, db, model))
+	}
+	assert.Equal(t, users, countRows(t, db, &models.User{}))
+	assert.Equal(t, companies, countRows(t, db, &models.Company{}))
+	assert.Equal(t, applications, countRows(t, db, &models.Application{}))
+}
+
+func TestSeeder_ClearUsers(t *testing.T) {
+	db := setupSeededDB(t)
+	users := countRows(t, db, &models.User{})
+	bugs := countRows(t, db, &models.BugReport{})
+	comments := countRows(t, db, &models.Comment{})
+	members := countRows(t, db, &models.CompanyMember{})
+
+	require.NoError(t, New(db).ClearUsers())
+
+	// Users are hidden but their rows remain for the records referencing them
+	var active int64
+	require.NoError(t, db.Model(&models.User{}).Count(&active).Error)
+	assert.Zero(t, active)
+	assert.Equal(t, users, countRows(t, db, &models.User{}))
+
+	var anonymized []models.User
+	require.NoError(t, db.Unscoped().Find(&anonymized).Error)
+	for _, user := range anonymized {
+		assert.Equal(t, "Deleted User", user.DisplayName)
+		assert.Equal(t, "deleted-"+user.ID.String()+"@deleted.invalid", user.Email)
+		assert.Nil(t, user.PasswordHash)
+		assert.True(t, user.DeletedAt.Valid)
+	}
+
+	assert.Equal(t, bugs, countRows(t, db, &models.BugReport{}))
+	assert.Equal(t, comments, countRows(t, db, &models.Comment{}))
+	assert.Equal(t, members, countRows(t, db, &models.CompanyMember{}))
+}
+
+func TestSeeder_ClearCompanies(t *testing.T) {
+	db := setupSeededDB(t)
+	users := countRows(t, db, &models.User{})
+	bugs := countRows(t, db, &models.BugReport{})
+	applications := countRows(t, db, &models.Application{})
+
+	require.NoError(t, New(db).ClearCompanies())
+
+	assert.Zero(t, countRows(t, db, &models.Company{}))
+	assert.Zero(t, countRows(t, db, &models.CompanyMember{}))
+
+	var assigned int64
+	require.NoError(t, db.Model(&models.BugReport{}).Where("assigned_company_id IS NOT NULL OR assigned_member_id IS NOT NULL").Count(&assigned).Error)
+	assert.Zero(t, assigned)
+	var owned int64
+	require.NoError(t, db.Model(&models.Application{}).Where("company_id IS NOT NULL").Count(&owned).Error)
+	assert.Zero(t, owned)
+
+	assert.Equal(t, users, countRows(t, db, &models.User{}))
+	assert.Equal(t, bugs, countRows(t, db, &models.BugReport{}))
+	assert.Equal(t, applications, countRows(t, db, &models.Application{}))
+}
+
+func TestSeeder_Clear(t *testing.T) {
+	db := setupSeededDB(t)
+
+	require.NoError(t, New(db).Clear())
+
+	for _, model := range []interface{}{&models.BugReport{}, &models.BugVote{}, &models.Company{}, &models.Application{}, &models.User{}} {
+		assert.Zero(t, countRows(t, db, model))
+	}
+}
+
+func TestSeeder_SeedAll_HealthProfiles(t *testing.T) {
+	db := setupSeededDB(t)
+
+	countBugs := func(appName, query string, args ...interface{}) int64 {
+		var count int64
+		require.NoError(t, db.Model(&models.BugReport{}).
+			Joins("JOIN applications ON applications.id = bug_reports.application_id").
+			Where("applications.name = ?", appName).
+			Where(query, args...).
+			Count(&count).Error)
+		return count
+	}
+
+	for name, profile := range healthProfiles {
+		for priority, open := range profile.open {
+			assert.GreaterOrEqual(t, countBugs(name, "status IN ? AND priority = ?", []string{models.BugStatusOpen, models.BugStatusReviewing}, priority), int64(open), name)
+		}
+		assert.Equal(t, int64(profile.resolved), countBugs(name, "resolved_at >= ?", time.Now().AddDate(0, 0, -30)), name)
+	}
+}
+
+func TestSeeder_SeedTags(t *testing.T) {
+	s := New(nil)
+	assert.Equal(t, pq.StringArray{"security", "authentication", "password reset"}, s.seedTags("security", "authentication", "password reset"))
+
+	s.SetTagConfig(utils.TagConfig{MaxTags: 2, MinLength: 4, MaxLength: 12})
+	assert.Equal(t, pq.StringArray{"security", "mobile"}, s.seedTags("ui", "security", "password reset", "mobile", "login"))
+}
@@ -0,0 +1,206 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"bugrelay-backend/internal/config"
+	"bugrelay-backend/internal/models"
+)
+
+// S3Backend serves attachments stored in an S3 bucket through pre-signed URLs
+type S3Backend struct {
+	bucket          string
+	region          string
+	endpoint        string
+	accessKeyID     string
+	secretAccessKey string
+	acl             string
+	expiry          time.Duration
+	now             func() time.Time
+	client          *http.Client
+}
+
+// NewS3Backend creates an S3 backend from the storage configuration
+func NewS3Backend(cfg config.StorageConfig) *S3Backend {
+	return &S3Backend{
+		bucket:          cfg.S3Bucket,
+		region:          cfg.S3Region,
+		endpoint:        strings.TrimSuffix(cfg.S3Endpoint, "/"),
+		accessKeyID:     cfg.S3AccessKeyID,
+		secretAccessKey: cfg.S3SecretAccessKey,
+		acl:             cfg.S3ACL,
+		expiry:          cfg.PresignExpiry,
+		now:             time.Now,
+		client:          &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// GeneratePreviewURL returns a pre-signed GET URL for the attachment's object, so it
+// can be viewed whether or not the object is publicly readable
+func (b *S3Backend) GeneratePreviewURL(attachment models.FileAttachment) string {
+	return b.presign(http.MethodGet, b.objectKey(attachment.FileURL), nil)
+}
+
+// Put uploads a publicly readable object and returns its unsigned URL
+func (b *S3Backend) Put(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+	return b.Upload(ctx, key, bytes.NewReader(data), int64(len(data)), contentType)
+}
+
+// Upload uploads an object through a pre-signed PUT URL with the configured canned
+// ACL, public-read by default, and returns its unsigned URL. Without an ACL the
+// bucket policy decides whether the URL is viewable.
+func (b *S3Backend) Upload(ctx context.Context, key string, body io.Reader, size int64, contentType string) (string, error) {
+	var query url.Values
+	if b.acl != "" {
+		// Presigned requests carry the ACL as a signed query parameter
+		query = url.Values{"x-amz-acl": {b.acl}}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, b.presign(http.MethodPut, key, query), body)
+	if err != nil {
+		return "", err
+	}
+	// S3 rejects chunked uploads, so the length is always sent
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("s3 put %s: unexpected status %s", key, resp.Status)
+	}
+
+	scheme, host, canonicalURI := b.location(key)
+	return scheme + "://" + host + canonicalURI, nil
+}
+
+// Delete removes the attachment's object through a pre-signed DELETE URL. S3 reports
+// success for objects that do not exist.
+func (b *S3Backend) Delete(ctx context.Context, attachment models.FileAttachment) error {
+	key := b.objectKey(attachment.FileURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, b.presign(http.MethodDelete, key, nil), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("s3 delete %s: unexpected status %s", key, resp.Status)
+	}
+	return nil
+}
+
+// objectKey returns the key of the object an attachment's FileURL refers to. Uploaded
+// attachments record the object's URL, and older ones the key itself.
+func (b *S3Backend) objectKey(fileURL string) string {
+	_, host, prefix := b.location("")
+	if parsed, err := url.Parse(fileURL); err == nil && parsed.Host == host {
+		return strings.TrimPrefix(parsed.Path, prefix)
+	}
+	return strings.TrimPrefix(fileURL, "/")
+}
+
+// location returns the scheme, host and canonical URI of the object stored under key
+func (b *S3Backend) location(key string) (scheme, host, canonicalURI string) {
+	scheme, host, canonicalURI = "https", b.bucket+".s3."+b.region+".amazonaws.com", "/"+s3URIEscape(key)
+	if b.region == "us-east-1" {
+		host = b.bucket + ".s3.amazonaws.com"
+	}
+	if b.endpoint != "" {
+		// Custom endpoints (MinIO, LocalStack) use path-style addressing
+		if endpointURL, err := url.Parse(b.endpoint); err == nil && endpointURL.Host != "" {
+			scheme, host = endpointURL.Scheme, endpointURL.Host
+		}
+		canonicalURI = "/" + s3URIEscape(b.bucket) + canonicalURI
+	}
+	return scheme, host, canonicalURI
+}
+
+// presign signs a request for key using AWS Signature Version 4 query parameters,
+// so the URL can be used without credentials until it expires. Any extra query
+// parameters are signed along with them.
+func (b *S3Backend) presign(method, key string, extra url.Values) string {
+	scheme, host, canonicalURI := b.location(key)
+
+	now := b.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	dateStamp := now.Format("20060102")
+	credentialScope := dateStamp + "/" + b.region + "/s3/aws4_request"
+
+	query := url.Values{}
+	for name, values := range extra {
+		query[name] = values
+	}
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", b.accessKeyID+"/"+credentialScope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.Itoa(int(b.expiry.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+	canonicalQuery := strings.ReplaceAll(query.Encode(), "+", "%20")
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		canonicalURI,
+		canonicalQuery,
+		"host:" + host + "\n",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+
+	hashedRequest := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		credentialScope,
+		hex.EncodeToString(hashedRequest[:]),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+b.secretAccessKey), dateStamp)
+	signingKey = hmacSHA256(signingKey, b.region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	return fmt.Sprintf("%s://%s%s?%s&X-Amz-Signature=%s", scheme, host, canonicalURI, canonicalQuery, signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3URIEscape encodes an object key as S3 expects in a canonical URI: every byte
+// except unreserved characters and path separators is percent-encoded
+func s3URIEscape(key string) string {
+	var builder strings.Builder
+	for _, b := range []byte(key) {
+		if ('A' <= b && b <= 'Z') || ('a' <= b && b <= 'z') || ('0' <= b && b <= '9') ||
+			b == '-' || b == '_' || b == '.' || b == '~' || b == '/' {
+			builder.WriteByte(b)
+			continue
+		}
+		fmt.Fprintf(&builder, "%%%02X", b)
+	}
+	return builder.String()
+}
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"bugrelay-backend/internal/config"
+	"bugrelay-backend/internal/models"
+)
+
+// DefaultLocalDir is the directory uploaded attachments are written to
+const DefaultLocalDir = "uploads/bugs"
+
+// LocalServePath is the route that serves locally stored attachments
+const LocalServePath = "/attachments/serve/"
+
+// DefaultLocalPublicDir is the directory publicly readable objects, such as
+// company logos, are written to
+const DefaultLocalPublicDir = "uploads/public"
+
+// LocalPublicPath is the route that serves locally stored public objects
+const LocalPublicPath = "/public/"
+
+// Backend describes where attachments are stored and how clients can view them
+type Backend interface {
+	// GeneratePreviewURL returns a URL a client can use to display the attachment
+	GeneratePreviewURL(attachment models.FileAttachment) string
+	// Put stores a publicly readable object under key and returns its URL
+	Put(ctx context.Context, key string, data []byte, contentType string) (string, error)
+	// Upload stores an attachment's size bytes read from body under key and returns
+	// the URL to record as its FileURL
+	Upload(ctx context.Context, key string, body io.Reader, size int64, contentType string) (string, error)
+	// Delete removes the attachment's file. Files that are already gone are not an error.
+	Delete(ctx context.Context, attachment models.FileAttachment) error
+}
+
+// New creates the storage backend selected by the configuration
+func New(cfg config.StorageConfig) Backend {
+	if cfg.Backend == "s3" {
+		return NewS3Backend(cfg)
+	}
+	return NewLocalBackend(DefaultLocalDir)
+}
+
+// LocalBackend stores attachments on the local filesystem
+type LocalBackend struct {
+	dir       string
+	publicDir string
+}
+
+// NewLocalBackend creates a backend serving attachments from dir
+func NewLocalBackend(dir string) *LocalBackend {
+	return &LocalBackend{dir: dir, publicDir: DefaultLocalPublicDir}
+}
+
+// GeneratePreviewURL returns the ServeAttachment route for the attachment's file
+func (b *LocalBackend) GeneratePreviewURL(attachment models.FileAttachment) string {
+	return LocalServePath + url.PathEscape(path.Base(attachment.FileURL))
+}
+
+// FilePath resolves a served filename to its location on disk. Filenames that
+// would escape the upload directory are rejected.
+func (b *LocalBackend) FilePath(filename string) (string, error) {
+	if filename == "" || filename == "." || filename == ".." ||
+		strings.ContainsAny(filename, `/\`) {
+		return "", fmt.Errorf("invalid filename: %q", filename)
+	}
+	return filepath.Join(b.dir, filename), nil
+}
+
+// Put writes an object to the public directory and returns its LocalPublicPath URL.
+// Keys that would escape the public directory are rejected.
+func (b *LocalBackend) Put(ctx context.Context, key string, data []byte, contentType string) (string, error) {
+	if !filepath.IsLocal(filepath.FromSlash(key)) || strings.Contains(key, `\`) {
+		return "", fmt.Errorf("invalid object key: %q", key)
+	}
+
+	filePath := filepath.Join(b.publicDir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return "", err
+	}
+	return LocalPublicPath + key, nil
+}
+
+// Upload writes an attachment to the upload directory, named after the last element
+// of key, and returns its path as the FileURL
+func (b *LocalBackend) Upload(ctx context.Context, key string, body io.Reader, size int64, contentType string) (string, error) {
+	filePath, err := b.FilePath(path.Base(key))
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(b.dir, 0755); err != nil {
+		return "", err
+	}
+
+	file, err := os.Create(filePath)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(file, body); err != nil {
+		file.Close()
+		os.Remove(filePath)
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(filePath), nil
+}
+
+// Delete removes the attachment's file from the upload directory
+func (b *LocalBackend) Delete(ctx context.Context, attachment models.FileAttachment) error {
+	filePath, err := b.FilePath(path.Base(attachment.FileURL))
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"sync/atomic"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	// DefaultCommentMinChars is the default number of non-whitespace characters a
+	// comment must contain
+	DefaultCommentMinChars = 10
+
+	// MaxCommentWhitespaceRatio is the largest share of a comment that may be whitespace
+	MaxCommentWhitespaceRatio = 0.8
+)
+
+// commentMinChars is read by the comment_min validator. Binding validators are
+// shared by every request, so the minimum is process-wide.
+var commentMinChars atomic.Int64
+
+func init() {
+	commentMinChars.Store(DefaultCommentMinChars)
+}
+
+// SetCommentMinChars sets the number of non-whitespace characters a comment must contain
+func SetCommentMinChars(minChars int) {
+	commentMinChars.Store(int64(minChars))
+}
+
+// CommentMinChars returns the number of non-whitespace characters a comment must contain
+func CommentMinChars() int {
+	return int(commentMinChars.Load())
+}
+
+// CountNonWhitespace counts the characters in input that are not whitespace
+func CountNonWhitespace(input string) int {
+	count := 0
+	for _, r := range input {
+		if !unicode.IsSpace(r) {
+			count++
+		}
+	}
+	return count
+}
+
+// WhitespaceRatio returns the share of the characters in input that are whitespace.
+// Empty input has a ratio of 0.
+func WhitespaceRatio(input string) float64 {
+	total, whitespace := 0, 0
+	for _, r := range input {
+		total++
+		if unicode.IsSpace(r) {
+			whitespace++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(whitespace) / float64(total)
+}
+
+// IsMostlyWhitespace reports whether more than MaxCommentWhitespaceRatio of input is
+// whitespace
+func IsMostlyWhitespace(input string) bool {
+	return WhitespaceRatio(input) > MaxCommentWhitespaceRatio
+}
+
+// validateCommentMin implements the comment_min binding tag, which requires at least
+// CommentMinChars non-whitespace characters
+func validateCommentMin(fl validator.FieldLevel) bool {
+	return CountNonWhitespace(fl.Field().String()) >= CommentMinChars()
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountNonWhitespace(t *testing.T) {
+	assert.Equal(t, 0, CountNonWhitespace(""))
+	assert.Equal(t, 0, CountNonWhitespace(" \t\n 　"))
+	assert.Equal(t, 13, CountNonWhitespace("  Looks   good to me \n"))
+	assert.Equal(t, 16, CountNonWhitespace("приложение падает"))
+}
+
+func TestIsMostlyWhitespace(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{name: "empty", input: "", expected: false},
+		{name: "no whitespace", input: "reproduced", expected: false},
+		{name: "exactly 80 percent whitespace", input: "reproduced" + strings.Repeat(" ", 40), expected: false},
+		{name: "just over 80 percent whitespace", input: "reproduced" + strings.Repeat(" ", 41), expected: true},
+		{name: "whitespace only", input: " \t\n ", expected: true},
+		{name: "padding on both sides counts", input: strings.Repeat("\n", 21) + "reproduced" + strings.Repeat("\n", 20), expected: true},
+		{name: "multi-byte characters count once", input: "воспроизвёл" + strings.Repeat(" ", 44), expected: false},
+		{name: "Unicode whitespace", input: "reproduced" + strings.Repeat("　", 41), expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsMostlyWhitespace(tt.input))
+		})
+	}
+}
+
+func TestCommentMinValidation(t *testing.T) {
+	type request struct {
+		Content string `json:"content" binding:"comment_min"`
+	}
+	defer SetCommentMinChars(CommentMinChars())
+
+	SetCommentMinChars(DefaultCommentMinChars)
+	err := binding.Validator.ValidateStruct(request{Content: "  too   short  "})
+	assert.True(t, HasValidationTag(err, "comment_min"))
+	assert.Equal(t, "must contain at least 10 non-whitespace characters", FormatValidationErrors(err)["content"])
+	assert.NoError(t, binding.Validator.ValidateStruct(request{Content: "Looks good to me"}))
+
+	SetCommentMinChars(3)
+	assert.NoError(t, binding.Validator.ValidateStruct(request{Content: "+1 !"}))
+	assert.True(t, HasValidationTag(binding.Validator.ValidateStruct(request{Content: " ok "}), "comment_min"))
+}
@@ -0,0 +1,19 @@
+package utils
+
+import "fmt"
+
+// FormatFileSize formats a size in bytes for display, e.g. 1536 as "1.5 KB". Sizes
+// use binary units.
+func FormatFileSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit && exp < 4; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTP"[exp])
+}
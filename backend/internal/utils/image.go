@@ -7,6 +7,7 @@ import (
 	"image/jpeg"
 	"image/png"
 	"io"
+	"math"
 	"mime/multipart"
 	"path/filepath"
 	"strings"
@@ -288,4 +289,28 @@ func (p *ImageProcessor) GenerateThumbnail(img image.Image, maxSize int) image.I
 	}
 	
 	return p.resizeImage(img, newWidth, newHeight)
-}
\ No newline at end of file
+}
+// Lanczos is a Lanczos resampling kernel with a support of 3. golang.org/x/image/draw
+// provides CatmullRom but no Lanczos kernel.
+var Lanczos = &draw.Kernel{Support: 3, At: lanczos3}
+
+func lanczos3(t float64) float64 {
+	if t == 0 {
+		return 1
+	}
+	x := math.Pi * t
+	return 3 * math.Sin(x) * math.Sin(x/3) / (x * x)
+}
+
+// ResizeSquare crops the center square out of an image and scales it to size
+// by size pixels with Lanczos resampling
+func ResizeSquare(img image.Image, size int) *image.NRGBA {
+	bounds := img.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	crop := image.Rect(0, 0, side, side).Add(bounds.Min).
+		Add(image.Pt((bounds.Dx()-side)/2, (bounds.Dy()-side)/2))
+
+	dst := image.NewNRGBA(image.Rect(0, 0, size, size))
+	Lanczos.Scale(dst, dst.Bounds(), img, crop, draw.Src, nil)
+	return dst
+}
@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Default limits on the tags of a bug report
+const (
+	DefaultMaxTagsPerBug = 10
+	DefaultMinTagLength  = 2
+	DefaultMaxTagLength  = 50
+)
+
+// Reasons a tag is rejected by ValidateTags
+const (
+	TagReasonTooShort          = "too_short"
+	TagReasonTooLong           = "too_long"
+	TagReasonInvalidCharacters = "invalid_characters"
+)
+
+// TagConfig holds the limits on the tags of a bug report. Lengths are counted in
+// characters. Zero disables a limit.
+type TagConfig struct {
+	MaxTags   int
+	MinLength int
+	MaxLength int
+}
+
+// DefaultTagConfig returns the default tag limits
+func DefaultTagConfig() TagConfig {
+	return TagConfig{
+		MaxTags:   DefaultMaxTagsPerBug,
+		MinLength: DefaultMinTagLength,
+		MaxLength: DefaultMaxTagLength,
+	}
+}
+
+// InvalidTag is a tag rejected by ValidateTags and the reason it was rejected
+type InvalidTag struct {
+	Tag    string `json:"tag"`
+	Reason string `json:"reason"`
+}
+
+// InvalidTagsError lists every tag rejected by ValidateTags
+type InvalidTagsError struct {
+	Tags []InvalidTag
+}
+
+func (e *InvalidTagsError) Error() string {
+	invalid := make([]string, 0, len(e.Tags))
+	for _, tag := range e.Tags {
+		invalid = append(invalid, fmt.Sprintf("%q (%s)", tag.Tag, tag.Reason))
+	}
+	return "invalid tags: " + strings.Join(invalid, ", ")
+}
+
+// TooManyTagsError is returned by ValidateTags when a bug has more tags than allowed
+type TooManyTagsError struct {
+	Count int
+	Max   int
+}
+
+func (e *TooManyTagsError) Error() string {
+	return fmt.Sprintf("%d tags given, at most %d allowed", e.Count, e.Max)
+}
+
+// CheckTag lowercases and trims a tag and checks it against the limits in cfg. It
+// returns the normalized tag and, if the tag is invalid, one of the TagReason values.
+func CheckTag(tag string, cfg TagConfig) (string, string) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+
+	length := utf8.RuneCountInString(tag)
+	switch {
+	case cfg.MinLength > 0 && length < cfg.MinLength:
+		return tag, TagReasonTooShort
+	case cfg.MaxLength > 0 && length > cfg.MaxLength:
+		return tag, TagReasonTooLong
+	case !validTagCharacters(tag):
+		return tag, TagReasonInvalidCharacters
+	}
+	return tag, ""
+}
+
+// ValidateTags normalizes a bug's tags and checks them against the limits in cfg.
+// Blank tags are dropped. It returns a *TooManyTagsError when there are more tags
+// than allowed, or an *InvalidTagsError listing every tag that failed a check.
+func ValidateTags(tags []string, cfg TagConfig) ([]string, error) {
+	if cfg.MaxTags > 0 && len(tags) > cfg.MaxTags {
+		return nil, &TooManyTagsError{Count: len(tags), Max: cfg.MaxTags}
+	}
+
+	var sanitized []string
+	var invalid []InvalidTag
+	for _, tag := range tags {
+		if strings.TrimSpace(tag) == "" {
+			continue
+		}
+
+		normalized, reason := CheckTag(tag, cfg)
+		if reason != "" {
+			invalid = append(invalid, InvalidTag{Tag: tag, Reason: reason})
+			continue
+		}
+		sanitized = append(sanitized, normalized)
+	}
+
+	if len(invalid) > 0 {
+		return nil, &InvalidTagsError{Tags: invalid}
+	}
+	return sanitized, nil
+}
+
+// validTagCharacters reports whether a tag contains only letters, digits, spaces,
+// hyphens and underscores
+func validTagCharacters(tag string) bool {
+	for _, r := range tag {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != ' ' && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTags(t *testing.T) {
+	cfg := DefaultTagConfig()
+
+	t.Run("normalizes tags and drops blank ones", func(t *testing.T) {
+		tags, err := ValidateTags([]string{" Mobile ", "", "  ", "iOS 17", "dark_mode"}, cfg)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"mobile", "ios 17", "dark_mode"}, tags)
+	})
+
+	t.Run("limits the number of tags", func(t *testing.T) {
+		tags := make([]string, 11)
+		for i := range tags {
+			tags[i] = "tag"
+		}
+		_, err := ValidateTags(tags, cfg)
+		var tooMany *TooManyTagsError
+		require.ErrorAs(t, err, &tooMany)
+		assert.Equal(t, 11, tooMany.Count)
+		assert.Equal(t, 10, tooMany.Max)
+
+		_, err = ValidateTags(tags[:10], cfg)
+		assert.NoError(t, err)
+
+		_, err = ValidateTags(tags[:3], TagConfig{MaxTags: 2})
+		assert.ErrorAs(t, err, &tooMany)
+	})
+
+	t.Run("limits the minimum tag length", func(t *testing.T) {
+		_, err := ValidateTags([]string{"ui"}, cfg)
+		assert.NoError(t, err)
+
+		_, err = ValidateTags([]string{"x"}, cfg)
+		var invalid *InvalidTagsError
+		require.ErrorAs(t, err, &invalid)
+		assert.Equal(t, []InvalidTag{{Tag: "x", Reason: TagReasonTooShort}}, invalid.Tags)
+
+		_, err = ValidateTags([]string{"x"}, TagConfig{MinLength: 0})
+		assert.NoError(t, err)
+	})
+
+	t.Run("limits the maximum tag length in characters", func(t *testing.T) {
+		_, err := ValidateTags([]string{strings.Repeat("é", 50)}, cfg)
+		assert.NoError(t, err)
+
+		long := strings.Repeat("a", 51)
+		_, err = ValidateTags([]string{long}, cfg)
+		var invalid *InvalidTagsError
+		require.ErrorAs(t, err, &invalid)
+		assert.Equal(t, []InvalidTag{{Tag: long, Reason: TagReasonTooLong}}, invalid.Tags)
+
+		_, err = ValidateTags([]string{"performance"}, TagConfig{MaxLength: 5})
+		assert.ErrorAs(t, err, &invalid)
+	})
+
+	t.Run("reports every invalid tag", func(t *testing.T) {
+		_, err := ValidateTags([]string{"mobile", "x", "c++", "ok", strings.Repeat("a", 51)}, cfg)
+		var invalid *InvalidTagsError
+		require.ErrorAs(t, err, &invalid)
+		assert.Equal(t, []InvalidTag{
+			{Tag: "x", Reason: TagReasonTooShort},
+			{Tag: "c++", Reason: TagReasonInvalidCharacters},
+			{Tag: strings.Repeat("a", 51), Reason: TagReasonTooLong},
+		}, invalid.Tags)
+		assert.Contains(t, err.Error(), `"c++" (invalid_characters)`)
+	})
+}
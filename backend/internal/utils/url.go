@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// trackingParams are query parameters that identify where a visitor came from
+// rather than what they are looking at. Parameters starting with "utm_" are
+// also tracking parameters.
+var trackingParams = map[string]bool{
+	"fbclid":  true,
+	"gclid":   true,
+	"dclid":   true,
+	"msclkid": true,
+	"mc_cid":  true,
+	"mc_eid":  true,
+	"_ga":     true,
+	"_gl":     true,
+	"igshid":  true,
+	"yclid":   true,
+}
+
+// NormalizeURL returns the canonical form of an application URL, so that URLs
+// that point at the same application compare equal. The canonical form uses
+// https, a lowercase host without a "www." prefix or default port, no trailing
+// slash, no fragment and no tracking parameters, with the remaining query
+// parameters sorted. URLs without a scheme are treated as https.
+func NormalizeURL(rawURL string) (string, error) {
+	parsedURL, err := parseApplicationURL(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	host, err := canonicalHost(parsedURL)
+	if err != nil {
+		return "", err
+	}
+
+	normalized := "https://" + host + strings.TrimRight(parsedURL.EscapedPath(), "/")
+
+	query := parsedURL.Query()
+	for param := range query {
+		if isTrackingParam(param) {
+			query.Del(param)
+		}
+	}
+	if len(query) > 0 {
+		// Encode sorts the parameters by name
+		normalized += "?" + query.Encode()
+	}
+
+	return normalized, nil
+}
+
+// CanonicalHost returns the host of an application URL as it appears in the
+// URL's canonical form: lowercase, without a "www." prefix and without a
+// default port
+func CanonicalHost(rawURL string) (string, error) {
+	parsedURL, err := parseApplicationURL(rawURL)
+	if err != nil {
+		return "", err
+	}
+	return canonicalHost(parsedURL)
+}
+
+// parseApplicationURL parses an http or https URL, adding an https scheme when
+// the URL has none
+func parseApplicationURL(rawURL string) (*url.URL, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return nil, fmt.Errorf("empty URL")
+	}
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := strings.ToLower(parsedURL.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return nil, fmt.Errorf("unsupported URL scheme %q", parsedURL.Scheme)
+	}
+
+	return parsedURL, nil
+}
+
+// canonicalHost lowercases a URL's host, removes a "www." prefix and drops the
+// port when it is the default for http or https
+func canonicalHost(parsedURL *url.URL) (string, error) {
+	hostname := strings.TrimSuffix(strings.ToLower(parsedURL.Hostname()), ".")
+	hostname = strings.TrimPrefix(hostname, "www.")
+	if hostname == "" {
+		return "", fmt.Errorf("URL %q has no host", parsedURL.String())
+	}
+
+	// IPv6 addresses are bracketed in URLs
+	if strings.Contains(hostname, ":") {
+		hostname = "[" + hostname + "]"
+	}
+
+	port := parsedURL.Port()
+	if port == "" || port == "80" || port == "443" {
+		return hostname, nil
+	}
+	return hostname + ":" + port, nil
+}
+
+// isTrackingParam reports whether a query parameter only tracks where a visitor
+// came from
+func isTrackingParam(param string) bool {
+	param = strings.ToLower(param)
+	return strings.HasPrefix(param, "utm_") || trackingParams[param]
+}
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// UserAgentInfo is the operating system and browser found in a User-Agent header.
+// Fields are empty when they could not be recognised.
+type UserAgentInfo struct {
+	OSName         string
+	OSVersion      string
+	BrowserName    string
+	BrowserVersion string
+}
+
+var (
+	windowsPattern   = regexp.MustCompile(`Windows NT ([0-9.]+)`)
+	androidPattern   = regexp.MustCompile(`Android ([0-9.]+)`)
+	iosPattern       = regexp.MustCompile(`(?:iPhone OS|CPU OS) ([0-9_]+)`)
+	macOSPattern     = regexp.MustCompile(`Mac OS X ([0-9_.]+)`)
+	browserVersionRe = `/([0-9][0-9.]*)`
+
+	// browserPatterns are checked in order, since browsers include the tokens of the
+	// browsers they are based on, e.g. Edge's User-Agent also names Chrome and Safari
+	browserPatterns = []struct {
+		name    string
+		pattern *regexp.Regexp
+	}{
+		{"Edge", regexp.MustCompile(`(?:Edg|EdgA|EdgiOS)` + browserVersionRe)},
+		{"Opera", regexp.MustCompile(`(?:OPR|OPiOS)` + browserVersionRe)},
+		{"Samsung Internet", regexp.MustCompile(`SamsungBrowser` + browserVersionRe)},
+		{"Firefox", regexp.MustCompile(`(?:Firefox|FxiOS)` + browserVersionRe)},
+		{"Chrome", regexp.MustCompile(`(?:Chrome|CriOS)` + browserVersionRe)},
+		{"Safari", regexp.MustCompile(`Version` + browserVersionRe + `.*Safari/`)},
+		{"Internet Explorer", regexp.MustCompile(`(?:MSIE |Trident/.*rv:)([0-9.]+)`)},
+	}
+
+	// windowsVersions are the marketing names of Windows NT versions
+	windowsVersions = map[string]string{
+		"10.0": "10",
+		"6.3":  "8.1",
+		"6.2":  "8",
+		"6.1":  "7",
+	}
+)
+
+// ParseUserAgent extracts the operating system and browser from a User-Agent header
+func ParseUserAgent(userAgent string) UserAgentInfo {
+	var info UserAgentInfo
+
+	// iOS and Android are checked first since their User-Agents also mention
+	// Mac OS X and Linux
+	switch {
+	case androidPattern.MatchString(userAgent):
+		info.OSName = "Android"
+		info.OSVersion = androidPattern.FindStringSubmatch(userAgent)[1]
+	case iosPattern.MatchString(userAgent):
+		info.OSName = "iOS"
+		info.OSVersion = strings.ReplaceAll(iosPattern.FindStringSubmatch(userAgent)[1], "_", ".")
+	case windowsPattern.MatchString(userAgent):
+		info.OSName = "Windows"
+		version := windowsPattern.FindStringSubmatch(userAgent)[1]
+		if name, ok := windowsVersions[version]; ok {
+			version = name
+		}
+		info.OSVersion = version
+	case macOSPattern.MatchString(userAgent):
+		info.OSName = "macOS"
+		info.OSVersion = strings.ReplaceAll(macOSPattern.FindStringSubmatch(userAgent)[1], "_", ".")
+	case strings.Contains(userAgent, "CrOS"):
+		info.OSName = "Chrome OS"
+	case strings.Contains(userAgent, "Linux"):
+		info.OSName = "Linux"
+	}
+
+	for _, browser := range browserPatterns {
+		if match := browser.pattern.FindStringSubmatch(userAgent); match != nil {
+			info.BrowserName = browser.name
+			info.BrowserVersion = match[1]
+			break
+		}
+	}
+
+	return info
+}
+
+// PrimaryLanguage returns the first language of an Accept-Language header, e.g. en-US
+// for "en-US,en;q=0.9", or an empty string when none is given
+func PrimaryLanguage(acceptLanguage string) string {
+	first, _, _ := strings.Cut(acceptLanguage, ",")
+	language, _, _ := strings.Cut(first, ";")
+	language = strings.TrimSpace(language)
+	if language == "*" {
+		return ""
+	}
+	return language
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUserAgent(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		expected  UserAgentInfo
+	}{
+		{
+			name:      "chrome on windows",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			expected:  UserAgentInfo{OSName: "Windows", OSVersion: "10", BrowserName: "Chrome", BrowserVersion: "120.0.0.0"},
+		},
+		{
+			name:      "edge on windows",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.61",
+			expected:  UserAgentInfo{OSName: "Windows", OSVersion: "10", BrowserName: "Edge", BrowserVersion: "120.0.2210.61"},
+		},
+		{
+			name:      "safari on macos",
+			userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15",
+			expected:  UserAgentInfo{OSName: "macOS", OSVersion: "10.15.7", BrowserName: "Safari", BrowserVersion: "17.1"},
+		},
+		{
+			name:      "firefox on linux",
+			userAgent: "Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+			expected:  UserAgentInfo{OSName: "Linux", BrowserName: "Firefox", BrowserVersion: "121.0"},
+		},
+		{
+			name:      "safari on iphone",
+			userAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1.2 Mobile/15E148 Safari/604.1",
+			expected:  UserAgentInfo{OSName: "iOS", OSVersion: "17.1.2", BrowserName: "Safari", BrowserVersion: "17.1.2"},
+		},
+		{
+			name:      "chrome on ipad",
+			userAgent: "Mozilla/5.0 (iPad; CPU OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/119.0.6045.169 Mobile/15E148 Safari/604.1",
+			expected:  UserAgentInfo{OSName: "iOS", OSVersion: "16.6", BrowserName: "Chrome", BrowserVersion: "119.0.6045.169"},
+		},
+		{
+			name:      "samsung internet on android",
+			userAgent: "Mozilla/5.0 (Linux; Android 13; SM-S918B) AppleWebKit/537.36 (KHTML, like Gecko) SamsungBrowser/23.0 Chrome/115.0.0.0 Mobile Safari/537.36",
+			expected:  UserAgentInfo{OSName: "Android", OSVersion: "13", BrowserName: "Samsung Internet", BrowserVersion: "23.0"},
+		},
+		{
+			name:      "api client",
+			userAgent: "curl/8.4.0",
+			expected:  UserAgentInfo{},
+		},
+		{
+			name:      "empty",
+			userAgent: "",
+			expected:  UserAgentInfo{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParseUserAgent(tt.userAgent))
+		})
+	}
+}
+
+func TestPrimaryLanguage(t *testing.T) {
+	assert.Equal(t, "en-US", PrimaryLanguage("en-US,en;q=0.9,fr;q=0.8"))
+	assert.Equal(t, "de", PrimaryLanguage("de;q=0.9"))
+	assert.Equal(t, "", PrimaryLanguage("*"))
+	assert.Equal(t, "", PrimaryLanguage(""))
+}
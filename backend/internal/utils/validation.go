@@ -119,6 +119,15 @@ func ValidateString(input string, minLength, maxLength int) (string, bool) {
 	return sanitized, true
 }
 
+// CountWords counts the words in input, splitting on whitespace and Unicode
+// punctuation. HTML entities left by SanitizeInput are decoded first so their
+// letters are not counted as words.
+func CountWords(input string) int {
+	return len(strings.FieldsFunc(html.UnescapeString(input), func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}))
+}
+
 // ValidateTag validates bug report tags
 func ValidateTag(tag string) bool {
 	// Tags should be alphanumeric with spaces, hyphens, and underscores
@@ -127,13 +136,7 @@ func ValidateTag(tag string) bool {
 	}
 	
 	// Check if tag contains only allowed characters
-	for _, r := range tag {
-		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != ' ' && r != '-' && r != '_' {
-			return false
-		}
-	}
-	
-	return true
+	return validTagCharacters(tag)
 }
 
 // ValidatePriority validates bug priority values
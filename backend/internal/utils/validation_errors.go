@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// requestErrorKey is the key used for errors that do not belong to a single field,
+// such as malformed JSON
+const requestErrorKey = "request"
+
+func init() {
+	// Report fields by the names clients send rather than the Go struct field names
+	if validate, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		validate.RegisterTagNameFunc(requestFieldName)
+		_ = validate.RegisterValidation("comment_min", validateCommentMin)
+	}
+}
+
+// requestFieldName returns the JSON name of a field, falling back to its form name
+// for query parameters and then to the Go field name
+func requestFieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// FormatValidationErrors converts a request binding error into human-readable
+// messages keyed by field name. Errors that are not tied to a field, like a
+// malformed body, are reported under "request".
+func FormatValidationErrors(err error) map[string]string {
+	fields := make(map[string]string)
+
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		for _, fieldError := range validationErrors {
+			fields[validationFieldPath(fieldError)] = validationMessage(fieldError)
+		}
+		return fields
+	}
+
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) && typeError.Field != "" {
+		fields[typeError.Field] = fmt.Sprintf("must be a %s", jsonTypeName(typeError.Type))
+		return fields
+	}
+
+	fields[requestErrorKey] = err.Error()
+	return fields
+}
+
+// HasValidationTag reports whether a request binding error includes a failure of the
+// given validation tag
+func HasValidationTag(err error, tag string) bool {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return false
+	}
+	for _, fieldError := range validationErrors {
+		if fieldError.Tag() == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// validationFieldPath returns the field's path without the request struct name,
+// e.g. "tags[0]" rather than "CreateBugRequest.tags[0]"
+func validationFieldPath(fieldError validator.FieldError) string {
+	namespace := fieldError.Namespace()
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return fieldError.Field()
+}
+
+// validationMessage describes a failed validation rule
+func validationMessage(fieldError validator.FieldError) string {
+	param := fieldError.Param()
+
+	switch fieldError.Tag() {
+	case "required":
+		return "field is required"
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	case "uuid", "uuid4":
+		return "must be a valid UUID"
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", strings.Join(strings.Fields(param), ", "))
+	case "min":
+		return fmt.Sprintf("must be at least %s", sizeDescription(fieldError.Kind(), param))
+	case "max":
+		return fmt.Sprintf("must be at most %s", sizeDescription(fieldError.Kind(), param))
+	case "len":
+		return fmt.Sprintf("must be exactly %s", sizeDescription(fieldError.Kind(), param))
+	case "gt":
+		return fmt.Sprintf("must be greater than %s", param)
+	case "gte":
+		return fmt.Sprintf("must be greater than or equal to %s", param)
+	case "lt":
+		return fmt.Sprintf("must be less than %s", param)
+	case "lte":
+		return fmt.Sprintf("must be less than or equal to %s", param)
+	case "comment_min":
+		return fmt.Sprintf("must contain at least %d non-whitespace characters", CommentMinChars())
+	}
+	return fmt.Sprintf("failed the %s validation", fieldError.Tag())
+}
+
+// sizeDescription describes a size limit in the unit of the field's kind
+func sizeDescription(kind reflect.Kind, param string) string {
+	switch kind {
+	case reflect.String:
+		return param + " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return param + " items"
+	}
+	return param
+}
+
+// jsonTypeName returns the JSON name of the type a value failed to decode into
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return t.String()
+}
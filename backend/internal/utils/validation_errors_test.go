@@ -0,0 +1,133 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type validationTestRequest struct {
+	Title    string   `json:"title" binding:"required,min=5,max=10"`
+	Email    string   `json:"email,omitempty" binding:"omitempty,email"`
+	Priority string   `json:"priority,omitempty" binding:"omitempty,oneof=low medium high"`
+	Tags     []string `json:"tags,omitempty" binding:"omitempty,max=2,dive,min=2"`
+	Count    int      `json:"count,omitempty" binding:"omitempty,gte=1,lte=5"`
+	Website  *string  `json:"website,omitempty" binding:"omitempty,url"`
+}
+
+type validationTestQuery struct {
+	Page int `form:"page" binding:"min=1"`
+}
+
+// bindJSON binds body into a validationTestRequest the way handlers do
+func bindJSON(t *testing.T, body string) error {
+	req, err := http.NewRequest("POST", "/", strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	var dest validationTestRequest
+	return binding.JSON.Bind(req, &dest)
+}
+
+func TestFormatValidationErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected map[string]string
+	}{
+		{
+			name:     "required",
+			body:     `{}`,
+			expected: map[string]string{"title": "field is required"},
+		},
+		{
+			name:     "min length",
+			body:     `{"title": "abc"}`,
+			expected: map[string]string{"title": "must be at least 5 characters"},
+		},
+		{
+			name:     "max length",
+			body:     `{"title": "a very long title"}`,
+			expected: map[string]string{"title": "must be at most 10 characters"},
+		},
+		{
+			name:     "email",
+			body:     `{"title": "valid", "email": "not-an-email"}`,
+			expected: map[string]string{"email": "must be a valid email address"},
+		},
+		{
+			name:     "oneof",
+			body:     `{"title": "valid", "priority": "urgent"}`,
+			expected: map[string]string{"priority": "must be one of: low, medium, high"},
+		},
+		{
+			name:     "max items",
+			body:     `{"title": "valid", "tags": ["ui", "api", "db"]}`,
+			expected: map[string]string{"tags": "must be at most 2 items"},
+		},
+		{
+			name:     "nested field",
+			body:     `{"title": "valid", "tags": ["ui", "x"]}`,
+			expected: map[string]string{"tags[1]": "must be at least 2 characters"},
+		},
+		{
+			name:     "numeric bounds",
+			body:     `{"title": "valid", "count": 9}`,
+			expected: map[string]string{"count": "must be less than or equal to 5"},
+		},
+		{
+			name:     "url on a pointer field",
+			body:     `{"title": "valid", "website": "nope"}`,
+			expected: map[string]string{"website": "must be a valid URL"},
+		},
+		{
+			name: "several fields at once",
+			body: `{"email": "nope", "priority": "urgent"}`,
+			expected: map[string]string{
+				"title":    "field is required",
+				"email":    "must be a valid email address",
+				"priority": "must be one of: low, medium, high",
+			},
+		},
+		{
+			name:     "wrong JSON type",
+			body:     `{"title": "valid", "count": "three"}`,
+			expected: map[string]string{"count": "must be a number"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := bindJSON(t, tt.body)
+			require.Error(t, err)
+			assert.Equal(t, tt.expected, FormatValidationErrors(err))
+		})
+	}
+
+	t.Run("query parameters use their form names", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/?page=0", nil)
+		require.NoError(t, err)
+
+		var dest validationTestQuery
+		err = binding.Query.Bind(req, &dest)
+		require.Error(t, err)
+		assert.Equal(t, map[string]string{"page": "must be at least 1"}, FormatValidationErrors(err))
+	})
+
+	t.Run("malformed body", func(t *testing.T) {
+		err := bindJSON(t, `{"title": `)
+		require.Error(t, err)
+		formatted := FormatValidationErrors(err)
+		assert.Contains(t, formatted, "request")
+		assert.Len(t, formatted, 1)
+	})
+
+	t.Run("other errors", func(t *testing.T) {
+		assert.Equal(t, map[string]string{"request": "boom"}, FormatValidationErrors(errors.New("boom")))
+	})
+}
@@ -289,6 +289,34 @@ func TestValidateString(t *testing.T) {
 	}
 }
 
+func TestCountWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int
+	}{
+		{name: "empty", input: "", expected: 0},
+		{name: "whitespace only", input: " \t\n ", expected: 0},
+		{name: "single word", input: "broken", expected: 1},
+		{name: "multi-space sequences", input: "  app   crashes \t on\n\nstartup  ", expected: 4},
+		{name: "punctuation only", input: "... !!! ???", expected: 0},
+		{name: "punctuation separates words", input: "crash,again;and-again", expected: 4},
+		{name: "punctuation next to words", input: "It crashes! Every time.", expected: 4},
+		{name: "accented words", input: "l'écran reste bloqué après démarrage", expected: 6},
+		{name: "non-Latin words", input: "приложение падает при запуске", expected: 4},
+		{name: "Unicode punctuation", input: "crash—again…and«again»", expected: 4},
+		{name: "no-break and ideographic spaces", input: "app\u00a0crashes\u3000again", expected: 3},
+		{name: "escaped HTML entities", input: "Tom &amp; Jerry", expected: 2},
+		{name: "digits count as words", input: "fails on iOS 17", expected: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, CountWords(tt.input))
+		})
+	}
+}
+
 func TestValidateTag(t *testing.T) {
 	tests := []struct {
 		name     string
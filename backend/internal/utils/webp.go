@@ -0,0 +1,292 @@
+package utils
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"sort"
+)
+
+// codeLengthCodeOrder is the order code length code lengths are written in
+var codeLengthCodeOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// Alphabet sizes of the green, red, blue, alpha and distance prefix codes
+const (
+	webpGreenAlphabetSize    = 256 + 24
+	webpColorAlphabetSize    = 256
+	webpDistanceAlphabetSize = 40
+)
+
+// EncodeWebP writes img to w as a lossless WebP image. golang.org/x/image/webp
+// only decodes, so the VP8L bitstream is written here: a subtract green transform
+// followed by a single set of prefix codes for the whole image, without backward
+// references or a color cache.
+func EncodeWebP(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width < 1 || height < 1 || width > 1<<14 || height > 1<<14 {
+		return fmt.Errorf("invalid image size for WebP: %dx%d", width, height)
+	}
+
+	// Channels in the order their prefix codes are written: green, red, blue, alpha
+	pixels := make([][4]uint8, 0, width*height)
+	histograms := [4][]int{
+		make([]int, webpGreenAlphabetSize),
+		make([]int, webpColorAlphabetSize),
+		make([]int, webpColorAlphabetSize),
+		make([]int, webpColorAlphabetSize),
+	}
+	hasAlpha := false
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			pixel := [4]uint8{c.G, c.R - c.G, c.B - c.G, c.A}
+			for i, value := range pixel {
+				histograms[i][value]++
+			}
+			hasAlpha = hasAlpha || c.A != 0xff
+			pixels = append(pixels, pixel)
+		}
+	}
+
+	bw := &bitWriter{}
+	bw.write(0x2f, 8)
+	bw.write(uint32(width-1), 14)
+	bw.write(uint32(height-1), 14)
+	if hasAlpha {
+		bw.write(1, 1)
+	} else {
+		bw.write(0, 1)
+	}
+	bw.write(0, 3) // version
+
+	// Subtract green transform, then no more transforms
+	bw.write(1, 1)
+	bw.write(2, 2)
+	bw.write(0, 1)
+
+	// No color cache and no meta prefix codes
+	bw.write(0, 1)
+	bw.write(0, 1)
+
+	var codes [4]prefixCode
+	for i, histogram := range histograms {
+		codes[i] = newPrefixCode(histogram, 15)
+		bw.writePrefixCode(codes[i])
+	}
+	distances := make([]int, webpDistanceAlphabetSize)
+	distances[0] = 1
+	bw.writePrefixCode(newPrefixCode(distances, 15))
+
+	for _, pixel := range pixels {
+		for i, value := range pixel {
+			codes[i].write(bw, int(value))
+		}
+	}
+	data := bw.flush()
+
+	// RIFF container with a single VP8L chunk, padded to an even size
+	padding := len(data) & 1
+	header := make([]byte, 20)
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], uint32(4+8+len(data)+padding))
+	copy(header[8:], "WEBPVP8L")
+	binary.LittleEndian.PutUint32(header[16:], uint32(len(data)))
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if padding == 1 {
+		_, err := w.Write([]byte{0})
+		return err
+	}
+	return nil
+}
+
+// bitWriter packs values least significant bit first, as VP8L reads them
+type bitWriter struct {
+	buf   []byte
+	bits  uint64
+	nBits uint
+}
+
+func (w *bitWriter) write(value uint32, n uint) {
+	w.bits |= uint64(value) << w.nBits
+	w.nBits += n
+	for w.nBits >= 8 {
+		w.buf = append(w.buf, byte(w.bits))
+		w.bits >>= 8
+		w.nBits -= 8
+	}
+}
+
+// flush writes any partial byte and returns the written bytes
+func (w *bitWriter) flush() []byte {
+	if w.nBits > 0 {
+		w.buf = append(w.buf, byte(w.bits))
+		w.bits, w.nBits = 0, 0
+	}
+	return w.buf
+}
+
+// prefixCode is a canonical Huffman code. codes holds each symbol's code with its
+// bits reversed, since codes are read most significant bit first.
+type prefixCode struct {
+	lengths []uint8
+	codes   []uint16
+	// single is set when only one symbol is used, which takes no bits to write
+	single bool
+}
+
+// newPrefixCode builds a prefix code for the symbol counts in histogram whose
+// codes are at most maxLength bits long
+func newPrefixCode(histogram []int, maxLength int) prefixCode {
+	lengths := huffmanLengths(histogram, maxLength)
+
+	var lengthCounts [16]int
+	used := 0
+	for _, length := range lengths {
+		if length > 0 {
+			lengthCounts[length]++
+			used++
+		}
+	}
+	var nextCode [16]int
+	for length, code := 1, 0; length < len(nextCode); length++ {
+		code = (code + lengthCounts[length-1]) << 1
+		nextCode[length] = code
+	}
+
+	codes := make([]uint16, len(lengths))
+	for symbol, length := range lengths {
+		if length == 0 {
+			continue
+		}
+		code := nextCode[length]
+		nextCode[length]++
+		for i := uint8(0); i < length; i++ {
+			codes[symbol] = codes[symbol]<<1 | uint16(code>>i&1)
+		}
+	}
+
+	return prefixCode{lengths: lengths, codes: codes, single: used == 1}
+}
+
+func (c prefixCode) write(w *bitWriter, symbol int) {
+	if c.single {
+		return
+	}
+	w.write(uint32(c.codes[symbol]), uint(c.lengths[symbol]))
+}
+
+// writePrefixCode writes a prefix code's lengths, using the simple form for codes
+// of one or two symbols below 256
+func (w *bitWriter) writePrefixCode(code prefixCode) {
+	var symbols []int
+	for symbol, length := range code.lengths {
+		if length > 0 {
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	if len(symbols) <= 2 && symbols[len(symbols)-1] < 256 {
+		w.write(1, 1)
+		w.write(uint32(len(symbols)-1), 1)
+		if symbols[0] < 2 {
+			w.write(0, 1)
+			w.write(uint32(symbols[0]), 1)
+		} else {
+			w.write(1, 1)
+			w.write(uint32(symbols[0]), 8)
+		}
+		if len(symbols) == 2 {
+			w.write(uint32(symbols[1]), 8)
+		}
+		return
+	}
+
+	// Code lengths are written literally with a code length code, without the
+	// repeat codes 16 to 18
+	lengthHistogram := make([]int, len(codeLengthCodeOrder))
+	for _, length := range code.lengths {
+		lengthHistogram[length]++
+	}
+	lengthCode := newPrefixCode(lengthHistogram, 7)
+
+	w.write(0, 1)
+	w.write(uint32(len(codeLengthCodeOrder)-4), 4)
+	for _, symbol := range codeLengthCodeOrder {
+		w.write(uint32(lengthCode.lengths[symbol]), 3)
+	}
+	w.write(0, 1) // lengths for the whole alphabet follow
+	for _, length := range code.lengths {
+		lengthCode.write(w, int(length))
+	}
+}
+
+// huffmanLengths returns Huffman code lengths for the symbol counts in histogram.
+// Counts are flattened until no code is longer than maxLength.
+func huffmanLengths(histogram []int, maxLength int) []uint8 {
+	counts := append([]int(nil), histogram...)
+	lengths := make([]uint8, len(counts))
+	for huffmanTree(counts, lengths) > maxLength {
+		for symbol, count := range counts {
+			if count > 0 {
+				counts[symbol] = count/2 + 1
+			}
+		}
+	}
+	return lengths
+}
+
+// huffmanTree fills lengths with the depth of each used symbol in a Huffman tree
+// for counts and returns the deepest. A lone symbol gets a length of one.
+func huffmanTree(counts []int, lengths []uint8) int {
+	type node struct {
+		count, symbol, left, right int
+	}
+
+	var nodes []node
+	var active []int
+	for symbol, count := range counts {
+		lengths[symbol] = 0
+		if count > 0 {
+			nodes = append(nodes, node{count: count, symbol: symbol, left: -1, right: -1})
+			active = append(active, len(nodes)-1)
+		}
+	}
+	if len(active) == 1 {
+		lengths[nodes[0].symbol] = 1
+		return 1
+	}
+
+	for len(active) > 1 {
+		sort.SliceStable(active, func(i, j int) bool {
+			return nodes[active[i]].count < nodes[active[j]].count
+		})
+		left, right := active[0], active[1]
+		nodes = append(nodes, node{count: nodes[left].count + nodes[right].count, symbol: -1, left: left, right: right})
+		active = append(active[2:], len(nodes)-1)
+	}
+
+	maxDepth := 0
+	var walk func(i, depth int)
+	walk = func(i, depth int) {
+		if nodes[i].left < 0 {
+			lengths[nodes[i].symbol] = uint8(depth)
+			if depth > maxDepth {
+				maxDepth = depth
+			}
+			return
+		}
+		walk(nodes[i].left, depth+1)
+		walk(nodes[i].right, depth+1)
+	}
+	walk(active[0], 0)
+	return maxDepth
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/webp"
+)
+
+func TestEncodeWebP_RoundTrip(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	images := map[string]*image.NRGBA{
+		"single pixel": image.NewNRGBA(image.Rect(0, 0, 1, 1)),
+		"solid color":  image.NewNRGBA(image.Rect(0, 0, 40, 30)),
+		"gradient":     image.NewNRGBA(image.Rect(0, 0, 200, 200)),
+		"noise":        image.NewNRGBA(image.Rect(0, 0, 63, 17)),
+		"two colors":   image.NewNRGBA(image.Rect(0, 0, 9, 9)),
+	}
+	fill := func(img *image.NRGBA, at func(x, y int) color.NRGBA) {
+		for y := 0; y < img.Bounds().Dy(); y++ {
+			for x := 0; x < img.Bounds().Dx(); x++ {
+				img.SetNRGBA(x, y, at(x, y))
+			}
+		}
+	}
+	fill(images["single pixel"], func(x, y int) color.NRGBA { return color.NRGBA{200, 100, 50, 255} })
+	fill(images["solid color"], func(x, y int) color.NRGBA { return color.NRGBA{10, 20, 30, 255} })
+	fill(images["gradient"], func(x, y int) color.NRGBA {
+		return color.NRGBA{uint8(x), uint8(y), uint8(x + y), 255}
+	})
+	fill(images["noise"], func(x, y int) color.NRGBA {
+		return color.NRGBA{uint8(random.Intn(256)), uint8(random.Intn(256)), uint8(random.Intn(256)), uint8(random.Intn(256))}
+	})
+	fill(images["two colors"], func(x, y int) color.NRGBA {
+		if (x+y)%2 == 0 {
+			return color.NRGBA{255, 255, 255, 255}
+		}
+		return color.NRGBA{0, 0, 0, 0}
+	})
+
+	for name, img := range images {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, EncodeWebP(&buf, img))
+			assert.Equal(t, 0, buf.Len()%2)
+
+			decoded, err := webp.Decode(bytes.NewReader(buf.Bytes()))
+			require.NoError(t, err)
+			require.Equal(t, img.Bounds(), decoded.Bounds())
+
+			for y := 0; y < img.Bounds().Dy(); y++ {
+				for x := 0; x < img.Bounds().Dx(); x++ {
+					require.Equal(t, img.NRGBAAt(x, y), decoded.At(x, y), "pixel %d,%d", x, y)
+				}
+			}
+		})
+	}
+}
+
+func TestHuffmanLengths_LimitsCodeLength(t *testing.T) {
+	// Fibonacci counts produce the deepest possible Huffman tree
+	histogram := make([]int, 30)
+	histogram[0], histogram[1] = 1, 1
+	for i := 2; i < len(histogram); i++ {
+		histogram[i] = histogram[i-1] + histogram[i-2]
+	}
+
+	lengths := huffmanLengths(histogram, 15)
+
+	kraft := 0.0
+	for _, length := range lengths {
+		require.NotZero(t, length)
+		assert.LessOrEqual(t, length, uint8(15))
+		kraft += 1 / float64(uint(1)<<length)
+	}
+	assert.Equal(t, 1.0, kraft)
+}
+
+func TestResizeSquare(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 300, 150))
+	for y := 0; y < 150; y++ {
+		for x := 0; x < 300; x++ {
+			// Only the center square is blue
+			c := color.NRGBA{255, 0, 0, 255}
+			if x >= 75 && x < 225 {
+				c = color.NRGBA{0, 0, 255, 255}
+			}
+			src.SetNRGBA(x, y, c)
+		}
+	}
+
+	dst := ResizeSquare(src, 200)
+	assert.Equal(t, image.Rect(0, 0, 200, 200), dst.Bounds())
+	assert.Equal(t, color.NRGBA{0, 0, 255, 255}, dst.NRGBAAt(0, 0))
+	assert.Equal(t, color.NRGBA{0, 0, 255, 255}, dst.NRGBAAt(199, 199))
+}
@@ -0,0 +1,197 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// SignatureHeader carries the hex HMAC-SHA256 of the request body, keyed with
+	// the webhook's secret, as "sha256=<hex>"
+	SignatureHeader = "X-BugRelay-Signature"
+	// EventHeader carries the event type of a delivery
+	EventHeader = "X-BugRelay-Event"
+
+	// MaxAttempts is the number of times a delivery is attempted before it is dropped
+	MaxAttempts = 3
+	// DefaultRetryDelay is the wait before the first retry. Each further retry waits
+	// twice as long as the one before.
+	DefaultRetryDelay = 2 * time.Second
+
+	// DefaultWorkers is the number of deliveries made at once by default
+	DefaultWorkers = 4
+	// DefaultQueueSize is the number of deliveries buffered by default
+	DefaultQueueSize = 100
+)
+
+// Payload is the JSON body posted to a company webhook
+type Payload struct {
+	Event     string           `json:"event"`
+	Timestamp time.Time        `json:"timestamp"`
+	Bug       models.BugReport `json:"bug"`
+	Comment   *models.Comment  `json:"comment,omitempty"`
+}
+
+// delivery is a payload to post to a single webhook
+type delivery struct {
+	webhookID uuid.UUID
+	url       string
+	secret    string
+	event     string
+	body      []byte
+}
+
+// Dispatcher delivers bug events to the webhooks of the company the bug is assigned
+// to. Deliveries are made off the request path by a fixed pool of workers and are
+// retried with exponential backoff. They are held in memory, so deliveries still
+// queued when the server stops are lost.
+type Dispatcher struct {
+	db         *gorm.DB
+	client     *http.Client
+	workers    int
+	retryDelay time.Duration
+	deliveries chan delivery
+}
+
+// NewDispatcher creates a dispatcher that posts with client from workers goroutines,
+// buffering up to queueSize deliveries
+func NewDispatcher(db *gorm.DB, client *http.Client, workers, queueSize int) *Dispatcher {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	return &Dispatcher{
+		db:         db,
+		client:     client,
+		workers:    workers,
+		retryDelay: DefaultRetryDelay,
+		deliveries: make(chan delivery, queueSize),
+	}
+}
+
+// Dispatch queues the payload for each webhook of the company subscribed to its
+// event. Deliveries are dropped when the queue is full.
+func (d *Dispatcher) Dispatch(ctx context.Context, companyID uuid.UUID, payload Payload) error {
+	var webhooks []models.CompanyWebhook
+	if err := d.db.WithContext(ctx).Where("company_id = ?", companyID).Find(&webhooks).Error; err != nil {
+		return err
+	}
+
+	var body []byte
+	for _, webhook := range webhooks {
+		if !webhook.Subscribes(payload.Event) {
+			continue
+		}
+
+		if body == nil {
+			if payload.Timestamp.IsZero() {
+				payload.Timestamp = time.Now().UTC()
+			}
+			var err error
+			if body, err = json.Marshal(payload); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case d.deliveries <- delivery{webhookID: webhook.ID, url: webhook.URL, secret: webhook.Secret, event: payload.Event, body: body}:
+		default:
+			logger.FromContext(ctx).Warn("Webhook queue full, dropping delivery", logger.Fields{
+				"webhook_id": webhook.ID.String(),
+				"event":      payload.Event,
+			})
+		}
+	}
+	return nil
+}
+
+// Run makes queued deliveries until the context is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < d.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case next := <-d.deliveries:
+					d.deliver(ctx, next)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// deliver posts a delivery, retrying failed attempts after a delay that doubles
+// each time
+func (d *Dispatcher) deliver(ctx context.Context, next delivery) {
+	delay := d.retryDelay
+	for attempt := 1; ; attempt++ {
+		err := d.post(ctx, next)
+		if err == nil {
+			return
+		}
+
+		if attempt == MaxAttempts {
+			logger.Error("Webhook delivery failed", err, logger.Fields{
+				"webhook_id": next.webhookID.String(),
+				"event":      next.event,
+				"attempts":   attempt,
+			})
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// post makes a single delivery attempt
+func (d *Dispatcher) post(ctx context.Context, next delivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, next.url, bytes.NewReader(next.body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, next.event)
+	req.Header.Set(SignatureHeader, Sign(next.secret, next.body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the signature header value of a body signed with secret, which
+// receivers compare against to check a delivery came from BugRelay
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,203 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"bugrelay-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupDispatcherTestDB creates an in-memory database with the company_webhooks table
+func setupDispatcherTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	// The models' postgres defaults cannot be migrated on sqlite
+	require.NoError(t, db.Exec(`CREATE TABLE company_webhooks (
+		id TEXT PRIMARY KEY,
+		company_id TEXT NOT NULL,
+		url TEXT NOT NULL,
+		secret TEXT NOT NULL,
+		events TEXT,
+		created_by TEXT NOT NULL,
+		created_at DATETIME,
+		updated_at DATETIME
+	)`).Error)
+	return db
+}
+
+// createWebhook registers a webhook for the company subscribed to events
+func createWebhook(t *testing.T, db *gorm.DB, companyID uuid.UUID, url string, events ...string) *models.CompanyWebhook {
+	webhook := &models.CompanyWebhook{
+		CompanyID: companyID,
+		URL:       url,
+		Secret:    "test-secret-0123456789",
+		Events:    pq.StringArray(events),
+		CreatedBy: uuid.New(),
+	}
+	require.NoError(t, db.Create(webhook).Error)
+	return webhook
+}
+
+// receivedRequest is a request made to a test webhook receiver
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// newReceiver starts a webhook receiver that responds with the next status of
+// statuses, then 200, and returns the requests it receives
+func newReceiver(t *testing.T, statuses ...int) (*httptest.Server, <-chan receivedRequest) {
+	requests := make(chan receivedRequest, 10)
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- receivedRequest{header: r.Header, body: body}
+		if call := int(atomic.AddInt32(&calls, 1)); call <= len(statuses) {
+			w.WriteHeader(statuses[call-1])
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+// startDispatcher runs a dispatcher with short retry delays until the test ends
+func startDispatcher(t *testing.T, db *gorm.DB) *Dispatcher {
+	dispatcher := NewDispatcher(db, http.DefaultClient, 2, DefaultQueueSize)
+	dispatcher.retryDelay = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		dispatcher.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return dispatcher
+}
+
+// receive returns the next request made to a receiver, or fails if none arrives
+func receive(t *testing.T, requests <-chan receivedRequest) receivedRequest {
+	t.Helper()
+	select {
+	case req := <-requests:
+		return req
+	case <-time.After(2 * time.Second):
+		t.Fatal("no webhook request received")
+		return receivedRequest{}
+	}
+}
+
+// assertNoRequest fails if a receiver gets a request within a short wait
+func assertNoRequest(t *testing.T, requests <-chan receivedRequest) {
+	t.Helper()
+	select {
+	case req := <-requests:
+		t.Fatalf("unexpected webhook request %s", req.body)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestDispatcher_Dispatch(t *testing.T) {
+	db := setupDispatcherTestDB(t)
+	dispatcher := startDispatcher(t, db)
+
+	companyID := uuid.New()
+	subscribed, subscribedRequests := newReceiver(t)
+	other, otherRequests := newReceiver(t)
+	webhook := createWebhook(t, db, companyID, subscribed.URL, models.WebhookEventBugStatusChanged, models.WebhookEventCommentCreated)
+	createWebhook(t, db, companyID, other.URL, models.WebhookEventBugCreated)
+	// Another company's webhook
+	createWebhook(t, db, uuid.New(), other.URL, models.WebhookEventBugStatusChanged)
+
+	bug := models.BugReport{ID: uuid.New(), Title: "Crash on save", Status: models.BugStatusFixed, AssignedCompanyID: &companyID}
+	require.NoError(t, dispatcher.Dispatch(context.Background(), companyID, Payload{Event: models.WebhookEventBugStatusChanged, Bug: bug}))
+
+	req := receive(t, subscribedRequests)
+	assert.Equal(t, "application/json", req.header.Get("Content-Type"))
+	assert.Equal(t, models.WebhookEventBugStatusChanged, req.header.Get(EventHeader))
+	assert.Equal(t, Sign(webhook.Secret, req.body), req.header.Get(SignatureHeader))
+
+	var payload Payload
+	require.NoError(t, json.Unmarshal(req.body, &payload))
+	assert.Equal(t, models.WebhookEventBugStatusChanged, payload.Event)
+	assert.False(t, payload.Timestamp.IsZero())
+	assert.Equal(t, bug.ID, payload.Bug.ID)
+	assert.Equal(t, models.BugStatusFixed, payload.Bug.Status)
+	assert.Nil(t, payload.Comment)
+
+	// Webhooks not subscribed to the event, or of other companies, are not called
+	assertNoRequest(t, otherRequests)
+}
+
+func TestDispatcher_Retries(t *testing.T) {
+	db := setupDispatcherTestDB(t)
+	dispatcher := startDispatcher(t, db)
+	companyID := uuid.New()
+
+	t.Run("until a delivery succeeds", func(t *testing.T) {
+		receiver, requests := newReceiver(t, http.StatusInternalServerError, http.StatusBadGateway)
+		createWebhook(t, db, companyID, receiver.URL, models.WebhookEventBugCreated)
+		defer db.Where("company_id = ?", companyID).Delete(&models.CompanyWebhook{})
+
+		require.NoError(t, dispatcher.Dispatch(context.Background(), companyID, Payload{Event: models.WebhookEventBugCreated}))
+
+		// The same body is sent on every attempt
+		first := receive(t, requests)
+		second := receive(t, requests)
+		third := receive(t, requests)
+		assert.Equal(t, first.body, second.body)
+		assert.Equal(t, first.body, third.body)
+		assertNoRequest(t, requests)
+	})
+
+	t.Run("for at most 3 attempts", func(t *testing.T) {
+		receiver, requests := newReceiver(t, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError)
+		createWebhook(t, db, companyID, receiver.URL, models.WebhookEventBugCreated)
+
+		require.NoError(t, dispatcher.Dispatch(context.Background(), companyID, Payload{Event: models.WebhookEventBugCreated}))
+
+		for i := 0; i < MaxAttempts; i++ {
+			receive(t, requests)
+		}
+		assertNoRequest(t, requests)
+	})
+}
+
+func TestDispatcher_QueueFull(t *testing.T) {
+	db := setupDispatcherTestDB(t)
+	companyID := uuid.New()
+	createWebhook(t, db, companyID, "http://localhost/hook", models.WebhookEventBugCreated)
+
+	// Without workers running, deliveries past the queue size are dropped
+	dispatcher := NewDispatcher(db, http.DefaultClient, 1, 2)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, dispatcher.Dispatch(context.Background(), companyID, Payload{Event: models.WebhookEventBugCreated}))
+	}
+	assert.Len(t, dispatcher.deliveries, 2)
+}
+
+func TestSign(t *testing.T) {
+	// echo -n '{"event":"bug.created"}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "sha256=58ff1c2cddf87173c8286fe0cabf2d19ea7ece0f8535a76b2429b4b38a9271c9", Sign("secret", []byte(`{"event":"bug.created"}`)))
+	assert.NotEqual(t, Sign("secret", []byte("a")), Sign("other", []byte("a")))
+}
@@ -0,0 +1,90 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrDisallowedAddress is returned for webhook URLs that point at a loopback, private
+// or link-local address, which company webhooks may not reach
+var ErrDisallowedAddress = errors.New("webhook address is not publicly routable")
+
+// lookupIPAddr resolves webhook hosts, replaced in tests
+var lookupIPAddr = net.DefaultResolver.LookupIPAddr
+
+// ValidateURL checks that rawURL is an http or https URL whose host is not a
+// loopback, private or link-local address. Hosts that resolve to such an address are
+// rejected too. Hosts that do not resolve are accepted, since the client from
+// NewHTTPClient checks the address it connects to on every delivery.
+func ValidateURL(ctx context.Context, rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("unsupported webhook URL scheme %q", parsed.Scheme)
+	}
+
+	host := parsed.Hostname()
+	if host == "" {
+		return fmt.Errorf("webhook URL has no host")
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		if !IsAllowedIP(ip) {
+			return ErrDisallowedAddress
+		}
+		return nil
+	}
+
+	addrs, err := lookupIPAddr(ctx, host)
+	if err != nil {
+		return nil
+	}
+	for _, addr := range addrs {
+		if !IsAllowedIP(addr.IP) {
+			return ErrDisallowedAddress
+		}
+	}
+	return nil
+}
+
+// IsAllowedIP reports whether webhooks may be delivered to ip. Loopback, private
+// (including unique local IPv6), link-local, unspecified and multicast addresses
+// are not allowed, so a webhook cannot reach the server's own network.
+func IsAllowedIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsMulticast())
+}
+
+// NewHTTPClient returns a client for webhook deliveries that refuses to connect to
+// addresses IsAllowedIP rejects. The check is made on the address actually dialled,
+// after DNS resolution, so a host that is re-pointed at an internal address after
+// registration is still refused.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 30 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !IsAllowedIP(ip) {
+				return ErrDisallowedAddress
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
@@ -0,0 +1,71 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateURL(t *testing.T) {
+	// Resolve hosts without the network
+	lookupIPAddr = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		switch host {
+		case "hooks.example.com":
+			return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}}, nil
+		case "internal.example.com":
+			return []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}, {IP: net.ParseIP("192.168.1.10")}}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+	t.Cleanup(func() { lookupIPAddr = net.DefaultResolver.LookupIPAddr })
+
+	tests := []struct {
+		url     string
+		allowed bool
+	}{
+		{"https://hooks.example.com/bugrelay", true},
+		{"http://93.184.216.34:8080/bugrelay", true},
+		{"https://unresolved.example.com/bugrelay", true},
+		{"ftp://hooks.example.com/bugrelay", false},
+		{"https:///bugrelay", false},
+		{"http://127.0.0.1/bugrelay", false},
+		{"http://[::1]/bugrelay", false},
+		{"http://10.1.2.3/bugrelay", false},
+		{"http://172.16.0.1/bugrelay", false},
+		{"http://192.168.0.1/bugrelay", false},
+		{"http://169.254.169.254/latest/meta-data", false},
+		{"http://[fe80::1]/bugrelay", false},
+		{"http://[fd00::1]/bugrelay", false},
+		{"http://[::ffff:127.0.0.1]/bugrelay", false},
+		{"http://0.0.0.0/bugrelay", false},
+		{"https://internal.example.com/bugrelay", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := ValidateURL(context.Background(), tt.url)
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestNewHTTPClient_RefusesInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(server.Close)
+
+	// The test server listens on loopback, as an internal service would
+	_, err := NewHTTPClient(5 * time.Second).Get(server.URL)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrDisallowedAddress)
+}
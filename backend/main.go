@@ -1,17 +1,36 @@
 package main
 
 import (
+	"context"
+	"net"
+	"net/http"
 	"os"
+	"time"
 
+	"bugrelay-backend/internal/cache"
 	"bugrelay-backend/internal/config"
 	"bugrelay-backend/internal/database"
+	"bugrelay-backend/internal/jobs"
 	"bugrelay-backend/internal/logger"
+	"bugrelay-backend/internal/models"
 	"bugrelay-backend/internal/redis"
 	"bugrelay-backend/internal/router"
+	"bugrelay-backend/internal/webhooks"
 
+	"github.com/getsentry/sentry-go"
 	"github.com/joho/godotenv"
 )
 
+//go:generate go tool swag init --generalInfo main.go --output internal/apidocs --outputTypes go,json,yaml --parseInternal --parseDependency
+
+// @title                      BugRelay API
+// @version                    1.0
+// @description                Bug reporting and management platform. Every error code is listed by GET /api/v1/error-codes.
+// @BasePath                   /api/v1
+// @securityDefinitions.apikey BearerAuth
+// @in                         header
+// @name                       Authorization
+// @description                Access token, sent as "Bearer <token>"
 func main() {
 	// Load environment variables
 	if err := godotenv.Load(); err != nil {
@@ -43,6 +62,18 @@ func main() {
 		"environment": cfg.Server.Environment,
 	})
 
+	// Initialize error reporting. Without a DSN events are dropped.
+	if err := sentry.Init(sentry.ClientOptions{
+		Dsn:         cfg.Sentry.DSN,
+		Environment: cfg.Server.Environment,
+	}); err != nil {
+		logger.Error("Failed to initialize Sentry", err)
+	}
+	defer sentry.Flush(2 * time.Second)
+
+	// Root context carrying the logger, which requests and background jobs inherit
+	ctx := logger.NewContext(context.Background(), logger.Default())
+
 	// Initialize database
 	db, err := database.Initialize(cfg.Database)
 	if err != nil {
@@ -57,8 +88,39 @@ func main() {
 	}
 	logger.Info("Redis initialized successfully")
 
+	// Apply bug events to the bug report projection in the background
+	bugProjector := jobs.NewBugProjector(db)
+	go bugProjector.Run(ctx)
+
+	// Start background jobs
+	scheduler := jobs.NewScheduler()
+	scheduler.Register(jobs.NewRefreshBugStatsJob(db))
+	scheduler.Register(jobs.NewRefreshTrendingScoresJob(db, cfg.Trending))
+	scheduler.Register(jobs.NewCleanupExpiredVerificationsJob(db))
+	scheduler.Register(jobs.NewUserCleanupJob(db))
+	scheduler.Register(jobs.NewPriorityEscalationJob(db, cache.NewCacheService(redisClient), bugProjector, cfg.Escalation))
+
+	// Webhook deliveries share one client so slow receivers cannot hold the outbox job
+	webhookClient := &http.Client{Timeout: time.Duration(cfg.Webhooks.DeliveryTimeoutSeconds) * time.Second}
+	outboxProcessor := jobs.NewOutboxProcessor(db)
+	outboxProcessor.Handle(models.OutboxEventBugCreated, jobs.NewWebhookHandler(webhookClient, cfg.Outbox.WebhookURL))
+	outboxProcessor.Handle(models.OutboxEventEmail, jobs.NewEmailHandler(cfg.SMTP))
+	outboxProcessor.OnDeadLetter(jobs.NewDeadLetterNotifier(cfg.SMTP, cfg.Outbox.AdminEmail))
+	scheduler.Register(jobs.NewOutboxJob(outboxProcessor, cfg.Outbox.PollInterval))
+	scheduler.Start(ctx)
+
+	// Low-priority work, such as warming caches, runs one task at a time off the request path
+	backgroundQueue := jobs.NewQueue("low_priority", jobs.DefaultQueueSize)
+	go backgroundQueue.Run(ctx)
+
+	// Bug events are posted to company webhooks by a pool of workers
+	// Their URLs are set by company admins, so their client refuses internal addresses
+	companyWebhookClient := webhooks.NewHTTPClient(time.Duration(cfg.Webhooks.DeliveryTimeoutSeconds) * time.Second)
+	webhookDispatcher := webhooks.NewDispatcher(db, companyWebhookClient, cfg.Webhooks.Workers, webhooks.DefaultQueueSize)
+	go webhookDispatcher.Run(ctx)
+
 	// Initialize router
-	r := router.Setup(db, redisClient, cfg)
+	r := router.Setup(db, redisClient, cfg, bugProjector, backgroundQueue, webhookDispatcher)
 
 	// Start server
 	port := os.Getenv("PORT")
@@ -71,7 +133,12 @@ func main() {
 		"environment": cfg.Server.Environment,
 	})
 
-	if err := r.Run(":" + port); err != nil {
+	server := &http.Server{
+		Addr:        ":" + port,
+		Handler:     r,
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	if err := server.ListenAndServe(); err != nil {
 		logger.Fatal("Failed to start server", err)
 	}
 }
\ No newline at end of file
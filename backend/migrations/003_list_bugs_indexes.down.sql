@@ -0,0 +1,6 @@
+-- Drop ListBugs query pattern indexes
+
+DROP INDEX CONCURRENTLY IF EXISTS idx_bug_reports_assigned_company_status;
+DROP INDEX CONCURRENTLY IF EXISTS idx_bug_reports_reporter;
+DROP INDEX CONCURRENTLY IF EXISTS idx_bug_votes_user;
+DROP INDEX CONCURRENTLY IF EXISTS idx_company_members_user_company;
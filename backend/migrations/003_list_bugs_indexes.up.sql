@@ -0,0 +1,14 @@
+-- Indexes for common ListBugs query patterns
+-- status/priority/application composites are created in 002_performance_indexes
+
+-- Company assignment filter without the partial predicate so status-only lookups can use it
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_bug_reports_assigned_company_status ON bug_reports(assigned_company_id, status);
+
+-- Reporter lookups for user profiles and activity
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_bug_reports_reporter ON bug_reports(reporter_id);
+
+-- Vote lookups by user
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_bug_votes_user ON bug_votes(user_id);
+
+-- Membership checks starting from the user
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_company_members_user_company ON company_members(user_id, company_id);
@@ -0,0 +1,3 @@
+-- Drop bug statistics materialized view
+DROP INDEX IF EXISTS idx_bug_stats_by_company_company_status;
+DROP MATERIALIZED VIEW IF EXISTS bug_stats_by_company;
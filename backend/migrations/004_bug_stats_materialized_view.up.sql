@@ -0,0 +1,13 @@
+-- Materialized view for company dashboard bug statistics
+CREATE MATERIALIZED VIEW IF NOT EXISTS bug_stats_by_company AS
+SELECT
+    assigned_company_id,
+    status,
+    COUNT(*) AS count,
+    AVG(EXTRACT(EPOCH FROM resolved_at - created_at) / 3600) AS avg_resolution_hours
+FROM bug_reports
+WHERE deleted_at IS NULL
+GROUP BY assigned_company_id, status;
+
+-- Unique index required for REFRESH MATERIALIZED VIEW CONCURRENTLY
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bug_stats_by_company_company_status ON bug_stats_by_company(assigned_company_id, status);
@@ -0,0 +1,4 @@
+-- Drop custom fields support
+DROP INDEX IF EXISTS idx_bug_reports_custom_fields;
+DROP TABLE IF EXISTS custom_field_schemas;
+ALTER TABLE bug_reports DROP COLUMN IF EXISTS custom_fields;
@@ -0,0 +1,14 @@
+-- Application-specific custom fields on bug reports
+ALTER TABLE bug_reports ADD COLUMN IF NOT EXISTS custom_fields JSONB;
+
+-- Custom field schema definitions per application
+CREATE TABLE IF NOT EXISTS custom_field_schemas (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    application_id UUID UNIQUE NOT NULL REFERENCES applications(id),
+    fields JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+-- Index for custom field filtering
+CREATE INDEX IF NOT EXISTS idx_bug_reports_custom_fields ON bug_reports USING gin(custom_fields);
@@ -0,0 +1,2 @@
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS after_state;
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS before_state;
@@ -0,0 +1,3 @@
+-- Capture resource snapshots before and after audited actions
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS before_state JSONB;
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS after_state JSONB;
@@ -0,0 +1,2 @@
+ALTER TABLE applications DROP COLUMN IF EXISTS archived_at;
+ALTER TABLE applications DROP COLUMN IF EXISTS is_archived;
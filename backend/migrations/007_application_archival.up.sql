@@ -0,0 +1,3 @@
+-- Allow companies to archive retired applications
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS is_archived BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP;
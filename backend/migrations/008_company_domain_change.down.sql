@@ -0,0 +1,2 @@
+ALTER TABLE companies DROP COLUMN IF EXISTS pending_domain_verification_token;
+ALTER TABLE companies DROP COLUMN IF EXISTS pending_domain;
@@ -0,0 +1,3 @@
+-- Track a pending company domain change until it is confirmed
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS pending_domain VARCHAR(255);
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS pending_domain_verification_token VARCHAR(255);
@@ -0,0 +1,2 @@
+ALTER TABLE bug_reports DROP COLUMN IF EXISTS assigned_member_id;
+DROP TABLE IF EXISTS bug_assignment_rules;
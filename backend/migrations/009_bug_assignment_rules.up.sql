@@ -0,0 +1,18 @@
+-- Company-configured rules for assigning new bugs to team members
+CREATE TABLE IF NOT EXISTS bug_assignment_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    application_id UUID NOT NULL REFERENCES applications(id) ON DELETE CASCADE,
+    tags TEXT[],
+    assignee_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    priority_filter VARCHAR(20),
+    priority_order INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_bug_assignment_rules_company ON bug_assignment_rules(company_id);
+CREATE INDEX IF NOT EXISTS idx_bug_assignment_rules_application ON bug_assignment_rules(application_id, priority_order);
+
+-- Team member a bug has been assigned to by an assignment rule
+ALTER TABLE bug_reports ADD COLUMN IF NOT EXISTS assigned_member_id UUID REFERENCES users(id) ON DELETE SET NULL;
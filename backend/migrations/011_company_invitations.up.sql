@@ -0,0 +1,15 @@
+-- Pending invitations for people to join a company, by email
+CREATE TABLE IF NOT EXISTS company_invitations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(20) DEFAULT 'member',
+    token VARCHAR(255) NOT NULL UNIQUE,
+    invited_by_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_company_invitations_company_email ON company_invitations(company_id, email);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_bug_reports_is_spam;
+ALTER TABLE bug_reports DROP COLUMN IF EXISTS spam_score;
+ALTER TABLE bug_reports DROP COLUMN IF EXISTS is_spam;
@@ -0,0 +1,5 @@
+-- Spam detection for anonymous bug submissions
+ALTER TABLE bug_reports ADD COLUMN IF NOT EXISTS is_spam BOOLEAN DEFAULT FALSE;
+ALTER TABLE bug_reports ADD COLUMN IF NOT EXISTS spam_score REAL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_bug_reports_is_spam ON bug_reports(is_spam);
@@ -0,0 +1,10 @@
+-- Per-user email notification opt-outs
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    bug_status_change BOOLEAN DEFAULT TRUE,
+    company_response BOOLEAN DEFAULT TRUE,
+    mention BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
@@ -0,0 +1,5 @@
+ALTER TABLE notification_preferences DROP COLUMN IF EXISTS marketing;
+ALTER TABLE notification_preferences DROP COLUMN IF EXISTS digest_frequency;
+ALTER TABLE notification_preferences DROP COLUMN IF EXISTS vote_milestone;
+ALTER TABLE notification_preferences DROP COLUMN IF EXISTS comment_reply;
+ALTER TABLE notification_preferences DROP COLUMN IF EXISTS comment_on_my_bug;
@@ -0,0 +1,6 @@
+-- More notification types and digest settings for notification preferences
+ALTER TABLE notification_preferences ADD COLUMN IF NOT EXISTS comment_on_my_bug BOOLEAN DEFAULT TRUE;
+ALTER TABLE notification_preferences ADD COLUMN IF NOT EXISTS comment_reply BOOLEAN DEFAULT TRUE;
+ALTER TABLE notification_preferences ADD COLUMN IF NOT EXISTS vote_milestone BOOLEAN DEFAULT TRUE;
+ALTER TABLE notification_preferences ADD COLUMN IF NOT EXISTS digest_frequency VARCHAR(20) DEFAULT 'never';
+ALTER TABLE notification_preferences ADD COLUMN IF NOT EXISTS marketing BOOLEAN DEFAULT FALSE;
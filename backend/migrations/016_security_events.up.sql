@@ -0,0 +1,26 @@
+-- Log of suspicious authentication activity. Rows are never deleted.
+CREATE TABLE IF NOT EXISTS security_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    event_type VARCHAR(30) NOT NULL,
+    ip_address VARCHAR(45),
+    user_agent VARCHAR(500),
+    details JSONB,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_security_events_user_id ON security_events(user_id);
+CREATE INDEX IF NOT EXISTS idx_security_events_event_type ON security_events(event_type);
+CREATE INDEX IF NOT EXISTS idx_security_events_ip_created ON security_events(ip_address, created_at);
+
+-- Items raised automatically for administrators to review
+CREATE TABLE IF NOT EXISTS moderation_queue (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    entry_type VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    details JSONB,
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_moderation_queue_entry_type ON moderation_queue(entry_type);
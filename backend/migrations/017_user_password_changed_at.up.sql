@@ -0,0 +1,2 @@
+-- Track when a user last changed their password
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_password_changed_at TIMESTAMP;
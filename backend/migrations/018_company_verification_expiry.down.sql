@@ -0,0 +1 @@
+ALTER TABLE companies DROP COLUMN IF EXISTS verification_token_expires_at;
@@ -0,0 +1,5 @@
+-- Expire company verification tokens. Tokens issued before this migration get a
+-- fresh 24 hours.
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS verification_token_expires_at TIMESTAMP;
+UPDATE companies SET verification_token_expires_at = NOW() + INTERVAL '24 hours'
+WHERE verification_token IS NOT NULL AND verification_token_expires_at IS NULL;
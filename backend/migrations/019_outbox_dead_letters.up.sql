@@ -0,0 +1,7 @@
+-- Record when outbox events are dead-lettered so administrators can review and
+-- re-queue them. Events dead-lettered before this migration use their creation time.
+ALTER TABLE outbox_events ADD COLUMN IF NOT EXISTS dead_lettered_at TIMESTAMP;
+UPDATE outbox_events SET dead_lettered_at = created_at
+WHERE status = 'dead_lettered' AND dead_lettered_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_dead_lettered ON outbox_events(dead_lettered_at) WHERE status = 'dead_lettered';
@@ -0,0 +1,2 @@
+ALTER TABLE bug_reports DROP COLUMN IF EXISTS event_sequence;
+DROP TABLE IF EXISTS bug_events;
@@ -0,0 +1,15 @@
+-- Append-only event stream of bug report changes. Status and priority on bug_reports
+-- are a projection of these events, event_sequence being the last event applied.
+CREATE TABLE IF NOT EXISTS bug_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    bug_id UUID NOT NULL REFERENCES bug_reports(id) ON DELETE CASCADE,
+    sequence BIGINT NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bug_events_bug_sequence ON bug_events(bug_id, sequence);
+
+ALTER TABLE bug_reports ADD COLUMN IF NOT EXISTS event_sequence BIGINT DEFAULT 0;
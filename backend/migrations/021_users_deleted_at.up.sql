@@ -0,0 +1,4 @@
+-- Soft delete users so anonymized accounts keep the rows their bugs, votes and
+-- comments reference
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users(deleted_at);
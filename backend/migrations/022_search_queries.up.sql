@@ -0,0 +1,13 @@
+-- Bug searches and their result counts, used for search analytics. Queries are
+-- kept after their user is deleted, only losing the user reference.
+CREATE TABLE IF NOT EXISTS search_queries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    query_text VARCHAR(255) NOT NULL,
+    result_count INTEGER NOT NULL DEFAULT 0,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    session_id VARCHAR(100),
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_search_queries_query_text ON search_queries(query_text);
+CREATE INDEX IF NOT EXISTS idx_search_queries_created_at ON search_queries(created_at);
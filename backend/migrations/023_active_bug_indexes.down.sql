@@ -0,0 +1,3 @@
+-- Drop partial indexes on active bugs
+
+DROP INDEX CONCURRENTLY IF EXISTS idx_bug_reports_active_status_created_at;
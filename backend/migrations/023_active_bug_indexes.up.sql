@@ -0,0 +1,8 @@
+-- Partial indexes covering only bugs that have not been soft-deleted. Every
+-- bug_reports query GORM builds filters on deleted_at IS NULL, so deleted rows
+-- only add bloat to full indexes.
+--
+-- Comments are hard-deleted (comments has no deleted_at column) and are already
+-- covered by idx_comments_bug_created from 002_performance_indexes.
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_bug_reports_active_status_created_at ON bug_reports(status, created_at DESC) WHERE deleted_at IS NULL;
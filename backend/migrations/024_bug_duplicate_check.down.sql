@@ -0,0 +1,4 @@
+-- Remove the duplicate check flag
+
+DROP INDEX IF EXISTS idx_bug_reports_duplicate_check_skipped;
+ALTER TABLE bug_reports DROP COLUMN IF EXISTS duplicate_check_skipped;
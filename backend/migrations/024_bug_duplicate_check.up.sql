@@ -0,0 +1,4 @@
+-- Flag bugs submitted with force_create despite suspected duplicates in the same
+-- application, so the moderation view can list them
+ALTER TABLE bug_reports ADD COLUMN IF NOT EXISTS duplicate_check_skipped BOOLEAN NOT NULL DEFAULT false;
+CREATE INDEX IF NOT EXISTS idx_bug_reports_duplicate_check_skipped ON bug_reports(created_at DESC) WHERE duplicate_check_skipped = true;
@@ -0,0 +1,4 @@
+-- Remove the admin verification columns
+
+ALTER TABLE companies DROP COLUMN IF EXISTS admin_verified_by;
+ALTER TABLE companies DROP COLUMN IF EXISTS admin_verified;
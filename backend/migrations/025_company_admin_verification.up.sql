@@ -0,0 +1,3 @@
+-- Record companies verified by an administrator instead of the email flow
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS admin_verified BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS admin_verified_by UUID REFERENCES users(id) ON DELETE SET NULL;
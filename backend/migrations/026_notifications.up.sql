@@ -0,0 +1,15 @@
+-- In-app notifications, such as a reporter's bug reaching a vote milestone.
+-- Notifications are deleted with their user.
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(30) NOT NULL,
+    resource_id UUID,
+    body TEXT NOT NULL,
+    payload JSONB,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_resource_id ON notifications(resource_id, type);
@@ -0,0 +1,3 @@
+-- Remove the company logo column
+
+ALTER TABLE companies DROP COLUMN IF EXISTS logo_url;
@@ -0,0 +1,2 @@
+-- Store the URL of each company's uploaded logo
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS logo_url VARCHAR(500);
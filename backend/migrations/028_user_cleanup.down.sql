@@ -0,0 +1,3 @@
+-- Remove the user cleanup column
+
+ALTER TABLE users DROP COLUMN IF EXISTS cleanup_completed_at;
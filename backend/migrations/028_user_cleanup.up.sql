@@ -0,0 +1,2 @@
+-- Record when the records a deleted user left behind were cleaned up
+ALTER TABLE users ADD COLUMN IF NOT EXISTS cleanup_completed_at TIMESTAMP;
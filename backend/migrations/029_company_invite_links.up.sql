@@ -0,0 +1,16 @@
+-- Shareable links for joining a company, optionally admitting a limited number of
+-- people whose email is outside the company's domain
+CREATE TABLE IF NOT EXISTS company_invite_links (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    role VARCHAR(20) DEFAULT 'member',
+    token VARCHAR(255) NOT NULL UNIQUE,
+    created_by_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    bypass_domain_check BOOLEAN NOT NULL DEFAULT FALSE,
+    bypass_count INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_company_invite_links_company_id ON company_invite_links(company_id);
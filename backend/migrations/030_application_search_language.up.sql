@@ -0,0 +1,4 @@
+-- Text search configuration used when searching an application's bugs. The
+-- full-text index on bug_reports stays English; searches in other languages are
+-- limited to one application, so they use the application index instead.
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS search_language VARCHAR(64) NOT NULL DEFAULT 'english';
@@ -0,0 +1,6 @@
+-- Remove the attachment metadata columns
+
+DROP INDEX IF EXISTS idx_file_attachments_deleted_at;
+ALTER TABLE file_attachments DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE file_attachments DROP COLUMN IF EXISTS download_count;
+ALTER TABLE file_attachments DROP COLUMN IF EXISTS uploaded_by_id;
@@ -0,0 +1,6 @@
+-- Track who uploaded each attachment and how often it is downloaded, and soft
+-- delete attachments removed from a bug
+ALTER TABLE file_attachments ADD COLUMN IF NOT EXISTS uploaded_by_id UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE file_attachments ADD COLUMN IF NOT EXISTS download_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE file_attachments ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_file_attachments_deleted_at ON file_attachments(deleted_at);
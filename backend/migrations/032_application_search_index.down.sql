@@ -0,0 +1,5 @@
+-- Drop the stored search vector and its indexes
+
+DROP INDEX CONCURRENTLY IF EXISTS idx_bug_reports_application_search;
+DROP INDEX CONCURRENTLY IF EXISTS idx_bug_reports_active_search_vector;
+ALTER TABLE bug_reports DROP COLUMN IF EXISTS search_vector;
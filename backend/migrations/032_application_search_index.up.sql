@@ -0,0 +1,14 @@
+-- Stored full-text search vector of each bug's title and description. Searching
+-- the to_tsvector expression directly cannot use an index once the search also
+-- filters by application, so searches scoped to one application match on this
+-- column instead. It is English like idx_bug_reports_fulltext_search; applications
+-- searched in other languages still search the expression.
+ALTER TABLE bug_reports ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('english', title || ' ' || description)) STORED;
+
+-- Global search over active bugs
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_bug_reports_active_search_vector ON bug_reports USING gin(search_vector) WHERE deleted_at IS NULL;
+
+-- Search within one application: the planner narrows to the application's bugs
+-- before matching their search vectors
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_bug_reports_application_search ON bug_reports(application_id) INCLUDE (search_vector);
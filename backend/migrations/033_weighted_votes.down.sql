@@ -0,0 +1,5 @@
+-- Drop vote weights
+
+DROP INDEX IF EXISTS idx_bug_reports_weighted_vote_count_created_at;
+ALTER TABLE bug_reports DROP COLUMN IF EXISTS weighted_vote_count;
+ALTER TABLE bug_votes DROP COLUMN IF EXISTS vote_weight;
@@ -0,0 +1,10 @@
+-- Votes are weighted by the voter's reputation when cast, from 1.0 for new accounts
+-- up to 2.0. vote_count keeps counting votes; weighted_vote_count sums their
+-- weights and orders popular listings.
+ALTER TABLE bug_votes ADD COLUMN IF NOT EXISTS vote_weight DOUBLE PRECISION NOT NULL DEFAULT 1.0;
+ALTER TABLE bug_reports ADD COLUMN IF NOT EXISTS weighted_vote_count DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+-- Existing votes keep a weight of 1.0
+UPDATE bug_reports SET weighted_vote_count = vote_count;
+
+CREATE INDEX IF NOT EXISTS idx_bug_reports_weighted_vote_count_created_at ON bug_reports(weighted_vote_count DESC, created_at DESC);
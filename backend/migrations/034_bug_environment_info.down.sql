@@ -0,0 +1,4 @@
+-- Drop bug environment details
+
+DROP INDEX IF EXISTS idx_bug_reports_environment_os;
+ALTER TABLE bug_reports DROP COLUMN IF EXISTS environment_info;
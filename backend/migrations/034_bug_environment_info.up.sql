@@ -0,0 +1,5 @@
+-- Structured details of the environment a bug was reported from, such as OS,
+-- browser and screen resolution. Bug listings filter by os_name.
+ALTER TABLE bug_reports ADD COLUMN IF NOT EXISTS environment_info JSONB;
+
+CREATE INDEX IF NOT EXISTS idx_bug_reports_environment_os ON bug_reports((environment_info->>'os_name'));
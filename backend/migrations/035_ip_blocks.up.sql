@@ -0,0 +1,13 @@
+-- IP addresses and CIDR ranges blocked by administrators. The active blocks are
+-- also kept in Redis, which the request middleware checks.
+CREATE TABLE IF NOT EXISTS ip_blocks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ip VARCHAR(50) NOT NULL,
+    reason VARCHAR(500) NOT NULL,
+    blocked_by_id UUID NOT NULL REFERENCES users(id),
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ip_blocks_ip ON ip_blocks(ip);
+CREATE INDEX IF NOT EXISTS idx_ip_blocks_expires_at ON ip_blocks(expires_at);
@@ -0,0 +1,4 @@
+-- Drop normalized application URLs
+
+DROP INDEX IF EXISTS idx_applications_canonical_url;
+ALTER TABLE applications DROP COLUMN IF EXISTS canonical_url;
@@ -0,0 +1,6 @@
+-- Normalized application URLs, so that variants of the same URL such as a
+-- trailing slash or http instead of https resolve to one application. Existing
+-- rows are left NULL and are still matched by name or by their exact URL.
+ALTER TABLE applications ADD COLUMN IF NOT EXISTS canonical_url VARCHAR(2048);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_applications_canonical_url ON applications(canonical_url);
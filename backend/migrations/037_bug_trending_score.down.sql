@@ -0,0 +1,4 @@
+-- Drop stored trending scores
+
+DROP INDEX IF EXISTS idx_bug_reports_trending_score;
+ALTER TABLE bug_reports DROP COLUMN IF EXISTS trending_score;
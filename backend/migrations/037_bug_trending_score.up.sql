@@ -0,0 +1,10 @@
+-- Stored trending scores, vote_count / (hours_since_created + 2) ^ gravity. The
+-- refresh_trending_scores job keeps them current; existing bugs are scored here
+-- with the default gravity so the trending sort works before its first run.
+ALTER TABLE bug_reports ADD COLUMN IF NOT EXISTS trending_score DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+UPDATE bug_reports
+SET trending_score = vote_count / POWER(EXTRACT(EPOCH FROM NOW() - created_at) / 3600.0 + 2, 1.8)
+WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_bug_reports_trending_score ON bug_reports(trending_score DESC);
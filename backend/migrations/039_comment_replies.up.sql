@@ -0,0 +1,7 @@
+-- Comment replies. A reply references the comment it answers on the same bug;
+-- existing comments are left NULL and stay top-level. Only top-level comments
+-- are counted in bug_reports.comment_count, which already holds for every
+-- existing bug.
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES comments(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_comments_parent_id ON comments(parent_id);
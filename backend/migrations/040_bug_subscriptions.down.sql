@@ -0,0 +1,3 @@
+-- Drop bug subscriptions
+
+DROP TABLE IF EXISTS bug_subscriptions;
@@ -0,0 +1,15 @@
+-- Users watching bugs for status changes, comments and company responses.
+-- Existing reporters are subscribed to their bugs, as new reporters are on
+-- submission. Subscriptions are deleted with their bug or user.
+CREATE TABLE IF NOT EXISTS bug_subscriptions (
+    bug_id UUID NOT NULL REFERENCES bug_reports(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (bug_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bug_subscriptions_user_id ON bug_subscriptions(user_id);
+
+INSERT INTO bug_subscriptions (bug_id, user_id, created_at)
+SELECT id, reporter_id, created_at FROM bug_reports WHERE reporter_id IS NOT NULL
+ON CONFLICT DO NOTHING;
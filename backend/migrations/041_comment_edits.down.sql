@@ -0,0 +1,7 @@
+-- Drop comment edit history and soft deletion
+
+DROP TABLE IF EXISTS comment_edits;
+
+DROP INDEX IF EXISTS idx_comments_deleted_at;
+ALTER TABLE comments DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE comments DROP COLUMN IF EXISTS edited_at;
@@ -0,0 +1,16 @@
+-- Comment editing and deletion. Authors can edit or delete their comments for 24
+-- hours. Deleted comments are kept, with deleted_at set, so replies to them keep
+-- their place; each edit saves the previous content to comment_edits.
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS edited_at TIMESTAMP;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_comments_deleted_at ON comments(deleted_at);
+
+CREATE TABLE IF NOT EXISTS comment_edits (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    comment_id UUID NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    edited_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_edits_comment_id ON comment_edits(comment_id);
@@ -0,0 +1,5 @@
+-- Drop bug submission locations
+
+DROP INDEX IF EXISTS idx_bug_reports_country_code;
+ALTER TABLE bug_reports DROP COLUMN IF EXISTS region;
+ALTER TABLE bug_reports DROP COLUMN IF EXISTS country_code;
@@ -0,0 +1,6 @@
+-- Where bugs were submitted from, looked up from the submitter's IP in the GeoIP
+-- database. Existing bugs and bugs submitted without a database stay NULL.
+ALTER TABLE bug_reports ADD COLUMN IF NOT EXISTS country_code VARCHAR(2);
+ALTER TABLE bug_reports ADD COLUMN IF NOT EXISTS region VARCHAR(100);
+
+CREATE INDEX IF NOT EXISTS idx_bug_reports_country_code ON bug_reports(country_code);
@@ -0,0 +1,4 @@
+-- Remove the system user and the audit log entries attributed to it
+
+DELETE FROM audit_logs WHERE user_id = '00000000-0000-0000-0000-000000000001';
+DELETE FROM users WHERE id = '00000000-0000-0000-0000-000000000001';
@@ -0,0 +1,5 @@
+-- The user background jobs act as, e.g. in the audit log entries of automatic
+-- priority escalations. It has no password, so nobody can sign in as it.
+INSERT INTO users (id, email, display_name, auth_provider, is_email_verified)
+VALUES ('00000000-0000-0000-0000-000000000001', 'system@bugrelay.internal', 'BugRelay', 'system', TRUE)
+ON CONFLICT (id) DO NOTHING;
@@ -0,0 +1,3 @@
+-- Drop bug edit history
+
+DROP TABLE IF EXISTS bug_edit_history;
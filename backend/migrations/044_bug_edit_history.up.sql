@@ -0,0 +1,13 @@
+-- Edits to bug reports by their reporters, company members and admins. Each row
+-- is one changed field, with the old and new values as text.
+CREATE TABLE IF NOT EXISTS bug_edit_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    bug_id UUID NOT NULL REFERENCES bug_reports(id) ON DELETE CASCADE,
+    field_name VARCHAR(50) NOT NULL,
+    old_value TEXT,
+    new_value TEXT,
+    changed_by UUID NOT NULL REFERENCES users(id),
+    changed_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_bug_edit_history_bug_id ON bug_edit_history(bug_id, changed_at);
@@ -0,0 +1,7 @@
+-- Email pending domain change tokens to an address at the new domain and expire
+-- them. Changes started before this migration returned the token to the requesting
+-- admin, so they are cancelled and have to be started again.
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS pending_domain_email VARCHAR(255);
+ALTER TABLE companies ADD COLUMN IF NOT EXISTS pending_domain_verification_expires_at TIMESTAMP;
+UPDATE companies SET pending_domain = NULL, pending_domain_verification_token = NULL
+WHERE pending_domain_verification_expires_at IS NULL;
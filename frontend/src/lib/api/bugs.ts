@@ -72,7 +72,7 @@ export const bugsAPI = {
   },
 
   get: async (id: string): Promise<BugReport> => {
-    const response = await apiClient.get<{bug: BugReport}>(`/bugs/${id}/`)
+    const response = await apiClient.get<{bug: BugReport}>(`/bugs/${id}/?include=application,reporter,company,comments,attachments`)
     return response.bug
   },
 